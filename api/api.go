@@ -0,0 +1,62 @@
+// Package api is the stable public API of build-info-go, intended for tools which embed the library.
+//
+// The package is built around three interfaces: a Collector resolves a project's dependencies into build-info modules,
+// a Formatter serializes a build-info, and a Publisher delivers it to its destination.
+// Each interface has adapters to the existing implementations in the build and entities packages,
+// so that consumers don't depend on their concrete types.
+//
+// Compatibility: this package follows the module's semantic versioning.
+// Within a major version, its exported identifiers are not removed, and their signatures are not changed.
+// New methods are not added to the exported interfaces, so that consumers may implement them.
+// The other packages of the module (build, utils, etc.) may change between minor versions.
+package api
+
+import (
+	"context"
+	"io"
+
+	"github.com/jfrog/build-info-go/entities"
+)
+
+// Collector resolves the dependencies of a project, and returns the build-info modules which describe it.
+type Collector interface {
+	Collect(ctx context.Context) ([]entities.Module, error)
+}
+
+// Formatter serializes a build-info, for example to JSON or to a CycloneDX SBOM.
+type Formatter interface {
+	Format(buildInfo *entities.BuildInfo) ([]byte, error)
+}
+
+// StreamFormatter is a Formatter which can also write the serialized build-info directly to a writer,
+// without holding the whole document in memory. The publishers of this package use it when the formatter supports it.
+type StreamFormatter interface {
+	Formatter
+	FormatTo(writer io.Writer, buildInfo *entities.BuildInfo) error
+}
+
+// Publisher delivers a build-info to its destination, such as a file or a server.
+type Publisher interface {
+	Publish(ctx context.Context, buildInfo *entities.BuildInfo) error
+}
+
+// CollectorFunc is an adapter to allow the use of an ordinary function as a Collector.
+type CollectorFunc func(ctx context.Context) ([]entities.Module, error)
+
+func (f CollectorFunc) Collect(ctx context.Context) ([]entities.Module, error) {
+	return f(ctx)
+}
+
+// FormatterFunc is an adapter to allow the use of an ordinary function as a Formatter.
+type FormatterFunc func(buildInfo *entities.BuildInfo) ([]byte, error)
+
+func (f FormatterFunc) Format(buildInfo *entities.BuildInfo) ([]byte, error) {
+	return f(buildInfo)
+}
+
+// PublisherFunc is an adapter to allow the use of an ordinary function as a Publisher.
+type PublisherFunc func(ctx context.Context, buildInfo *entities.BuildInfo) error
+
+func (f PublisherFunc) Publish(ctx context.Context, buildInfo *entities.BuildInfo) error {
+	return f(ctx, buildInfo)
+}
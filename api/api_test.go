@@ -0,0 +1,240 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/build"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBuildInfo = &entities.BuildInfo{
+	Name:   "api-test",
+	Number: "1",
+	Modules: []entities.Module{{
+		Id:           "github.com/jfrog/api-test",
+		Type:         entities.Go,
+		Dependencies: []entities.Dependency{{Id: "github.com/jfrog/gofrog:v1.7.6", Checksum: entities.Checksum{Sha1: "123", Md5: "456", Sha256: "789"}}},
+	}},
+}
+
+func TestNewFormatter(t *testing.T) {
+	formatter, err := NewFormatter(JsonFormat)
+	require.NoError(t, err)
+	content, err := formatter.Format(testBuildInfo)
+	require.NoError(t, err)
+	var buildInfo entities.BuildInfo
+	require.NoError(t, json.Unmarshal(content, &buildInfo))
+	assert.Equal(t, *testBuildInfo, buildInfo)
+	// The JSON formatter can also stream the build-info, with an identical output.
+	require.Implements(t, (*StreamFormatter)(nil), formatter)
+	var streamed bytes.Buffer
+	require.NoError(t, formatter.(StreamFormatter).FormatTo(&streamed, testBuildInfo))
+	assert.Equal(t, content, streamed.Bytes())
+
+	formatter, err = NewFormatter(CycloneDxJsonFormat)
+	require.NoError(t, err)
+	content, err = formatter.Format(testBuildInfo)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"bomFormat": "CycloneDX"`)
+
+	formatter, err = NewFormatter(CycloneDxXmlFormat)
+	require.NoError(t, err)
+	content, err = formatter.Format(testBuildInfo)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "<bom")
+
+	_, err = NewFormatter("yaml")
+	assert.Error(t, err)
+}
+
+func TestPublishers(t *testing.T) {
+	formatter, err := NewFormatter(JsonFormat)
+	require.NoError(t, err)
+	expected, err := formatter.Format(testBuildInfo)
+	require.NoError(t, err)
+
+	var content bytes.Buffer
+	require.NoError(t, NewWriterPublisher(&content, formatter).Publish(context.Background(), testBuildInfo))
+	assert.Equal(t, expected, content.Bytes())
+
+	path := filepath.Join(t.TempDir(), "build-info.json")
+	require.NoError(t, NewFilePublisher(path, formatter).Publish(context.Background(), testBuildInfo))
+	fileContent, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, fileContent)
+
+	// A publishing failure is categorized.
+	err = NewFilePublisher(filepath.Join(t.TempDir(), "missing", "build-info.json"), formatter).Publish(context.Background(), testBuildInfo)
+	assert.Equal(t, utils.PublishFailure, utils.GetErrorCategory(err))
+
+	// Nothing is published once the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	content.Reset()
+	assert.ErrorIs(t, NewWriterPublisher(&content, formatter).Publish(ctx, testBuildInfo), context.Canceled)
+	assert.Zero(t, content.Len())
+}
+
+func TestCollectInto(t *testing.T) {
+	service := build.NewBuildInfoService()
+	service.SetTempDirPath(t.TempDir())
+	bld, err := service.GetOrCreateBuild("api-test", "1")
+	require.NoError(t, err)
+	collector := CollectorFunc(func(context.Context) ([]entities.Module, error) {
+		return testBuildInfo.Modules, nil
+	})
+	require.NoError(t, CollectInto(context.Background(), bld, collector))
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	// The build-info is created with the component IDs of the dependencies.
+	content, err := json.Marshal(testBuildInfo)
+	require.NoError(t, err)
+	var expected entities.BuildInfo
+	require.NoError(t, json.Unmarshal(content, &expected))
+	expected.SetComponentIds()
+	assert.Equal(t, expected.Modules, buildInfo.Modules)
+
+	failingCollector := CollectorFunc(func(context.Context) ([]entities.Module, error) {
+		return nil, errors.New("collection failed")
+	})
+	assert.EqualError(t, CollectInto(context.Background(), bld, failingCollector), "collection failed")
+}
+
+func TestProjectCollector(t *testing.T) {
+	collector := NewProjectCollector(GoTechnology, filepath.Join("..", "build", "testdata", "golang", "project"), nil)
+	modules, err := collector.Collect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, modules, 1)
+	assert.Equal(t, "github.com/jfrog/dependency", modules[0].Id)
+	assert.Len(t, modules[0].Dependencies, 6)
+
+	_, err = NewProjectCollector(build.PythonTechnology, "", nil).Collect(context.Background())
+	assert.Error(t, err)
+}
+
+func TestTableFormatter(t *testing.T) {
+	buildInfo := &entities.BuildInfo{
+		Name:   "table-test",
+		Number: "7",
+		Modules: []entities.Module{
+			{
+				Id:   "org.example:core:1.0.0",
+				Type: entities.Maven,
+				Dependencies: []entities.Dependency{
+					{Id: "org.slf4j:slf4j-api:2.0.9", Scopes: []string{"compile"}, Checksum: entities.Checksum{Sha1: "1"}},
+					{Id: "junit:junit:4.13.2", Scopes: []string{"test"}},
+					{Id: "org.example:shared:1.0.0", Scopes: []string{"compile", "runtime"}, Checksum: entities.Checksum{Sha1: "2"}},
+				},
+				Artifacts: []entities.Artifact{{Name: "core-1.0.0.jar", Checksum: entities.Checksum{Sha1: "3"}}},
+			},
+			{Id: "docs", Type: entities.Generic, Artifacts: []entities.Artifact{{Name: "docs.zip"}}},
+		},
+	}
+	formatter, err := NewFormatter(TableFormat)
+	require.NoError(t, err)
+	content, err := formatter.Format(buildInfo)
+	require.NoError(t, err)
+	assert.Equal(t, `Build: table-test 7
+
+MODULE                  TYPE     DEPENDENCIES  SCOPES                        ARTIFACTS  MISSING CHECKSUMS
+org.example:core:1.0.0  maven    3             compile=2, runtime=1, test=1  1          1
+docs                    generic  0             -                             1          1
+TOTAL (2 modules)                3             compile=2, runtime=1, test=1  2          2
+`, string(content))
+}
+
+func TestGzipFormatter(t *testing.T) {
+	jsonFormatter, err := NewFormatter(JsonFormat)
+	require.NoError(t, err)
+	expected, err := jsonFormatter.Format(testBuildInfo)
+	require.NoError(t, err)
+
+	for _, formatter := range []Formatter{jsonFormatter, FormatterFunc(jsonFormatter.Format)} {
+		content, err := NewGzipFormatter(formatter).Format(testBuildInfo)
+		require.NoError(t, err)
+		gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+		require.NoError(t, err)
+		decompressed, err := io.ReadAll(gzipReader)
+		require.NoError(t, err)
+		assert.Equal(t, expected, decompressed)
+	}
+}
+
+func TestChunkedPublisher(t *testing.T) {
+	buildInfo := &entities.BuildInfo{Name: "chunked", Number: "1", Modules: []entities.Module{
+		{Id: "a", Type: entities.Go, Dependencies: []entities.Dependency{{Id: "dep-a:1.0"}}},
+		{Id: "b", Type: entities.Go, Dependencies: []entities.Dependency{{Id: "dep-b:1.0"}}},
+		{Id: "c", Type: entities.Go, Dependencies: []entities.Dependency{{Id: "dep-c:1.0"}}},
+	}}
+	var published []entities.BuildInfo
+	collectingPublisher := PublisherFunc(func(_ context.Context, buildInfo *entities.BuildInfo) error {
+		published = append(published, *buildInfo)
+		return nil
+	})
+
+	// A build-info within the limit is published as is.
+	require.NoError(t, NewChunkedPublisher(collectingPublisher, 1024*1024).Publish(context.Background(), buildInfo))
+	require.Len(t, published, 1)
+	assert.Equal(t, *buildInfo, published[0])
+
+	// Each module exceeds the limit, so each is published in a chunk of its own, followed by the aggregating build-info.
+	published = nil
+	require.NoError(t, NewChunkedPublisher(collectingPublisher, 10).Publish(context.Background(), buildInfo))
+	require.Len(t, published, 4)
+	for i, module := range buildInfo.Modules {
+		assert.Equal(t, "chunked", published[i].Name)
+		assert.Equal(t, fmt.Sprintf("1-%d", i+1), published[i].Number)
+		assert.Equal(t, []entities.Module{module}, published[i].Modules)
+	}
+	aggregated := published[3]
+	assert.Equal(t, "1", aggregated.Number)
+	require.Len(t, aggregated.Modules, 3)
+	var content bytes.Buffer
+	_, err := published[0].WriteTo(&content)
+	require.NoError(t, err)
+	checksums, err := crypto.CalcChecksums(&content)
+	require.NoError(t, err)
+	assert.Equal(t, entities.Module{
+		Type:     entities.Build,
+		Id:       "chunked/1-1",
+		Checksum: entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]},
+	}, aggregated.Modules[0])
+}
+
+func TestChunkedPublisherChunkSize(t *testing.T) {
+	buildInfo := &entities.BuildInfo{Name: "chunked", Number: "1", Agent: &entities.Agent{Name: "build-info-go", Version: "1.0.0"}}
+	for i := 0; i < 10; i++ {
+		buildInfo.Modules = append(buildInfo.Modules, entities.Module{Id: fmt.Sprintf("module-%d", i), Type: entities.Go, Dependencies: []entities.Dependency{{Id: fmt.Sprintf("dep-%d:1.0", i)}}})
+	}
+	var published []entities.BuildInfo
+	collectingPublisher := PublisherFunc(func(_ context.Context, buildInfo *entities.BuildInfo) error {
+		published = append(published, *buildInfo)
+		return nil
+	})
+
+	maxChunkSize := 400
+	require.NoError(t, NewChunkedPublisher(collectingPublisher, maxChunkSize).Publish(context.Background(), buildInfo))
+	// At least 3 chunks, followed by the aggregating build-info.
+	require.GreaterOrEqual(t, len(published), 4)
+	var chunkedModules []entities.Module
+	for _, chunk := range published[:len(published)-1] {
+		content, err := json.Marshal(chunk)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(content), maxChunkSize, chunk.Number)
+		chunkedModules = append(chunkedModules, chunk.Modules...)
+	}
+	assert.Equal(t, buildInfo.Modules, chunkedModules)
+}
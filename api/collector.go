@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jfrog/build-info-go/build"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+)
+
+// The name and number of the scratch build, in which a project collector saves the collected modules.
+const (
+	collectorBuildName   = "api-collector"
+	collectorBuildNumber = "1"
+)
+
+// ProjectTechnology is the technology of a project, which determines the collector used for it.
+type ProjectTechnology = build.ProjectTechnology
+
+const (
+	GoTechnology     = build.GoTechnology
+	MavenTechnology  = build.MavenTechnology
+	GradleTechnology = build.GradleTechnology
+	NpmTechnology    = build.NpmTechnology
+	YarnTechnology   = build.YarnTechnology
+)
+
+type projectCollector struct {
+	technology ProjectTechnology
+	srcPath    string
+	logger     utils.Log
+}
+
+// NewProjectCollector returns a Collector of the project in srcPath, using the collector of the provided technology.
+// Pass srcPath as an empty string if the root of the project is the working directory.
+// If logger is nil, nothing is logged.
+func NewProjectCollector(technology ProjectTechnology, srcPath string, logger utils.Log) Collector {
+	if logger == nil {
+		logger = &utils.NullLog{}
+	}
+	return &projectCollector{technology: technology, srcPath: srcPath, logger: logger}
+}
+
+// Collect resolves the project's dependencies. The context is checked before the collection starts,
+// but the underlying package managers aren't interrupted once they run.
+func (pc *projectCollector) Collect(ctx context.Context) (modules []entities.Module, err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	scratchDir, err := utils.CreateTempDir()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, utils.RemoveTempDir(scratchDir))
+	}()
+	service := build.NewBuildInfoService()
+	service.SetTempDirPath(scratchDir)
+	service.SetLogger(pc.logger)
+	bld, err := service.GetOrCreateBuild(collectorBuildName, collectorBuildNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err = bld.CollectProject(pc.srcPath, pc.technology); err != nil {
+		return nil, err
+	}
+	buildInfo, err := bld.ToBuildInfo()
+	if err != nil {
+		return nil, err
+	}
+	return buildInfo.Modules, nil
+}
+
+// CollectInto runs the collector, and saves the collected modules in the build,
+// so that they are included in the build-info returned by bld.ToBuildInfo().
+func CollectInto(ctx context.Context, bld *build.Build, collector Collector) error {
+	modules, err := collector.Collect(ctx)
+	if err != nil || len(modules) == 0 {
+		return err
+	}
+	return bld.SaveBuildInfo(&entities.BuildInfo{Modules: modules})
+}
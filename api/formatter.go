@@ -0,0 +1,182 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/jfrog/build-info-go/entities"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// The formats supported by NewFormatter.
+const (
+	JsonFormat          = ""
+	CycloneDxXmlFormat  = "cyclonedx/xml"
+	CycloneDxJsonFormat = "cyclonedx/json"
+	// A human-readable summary of the build-info's modules, for a quick local inspection. It can't be converted back to a build-info.
+	TableFormat = "table"
+)
+
+// NewFormatter returns a Formatter of the provided format.
+func NewFormatter(format string) (Formatter, error) {
+	switch format {
+	case JsonFormat:
+		return jsonFormatter{}, nil
+	case CycloneDxXmlFormat:
+		return newCycloneDxFormatter(cdx.BOMFileFormatXML), nil
+	case CycloneDxJsonFormat:
+		return newCycloneDxFormatter(cdx.BOMFileFormatJSON), nil
+	case TableFormat:
+		return FormatterFunc(formatTable), nil
+	}
+	return nil, fmt.Errorf("'%s' is not a supported build-info format. Supported formats are '%s', '%s' and '%s'", format, CycloneDxXmlFormat, CycloneDxJsonFormat, TableFormat)
+}
+
+// Serializes the build-info to indented JSON, followed by a newline.
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(buildInfo *entities.BuildInfo) ([]byte, error) {
+	var content bytes.Buffer
+	if err := (jsonFormatter{}).FormatTo(&content, buildInfo); err != nil {
+		return nil, err
+	}
+	return content.Bytes(), nil
+}
+
+// FormatTo writes the modules one after the other, which keeps the memory usage low for very large build-infos.
+func (jsonFormatter) FormatTo(writer io.Writer, buildInfo *entities.BuildInfo) error {
+	_, err := buildInfo.WriteTo(writer)
+	return err
+}
+
+// NewGzipFormatter returns a Formatter, which compresses the output of the provided formatter with gzip.
+// If the provided formatter is a StreamFormatter, its output is compressed while it's being written.
+func NewGzipFormatter(formatter Formatter) StreamFormatter {
+	return gzipFormatter{formatter: formatter}
+}
+
+type gzipFormatter struct {
+	formatter Formatter
+}
+
+func (f gzipFormatter) Format(buildInfo *entities.BuildInfo) ([]byte, error) {
+	var content bytes.Buffer
+	if err := f.FormatTo(&content, buildInfo); err != nil {
+		return nil, err
+	}
+	return content.Bytes(), nil
+}
+
+func (f gzipFormatter) FormatTo(writer io.Writer, buildInfo *entities.BuildInfo) error {
+	gzipWriter := gzip.NewWriter(writer)
+	if streamFormatter, ok := f.formatter.(StreamFormatter); ok {
+		if err := streamFormatter.FormatTo(gzipWriter, buildInfo); err != nil {
+			return err
+		}
+	} else {
+		content, err := f.formatter.Format(buildInfo)
+		if err != nil {
+			return err
+		}
+		if _, err = gzipWriter.Write(content); err != nil {
+			return err
+		}
+	}
+	return gzipWriter.Close()
+}
+
+// Converts the build-info to a CycloneDX SBOM, and serializes it in the provided file format.
+func newCycloneDxFormatter(fileFormat cdx.BOMFileFormat) Formatter {
+	return FormatterFunc(func(buildInfo *entities.BuildInfo) ([]byte, error) {
+		cdxBom, err := buildInfo.ToCycloneDxBom()
+		if err != nil {
+			return nil, err
+		}
+		var content bytes.Buffer
+		encoder := cdx.NewBOMEncoder(&content, fileFormat)
+		encoder.SetPretty(true)
+		if err = encoder.Encode(cdxBom); err != nil {
+			return nil, err
+		}
+		return content.Bytes(), nil
+	})
+}
+
+// Summarizes each module of the build-info in a row of a table: its ID and type, the number of its dependencies, in total and by scope,
+// the number of its artifacts, and the number of its dependencies and artifacts without checksums. The last row sums up all the modules.
+func formatTable(buildInfo *entities.BuildInfo) ([]byte, error) {
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "Build: %s %s\n\n", buildInfo.Name, buildInfo.Number)
+	tableWriter := tabwriter.NewWriter(&content, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tableWriter, "MODULE\tTYPE\tDEPENDENCIES\tSCOPES\tARTIFACTS\tMISSING CHECKSUMS")
+	var total moduleSummary
+	for _, module := range buildInfo.Modules {
+		summary := summarizeModule(module)
+		fmt.Fprintf(tableWriter, "%s\t%s\t%d\t%s\t%d\t%d\n", module.Id, module.Type, summary.dependencies, formatScopes(summary.scopes), summary.artifacts, summary.missingChecksums)
+		total.add(summary)
+	}
+	fmt.Fprintf(tableWriter, "TOTAL (%d modules)\t\t%d\t%s\t%d\t%d\n", len(buildInfo.Modules), total.dependencies, formatScopes(total.scopes), total.artifacts, total.missingChecksums)
+	if err := tableWriter.Flush(); err != nil {
+		return nil, err
+	}
+	return content.Bytes(), nil
+}
+
+type moduleSummary struct {
+	dependencies int
+	// The number of dependencies in each scope. A dependency with several scopes is counted in each of them.
+	scopes           map[string]int
+	artifacts        int
+	missingChecksums int
+}
+
+func summarizeModule(module entities.Module) moduleSummary {
+	summary := moduleSummary{dependencies: len(module.Dependencies), artifacts: len(module.Artifacts), scopes: map[string]int{}}
+	for _, dependency := range module.Dependencies {
+		for _, scope := range dependency.Scopes {
+			summary.scopes[scope]++
+		}
+		if dependency.Checksum.IsEmpty() {
+			summary.missingChecksums++
+		}
+	}
+	for _, artifact := range module.Artifacts {
+		if artifact.Checksum.IsEmpty() {
+			summary.missingChecksums++
+		}
+	}
+	return summary
+}
+
+func (ms *moduleSummary) add(other moduleSummary) {
+	ms.dependencies += other.dependencies
+	ms.artifacts += other.artifacts
+	ms.missingChecksums += other.missingChecksums
+	if ms.scopes == nil {
+		ms.scopes = map[string]int{}
+	}
+	for scope, count := range other.scopes {
+		ms.scopes[scope] += count
+	}
+}
+
+// Formats the number of dependencies in each scope, sorted by the scopes' names, for example: compile=12, test=3
+// Returns '-' if the dependencies have no scopes.
+func formatScopes(scopes map[string]int) string {
+	if len(scopes) == 0 {
+		return "-"
+	}
+	names := maps.Keys(scopes)
+	slices.Sort(names)
+	formatted := make([]string, 0, len(names))
+	for _, name := range names {
+		formatted = append(formatted, fmt.Sprintf("%s=%d", name, scopes[name]))
+	}
+	return strings.Join(formatted, ", ")
+}
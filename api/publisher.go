@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+)
+
+// NewWriterPublisher returns a Publisher, which writes the build-info to the writer, serialized by the formatter.
+func NewWriterPublisher(writer io.Writer, formatter Formatter) Publisher {
+	return PublisherFunc(func(ctx context.Context, buildInfo *entities.BuildInfo) error {
+		return publishTo(ctx, writer, buildInfo, formatter)
+	})
+}
+
+// NewFilePublisher returns a Publisher, which writes the build-info to the file, serialized by the formatter.
+// The file is created if it doesn't exist, and overwritten otherwise.
+func NewFilePublisher(path string, formatter Formatter) Publisher {
+	return PublisherFunc(func(ctx context.Context, buildInfo *entities.BuildInfo) (err error) {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		file, err := os.Create(path)
+		if err != nil {
+			return utils.NewCategorizedError(utils.PublishFailure, err)
+		}
+		defer func() {
+			if closeErr := file.Close(); closeErr != nil && err == nil {
+				err = utils.NewCategorizedError(utils.PublishFailure, closeErr)
+			}
+		}()
+		return publishTo(ctx, file, buildInfo, formatter)
+	})
+}
+
+// NewChunkedPublisher returns a Publisher, which splits build-infos larger than maxChunkSize bytes, to stay within the size limits of the server.
+// The modules are split into chunk build-infos of up to maxChunkSize bytes each, numbered <number>-1, <number>-2, etc.
+// A module larger than maxChunkSize is published in a chunk of its own.
+// Each chunk is published with the provided publisher, followed by an aggregating build-info with the original number,
+// whose modules reference the chunks, as done when appending builds in Artifactory.
+// Build-infos which don't exceed maxChunkSize are published as is.
+func NewChunkedPublisher(publisher Publisher, maxChunkSize int) Publisher {
+	return PublisherFunc(func(ctx context.Context, buildInfo *entities.BuildInfo) error {
+		chunks, err := splitToChunks(buildInfo, maxChunkSize)
+		if err != nil {
+			return err
+		}
+		if len(chunks) < 2 {
+			return publisher.Publish(ctx, buildInfo)
+		}
+		aggregated := *buildInfo
+		aggregated.Modules = nil
+		for i, chunk := range chunks {
+			chunkBuildInfo := *buildInfo
+			chunkBuildInfo.Number = fmt.Sprintf("%s-%d", buildInfo.Number, i+1)
+			chunkBuildInfo.Modules = chunk
+			if err = publisher.Publish(ctx, &chunkBuildInfo); err != nil {
+				return err
+			}
+			var content bytes.Buffer
+			if _, err = chunkBuildInfo.WriteTo(&content); err != nil {
+				return err
+			}
+			checksums, err := crypto.CalcChecksums(&content)
+			if err != nil {
+				return err
+			}
+			aggregated.Modules = append(aggregated.Modules, entities.Module{
+				Type:     entities.Build,
+				Id:       chunkBuildInfo.Name + "/" + chunkBuildInfo.Number,
+				Checksum: entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]},
+			})
+		}
+		return publisher.Publish(ctx, &aggregated)
+	})
+}
+
+// Splits the modules of the build-info into chunks, whose serialized sizes don't exceed maxChunkSize bytes.
+func splitToChunks(buildInfo *entities.BuildInfo, maxChunkSize int) (chunks [][]entities.Module, err error) {
+	// Each chunk is serialized in an envelope of the build-info's other fields, with the longest chunk number.
+	envelope := *buildInfo
+	envelope.Number = fmt.Sprintf("%s-%d", buildInfo.Number, len(buildInfo.Modules))
+	envelope.Modules = nil
+	content, err := json.Marshal(envelope)
+	if err != nil {
+		return
+	}
+	envelopeSize := len(content) + len(`,"modules":[]`)
+	var chunk []entities.Module
+	chunkSize := envelopeSize
+	for _, module := range buildInfo.Modules {
+		if content, err = json.Marshal(module); err != nil {
+			return
+		}
+		// The modules are separated by commas.
+		moduleSize := len(content) + 1
+		if len(chunk) > 0 && chunkSize+moduleSize > maxChunkSize {
+			chunks = append(chunks, chunk)
+			chunk, chunkSize = nil, envelopeSize+moduleSize
+		} else {
+			chunkSize += moduleSize
+		}
+		chunk = append(chunk, module)
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return
+}
+
+// Writes the build-info to the writer, serialized by the formatter.
+// Formatters which implement StreamFormatter write to the writer directly, without holding the serialized build-info in memory.
+func publishTo(ctx context.Context, writer io.Writer, buildInfo *entities.BuildInfo, formatter Formatter) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if streamFormatter, ok := formatter.(StreamFormatter); ok {
+		// A buffered writer avoids a write call for each module.
+		bufferedWriter := bufio.NewWriter(writer)
+		if err := streamFormatter.FormatTo(bufferedWriter, buildInfo); err != nil {
+			return utils.NewCategorizedError(utils.PublishFailure, err)
+		}
+		if err := bufferedWriter.Flush(); err != nil {
+			return utils.NewCategorizedError(utils.PublishFailure, err)
+		}
+		return nil
+	}
+	content, err := formatter.Format(buildInfo)
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(content); err != nil {
+		return utils.NewCategorizedError(utils.PublishFailure, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package build
+
+import (
+	"fmt"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/build-info-go/utils/purl"
+	"github.com/jfrog/gofrog/crypto"
+)
+
+// BackfillChecksums fills in the missing checksums of the build-info's dependencies, from their files found in the local caches,
+// for example, when the build-info was collected on a machine whose caches weren't populated.
+// The package of each dependency is identified by its package URL, or by the type of its module if it has none.
+// A dependency which already has some of its checksums is filled only if they match its cached file.
+// The checksumCache may be nil. Returns the number of dependencies whose checksums were filled in.
+func BackfillChecksums(buildInfo *entities.BuildInfo, localCaches *buildutils.LocalCaches, checksumCache *utils.ChecksumCache, logger utils.Log) (filled int, err error) {
+	for i := range buildInfo.Modules {
+		module := &buildInfo.Modules[i]
+		for j := range module.Dependencies {
+			dependency := &module.Dependencies[j]
+			if dependency.Sha1 != "" && dependency.Md5 != "" && dependency.Sha256 != "" {
+				continue
+			}
+			packageUrl := getDependencyPackageUrl(module.Type, dependency)
+			if packageUrl == nil {
+				continue
+			}
+			filePath, err := localCaches.FindPackageFile(packageUrl)
+			if err != nil {
+				return filled, fmt.Errorf("failed looking up the dependency '%s' in the local caches: %w", dependency.Id, err)
+			}
+			if filePath == "" {
+				logger.Debug("The dependency", dependency.Id, "of the module", module.Id, "wasn't found in the local caches.")
+				continue
+			}
+			checksums, err := checksumCache.GetFileChecksums(filePath)
+			if err != nil {
+				return filled, err
+			}
+			cached := entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}
+			if !isChecksumConsistent(dependency.Checksum, cached) {
+				logger.Warn("The checksums of the dependency", dependency.Id, "don't match its file in the local caches,", filePath+". Its checksums weren't filled in.")
+				continue
+			}
+			dependency.Checksum = cached
+			filled++
+		}
+	}
+	return
+}
+
+// Returns the package URL of the dependency, or nil if it has none and its module type has no package URL type.
+func getDependencyPackageUrl(moduleType entities.ModuleType, dependency *entities.Dependency) *purl.PackageURL {
+	if dependency.Purl != "" {
+		packageUrl, err := purl.Parse(dependency.Purl)
+		if err != nil {
+			return nil
+		}
+		return packageUrl
+	}
+	packageUrlString, err := entities.PackageIdToPurl(moduleType, dependency.Id)
+	if err != nil {
+		return nil
+	}
+	packageUrl, err := purl.Parse(packageUrlString)
+	if err != nil {
+		return nil
+	}
+	// The build-info ID of a Maven dependency doesn't include its type.
+	if packageUrl.Type == purl.Maven && dependency.Type != "" && dependency.Type != "jar" {
+		packageUrl.Qualifiers = map[string]string{"type": dependency.Type}
+	}
+	return packageUrl
+}
+
+// Returns true if each of the existing checksums is equal to the cached checksum of the same algorithm.
+func isChecksumConsistent(existing, cached entities.Checksum) bool {
+	return (existing.Sha1 == "" || existing.Sha1 == cached.Sha1) &&
+		(existing.Md5 == "" || existing.Md5 == cached.Md5) &&
+		(existing.Sha256 == "" || existing.Sha256 == cached.Sha256)
+}
@@ -0,0 +1,60 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillChecksums(t *testing.T) {
+	mavenRepository := t.TempDir()
+	jarPath := filepath.Join(mavenRepository, "org", "slf4j", "slf4j-api", "2.0.9", "slf4j-api-2.0.9.jar")
+	pomPath := filepath.Join(mavenRepository, "org", "jfrog", "bom", "1.0.0", "bom-1.0.0.pom")
+	helmCache := t.TempDir()
+	chartPath := filepath.Join(helmCache, "nginx-15.4.0.tgz")
+	for _, filePath := range []string{jarPath, pomPath, chartPath} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		require.NoError(t, os.WriteFile(filePath, []byte(filepath.Base(filePath)), 0644))
+	}
+	jarChecksums, err := crypto.GetFileChecksums(jarPath)
+	require.NoError(t, err)
+	pomChecksums, err := crypto.GetFileChecksums(pomPath)
+	require.NoError(t, err)
+	chartChecksums, err := crypto.GetFileChecksums(chartPath)
+	require.NoError(t, err)
+
+	buildInfo := &entities.BuildInfo{Modules: []entities.Module{
+		{Id: "app:1.0.0", Type: entities.Maven, Dependencies: []entities.Dependency{
+			{Id: "org.slf4j:slf4j-api:2.0.9", Type: "jar"},
+			// The dependency's type is used to find its file.
+			{Id: "org.jfrog:bom:1.0.0", Type: "pom", Checksum: entities.Checksum{Sha1: pomChecksums[crypto.SHA1]}},
+			// A dependency whose checksums don't match its cached file isn't filled.
+			{Id: "org.slf4j:slf4j-api:2.0.9", Type: "jar", Checksum: entities.Checksum{Sha1: "other"}},
+			{Id: "org.slf4j:slf4j-api:1.7.36", Type: "jar"},
+		}},
+		// The package URL of a dependency identifies its package, regardless of its module's type.
+		{Id: "chart", Type: entities.Generic, Dependencies: []entities.Dependency{
+			{Id: "nginx-15.4.0.tgz", Purl: "pkg:helm/nginx@15.4.0"},
+			{Id: "unknown"},
+		}},
+	}}
+	localCaches := &buildutils.LocalCaches{MavenRepository: mavenRepository, HelmRepositoryCache: helmCache}
+	filled, err := BackfillChecksums(buildInfo, localCaches, nil, &utils.NullLog{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, filled)
+
+	dependencies := buildInfo.Modules[0].Dependencies
+	assert.Equal(t, entities.Checksum{Sha1: jarChecksums[crypto.SHA1], Md5: jarChecksums[crypto.MD5], Sha256: jarChecksums[crypto.SHA256]}, dependencies[0].Checksum)
+	assert.Equal(t, entities.Checksum{Sha1: pomChecksums[crypto.SHA1], Md5: pomChecksums[crypto.MD5], Sha256: pomChecksums[crypto.SHA256]}, dependencies[1].Checksum)
+	assert.Equal(t, entities.Checksum{Sha1: "other"}, dependencies[2].Checksum)
+	assert.Empty(t, dependencies[3].Checksum)
+	assert.Equal(t, chartChecksums[crypto.SHA256], buildInfo.Modules[1].Dependencies[0].Sha256)
+	assert.Empty(t, buildInfo.Modules[1].Dependencies[1].Checksum)
+}
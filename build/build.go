@@ -1,11 +1,14 @@
 package build
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
 	"fmt"
 	ioutils "github.com/jfrog/gofrog/io"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -13,6 +16,8 @@ import (
 	"time"
 
 	"github.com/jfrog/build-info-go/utils/pythonutils"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 
 	"github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/build-info-go/utils"
@@ -26,6 +31,9 @@ const (
 	dependenciesDirName = ".build-info"
 )
 
+// The first bytes of gzip compressed files.
+var gzipMagicNumber = []byte{0x1f, 0x8b}
+
 type Build struct {
 	buildName         string
 	buildNumber       string
@@ -38,6 +46,52 @@ type Build struct {
 	buildAgentVersion string
 	principal         string
 	buildUrl          string
+	resolutionAudit   bool
+	// Add the resolution times of the dependencies, and the modification times of their cached files, to their properties.
+	recordCacheTimestamps bool
+	// If set, the dependencies of unchanged projects are read from this directory, rather than collected again.
+	incrementalCacheDir string
+	// If set, the checksums of unchanged files are read from this cache, rather than calculated again.
+	checksumCache *utils.ChecksumCache
+	// Determines how a mismatch between a dependency's checksum and the hash declared in its lockfile is handled.
+	integrityVerification utils.IntegrityVerificationMode
+	// Properties added to the build-info and to each of its modules.
+	buildProperties  map[string]string
+	moduleProperties map[string]string
+	// The deploy path configurations of the artifacts, by their modules' types.
+	deployPaths map[entities.ModuleType]DeployPathConfig
+	// The templates of the modules' IDs, by the modules' types.
+	moduleIdTemplates map[entities.ModuleType]string
+	// Move the dependencies shared by several modules to shared dependencies modules.
+	shareDependencies bool
+	// Compress the build-info files saved in the local cache with gzip.
+	compress bool
+	// Rules which exclude the matching dependencies from the build-info.
+	dependencyExclusions []DependencyExclusion
+	// Caps on the requestedBy paths of each dependency.
+	requestedByLimits RequestedByLimits
+	// Test reports whose results are summarized in the properties of the build-info or its modules.
+	testReports []testReports
+	// Code coverage reports whose results are summarized in the properties of the build-info or its modules.
+	coverageReports []coverageReports
+	// Built jars whose embedded artifacts are reconciled with the dependencies of the build-info's modules.
+	jarAnalyses []jarAnalyses
+	// The patterns of the entries of archive artifacts, which are recorded as their sub-artifacts.
+	subArtifactsPatterns []string
+	// Functions which mutate the build-info created by ToBuildInfo, after all the other changes to it.
+	postProcessors []PostProcessor
+	// If set, encrypts the matching properties of the build-info created by ToBuildInfo, after the post-processors.
+	propertyEncrypter *PropertyEncrypter
+	// If set, the modules of the build-info created by ToBuildInfo may have types which aren't known module types.
+	allowCustomModuleTypes bool
+	// If set, the modules saved by SaveBuildInfo are collected by CollectIncrementally, and the duration of their collection is added to their properties.
+	collectionStarted time.Time
+	// If set, receives the modules and dependencies as soon as they're collected, and the build-info created by ToBuildInfo.
+	collectionListener CollectionListener
+	// If set, the output of the build tools' commands is written to it rather than to the standard output. See SetCommandOutput.
+	commandOutput io.Writer
+	// Counts the dependencies, checksums, cache hits and commands of the collectors. See CollectionStats.
+	statsCounter *collectionStatsCounter
 }
 
 func NewBuild(buildName, buildNumber string, buildTimestamp time.Time, projectKey, tempDirPath string, logger utils.Log) *Build {
@@ -48,6 +102,7 @@ func NewBuild(buildName, buildNumber string, buildTimestamp time.Time, projectKe
 		projectKey:     projectKey,
 		tempDirPath:    tempDirPath,
 		logger:         logger,
+		statsCounter:   newCollectionStatsCounter(),
 	}
 }
 
@@ -76,6 +131,125 @@ func (b *Build) SetBuildUrl(buildUrl string) {
 	b.buildUrl = buildUrl
 }
 
+// SetResolutionAudit sets whether the resolution source of each dependency should be kept in the build-info and summarized.
+// This field is not saved in local cache. It is used only when creating a build-info using the ToBuildInfo() function.
+func (b *Build) SetResolutionAudit(resolutionAudit bool) {
+	b.resolutionAudit = resolutionAudit
+}
+
+// SetChecksumCache sets the cache from which the checksums of files which didn't change since they were cached are read,
+// rather than calculated again. Pass nil to disable it.
+// The cache isn't saved by the build. Call its Save method after the collection.
+func (b *Build) SetChecksumCache(checksumCache *utils.ChecksumCache) {
+	b.checksumCache = checksumCache
+	b.statsCounter.setChecksumCacheHitsBaseline(checksumCache.Hits())
+}
+
+// SetIntegrityVerification sets whether the checksums of the dependencies should be verified against the hashes declared in the project's lockfile
+// (package-lock.json, go.sum or poetry.lock), and whether a mismatch should fail the collection or only log a warning.
+// It also determines whether a mismatch between the versions of the Gradle dependencies and their gradle.lockfile fails the collection.
+func (b *Build) SetIntegrityVerification(integrityVerification utils.IntegrityVerificationMode) {
+	b.integrityVerification = integrityVerification
+}
+
+// SetBuildProperties sets properties, such as a ticket ID or a pipeline ID, which are added to the build-info's properties.
+// These properties are not saved in local cache. They are used only when creating a build-info using the ToBuildInfo() function.
+func (b *Build) SetBuildProperties(buildProperties map[string]string) {
+	b.buildProperties = buildProperties
+}
+
+// SetModuleProperties sets properties which are added to the properties of each of the build-info's modules.
+// These properties are not saved in local cache. They are used only when creating a build-info using the ToBuildInfo() function.
+func (b *Build) SetModuleProperties(moduleProperties map[string]string) {
+	b.moduleProperties = moduleProperties
+}
+
+// SetDeployPaths sets the deploy path configurations, by package type, which determine the paths of the artifacts of the matching modules.
+// These configurations are not saved in local cache. They are used only when creating a build-info using the ToBuildInfo() function.
+func (b *Build) SetDeployPaths(deployPaths map[entities.ModuleType]DeployPathConfig) {
+	b.deployPaths = deployPaths
+}
+
+// SetModuleIdTemplates sets the templates of the modules' IDs, by package type, for consistent module IDs across the technologies,
+// for example: {maven: "{group}:{name}:{version}", npm: "{group}:{name}:{version}"}. See ResolveModuleId for the templates' syntax.
+// These templates are not saved in local cache. They are used only when creating a build-info using the ToBuildInfo() function.
+func (b *Build) SetModuleIdTemplates(moduleIdTemplates map[entities.ModuleType]string) {
+	b.moduleIdTemplates = moduleIdTemplates
+}
+
+// SetShareDependencies sets whether the dependencies which are identical in several modules should be moved to shared dependencies modules,
+// to reduce the size of builds with many modules. See entities.BuildInfo.ShareDependencies for details.
+// This option is not saved in local cache. It is used only when creating a build-info using the ToBuildInfo() function.
+func (b *Build) SetShareDependencies(shareDependencies bool) {
+	b.shareDependencies = shareDependencies
+}
+
+// SetCompress sets whether the build-info files saved in the local cache by SaveBuildInfo should be compressed with gzip.
+// Both compressed and uncompressed files are read when creating a build-info using the ToBuildInfo() function.
+func (b *Build) SetCompress(compress bool) {
+	b.compress = compress
+}
+
+// SetSubArtifactsPatterns sets the patterns of the entries of the archive artifacts added by AddGenericArtifacts,
+// such as the jars inside a WAR, which are recorded as the artifacts' sub-artifacts. See GetSubArtifacts for the patterns' syntax.
+func (b *Build) SetSubArtifactsPatterns(patterns ...string) {
+	b.subArtifactsPatterns = patterns
+}
+
+// AddDependencyExclusions adds rules which exclude the matching dependencies from the modules of the build-info,
+// regardless of the technology which collected them.
+// These rules are not saved in local cache. They are used only when creating a build-info using the ToBuildInfo() function.
+func (b *Build) AddDependencyExclusions(exclusions ...DependencyExclusion) {
+	b.dependencyExclusions = append(b.dependencyExclusions, exclusions...)
+}
+
+// SetRequestedByLimits sets caps on the number and the depth of the requestedBy paths of each dependency, to limit the size of the build-info.
+// See RequestedByLimits for the paths which are kept.
+// These limits are not saved in local cache. They are used only when creating a build-info using the ToBuildInfo() function.
+func (b *Build) SetRequestedByLimits(limits RequestedByLimits) {
+	b.requestedByLimits = limits
+}
+
+// AddPostProcessors adds functions which mutate the build-info, for example to rename modules, add properties or remove dependencies.
+// They're called in the order they were added, after all the other changes to the build-info.
+// These functions are not saved in local cache. They are used only when creating a build-info using the ToBuildInfo() function.
+func (b *Build) AddPostProcessors(postProcessors ...PostProcessor) {
+	b.postProcessors = append(b.postProcessors, postProcessors...)
+}
+
+// SetPropertyEncrypter sets the encrypter of the build-info's properties whose keys match its patterns. Pass nil to disable it.
+// The properties are encrypted after the post-processors, so the post-processors receive their plain values.
+// The encrypter is not saved in local cache. It is used only when creating a build-info using the ToBuildInfo() function.
+func (b *Build) SetPropertyEncrypter(propertyEncrypter *PropertyEncrypter) {
+	b.propertyEncrypter = propertyEncrypter
+}
+
+// SetAllowCustomModuleTypes sets whether the modules may have types which aren't known module types (see entities.ModuleType.IsKnown).
+// By default, ToBuildInfo() fails if the type of a module, for example one set by a post-processor, isn't known.
+func (b *Build) SetAllowCustomModuleTypes(allowCustomModuleTypes bool) {
+	b.allowCustomModuleTypes = allowCustomModuleTypes
+}
+
+// SetCollectionListener sets a listener which receives the modules and their dependencies as soon as they're collected,
+// and the build-info created by ToBuildInfo().
+func (b *Build) SetCollectionListener(listener CollectionListener) {
+	b.collectionListener = listener
+}
+
+// SetCommandOutput sets the writer of the output of the build tools' commands run by the modules, such as 'npm install', 'gradle build' and 'dotnet restore',
+// which is written to the standard output by default. Set it to os.Stderr if the standard output carries the events of a CollectionListener.
+func (b *Build) SetCommandOutput(writer io.Writer) {
+	b.commandOutput = writer
+}
+
+// Returns the writer of the output of the build tools' commands. See SetCommandOutput.
+func (b *Build) getCommandOutput() io.Writer {
+	if b.commandOutput == nil {
+		return os.Stdout
+	}
+	return b.commandOutput
+}
+
 // AddGoModule adds a Go module to this Build. Pass srcPath as an empty string if the root of the Go project is the working directory.
 func (b *Build) AddGoModule(srcPath string) (*GoModule, error) {
 	return newGoModule(srcPath, b)
@@ -96,6 +270,20 @@ func (b *Build) AddNpmModule(srcPath string) (*NpmModule, error) {
 	return newNpmModule(srcPath, b)
 }
 
+// AddOfflineNpmModule adds an npm module to this Build, whose dependencies are built directly from its lockfile, without running npm,
+// which makes the collection deterministic and faster. The npm CLI doesn't have to be installed.
+// Pass srcPath as an empty string if the root of the npm project is the working directory.
+func (b *Build) AddOfflineNpmModule(srcPath string) (*NpmModule, error) {
+	return newOfflineNpmModule(srcPath, b)
+}
+
+// AddNpmModuleFromManifests adds an npm module to this Build, whose dependencies are built from the package.json and package-lock.json in the reader,
+// without running npm and without reading the project from the disk. See buildutils.ReadNpmManifests for the supported payload formats.
+// The dependencies' checksums aren't calculated, since the npm cache isn't used.
+func (b *Build) AddNpmModuleFromManifests(manifests io.Reader) (*NpmModule, error) {
+	return newNpmModuleFromManifests(manifests, b)
+}
+
 // AddPythonModule adds a Python module to this Build. Pass srcPath as an empty string if the root of the python project is the working directory.
 func (b *Build) AddPythonModule(srcPath string, tool pythonutils.PythonTool) (*PythonModule, error) {
 	return newPythonModule(srcPath, tool, b)
@@ -106,6 +294,41 @@ func (b *Build) AddYarnModule(srcPath string) (*YarnModule, error) {
 	return newYarnModule(srcPath, b)
 }
 
+// AddBundlerModule adds a Bundler (RubyGems) module to this Build. Pass srcPath as an empty string if the root of the Bundler project is the working directory.
+func (b *Build) AddBundlerModule(srcPath string) (*BundlerModule, error) {
+	return newBundlerModule(srcPath, b)
+}
+
+// AddMixModule adds an Elixir Mix (Hex) module to this Build. Pass srcPath as an empty string if the root of the Mix project is the working directory.
+func (b *Build) AddMixModule(srcPath string) (*MixModule, error) {
+	return newMixModule(srcPath, b)
+}
+
+// AddHaskellModule adds a Haskell (Cabal or Stack) module to this Build. Pass srcPath as an empty string if the root of the Haskell project is the working directory.
+func (b *Build) AddHaskellModule(srcPath string) (*HaskellModule, error) {
+	return newHaskellModule(srcPath, b)
+}
+
+// AddZigModule adds a Zig package module to this Build. Pass srcPath as an empty string if the root of the Zig package is the working directory.
+func (b *Build) AddZigModule(srcPath string) (*ZigModule, error) {
+	return newZigModule(srcPath, b)
+}
+
+// AddHelmModule adds a Helm chart module to this Build. Pass srcPath as an empty string if the root of the chart is the working directory.
+func (b *Build) AddHelmModule(srcPath string) (*HelmModule, error) {
+	return newHelmModule(srcPath, b)
+}
+
+// AddCMakeModule adds a CMake project module to this Build. Pass srcPath as an empty string if the root of the CMake project is the working directory.
+func (b *Build) AddCMakeModule(srcPath string) (*CMakeModule, error) {
+	return newCMakeModule(srcPath, b)
+}
+
+// AddVcpkgModule adds a vcpkg manifest module to this Build. Pass srcPath as an empty string if the root of the vcpkg project is the working directory.
+func (b *Build) AddVcpkgModule(srcPath string) (*VcpkgModule, error) {
+	return newVcpkgModule(srcPath, b)
+}
+
 // AddNugetModules adds a Nuget module to this Build. Pass srcPath as an empty string if the root of the Nuget project is the working directory.
 func (b *Build) AddNugetModules(srcPath string) (*DotnetModule, error) {
 	return newDotnetModule(srcPath, b)
@@ -131,11 +354,18 @@ func (b *Build) CollectEnv() error {
 	return b.SavePartialBuildInfo(partial)
 }
 
-func (b *Build) Clean() error {
+func (b *Build) Clean() (err error) {
 	tempDirPath, err := utils.GetBuildDir(b.buildName, b.buildNumber, b.projectKey, b.tempDirPath)
 	if err != nil {
 		return err
 	}
+	unlock, err := utils.LockBuildDir(b.buildName, b.buildNumber, b.projectKey, b.tempDirPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, unlock())
+	}()
 	exists, err := utils.IsDirExists(tempDirPath, true)
 	if err != nil {
 		return err
@@ -150,7 +380,7 @@ func (b *Build) ToBuildInfo() (*entities.BuildInfo, error) {
 	if !b.buildNameAndNumberProvided() {
 		return nil, errors.New("a build name must be provided in order to generate build-info")
 	}
-	buildInfo, err := b.createBuildInfoFromPartials()
+	buildInfo, generatedBuildsInfo, err := b.readBuildFiles()
 	if err != nil {
 		return nil, err
 	}
@@ -160,17 +390,100 @@ func (b *Build) ToBuildInfo() (*entities.BuildInfo, error) {
 	buildInfo.Principal = b.principal
 	buildInfo.BuildUrl = b.buildUrl
 
-	generatedBuildsInfo, err := b.getGeneratedBuildsInfo()
-	if err != nil {
-		return nil, err
-	}
 	for _, v := range generatedBuildsInfo {
 		buildInfo.Append(v)
 	}
+	buildInfo.AddProperties(b.buildProperties)
+	for i := range buildInfo.Modules {
+		buildInfo.Modules[i].AddProperties(b.moduleProperties)
+	}
+	if err = applyTestReports(buildInfo, b.testReports, b.logger); err != nil {
+		return nil, err
+	}
+	if err = applyCoverageReports(buildInfo, b.coverageReports, b.logger); err != nil {
+		return nil, err
+	}
+	if err = applyJarAnalyses(buildInfo, b.jarAnalyses, b.logger); err != nil {
+		return nil, err
+	}
+	applyDeployPaths(buildInfo, b.deployPaths)
+	// The deploy paths are resolved from the original module IDs, which are in the formats the collectors produce.
+	if err = applyModuleIdTemplates(buildInfo, b.moduleIdTemplates); err != nil {
+		return nil, err
+	}
+	if err = applyDependencyExclusions(buildInfo, b.dependencyExclusions); err != nil {
+		return nil, err
+	}
+	applyMutableDependencies(buildInfo, b.logger)
+	if b.recordCacheTimestamps {
+		if err = b.applyCachedFileTimestamps(buildInfo); err != nil {
+			return nil, err
+		}
+	}
+	applyRequestedByLimits(buildInfo, b.requestedByLimits, b.logger)
+	buildInfo.SetComponentIds()
 
+	if b.resolutionAudit {
+		b.logResolutionSourcesSummary(buildInfo)
+	} else {
+		buildInfo.ClearResolutionSources()
+	}
+	if b.shareDependencies {
+		if err = buildInfo.ShareDependencies(); err != nil {
+			return nil, err
+		}
+	}
+	if err = applyPostProcessors(buildInfo, b.postProcessors); err != nil {
+		return nil, err
+	}
+	if !b.allowCustomModuleTypes {
+		if err = buildInfo.ValidateModuleTypes(); err != nil {
+			return nil, err
+		}
+	}
+	if b.propertyEncrypter != nil {
+		if err = b.propertyEncrypter.EncryptProperties(buildInfo); err != nil {
+			return nil, err
+		}
+	}
+	if b.collectionListener != nil {
+		if err = b.collectionListener(CollectionEvent{Event: BuildInfoEvent, BuildInfo: buildInfo}); err != nil {
+			return nil, err
+		}
+	}
 	return buildInfo, nil
 }
 
+// Reads the partials and the build-info files saved for the build while holding its lock, so that the files written by other processes concurrently aren't read.
+func (b *Build) readBuildFiles() (buildInfo *entities.BuildInfo, generatedBuildsInfo []*entities.BuildInfo, err error) {
+	unlock, err := utils.LockBuildDir(b.buildName, b.buildNumber, b.projectKey, b.tempDirPath)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, unlock())
+	}()
+	if buildInfo, err = b.createBuildInfoFromPartials(); err != nil {
+		return
+	}
+	generatedBuildsInfo, err = b.getGeneratedBuildsInfo()
+	return
+}
+
+func (b *Build) logResolutionSourcesSummary(buildInfo *entities.BuildInfo) {
+	summary := buildInfo.ResolutionSourcesSummary()
+	if len(summary) == 0 {
+		return
+	}
+	sources := maps.Keys(summary)
+	slices.Sort(sources)
+	var summaryLines []string
+	for _, source := range sources {
+		summaryLines = append(summaryLines, fmt.Sprintf("%s: %d", source, summary[source]))
+	}
+	b.logger.Info("Dependency resolution sources summary:\n" + strings.Join(summaryLines, "\n"))
+}
+
 func (b *Build) getGeneratedBuildsInfo() ([]*entities.BuildInfo, error) {
 	buildDir, err := utils.GetBuildDir(b.buildName, b.buildNumber, b.projectKey, b.tempDirPath)
 	if err != nil {
@@ -187,10 +500,11 @@ func (b *Build) getGeneratedBuildsInfo() ([]*entities.BuildInfo, error) {
 		if err != nil {
 			return nil, err
 		}
-		if dir {
+		// The temporary files of interrupted writes are skipped.
+		if dir || strings.HasSuffix(buildFile, utils.AtomicTempFileSuffix) {
 			continue
 		}
-		content, err := os.ReadFile(buildFile)
+		content, err := readBuildInfoFile(buildFile)
 		if err != nil {
 			return nil, err
 		}
@@ -207,28 +521,91 @@ func (b *Build) getGeneratedBuildsInfo() ([]*entities.BuildInfo, error) {
 	return generatedBuildsInfo, nil
 }
 
-func (b *Build) SaveBuildInfo(buildInfo *entities.BuildInfo) (err error) {
-	buildJson, err := json.Marshal(buildInfo)
-	if err != nil {
-		return
+func (b *Build) SaveBuildInfo(buildInfo *entities.BuildInfo) error {
+	return b.saveBuildInfo(buildInfo, true)
+}
+
+// Saves the build-info in the builds directory. If notify is false, the modules aren't sent to the collection listener and aren't added to the stats,
+// because they were already, for example by the build which collected them.
+func (b *Build) saveBuildInfo(buildInfo *entities.BuildInfo, notify bool) (err error) {
+	if !b.collectionStarted.IsZero() {
+		addCollectDuration(buildInfo.Modules, b.collectionStarted)
 	}
-	var content bytes.Buffer
-	err = json.Indent(&content, buildJson, "", "  ")
+	if b.recordCacheTimestamps {
+		addResolutionTimes(buildInfo.Modules, time.Now())
+	}
+	dirPath, err := utils.GetBuildDir(b.buildName, b.buildNumber, b.projectKey, b.tempDirPath)
 	if err != nil {
 		return
 	}
-	dirPath, err := utils.GetBuildDir(b.buildName, b.buildNumber, b.projectKey, b.tempDirPath)
+	unlock, err := utils.LockBuildDir(b.buildName, b.buildNumber, b.projectKey, b.tempDirPath)
 	if err != nil {
 		return
 	}
+	defer func() {
+		err = errors.Join(err, unlock())
+	}()
 	b.logger.Debug("Creating temp build file at: " + dirPath)
+	// The empty file reserves a unique name, and is replaced by the build-info once it's completely written.
+	// An empty file, which is left if the process is interrupted, is skipped by the readers of the build's files.
 	tempFile, err := utils.CreateTempBuildFile(b.buildName, b.buildNumber, b.projectKey, b.tempDirPath, b.logger)
 	if err != nil {
 		return
 	}
-	defer ioutils.Close(tempFile, &err)
-	_, err = tempFile.Write(content.Bytes())
-	return
+	if err = tempFile.Close(); err != nil {
+		return
+	}
+	// The build-info is streamed to the file, since it may be very large.
+	err = utils.StreamFileAtomically(tempFile.Name(), 0600, func(file io.Writer) error {
+		writer := bufio.NewWriter(file)
+		if b.compress {
+			gzipWriter := gzip.NewWriter(writer)
+			if _, err := buildInfo.WriteTo(gzipWriter); err != nil {
+				return err
+			}
+			if err := gzipWriter.Close(); err != nil {
+				return err
+			}
+		} else if _, err := buildInfo.WriteTo(writer); err != nil {
+			return err
+		}
+		return writer.Flush()
+	})
+	if err != nil {
+		return
+	}
+	if !notify {
+		return
+	}
+	b.statsCounter.addModules(buildInfo.Modules)
+	return b.notifyModulesCollected(buildInfo.Modules)
+}
+
+// Reads a build-info file from the local cache, which may be compressed with gzip.
+func readBuildInfoFile(path string) (content []byte, err error) {
+	content, err = os.ReadFile(path)
+	if err != nil || !bytes.HasPrefix(content, gzipMagicNumber) {
+		return
+	}
+	gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer ioutils.Close(gzipReader, &err)
+	return io.ReadAll(gzipReader)
+}
+
+// ReadBuildInfo reads a build-info JSON file, such as the output of the CLI, which may be compressed with gzip.
+func ReadBuildInfo(path string) (*entities.BuildInfo, error) {
+	content, err := readBuildInfoFile(path)
+	if err != nil {
+		return nil, err
+	}
+	buildInfo := new(entities.BuildInfo)
+	if err = json.Unmarshal(content, buildInfo); err != nil {
+		return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed to parse the build-info file %s: %w", path, err))
+	}
+	return buildInfo, nil
 }
 
 // SavePartialBuildInfo saves the given partial in the builds directory.
@@ -248,13 +625,26 @@ func (b *Build) SavePartialBuildInfo(partial *entities.Partial) (err error) {
 	if err != nil {
 		return
 	}
+	unlock, err := utils.LockBuildDir(b.buildName, b.buildNumber, b.projectKey, b.tempDirPath)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, unlock())
+	}()
 	b.logger.Debug("Creating temp build file at:", dirPath)
 	tempFile, err := os.CreateTemp(dirPath, "temp")
 	if err != nil {
 		return
 	}
-	defer ioutils.Close(tempFile, &err)
-	_, err = tempFile.Write(content.Bytes())
+	// Like in SaveBuildInfo, the empty file reserves a unique name for the partial.
+	if err = tempFile.Close(); err != nil {
+		return
+	}
+	if err = utils.WriteFileAtomically(tempFile.Name(), content.Bytes(), 0600); err != nil {
+		return
+	}
+	b.statsCounter.addDependencies(partial.Dependencies)
 	return
 }
 
@@ -273,6 +663,7 @@ func (b *Build) createBuildInfoFromPartials() (*entities.BuildInfo, error) {
 		return nil, err
 	}
 	buildInfo.Started = buildGeneralDetails.Timestamp.Format(entities.TimeFormat)
+	buildInfo.DurationMillis = time.Since(buildGeneralDetails.Timestamp).Milliseconds()
 	modules, env, vcsList, issues, err := extractBuildInfoData(partials)
 	if err != nil {
 		return nil, err
@@ -311,13 +702,16 @@ func (b *Build) readPartialBuildInfoFiles() (entities.Partials, error) {
 		if err != nil {
 			return nil, err
 		}
-		if dir || strings.HasSuffix(buildFile, BuildInfoDetails) {
+		if dir || strings.HasSuffix(buildFile, BuildInfoDetails) || strings.HasSuffix(buildFile, utils.AtomicTempFileSuffix) {
 			continue
 		}
 		content, err := os.ReadFile(buildFile)
 		if err != nil {
 			return nil, err
 		}
+		if len(content) == 0 {
+			continue
+		}
 		partial := new(entities.Partial)
 		err = json.Unmarshal(content, &partial)
 		if err != nil {
@@ -523,3 +917,27 @@ func createEmptyBuildInfoFile(containingBuild *Build) (string, error) {
 	// If this is a Windows machine, there is a need to modify the path for the build info file to match Java syntax with double \\
 	return utils.DoubleWinPathSeparator(buildDir.Name()), nil
 }
+
+// Reads the build-info generated by an extractor, updates it using the provided function and writes it back.
+func (b *Build) updateGeneratedBuildInfo(buildInfoPath string, update func(buildInfo *entities.BuildInfo)) (err error) {
+	unlock, err := utils.LockBuildDir(b.buildName, b.buildNumber, b.projectKey, b.tempDirPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, unlock())
+	}()
+	content, err := os.ReadFile(buildInfoPath)
+	if err != nil || len(content) == 0 {
+		return err
+	}
+	buildInfo := new(entities.BuildInfo)
+	if err = json.Unmarshal(content, buildInfo); err != nil {
+		return err
+	}
+	update(buildInfo)
+	if content, err = json.MarshalIndent(buildInfo, "", "  "); err != nil {
+		return err
+	}
+	return utils.WriteFileAtomically(buildInfoPath, content, 0600)
+}
@@ -1,10 +1,15 @@
 package build
 
 import (
+	"bytes"
 	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
 	"github.com/stretchr/testify/assert"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestCollectEnv(t *testing.T) {
@@ -79,3 +84,66 @@ func TestCollectEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestSaveCompressedBuildInfo(t *testing.T) {
+	bld := createTestBuild(t, "compressed-build")
+	modules := []entities.Module{{Id: "compressed", Type: entities.Go, Dependencies: []entities.Dependency{{Id: "dep:1.0"}}}}
+	bld.SetCompress(true)
+	assert.NoError(t, bld.SaveBuildInfo(&entities.BuildInfo{Modules: modules}))
+	buildDir, err := utils.GetBuildDir(bld.buildName, bld.buildNumber, bld.projectKey, bld.tempDirPath)
+	assert.NoError(t, err)
+	buildFiles, err := utils.ListFiles(buildDir, false)
+	assert.NoError(t, err)
+	assert.Len(t, buildFiles, 1)
+	content, err := os.ReadFile(buildFiles[0])
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(content, gzipMagicNumber))
+
+	buildInfo, err := ReadBuildInfo(buildFiles[0])
+	assert.NoError(t, err)
+	assert.Equal(t, modules, buildInfo.Modules)
+
+	buildInfo, err = bld.ToBuildInfo()
+	assert.NoError(t, err)
+	modules[0].Dependencies[0].Purl = "pkg:golang/dep@1.0"
+	modules[0].Dependencies[0].CorrelationIds = map[string]string{entities.XrayCorrelationKey: "go://dep:1.0"}
+	assert.Equal(t, modules, buildInfo.Modules)
+}
+
+func TestToBuildInfoSkipsInterruptedWrites(t *testing.T) {
+	bld := createTestBuild(t, "interrupted-build")
+	modules := []entities.Module{{Id: "interrupted", Type: entities.Generic, Dependencies: []entities.Dependency{{Id: "dep:1.0"}}}}
+	assert.NoError(t, bld.SaveBuildInfo(&entities.BuildInfo{Modules: modules}))
+
+	// The files left by a process which was interrupted while saving a build-info and a partial.
+	buildDir, err := utils.GetBuildDir(bld.buildName, bld.buildNumber, bld.projectKey, bld.tempDirPath)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(buildDir, "temp123"), nil, 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(buildDir, "temp123.456"+utils.AtomicTempFileSuffix), []byte(`{"modules":[{"id":`), 0600))
+	partialsDir, err := utils.GetPartialsBuildDir(bld.buildName, bld.buildNumber, bld.projectKey, bld.tempDirPath)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(partialsDir, "temp789"), nil, 0600))
+
+	buildInfo, err := bld.ToBuildInfo()
+	if assert.NoError(t, err) && assert.Len(t, buildInfo.Modules, 1) {
+		assert.Equal(t, "interrupted", buildInfo.Modules[0].Id)
+	}
+}
+
+func TestSetCommandOutput(t *testing.T) {
+	bld := NewBuild("build-info-go-test-command-output", "1", time.Now(), "", t.TempDir(), &utils.NullLog{})
+	assert.Equal(t, os.Stdout, bld.getCommandOutput())
+
+	// The output of the build tools' commands is written to the command output, rather than to the standard output.
+	restore := utils.SetCommandRunner(utils.CommandRunnerFunc(func(command *exec.Cmd) ([]byte, []byte, error) {
+		assert.Equal(t, []string{"npm", "install"}, command.Args)
+		return []byte("added 1 package\n"), nil, nil
+	}))
+	defer restore()
+	var commandOutput bytes.Buffer
+	bld.SetCommandOutput(&commandOutput)
+	assert.Equal(t, &commandOutput, bld.getCommandOutput())
+	npmModule := &NpmModule{containingBuild: bld, executablePath: "npm", srcPath: t.TempDir(), npmArgs: []string{"install"}}
+	assert.NoError(t, npmModule.Build())
+	assert.Equal(t, "added 1 package\n", commandOutput.String())
+}
@@ -0,0 +1,287 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+	"golang.org/x/exp/maps"
+)
+
+// The directories in which 'gem build' and 'rake build' write the packages of the project's gem.
+var gemPackageDirs = []string{".", "pkg"}
+
+type BundlerModule struct {
+	containingBuild *Build
+	name            string
+	srcPath         string
+	// The arguments of a bundle command, such as 'install', which runs before the dependencies are collected.
+	bundlerArgs []string
+	// The duration of the command, if it ran.
+	commandDuration time.Duration
+}
+
+// Pass an empty string for srcPath to find the Bundler project in the working directory.
+func newBundlerModule(srcPath string, containingBuild *Build) (*BundlerModule, error) {
+	if srcPath == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		srcPath, err = utils.FindFileInDirAndParents(wd, buildutils.GemfileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &BundlerModule{srcPath: srcPath, containingBuild: containingBuild}, nil
+}
+
+func (bm *BundlerModule) SetName(name string) {
+	bm.name = name
+}
+
+// SetBundlerArgs sets the arguments of a bundle command, for example: install --jobs 4
+// The command runs in the project's directory before the dependencies are collected, so that the Gemfile.lock is written and the gems are cached.
+func (bm *BundlerModule) SetBundlerArgs(bundlerArgs []string) {
+	bm.bundlerArgs = bundlerArgs
+}
+
+// Build runs the bundle command set by SetBundlerArgs, if any, and then collects the project's dependencies.
+func (bm *BundlerModule) Build() error {
+	if len(bm.bundlerArgs) > 0 {
+		command := exec.Command("bundle", bm.bundlerArgs...)
+		command.Dir = bm.srcPath
+		var err error
+		if bm.commandDuration, err = runModuleCommand(command); err != nil {
+			return fmt.Errorf("failed running 'bundle %s': %w", strings.Join(bm.bundlerArgs, " "), err)
+		}
+	}
+	return bm.CalcDependencies()
+}
+
+// CalcDependencies collects the gems resolved in the project's Gemfile.lock, without running Bundler.
+// The scopes of the gems are the groups of the Gemfile which require them, directly or through other gems.
+// The checksums of the gems are calculated from their packages in the gem caches, or taken from the lockfile's CHECKSUMS section if they aren't cached.
+// If the project builds a gem, the gem's packages built by 'gem build' or 'rake build' are added as the module's artifacts.
+func (bm *BundlerModule) CalcDependencies() error {
+	if !bm.containingBuild.buildNameAndNumberProvided() {
+		return errors.New("a build name must be provided in order to collect the project's dependencies")
+	}
+	lock, err := buildutils.ReadGemfileLock(bm.srcPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("no Gemfile.lock was found in " + bm.srcPath + ". Run 'bundle install' or 'bundle lock' before collecting the dependencies")
+		}
+		return err
+	}
+	projectGem := lock.GetProjectGem()
+	bm.setModuleId(projectGem)
+	dependencies, err := bm.getDependencies(lock, projectGem)
+	if err != nil {
+		return err
+	}
+	buildInfoModule := entities.Module{Id: bm.name, Type: entities.Gem, Dependencies: dependencies}
+	if projectGem != nil {
+		if buildInfoModule.Artifacts, err = bm.getGemArtifacts(projectGem); err != nil {
+			return err
+		}
+	}
+	addCommandDuration(&buildInfoModule, bm.commandDuration)
+	return bm.containingBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{buildInfoModule}})
+}
+
+// If the module ID wasn't set, it's the ID of the project's gem, or the name of the project's directory.
+func (bm *BundlerModule) setModuleId(projectGem *buildutils.GemSpec) {
+	if bm.name != "" {
+		return
+	}
+	if projectGem != nil {
+		bm.name = projectGem.Id()
+		return
+	}
+	bm.name = filepath.Base(bm.srcPath)
+	bm.containingBuild.logger.Debug(fmt.Sprintf("The project doesn't build a gem. Using its directory name: %s as the module name.", bm.name))
+}
+
+func (bm *BundlerModule) getDependencies(lock *buildutils.GemfileLock, projectGem *buildutils.GemSpec) ([]entities.Dependency, error) {
+	gemfileGroups, loadsGemspec, err := buildutils.ReadGemfileGroups(bm.srcPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	cacheDirs, err := buildutils.GetGemCacheDirs(bm.srcPath)
+	if err != nil {
+		return nil, err
+	}
+	// The specs of each gem, by the gem's name. A gem with native extensions has a spec for each of its platforms.
+	specsByName := make(map[string][]*buildutils.GemSpec)
+	for _, spec := range lock.Specs {
+		if projectGem == nil || spec.Name != projectGem.Name {
+			specsByName[spec.Name] = append(specsByName[spec.Name], spec)
+		}
+	}
+	directGroups := make(map[string][]string)
+	for _, name := range lock.Dependencies {
+		groups, declared := gemfileGroups[name]
+		if !declared {
+			groups = []string{buildutils.DefaultGemGroup}
+			if loadsGemspec {
+				groups = []string{buildutils.DevelopmentGemGroup}
+			}
+		}
+		directGroups[name] = groups
+	}
+	if projectGem != nil {
+		// The runtime dependencies of the project's gem are required by the project itself.
+		for _, name := range projectGem.Dependencies {
+			directGroups[name] = addSortedUnique(directGroups[name], buildutils.DefaultGemGroup)
+		}
+		delete(directGroups, projectGem.Name)
+	}
+
+	dependenciesMap := make(map[string]entities.Dependency)
+	dependenciesGraph := make(map[string][]string)
+	var mismatches []utils.IntegrityMismatchDetails
+	for name, specs := range specsByName {
+		dependency, mismatch, err := bm.getDependency(specs, cacheDirs)
+		if err != nil {
+			return nil, err
+		}
+		if mismatch != nil {
+			mismatches = append(mismatches, *mismatch)
+		}
+		dependency.Scopes = getGemScopes(name, directGroups, specsByName)
+		dependenciesMap[dependency.Id] = dependency
+		for _, childName := range specs[0].Dependencies {
+			if childSpecs, ok := specsByName[childName]; ok {
+				dependenciesGraph[dependency.Id] = append(dependenciesGraph[dependency.Id], childSpecs[0].Id())
+			}
+		}
+	}
+	directNames := maps.Keys(directGroups)
+	sort.Strings(directNames)
+	for _, name := range directNames {
+		if specs, ok := specsByName[name]; ok {
+			dependenciesGraph[bm.name] = append(dependenciesGraph[bm.name], specs[0].Id())
+		}
+	}
+	if err = bm.containingBuild.integrityVerification.HandleMismatches(mismatches, bm.containingBuild.logger); err != nil {
+		return nil, err
+	}
+	emptyRequestedBy := [][]string{{}}
+	populateRequestedByField(bm.name, emptyRequestedBy, dependenciesMap, dependenciesGraph)
+	return dependenciesMapToList(dependenciesMap), nil
+}
+
+// Returns the dependency of a gem, whose checksums are calculated from the first of its platforms' packages found in the gem caches.
+// If the lockfile has the SHA-256 checksum of the cached package, the checksums are compared, and a mismatch is returned if they differ.
+func (bm *BundlerModule) getDependency(specs []*buildutils.GemSpec, cacheDirs []string) (dependency entities.Dependency, mismatch *utils.IntegrityMismatchDetails, err error) {
+	dependency = entities.Dependency{Id: specs[0].Id(), Type: "gem"}
+	dependency.SetResolutionSource(entities.LockfileSource)
+	switch {
+	case specs[0].SourceType == buildutils.GemServerSource:
+		dependency.RemoteRepository = specs[0].Remote
+	case specs[0].SourceType == buildutils.PathGemSource, specs[0].Branch != "":
+		// The gems installed from a local path or from a git branch may change without a change to their versions.
+		dependency.SetMutable()
+	}
+	for _, spec := range specs {
+		gemPath, err := buildutils.FindGemFile(spec, cacheDirs)
+		if err != nil {
+			return dependency, nil, err
+		}
+		if gemPath == "" {
+			continue
+		}
+		checksums, err := bm.containingBuild.checksumCache.GetFileChecksums(gemPath)
+		if err != nil {
+			return dependency, nil, err
+		}
+		dependency.Checksum = entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}
+		dependency.SetResolutionSource(entities.CacheSource)
+		if spec.Sha256 != "" && spec.Sha256 != dependency.Sha256 && bm.containingBuild.integrityVerification != utils.IntegrityVerificationOff {
+			mismatch = &utils.IntegrityMismatchDetails{DependencyId: dependency.Id, Lockfile: buildutils.GemfileLockName, Expected: "sha256:" + spec.Sha256, Actual: "sha256:" + dependency.Sha256}
+		}
+		return dependency, mismatch, nil
+	}
+	// Without a cached package, only the SHA-256 checksum recorded in the lockfile is known.
+	for _, spec := range specs {
+		if spec.Sha256 != "" {
+			dependency.Sha256 = spec.Sha256
+			break
+		}
+	}
+	bm.containingBuild.logger.Debug("The package of the gem", dependency.Id, "wasn't found in the gem caches.")
+	return dependency, nil, nil
+}
+
+// Returns the groups of the direct dependencies which require the gem, directly or through other gems, sorted.
+func getGemScopes(name string, directGroups map[string][]string, specsByName map[string][]*buildutils.GemSpec) []string {
+	var scopes []string
+	for directName, groups := range directGroups {
+		if directName == name || gemRequires(directName, name, specsByName, map[string]bool{}) {
+			scopes = addSortedUnique(scopes, groups...)
+		}
+	}
+	return scopes
+}
+
+// Returns true if the gem named parent requires the gem named child, directly or through other gems.
+func gemRequires(parent, child string, specsByName map[string][]*buildutils.GemSpec, visited map[string]bool) bool {
+	if visited[parent] {
+		return false
+	}
+	visited[parent] = true
+	for _, spec := range specsByName[parent] {
+		for _, dependency := range spec.Dependencies {
+			if dependency == child || gemRequires(dependency, child, specsByName, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns the packages of the project's gem, built by 'gem build' into the project's directory, or by 'rake build' into its pkg directory.
+// Their paths are their deploy paths in a RubyGems repository.
+func (bm *BundlerModule) getGemArtifacts(projectGem *buildutils.GemSpec) ([]entities.Artifact, error) {
+	var artifacts []entities.Artifact
+	for _, packageDir := range gemPackageDirs {
+		gemPaths, err := filepath.Glob(filepath.Join(bm.srcPath, packageDir, projectGem.Name+"-"+projectGem.Version+"*.gem"))
+		if err != nil {
+			return nil, err
+		}
+		for _, gemPath := range gemPaths {
+			fileName := filepath.Base(gemPath)
+			// Skip the packages of other gems whose names start with the project gem's name and version.
+			if fileName != projectGem.Name+"-"+projectGem.Version+".gem" && !strings.HasPrefix(fileName, projectGem.Name+"-"+projectGem.Version+"-") {
+				continue
+			}
+			checksum, size, err := bm.containingBuild.getArtifactFileDetails(gemPath)
+			if err != nil {
+				return nil, err
+			}
+			bm.containingBuild.logger.Debug("Adding the gem package", gemPath, "to the artifacts of", projectGem.Id())
+			artifacts = append(artifacts, entities.Artifact{
+				Name:     fileName,
+				Type:     "gem",
+				Path:     "gems/" + fileName,
+				Size:     size,
+				Checksum: checksum,
+			})
+		}
+	}
+	return artifacts, nil
+}
+
+func (bm *BundlerModule) AddArtifacts(artifacts ...entities.Artifact) error {
+	return bm.containingBuild.AddArtifacts(bm.name, entities.Gem, artifacts...)
+}
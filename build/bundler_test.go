@@ -0,0 +1,150 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGemfile = `source "https://rubygems.org"
+
+gemspec
+
+gem "nokogiri", "~> 1.15"
+
+group :development, :test do
+  gem "rspec", "~> 3.12"
+end
+`
+
+const testGemfileLock = `PATH
+  remote: .
+  specs:
+    mygem (1.0.0)
+      rack (>= 2.0)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.15.4-x86_64-linux)
+      racc (~> 1.4)
+    pry (0.14.2)
+    racc (1.7.1)
+    rack (3.0.8)
+    rspec (3.12.0)
+      rspec-core (~> 3.12.0)
+    rspec-core (3.12.2)
+
+PLATFORMS
+  x86_64-linux
+
+DEPENDENCIES
+  mygem!
+  nokogiri (~> 1.15)
+  pry
+  rspec (~> 3.12)
+
+CHECKSUMS
+  racc (1.7.1) sha256=%s
+  rack (3.0.8) sha256=%s
+
+BUNDLED WITH
+   2.5.3
+`
+
+func TestGenerateBuildInfoForBundlerProject(t *testing.T) {
+	projectDir := createBundlerProject(t, "")
+	rackChecksums, err := crypto.GetFileChecksums(filepath.Join(projectDir, "vendor", "cache", "rack-3.0.8.gem"))
+	require.NoError(t, err)
+	gemChecksums, err := crypto.GetFileChecksums(filepath.Join(projectDir, "pkg", "mygem-1.0.0.gem"))
+	require.NoError(t, err)
+
+	bundlerBuild := createTestBuild(t, "build-info-go-test-bundler")
+	bundlerBuild.SetResolutionAudit(true)
+	bundlerBuild.SetIntegrityVerification(utils.IntegrityVerificationFail)
+	bundlerModule, err := bundlerBuild.AddBundlerModule(projectDir)
+	require.NoError(t, err)
+	require.NoError(t, bundlerModule.CalcDependencies())
+	buildInfo, err := bundlerBuild.ToBuildInfo()
+	require.NoError(t, err)
+
+	require.Len(t, buildInfo.Modules, 1)
+	module := buildInfo.Modules[0]
+	assert.Equal(t, "mygem:1.0.0", module.Id)
+	assert.Equal(t, entities.Gem, module.Type)
+	assert.Equal(t, []entities.Artifact{{
+		Name:     "mygem-1.0.0.gem",
+		Type:     "gem",
+		Path:     "gems/mygem-1.0.0.gem",
+		Size:     int64(len("mygem")),
+		Checksum: entities.Checksum{Sha1: gemChecksums[crypto.SHA1], Md5: gemChecksums[crypto.MD5], Sha256: gemChecksums[crypto.SHA256]},
+	}}, module.Artifacts)
+
+	dependencies := make(map[string]entities.Dependency)
+	for _, dependency := range module.Dependencies {
+		dependencies[dependency.Id] = dependency
+	}
+	assert.Len(t, dependencies, 6)
+	assert.Equal(t, entities.Dependency{
+		Id:               "rack:3.0.8",
+		Type:             "gem",
+		Scopes:           []string{buildutils.DefaultGemGroup},
+		RequestedBy:      [][]string{{"mygem:1.0.0"}},
+		RemoteRepository: "https://rubygems.org/",
+		Purl:             "pkg:gem/rack@3.0.8",
+		CorrelationIds:   map[string]string{entities.XrayCorrelationKey: "rubygems://rack:3.0.8"},
+		Properties:       map[string]string{entities.ResolutionSourceProperty: string(entities.CacheSource)},
+		Checksum:         entities.Checksum{Sha1: rackChecksums[crypto.SHA1], Md5: rackChecksums[crypto.MD5], Sha256: rackChecksums[crypto.SHA256]},
+	}, dependencies["rack:3.0.8"])
+	// The checksum of a gem which isn't cached is taken from the lockfile.
+	assert.Equal(t, entities.Checksum{Sha256: strings.Repeat("a", 64)}, dependencies["racc:1.7.1"].Checksum)
+	assert.Equal(t, string(entities.LockfileSource), dependencies["racc:1.7.1"].Properties[entities.ResolutionSourceProperty])
+	assert.Equal(t, [][]string{{"nokogiri:1.15.4", "mygem:1.0.0"}}, dependencies["racc:1.7.1"].RequestedBy)
+	assert.Equal(t, []string{buildutils.DefaultGemGroup}, dependencies["racc:1.7.1"].Scopes)
+	assert.Equal(t, []string{"development", "test"}, dependencies["rspec-core:3.12.2"].Scopes)
+	// A gem declared by the gemspec rather than the Gemfile is one of its development dependencies.
+	assert.Equal(t, []string{buildutils.DevelopmentGemGroup}, dependencies["pry:0.14.2"].Scopes)
+}
+
+func TestBundlerIntegrityMismatch(t *testing.T) {
+	projectDir := createBundlerProject(t, strings.Repeat("b", 64))
+	bundlerBuild := createTestBuild(t, "build-info-go-test-bundler-integrity")
+	bundlerBuild.SetIntegrityVerification(utils.IntegrityVerificationFail)
+	bundlerModule, err := bundlerBuild.AddBundlerModule(projectDir)
+	require.NoError(t, err)
+	assert.ErrorContains(t, bundlerModule.CalcDependencies(), "rack:3.0.8")
+
+	require.NoError(t, os.Remove(filepath.Join(projectDir, buildutils.GemfileLockName)))
+	assert.ErrorContains(t, bundlerModule.CalcDependencies(), "no Gemfile.lock was found")
+}
+
+// Creates a Bundler project whose gem is built into its pkg directory, and whose rack gem is cached in its vendor/cache directory.
+// If rackSha256 is empty, the lockfile has the checksum of the cached rack gem.
+func createBundlerProject(t *testing.T, rackSha256 string) string {
+	t.Setenv("GEM_HOME", "")
+	t.Setenv("GEM_PATH", "")
+	t.Setenv("BUNDLE_PATH", "")
+	projectDir := t.TempDir()
+	vendorCache := filepath.Join(projectDir, "vendor", "cache")
+	require.NoError(t, os.MkdirAll(vendorCache, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, "pkg"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(vendorCache, "rack-3.0.8.gem"), []byte("rack"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "pkg", "mygem-1.0.0.gem"), []byte("mygem"), 0644))
+	if rackSha256 == "" {
+		checksums, err := crypto.GetFileChecksums(filepath.Join(vendorCache, "rack-3.0.8.gem"))
+		require.NoError(t, err)
+		rackSha256 = checksums[crypto.SHA256]
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, buildutils.GemfileName), []byte(testGemfile), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, buildutils.GemfileLockName), []byte(fmt.Sprintf(testGemfileLock, strings.Repeat("a", 64), rackSha256)), 0644))
+	return projectDir
+}
@@ -0,0 +1,103 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gofrog/crypto"
+)
+
+// The properties of the dependencies, which allow auditing the freshness of the cached files the dependencies were resolved from.
+// They're added only if the build records them. See Build.SetRecordCacheTimestamps.
+const (
+	// The time in which the dependency was resolved, which is the time its module was saved by the collector.
+	DependencyResolvedAtProperty = "buildInfo.timing.resolvedAt"
+	// The modification time of the dependency's file in the local caches, from which its checksums were calculated.
+	// A file which was modified long before the build was downloaded by an earlier build, and reused by this one.
+	CachedFileModifiedAtProperty = "buildInfo.timing.cachedFileModifiedAt"
+)
+
+// SetRecordCacheTimestamps sets whether the time in which each dependency was resolved, and the modification time of its file in the local caches,
+// are added to the dependency's properties, so that stale files, which were downloaded before the build and reused by it, can be detected.
+// The files are looked up in the local caches of Maven, Gradle, npm, pip, Conan and Helm, like in BackfillChecksums.
+func (b *Build) SetRecordCacheTimestamps(recordCacheTimestamps bool) {
+	b.recordCacheTimestamps = recordCacheTimestamps
+}
+
+// Adds the resolution time to the properties of the modules' dependencies, which don't have it yet.
+func addResolutionTimes(modules []entities.Module, resolvedAt time.Time) {
+	formattedTime := resolvedAt.Format(entities.TimeFormat)
+	for i := range modules {
+		for j := range modules[i].Dependencies {
+			dependency := &modules[i].Dependencies[j]
+			if _, exists := dependency.Properties[DependencyResolvedAtProperty]; exists {
+				continue
+			}
+			if dependency.Properties == nil {
+				dependency.Properties = map[string]string{}
+			}
+			dependency.Properties[DependencyResolvedAtProperty] = formattedTime
+		}
+	}
+}
+
+// Adds the resolution time to the dependencies of the build-info generated by the Maven or the Gradle extractor, after the build tool ran.
+func (b *Build) addGeneratedResolutionTimes(buildInfoPath string) error {
+	if !b.recordCacheTimestamps {
+		return nil
+	}
+	resolvedAt := time.Now()
+	return b.updateGeneratedBuildInfo(buildInfoPath, func(buildInfo *entities.BuildInfo) {
+		addResolutionTimes(buildInfo.Modules, resolvedAt)
+	})
+}
+
+// Adds the modification times of the dependencies' files in the local caches, from which their checksums were calculated, to their properties.
+// A cached file whose checksums don't match the dependency's checksums isn't the file the dependency was resolved from, so its time isn't added.
+// The dependencies without checksums weren't resolved from cached files.
+func (b *Build) applyCachedFileTimestamps(buildInfo *entities.BuildInfo) error {
+	localCaches, err := buildutils.GetDefaultLocalCaches()
+	if err != nil {
+		return err
+	}
+	for i := range buildInfo.Modules {
+		module := &buildInfo.Modules[i]
+		for j := range module.Dependencies {
+			dependency := &module.Dependencies[j]
+			if dependency.Checksum.IsEmpty() {
+				continue
+			}
+			packageUrl := getDependencyPackageUrl(module.Type, dependency)
+			if packageUrl == nil {
+				continue
+			}
+			filePath, err := localCaches.FindPackageFile(packageUrl)
+			if err != nil {
+				return fmt.Errorf("failed looking up the dependency '%s' in the local caches: %w", dependency.Id, err)
+			}
+			if filePath == "" {
+				continue
+			}
+			checksums, err := b.checksumCache.GetFileChecksums(filePath)
+			if err != nil {
+				return err
+			}
+			if !isChecksumConsistent(dependency.Checksum, entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}) {
+				b.logger.Debug("The checksums of the dependency", dependency.Id, "don't match its file in the local caches,", filePath+". Its modification time isn't recorded.")
+				continue
+			}
+			fileInfo, err := os.Stat(filePath)
+			if err != nil {
+				return err
+			}
+			if dependency.Properties == nil {
+				dependency.Properties = map[string]string{}
+			}
+			dependency.Properties[CachedFileModifiedAtProperty] = fileInfo.ModTime().Format(entities.TimeFormat)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gofrog/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordCacheTimestamps(t *testing.T) {
+	// The local Maven repository is in the user's home directory.
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GRADLE_USER_HOME", filepath.Join(home, ".gradle"))
+	jarPath := filepath.Join(home, ".m2", "repository", "org", "example", "lib", "1.0", "lib-1.0.jar")
+	require.NoError(t, os.MkdirAll(filepath.Dir(jarPath), 0755))
+	require.NoError(t, os.WriteFile(jarPath, []byte("jar"), 0644))
+	downloaded := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(jarPath, downloaded, downloaded))
+	checksums, err := crypto.GetFileChecksums(jarPath)
+	require.NoError(t, err)
+
+	bld := createTestBuild(t, "build-info-go-test-cache-timestamps")
+	bld.SetRecordCacheTimestamps(true)
+	resolutionStarted := time.Now().Truncate(time.Millisecond)
+	require.NoError(t, bld.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{{
+		Id:   "org.example:app:1.0",
+		Type: entities.Maven,
+		Dependencies: []entities.Dependency{
+			{Id: "org.example:lib:1.0", Checksum: entities.Checksum{Sha1: checksums[crypto.SHA1]}},
+			// The cached file isn't the file whose checksums were calculated.
+			{Id: "org.example:lib:1.0", Type: "jar", Scopes: []string{"test"}, Checksum: entities.Checksum{Sha1: "other"}},
+			// The dependency wasn't checksummed from a cached file.
+			{Id: "org.example:missing:1.0"},
+		},
+	}}}))
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+
+	require.Len(t, buildInfo.Modules, 1)
+	dependencies := buildInfo.Modules[0].Dependencies
+	require.Len(t, dependencies, 3)
+	for _, dependency := range dependencies {
+		resolvedAt, err := time.Parse(entities.TimeFormat, dependency.Properties[DependencyResolvedAtProperty])
+		require.NoError(t, err)
+		assert.False(t, resolvedAt.Before(resolutionStarted), dependency.Id)
+		if dependency.Sha1 == checksums[crypto.SHA1] {
+			assert.Equal(t, downloaded.Local().Format(entities.TimeFormat), dependency.Properties[CachedFileModifiedAtProperty])
+		} else {
+			assert.NotContains(t, dependency.Properties, CachedFileModifiedAtProperty, dependency.Id)
+		}
+	}
+}
+
+func TestAddResolutionTimes(t *testing.T) {
+	resolvedAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	modules := []entities.Module{{Id: "module", Dependencies: []entities.Dependency{
+		{Id: "dep-a"},
+		{Id: "dep-b", Properties: map[string]string{DependencyResolvedAtProperty: "earlier"}},
+	}}}
+	addResolutionTimes(modules, resolvedAt)
+	assert.Equal(t, resolvedAt.Format(entities.TimeFormat), modules[0].Dependencies[0].Properties[DependencyResolvedAtProperty])
+	// The dependencies resolved by an earlier collection, such as the dependencies read from the incremental cache, keep their resolution times.
+	assert.Equal(t, "earlier", modules[0].Dependencies[1].Properties[DependencyResolvedAtProperty])
+}
@@ -0,0 +1,210 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+)
+
+// The property of a CMake Git dependency, whose value is the commit checked out in its source directory.
+const CMakeGitCommitProperty = "cmake.git.commit"
+
+// The algorithms of URL_HASH which are also the algorithms of the dependencies' checksums. The other algorithms, such as SHA512, aren't verified.
+var cmakeHashAlgorithms = map[string]crypto.Algorithm{"sha256": crypto.SHA256, "sha1": crypto.SHA1, "md5": crypto.MD5}
+
+type CMakeModule struct {
+	containingBuild *Build
+	name            string
+	srcPath         string
+	buildDir        string
+	// The arguments of a cmake command, such as '-S . -B build', which runs before the dependencies are collected.
+	cmakeArgs []string
+	// The duration of the command, if it ran.
+	commandDuration time.Duration
+}
+
+// Pass an empty string for srcPath to use the CMake project in the working directory.
+func newCMakeModule(srcPath string, containingBuild *Build) (*CMakeModule, error) {
+	if srcPath == "" {
+		var err error
+		if srcPath, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	}
+	return &CMakeModule{srcPath: srcPath, buildDir: filepath.Join(srcPath, buildutils.DefaultCMakeBuildDir), containingBuild: containingBuild}, nil
+}
+
+func (cm *CMakeModule) SetName(name string) {
+	cm.name = name
+}
+
+// SetBuildDir sets the project's build directory, into which FetchContent and ExternalProject download the dependencies.
+// A relative path is relative to the project's directory. The default is the 'build' directory of the project.
+func (cm *CMakeModule) SetBuildDir(buildDir string) {
+	if !filepath.IsAbs(buildDir) {
+		buildDir = filepath.Join(cm.srcPath, buildDir)
+	}
+	cm.buildDir = buildDir
+}
+
+// SetCMakeArgs sets the arguments of a cmake command, for example: -S . -B build
+// The command runs in the project's directory before the dependencies are collected, so that the project is configured and FetchContent downloads the dependencies.
+func (cm *CMakeModule) SetCMakeArgs(cmakeArgs []string) {
+	cm.cmakeArgs = cmakeArgs
+}
+
+// Build runs the cmake command set by SetCMakeArgs, if any, and then collects the project's dependencies.
+func (cm *CMakeModule) Build() error {
+	if len(cm.cmakeArgs) > 0 {
+		command := exec.Command("cmake", cm.cmakeArgs...)
+		command.Dir = cm.srcPath
+		var err error
+		if cm.commandDuration, err = runModuleCommand(command); err != nil {
+			return fmt.Errorf("failed running 'cmake %s': %w", strings.Join(cm.cmakeArgs, " "), err)
+		}
+	}
+	return cm.CalcDependencies()
+}
+
+// CalcDependencies collects the C and C++ dependencies downloaded by FetchContent and ExternalProject into the project's build directory.
+// The dependencies resolved by package managers, such as Conan, aren't collected.
+// FetchContent and ExternalProject don't record which of the dependencies requested each other, so all the dependencies are recorded as direct dependencies.
+func (cm *CMakeModule) CalcDependencies() error {
+	if !cm.containingBuild.buildNameAndNumberProvided() {
+		return errors.New("a build name must be provided in order to collect the project's dependencies")
+	}
+	if _, err := os.Stat(cm.buildDir); err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("the CMake build directory " + cm.buildDir + " doesn't exist. Configure the project before collecting its dependencies")
+		}
+		return err
+	}
+	if err := cm.setModuleId(); err != nil {
+		return err
+	}
+	externalDependencies, err := buildutils.FindCMakeExternalDependencies(cm.buildDir)
+	if err != nil {
+		return err
+	}
+	dependenciesMap := make(map[string]entities.Dependency)
+	dependenciesGraph := make(map[string][]string)
+	var mismatches []utils.IntegrityMismatchDetails
+	for _, externalDependency := range externalDependencies {
+		dependency, mismatch, err := cm.getDependency(externalDependency)
+		if err != nil {
+			return err
+		}
+		if mismatch != nil {
+			mismatches = append(mismatches, *mismatch)
+		}
+		if _, exists := dependenciesMap[dependency.Id]; exists {
+			continue
+		}
+		dependenciesMap[dependency.Id] = dependency
+		dependenciesGraph[cm.name] = append(dependenciesGraph[cm.name], dependency.Id)
+	}
+	if err = cm.containingBuild.integrityVerification.HandleMismatches(mismatches, cm.containingBuild.logger); err != nil {
+		return err
+	}
+	emptyRequestedBy := [][]string{{}}
+	populateRequestedByField(cm.name, emptyRequestedBy, dependenciesMap, dependenciesGraph)
+	buildInfoModule := entities.Module{Id: cm.name, Type: entities.CMake, Dependencies: dependenciesMapToList(dependenciesMap)}
+	addCommandDuration(&buildInfoModule, cm.commandDuration)
+	return cm.containingBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{buildInfoModule}})
+}
+
+// If the module ID wasn't set, it's the name and the version declared by the project() command, or the name of the project's directory.
+func (cm *CMakeModule) setModuleId() error {
+	if cm.name != "" {
+		return nil
+	}
+	name, version, err := buildutils.ReadCMakeProjectName(cm.srcPath, cm.buildDir)
+	if err != nil {
+		return err
+	}
+	if name != "" {
+		cm.name = getCMakeDependencyId(name, version)
+		return nil
+	}
+	cm.name = filepath.Base(cm.srcPath)
+	cm.containingBuild.logger.Debug(fmt.Sprintf("The CMakeLists.txt doesn't declare the project's name. Using its directory name: %s as the module name.", cm.name))
+	return nil
+}
+
+// Returns the dependency of an external project, and the mismatch between its archive's checksum and the URL_HASH declared for it, if there is one.
+// The version of a Git dependency is its GIT_TAG, or its commit if the tag is unknown. The version of a URL dependency is taken from its archive's name.
+func (cm *CMakeModule) getDependency(externalDependency *buildutils.CMakeExternalDependency) (dependency entities.Dependency, mismatch *utils.IntegrityMismatchDetails, err error) {
+	var version string
+	if externalDependency.Method == buildutils.GitCMakeMethod {
+		dependency.Type = buildutils.GitCMakeMethod
+		dependency.RemoteRepository = externalDependency.Repository
+		dependency.SetResolutionSource(entities.FilesystemSource)
+		version = externalDependency.Tag
+		if version == "" {
+			version = externalDependency.Commit
+		}
+		if externalDependency.Commit != "" {
+			dependency.Properties[CMakeGitCommitProperty] = externalDependency.Commit
+		} else {
+			cm.containingBuild.logger.Debug("The commit of the CMake dependency", externalDependency.Name, "wasn't found in", externalDependency.SourceDir)
+		}
+	} else {
+		archiveName := buildutils.GetUrlFileName(externalDependency.Url)
+		dependency.Type = buildutils.GetCMakeArchiveType(archiveName)
+		dependency.RemoteRepository = externalDependency.Url
+		version = buildutils.GetCMakeArchiveVersion(externalDependency.Name, archiveName)
+		algorithmName, expectedHash, _ := strings.Cut(externalDependency.Hash, "=")
+		algorithmName, expectedHash = strings.ToLower(algorithmName), strings.ToLower(expectedHash)
+		algorithm, supported := cmakeHashAlgorithms[algorithmName]
+		if externalDependency.ArchivePath != "" {
+			checksums, err := cm.containingBuild.checksumCache.GetFileChecksums(externalDependency.ArchivePath)
+			if err != nil {
+				return dependency, nil, err
+			}
+			dependency.Checksum = entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}
+			dependency.SetResolutionSource(entities.CacheSource)
+			if supported && expectedHash != "" && checksums[algorithm] != expectedHash {
+				mismatch = &utils.IntegrityMismatchDetails{Lockfile: "URL_HASH", Expected: algorithmName + ":" + expectedHash, Actual: algorithmName + ":" + checksums[algorithm]}
+			}
+		} else {
+			// The archive was removed after its extraction, so its checksum is the hash declared in the project's CMake files, if any.
+			dependency.SetResolutionSource(entities.LockfileSource)
+			if supported {
+				switch algorithm {
+				case crypto.SHA256:
+					dependency.Sha256 = expectedHash
+				case crypto.SHA1:
+					dependency.Sha1 = expectedHash
+				case crypto.MD5:
+					dependency.Md5 = expectedHash
+				}
+			}
+		}
+	}
+	dependency.Id = getCMakeDependencyId(externalDependency.Name, version)
+	if mismatch != nil {
+		mismatch.DependencyId = dependency.Id
+	}
+	return dependency, mismatch, nil
+}
+
+// Returns the ID of a CMake project or dependency: <name>:<version>, or <name> if its version is unknown.
+func getCMakeDependencyId(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + ":" + version
+}
+
+func (cm *CMakeModule) AddArtifacts(artifacts ...entities.Artifact) error {
+	return cm.containingBuild.AddArtifacts(cm.name, entities.CMake, artifacts...)
+}
@@ -0,0 +1,92 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testFmtArchiveSha256    = "8cfb7d0586862cce28314669d419478556d84fa19cb1c8f3628a892482a25551"
+	testGoogletestGitCommit = "f8d7d77c06936315286eb55f8de22cd23c188571"
+)
+
+func TestGenerateBuildInfoForCMakeProject(t *testing.T) {
+	projectDir := t.TempDir()
+	writeCMakeTestFile(t, filepath.Join(projectDir, "CMakeLists.txt"), "cmake_minimum_required(VERSION 3.24)\nproject(my_app VERSION 1.0.0 LANGUAGES CXX)\n")
+	buildDir := filepath.Join(projectDir, "out")
+	depsDir := filepath.Join(buildDir, "_deps")
+	writeCMakeTestFile(t, filepath.Join(depsDir, "googletest-subbuild", "googletest-populate-prefix", "src", "googletest-populate-stamp", "googletest-populate-gitinfo.txt"),
+		"repository=https://github.com/google/googletest.git\ntag=v1.14.0\nsource_dir="+filepath.Join(depsDir, "googletest-src")+"\n")
+	writeCMakeTestFile(t, filepath.Join(depsDir, "googletest-src", ".git", "HEAD"), testGoogletestGitCommit+"\n")
+	fmtSrcDir := filepath.Join(depsDir, "fmt-subbuild", "fmt-populate-prefix", "src")
+	writeCMakeTestFile(t, filepath.Join(fmtSrcDir, "fmt-populate-stamp", "fmt-populate-urlinfo.txt"),
+		"url(s)=https://github.com/fmtlib/fmt/releases/download/10.2.1/fmt-10.2.1.zip\nhash=SHA256="+testFmtArchiveSha256+"\n")
+	writeCMakeTestFile(t, filepath.Join(fmtSrcDir, "fmt-10.2.1.zip"), "fmt archive")
+	// The archive of json was removed after its extraction.
+	writeCMakeTestFile(t, filepath.Join(buildDir, "json-prefix", "src", "json-stamp", "json-urlinfo.txt"),
+		"url(s)=https://github.com/nlohmann/json/archive/refs/tags/v3.11.3.tar.gz\nhash=MD5=D41D8CD98F00B204E9800998ECF8427E\n")
+
+	cmakeBuild := createTestBuild(t, "build-info-go-test-cmake")
+	cmakeBuild.SetResolutionAudit(true)
+	cmakeModule, err := cmakeBuild.AddCMakeModule(projectDir)
+	require.NoError(t, err)
+	assert.ErrorContains(t, cmakeModule.CalcDependencies(), "doesn't exist")
+	cmakeModule.SetBuildDir("out")
+	require.NoError(t, cmakeModule.CalcDependencies())
+	buildInfo, err := cmakeBuild.ToBuildInfo()
+	require.NoError(t, err)
+
+	require.Len(t, buildInfo.Modules, 1)
+	module := buildInfo.Modules[0]
+	assert.Equal(t, "my_app:1.0.0", module.Id)
+	assert.Equal(t, entities.CMake, module.Type)
+	dependencies := make(map[string]entities.Dependency)
+	for _, dependency := range module.Dependencies {
+		dependencies[dependency.Id] = dependency
+	}
+	assert.Len(t, dependencies, 3)
+	assert.Equal(t, entities.Dependency{
+		Id:               "googletest:v1.14.0",
+		Type:             "git",
+		RequestedBy:      [][]string{{"my_app:1.0.0"}},
+		RemoteRepository: "https://github.com/google/googletest.git",
+		Properties:       map[string]string{CMakeGitCommitProperty: testGoogletestGitCommit, entities.ResolutionSourceProperty: string(entities.FilesystemSource)},
+	}, dependencies["googletest:v1.14.0"])
+	fmtDependency := dependencies["fmt:10.2.1"]
+	assert.Equal(t, "zip", fmtDependency.Type)
+	assert.Equal(t, entities.CacheSource, fmtDependency.GetResolutionSource())
+	assert.Equal(t, testFmtArchiveSha256, fmtDependency.Sha256)
+	assert.NotEmpty(t, fmtDependency.Sha1)
+	// The checksum of a removed archive is the hash declared for it.
+	jsonDependency := dependencies["json:v3.11.3"]
+	assert.Equal(t, entities.LockfileSource, jsonDependency.GetResolutionSource())
+	assert.Equal(t, entities.Checksum{Md5: "d41d8cd98f00b204e9800998ecf8427e"}, jsonDependency.Checksum)
+}
+
+func TestCMakeIntegrityMismatch(t *testing.T) {
+	projectDir := t.TempDir()
+	stampDir := filepath.Join(projectDir, "build", "_deps", "fmt-subbuild", "fmt-populate-prefix", "src", "fmt-populate-stamp")
+	writeCMakeTestFile(t, filepath.Join(stampDir, "fmt-populate-urlinfo.txt"),
+		"url(s)=https://github.com/fmtlib/fmt/releases/download/10.2.1/fmt-10.2.1.zip\nhash=SHA256=0000000000000000000000000000000000000000000000000000000000000000\n")
+	writeCMakeTestFile(t, filepath.Join(filepath.Dir(stampDir), "fmt-10.2.1.zip"), "fmt archive")
+
+	cmakeBuild := createTestBuild(t, "build-info-go-test-cmake-integrity")
+	cmakeModule, err := cmakeBuild.AddCMakeModule(projectDir)
+	require.NoError(t, err)
+	require.NoError(t, cmakeModule.CalcDependencies())
+
+	cmakeBuild.SetIntegrityVerification(utils.IntegrityVerificationFail)
+	err = cmakeModule.CalcDependencies()
+	assert.ErrorContains(t, err, "fmt:10.2.1: URL_HASH declares 'sha256:0000000000000000000000000000000000000000000000000000000000000000', but the computed hash is 'sha256:"+testFmtArchiveSha256+"'")
+}
+
+func writeCMakeTestFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
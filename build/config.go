@@ -0,0 +1,114 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jfrog/build-info-go/entities"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// The name of the project's configuration file, which is read from the project's root directory.
+const ConfigFileName = "bi.yaml"
+
+// Config is the content of the project's configuration file.
+type Config struct {
+	// The deploy path configurations of the artifacts, by package type, for example:
+	// deployPaths: {maven: {repo: libs-release-local, template: "{repo}/{group}/{name}/{version}/{file}"}}
+	DeployPaths map[entities.ModuleType]DeployPathConfig `yaml:"deployPaths,omitempty"`
+	// The templates of the modules' IDs, by package type, for example:
+	// moduleIds: {maven: "{group}:{name}:{version}", npm: "{type}-{group}:{name}:{version}"}
+	ModuleIds map[entities.ModuleType]string `yaml:"moduleIds,omitempty"`
+	// Move the dependencies shared by several modules to shared dependencies modules.
+	ShareDependencies bool `yaml:"shareDependencies,omitempty"`
+	// Rules which exclude the matching dependencies from the build-info, for example:
+	// excludeDependencies: [{id: "org.example:test-fixtures:*"}, {scope: test}]
+	ExcludeDependencies []DependencyExclusion `yaml:"excludeDependencies,omitempty"`
+	// The options of the Maven build, for example:
+	// maven: {pomFile: services/api/pom.xml, profiles: [release], properties: {skipTests: "true"}}
+	Maven MavenConfig `yaml:"maven,omitempty"`
+	// The options of the Gradle build, for example:
+	// gradle: {noDaemon: true, jvmArgs: "-Xmx2g", args: ["--max-workers=2"]}
+	Gradle GradleConfig `yaml:"gradle,omitempty"`
+	// Caps on the requestedBy paths of each dependency, for example:
+	// requestedBy: {maxPaths: 10, maxDepth: 5}
+	RequestedBy RequestedByLimits `yaml:"requestedBy,omitempty"`
+	// Wildcard patterns of the keys of the build and module properties whose values are encrypted, for example:
+	// encryptProperties: ["vault.*", "customer.name"]
+	EncryptProperties []string `yaml:"encryptProperties,omitempty"`
+	// Record the CPU and memory limits of the runner's cgroup, and its container runtime, in the build properties.
+	CollectRunnerLimits bool `yaml:"collectRunnerLimits,omitempty"`
+}
+
+// MavenConfig is the configuration of the Maven build which collects the build-info.
+type MavenConfig struct {
+	// The path of the POM, or of its directory, passed to Maven with -f. Relative to the project's directory. Defaults to pom.xml.
+	PomFile string `yaml:"pomFile,omitempty"`
+	// Profiles to activate, or deactivate with a '!' prefix, passed to Maven with -P.
+	Profiles []string `yaml:"profiles,omitempty"`
+	// System properties passed to Maven with -D.
+	Properties map[string]string `yaml:"properties,omitempty"`
+	// The home directory of the Maven installation which builds the project, overriding M2_HOME, the wrapper and the mvn executable in the PATH.
+	// Relative to the project's directory.
+	Home string `yaml:"home,omitempty"`
+	// The version which the Maven installation must be of, for reproducible builds.
+	Version string `yaml:"version,omitempty"`
+}
+
+// GradleConfig is the configuration of the Gradle build which collects the build-info.
+// The options are passed to each of the Gradle commands which run during the collection.
+type GradleConfig struct {
+	// Run Gradle without its daemon, passing --no-daemon, so that no daemon outlives the collection on a shared agent.
+	NoDaemon bool `yaml:"noDaemon,omitempty"`
+	// The home directory of the JDK which runs Gradle, overriding JAVA_HOME. Relative to the project's directory.
+	JavaHome string `yaml:"javaHome,omitempty"`
+	// The JVM arguments of the Gradle daemon, overriding the org.gradle.jvmargs property of gradle.properties, for example: -Xmx2g -XX:MaxMetaspaceSize=512m
+	JvmArgs string `yaml:"jvmArgs,omitempty"`
+	// Additional arguments passed to Gradle, for example: --max-workers=2
+	Args []string `yaml:"args,omitempty"`
+}
+
+// ReadConfig reads the configuration file from the project's directory.
+// If the file doesn't exist, an empty configuration is returned.
+func ReadConfig(projectDir string) (*Config, error) {
+	configPath := filepath.Join(projectDir, ConfigFileName)
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Config{}, nil
+		}
+		return nil, err
+	}
+	config := &Config{}
+	if err = yaml.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the configuration file '%s': %w", configPath, err)
+	}
+	for moduleType, deployPath := range config.DeployPaths {
+		if deployPath.Template == "" && DefaultDeployPathTemplates[moduleType] == "" {
+			return nil, fmt.Errorf("no deploy path template is configured for the '%s' package type in '%s'", moduleType, configPath)
+		}
+		if err = ValidateDeployPathTemplate(deployPath.Template); err != nil {
+			return nil, err
+		}
+	}
+	if err = config.RequestedBy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid requestedBy limits in '%s': %w", configPath, err)
+	}
+	for _, template := range config.ModuleIds {
+		if err = ValidateModuleIdTemplate(template); err != nil {
+			return nil, fmt.Errorf("invalid module ID template in '%s': %w", configPath, err)
+		}
+	}
+	for _, exclusion := range config.ExcludeDependencies {
+		if err = exclusion.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid dependency exclusion rule in '%s': %w", configPath, err)
+		}
+	}
+	if slices.Contains(config.EncryptProperties, "") {
+		return nil, fmt.Errorf("the patterns of the properties to encrypt in '%s' can't be empty", configPath)
+	}
+	return config, nil
+}
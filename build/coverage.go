@@ -0,0 +1,129 @@
+package build
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+)
+
+// The properties which hold the summary of the code coverage, added to the build-info or to its modules.
+const (
+	CoverageCoveredProperty    = "buildInfo.coverage.covered"
+	CoverageTotalProperty      = "buildInfo.coverage.total"
+	CoveragePercentageProperty = "buildInfo.coverage.percentage"
+	// The ID of the module to which the coverage reports of the build-info itself are attached as artifacts.
+	CoverageReportsModuleId = "coverage-reports"
+)
+
+// Code coverage reports whose results are summarized in the build-info.
+type coverageReports struct {
+	// The ID of the module to whose properties the results are added. If empty, they're added to the build-info's properties.
+	moduleId string
+	// Paths of the reports, which may contain the wildcards supported by filepath.Glob.
+	patterns []string
+	// If true, the reports are also added as artifacts, with their checksums.
+	attachArtifacts bool
+}
+
+// AddCoverageReports adds code coverage reports, whose covered and total lines (or statements, for Go cover profiles) are summed
+// and added as properties to the module with the given ID, or to the build-info itself if moduleId is empty.
+// The supported formats are lcov, Cobertura XML and Go cover profiles.
+// If attachArtifacts is true, the reports are also added as artifacts to the module,
+// or to a generic module with the CoverageReportsModuleId ID if moduleId is empty.
+// The reports are read when creating the build-info using the ToBuildInfo() function, so they can be written by the build itself.
+func (b *Build) AddCoverageReports(moduleId string, attachArtifacts bool, reportPatterns ...string) {
+	if len(reportPatterns) == 0 {
+		return
+	}
+	b.coverageReports = append(b.coverageReports, coverageReports{moduleId: moduleId, patterns: reportPatterns, attachArtifacts: attachArtifacts})
+}
+
+func applyCoverageReports(buildInfo *entities.BuildInfo, reports []coverageReports, logger utils.Log) error {
+	for _, report := range reports {
+		results, artifacts, err := readCoverageReports(report.patterns)
+		if err != nil {
+			return err
+		}
+		if len(artifacts) == 0 {
+			logger.Warn(fmt.Sprintf("No coverage reports were found at: %v", report.patterns))
+			continue
+		}
+		properties := coverageResultsToProperties(results)
+		if report.moduleId == "" {
+			buildInfo.AddProperties(properties)
+			if report.attachArtifacts {
+				module := getOrAddModule(buildInfo, CoverageReportsModuleId, entities.Generic)
+				module.Artifacts = append(module.Artifacts, artifacts...)
+			}
+			continue
+		}
+		moduleFound := false
+		for i := range buildInfo.Modules {
+			if buildInfo.Modules[i].Id == report.moduleId {
+				buildInfo.Modules[i].AddProperties(properties)
+				if report.attachArtifacts {
+					buildInfo.Modules[i].Artifacts = append(buildInfo.Modules[i].Artifacts, artifacts...)
+				}
+				moduleFound = true
+			}
+		}
+		if !moduleFound {
+			return fmt.Errorf("the coverage reports %v were added to the '%s' module, which isn't part of the build-info", report.patterns, report.moduleId)
+		}
+	}
+	return nil
+}
+
+// Reads the reports matching the patterns, and returns the sum of their results and an artifact for each of them.
+func readCoverageReports(patterns []string) (results buildutils.CoverageResults, artifacts []entities.Artifact, err error) {
+	for _, pattern := range patterns {
+		var reportPaths []string
+		if reportPaths, err = filepath.Glob(pattern); err != nil {
+			return
+		}
+		for _, reportPath := range reportPaths {
+			var reportResults buildutils.CoverageResults
+			var format buildutils.CoverageFormat
+			if reportResults, format, err = buildutils.ReadCoverageReport(reportPath); err != nil {
+				return
+			}
+			results.Add(reportResults)
+			var fileDetails *crypto.FileDetails
+			if fileDetails, err = crypto.GetFileDetails(reportPath, true); err != nil {
+				return
+			}
+			artifacts = append(artifacts, entities.Artifact{
+				Name:     filepath.Base(reportPath),
+				Type:     string(format),
+				Path:     filepath.ToSlash(reportPath),
+				Size:     fileDetails.Size,
+				Checksum: entities.Checksum{Sha1: fileDetails.Checksum.Sha1, Md5: fileDetails.Checksum.Md5, Sha256: fileDetails.Checksum.Sha256},
+			})
+		}
+	}
+	return
+}
+
+// Returns the module with the given ID, and adds it if it doesn't exist.
+func getOrAddModule(buildInfo *entities.BuildInfo, moduleId string, moduleType entities.ModuleType) *entities.Module {
+	for i := range buildInfo.Modules {
+		if buildInfo.Modules[i].Id == moduleId {
+			return &buildInfo.Modules[i]
+		}
+	}
+	buildInfo.Modules = append(buildInfo.Modules, entities.Module{Id: moduleId, Type: moduleType})
+	return &buildInfo.Modules[len(buildInfo.Modules)-1]
+}
+
+func coverageResultsToProperties(results buildutils.CoverageResults) map[string]string {
+	return map[string]string{
+		CoverageCoveredProperty:    strconv.Itoa(results.Covered),
+		CoverageTotalProperty:      strconv.Itoa(results.Total),
+		CoveragePercentageProperty: strconv.FormatFloat(results.Percentage(), 'f', 2, 64),
+	}
+}
@@ -0,0 +1,56 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gofrog/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyCoverageReports(t *testing.T) {
+	reportsDir := t.TempDir()
+	lcovPath := filepath.Join(reportsDir, "lcov.info")
+	require.NoError(t, os.WriteFile(lcovPath, []byte("SF:src/app.js\nLF:10\nLH:5\nend_of_record\n"), 0644))
+	coverProfilePath := filepath.Join(reportsDir, "coverage.out")
+	require.NoError(t, os.WriteFile(coverProfilePath, []byte("mode: set\nexample.com/app/main.go:5.13,7.2 2 1\nexample.com/app/main.go:9.20,11.2 1 0\n"), 0644))
+
+	buildInfo := &entities.BuildInfo{Modules: []entities.Module{{Id: "example.com/app"}}}
+	assert.NoError(t, applyCoverageReports(buildInfo, []coverageReports{
+		{moduleId: "example.com/app", patterns: []string{filepath.Join(reportsDir, "*.out")}},
+		{patterns: []string{lcovPath}, attachArtifacts: true},
+		// A pattern which doesn't match any report is skipped with a warning.
+		{patterns: []string{filepath.Join(reportsDir, "missing.xml")}, attachArtifacts: true},
+	}, logger))
+	assert.Equal(t, entities.Env{
+		CoverageCoveredProperty:    "5",
+		CoverageTotalProperty:      "10",
+		CoveragePercentageProperty: "50.00",
+	}, buildInfo.Properties)
+	assert.Equal(t, map[string]any{
+		CoverageCoveredProperty:    "2",
+		CoverageTotalProperty:      "3",
+		CoveragePercentageProperty: "66.67",
+	}, buildInfo.Modules[0].Properties)
+	assert.Empty(t, buildInfo.Modules[0].Artifacts)
+
+	require.Len(t, buildInfo.Modules, 2)
+	assert.Equal(t, CoverageReportsModuleId, buildInfo.Modules[1].Id)
+	assert.Equal(t, entities.Generic, buildInfo.Modules[1].Type)
+	checksums, err := crypto.GetFileChecksums(lcovPath)
+	require.NoError(t, err)
+	lcovInfo, err := os.Stat(lcovPath)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Artifact{{
+		Name:     "lcov.info",
+		Type:     "lcov",
+		Path:     filepath.ToSlash(lcovPath),
+		Checksum: entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]},
+		Size:     lcovInfo.Size(),
+	}}, buildInfo.Modules[1].Artifacts)
+
+	assert.Error(t, applyCoverageReports(buildInfo, []coverageReports{{moduleId: "missing", patterns: []string{lcovPath}}}, logger))
+}
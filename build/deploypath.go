@@ -0,0 +1,133 @@
+package build
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+)
+
+// The placeholders which can be used in a deploy path template.
+const (
+	RepoPlaceholder    = "{repo}"
+	GroupPlaceholder   = "{group}"
+	NamePlaceholder    = "{name}"
+	VersionPlaceholder = "{version}"
+	FilePlaceholder    = "{file}"
+)
+
+var deployPathPlaceholderRegex = regexp.MustCompile(`\{[^{}]*}`)
+
+// The layouts Artifactory uses for each package type. Empty path segments, such as a missing npm scope, are removed.
+var DefaultDeployPathTemplates = map[entities.ModuleType]string{
+	entities.Maven:   "{repo}/{group}/{name}/{version}/{file}",
+	entities.Gradle:  "{repo}/{group}/{name}/{version}/{file}",
+	entities.Npm:     "{repo}/{group}/{name}/-/{group}/{file}",
+	entities.Go:      "{repo}/{name}/@v/{file}",
+	entities.Python:  "{repo}/{name}/{version}/{file}",
+	entities.Nuget:   "{repo}/{name}/{version}/{file}",
+	entities.Gem:     "{repo}/gems/{file}",
+	entities.Hackage: "{repo}/package/{name}-{version}/{file}",
+	entities.Generic: "{repo}/{file}",
+}
+
+// DeployPathConfig determines the deploy paths of the artifacts of one package type.
+type DeployPathConfig struct {
+	// The repository to which the artifacts are deployed. If empty, the artifact's original deployment repository is used.
+	Repo string `json:"repo,omitempty" yaml:"repo,omitempty"`
+	// The template of the artifact's path, for example: {repo}/{group}/{name}/{version}/{file}.
+	// If empty, the package type's default template is used.
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+}
+
+// DeployPathFields are the values which replace the placeholders of a deploy path template.
+type DeployPathFields struct {
+	Repo string
+	// The group in its path form, for example: org/jfrog for Maven, or @jfrog for npm.
+	Group   string
+	Name    string
+	Version string
+	File    string
+}
+
+// ValidateDeployPathTemplate returns an error if the template contains unknown placeholders.
+func ValidateDeployPathTemplate(template string) error {
+	for _, placeholder := range deployPathPlaceholderRegex.FindAllString(template, -1) {
+		switch placeholder {
+		case RepoPlaceholder, GroupPlaceholder, NamePlaceholder, VersionPlaceholder, FilePlaceholder:
+		default:
+			return fmt.Errorf("the deploy path template '%s' contains the unknown placeholder '%s'", template, placeholder)
+		}
+	}
+	return nil
+}
+
+// ResolveDeployPath replaces the placeholders of the template with the fields, and removes the empty path segments.
+func ResolveDeployPath(template string, fields DeployPathFields) string {
+	replacer := strings.NewReplacer(
+		RepoPlaceholder, fields.Repo,
+		GroupPlaceholder, fields.Group,
+		NamePlaceholder, fields.Name,
+		VersionPlaceholder, fields.Version,
+		FilePlaceholder, fields.File,
+	)
+	var segments []string
+	for _, segment := range strings.Split(replacer.Replace(template), "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Sets the paths of the artifacts of the modules, whose types have a deploy path configuration.
+// The module ID provides the group, name and version, and the artifact's name provides the file.
+// If the template starts with the repository, it is recorded as the artifact's original deployment repository,
+// and the rest of the resolved path is the artifact's path, since build-info artifact paths are relative to their repository.
+// In that case, the whole resolved path is also recorded as the artifact's remote path.
+func applyDeployPaths(buildInfo *entities.BuildInfo, deployPaths map[entities.ModuleType]DeployPathConfig) {
+	for i := range buildInfo.Modules {
+		module := &buildInfo.Modules[i]
+		deployPath, ok := deployPaths[module.Type]
+		if !ok {
+			continue
+		}
+		template := deployPath.Template
+		if template == "" {
+			template = DefaultDeployPathTemplates[module.Type]
+		}
+		for j := range module.Artifacts {
+			artifact := &module.Artifacts[j]
+			fields := getModuleDeployPathFields(module)
+			fields.File = artifact.Name
+			fields.Repo = deployPath.Repo
+			if fields.Repo == "" {
+				fields.Repo = artifact.OriginalDeploymentRepo
+			}
+			path := ResolveDeployPath(template, fields)
+			if fields.Repo != "" && strings.HasPrefix(template, RepoPlaceholder+"/") {
+				artifact.OriginalDeploymentRepo = fields.Repo
+				artifact.RemotePath = path
+				path = strings.TrimPrefix(path, fields.Repo+"/")
+			}
+			artifact.Path = path
+		}
+	}
+}
+
+// Returns the group, name and version from a module ID, in the <group>:<name>:<version> or <name>:<version> format.
+func getModuleDeployPathFields(module *entities.Module) (fields DeployPathFields) {
+	idFields := getModuleIdFields(module)
+	fields.Group, fields.Name, fields.Version = idFields.Group, idFields.Name, idFields.Version
+	switch module.Type {
+	case entities.Maven, entities.Gradle:
+		fields.Group = strings.ReplaceAll(fields.Group, ".", "/")
+	case entities.Npm:
+		// The npm module ID contains the scope without its '@' prefix.
+		if fields.Group != "" {
+			fields.Group = "@" + fields.Group
+		}
+	}
+	return
+}
@@ -0,0 +1,123 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDeployPath(t *testing.T) {
+	fields := DeployPathFields{Repo: "libs-release-local", Group: "org/jfrog", Name: "build-info", Version: "1.0.0", File: "build-info-1.0.0.jar"}
+	assert.Equal(t, "libs-release-local/org/jfrog/build-info/1.0.0/build-info-1.0.0.jar", ResolveDeployPath(DefaultDeployPathTemplates[entities.Maven], fields))
+
+	// Empty segments, such as a missing npm scope, are removed.
+	fields = DeployPathFields{Repo: "npm-local", Name: "build-info", Version: "1.0.0", File: "build-info-1.0.0.tgz"}
+	assert.Equal(t, "npm-local/build-info/-/build-info-1.0.0.tgz", ResolveDeployPath(DefaultDeployPathTemplates[entities.Npm], fields))
+
+	assert.NoError(t, ValidateDeployPathTemplate("{repo}/{group}/{name}/{version}/{file}"))
+	assert.Error(t, ValidateDeployPathTemplate("{repo}/{classifier}/{file}"))
+}
+
+func TestApplyDeployPaths(t *testing.T) {
+	bld := createTestBuild(t, "deploy-path-test")
+	require.NoError(t, bld.AddArtifacts("org.jfrog:build-info:1.0.0", entities.Maven, entities.Artifact{Name: "build-info-1.0.0.jar"}))
+	require.NoError(t, bld.AddArtifacts("jfrog:build-info:1.0.0", entities.Npm, entities.Artifact{Name: "build-info-1.0.0.tgz", OriginalDeploymentRepo: "npm-local"}))
+	require.NoError(t, bld.AddArtifacts("build-info:1.0.0", entities.Generic, entities.Artifact{Name: "build-info.zip", Path: "original/build-info.zip"}))
+
+	bld.SetDeployPaths(map[entities.ModuleType]DeployPathConfig{
+		entities.Maven: {Repo: "libs-release-local"},
+		entities.Npm:   {},
+	})
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	artifacts := map[string]entities.Artifact{}
+	for _, module := range buildInfo.Modules {
+		require.Len(t, module.Artifacts, 1)
+		artifacts[module.Id] = module.Artifacts[0]
+	}
+	assert.Equal(t, "libs-release-local", artifacts["org.jfrog:build-info:1.0.0"].OriginalDeploymentRepo)
+	assert.Equal(t, "org/jfrog/build-info/1.0.0/build-info-1.0.0.jar", artifacts["org.jfrog:build-info:1.0.0"].Path)
+	assert.Equal(t, "libs-release-local/org/jfrog/build-info/1.0.0/build-info-1.0.0.jar", artifacts["org.jfrog:build-info:1.0.0"].RemotePath)
+	assert.Equal(t, "npm-local", artifacts["jfrog:build-info:1.0.0"].OriginalDeploymentRepo)
+	assert.Equal(t, "@jfrog/build-info/-/@jfrog/build-info-1.0.0.tgz", artifacts["jfrog:build-info:1.0.0"].Path)
+	// Modules without a deploy path configuration are left unchanged.
+	assert.Equal(t, "original/build-info.zip", artifacts["build-info:1.0.0"].Path)
+	assert.Empty(t, artifacts["build-info:1.0.0"].RemotePath)
+}
+
+func TestReadConfig(t *testing.T) {
+	projectDir := t.TempDir()
+	config, err := ReadConfig(projectDir)
+	require.NoError(t, err)
+	assert.Empty(t, config.DeployPaths)
+
+	configPath := filepath.Join(projectDir, ConfigFileName)
+	require.NoError(t, os.WriteFile(configPath, []byte("deployPaths:\n  maven:\n    repo: libs-release-local\n    template: \"{repo}/{group}/{name}/{file}\"\nshareDependencies: true\n"), 0644))
+	config, err = ReadConfig(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, map[entities.ModuleType]DeployPathConfig{entities.Maven: {Repo: "libs-release-local", Template: "{repo}/{group}/{name}/{file}"}}, config.DeployPaths)
+	assert.True(t, config.ShareDependencies)
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"deployPaths": {"maven": {"template": "{repo}/{classifier}/{file}"}}}`), 0644))
+	_, err = ReadConfig(projectDir)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"deployPaths": {"docker": {"repo": "docker-local"}}}`), 0644))
+	_, err = ReadConfig(projectDir)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("excludeDependencies:\n  - id: \"org.example:test-fixtures:*\"\n  - scope: test\n"), 0644))
+	config, err = ReadConfig(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, []DependencyExclusion{{Id: "org.example:test-fixtures:*"}, {Scope: "test"}}, config.ExcludeDependencies)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("excludeDependencies:\n  - regex: true\n"), 0644))
+	_, err = ReadConfig(projectDir)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("moduleIds:\n  npm: \"{type}-{group}:{name}:{version}\"\n"), 0644))
+	config, err = ReadConfig(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, map[entities.ModuleType]string{entities.Npm: "{type}-{group}:{name}:{version}"}, config.ModuleIds)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("moduleIds:\n  npm: \"{group}:{version}\"\n"), 0644))
+	_, err = ReadConfig(projectDir)
+	assert.ErrorContains(t, err, "must contain the '{name}' placeholder")
+
+	require.NoError(t, os.WriteFile(configPath, []byte("requestedBy:\n  maxPaths: 10\n  maxDepth: 5\n"), 0644))
+	config, err = ReadConfig(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, RequestedByLimits{MaxPaths: 10, MaxDepth: 5}, config.RequestedBy)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("requestedBy:\n  maxPaths: -1\n"), 0644))
+	_, err = ReadConfig(projectDir)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("encryptProperties: [\"vault.*\", customer.name]\n"), 0644))
+	config, err = ReadConfig(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vault.*", "customer.name"}, config.EncryptProperties)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("collectRunnerLimits: true\n"), 0644))
+	config, err = ReadConfig(projectDir)
+	require.NoError(t, err)
+	assert.True(t, config.CollectRunnerLimits)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("encryptProperties: [\"\"]\n"), 0644))
+	_, err = ReadConfig(projectDir)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("maven:\n  pomFile: services/api/pom.xml\n  profiles: [release]\n  properties:\n    skipTests: \"true\"\n  home: /opt/maven-3.9.6\n  version: 3.9.6\n"), 0644))
+	config, err = ReadConfig(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, MavenConfig{PomFile: "services/api/pom.xml", Profiles: []string{"release"}, Properties: map[string]string{"skipTests": "true"}, Home: "/opt/maven-3.9.6", Version: "3.9.6"}, config.Maven)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("gradle:\n  noDaemon: true\n  javaHome: /opt/jdk-17\n  jvmArgs: -Xmx2g\n  args: [\"--max-workers=2\"]\n"), 0644))
+	config, err = ReadConfig(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, GradleConfig{NoDaemon: true, JavaHome: "/opt/jdk-17", JvmArgs: "-Xmx2g", Args: []string{"--max-workers=2"}}, config.Gradle)
+}
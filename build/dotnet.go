@@ -4,7 +4,6 @@ import (
 	"github.com/jfrog/build-info-go/build/utils/dotnet"
 	"github.com/jfrog/build-info-go/build/utils/dotnet/solution"
 	"github.com/jfrog/build-info-go/utils"
-	"github.com/jfrog/gofrog/io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -108,12 +107,16 @@ func (dm *DotnetModule) runCmd() error {
 		return err
 	}
 
-	err = io.RunCmd(cmd)
-	if err != nil {
-		return err
+	for key, value := range cmd.GetEnv() {
+		if err = os.Setenv(key, value); err != nil {
+			return err
+		}
 	}
-
-	return nil
+	command := cmd.GetCmd()
+	command.Stdout = dm.containingBuild.getCommandOutput()
+	command.Stderr = os.Stderr
+	_, err = runTimedCommand(command)
+	return err
 }
 
 func (dm *DotnetModule) createCmd() (*dotnet.Cmd, error) {
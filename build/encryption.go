@@ -0,0 +1,147 @@
+package build
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+)
+
+// The prefix of the encrypted values of properties, which is followed by the base64 encoded nonce and ciphertext.
+const EncryptedPropertyPrefix = "encrypted:aes-256-gcm:"
+
+// The size of the AES-256 keys, in bytes.
+const PropertyEncryptionKeySize = 32
+
+// PropertyEncrypter encrypts the values of the build-info's properties whose keys match its patterns, with AES-256-GCM,
+// so that restricted metadata can be included in the build-info without exposing it to its readers.
+// The key of each property is authenticated with its value, so an encrypted value can't be moved to a different property.
+type PropertyEncrypter struct {
+	aead     cipher.AEAD
+	patterns []*regexp.Regexp
+}
+
+// ParsePropertyEncryptionKey decodes a base64 encoded AES-256 key, for example, a key generated with: openssl rand -base64 32
+func ParsePropertyEncryptionKey(encodedKey string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, fmt.Errorf("the property encryption key isn't base64 encoded: %w", err)
+	}
+	if len(key) != PropertyEncryptionKeySize {
+		return nil, fmt.Errorf("the property encryption key must be %d bytes long, but it's %d bytes long", PropertyEncryptionKeySize, len(key))
+	}
+	return key, nil
+}
+
+// NewPropertyEncrypter creates an encrypter with the AES-256 key, which encrypts the properties whose keys match the patterns.
+// The patterns are wildcard patterns, in which '*' matches any sequence of characters, for example: vault.*
+// An encrypter without patterns can only decrypt.
+func NewPropertyEncrypter(key []byte, patterns ...string) (*PropertyEncrypter, error) {
+	if len(key) != PropertyEncryptionKeySize {
+		return nil, fmt.Errorf("the property encryption key must be %d bytes long, but it's %d bytes long", PropertyEncryptionKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	encrypter := &PropertyEncrypter{aead: aead}
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return nil, errors.New("the pattern of the properties to encrypt can't be empty")
+		}
+		encrypter.patterns = append(encrypter.patterns, regexp.MustCompile("^(?:"+wildcardToRegexp(pattern)+")$"))
+	}
+	return encrypter, nil
+}
+
+// EncryptProperties encrypts the values of the build-info's and the modules' properties whose keys match the patterns.
+// Values which are already encrypted are kept.
+func (pe *PropertyEncrypter) EncryptProperties(buildInfo *entities.BuildInfo) error {
+	return transformProperties(buildInfo, func(key, value string) (string, error) {
+		if strings.HasPrefix(value, EncryptedPropertyPrefix) || !pe.matches(key) {
+			return value, nil
+		}
+		return pe.encrypt(key, value)
+	})
+}
+
+// DecryptProperties decrypts the encrypted values of the build-info's and the modules' properties, regardless of the patterns.
+// Returns the number of decrypted values. An error is returned if a value was encrypted with a different key.
+func (pe *PropertyEncrypter) DecryptProperties(buildInfo *entities.BuildInfo) (decrypted int, err error) {
+	err = transformProperties(buildInfo, func(key, value string) (string, error) {
+		if !strings.HasPrefix(value, EncryptedPropertyPrefix) {
+			return value, nil
+		}
+		decrypted++
+		return pe.decrypt(key, value)
+	})
+	return
+}
+
+func (pe *PropertyEncrypter) matches(key string) bool {
+	for _, pattern := range pe.patterns {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (pe *PropertyEncrypter) encrypt(key, value string) (string, error) {
+	nonce := make([]byte, pe.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := pe.aead.Seal(nonce, nonce, []byte(value), []byte(key))
+	return EncryptedPropertyPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (pe *PropertyEncrypter) decrypt(key, value string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedPropertyPrefix))
+	if err != nil || len(sealed) < pe.aead.NonceSize() {
+		return "", fmt.Errorf("the encrypted value of the '%s' property is malformed", key)
+	}
+	plaintext, err := pe.aead.Open(nil, sealed[:pe.aead.NonceSize()], sealed[pe.aead.NonceSize():], []byte(key))
+	if err != nil {
+		return "", fmt.Errorf("failed decrypting the '%s' property. Make sure it was encrypted with the same key", key)
+	}
+	return string(plaintext), nil
+}
+
+// Replaces the string values of the build-info's and the modules' properties with the results of the transform function.
+func transformProperties(buildInfo *entities.BuildInfo, transform func(key, value string) (string, error)) (err error) {
+	for key, value := range buildInfo.Properties {
+		if buildInfo.Properties[key], err = transform(key, value); err != nil {
+			return
+		}
+	}
+	for i := range buildInfo.Modules {
+		switch properties := buildInfo.Modules[i].Properties.(type) {
+		case map[string]interface{}:
+			for key, value := range properties {
+				if stringValue, ok := value.(string); ok {
+					if properties[key], err = transform(key, stringValue); err != nil {
+						return
+					}
+				}
+			}
+		case map[string]string:
+			for key, value := range properties {
+				if properties[key], err = transform(key, value); err != nil {
+					return
+				}
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,98 @@
+package build
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPropertyEncryptionKey = bytes.Repeat([]byte{7}, PropertyEncryptionKeySize)
+
+func TestParsePropertyEncryptionKey(t *testing.T) {
+	key, err := ParsePropertyEncryptionKey(base64.StdEncoding.EncodeToString(testPropertyEncryptionKey) + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, testPropertyEncryptionKey, key)
+
+	_, err = ParsePropertyEncryptionKey("not base64!")
+	assert.ErrorContains(t, err, "isn't base64 encoded")
+	_, err = ParsePropertyEncryptionKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.ErrorContains(t, err, "must be 32 bytes long")
+}
+
+func TestEncryptProperties(t *testing.T) {
+	encrypter, err := NewPropertyEncrypter(testPropertyEncryptionKey, "vault.*", "customer.name")
+	require.NoError(t, err)
+	buildInfo := &entities.BuildInfo{
+		Properties: entities.Env{"vault.token": "s3cr3t", "team": "platform"},
+		Modules: []entities.Module{
+			{Id: "app", Properties: map[string]interface{}{"customer.name": "acme", "count": 3.0}},
+			{Id: "lib", Properties: map[string]string{"vault.path": "secret/lib", "customer.name.public": "public"}},
+		},
+	}
+	require.NoError(t, encrypter.EncryptProperties(buildInfo))
+	encrypted := buildInfo.Properties["vault.token"]
+	assert.True(t, strings.HasPrefix(encrypted, EncryptedPropertyPrefix))
+	assert.NotContains(t, encrypted, "s3cr3t")
+	assert.Equal(t, "platform", buildInfo.Properties["team"])
+	appProperties := buildInfo.Modules[0].Properties.(map[string]interface{})
+	assert.True(t, strings.HasPrefix(appProperties["customer.name"].(string), EncryptedPropertyPrefix))
+	assert.Equal(t, 3.0, appProperties["count"])
+	libProperties := buildInfo.Modules[1].Properties.(map[string]string)
+	assert.True(t, strings.HasPrefix(libProperties["vault.path"], EncryptedPropertyPrefix))
+	// The patterns must match the whole key.
+	assert.Equal(t, "public", libProperties["customer.name.public"])
+
+	// Encrypted values aren't encrypted again.
+	require.NoError(t, encrypter.EncryptProperties(buildInfo))
+	assert.Equal(t, encrypted, buildInfo.Properties["vault.token"])
+
+	// A value moved to a different property can't be decrypted.
+	movedBuildInfo := &entities.BuildInfo{Properties: entities.Env{"vault.other": encrypted}}
+	_, err = encrypter.DecryptProperties(movedBuildInfo)
+	assert.ErrorContains(t, err, "failed decrypting the 'vault.other' property")
+
+	// A different key can't decrypt the values.
+	otherEncrypter, err := NewPropertyEncrypter(bytes.Repeat([]byte{8}, PropertyEncryptionKeySize))
+	require.NoError(t, err)
+	_, err = otherEncrypter.DecryptProperties(&entities.BuildInfo{Properties: entities.Env{"vault.token": encrypted}})
+	assert.Error(t, err)
+
+	// The decryption doesn't depend on the patterns.
+	decrypter, err := NewPropertyEncrypter(testPropertyEncryptionKey)
+	require.NoError(t, err)
+	decrypted, err := decrypter.DecryptProperties(buildInfo)
+	require.NoError(t, err)
+	assert.Equal(t, 3, decrypted)
+	assert.Equal(t, "s3cr3t", buildInfo.Properties["vault.token"])
+	assert.Equal(t, "acme", appProperties["customer.name"])
+	assert.Equal(t, "secret/lib", libProperties["vault.path"])
+}
+
+func TestNewPropertyEncrypterErrors(t *testing.T) {
+	_, err := NewPropertyEncrypter([]byte("short"))
+	assert.Error(t, err)
+	_, err = NewPropertyEncrypter(testPropertyEncryptionKey, "")
+	assert.Error(t, err)
+}
+
+func TestToBuildInfoEncryptsProperties(t *testing.T) {
+	bld := createTestBuild(t, "encryption-test")
+	require.NoError(t, bld.AddArtifacts("app:1.0.0", entities.Generic, entities.Artifact{Name: "app.zip"}))
+	bld.SetBuildProperties(map[string]string{"vault.token": "s3cr3t"})
+	encrypter, err := NewPropertyEncrypter(testPropertyEncryptionKey, "vault.*")
+	require.NoError(t, err)
+	bld.SetPropertyEncrypter(encrypter)
+	// The post-processors receive the plain values.
+	bld.AddPostProcessors(func(buildInfo *entities.BuildInfo) error {
+		assert.Equal(t, "s3cr3t", buildInfo.Properties["vault.token"])
+		return nil
+	})
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(buildInfo.Properties["vault.token"], EncryptedPropertyPrefix))
+}
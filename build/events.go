@@ -0,0 +1,90 @@
+package build
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/jfrog/build-info-go/entities"
+)
+
+type CollectionEventType string
+
+const (
+	// A dependency of a module, sent before the module's event.
+	DependencyEvent CollectionEventType = "dependency"
+	// A module whose dependencies were resolved. The module is sent without its dependencies, which are sent in their own events.
+	ModuleEvent CollectionEventType = "module"
+	// The complete build-info, created after the collection.
+	BuildInfoEvent CollectionEventType = "buildInfo"
+)
+
+// CollectionEvent is sent to the CollectionListener of the build as soon as a part of the build-info is collected,
+// so that wrappers can show incremental results, and keep the collected modules if the process doesn't complete.
+type CollectionEvent struct {
+	Event CollectionEventType `json:"event"`
+	// The ID of the module of the dependency, in dependency events.
+	ModuleId   string               `json:"moduleId,omitempty"`
+	Dependency *entities.Dependency `json:"dependency,omitempty"`
+	Module     *entities.Module     `json:"module,omitempty"`
+	BuildInfo  *entities.BuildInfo  `json:"buildInfo,omitempty"`
+}
+
+// CollectionListener receives the events of the collection. An error returned by the listener fails the collection.
+type CollectionListener func(event CollectionEvent) error
+
+// NewJsonLinesListener returns a CollectionListener which writes each event to the writer as a single line of JSON.
+// The listener can be called concurrently.
+func NewJsonLinesListener(writer io.Writer) CollectionListener {
+	var writeLock sync.Mutex
+	return func(event CollectionEvent) error {
+		content, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		writeLock.Lock()
+		defer writeLock.Unlock()
+		_, err = writer.Write(append(content, '\n'))
+		return err
+	}
+}
+
+// Sends the events of the modules of a build-info file generated by the Maven or Gradle extractor, once the extractor completed,
+// and adds the modules to the build's CollectionStats.
+func (b *Build) notifyGeneratedModulesCollected(buildInfoPath string) error {
+	if b.collectionListener == nil && b.statsCounter == nil {
+		return nil
+	}
+	content, err := os.ReadFile(buildInfoPath)
+	if err != nil || len(content) == 0 {
+		return err
+	}
+	buildInfo := new(entities.BuildInfo)
+	if err = json.Unmarshal(content, buildInfo); err != nil {
+		return err
+	}
+	b.statsCounter.addModules(buildInfo.Modules)
+	return b.notifyModulesCollected(buildInfo.Modules)
+}
+
+// Sends the events of the modules, each module's dependencies followed by the module itself.
+func (b *Build) notifyModulesCollected(modules []entities.Module) error {
+	if b.collectionListener == nil {
+		return nil
+	}
+	for i := range modules {
+		for j := range modules[i].Dependencies {
+			event := CollectionEvent{Event: DependencyEvent, ModuleId: modules[i].Id, Dependency: &modules[i].Dependencies[j]}
+			if err := b.collectionListener(event); err != nil {
+				return err
+			}
+		}
+		module := modules[i]
+		module.Dependencies = nil
+		if err := b.collectionListener(CollectionEvent{Event: ModuleEvent, Module: &module}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
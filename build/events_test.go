@@ -0,0 +1,67 @@
+package build
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJsonLinesListener(t *testing.T) {
+	bld := createTestBuild(t, "build-info-go-test-events")
+	var output bytes.Buffer
+	bld.SetCollectionListener(NewJsonLinesListener(&output))
+
+	require.NoError(t, bld.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{
+		{Id: "app", Type: entities.Npm, Dependencies: []entities.Dependency{{Id: "lodash:4.17.21"}, {Id: "debug:4.3.4"}}},
+	}}))
+	// The modules generated by an extractor are sent when it completes.
+	generatedPath, err := createEmptyBuildInfoFile(bld)
+	require.NoError(t, err)
+	content, err := json.Marshal(entities.BuildInfo{Modules: []entities.Module{
+		{Id: "com.example:lib:1.0", Type: entities.Maven, Dependencies: []entities.Dependency{{Id: "junit:junit:4.13.2"}}},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(generatedPath, content, 0600))
+	require.NoError(t, bld.notifyGeneratedModulesCollected(generatedPath))
+	_, err = bld.ToBuildInfo()
+	require.NoError(t, err)
+
+	var events []CollectionEvent
+	scanner := bufio.NewScanner(&output)
+	for scanner.Scan() {
+		var event CollectionEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.Len(t, events, 6)
+	assert.Equal(t, CollectionEvent{Event: DependencyEvent, ModuleId: "app", Dependency: &entities.Dependency{Id: "lodash:4.17.21"}}, events[0])
+	assert.Equal(t, CollectionEvent{Event: DependencyEvent, ModuleId: "app", Dependency: &entities.Dependency{Id: "debug:4.3.4"}}, events[1])
+	// The modules are sent without their dependencies.
+	assert.Equal(t, CollectionEvent{Event: ModuleEvent, Module: &entities.Module{Id: "app", Type: entities.Npm}}, events[2])
+	assert.Equal(t, CollectionEvent{Event: DependencyEvent, ModuleId: "com.example:lib:1.0", Dependency: &entities.Dependency{Id: "junit:junit:4.13.2"}}, events[3])
+	assert.Equal(t, CollectionEvent{Event: ModuleEvent, Module: &entities.Module{Id: "com.example:lib:1.0", Type: entities.Maven}}, events[4])
+	assert.Equal(t, BuildInfoEvent, events[5].Event)
+	require.NotNil(t, events[5].BuildInfo)
+	assert.Len(t, events[5].BuildInfo.Modules, 2)
+}
+
+func TestCollectionListenerError(t *testing.T) {
+	bld := createTestBuild(t, "build-info-go-test-events")
+	bld.SetCollectionListener(func(CollectionEvent) error {
+		return errors.New("the standard output is closed")
+	})
+	err := bld.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{{Id: "app", Type: entities.Npm}}})
+	assert.EqualError(t, err, "the standard output is closed")
+	// The module is saved before it's sent.
+	bld.SetCollectionListener(nil)
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	assert.Len(t, buildInfo.Modules, 1)
+}
@@ -0,0 +1,138 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+)
+
+// DependencyExclusion is a rule which excludes the matching dependencies from the build-info,
+// for example internal test fixtures or BOM-only entries.
+// A dependency matches the rule if it matches all the rule's non-empty patterns.
+type DependencyExclusion struct {
+	// A pattern of the dependency's ID, for example: org.example:test-fixtures:*
+	Id string `yaml:"id,omitempty"`
+	// A pattern of the dependency's group: the Maven groupId, the npm scope, or the Go module path without its last element.
+	Group string `yaml:"group,omitempty"`
+	// A pattern of one of the dependency's scopes, for example: test
+	Scope string `yaml:"scope,omitempty"`
+	// If true, the patterns are regular expressions. Otherwise, they're wildcard patterns, in which '*' matches any sequence of characters.
+	// In both cases, a pattern must match the whole value.
+	Regex bool `yaml:"regex,omitempty"`
+}
+
+// A DependencyExclusion with compiled patterns. A nil pattern matches any value.
+type dependencyExclusionMatcher struct {
+	id, group, scope *regexp.Regexp
+}
+
+func (e DependencyExclusion) compile() (matcher dependencyExclusionMatcher, err error) {
+	if e.Id == "" && e.Group == "" && e.Scope == "" {
+		return matcher, errors.New("a dependency exclusion rule must have at least one pattern")
+	}
+	if matcher.id, err = e.compilePattern(e.Id); err != nil {
+		return
+	}
+	if matcher.group, err = e.compilePattern(e.Group); err != nil {
+		return
+	}
+	matcher.scope, err = e.compilePattern(e.Scope)
+	return
+}
+
+func (e DependencyExclusion) compilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	expression := pattern
+	if !e.Regex {
+		expression = wildcardToRegexp(pattern)
+	}
+	compiled, err := regexp.Compile("^(?:" + expression + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid dependency exclusion pattern '%s': %w", pattern, err)
+	}
+	return compiled, nil
+}
+
+// Converts a wildcard pattern, in which '*' matches any sequence of characters, to a regular expression.
+func wildcardToRegexp(pattern string) string {
+	return strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+}
+
+// Validate returns an error if the rule has no patterns, or if one of its patterns is invalid.
+func (e DependencyExclusion) Validate() error {
+	_, err := e.compile()
+	return err
+}
+
+func (m dependencyExclusionMatcher) matches(dependency *entities.Dependency) bool {
+	if m.id != nil && !m.id.MatchString(dependency.Id) {
+		return false
+	}
+	if m.group != nil && !m.group.MatchString(getDependencyGroup(dependency.Id)) {
+		return false
+	}
+	if m.scope != nil {
+		for _, scope := range dependency.Scopes {
+			if m.scope.MatchString(scope) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Returns the group of a dependency ID:
+// the first part of a <group>:<name>:<version> ID, such as a Maven ID,
+// or the name's part before its last '/', such as an npm scope (@jfrog) or a Go module path without its last element (github.com/jfrog).
+func getDependencyGroup(dependencyId string) string {
+	parts := strings.Split(dependencyId, ":")
+	if len(parts) >= 3 {
+		return parts[0]
+	}
+	if lastSlash := strings.LastIndex(parts[0], "/"); lastSlash > 0 {
+		return parts[0][:lastSlash]
+	}
+	return ""
+}
+
+// Removes the dependencies matching any of the exclusion rules from all the modules.
+func applyDependencyExclusions(buildInfo *entities.BuildInfo, exclusions []DependencyExclusion) error {
+	if len(exclusions) == 0 {
+		return nil
+	}
+	matchers := make([]dependencyExclusionMatcher, 0, len(exclusions))
+	for _, exclusion := range exclusions {
+		matcher, err := exclusion.compile()
+		if err != nil {
+			return err
+		}
+		matchers = append(matchers, matcher)
+	}
+	for i := range buildInfo.Modules {
+		module := &buildInfo.Modules[i]
+		dependencies := module.Dependencies[:0]
+		for j := range module.Dependencies {
+			excluded := false
+			for _, matcher := range matchers {
+				if matcher.matches(&module.Dependencies[j]) {
+					excluded = true
+					break
+				}
+			}
+			if !excluded {
+				dependencies = append(dependencies, module.Dependencies[j])
+			}
+		}
+		if len(dependencies) == 0 {
+			dependencies = nil
+		}
+		module.Dependencies = dependencies
+	}
+	return nil
+}
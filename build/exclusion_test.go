@@ -0,0 +1,54 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDependencyGroup(t *testing.T) {
+	assert.Equal(t, "org.example", getDependencyGroup("org.example:lib:1.0"))
+	assert.Equal(t, "@jfrog", getDependencyGroup("@jfrog/build-info:1.0.0"))
+	assert.Equal(t, "github.com/jfrog", getDependencyGroup("github.com/jfrog/gofrog:v1.7.6"))
+	assert.Equal(t, "", getDependencyGroup("lodash:4.17.21"))
+}
+
+func TestApplyDependencyExclusions(t *testing.T) {
+	newBuildInfo := func() *entities.BuildInfo {
+		return &entities.BuildInfo{Modules: []entities.Module{
+			{Id: "maven-module", Dependencies: []entities.Dependency{
+				{Id: "org.example:lib:1.0", Scopes: []string{"compile"}},
+				{Id: "org.example:test-fixtures:1.0", Scopes: []string{"test"}},
+				{Id: "org.example:bom:1.0", Type: "pom", Scopes: []string{"import"}},
+				{Id: "junit:junit:4.13", Scopes: []string{"test"}},
+			}},
+			{Id: "npm-module", Dependencies: []entities.Dependency{{Id: "@internal/fixtures:1.0.0", Scopes: []string{"dev"}}}},
+		}}
+	}
+	testCases := []struct {
+		exclusions []DependencyExclusion
+		expected   []string
+	}{
+		{[]DependencyExclusion{{Id: "org.example:test-fixtures:*"}}, []string{"org.example:lib:1.0", "org.example:bom:1.0", "junit:junit:4.13", "@internal/fixtures:1.0.0"}},
+		{[]DependencyExclusion{{Group: "org.example", Scope: "test"}}, []string{"org.example:lib:1.0", "org.example:bom:1.0", "junit:junit:4.13", "@internal/fixtures:1.0.0"}},
+		{[]DependencyExclusion{{Scope: "test"}, {Group: "@internal"}}, []string{"org.example:lib:1.0", "org.example:bom:1.0"}},
+		{[]DependencyExclusion{{Id: `.*:(bom|test-fixtures):.*`, Regex: true}}, []string{"org.example:lib:1.0", "junit:junit:4.13", "@internal/fixtures:1.0.0"}},
+		// Wildcard patterns must match the whole value.
+		{[]DependencyExclusion{{Id: "org.example"}}, []string{"org.example:lib:1.0", "org.example:test-fixtures:1.0", "org.example:bom:1.0", "junit:junit:4.13", "@internal/fixtures:1.0.0"}},
+	}
+	for _, testCase := range testCases {
+		buildInfo := newBuildInfo()
+		assert.NoError(t, applyDependencyExclusions(buildInfo, testCase.exclusions))
+		var actual []string
+		for _, module := range buildInfo.Modules {
+			for _, dependency := range module.Dependencies {
+				actual = append(actual, dependency.Id)
+			}
+		}
+		assert.Equal(t, testCase.expected, actual, testCase.exclusions)
+	}
+
+	assert.Error(t, applyDependencyExclusions(newBuildInfo(), []DependencyExclusion{{}}))
+	assert.Error(t, applyDependencyExclusions(newBuildInfo(), []DependencyExclusion{{Id: "(", Regex: true}}))
+}
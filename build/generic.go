@@ -0,0 +1,258 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+	"golang.org/x/exp/slices"
+)
+
+// AddGenericArtifacts adds the files matching the patterns as artifacts of the generic module with the given ID,
+// for files produced by build steps which no package manager understands, such as zipped distributions or firmware images.
+// In the patterns, '*' and '?' match within a single directory, and '**' matches any number of directories, for example: dist/**/*.zip
+// The files ignored by the .biignore file in the working directory aren't added (see buildutils.IgnoreRules).
+// The entries of archive artifacts which match the build's sub-artifacts patterns are recorded as their sub-artifacts (see SetSubArtifactsPatterns).
+// The checksums and the size of files which are Git LFS pointers are of the large files they point to, rather than of the pointers.
+// The artifacts are saved in the build's local cache, so the build must have a name and a number. Returns the added artifacts.
+func (b *Build) AddGenericArtifacts(moduleId string, patterns ...string) ([]entities.Artifact, error) {
+	if moduleId == "" {
+		return nil, errors.New("a module ID must be provided in order to add generic artifacts")
+	}
+	var filePaths []string
+	for _, pattern := range patterns {
+		matches, err := globFiles(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			b.logger.Warn("No files match the pattern", pattern)
+		}
+		for _, match := range matches {
+			if !slices.Contains(filePaths, match) {
+				filePaths = append(filePaths, match)
+			}
+		}
+	}
+	filePaths, err := b.filterIgnoredArtifactFiles("", filePaths)
+	if err != nil {
+		return nil, err
+	}
+	var artifacts []entities.Artifact
+	for _, filePath := range filePaths {
+		checksum, size, err := b.getArtifactFileDetails(filePath)
+		if err != nil {
+			return nil, err
+		}
+		artifact := entities.Artifact{
+			Name:     filepath.Base(filePath),
+			Type:     strings.TrimPrefix(filepath.Ext(filePath), "."),
+			Path:     filepath.ToSlash(filePath),
+			Size:     size,
+			Checksum: checksum,
+		}
+		if err = b.setSubArtifacts(&artifact, filePath); err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	if len(artifacts) == 0 {
+		return nil, nil
+	}
+	return artifacts, b.AddArtifacts(moduleId, entities.Generic, artifacts...)
+}
+
+// Returns the checksums and the size of an artifact's file. The checksums are taken from the build's checksum cache, if it has one.
+// If the file is a Git LFS pointer, the checksums and the size are of the large file it points to (see getLocalFileDetails).
+func (b *Build) getArtifactFileDetails(filePath string) (entities.Checksum, int64, error) {
+	checksum, size, _, err := b.getLocalFileDetails(filePath)
+	return checksum, size, err
+}
+
+// Removes the files ignored by the .biignore file in the root directory from the files of the discovered artifacts.
+// Pass an empty root directory for the working directory.
+func (b *Build) filterIgnoredArtifactFiles(rootDir string, filePaths []string) ([]string, error) {
+	ignoreRules, err := buildutils.ReadBiIgnoreFile(rootDir)
+	if err != nil || ignoreRules == nil {
+		return filePaths, err
+	}
+	absRootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	var filteredPaths []string
+	for _, filePath := range filePaths {
+		absFilePath, err := filepath.Abs(filePath)
+		if err != nil {
+			return nil, err
+		}
+		relativePath, err := filepath.Rel(absRootDir, absFilePath)
+		if err != nil {
+			return nil, err
+		}
+		if ignoreRules.IsIgnored(relativePath) {
+			b.logger.Debug("Skipping", filePath, "which is ignored by", filepath.Join(rootDir, buildutils.BiIgnoreFileName))
+			continue
+		}
+		filteredPaths = append(filteredPaths, filePath)
+	}
+	return filteredPaths, nil
+}
+
+// AddGenericDependencies verifies the checksums of the external inputs declared in a dependencies file (see buildutils.ReadGenericDependencies),
+// such as firmware blobs or vendored SDKs, and adds them as dependencies of the generic module with the given ID.
+// The dependencies declared by URL are downloaded to calculate their checksums, and aren't saved.
+// The dependencies whose files are Git LFS pointers are verified by the large files they point to, and get the GitLfsOidProperty and GitLfsSizeProperty properties.
+// If the checksums of any of the dependencies don't match the declared ones, an error is returned and no dependencies are added.
+// The dependencies are saved in the build's local cache, so the build must have a name and a number. Returns the added dependencies.
+func (b *Build) AddGenericDependencies(moduleId, dependenciesFilePath string) ([]entities.Dependency, error) {
+	if moduleId == "" {
+		return nil, errors.New("a module ID must be provided in order to add generic dependencies")
+	}
+	if !b.buildNameAndNumberProvided() {
+		return nil, errors.New("a build name must be provided in order to add dependencies")
+	}
+	declaredDependencies, err := buildutils.ReadGenericDependencies(dependenciesFilePath)
+	if err != nil {
+		return nil, err
+	}
+	var dependencies []entities.Dependency
+	var mismatches []utils.IntegrityMismatchDetails
+	for _, declared := range declaredDependencies {
+		var checksums map[crypto.Algorithm]string
+		var properties map[string]string
+		if declared.Path != "" {
+			var checksum entities.Checksum
+			var pointer *buildutils.GitLfsPointer
+			checksum, _, pointer, err = b.getLocalFileDetails(declared.Path)
+			checksums = map[crypto.Algorithm]string{crypto.SHA1: checksum.Sha1, crypto.MD5: checksum.Md5, crypto.SHA256: checksum.Sha256}
+			if pointer != nil {
+				properties = getGitLfsProperties(pointer)
+			}
+		} else {
+			b.logger.Debug("Downloading", declared.Url, "to verify its checksums.")
+			checksums, err = getUrlChecksums(declared.Url)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, declaredChecksum := range []struct {
+			algorithm crypto.Algorithm
+			expected  string
+		}{{crypto.SHA256, declared.Sha256}, {crypto.SHA1, declared.Sha1}, {crypto.MD5, declared.Md5}} {
+			if actual := checksums[declaredChecksum.algorithm]; declaredChecksum.expected != "" && !strings.EqualFold(declaredChecksum.expected, actual) {
+				mismatches = append(mismatches, utils.IntegrityMismatchDetails{DependencyId: declared.Id, Lockfile: filepath.Base(dependenciesFilePath), Expected: declaredChecksum.expected, Actual: actual})
+			}
+		}
+		dependency := entities.Dependency{
+			Id:         declared.Id,
+			Type:       strings.TrimPrefix(path.Ext(declared.FileName()), "."),
+			Checksum:   entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]},
+			Properties: properties,
+		}
+		dependency.SetResolutionSource(entities.LockfileSource)
+		dependencies = append(dependencies, dependency)
+	}
+	if err = utils.IntegrityVerificationFail.HandleMismatches(mismatches, b.logger); err != nil {
+		return nil, err
+	}
+	if len(dependencies) == 0 {
+		return nil, nil
+	}
+	return dependencies, b.SavePartialBuildInfo(&entities.Partial{ModuleId: moduleId, ModuleType: entities.Generic, Dependencies: dependencies})
+}
+
+// Downloads the content of the URL, and returns its checksums.
+func getUrlChecksums(fileUrl string) (checksums map[crypto.Algorithm]string, err error) {
+	resp, err := http.Get(fileUrl)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s. Status: %s", fileUrl, resp.Status)
+	}
+	return crypto.CalcChecksums(resp.Body)
+}
+
+// Returns the regular files matching the pattern, in lexical order. Unlike filepath.Glob, '**' matches any number of directories.
+func globFiles(pattern string) (filePaths []string, err error) {
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	// The base directory is the longest prefix of the pattern without wildcards.
+	baseLength := 0
+	for baseLength < len(segments)-1 && !strings.ContainsAny(segments[baseLength], "*?[") {
+		baseLength++
+	}
+	baseDir := filepath.FromSlash(strings.Join(segments[:baseLength], "/"))
+	if baseLength == 1 && segments[0] == "" {
+		// An absolute path on Unix.
+		baseDir = string(filepath.Separator)
+	}
+	patternSegments := segments[baseLength:]
+	walkRoot := baseDir
+	if walkRoot == "" {
+		walkRoot = "."
+	}
+	err = filepath.WalkDir(walkRoot, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && filePath == walkRoot {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		relativePath, err := filepath.Rel(walkRoot, filePath)
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			// Without '**', the pattern can't match files deeper than its number of segments.
+			if filePath != walkRoot && !slices.Contains(patternSegments, "**") && strings.Count(filepath.ToSlash(relativePath), "/")+1 >= len(patternSegments) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		matched, err := matchGlobSegments(patternSegments, strings.Split(filepath.ToSlash(relativePath), "/"))
+		if err != nil || !matched {
+			return err
+		}
+		filePaths = append(filePaths, filepath.Join(baseDir, relativePath))
+		return nil
+	})
+	return
+}
+
+// Returns true if the path segments match the pattern segments, in which '**' matches any number of segments.
+func matchGlobSegments(patternSegments, pathSegments []string) (bool, error) {
+	if len(patternSegments) == 0 {
+		return len(pathSegments) == 0, nil
+	}
+	if patternSegments[0] == "**" {
+		for i := 0; i <= len(pathSegments); i++ {
+			if matched, err := matchGlobSegments(patternSegments[1:], pathSegments[i:]); err != nil || matched {
+				return matched, err
+			}
+		}
+		return false, nil
+	}
+	if len(pathSegments) == 0 {
+		return false, nil
+	}
+	matched, err := path.Match(patternSegments[0], pathSegments[0])
+	if err != nil || !matched {
+		return false, err
+	}
+	return matchGlobSegments(patternSegments[1:], pathSegments[1:])
+}
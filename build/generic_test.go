@@ -0,0 +1,190 @@
+package build
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddGenericArtifacts(t *testing.T) {
+	projectDir := t.TempDir()
+	for _, filePath := range []string{"dist/app.zip", "dist/linux/amd64/app.zip", "dist/linux/app.tar.gz", "docs/readme.zip"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(projectDir, filepath.Dir(filePath)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(projectDir, filePath), []byte(filePath), 0644))
+	}
+	bld := createTestBuild(t, "generic-artifacts-test")
+
+	// The app.zip file matches both patterns, and is added once.
+	artifacts, err := bld.AddGenericArtifacts("my-generic-module", filepath.Join(projectDir, "dist", "**", "*.zip"), filepath.Join(projectDir, "dist", "app.zip"))
+	require.NoError(t, err)
+	if assert.Len(t, artifacts, 2) {
+		assert.Equal(t, "app.zip", artifacts[0].Name)
+		assert.Equal(t, "zip", artifacts[0].Type)
+		assert.Equal(t, filepath.ToSlash(filepath.Join(projectDir, "dist", "app.zip")), artifacts[0].Path)
+		assert.NotEmpty(t, artifacts[0].Sha256)
+		assert.Equal(t, filepath.ToSlash(filepath.Join(projectDir, "dist", "linux", "amd64", "app.zip")), artifacts[1].Path)
+	}
+	artifacts, err = bld.AddGenericArtifacts("my-generic-module", filepath.Join(projectDir, "*", "*.zip"))
+	require.NoError(t, err)
+	assert.Len(t, artifacts, 2)
+	artifacts, err = bld.AddGenericArtifacts("my-generic-module", filepath.Join(projectDir, "missing", "**"))
+	require.NoError(t, err)
+	assert.Empty(t, artifacts)
+	_, err = bld.AddGenericArtifacts("", filepath.Join(projectDir, "**"))
+	assert.Error(t, err)
+
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	if assert.Len(t, buildInfo.Modules, 1) {
+		assert.Equal(t, "my-generic-module", buildInfo.Modules[0].Id)
+		assert.Equal(t, entities.Generic, buildInfo.Modules[0].Type)
+		assert.Len(t, buildInfo.Modules[0].Artifacts, 3)
+	}
+}
+
+func TestAddGenericArtifactsBiIgnore(t *testing.T) {
+	projectDir := t.TempDir()
+	for _, filePath := range []string{"dist/app.zip", "dist/app.zip.tmp", "dist/test-dumps/core.zip", "dist/keep.tmp"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(projectDir, filepath.Dir(filePath)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(projectDir, filePath), []byte(filePath), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ".biignore"), []byte("*.tmp\n!keep.tmp\ntest-dumps/\n"), 0644))
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(projectDir))
+	defer func() {
+		assert.NoError(t, os.Chdir(wd))
+	}()
+	bld := createTestBuild(t, "generic-artifacts-test")
+
+	// The .biignore file applies to the relative and the absolute patterns alike.
+	for _, pattern := range []string{filepath.Join("dist", "**"), filepath.Join(projectDir, "dist", "**")} {
+		artifacts, err := bld.AddGenericArtifacts("my-generic-module", pattern)
+		require.NoError(t, err)
+		var paths []string
+		for _, artifact := range artifacts {
+			paths = append(paths, strings.TrimPrefix(artifact.Path, filepath.ToSlash(projectDir)+"/"))
+		}
+		assert.Equal(t, []string{"dist/app.zip", "dist/keep.tmp"}, paths)
+	}
+}
+
+func TestAddGenericDependencies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sdk-2.1.0.tar.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte("sdk"))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "wifi.bin"), []byte("firmware"), 0644))
+	sdkSha256, firmwareSha1 := sha256.Sum256([]byte("sdk")), sha1.Sum([]byte("firmware"))
+	declarations := fmt.Sprintf("id,url,path,sha256,sha1\nsdk:2.1.0,%s/sdk-2.1.0.tar.gz,,%s,\n,,wifi.bin,,%s\n", server.URL, hex.EncodeToString(sdkSha256[:]), strings.ToUpper(hex.EncodeToString(firmwareSha1[:])))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deps.csv"), []byte(declarations), 0644))
+
+	bld := createTestBuild(t, "generic-dependencies-test")
+	dependencies, err := bld.AddGenericDependencies("firmware", filepath.Join(dir, "deps.csv"))
+	require.NoError(t, err)
+	if assert.Len(t, dependencies, 2) {
+		assert.Equal(t, "sdk:2.1.0", dependencies[0].Id)
+		assert.Equal(t, "gz", dependencies[0].Type)
+		assert.Equal(t, hex.EncodeToString(sdkSha256[:]), dependencies[0].Sha256)
+		assert.Equal(t, "wifi.bin", dependencies[1].Id)
+		assert.Equal(t, hex.EncodeToString(firmwareSha1[:]), dependencies[1].Sha1)
+	}
+
+	// A checksum mismatch fails without adding the dependencies.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deps.yaml"), []byte("dependencies: [{path: wifi.bin, sha256: abc}]"), 0644))
+	_, err = bld.AddGenericDependencies("firmware", filepath.Join(dir, "deps.yaml"))
+	var categorizedErr *utils.CategorizedError
+	if assert.ErrorAs(t, err, &categorizedErr) {
+		assert.Equal(t, utils.IntegrityMismatch, categorizedErr.Category)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deps.yaml"), []byte(fmt.Sprintf("dependencies: [{url: %s/missing.zip, sha256: abc}]", server.URL)), 0644))
+	_, err = bld.AddGenericDependencies("firmware", filepath.Join(dir, "deps.yaml"))
+	assert.Error(t, err)
+
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	if assert.Len(t, buildInfo.Modules, 1) {
+		assert.Equal(t, entities.Generic, buildInfo.Modules[0].Type)
+		assert.Len(t, buildInfo.Modules[0].Dependencies, 2)
+	}
+}
+
+func TestMatchGlobSegments(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		expected      bool
+	}{
+		{"**/*.zip", "app.zip", true},
+		{"**/*.zip", "linux/amd64/app.zip", true},
+		{"linux/**/app.zip", "linux/app.zip", true},
+		{"*/app.zip", "linux/amd64/app.zip", false},
+		{"app-?.zip", "app-1.zip", true},
+		{"**", "linux/app.tar.gz", true},
+		{"*.zip", "app.tar.gz", false},
+	}
+	for _, test := range tests {
+		matched, err := matchGlobSegments(strings.Split(test.pattern, "/"), strings.Split(test.path, "/"))
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, matched, test.pattern+" "+test.path)
+	}
+}
+
+func TestGitLfsPointerArtifactsAndDependencies(t *testing.T) {
+	repoDir := t.TempDir()
+	content := []byte("large model")
+	contentSha256, contentSha1 := sha256.Sum256(content), sha1.Sum(content)
+	oid := hex.EncodeToString(contentSha256[:])
+	pointerPath := filepath.Join(repoDir, "model.bin")
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(pointerPath, []byte(fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n", oid, len(content))), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "deps.yaml"), []byte("dependencies: [{path: model.bin, sha256: "+oid+"}]"), 0644))
+
+	bld := createTestBuild(t, "git-lfs-test")
+
+	// The object wasn't fetched, so only its SHA-256 and size are taken from the pointer.
+	artifacts, err := bld.AddGenericArtifacts("models", pointerPath)
+	require.NoError(t, err)
+	if assert.Len(t, artifacts, 1) {
+		assert.Equal(t, entities.Checksum{Sha256: oid}, artifacts[0].Checksum)
+		assert.Equal(t, int64(len(content)), artifacts[0].Size)
+	}
+	dependencies, err := bld.AddGenericDependencies("models", filepath.Join(repoDir, "deps.yaml"))
+	require.NoError(t, err)
+	if assert.Len(t, dependencies, 1) {
+		assert.Equal(t, map[string]string{GitLfsOidProperty: oid, GitLfsSizeProperty: fmt.Sprint(len(content)), entities.ResolutionSourceProperty: string(entities.LockfileSource)}, dependencies[0].Properties)
+	}
+
+	// The fetched object is hashed instead of the pointer.
+	objectPath := filepath.Join(repoDir, ".git", "lfs", "objects", oid[0:2], oid[2:4], oid)
+	require.NoError(t, os.MkdirAll(filepath.Dir(objectPath), 0755))
+	require.NoError(t, os.WriteFile(objectPath, content, 0644))
+	artifacts, err = bld.AddGenericArtifacts("models", pointerPath)
+	require.NoError(t, err)
+	if assert.Len(t, artifacts, 1) {
+		assert.Equal(t, oid, artifacts[0].Sha256)
+		assert.Equal(t, hex.EncodeToString(contentSha1[:]), artifacts[0].Sha1)
+		assert.Equal(t, int64(len(content)), artifacts[0].Size)
+	}
+
+	require.NoError(t, os.WriteFile(objectPath, []byte("corrupted"), 0644))
+	_, err = bld.AddGenericArtifacts("models", pointerPath)
+	assert.ErrorContains(t, err, "corrupted")
+}
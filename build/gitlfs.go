@@ -0,0 +1,57 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gofrog/crypto"
+)
+
+// The properties of the dependencies whose files are Git LFS pointers, which hold the OID (the SHA-256) and the size of the large files they point to.
+const (
+	GitLfsOidProperty  = "gitLfs.oid"
+	GitLfsSizeProperty = "gitLfs.size"
+)
+
+// Returns the checksums and the size of a local file. If the file is a Git LFS pointer, the checksums and the size of the large file it points to are returned
+// instead of the pointer's, by hashing its object in the repository's local Git LFS storage. If the object wasn't fetched, only the large file's SHA-256,
+// which is the pointer's OID, and its size are known. The pointer is returned for the files which are Git LFS pointers, and nil for the others.
+func (b *Build) getLocalFileDetails(filePath string) (checksum entities.Checksum, size int64, pointer *buildutils.GitLfsPointer, err error) {
+	if pointer, err = buildutils.ReadGitLfsPointer(filePath); err != nil {
+		return
+	}
+	hashedPath := filePath
+	if pointer != nil {
+		var objectPath string
+		if objectPath, err = buildutils.FindGitLfsObject(filePath, pointer); err != nil {
+			return
+		}
+		if objectPath == "" {
+			b.logger.Warn(fmt.Sprintf("%s is a Git LFS pointer, whose object wasn't fetched. Only its SHA-256 is recorded. Run 'git lfs pull' to fetch the object.", filePath))
+			return entities.Checksum{Sha256: pointer.Oid}, pointer.Size, pointer, nil
+		}
+		b.logger.Debug(fmt.Sprintf("%s is a Git LFS pointer. Hashing its object %s.", filePath, objectPath))
+		hashedPath = objectPath
+	}
+	fileInfo, err := os.Stat(hashedPath)
+	if err != nil {
+		return
+	}
+	checksums, err := b.checksumCache.GetFileChecksums(hashedPath)
+	if err != nil {
+		return
+	}
+	if pointer != nil && !strings.EqualFold(checksums[crypto.SHA256], pointer.Oid) {
+		return entities.Checksum{}, 0, nil, fmt.Errorf("the Git LFS object of %s is corrupted: its SHA-256 is %s, while the pointer's OID is %s", filePath, checksums[crypto.SHA256], pointer.Oid)
+	}
+	return entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}, fileInfo.Size(), pointer, nil
+}
+
+// Returns the properties of a dependency whose file is the Git LFS pointer.
+func getGitLfsProperties(pointer *buildutils.GitLfsPointer) map[string]string {
+	return map[string]string{GitLfsOidProperty: pointer.Oid, GitLfsSizeProperty: strconv.FormatInt(pointer.Size, 10)}
+}
@@ -6,15 +6,22 @@ import (
 	"github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/gofrog/crypto"
+	"golang.org/x/exp/slices"
+	"io/fs"
 	"path/filepath"
 	"strings"
 	"unicode"
 )
 
+// Directories which the go command ignores, and therefore never contain Go modules.
+var goModulesExcludedDirs = []string{"vendor", "testdata"}
+
 type GoModule struct {
 	containingBuild *Build
 	name            string
 	srcPath         string
+	// If true, the collection fails when the checksum database verification is disabled for all modules.
+	requireSumDbVerification bool
 }
 
 func newGoModule(srcPath string, containingBuild *Build) (*GoModule, error) {
@@ -35,6 +42,43 @@ func newGoModule(srcPath string, containingBuild *Build) (*GoModule, error) {
 	return &GoModule{name: name, srcPath: srcPath, containingBuild: containingBuild}, nil
 }
 
+// DiscoverGoModules walks the root directory and returns the paths of the directories which contain a go.mod file, relative to the root.
+// The root itself is returned as ".", if it contains a go.mod file. Nested modules, such as tools/ or examples/ modules, are returned as well.
+// The vendor and testdata directories, and the directories whose names start with '.' or '_', are skipped, like the go command does.
+// maxDepth - The maximal depth of the returned directories, where the root's depth is 0. If 0 or less, the depth isn't limited.
+func DiscoverGoModules(rootPath string, maxDepth int) ([]string, error) {
+	var modulePaths []string
+	err := filepath.WalkDir(rootPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return err
+		}
+		if relativePath != "." {
+			if slices.Contains(goModulesExcludedDirs, entry.Name()) || entry.Name()[0] == '.' || entry.Name()[0] == '_' {
+				return filepath.SkipDir
+			}
+			if depth := len(strings.Split(relativePath, string(filepath.Separator))); maxDepth > 0 && depth > maxDepth {
+				return filepath.SkipDir
+			}
+		}
+		exists, err := utils.IsFileExists(filepath.Join(path, "go.mod"), false)
+		if err != nil {
+			return err
+		}
+		if exists {
+			modulePaths = append(modulePaths, relativePath)
+		}
+		return nil
+	})
+	return modulePaths, err
+}
+
 func (gm *GoModule) CalcDependencies() error {
 	if !gm.containingBuild.buildNameAndNumberProvided() {
 		return errors.New("a build name must be provided in order to collect the project's dependencies")
@@ -54,6 +98,12 @@ func (gm *GoModule) SetName(name string) {
 	gm.name = name
 }
 
+// SetRequireSumDbVerification sets whether the collection should fail when the go command's checksum database verification
+// is disabled for all modules, for example by GOSUMDB=off, GONOSUMCHECK=1 or GOFLAGS=-insecure.
+func (gm *GoModule) SetRequireSumDbVerification(requireSumDbVerification bool) {
+	gm.requireSumDbVerification = requireSumDbVerification
+}
+
 func (gm *GoModule) AddArtifacts(artifacts ...entities.Artifact) error {
 	return gm.containingBuild.AddArtifacts(gm.name, entities.Go, artifacts...)
 }
@@ -81,6 +131,22 @@ func (gm *GoModule) getGoDependencies(cachePath string) (map[string]entities.Dep
 	if err != nil || len(modulesMap) == 0 {
 		return nil, err
 	}
+	sumDbSettings, err := utils.GetGoSumDbSettings(gm.srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the checksum database settings of the go command: %w", err)
+	}
+	if gm.requireSumDbVerification && sumDbSettings.DisabledBy != "" {
+		return nil, errors.New("the checksum database verification of the Go modules is disabled by " + sumDbSettings.DisabledBy)
+	}
+	proxySettings, err := utils.GetGoProxySettings(gm.srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the module proxy settings of the go command: %w", err)
+	}
+	goSumHashes, err := utils.ReadGoSumHashes(gm.srcPath)
+	if err != nil {
+		return nil, err
+	}
+	var mismatches []utils.IntegrityMismatchDetails
 	// Create a map from dependency to parents
 	buildInfoDependencies := make(map[string]entities.Dependency)
 	for moduleId := range modulesMap {
@@ -96,13 +162,26 @@ func (gm *GoModule) getGoDependencies(cachePath string) (map[string]entities.Dep
 		if zipPath == "" {
 			continue
 		}
-		zipDependency, err := populateZip(encodedDependencyId, zipPath)
+		zipDependency, err := populateZip(encodedDependencyId, zipPath, gm.containingBuild.checksumCache)
 		if err != nil {
 			return nil, err
 		}
+		modulePath, version, _ := strings.Cut(moduleId, ":")
+		moduleIntegrity := sumDbSettings.GetModuleIntegrity(modulePath, version, goSumHashes[moduleId])
+		zipDependency.GoIntegrity = &moduleIntegrity
+		zipDependency.RemoteRepository = proxySettings.GetModuleSource(modulePath, version)
+		if expectedHash, ok := goSumHashes[moduleId]; ok && gm.containingBuild.integrityVerification != utils.IntegrityVerificationOff {
+			actualHash, err := utils.GetGoModuleZipHash(zipPath)
+			if err != nil {
+				return nil, err
+			}
+			if actualHash != expectedHash {
+				mismatches = append(mismatches, utils.IntegrityMismatchDetails{DependencyId: moduleId, Lockfile: "go.sum", Expected: expectedHash, Actual: actualHash})
+			}
+		}
 		buildInfoDependencies[moduleId] = zipDependency
 	}
-	return buildInfoDependencies, nil
+	return buildInfoDependencies, gm.containingBuild.integrityVerification.HandleMismatches(mismatches, gm.containingBuild.logger)
 }
 
 // Returns the actual path to the dependency.
@@ -157,10 +236,11 @@ func (gm *GoModule) getPackagePathIfExists(cachePath, encodedDependencyId string
 }
 
 // populateZip adds the zip file as build-info dependency
-func populateZip(packageId, zipPath string) (zipDependency entities.Dependency, err error) {
+func populateZip(packageId, zipPath string, checksumCache *utils.ChecksumCache) (zipDependency entities.Dependency, err error) {
 	// Zip file dependency for the build-info
 	zipDependency = entities.Dependency{Id: packageId}
-	checksums, err := crypto.GetFileChecksums(zipPath)
+	zipDependency.SetResolutionSource(entities.CliTreeSource)
+	checksums, err := checksumCache.GetFileChecksums(zipPath)
 	if err != nil {
 		return
 	}
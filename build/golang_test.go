@@ -1,11 +1,14 @@
 package build
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jfrog/build-info-go/entities"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGenerateBuildInfoForGoProject(t *testing.T) {
@@ -26,6 +29,27 @@ func TestGenerateBuildInfoForGoProject(t *testing.T) {
 		assert.Len(t, buildInfo.Modules, 1)
 		validateModule(t, buildInfo.Modules[0], 6, 1, "github.com/jfrog/dependency", entities.Go, true)
 		validateRequestedBy(t, buildInfo.Modules[0])
+		for _, dependency := range buildInfo.Modules[0].Dependencies {
+			if assert.NotNil(t, dependency.GoIntegrity, dependency.Id) {
+				assert.True(t, strings.HasPrefix(dependency.GoIntegrity.GoSumHash, "h1:"), dependency.Id)
+				assert.NotEmpty(t, dependency.GoIntegrity.SumDbStatus, dependency.Id)
+			}
+		}
+	}
+}
+
+func TestGenerateBuildInfoForGoProjectWithSumDbDisabled(t *testing.T) {
+	t.Setenv("GOSUMDB", "off")
+	service := NewBuildInfoService()
+	goBuild, err := service.GetOrCreateBuild("build-info-go-test-golang-sumdb", "1")
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, goBuild.Clean())
+	}()
+	goModule, err := goBuild.AddGoModule(filepath.Join("testdata", "golang", "project"))
+	if assert.NoError(t, err) {
+		goModule.SetRequireSumDbVerification(true)
+		assert.ErrorContains(t, goModule.CalcDependencies(), "disabled by GOSUMDB=off")
 	}
 }
 
@@ -61,3 +85,30 @@ func validateRequestedBy(t *testing.T, module entities.Module) {
 		}
 	}
 }
+
+func TestDiscoverGoModules(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{
+		"go.mod",
+		filepath.Join("tools", "go.mod"),
+		filepath.Join("examples", "basic", "go.mod"),
+		filepath.Join("examples", "basic", "nested", "deep", "go.mod"),
+		// Directories which the go command ignores.
+		filepath.Join("vendor", "github.com", "dep", "go.mod"),
+		filepath.Join("internal", "testdata", "go.mod"),
+		filepath.Join(".git", "go.mod"),
+		filepath.Join("_scratch", "go.mod"),
+	} {
+		path := filepath.Join(root, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte{}, 0644))
+	}
+
+	modulePaths, err := DiscoverGoModules(root, 0)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{".", "tools", filepath.Join("examples", "basic"), filepath.Join("examples", "basic", "nested", "deep")}, modulePaths)
+
+	modulePaths, err = DiscoverGoModules(root, 2)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{".", "tools", filepath.Join("examples", "basic")}, modulePaths)
+}
@@ -0,0 +1,86 @@
+package build
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/build-info-go/buildinfotest"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/require"
+)
+
+// The golden files of the tests in this file. Run the tests with BUILD_INFO_UPDATE_GOLDEN=true to update them.
+var goldenDir = filepath.Join("testdata", "golden")
+
+func TestNpmManifestsGolden(t *testing.T) {
+	fixture := buildinfotest.CreateNpmProject(t, buildinfotest.NpmProject{
+		Name:            "app",
+		Version:         "1.0.0",
+		Dependencies:    []string{"debug", "@company/utils"},
+		DevDependencies: []string{"mocha"},
+		Packages: []buildinfotest.NpmPackage{
+			{Name: "debug", Version: "4.3.4", Dependencies: []string{"ms"}},
+			{Name: "ms", Version: "2.1.2"},
+			{Name: "@company/utils", Version: "1.0.0", Resolved: "https://npm.company.com/@company/utils/-/utils-1.0.0.tgz"},
+			{Name: "mocha", Version: "10.2.0", Dev: true, Dependencies: []string{"debug"}},
+		},
+	})
+	// The registries configured in the user's .npmrc file aren't used.
+	t.Setenv("NPM_CONFIG_USERCONFIG", filepath.Join(t.TempDir(), ".npmrc"))
+	bld := createTestBuild(t, "npm-golden")
+	npmModule, err := bld.AddNpmModuleFromManifests(fixture.Manifests(t))
+	require.NoError(t, err)
+	require.NoError(t, npmModule.CalcDependencies())
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	buildinfotest.AssertGolden(t, filepath.Join(goldenDir, "npm.json"), buildInfo)
+}
+
+func TestMavenLogGolden(t *testing.T) {
+	fixture := buildinfotest.CreateMavenProject(t, buildinfotest.MavenProject{
+		GroupId:    "org.example",
+		ArtifactId: "app",
+		Version:    "1.0.0",
+		Dependencies: []buildinfotest.MavenDependency{
+			{GroupId: "org.slf4j", ArtifactId: "slf4j-api", Version: "2.0.9", RepositoryUrl: "https://repo.maven.apache.org/maven2"},
+			{GroupId: "com.google.guava", ArtifactId: "guava", Version: "32.1.2-jre"},
+			{GroupId: "junit", ArtifactId: "junit", Version: "4.13.2", Scope: "test", RepositoryUrl: "https://repo.maven.apache.org/maven2"},
+		},
+	})
+	bld := createTestBuild(t, "maven-golden")
+	mavenModule, err := bld.AddMavenModule(fixture.Dir)
+	require.NoError(t, err)
+	require.NoError(t, mavenModule.calcDependenciesFromLog(strings.NewReader(fixture.Log), fixture.LocalRepository))
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	buildinfotest.AssertGolden(t, filepath.Join(goldenDir, "maven-log.json"), buildInfo)
+}
+
+func TestGradleBuildDependenciesGolden(t *testing.T) {
+	fixture := buildinfotest.CreateGradleProject(t, buildinfotest.GradleProject{
+		Group:        "com.example",
+		Name:         "root-project",
+		Version:      "1.0",
+		Dependencies: map[string][]string{"implementation": {"org.slf4j:slf4j-api:2.0.9"}},
+		Subprojects: []buildinfotest.GradleProject{{
+			Name:         "app",
+			Plugins:      []buildinfotest.GradlePlugin{{Id: "org.springframework.boot", Version: "3.1.0"}},
+			Dependencies: map[string][]string{"implementation": {"com.google.guava:guava:32.1.2-jre"}, "testImplementation": {"junit:junit:4.13.2"}},
+		}},
+	})
+	t.Setenv("GRADLE_USER_HOME", t.TempDir())
+	bld := createTestBuild(t, "gradle-golden")
+	gradleModule := &GradleModule{containingBuild: bld, buildInfoPath: fixture.ExtractorBuildInfoPath}
+	projects, err := getGradleProjects(fixture.Dir, "")
+	require.NoError(t, err)
+	require.NoError(t, gradleModule.addBuildDependencies(fixture.Dir, projects))
+
+	content, err := os.ReadFile(fixture.ExtractorBuildInfoPath)
+	require.NoError(t, err)
+	var buildInfo entities.BuildInfo
+	require.NoError(t, json.Unmarshal(content, &buildInfo))
+	buildinfotest.AssertGolden(t, filepath.Join(goldenDir, "gradle-build-dependencies.json"), &buildInfo)
+}
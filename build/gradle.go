@@ -2,7 +2,9 @@ package build
 
 import (
 	"bytes"
+	"context"
 	_ "embed"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -12,12 +14,19 @@ import (
 	"regexp"
 	"strings"
 
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
 	"github.com/jfrog/gofrog/version"
+	"golang.org/x/exp/slices"
 )
 
 const (
 	extractorPropsDir                 = "BUILDINFO_PROPFILE"
+	publishedArtifactsEnv             = "BUILDINFO_PUBLISHED_ARTIFACTS"
+	resolvedRepositoriesEnv           = "BUILDINFO_RESOLVED_REPOSITORIES"
+	projectsEnv                       = "BUILDINFO_PROJECTS"
 	gradleExtractorFileName           = "build-info-extractor-gradle-%s-uber.jar"
 	gradleInitScriptTemplate          = "gradle.init"
 	gradleExtractorRemotePath         = "org/jfrog/buildinfo/build-info-extractor-gradle/%s"
@@ -25,6 +34,14 @@ const (
 	gradleExtractor5DependencyVersion = "5.2.5"
 	projectPropertiesFlag             = "-P"
 	systemPropertiesFlag              = "-D"
+	GradleBuildScope                  = "build"
+)
+
+// The build properties which describe the Gradle distribution used by the project's wrapper.
+const (
+	GradleDistributionUrlProperty      = ToolchainPropertyPrefix + "gradle.distributionUrl"
+	GradleDistributionSha256Property   = ToolchainPropertyPrefix + "gradle.distributionSha256"
+	GradleDistributionVerifiedProperty = ToolchainPropertyPrefix + "gradle.distributionVerified"
 )
 
 var versionRegex = regexp.MustCompile(`Gradle (\d+\.\d+(?:\.\d+|-\w+-\d+)?)`)
@@ -42,6 +59,22 @@ type GradleModule struct {
 	srcPath string
 	// The Gradle extractor (dependency) which calculates the build-info.
 	gradleExtractorDetails *gradleExtractorDetails
+	// Path to the build info temp file that will be generated by the gradle extractor.
+	buildInfoPath string
+	// Path to the temp file to which the init script writes the artifacts published by the maven-publish tasks.
+	publishedArtifactsPath string
+	// Path to the temp file to which the init script writes the repositories from which the dependencies were downloaded.
+	resolvedRepositoriesPath string
+	// Path to the temp file to which the init script writes the Gradle paths of the projects, by the IDs of their modules.
+	projectsPath string
+	// Add the buildscript classpath and the applied plugins of each module to the build-info.
+	collectBuildPlugins bool
+	// Verify the distribution of the wrapper by the checksum published in the official Gradle distributions.
+	verifyWrapperDistribution bool
+	// The URL of the Gradle distributions, whose checksums the wrapper's distribution is verified by.
+	distributionsUrl string
+	// Add the artifacts published by the maven-publish and the ivy-publish tasks, and the distribution and Spring Boot archives, to the build-info.
+	capturePublishedArtifacts bool
 }
 
 type gradleExtractorDetails struct {
@@ -60,6 +93,14 @@ type gradleExtractorDetails struct {
 	props map[string]string
 	// Local path to the configuration file.
 	propsDir string
+	// Run Gradle with --no-daemon.
+	noDaemon bool
+	// The JDK which runs Gradle. If empty, it's found by the JAVA_HOME environment variable.
+	javaHome string
+	// The JVM arguments of the Gradle daemon, passed as the org.gradle.jvmargs property. The project's gradle.properties are used if empty.
+	jvmArgs string
+	// Additional arguments passed to each Gradle command.
+	args []string
 }
 
 // Add a new Gradle module to a given build.
@@ -72,6 +113,8 @@ func newGradleModule(containingBuild *Build, srcPath string) *GradleModule {
 			propsDir: filepath.Join(containingBuild.tempDirPath, PropertiesTempFolderName),
 			props:    map[string]string{},
 		},
+		distributionsUrl:          buildutils.GradleDistributionsUrl,
+		capturePublishedArtifacts: true,
 	}
 }
 
@@ -86,6 +129,103 @@ func (gm *GradleModule) SetExtractorDetails(localExtractorPath, extractorPropsDi
 	return gm
 }
 
+// SetUseWrapper sets whether the project is built with its Gradle wrapper (gradlew) rather than with the gradle executable in the PATH.
+// When the wrapper is used, the URL and the SHA-256 checksum of its Gradle distribution are added to the build properties.
+func (gm *GradleModule) SetUseWrapper(useWrapper bool) {
+	gm.gradleExtractorDetails.useWrapper = useWrapper
+}
+
+// SetVerifyWrapperDistribution sets whether the Gradle distribution of the wrapper should be verified by the SHA-256 checksum
+// published for it in the official Gradle distributions. The verification fails the collection if the checksums don't match.
+func (gm *GradleModule) SetVerifyWrapperDistribution(verifyWrapperDistribution bool) {
+	gm.verifyWrapperDistribution = verifyWrapperDistribution
+}
+
+// SetCollectBuildPlugins sets whether the buildscript classpath and the plugins applied in the build scripts should be added to the build-info.
+// They are added as dependencies with the 'build' scope, so that the tools which ran during the build are audited as well.
+func (gm *GradleModule) SetCollectBuildPlugins(collectBuildPlugins bool) {
+	gm.collectBuildPlugins = collectBuildPlugins
+}
+
+// SetTasks sets the tasks and the options of the Gradle command which builds the project. The default is: artifactoryPublish
+func (gm *GradleModule) SetTasks(tasks ...string) {
+	gm.gradleExtractorDetails.tasks = tasks
+}
+
+// SetCapturePublishedArtifacts sets whether the artifacts published by the maven-publish and the ivy-publish tasks,
+// and the distribution and Spring Boot archives, are added to the build-info. The default is true.
+// Use IsGradlePublishCommand to capture them only when the Gradle command publishes the project.
+func (gm *GradleModule) SetCapturePublishedArtifacts(capturePublishedArtifacts bool) {
+	gm.capturePublishedArtifacts = capturePublishedArtifacts
+}
+
+// SetNoDaemon sets whether Gradle runs without its daemon, by passing the --no-daemon option to each Gradle command.
+func (gm *GradleModule) SetNoDaemon(noDaemon bool) {
+	gm.gradleExtractorDetails.noDaemon = noDaemon
+}
+
+// SetJavaHome sets the home directory of the JDK which runs Gradle, overriding the JAVA_HOME environment variable.
+// A relative path is relative to the project's directory.
+func (gm *GradleModule) SetJavaHome(javaHome string) {
+	gm.gradleExtractorDetails.javaHome = javaHome
+}
+
+// SetJvmArgs sets the JVM arguments of the Gradle daemon, which are passed as the org.gradle.jvmargs property,
+// overriding the property in the project's gradle.properties file.
+func (gm *GradleModule) SetJvmArgs(jvmArgs string) {
+	gm.gradleExtractorDetails.jvmArgs = jvmArgs
+}
+
+// SetArgs sets additional arguments, which are passed to each Gradle command, before the tasks.
+func (gm *GradleModule) SetArgs(args ...string) {
+	gm.gradleExtractorDetails.args = args
+}
+
+// SetConfig sets the daemon, the JDK, the JVM arguments and the additional arguments of the Gradle commands from the project's configuration file.
+func (gm *GradleModule) SetConfig(config GradleConfig) {
+	gm.SetNoDaemon(config.NoDaemon)
+	gm.SetJavaHome(config.JavaHome)
+	gm.SetJvmArgs(config.JvmArgs)
+	gm.SetArgs(config.Args...)
+}
+
+// Returns the options passed to each Gradle command, and the JDK which runs it.
+// The working directory is expected to be the project's directory, which a relative JDK path is relative to.
+func (gm *GradleModule) getGradleOptions() (options []string, javaHome string, err error) {
+	details := gm.gradleExtractorDetails
+	if details == nil {
+		return
+	}
+	if details.noDaemon {
+		options = append(options, "--no-daemon")
+	}
+	if details.javaHome != "" {
+		if javaHome, err = filepath.Abs(details.javaHome); err != nil {
+			return
+		}
+		// The daemon's JDK is set as well, since the org.gradle.java.home property of gradle.properties takes precedence over JAVA_HOME.
+		options = append(options, systemPropertiesFlag+"org.gradle.java.home="+javaHome)
+	}
+	if details.jvmArgs != "" {
+		options = append(options, systemPropertiesFlag+"org.gradle.jvmargs="+details.jvmArgs)
+	}
+	options = append(options, details.args...)
+	return
+}
+
+// ReadSettings parses the settings file (settings.gradle.kts or settings.gradle) of the Gradle project.
+// Returns nil if the project has no settings file.
+func (gm *GradleModule) ReadSettings() (*buildutils.GradleSettings, error) {
+	srcPath := gm.srcPath
+	if srcPath == "" {
+		var err error
+		if srcPath, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	}
+	return buildutils.ReadGradleSettings(srcPath)
+}
+
 // Generates Gradle build-info.
 func (gm *GradleModule) CalcDependencies() (err error) {
 	gm.containingBuild.logger.Info("Running gradle...")
@@ -115,7 +255,527 @@ func (gm *GradleModule) CalcDependencies() (err error) {
 	if err != nil {
 		return err
 	}
-	return gradleRunConfig.runCmd(os.Stdout, os.Stderr)
+	defer func() {
+		if err == nil {
+			err = gm.containingBuild.addGeneratedResolutionTimes(gm.buildInfoPath)
+		}
+		if err == nil {
+			err = gm.containingBuild.notifyGeneratedModulesCollected(gm.buildInfoPath)
+		}
+	}()
+	defer func() {
+		for _, tempPath := range []string{gradleRunConfig.extractorPropsFile, gm.publishedArtifactsPath, gm.resolvedRepositoriesPath, gm.projectsPath} {
+			if tempPath == "" {
+				continue
+			}
+			if removeErr := os.Remove(tempPath); !errors.Is(removeErr, os.ErrNotExist) {
+				err = errors.Join(err, removeErr)
+			}
+		}
+	}()
+	if err = gradleRunConfig.runCmd(gm.containingBuild.getCommandOutput(), os.Stderr); err != nil {
+		return
+	}
+	if err = gm.addPublishedArtifacts(); err != nil {
+		return
+	}
+	if err = gm.addResolvedRepositories(); err != nil {
+		return
+	}
+	// The working directory is the project's root at this point.
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	if gm.gradleExtractorDetails.useWrapper {
+		if err = gm.addWrapperDistribution(projectDir); err != nil {
+			return
+		}
+	}
+	projects, err := getGradleProjects(projectDir, gm.projectsPath)
+	if err != nil {
+		return
+	}
+	if err = gm.verifyLockfiles(projects); err != nil || !gm.collectBuildPlugins {
+		return
+	}
+	return gm.addBuildDependencies(projectDir, projects)
+}
+
+// The projects of a Gradle build, to which the modules of the build-info generated by the extractor belong.
+type gradleProjects struct {
+	// The names of the root project and of the projects included in the settings file, mapped by the projects' Gradle paths, such as ':a:api'.
+	names map[string]string
+	// The directories of the projects, mapped by their Gradle paths.
+	dirs map[string]string
+	// The Gradle paths of the projects, mapped by the IDs of their modules, as recorded by the init script.
+	pathsByModuleId map[string]string
+}
+
+// Returns the projects of the Gradle build in the project directory, by its settings file, and by the project paths
+// written by the init script to projectsPath. The file is ignored if projectsPath is empty or doesn't exist.
+func getGradleProjects(projectDir, projectsPath string) (*gradleProjects, error) {
+	settings, err := buildutils.ReadGradleSettings(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	projects := &gradleProjects{names: map[string]string{}, dirs: map[string]string{}, pathsByModuleId: map[string]string{}}
+	rootProjectName := filepath.Base(projectDir)
+	if settings != nil {
+		if settings.RootProjectName != "" {
+			rootProjectName = settings.RootProjectName
+		}
+		for _, includedProject := range settings.IncludedProjects {
+			pathSegments := strings.Split(strings.Trim(includedProject, ":"), ":")
+			projectPath := ":" + strings.Join(pathSegments, ":")
+			projects.names[projectPath] = pathSegments[len(pathSegments)-1]
+			projects.dirs[projectPath] = filepath.Join(projectDir, filepath.Join(pathSegments...))
+		}
+	}
+	projects.names[":"] = rootProjectName
+	projects.dirs[":"] = projectDir
+	if projectsPath == "" {
+		return projects, nil
+	}
+	content, err := os.ReadFile(projectsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return projects, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ambiguousModuleIds := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		if line == "" {
+			continue
+		}
+		var project struct {
+			Id   string `json:"id"`
+			Path string `json:"path"`
+		}
+		if err = json.Unmarshal([]byte(line), &project); err != nil {
+			return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed to parse the projects of the Gradle build: %w", err))
+		}
+		// Projects with the same group, name and version have the same module ID, so their modules can't be told apart.
+		if existingPath, exists := projects.pathsByModuleId[project.Id]; exists && existingPath != project.Path {
+			ambiguousModuleIds[project.Id] = true
+		}
+		projects.pathsByModuleId[project.Id] = project.Path
+	}
+	for moduleId := range ambiguousModuleIds {
+		delete(projects.pathsByModuleId, moduleId)
+	}
+	return projects, nil
+}
+
+// Returns the Gradle path of the project of a module in the build-info generated by the extractor.
+// The module is matched by the path recorded for its ID by the init script, or by its artifact ID, if a single project has that name.
+func (gp *gradleProjects) findModuleProject(moduleId string) (projectPath string, found bool) {
+	if projectPath, found = gp.pathsByModuleId[moduleId]; found {
+		_, found = gp.dirs[projectPath]
+		return
+	}
+	moduleIdParts := strings.Split(moduleId, ":")
+	if len(moduleIdParts) < 2 {
+		return "", false
+	}
+	for path, name := range gp.names {
+		if name != moduleIdParts[1] {
+			continue
+		}
+		if found {
+			// Several projects have the module's name.
+			return "", false
+		}
+		projectPath, found = path, true
+	}
+	return
+}
+
+// A dependency whose version, as collected by the extractor, isn't one of the versions locked in the lockfile of its project.
+type gradleLockfileMismatch struct {
+	dependencyId   string
+	projectPath    string
+	lockedVersions []string
+}
+
+func (glm gradleLockfileMismatch) String() string {
+	return fmt.Sprintf("%s: the lockfile of the '%s' project locks '%s'", glm.dependencyId, glm.projectPath, strings.Join(glm.lockedVersions, "' or '"))
+}
+
+// When dependency locking is enabled, cross-checks the versions of the dependencies in the build-info generated by the extractor
+// against the versions locked in the lockfile of each module's project, and reports the dependencies whose versions don't match.
+// A warning is logged for the mismatches, or the collection fails if the integrity verification mode is IntegrityVerificationFail.
+func (gm *GradleModule) verifyLockfiles(projects *gradleProjects) error {
+	content, err := os.ReadFile(gm.buildInfoPath)
+	if err != nil || len(content) == 0 {
+		return err
+	}
+	buildInfo := new(entities.BuildInfo)
+	if err = json.Unmarshal(content, buildInfo); err != nil {
+		return err
+	}
+	var mismatches []string
+	for _, module := range buildInfo.Modules {
+		projectPath, found := projects.findModuleProject(module.Id)
+		if !found {
+			continue
+		}
+		lockedVersions, err := buildutils.ReadGradleLockfile(projects.dirs[projectPath])
+		if err != nil {
+			return err
+		}
+		if lockedVersions == nil {
+			continue
+		}
+		for _, dependency := range module.Dependencies {
+			idParts := strings.Split(dependency.Id, ":")
+			if len(idParts) < 3 {
+				continue
+			}
+			versions, ok := lockedVersions[idParts[0]+":"+idParts[1]]
+			if !ok || slices.Contains(versions, idParts[2]) {
+				continue
+			}
+			mismatches = append(mismatches, gradleLockfileMismatch{dependencyId: dependency.Id, projectPath: projectPath, lockedVersions: versions}.String())
+		}
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+	message := "The versions of the following dependencies don't match the versions locked in their Gradle lockfiles:\n" + strings.Join(mismatches, "\n")
+	if gm.containingBuild.integrityVerification == utils.IntegrityVerificationFail {
+		return utils.NewCategorizedError(utils.IntegrityMismatch, errors.New(message))
+	}
+	gm.containingBuild.logger.Warn(message)
+	return nil
+}
+
+// Adds the buildscript classpath and the applied plugins of each module to the build-info generated by the extractor.
+// The dependencies declared in the root project's build script are added to all the modules, since its buildscript classpath is inherited by the subprojects.
+func (gm *GradleModule) addBuildDependencies(projectDir string, projects *gradleProjects) error {
+	rootBuildScript, err := buildutils.ReadGradleBuildScript(projectDir)
+	if err != nil {
+		return err
+	}
+	// The build scripts of the projects, mapped by the projects' Gradle paths.
+	// The root project's build script is nil, since its dependencies are added to all the modules.
+	buildScripts := map[string]*buildutils.GradleBuildScript{}
+	for projectPath, dir := range projects.dirs {
+		if dir == projectDir {
+			buildScripts[projectPath] = nil
+			continue
+		}
+		if buildScripts[projectPath], err = buildutils.ReadGradleBuildScript(dir); err != nil {
+			return err
+		}
+	}
+	gradleUserHome, err := getGradleUserHome()
+	if err != nil {
+		return err
+	}
+	return gm.containingBuild.updateGeneratedBuildInfo(gm.buildInfoPath, func(buildInfo *entities.BuildInfo) {
+		for i, module := range buildInfo.Modules {
+			projectPath, found := projects.findModuleProject(module.Id)
+			if !found {
+				continue
+			}
+			buildScript := buildScripts[projectPath]
+			dependencies := gm.createBuildDependencies(rootBuildScript, gradleUserHome)
+			for _, dependency := range gm.createBuildDependencies(buildScript, gradleUserHome) {
+				if !slices.ContainsFunc(dependencies, func(existing entities.Dependency) bool { return existing.Id == dependency.Id }) {
+					dependencies = append(dependencies, dependency)
+				}
+			}
+			buildInfo.Modules[i].Dependencies = append(buildInfo.Modules[i].Dependencies, dependencies...)
+		}
+	})
+}
+
+func (gm *GradleModule) createBuildDependencies(buildScript *buildutils.GradleBuildScript, gradleUserHome string) (dependencies []entities.Dependency) {
+	if buildScript == nil {
+		return
+	}
+	for _, classpathDependency := range buildScript.Classpath {
+		dependencies = append(dependencies, gm.createBuildDependency(classpathDependency, "jar", gradleUserHome))
+	}
+	for _, plugin := range buildScript.Plugins {
+		// The plugin marker is a POM, which depends on the plugin's implementation.
+		dependencies = append(dependencies, gm.createBuildDependency(plugin.MarkerId(), "pom", gradleUserHome))
+	}
+	return
+}
+
+// Creates a build-info dependency with the 'build' scope. The checksums are calculated if the artifact exists in Gradle's cache.
+// dependencyNotation - The dependency in the group:name:version[:classifier][@extension] format. The classifier is kept in the dependency's ID,
+// so that the artifacts of the same version with different classifiers remain separate dependencies.
+// defaultType - The dependency's type, if the notation has no extension.
+func (gm *GradleModule) createBuildDependency(dependencyNotation, defaultType, gradleUserHome string) entities.Dependency {
+	dependencyId, extension, _ := strings.Cut(dependencyNotation, "@")
+	if extension == "" {
+		extension = defaultType
+	}
+	dependency := entities.Dependency{Id: dependencyId, Type: extension, Scopes: []string{GradleBuildScope}}
+	idParts := strings.Split(dependencyId, ":")
+	if len(idParts) < 3 || len(idParts) > 4 {
+		return dependency
+	}
+	group, name, version, classifier := idParts[0], idParts[1], idParts[2], ""
+	if len(idParts) == 4 {
+		classifier = idParts[3]
+	}
+	// Gradle's cache layout: caches/modules-2/files-2.1/<group>/<name>/<version>/<sha1>/<name>-<version>[-<classifier>].<extension>
+	artifacts, err := filepath.Glob(filepath.Join(gradleUserHome, "caches", "modules-2", "files-2.1", group, name, version, "*", gradleArtifactFileName(name, version, classifier, extension)))
+	if err != nil || len(artifacts) == 0 {
+		gm.containingBuild.logger.Debug("Couldn't find", dependencyNotation, "in Gradle's cache. Its checksums won't be calculated.")
+		return dependency
+	}
+	checksums, err := gm.containingBuild.checksumCache.GetFileChecksums(artifacts[0])
+	if err != nil {
+		gm.containingBuild.logger.Debug("Couldn't calculate the checksums of", dependencyNotation+":", err.Error())
+		return dependency
+	}
+	dependency.Checksum = entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}
+	return dependency
+}
+
+// The layouts of the artifacts recorded by the init script, other than the Maven layout of the artifacts published by maven-publish tasks.
+const (
+	// An artifact published by an ivy-publish task, with Gradle's default Ivy layout.
+	gradleIvyLayout = "ivy"
+	// A distribution or a Spring Boot archive, which was built but not necessarily published.
+	gradleArchiveLayout = "archive"
+)
+
+// An artifact published by a maven-publish or an ivy-publish task, or an archive built by a distribution or a Spring Boot task, as recorded by the init script.
+type gradlePublishedArtifact struct {
+	// Empty for artifacts published by maven-publish tasks.
+	Layout string `json:"layout,omitempty"`
+	// The URL of the remote repository. Empty if the artifact was published to the local Maven repository, or if it's an archive.
+	RepositoryUrl string `json:"repositoryUrl,omitempty"`
+	// The organisation of Ivy publications.
+	GroupId string `json:"groupId,omitempty"`
+	// The module of Ivy publications.
+	ArtifactId string `json:"artifactId,omitempty"`
+	// The revision of Ivy publications.
+	Version string `json:"version,omitempty"`
+	// The artifact's name in Ivy publications, or the archive's file name.
+	Name string `json:"name,omitempty"`
+	// The artifact's type in Ivy publications.
+	Type       string `json:"type,omitempty"`
+	Classifier string `json:"classifier,omitempty"`
+	Extension  string `json:"extension,omitempty"`
+	// The local path of the published file.
+	File string `json:"file,omitempty"`
+}
+
+// Returns the artifact's name in the repository.
+func (pa *gradlePublishedArtifact) name() string {
+	switch pa.Layout {
+	case gradleArchiveLayout:
+		return pa.Name
+	case gradleIvyLayout:
+		return gradleArtifactFileName(pa.Name, pa.Version, pa.Classifier, pa.Extension)
+	default:
+		return gradleArtifactFileName(pa.ArtifactId, pa.Version, pa.Classifier, pa.Extension)
+	}
+}
+
+// Returns the artifact's path in the repository. Ivy artifacts have Gradle's default Ivy layout: <organisation>/<module>/<revision>/<artifact>,
+// and the other artifacts have the Maven layout.
+func (pa *gradlePublishedArtifact) path() string {
+	group := pa.GroupId
+	if pa.Layout != gradleIvyLayout {
+		group = strings.ReplaceAll(group, ".", "/")
+	}
+	return strings.Join([]string{group, pa.ArtifactId, pa.Version, pa.name()}, "/")
+}
+
+// Returns the artifact's type.
+func (pa *gradlePublishedArtifact) artifactType() string {
+	if pa.Type != "" {
+		return pa.Type
+	}
+	return pa.Extension
+}
+
+// Returns the name of an artifact's file, which is the same in Maven repositories and in Gradle's cache: <name>-<version>[-<classifier>].<extension>
+func gradleArtifactFileName(name, version, classifier, extension string) string {
+	fileName := name + "-" + version
+	if classifier != "" {
+		fileName += "-" + classifier
+	}
+	return fileName + "." + extension
+}
+
+// Adds the artifacts published by the maven-publish and the ivy-publish tasks, and the distribution and Spring Boot archives,
+// to the build-info generated by the extractor.
+// Each artifact is added to the module of its publication, with the coordinates it was actually published with,
+// and with the URL of the remote repository it was published to. An archive is added to the module of its project.
+func (gm *GradleModule) addPublishedArtifacts() error {
+	if gm.publishedArtifactsPath == "" {
+		return nil
+	}
+	content, err := os.ReadFile(gm.publishedArtifactsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil || len(content) == 0 {
+		return err
+	}
+	artifactsByModule := map[string][]entities.Artifact{}
+	var moduleIds []string
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var publishedArtifact gradlePublishedArtifact
+		if err = json.Unmarshal([]byte(line), &publishedArtifact); err != nil {
+			return utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed to parse the artifacts published by Gradle: %w", err))
+		}
+		artifact := entities.Artifact{
+			Name:                   publishedArtifact.name(),
+			Type:                   publishedArtifact.artifactType(),
+			Path:                   publishedArtifact.path(),
+			OriginalDeploymentRepo: publishedArtifact.RepositoryUrl,
+		}
+		if checksum, size, err := gm.containingBuild.getArtifactFileDetails(publishedArtifact.File); err == nil {
+			artifact.Checksum = checksum
+			artifact.Size = size
+		} else {
+			gm.containingBuild.logger.Debug("Couldn't calculate the checksums of", publishedArtifact.File+":", err.Error())
+		}
+		moduleId := strings.Join([]string{publishedArtifact.GroupId, publishedArtifact.ArtifactId, publishedArtifact.Version}, ":")
+		if _, ok := artifactsByModule[moduleId]; !ok {
+			moduleIds = append(moduleIds, moduleId)
+		}
+		artifactsByModule[moduleId] = append(artifactsByModule[moduleId], artifact)
+	}
+	return gm.containingBuild.updateGeneratedBuildInfo(gm.buildInfoPath, func(buildInfo *entities.BuildInfo) {
+		for _, moduleId := range moduleIds {
+			moduleIndex := slices.IndexFunc(buildInfo.Modules, func(module entities.Module) bool { return module.Id == moduleId })
+			if moduleIndex < 0 {
+				buildInfo.Modules = append(buildInfo.Modules, entities.Module{Id: moduleId, Type: entities.Gradle})
+				moduleIndex = len(buildInfo.Modules) - 1
+			}
+			module := &buildInfo.Modules[moduleIndex]
+			for _, artifact := range artifactsByModule[moduleId] {
+				// An artifact published to several repositories is recorded once, with the last repository it was published to.
+				// An archive is recorded before it's published, so its published artifact replaces it.
+				if artifactIndex := slices.IndexFunc(module.Artifacts, func(existing entities.Artifact) bool { return existing.Name == artifact.Name }); artifactIndex >= 0 {
+					module.Artifacts[artifactIndex] = artifact
+				} else {
+					module.Artifacts = append(module.Artifacts, artifact)
+				}
+			}
+		}
+	})
+}
+
+// A module resolved by Gradle, with the repository from which it was downloaded, as recorded by the init script.
+type gradleResolvedRepository struct {
+	Id             string `json:"id,omitempty"`
+	RepositoryName string `json:"repositoryName,omitempty"`
+	// The URL of the repository. Empty if it isn't declared in the project, for example if it's declared in the settings file.
+	RepositoryUrl string `json:"repositoryUrl,omitempty"`
+}
+
+// Sets the remote repository of each dependency in the build-info generated by the extractor, to the URL of the repository
+// from which Gradle downloaded it, or to the repository's name if its URL is unknown.
+func (gm *GradleModule) addResolvedRepositories() error {
+	content, err := os.ReadFile(gm.resolvedRepositoriesPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil || len(content) == 0 {
+		return err
+	}
+	repositories := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var resolvedRepository gradleResolvedRepository
+		if err = json.Unmarshal([]byte(line), &resolvedRepository); err != nil {
+			return utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed to parse the repositories resolved by Gradle: %w", err))
+		}
+		repository := resolvedRepository.RepositoryUrl
+		if repository == "" {
+			repository = resolvedRepository.RepositoryName
+		}
+		// A module is recorded by each project which resolves it, but not every project declares the repository. The URL is preferred over the name.
+		if _, ok := repositories[resolvedRepository.Id]; !ok || resolvedRepository.RepositoryUrl != "" {
+			repositories[resolvedRepository.Id] = repository
+		}
+	}
+	return gm.containingBuild.updateGeneratedBuildInfo(gm.buildInfoPath, func(buildInfo *entities.BuildInfo) {
+		for i := range buildInfo.Modules {
+			for j := range buildInfo.Modules[i].Dependencies {
+				dependency := &buildInfo.Modules[i].Dependencies[j]
+				if repository, ok := repositories[dependency.Id]; ok && dependency.RemoteRepository == "" {
+					dependency.RemoteRepository = repository
+				}
+			}
+		}
+	})
+}
+
+// Adds the URL and the SHA-256 checksum of the Gradle distribution declared in the project's gradle-wrapper.properties to the build properties.
+// The checksum is calculated from the distribution's zip file, which the wrapper downloaded to the Gradle user home, or is the checksum declared
+// in the gradle-wrapper.properties if the zip file isn't found.
+func (gm *GradleModule) addWrapperDistribution(projectDir string) error {
+	wrapperProperties, err := buildutils.ReadGradleWrapperProperties(projectDir)
+	if err != nil || wrapperProperties == nil || wrapperProperties.DistributionUrl == "" {
+		return err
+	}
+	gradleUserHome, err := getGradleUserHome()
+	if err != nil {
+		return err
+	}
+	checksum := strings.ToLower(wrapperProperties.DistributionSha256Sum)
+	distributionPath := wrapperProperties.GetDistributionPath(gradleUserHome)
+	checksums, err := gm.containingBuild.checksumCache.GetFileChecksums(distributionPath)
+	switch {
+	case err == nil:
+		checksum = checksums[crypto.SHA256]
+	case errors.Is(err, os.ErrNotExist):
+		gm.containingBuild.logger.Debug("The Gradle distribution of the wrapper wasn't found at", distributionPath)
+	default:
+		return err
+	}
+	properties := map[string]string{GradleDistributionUrlProperty: wrapperProperties.DistributionUrl}
+	if checksum != "" {
+		properties[GradleDistributionSha256Property] = checksum
+	}
+	if gm.verifyWrapperDistribution {
+		if err = gm.verifyDistributionChecksum(wrapperProperties, checksum); err != nil {
+			return err
+		}
+		properties[GradleDistributionVerifiedProperty] = "true"
+	}
+	return gm.containingBuild.SavePartialBuildInfo(&entities.Partial{Env: properties})
+}
+
+// Compares the checksum of the wrapper's distribution with the checksum published for the distribution with the same file name in the official Gradle distributions.
+func (gm *GradleModule) verifyDistributionChecksum(wrapperProperties *buildutils.GradleWrapperProperties, checksum string) error {
+	fileName := wrapperProperties.DistributionFileName()
+	if checksum == "" {
+		return fmt.Errorf("the Gradle distribution %s can't be verified, since it wasn't downloaded and the gradle-wrapper.properties doesn't declare its distributionSha256Sum", fileName)
+	}
+	officialChecksum, err := buildutils.GetOfficialGradleDistributionSha256(context.Background(), gm.distributionsUrl, fileName)
+	if err != nil {
+		return fmt.Errorf("failed to get the official checksum of the Gradle distribution %s: %w", fileName, err)
+	}
+	if checksum != officialChecksum {
+		return utils.NewCategorizedError(utils.IntegrityMismatch, fmt.Errorf("the SHA-256 checksum of the Gradle distribution %s of the wrapper is '%s', but the official checksum is '%s'", fileName, checksum, officialChecksum))
+	}
+	gm.containingBuild.logger.Info("The Gradle distribution", fileName, "of the wrapper matches its official checksum.")
+	return nil
+}
+
+func getGradleUserHome() (string, error) {
+	if gradleUserHome := os.Getenv("GRADLE_USER_HOME"); gradleUserHome != "" {
+		return gradleUserHome, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".gradle"), nil
 }
 
 func (gm *GradleModule) downloadGradleExtractor(gradleExecPath string) (err error) {
@@ -140,10 +800,16 @@ func (gm *GradleModule) downloadGradleExtractor(gradleExecPath string) (err erro
 // For Gradle < 6.8.1 use Gradle extractor 4
 // gradleExecPath - The Gradle binary path
 func (gm *GradleModule) getExtractorVersionAndInitScript(gradleExecPath string) (string, string, error) {
+	options, javaHome, err := gm.getGradleOptions()
+	if err != nil {
+		return "", "", err
+	}
 	gradleRunConfig := &gradleRunConfig{
-		gradle: gradleExecPath,
-		tasks:  []string{"--version"},
-		logger: gm.containingBuild.logger,
+		gradle:   gradleExecPath,
+		options:  options,
+		javaHome: javaHome,
+		tasks:    []string{"--version"},
+		logger:   gm.containingBuild.logger,
 	}
 
 	outBuffer := new(bytes.Buffer)
@@ -171,7 +837,7 @@ func (gm *GradleModule) getExtractorVersionAndInitScript(gradleExecPath string)
 func parseGradleVersion(versionOutput string) (*version.Version, error) {
 	match := versionRegex.FindStringSubmatch(versionOutput)
 	if len(match) == 0 {
-		return nil, errors.New("couldn't parse the Gradle version: " + versionOutput)
+		return nil, utils.NewCategorizedError(utils.ParseFailure, errors.New("couldn't parse the Gradle version: "+versionOutput))
 	}
 	return version.NewVersion(match[1]), nil
 }
@@ -181,17 +847,33 @@ func (gm *GradleModule) createGradleRunConfig(gradleExecPath string) (*gradleRun
 	if err != nil {
 		return nil, err
 	}
+	gm.buildInfoPath = buildInfoPath
+	gm.publishedArtifactsPath = ""
+	if gm.capturePublishedArtifacts {
+		gm.publishedArtifactsPath = buildInfoPath + ".published"
+	}
+	gm.resolvedRepositoriesPath = buildInfoPath + ".repositories"
+	gm.projectsPath = buildInfoPath + ".projects"
 	extractorPropsFile, err := utils.CreateExtractorPropsFile(gm.gradleExtractorDetails.propsDir, buildInfoPath, gm.containingBuild.buildName, gm.containingBuild.buildNumber, gm.containingBuild.buildTimestamp, gm.containingBuild.projectKey, gm.gradleExtractorDetails.props)
 	if err != nil {
 		return nil, err
 	}
+	options, javaHome, err := gm.getGradleOptions()
+	if err != nil {
+		return nil, err
+	}
 	return &gradleRunConfig{
-		env:                gm.gradleExtractorDetails.props,
-		gradle:             gradleExecPath,
-		extractorPropsFile: extractorPropsFile,
-		tasks:              gm.gradleExtractorDetails.tasks,
-		initScript:         gm.gradleExtractorDetails.initScript,
-		logger:             gm.containingBuild.logger,
+		env:                  gm.gradleExtractorDetails.props,
+		gradle:               gradleExecPath,
+		options:              options,
+		javaHome:             javaHome,
+		extractorPropsFile:   extractorPropsFile,
+		tasks:                gm.gradleExtractorDetails.tasks,
+		initScript:           gm.gradleExtractorDetails.initScript,
+		publishedArtifacts:   gm.publishedArtifactsPath,
+		resolvedRepositories: gm.resolvedRepositoriesPath,
+		projects:             gm.projectsPath,
+		logger:               gm.containingBuild.logger,
 	}, nil
 }
 
@@ -208,14 +890,17 @@ func getInitScript(initScriptPattern, gradleDependenciesDir, gradlePluginFilenam
 	}
 	initScriptPath := filepath.Join(gradleDependenciesDir, gradleInitScriptTemplate)
 
-	exists, err := utils.IsFileExists(initScriptPath, true)
-	if exists || err != nil {
-		return initScriptPath, err
-	}
-
 	gradlePluginPath := filepath.Join(gradleDependenciesDir, gradlePluginFilename)
 	gradlePluginPath = strings.ReplaceAll(gradlePluginPath, "\\", "\\\\")
 	initScriptContent := strings.ReplaceAll(initScriptPattern, "${pluginLibDir}", gradlePluginPath)
+	// The init script is rewritten if it was created by an older version, whose script differs.
+	existingContent, err := os.ReadFile(initScriptPath)
+	if err == nil && string(existingContent) == initScriptContent {
+		return initScriptPath, nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
 	if !utils.IsPathExists(gradleDependenciesDir) {
 		err = os.MkdirAll(gradleDependenciesDir, 0777)
 		if err != nil {
@@ -243,17 +928,25 @@ func GetGradleExecPath(useWrapper bool) (string, error) {
 }
 
 type gradleRunConfig struct {
-	gradle             string
-	extractorPropsFile string
-	tasks              []string
-	initScript         string
-	env                map[string]string
-	logger             utils.Log
+	gradle string
+	// Options passed before the init script and the tasks. Unlike the tasks, their property values aren't quoted.
+	options []string
+	// The JDK which runs Gradle, set as JAVA_HOME. The JAVA_HOME of the environment is kept if empty.
+	javaHome             string
+	extractorPropsFile   string
+	tasks                []string
+	initScript           string
+	publishedArtifacts   string
+	resolvedRepositories string
+	projects             string
+	env                  map[string]string
+	logger               utils.Log
 }
 
 func (config *gradleRunConfig) GetCmd() *exec.Cmd {
 	var cmd []string
 	cmd = append(cmd, config.gradle)
+	cmd = append(cmd, config.options...)
 	if config.initScript != "" {
 		cmd = append(cmd, "--init-script", config.initScript)
 	}
@@ -294,8 +987,85 @@ func (config *gradleRunConfig) runCmd(stdout, stderr io.Writer) error {
 	for k, v := range config.env {
 		command.Env = append(command.Env, k+"="+v)
 	}
+	if config.javaHome != "" {
+		command.Env = append(command.Env, "JAVA_HOME="+config.javaHome)
+	}
 	command.Env = append(command.Env, extractorPropsDir+"="+config.extractorPropsFile)
+	if config.publishedArtifacts != "" {
+		command.Env = append(command.Env, publishedArtifactsEnv+"="+config.publishedArtifacts)
+	}
+	if config.resolvedRepositories != "" {
+		command.Env = append(command.Env, resolvedRepositoriesEnv+"="+config.resolvedRepositories)
+	}
+	if config.projects != "" {
+		command.Env = append(command.Env, projectsEnv+"="+config.projects)
+	}
 	command.Stderr = stderr
 	command.Stdout = stdout
-	return command.Run()
+	_, err := runTimedCommand(command)
+	return err
+}
+
+// The Gradle tasks which publish the project's artifacts.
+var gradlePublishTasks = map[string]bool{"publish": true, "publishToMavenLocal": true, "artifactoryPublish": true}
+
+// The Gradle options whose values are passed as separate arguments, and therefore aren't tasks.
+var gradleOptionsWithValues = map[string]bool{
+	"-x": true, "--exclude-task": true, "-p": true, "--project-dir": true, "-b": true, "--build-file": true, "-c": true, "--settings-file": true,
+	"-g": true, "--gradle-user-home": true, "-I": true, "--init-script": true, "--include-build": true, "--console": true, "--warning-mode": true,
+	"--priority": true, "-D": true, "--system-prop": true, "-P": true, "--project-prop": true,
+}
+
+// IsGradlePublishCommand returns true if the arguments of a Gradle command run a task which publishes the project's artifacts:
+// publish, publishToMavenLocal or artifactoryPublish, or a publishing task of a single publication or repository, such as publishMavenPublicationToMavenRepository.
+// Tasks which are qualified by the path of a project, such as :app:publish, are detected as well. Tasks excluded by -x aren't.
+func IsGradlePublishCommand(args []string) bool {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			if gradleOptionsWithValues[arg] {
+				// Skip the option's value.
+				i++
+			}
+			continue
+		}
+		task := arg[strings.LastIndex(arg, ":")+1:]
+		if gradlePublishTasks[task] || (strings.HasPrefix(task, "publish") && (strings.HasSuffix(task, "Repository") || strings.HasSuffix(task, "ToMavenLocal"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// RunGradleWithBuildInfo runs Gradle with the provided tasks and options in the project's working directory, and collects the project's build-info
+// into the build-info of the provided build. The project is built with its Gradle wrapper, if it has one.
+// The published artifacts are captured only if the command publishes the project (see IsGradlePublishCommand).
+// The build-info is saved in the temp directory of the build service, where it can be published by the JFrog CLI.
+func RunGradleWithBuildInfo(workingDir, buildName, buildNumber string, args ...string) error {
+	_, err := collectIntoBuild(buildName, buildNumber, collectGradle(workingDir, args))
+	return err
+}
+
+// RunGradleAndGetBuildInfo is like RunGradleWithBuildInfo, but also returns the build-info of the build,
+// and the directory in which its files are saved. See RunAndGetBuildInfo.
+func RunGradleAndGetBuildInfo(workingDir, buildName, buildNumber string, args ...string) (*entities.BuildInfo, string, error) {
+	return RunAndGetBuildInfo(buildName, buildNumber, collectGradle(workingDir, args))
+}
+
+// Returns a collection which runs Gradle with the provided arguments in the project's directory, and collects the project's build-info.
+func collectGradle(workingDir string, args []string) func(*Build) error {
+	return func(gradleBuild *Build) error {
+		gradleModule, err := gradleBuild.AddGradleModule(workingDir)
+		if err != nil {
+			return err
+		}
+		wrapperExists, err := utils.IsFileExists(filepath.Join(workingDir, "gradlew"), true)
+		if err != nil {
+			return err
+		}
+		gradleModule.SetUseWrapper(wrapperExists)
+		gradleModule.SetTasks(args...)
+		gradleModule.SetCapturePublishedArtifacts(IsGradlePublishCommand(args))
+		return gradleModule.CalcDependencies()
+	}
 }
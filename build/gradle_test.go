@@ -1,10 +1,20 @@
 package build
 
 import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/gofrog/version"
 	"github.com/stretchr/testify/assert"
@@ -131,3 +141,392 @@ func TestFormatCommandProperties(t *testing.T) {
 		assert.ElementsMatch(t, test.expected, result)
 	}
 }
+
+func TestGetGradleOptions(t *testing.T) {
+	gradleModule := &GradleModule{gradleExtractorDetails: &gradleExtractorDetails{}}
+	options, javaHome, err := gradleModule.getGradleOptions()
+	assert.NoError(t, err)
+	assert.Empty(t, options)
+	assert.Empty(t, javaHome)
+
+	// A relative JDK path is resolved in the working directory, which is the project's directory when Gradle runs.
+	gradleModule.SetConfig(GradleConfig{NoDaemon: true, JavaHome: "jdk-17", JvmArgs: "-Xmx2g -XX:MaxMetaspaceSize=512m", Args: []string{"--max-workers=2", "--offline"}})
+	options, javaHome, err = gradleModule.getGradleOptions()
+	assert.NoError(t, err)
+	expectedJavaHome, err := filepath.Abs("jdk-17")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedJavaHome, javaHome)
+	assert.Equal(t, []string{"--no-daemon", "-Dorg.gradle.java.home=" + expectedJavaHome, "-Dorg.gradle.jvmargs=-Xmx2g -XX:MaxMetaspaceSize=512m", "--max-workers=2", "--offline"}, options)
+
+	// The options precede the init script and the tasks, and their values aren't quoted.
+	runConfig := &gradleRunConfig{gradle: "gradle", options: options, initScript: "init.gradle", tasks: []string{"build"}, logger: &utils.NullLog{}}
+	assert.Equal(t, append(append([]string{"gradle"}, options...), "--init-script", "init.gradle", "build"), runConfig.GetCmd().Args)
+}
+
+func TestAddBuildDependencies(t *testing.T) {
+	projectDir := t.TempDir()
+	files := map[string]string{
+		"settings.gradle":                        "rootProject.name = 'root-project'\ninclude ':app'\n",
+		"build.gradle":                           "buildscript {\n    dependencies {\n        classpath 'com.example:root-plugin:1.0'\n    }\n}\n",
+		filepath.Join("app", "build.gradle.kts"): "plugins {\n    id(\"org.springframework.boot\") version \"3.1.0\"\n}\n",
+	}
+	for path, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(projectDir, path)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(projectDir, path), []byte(content), 0644))
+	}
+	buildInfo := entities.BuildInfo{Modules: []entities.Module{
+		{Id: "com.example:root-project:1.0", Type: entities.Gradle},
+		{Id: "com.example:app:1.0", Type: entities.Gradle, Dependencies: []entities.Dependency{{Id: "org.example:lib:2.0", Scopes: []string{"compile"}}}},
+	}}
+	content, err := json.Marshal(buildInfo)
+	assert.NoError(t, err)
+	buildInfoPath := filepath.Join(t.TempDir(), "build-info.json")
+	assert.NoError(t, os.WriteFile(buildInfoPath, content, 0644))
+	t.Setenv("GRADLE_USER_HOME", t.TempDir())
+
+	gradleModule := &GradleModule{containingBuild: &Build{logger: &utils.NullLog{}}, buildInfoPath: buildInfoPath}
+	projects, err := getGradleProjects(projectDir, "")
+	assert.NoError(t, err)
+	assert.NoError(t, gradleModule.addBuildDependencies(projectDir, projects))
+
+	content, err = os.ReadFile(buildInfoPath)
+	assert.NoError(t, err)
+	var updatedBuildInfo entities.BuildInfo
+	assert.NoError(t, json.Unmarshal(content, &updatedBuildInfo))
+	rootPluginDependency := entities.Dependency{Id: "com.example:root-plugin:1.0", Type: "jar", Scopes: []string{GradleBuildScope}}
+	assert.Equal(t, []entities.Dependency{rootPluginDependency}, updatedBuildInfo.Modules[0].Dependencies)
+	assert.Equal(t, []entities.Dependency{
+		{Id: "org.example:lib:2.0", Scopes: []string{"compile"}},
+		rootPluginDependency,
+		{Id: "org.springframework.boot:org.springframework.boot.gradle.plugin:3.1.0", Type: "pom", Scopes: []string{GradleBuildScope}},
+	}, updatedBuildInfo.Modules[1].Dependencies)
+}
+
+func TestCreateBuildDependency(t *testing.T) {
+	gradleUserHome := t.TempDir()
+	// Gradle's cache keeps each file in a directory named after its SHA-1 checksum.
+	cachedFiles := []string{
+		"org.lwjgl/lwjgl/3.3.3/5c8b2e5a3d1e4f6a7b8c9d0e1f2a3b4c5d6e7f80/lwjgl-3.3.3.jar",
+		"org.lwjgl/lwjgl/3.3.3/0f1e2d3c4b5a69788796a5b4c3d2e1f0a9b8c7d6/lwjgl-3.3.3-natives-linux.jar",
+		"com.example/native-tools/1.0/9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b/native-tools-1.0-linux.zip",
+	}
+	for _, cachedFile := range cachedFiles {
+		cachedFilePath := filepath.Join(gradleUserHome, "caches", "modules-2", "files-2.1", filepath.FromSlash(cachedFile))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(cachedFilePath), 0755))
+		assert.NoError(t, os.WriteFile(cachedFilePath, []byte(filepath.Base(cachedFile)), 0644))
+	}
+	gradleModule := &GradleModule{containingBuild: &Build{logger: &utils.NullLog{}}}
+
+	testCases := []struct {
+		notation     string
+		expectedId   string
+		expectedType string
+		expectedFile string
+	}{
+		{"org.lwjgl:lwjgl:3.3.3", "org.lwjgl:lwjgl:3.3.3", "jar", "lwjgl-3.3.3.jar"},
+		{"org.lwjgl:lwjgl:3.3.3:natives-linux", "org.lwjgl:lwjgl:3.3.3:natives-linux", "jar", "lwjgl-3.3.3-natives-linux.jar"},
+		{"com.example:native-tools:1.0:linux@zip", "com.example:native-tools:1.0:linux", "zip", "native-tools-1.0-linux.zip"},
+		// Not in the cache.
+		{"org.lwjgl:lwjgl:3.3.3:natives-windows", "org.lwjgl:lwjgl:3.3.3:natives-windows", "jar", ""},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.notation, func(t *testing.T) {
+			dependency := gradleModule.createBuildDependency(testCase.notation, "jar", gradleUserHome)
+			assert.Equal(t, testCase.expectedId, dependency.Id)
+			assert.Equal(t, testCase.expectedType, dependency.Type)
+			if testCase.expectedFile == "" {
+				assert.Empty(t, dependency.Sha1)
+				return
+			}
+			expectedSha1 := sha1.Sum([]byte(testCase.expectedFile))
+			assert.Equal(t, hex.EncodeToString(expectedSha1[:]), dependency.Sha1)
+		})
+	}
+}
+
+func TestAddPublishedArtifacts(t *testing.T) {
+	tempDir := t.TempDir()
+	jarPath := filepath.Join(tempDir, "app-1.0.jar")
+	assert.NoError(t, os.WriteFile(jarPath, []byte("jar"), 0644))
+	buildInfo := entities.BuildInfo{Modules: []entities.Module{
+		{Id: "com.example:app:1.0", Type: entities.Gradle, Artifacts: []entities.Artifact{{Name: "app-1.0.jar", Type: "jar"}}},
+	}}
+	content, err := json.Marshal(buildInfo)
+	assert.NoError(t, err)
+	buildInfoPath := filepath.Join(tempDir, "build-info.json")
+	assert.NoError(t, os.WriteFile(buildInfoPath, content, 0644))
+	publishedArtifacts := []string{
+		`{"repositoryUrl":"https://acme.jfrog.io/artifactory/libs-release-local","groupId":"com.example","artifactId":"app","version":"1.0","classifier":"","extension":"jar","file":"` + filepath.ToSlash(jarPath) + `"}`,
+		`{"repositoryUrl":"https://acme.jfrog.io/artifactory/libs-release-local","groupId":"com.example","artifactId":"app","version":"1.0","classifier":"sources","extension":"jar","file":"missing.jar"}`,
+		`{"repositoryUrl":"","groupId":"com.example","artifactId":"lib","version":"2.0","classifier":"","extension":"aar","file":"missing.aar"}`,
+		// The Spring Boot jar is recorded when it's built, and again when it's published.
+		`{"layout":"archive","groupId":"com.example","artifactId":"service","version":"3.0","name":"service-3.0.jar","classifier":"","extension":"jar","file":"missing.jar"}`,
+		`{"layout":"archive","groupId":"com.example","artifactId":"service","version":"3.0","name":"service-3.0.zip","classifier":"","extension":"zip","file":"missing.zip"}`,
+		`{"layout":"ivy","repositoryUrl":"https://acme.jfrog.io/artifactory/ivy-local","groupId":"com.example","artifactId":"service","version":"3.0","name":"service","type":"jar","classifier":"","extension":"jar","file":"missing.jar"}`,
+		`{"layout":"ivy","repositoryUrl":"https://acme.jfrog.io/artifactory/ivy-local","groupId":"com.example","artifactId":"service","version":"3.0","name":"ivy","type":"ivy","classifier":"","extension":"xml","file":"missing.xml"}`,
+	}
+	publishedArtifactsPath := filepath.Join(tempDir, "build-info.json.published")
+	assert.NoError(t, os.WriteFile(publishedArtifactsPath, []byte(strings.Join(publishedArtifacts, "\n")+"\n"), 0644))
+
+	gradleModule := &GradleModule{containingBuild: &Build{logger: &utils.NullLog{}}, buildInfoPath: buildInfoPath, publishedArtifactsPath: publishedArtifactsPath}
+	assert.NoError(t, gradleModule.addPublishedArtifacts())
+
+	content, err = os.ReadFile(buildInfoPath)
+	assert.NoError(t, err)
+	var updatedBuildInfo entities.BuildInfo
+	assert.NoError(t, json.Unmarshal(content, &updatedBuildInfo))
+	assert.Len(t, updatedBuildInfo.Modules, 3)
+	assert.Equal(t, []entities.Artifact{
+		{
+			Name:                   "app-1.0.jar",
+			Type:                   "jar",
+			Path:                   "com/example/app/1.0/app-1.0.jar",
+			OriginalDeploymentRepo: "https://acme.jfrog.io/artifactory/libs-release-local",
+			Checksum: entities.Checksum{
+				Sha1:   "f92e777f4341930bad9b2422283c4680d00dbc06",
+				Md5:    "68995fcbf432492d15484d04a9d2ac40",
+				Sha256: "0163f1eea7894350060624d315234d40c508ab251ba121714e234503045faadd",
+			},
+			Size: 3,
+		},
+		{Name: "app-1.0-sources.jar", Type: "jar", Path: "com/example/app/1.0/app-1.0-sources.jar", OriginalDeploymentRepo: "https://acme.jfrog.io/artifactory/libs-release-local"},
+	}, updatedBuildInfo.Modules[0].Artifacts)
+	assert.Equal(t, entities.Module{
+		Id:        "com.example:lib:2.0",
+		Type:      entities.Gradle,
+		Artifacts: []entities.Artifact{{Name: "lib-2.0.aar", Type: "aar", Path: "com/example/lib/2.0/lib-2.0.aar"}},
+	}, updatedBuildInfo.Modules[1])
+	assert.Equal(t, entities.Module{
+		Id:   "com.example:service:3.0",
+		Type: entities.Gradle,
+		Artifacts: []entities.Artifact{
+			{Name: "service-3.0.jar", Type: "jar", Path: "com.example/service/3.0/service-3.0.jar", OriginalDeploymentRepo: "https://acme.jfrog.io/artifactory/ivy-local"},
+			{Name: "service-3.0.zip", Type: "zip", Path: "com/example/service/3.0/service-3.0.zip"},
+			{Name: "ivy-3.0.xml", Type: "ivy", Path: "com.example/service/3.0/ivy-3.0.xml", OriginalDeploymentRepo: "https://acme.jfrog.io/artifactory/ivy-local"},
+		},
+	}, updatedBuildInfo.Modules[2])
+}
+
+func TestAddResolvedRepositories(t *testing.T) {
+	tempDir := t.TempDir()
+	buildInfo := entities.BuildInfo{Modules: []entities.Module{
+		{Id: "com.example:app:1.0", Type: entities.Gradle, Dependencies: []entities.Dependency{
+			{Id: "org.slf4j:slf4j-api:2.0.9"},
+			{Id: "junit:junit:4.13.2"},
+			{Id: "org.example:local:1.0", RemoteRepository: "https://repo.example.com"},
+			{Id: "org.example:unknown:1.0"},
+		}},
+	}}
+	content, err := json.Marshal(buildInfo)
+	assert.NoError(t, err)
+	buildInfoPath := filepath.Join(tempDir, "build-info.json")
+	assert.NoError(t, os.WriteFile(buildInfoPath, content, 0644))
+	resolvedRepositories := []string{
+		`{"id":"org.slf4j:slf4j-api:2.0.9","repositoryName":"maven","repositoryUrl":""}`,
+		`{"id":"org.slf4j:slf4j-api:2.0.9","repositoryName":"maven","repositoryUrl":"https://acme.jfrog.io/artifactory/api/maven/maven-remote"}`,
+		`{"id":"junit:junit:4.13.2","repositoryName":"MavenRepo","repositoryUrl":""}`,
+		`{"id":"org.example:local:1.0","repositoryName":"maven","repositoryUrl":"https://acme.jfrog.io/artifactory/api/maven/maven-remote"}`,
+	}
+	resolvedRepositoriesPath := filepath.Join(tempDir, "build-info.json.repositories")
+	assert.NoError(t, os.WriteFile(resolvedRepositoriesPath, []byte(strings.Join(resolvedRepositories, "\n")+"\n"), 0644))
+
+	gradleModule := &GradleModule{containingBuild: &Build{logger: &utils.NullLog{}}, buildInfoPath: buildInfoPath, resolvedRepositoriesPath: resolvedRepositoriesPath}
+	assert.NoError(t, gradleModule.addResolvedRepositories())
+
+	content, err = os.ReadFile(buildInfoPath)
+	assert.NoError(t, err)
+	var updatedBuildInfo entities.BuildInfo
+	assert.NoError(t, json.Unmarshal(content, &updatedBuildInfo))
+	assert.Equal(t, []entities.Dependency{
+		{Id: "org.slf4j:slf4j-api:2.0.9", RemoteRepository: "https://acme.jfrog.io/artifactory/api/maven/maven-remote"},
+		{Id: "junit:junit:4.13.2", RemoteRepository: "MavenRepo"},
+		{Id: "org.example:local:1.0", RemoteRepository: "https://repo.example.com"},
+		{Id: "org.example:unknown:1.0"},
+	}, updatedBuildInfo.Modules[0].Dependencies)
+
+	// No repositories are recorded if the init script didn't write the file.
+	gradleModule.resolvedRepositoriesPath = filepath.Join(tempDir, "missing")
+	assert.NoError(t, gradleModule.addResolvedRepositories())
+}
+
+func TestVerifyLockfiles(t *testing.T) {
+	projectDir := t.TempDir()
+	files := map[string]string{
+		"settings.gradle":                             "rootProject.name = 'root-project'\ninclude ':app'\n",
+		filepath.Join("app", "gradle.lockfile"):       "org.example:lib:2.0=compileClasspath\norg.example:other:1.0=runtimeClasspath\nempty=annotationProcessor\n",
+		filepath.Join("app", "build.gradle.kts"):      "",
+		filepath.Join("unlocked", "build.gradle.kts"): "",
+	}
+	for path, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(projectDir, path)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(projectDir, path), []byte(content), 0644))
+	}
+	buildInfo := entities.BuildInfo{Modules: []entities.Module{
+		{Id: "com.example:root-project:1.0", Type: entities.Gradle, Dependencies: []entities.Dependency{{Id: "org.example:lib:1.0"}}},
+		{Id: "com.example:app:1.0", Type: entities.Gradle, Dependencies: []entities.Dependency{
+			{Id: "org.example:lib:2.0"},
+			// The text-tree parser may attribute the requested version rather than the resolved one.
+			{Id: "org.example:other:0.9"},
+			{Id: "org.example:unlocked:3.0"},
+		}},
+	}}
+	content, err := json.Marshal(buildInfo)
+	assert.NoError(t, err)
+	buildInfoPath := filepath.Join(t.TempDir(), "build-info.json")
+	assert.NoError(t, os.WriteFile(buildInfoPath, content, 0644))
+	projects, err := getGradleProjects(projectDir, "")
+	assert.NoError(t, err)
+
+	gradleModule := &GradleModule{containingBuild: &Build{logger: &utils.NullLog{}}, buildInfoPath: buildInfoPath}
+	assert.NoError(t, gradleModule.verifyLockfiles(projects))
+
+	gradleModule.containingBuild.integrityVerification = utils.IntegrityVerificationFail
+	err = gradleModule.verifyLockfiles(projects)
+	assert.ErrorContains(t, err, "org.example:other:0.9: the lockfile of the ':app' project locks '1.0'")
+	assert.NotContains(t, err.Error(), "org.example:lib")
+	assert.Equal(t, utils.IntegrityMismatch, utils.GetErrorCategory(err))
+}
+
+func TestGradleProjectsWithSameName(t *testing.T) {
+	projectDir := t.TempDir()
+	files := map[string]string{
+		"settings.gradle":                            "rootProject.name = 'root-project'\ninclude ':a:api', ':b:api'\n",
+		filepath.Join("a", "api", "build.gradle"):    "plugins {\n    id 'com.example.a-plugin' version '1.0'\n}\n",
+		filepath.Join("a", "api", "gradle.lockfile"): "org.example:lib:1.0=compileClasspath\n",
+		filepath.Join("b", "api", "build.gradle"):    "plugins {\n    id 'com.example.b-plugin' version '2.0'\n}\n",
+		filepath.Join("b", "api", "gradle.lockfile"): "org.example:lib:2.0=compileClasspath\n",
+	}
+	for path, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(projectDir, path)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(projectDir, path), []byte(content), 0644))
+	}
+	// The init script records the Gradle paths of the projects, which have the same name, by the IDs of their modules.
+	projectsPath := filepath.Join(t.TempDir(), "build-info.json.projects")
+	assert.NoError(t, os.WriteFile(projectsPath, []byte(`{"id":"com.example:root-project:1.0","path":":"}
+{"id":"com.example.a:api:1.0","path":":a:api"}
+{"id":"com.example.b:api:1.0","path":":b:api"}
+`), 0644))
+	buildInfo := entities.BuildInfo{Modules: []entities.Module{
+		{Id: "com.example.a:api:1.0", Type: entities.Gradle, Dependencies: []entities.Dependency{{Id: "org.example:lib:1.0"}}},
+		{Id: "com.example.b:api:1.0", Type: entities.Gradle, Dependencies: []entities.Dependency{{Id: "org.example:lib:1.0"}}},
+	}}
+	content, err := json.Marshal(buildInfo)
+	assert.NoError(t, err)
+	buildInfoPath := filepath.Join(t.TempDir(), "build-info.json")
+	assert.NoError(t, os.WriteFile(buildInfoPath, content, 0644))
+	t.Setenv("GRADLE_USER_HOME", t.TempDir())
+
+	projects, err := getGradleProjects(projectDir, projectsPath)
+	assert.NoError(t, err)
+	gradleModule := &GradleModule{containingBuild: &Build{logger: &utils.NullLog{}, integrityVerification: utils.IntegrityVerificationFail}, buildInfoPath: buildInfoPath}
+	// Each module is verified against the lockfile of its own project.
+	err = gradleModule.verifyLockfiles(projects)
+	assert.ErrorContains(t, err, "org.example:lib:1.0: the lockfile of the ':b:api' project locks '2.0'")
+	assert.NotContains(t, err.Error(), ":a:api")
+
+	// Each module gets the plugins of its own project.
+	assert.NoError(t, gradleModule.addBuildDependencies(projectDir, projects))
+	content, err = os.ReadFile(buildInfoPath)
+	assert.NoError(t, err)
+	var updatedBuildInfo entities.BuildInfo
+	assert.NoError(t, json.Unmarshal(content, &updatedBuildInfo))
+	assert.Len(t, updatedBuildInfo.Modules, 2)
+	assert.Equal(t, []entities.Dependency{
+		{Id: "org.example:lib:1.0"},
+		{Id: "com.example.a-plugin:com.example.a-plugin.gradle.plugin:1.0", Type: "pom", Scopes: []string{GradleBuildScope}},
+	}, updatedBuildInfo.Modules[0].Dependencies)
+	assert.Equal(t, []entities.Dependency{
+		{Id: "org.example:lib:1.0"},
+		{Id: "com.example.b-plugin:com.example.b-plugin.gradle.plugin:2.0", Type: "pom", Scopes: []string{GradleBuildScope}},
+	}, updatedBuildInfo.Modules[1].Dependencies)
+
+	// Without the recorded paths, the modules of the projects which have the same name aren't matched to any of them.
+	projects, err = getGradleProjects(projectDir, "")
+	assert.NoError(t, err)
+	_, found := projects.findModuleProject("com.example.a:api:1.0")
+	assert.False(t, found)
+	projectPath, found := projects.findModuleProject("com.example:root-project:1.0")
+	assert.True(t, found)
+	assert.Equal(t, ":", projectPath)
+}
+
+func TestAddWrapperDistribution(t *testing.T) {
+	distribution := []byte("gradle distribution")
+	distributionChecksum := sha256.Sum256(distribution)
+	officialChecksum := hex.EncodeToString(distributionChecksum[:])
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(officialChecksum))
+	}))
+	defer server.Close()
+	gradleUserHome := t.TempDir()
+	t.Setenv("GRADLE_USER_HOME", gradleUserHome)
+	projectDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(projectDir, "gradle", "wrapper"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, buildutils.GradleWrapperPropertiesPath), []byte("distributionUrl=https\\://services.gradle.org/distributions/gradle-8.5-bin.zip\n"), 0644))
+
+	bld := createTestBuild(t, "build-info-go-test-gradle-wrapper")
+	gradleModule := newGradleModule(bld, projectDir)
+	gradleModule.distributionsUrl = server.URL
+	gradleModule.SetVerifyWrapperDistribution(true)
+	// The distribution can't be verified before the wrapper downloaded it.
+	assert.ErrorContains(t, gradleModule.addWrapperDistribution(projectDir), "the Gradle distribution gradle-8.5-bin.zip can't be verified")
+
+	wrapperProperties, err := buildutils.ReadGradleWrapperProperties(projectDir)
+	assert.NoError(t, err)
+	distributionPath := wrapperProperties.GetDistributionPath(gradleUserHome)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(distributionPath), 0755))
+	assert.NoError(t, os.WriteFile(distributionPath, distribution, 0644))
+	assert.NoError(t, gradleModule.addWrapperDistribution(projectDir))
+	buildInfo, err := bld.ToBuildInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://services.gradle.org/distributions/gradle-8.5-bin.zip", buildInfo.Properties[GradleDistributionUrlProperty])
+	assert.Equal(t, officialChecksum, buildInfo.Properties[GradleDistributionSha256Property])
+	assert.Equal(t, "true", buildInfo.Properties[GradleDistributionVerifiedProperty])
+
+	// A distribution which differs from the official one fails the verification.
+	assert.NoError(t, os.WriteFile(distributionPath, []byte("tampered distribution"), 0644))
+	err = gradleModule.addWrapperDistribution(projectDir)
+	assert.ErrorContains(t, err, "but the official checksum is '"+officialChecksum+"'")
+	assert.Equal(t, utils.IntegrityMismatch, utils.GetErrorCategory(err))
+}
+
+func TestIsGradlePublishCommand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{[]string{"clean", "build"}, false},
+		{[]string{"clean", "publish"}, true},
+		{[]string{"publishToMavenLocal"}, true},
+		{[]string{"--no-daemon", "artifactoryPublish", "-Pversion=1.0"}, true},
+		{[]string{":app:publish"}, true},
+		{[]string{"publishMavenPublicationToNexusRepository"}, true},
+		{[]string{"publishMavenPublicationToMavenLocal"}, true},
+		{[]string{"publishPlugins"}, false},
+		{[]string{"build", "-x", "publish"}, false},
+		{[]string{"build", "--exclude-task", "artifactoryPublish"}, false},
+		{[]string{"-p", "publish", "build"}, false},
+		{[]string{}, false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, IsGradlePublishCommand(test.args), test.args)
+	}
+}
+
+func TestCapturePublishedArtifacts(t *testing.T) {
+	gradleBuild := createTestBuild(t, "build-info-go-test-gradle-capture")
+	gradleModule, err := gradleBuild.AddGradleModule("")
+	assert.NoError(t, err)
+	gradleModule.SetTasks("clean", "publish")
+	runConfig, err := gradleModule.createGradleRunConfig("gradle")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"clean", "publish"}, runConfig.tasks)
+	assert.Equal(t, gradleModule.buildInfoPath+".published", runConfig.publishedArtifacts)
+
+	// The artifacts aren't captured, so the init script doesn't record them, and none are added to the build-info.
+	gradleModule.SetCapturePublishedArtifacts(false)
+	runConfig, err = gradleModule.createGradleRunConfig("gradle")
+	assert.NoError(t, err)
+	assert.Empty(t, runConfig.publishedArtifacts)
+	assert.NoError(t, gradleModule.addPublishedArtifacts())
+}
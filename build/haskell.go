@@ -0,0 +1,310 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+	"golang.org/x/exp/maps"
+)
+
+type HaskellModule struct {
+	containingBuild *Build
+	name            string
+	srcPath         string
+	tool            buildutils.HaskellTool
+	// The arguments of a cabal or a stack command, such as 'build', which runs before the dependencies are collected.
+	toolArgs []string
+	// The duration of the command, if it ran.
+	commandDuration time.Duration
+}
+
+// Pass an empty string for srcPath to use the Haskell project in the working directory.
+// The project's tool is Stack if the project has a stack.yaml, or Cabal otherwise.
+func newHaskellModule(srcPath string, containingBuild *Build) (*HaskellModule, error) {
+	if srcPath == "" {
+		var err error
+		if srcPath, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	}
+	tool, err := buildutils.GetHaskellTool(srcPath)
+	if err != nil {
+		return nil, err
+	}
+	return &HaskellModule{srcPath: srcPath, tool: tool, containingBuild: containingBuild}, nil
+}
+
+func (hm *HaskellModule) SetName(name string) {
+	hm.name = name
+}
+
+// SetToolArgs sets the arguments of a command of the project's tool (cabal or stack), for example: build --only-dependencies
+// The command runs in the project's directory before the dependencies are collected, so that the dependencies are installed in the package databases.
+func (hm *HaskellModule) SetToolArgs(toolArgs []string) {
+	hm.toolArgs = toolArgs
+}
+
+// Build runs the command set by SetToolArgs, if any, and then collects the project's dependencies.
+func (hm *HaskellModule) Build() error {
+	if len(hm.toolArgs) > 0 {
+		command := exec.Command(string(hm.tool), hm.toolArgs...)
+		command.Dir = hm.srcPath
+		var err error
+		if hm.commandDuration, err = runModuleCommand(command); err != nil {
+			return fmt.Errorf("failed running '%s %s': %w", hm.tool, strings.Join(hm.toolArgs, " "), err)
+		}
+	}
+	return hm.CalcDependencies()
+}
+
+// CalcDependencies collects the project's dependencies, without running Cabal or Stack.
+// The dependencies are the packages which the project's package depends on, directly or through the package databases in which the tool installed them,
+// and the packages pinned by the project's cabal.project.freeze (Cabal) or stack.yaml.lock (Stack).
+// The checksums of the packages are calculated from their source distributions in the Cabal packages cache,
+// or taken from the signed metadata of the Hackage index if they aren't cached.
+// The source distributions of the project's package, built by 'cabal sdist' or 'stack sdist', are added as the module's artifacts.
+func (hm *HaskellModule) CalcDependencies() error {
+	if !hm.containingBuild.buildNameAndNumberProvided() {
+		return errors.New("a build name must be provided in order to collect the project's dependencies")
+	}
+	description, err := buildutils.ReadHaskellPackageDescription(hm.srcPath)
+	if err != nil {
+		return err
+	}
+	pinnedPackages, err := buildutils.ReadHaskellPinnedPackages(hm.srcPath, hm.tool)
+	if err != nil {
+		return err
+	}
+	if description == nil && len(pinnedPackages) == 0 {
+		return errors.New("no .cabal file, package.yaml, " + buildutils.CabalFreezeFileName + " or " + buildutils.StackLockFileName + " was found in " + hm.srcPath)
+	}
+	hm.setModuleId(description)
+	dependencies, err := hm.getDependencies(description, pinnedPackages)
+	if err != nil {
+		return err
+	}
+	buildInfoModule := entities.Module{Id: hm.name, Type: entities.Hackage, Dependencies: dependencies}
+	if description != nil && description.Version != "" {
+		if buildInfoModule.Artifacts, err = hm.getSdistArtifacts(description); err != nil {
+			return err
+		}
+	}
+	addCommandDuration(&buildInfoModule, hm.commandDuration)
+	return hm.containingBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{buildInfoModule}})
+}
+
+// If the module ID wasn't set, it's the name and the version of the project's package, or the name of the project's directory.
+func (hm *HaskellModule) setModuleId(description *buildutils.HaskellPackageDescription) {
+	if hm.name != "" {
+		return
+	}
+	if description != nil && description.Name != "" {
+		hm.name = description.Name
+		if description.Version != "" {
+			hm.name += ":" + description.Version
+		}
+		return
+	}
+	hm.name = filepath.Base(hm.srcPath)
+	hm.containingBuild.logger.Debug(fmt.Sprintf("The project doesn't have a package description. Using its directory name: %s as the module name.", hm.name))
+}
+
+func (hm *HaskellModule) getDependencies(description *buildutils.HaskellPackageDescription, pinnedPackages []*buildutils.HaskellPackage) ([]entities.Dependency, error) {
+	dbDirs, err := buildutils.GetHaskellPackageDbDirs(hm.srcPath, hm.tool)
+	if err != nil {
+		return nil, err
+	}
+	installedPackages, err := buildutils.ReadHaskellPackageDbs(dbDirs)
+	if err != nil {
+		return nil, err
+	}
+	pinnedVersions := make(map[string]string)
+	for _, pinnedPackage := range pinnedPackages {
+		pinnedVersions[pinnedPackage.Name] = pinnedPackage.Version
+	}
+	// The installed packages by their names. The package databases may have several versions of a package, so the pinned version is preferred.
+	installedByName := make(map[string]*buildutils.InstalledHaskellPackage)
+	unitIds := maps.Keys(installedPackages)
+	sort.Strings(unitIds)
+	for _, unitId := range unitIds {
+		installedPackage := installedPackages[unitId]
+		if existing, ok := installedByName[installedPackage.Name]; !ok || existing.Version != pinnedVersions[installedPackage.Name] {
+			installedByName[installedPackage.Name] = installedPackage
+		}
+	}
+	packagesById := make(map[string]*buildutils.HaskellPackage)
+	dependenciesGraph := make(map[string][]string)
+	// Resolves the package of a name to its pinned or installed version, and adds it and its installed dependencies to the graph.
+	var addPackage func(name, version string, installedPackage *buildutils.InstalledHaskellPackage) string
+	addPackage = func(name, version string, installedPackage *buildutils.InstalledHaskellPackage) string {
+		if installedPackage == nil {
+			if pinnedVersion, ok := pinnedVersions[name]; ok {
+				version = pinnedVersion
+			}
+			if installed, ok := installedByName[name]; ok && (version == "" || installed.Version == version) {
+				installedPackage = installed
+				version = installed.Version
+			}
+		}
+		if version == "" {
+			hm.containingBuild.logger.Debug("The version of the Haskell package", name, "is unknown. Skipping it.")
+			return ""
+		}
+		haskellPackage := &buildutils.HaskellPackage{Name: name, Version: version}
+		if _, ok := packagesById[haskellPackage.Id()]; ok {
+			return haskellPackage.Id()
+		}
+		packagesById[haskellPackage.Id()] = haskellPackage
+		if installedPackage != nil {
+			for _, unitId := range installedPackage.Depends {
+				childName, childVersion := buildutils.ParseHaskellUnitId(unitId)
+				childInstalled := installedPackages[unitId]
+				if childInstalled != nil {
+					childName, childVersion = childInstalled.Name, childInstalled.Version
+				}
+				if childId := addPackage(childName, childVersion, childInstalled); childId != "" && childId != haskellPackage.Id() {
+					dependenciesGraph[haskellPackage.Id()] = append(dependenciesGraph[haskellPackage.Id()], childId)
+				}
+			}
+		}
+		return haskellPackage.Id()
+	}
+	if description != nil {
+		for _, name := range description.BuildDepends {
+			if id := addPackage(name, "", nil); id != "" {
+				dependenciesGraph[hm.name] = append(dependenciesGraph[hm.name], id)
+			}
+		}
+	}
+	for _, pinnedPackage := range pinnedPackages {
+		if description == nil || pinnedPackage.Name != description.Name {
+			addPackage(pinnedPackage.Name, pinnedPackage.Version, nil)
+		}
+	}
+
+	dependenciesMap, err := hm.getDependenciesDetails(packagesById, pinnedVersions)
+	if err != nil {
+		return nil, err
+	}
+	emptyRequestedBy := [][]string{{}}
+	populateRequestedByField(hm.name, emptyRequestedBy, dependenciesMap, dependenciesGraph)
+	return dependenciesMapToList(dependenciesMap), nil
+}
+
+// Returns the dependencies of the packages by their IDs, with their checksums.
+// The checksums of a package are calculated from its source distribution in the Cabal packages cache,
+// and compared with the SHA-256 checksum signed by Hackage, if the Hackage index has the package.
+// If the package isn't cached, the checksums in the Hackage index are recorded.
+func (hm *HaskellModule) getDependenciesDetails(packagesById map[string]*buildutils.HaskellPackage, pinnedVersions map[string]string) (map[string]entities.Dependency, error) {
+	dependenciesMap := make(map[string]entities.Dependency)
+	var uncachedPackages []*buildutils.HaskellPackage
+	for id, haskellPackage := range packagesById {
+		// Without checksums, a package is known from the pinned versions, or from the package databases.
+		dependency := entities.Dependency{Id: id, Type: "tar.gz"}
+		dependency.SetResolutionSource(entities.FilesystemSource)
+		if pinnedVersions[haskellPackage.Name] == haskellPackage.Version {
+			dependency.SetResolutionSource(entities.LockfileSource)
+		}
+		tarballPath, err := buildutils.FindHackageTarball(haskellPackage)
+		if err != nil {
+			return nil, err
+		}
+		if tarballPath == "" {
+			uncachedPackages = append(uncachedPackages, haskellPackage)
+		} else {
+			checksums, err := hm.containingBuild.checksumCache.GetFileChecksums(tarballPath)
+			if err != nil {
+				return nil, err
+			}
+			dependency.Checksum = entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}
+			dependency.SetResolutionSource(entities.CacheSource)
+		}
+		dependenciesMap[id] = dependency
+	}
+	verifyIntegrity := hm.containingBuild.integrityVerification != utils.IntegrityVerificationOff
+	indexPackages := uncachedPackages
+	if verifyIntegrity {
+		indexPackages = maps.Values(packagesById)
+	}
+	if len(indexPackages) == 0 {
+		return dependenciesMap, nil
+	}
+	indexPaths, err := buildutils.GetHackageIndexPaths(hm.tool)
+	if err != nil {
+		return nil, err
+	}
+	if len(indexPaths) == 0 {
+		hm.containingBuild.logger.Debug("No Hackage index was found. The checksums of the packages which aren't cached are unknown.")
+		return dependenciesMap, nil
+	}
+	indexChecksums, err := buildutils.ReadHackageIndexChecksums(indexPaths[0], indexPackages)
+	if err != nil {
+		return nil, err
+	}
+	var mismatches []utils.IntegrityMismatchDetails
+	indexedIds := maps.Keys(indexChecksums)
+	sort.Strings(indexedIds)
+	for _, id := range indexedIds {
+		checksums := indexChecksums[id]
+		dependency := dependenciesMap[id]
+		if dependency.GetResolutionSource() != entities.CacheSource {
+			dependency.Checksum = entities.Checksum{Md5: checksums.Md5, Sha256: checksums.Sha256}
+			dependency.SetResolutionSource(entities.CacheSource)
+			dependenciesMap[id] = dependency
+		} else if verifyIntegrity && checksums.Sha256 != "" && checksums.Sha256 != dependency.Sha256 {
+			mismatches = append(mismatches, utils.IntegrityMismatchDetails{DependencyId: id, Lockfile: buildutils.HackageIndexFileName, Expected: "sha256:" + checksums.Sha256, Actual: "sha256:" + dependency.Sha256})
+		}
+	}
+	if err = hm.containingBuild.integrityVerification.HandleMismatches(mismatches, hm.containingBuild.logger); err != nil {
+		return nil, err
+	}
+	return dependenciesMap, nil
+}
+
+// The directories in which 'cabal sdist' and 'stack sdist' write the source distributions of the project's package.
+var sdistPatterns = []string{
+	filepath.Join("dist-newstyle", "sdist"),
+	filepath.Join(".stack-work", "dist", "*", "*"),
+}
+
+// Returns the source distributions of the project's package, as the module's artifacts.
+// Their paths are the packages' paths on Hackage, so that they can be deployed to a generic repository which mirrors its layout.
+func (hm *HaskellModule) getSdistArtifacts(description *buildutils.HaskellPackageDescription) ([]entities.Artifact, error) {
+	projectPackage := &buildutils.HaskellPackage{Name: description.Name, Version: description.Version}
+	var artifacts []entities.Artifact
+	for _, pattern := range sdistPatterns {
+		sdistPaths, err := filepath.Glob(filepath.Join(hm.srcPath, pattern, projectPackage.FileName()))
+		if err != nil {
+			return nil, err
+		}
+		for _, sdistPath := range sdistPaths {
+			checksum, size, err := hm.containingBuild.getArtifactFileDetails(sdistPath)
+			if err != nil {
+				return nil, err
+			}
+			hm.containingBuild.logger.Debug("Adding the source distribution", sdistPath, "to the artifacts of", projectPackage.Id())
+			artifacts = append(artifacts, entities.Artifact{
+				Name:     projectPackage.FileName(),
+				Type:     "tar.gz",
+				Path:     "package/" + projectPackage.Name + "-" + projectPackage.Version + "/" + projectPackage.FileName(),
+				Size:     size,
+				Checksum: checksum,
+			})
+		}
+	}
+	return artifacts, nil
+}
+
+func (hm *HaskellModule) AddArtifacts(artifacts ...entities.Artifact) error {
+	return hm.containingBuild.AddArtifacts(hm.name, entities.Hackage, artifacts...)
+}
@@ -0,0 +1,144 @@
+package build
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCabalFile = `cabal-version: 3.0
+name:          my-app
+version:       0.1.0
+
+library
+    build-depends: base, aeson
+
+test-suite my-app-test
+    build-depends: base, my-app, hspec
+`
+
+const testCabalFreeze = `constraints: any.aeson ==2.1.2.1,
+             any.base ==4.17.2.0,
+             any.hspec ==2.11.7,
+             any.text ==2.0.2
+`
+
+func TestGenerateBuildInfoForCabalProject(t *testing.T) {
+	projectDir, cabalDir := createCabalProject(t, "")
+	aesonChecksums, err := crypto.GetFileChecksums(filepath.Join(cabalDir, "packages", buildutils.HackageRepository, "aeson", "2.1.2.1", "aeson-2.1.2.1.tar.gz"))
+	require.NoError(t, err)
+	sdistChecksums, err := crypto.GetFileChecksums(filepath.Join(projectDir, "dist-newstyle", "sdist", "my-app-0.1.0.tar.gz"))
+	require.NoError(t, err)
+
+	haskellBuild := createTestBuild(t, "build-info-go-test-haskell")
+	haskellBuild.SetResolutionAudit(true)
+	haskellBuild.SetIntegrityVerification(utils.IntegrityVerificationFail)
+	haskellModule, err := haskellBuild.AddHaskellModule(projectDir)
+	require.NoError(t, err)
+	require.NoError(t, haskellModule.CalcDependencies())
+	buildInfo, err := haskellBuild.ToBuildInfo()
+	require.NoError(t, err)
+
+	require.Len(t, buildInfo.Modules, 1)
+	module := buildInfo.Modules[0]
+	assert.Equal(t, "my-app:0.1.0", module.Id)
+	assert.Equal(t, entities.Hackage, module.Type)
+	assert.Equal(t, []entities.Artifact{{
+		Name:     "my-app-0.1.0.tar.gz",
+		Type:     "tar.gz",
+		Path:     "package/my-app-0.1.0/my-app-0.1.0.tar.gz",
+		Size:     int64(len("my-app")),
+		Checksum: entities.Checksum{Sha1: sdistChecksums[crypto.SHA1], Md5: sdistChecksums[crypto.MD5], Sha256: sdistChecksums[crypto.SHA256]},
+	}}, module.Artifacts)
+
+	dependencies := make(map[string]entities.Dependency)
+	for _, dependency := range module.Dependencies {
+		dependencies[dependency.Id] = dependency
+	}
+	assert.Len(t, dependencies, 4)
+	assert.Equal(t, entities.Dependency{
+		Id:          "aeson:2.1.2.1",
+		Type:        "tar.gz",
+		RequestedBy: [][]string{{"my-app:0.1.0"}},
+		Purl:        "pkg:hackage/aeson@2.1.2.1",
+		Properties:  map[string]string{entities.ResolutionSourceProperty: string(entities.CacheSource)},
+		Checksum:    entities.Checksum{Sha1: aesonChecksums[crypto.SHA1], Md5: aesonChecksums[crypto.MD5], Sha256: aesonChecksums[crypto.SHA256]},
+	}, dependencies["aeson:2.1.2.1"])
+	// The dependencies of the installed aeson package are found in the store's package database.
+	assert.Equal(t, [][]string{{"aeson:2.1.2.1", "my-app:0.1.0"}}, dependencies["text:2.0.2"].RequestedBy)
+	// The checksums of a package which isn't cached are taken from the Hackage index.
+	assert.Equal(t, entities.Checksum{Md5: strings.Repeat("c", 32), Sha256: strings.Repeat("d", 64)}, dependencies["text:2.0.2"].Checksum)
+	assert.Equal(t, string(entities.CacheSource), dependencies["text:2.0.2"].Properties[entities.ResolutionSourceProperty])
+	// A package which is neither cached nor installed is known from the freeze file only.
+	assert.Equal(t, string(entities.LockfileSource), dependencies["hspec:2.11.7"].Properties[entities.ResolutionSourceProperty])
+	assert.Empty(t, dependencies["hspec:2.11.7"].Checksum)
+}
+
+func TestHaskellIntegrityMismatch(t *testing.T) {
+	projectDir, _ := createCabalProject(t, strings.Repeat("b", 64))
+	haskellBuild := createTestBuild(t, "build-info-go-test-haskell-integrity")
+	haskellBuild.SetIntegrityVerification(utils.IntegrityVerificationFail)
+	haskellModule, err := haskellBuild.AddHaskellModule(projectDir)
+	require.NoError(t, err)
+	assert.ErrorContains(t, haskellModule.CalcDependencies(), "aeson:2.1.2.1")
+
+	// Without integrity verification, the checksums of the cached package are recorded.
+	haskellBuild.SetIntegrityVerification(utils.IntegrityVerificationOff)
+	assert.NoError(t, haskellModule.CalcDependencies())
+}
+
+// Creates a Cabal project, whose source distribution is built into dist-newstyle/sdist, and a Cabal directory in which aeson is cached and installed,
+// and whose Hackage index has the checksums of aeson and text. If aesonSha256 is empty, the index has the checksum of the cached aeson package.
+func createCabalProject(t *testing.T, aesonSha256 string) (projectDir, cabalDir string) {
+	projectDir = t.TempDir()
+	cabalDir = t.TempDir()
+	t.Setenv("CABAL_DIR", cabalDir)
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "my-app.cabal"), []byte(testCabalFile), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, buildutils.CabalFreezeFileName), []byte(testCabalFreeze), 0644))
+	sdistDir := filepath.Join(projectDir, "dist-newstyle", "sdist")
+	require.NoError(t, os.MkdirAll(sdistDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sdistDir, "my-app-0.1.0.tar.gz"), []byte("my-app"), 0644))
+
+	packagesDir := filepath.Join(cabalDir, "packages", buildutils.HackageRepository)
+	aesonPath := filepath.Join(packagesDir, "aeson", "2.1.2.1", "aeson-2.1.2.1.tar.gz")
+	require.NoError(t, os.MkdirAll(filepath.Dir(aesonPath), 0755))
+	require.NoError(t, os.WriteFile(aesonPath, []byte("aeson"), 0644))
+	if aesonSha256 == "" {
+		checksums, err := crypto.GetFileChecksums(aesonPath)
+		require.NoError(t, err)
+		aesonSha256 = checksums[crypto.SHA256]
+	}
+	writeTestHackageIndex(t, filepath.Join(packagesDir, buildutils.HackageIndexFileName), map[string]string{
+		"aeson/2.1.2.1/package.json": `{"signed":{"targets":{"<repo>/package/aeson-2.1.2.1.tar.gz":{"hashes":{"sha256":"` + aesonSha256 + `"}}}}}`,
+		"text/2.0.2/package.json":    `{"signed":{"targets":{"<repo>/package/text-2.0.2.tar.gz":{"hashes":{"md5":"` + strings.Repeat("c", 32) + `","sha256":"` + strings.Repeat("d", 64) + `"}}}}}`,
+	})
+
+	dbDir := filepath.Join(cabalDir, "store", "ghc-9.4.7", "package.db")
+	require.NoError(t, os.MkdirAll(dbDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dbDir, "aeson-2.1.2.1-3f9b0c1d.conf"), []byte("name: aeson\nversion: 2.1.2.1\nid: aeson-2.1.2.1-3f9b0c1d\ndepends:\n    base-4.17.2.0 text-2.0.2\n"), 0644))
+	return
+}
+
+func writeTestHackageIndex(t *testing.T, indexPath string, files map[string]string) {
+	indexFile, err := os.Create(indexPath)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, indexFile.Close())
+	}()
+	writer := tar.NewWriter(indexFile)
+	for name, content := range files {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+}
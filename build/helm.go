@@ -0,0 +1,187 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+)
+
+type HelmModule struct {
+	containingBuild *Build
+	name            string
+	srcPath         string
+	// The arguments of the helm command which fetches the dependencies into the chart's charts directory before they're collected.
+	helmArgs []string
+	// The duration of the command, if it ran.
+	commandDuration time.Duration
+}
+
+// Pass an empty string for srcPath to find the chart in the working directory.
+func newHelmModule(srcPath string, containingBuild *Build) (*HelmModule, error) {
+	if srcPath == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		srcPath, err = utils.FindFileInDirAndParents(wd, buildutils.HelmChartFileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &HelmModule{srcPath: srcPath, containingBuild: containingBuild, helmArgs: []string{"dependency", "build"}}, nil
+}
+
+func (hm *HelmModule) SetName(name string) {
+	hm.name = name
+}
+
+// SetHelmArgs sets the arguments of the helm command which runs by Build. The default is: dependency build
+// Set them to 'dependency update' to resolve the dependencies again, and update the Chart.lock.
+func (hm *HelmModule) SetHelmArgs(helmArgs []string) {
+	hm.helmArgs = helmArgs
+}
+
+// Build runs the helm command set by SetHelmArgs in the chart's directory, and then collects the chart's dependencies.
+func (hm *HelmModule) Build() error {
+	if len(hm.helmArgs) > 0 {
+		command := exec.Command("helm", hm.helmArgs...)
+		command.Dir = hm.srcPath
+		var err error
+		if hm.commandDuration, err = runModuleCommand(command); err != nil {
+			return fmt.Errorf("failed running 'helm %s': %w", strings.Join(hm.helmArgs, " "), err)
+		}
+	}
+	return hm.CalcDependencies()
+}
+
+// CalcDependencies collects the dependencies pinned in the chart's Chart.lock, without running Helm.
+// The checksums of the dependencies are calculated from their archives in the chart's charts directory.
+// The dependencies of the dependencies are packaged in their archives, so only the chart's direct dependencies are collected.
+func (hm *HelmModule) CalcDependencies() error {
+	if !hm.containingBuild.buildNameAndNumberProvided() {
+		return errors.New("a build name must be provided in order to collect the project's dependencies")
+	}
+	chart, err := buildutils.ReadHelmChart(hm.srcPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("no Chart.yaml was found in " + hm.srcPath)
+		}
+		return err
+	}
+	hm.setModuleId(chart)
+	var dependencies []entities.Dependency
+	if len(chart.Dependencies) > 0 {
+		if dependencies, err = hm.getDependencies(); err != nil {
+			return err
+		}
+	}
+	buildInfoModule := entities.Module{Id: hm.name, Type: entities.Helm, Dependencies: dependencies}
+	addCommandDuration(&buildInfoModule, hm.commandDuration)
+	return hm.containingBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{buildInfoModule}})
+}
+
+// If the module ID wasn't set, it's the name and the version of the chart, or the name of the chart's directory.
+func (hm *HelmModule) setModuleId(chart *buildutils.HelmChart) {
+	if hm.name != "" {
+		return
+	}
+	if chart.Name != "" {
+		hm.name = chart.Name
+		if chart.Version != "" {
+			hm.name += ":" + chart.Version
+		}
+		return
+	}
+	hm.name = filepath.Base(hm.srcPath)
+	hm.containingBuild.logger.Debug(fmt.Sprintf("The Chart.yaml doesn't declare the chart's name. Using its directory name: %s as the module name.", hm.name))
+}
+
+func (hm *HelmModule) getDependencies() ([]entities.Dependency, error) {
+	lock, err := buildutils.ReadHelmLock(hm.srcPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("no Chart.lock was found in " + hm.srcPath + ". Run 'helm dependency update' before collecting the dependencies")
+		}
+		return nil, err
+	}
+	var dependencies []entities.Dependency
+	for _, helmDependency := range lock.Dependencies {
+		dependency := entities.Dependency{
+			Id:               helmDependency.Id(),
+			RemoteRepository: helmDependency.GetRepositoryUrl(),
+			RequestedBy:      [][]string{{hm.name}},
+		}
+		dependency.SetResolutionSource(entities.LockfileSource)
+		if helmDependency.IsLocal() {
+			dependency.SetResolutionSource(entities.FilesystemSource)
+			dependency.SetMutable()
+		}
+		archivePath := helmDependency.GetArchivePath(hm.srcPath)
+		exists, err := utils.IsFileExists(archivePath, true)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			checksums, err := hm.containingBuild.checksumCache.GetFileChecksums(archivePath)
+			if err != nil {
+				return nil, err
+			}
+			dependency.Checksum = entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}
+		} else {
+			hm.containingBuild.logger.Debug("The archive of the Helm dependency", dependency.Id, "wasn't found in the charts directory. Run 'helm dependency build' to fetch it.")
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, nil
+}
+
+func (hm *HelmModule) AddArtifacts(artifacts ...entities.Artifact) error {
+	return hm.containingBuild.AddArtifacts(hm.name, entities.Helm, artifacts...)
+}
+
+// RunHelmDependencyBuildWithBuildInfo runs 'helm dependency build' in the chart's working directory, and then collects the chart's dependencies
+// into the build-info of the provided build. The build-info is saved in the temp directory of the build service, where it can be published by the JFrog CLI.
+func RunHelmDependencyBuildWithBuildInfo(workingDir, buildName, buildNumber string) error {
+	_, err := collectIntoBuild(buildName, buildNumber, collectHelm(workingDir, []string{"dependency", "build"}))
+	return err
+}
+
+// RunHelmDependencyUpdateWithBuildInfo is like RunHelmDependencyBuildWithBuildInfo, but runs 'helm dependency update',
+// which resolves the dependencies again and updates the Chart.lock.
+func RunHelmDependencyUpdateWithBuildInfo(workingDir, buildName, buildNumber string) error {
+	_, err := collectIntoBuild(buildName, buildNumber, collectHelm(workingDir, []string{"dependency", "update"}))
+	return err
+}
+
+// RunHelmDependencyBuildAndGetBuildInfo is like RunHelmDependencyBuildWithBuildInfo, but also returns the build-info of the build,
+// and the directory in which its files are saved. See RunAndGetBuildInfo.
+func RunHelmDependencyBuildAndGetBuildInfo(workingDir, buildName, buildNumber string) (*entities.BuildInfo, string, error) {
+	return RunAndGetBuildInfo(buildName, buildNumber, collectHelm(workingDir, []string{"dependency", "build"}))
+}
+
+// RunHelmDependencyUpdateAndGetBuildInfo is like RunHelmDependencyUpdateWithBuildInfo, but also returns the build-info of the build,
+// and the directory in which its files are saved. See RunAndGetBuildInfo.
+func RunHelmDependencyUpdateAndGetBuildInfo(workingDir, buildName, buildNumber string) (*entities.BuildInfo, string, error) {
+	return RunAndGetBuildInfo(buildName, buildNumber, collectHelm(workingDir, []string{"dependency", "update"}))
+}
+
+// Returns a collection which runs helm with the provided arguments in the chart's directory, and collects the chart's dependencies.
+func collectHelm(workingDir string, helmArgs []string) func(*Build) error {
+	return func(helmBuild *Build) error {
+		helmModule, err := helmBuild.AddHelmModule(workingDir)
+		if err != nil {
+			return err
+		}
+		helmModule.SetHelmArgs(helmArgs)
+		return helmModule.Build()
+	}
+}
@@ -0,0 +1,97 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateBuildInfoForHelmChart(t *testing.T) {
+	chartDir := t.TempDir()
+	writeHelmChartFile(t, chartDir, "Chart.yaml", `apiVersion: v2
+name: app
+version: 1.2.3
+dependencies:
+  - name: redis
+    version: ~17.3.0
+    repository: https://charts.bitnami.com/bitnami
+  - name: common
+    version: 0.1.0
+    repository: file://../common
+  - name: postgresql
+    version: 12.1.6
+    repository: oci://registry-1.docker.io/bitnamicharts
+`)
+	writeHelmChartFile(t, chartDir, "Chart.lock", `dependencies:
+- name: redis
+  repository: https://charts.bitnami.com/bitnami
+  version: 17.3.14
+- name: common
+  repository: file://../common
+  version: 0.1.0
+- name: postgresql
+  repository: oci://registry-1.docker.io/bitnamicharts
+  version: 12.1.6
+digest: sha256:5b2f0bd6c2d3d4e0
+`)
+	// The archive of postgresql wasn't fetched.
+	writeHelmChartFile(t, chartDir, filepath.Join("charts", "redis-17.3.14.tgz"), "redis")
+	writeHelmChartFile(t, chartDir, filepath.Join("charts", "common-0.1.0.tgz"), "common")
+
+	helmBuild := createTestBuild(t, "build-info-go-test-helm")
+	helmModule, err := helmBuild.AddHelmModule(chartDir)
+	require.NoError(t, err)
+	require.NoError(t, helmModule.CalcDependencies())
+	buildInfo, err := helmBuild.ToBuildInfo()
+	require.NoError(t, err)
+
+	require.Len(t, buildInfo.Modules, 1)
+	module := buildInfo.Modules[0]
+	assert.Equal(t, "app:1.2.3", module.Id)
+	assert.Equal(t, entities.Helm, module.Type)
+	require.Len(t, module.Dependencies, 3)
+	dependencies := make(map[string]entities.Dependency)
+	for _, dependency := range module.Dependencies {
+		dependencies[dependency.Id] = dependency
+		assert.Equal(t, [][]string{{"app:1.2.3"}}, dependency.RequestedBy)
+	}
+
+	redis := dependencies["redis:17.3.14"]
+	assert.Equal(t, "https://charts.bitnami.com/bitnami", redis.RemoteRepository)
+	assert.Equal(t, "34fb46c847bb9df96e5205a39d382f648a6e8dce1e014cd85b4ca6a88d88ed03", redis.Sha256)
+	assert.NotEmpty(t, redis.Sha1)
+	assert.False(t, redis.IsMutable())
+
+	common := dependencies["common:0.1.0"]
+	assert.Empty(t, common.RemoteRepository)
+	assert.NotEmpty(t, common.Sha256)
+	assert.True(t, common.IsMutable())
+
+	postgresql := dependencies["postgresql:12.1.6"]
+	assert.Equal(t, "oci://registry-1.docker.io/bitnamicharts", postgresql.RemoteRepository)
+	assert.True(t, postgresql.Checksum.IsEmpty())
+}
+
+func TestHelmChartWithoutLock(t *testing.T) {
+	helmBuild := createTestBuild(t, "build-info-go-test-helm-no-lock")
+
+	// A chart without dependencies doesn't need a Chart.lock.
+	chartDir := t.TempDir()
+	writeHelmChartFile(t, chartDir, "Chart.yaml", "apiVersion: v2\nname: library\nversion: 0.1.0\n")
+	helmModule, err := helmBuild.AddHelmModule(chartDir)
+	require.NoError(t, err)
+	require.NoError(t, helmModule.CalcDependencies())
+
+	writeHelmChartFile(t, chartDir, "Chart.yaml", "apiVersion: v2\nname: library\nversion: 0.1.0\ndependencies:\n  - name: redis\n    version: 17.3.14\n")
+	assert.ErrorContains(t, helmModule.CalcDependencies(), "no Chart.lock was found")
+}
+
+func writeHelmChartFile(t *testing.T, chartDir, path, content string) {
+	path = filepath.Join(chartDir, path)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
@@ -0,0 +1,256 @@
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/slices"
+)
+
+const IncrementalCachePath = "jfrog/build-info-cache/"
+
+// The manifests and lockfiles which determine the dependencies of each project technology.
+// If none of these files changed since the last collection, the project's dependencies are considered unchanged.
+var fingerprintInputFiles = map[ProjectTechnology][]string{
+	GoTechnology:      {"go.mod", "go.sum"},
+	MavenTechnology:   {"pom.xml"},
+	GradleTechnology:  {"settings.gradle", "settings.gradle.kts", "build.gradle", "build.gradle.kts", "gradle.properties", "gradle.lockfile", "libs.versions.toml"},
+	NpmTechnology:     {"package.json", "package-lock.json", "npm-shrinkwrap.json", ".npmrc"},
+	YarnTechnology:    {"package.json", "yarn.lock", ".yarnrc.yml"},
+	PythonTechnology:  {"pyproject.toml", "poetry.lock", "setup.py", "requirements.txt", "Pipfile", "Pipfile.lock"},
+	HelmTechnology:    {"Chart.yaml", "Chart.lock"},
+	BundlerTechnology: {"Gemfile", "Gemfile.lock"},
+	MixTechnology:     {"mix.exs", "mix.lock"},
+	HaskellTechnology: {"stack.yaml", "stack.yaml.lock", "cabal.project", "cabal.project.freeze", "package.yaml"},
+	ZigTechnology:     {"build.zig.zon"},
+	CMakeTechnology:   {"CMakeLists.txt", "CMakePresets.json", "CMakeUserPresets.json"},
+	VcpkgTechnology:   {"vcpkg.json", "vcpkg-lock.json", "vcpkg-configuration.json"},
+}
+
+// IncrementalInputs are the inputs of a collection which determine the collected dependencies, in addition to the project's manifests and lockfiles.
+// If any of them changes, the project's dependencies are collected again rather than read from the incremental cache.
+type IncrementalInputs struct {
+	// The options and arguments of the collector, for example: the profiles and system properties passed to Maven.
+	Options []string
+	// The files which the collector reads, other than the project's manifests and lockfiles, for example: Maven's settings files.
+	// A file which doesn't exist is fingerprinted as missing, so creating it triggers a new collection.
+	Files []string
+}
+
+// The content of a cache entry, saved after collecting the dependencies of a project.
+type incrementalCacheEntry struct {
+	Fingerprint string            `json:"fingerprint,omitempty"`
+	Modules     []entities.Module `json:"modules,omitempty"`
+}
+
+// SetIncrementalCacheDir enables the incremental collection, using the provided directory to store the collected modules between runs.
+// Pass an empty string to disable it.
+// This field is not saved in local cache.
+func (b *Build) SetIncrementalCacheDir(incrementalCacheDir string) {
+	b.incrementalCacheDir = incrementalCacheDir
+}
+
+// CollectIncrementally collects the dependencies of the project in srcPath by calling the collect function,
+// unless the project's manifests and lockfiles haven't changed since the last collection. In that case, the modules saved in
+// the incremental cache are added to this build instead.
+// The collect function should add its modules to the provided containing build, rather than to this build.
+// If the incremental cache directory isn't set, collect is called with a copy of this build, which saves the modules in this build.
+// The duration of the collection is added to the properties of the collected modules. See CollectDurationProperty.
+// Pass srcPath as an empty string if the root of the project is the working directory.
+// To collect again when the options of the collector change, use CollectIncrementallyWithInputs.
+func (b *Build) CollectIncrementally(srcPath string, technology ProjectTechnology, collect func(containingBuild *Build) error) error {
+	return b.CollectIncrementallyWithInputs(srcPath, technology, IncrementalInputs{}, collect)
+}
+
+// CollectIncrementallyWithInputs is like CollectIncrementally, but the project's dependencies are collected again
+// if the provided inputs changed since the last collection, as well as if its manifests and lockfiles changed.
+func (b *Build) CollectIncrementallyWithInputs(srcPath string, technology ProjectTechnology, inputs IncrementalInputs, collect func(containingBuild *Build) error) (err error) {
+	collectionStarted := time.Now()
+	if b.incrementalCacheDir == "" {
+		return collect(b.withCollectionStarted(collectionStarted))
+	}
+	if srcPath == "" {
+		if srcPath, err = os.Getwd(); err != nil {
+			return
+		}
+	}
+	if srcPath, err = filepath.Abs(srcPath); err != nil {
+		return
+	}
+	fingerprint, err := calcInputsFingerprint(srcPath, fingerprintInputFiles[technology])
+	if err != nil {
+		return
+	}
+	if fingerprint == "" {
+		b.logger.Debug("No manifests or lockfiles were found in", srcPath+". Skipping the incremental cache.")
+		return collect(b.withCollectionStarted(collectionStarted))
+	}
+	if fingerprint, err = b.addInputsToFingerprint(fingerprint, inputs); err != nil {
+		return
+	}
+	cacheEntryPath := filepath.Join(b.incrementalCacheDir, getIncrementalCacheKey(srcPath, technology)+".json")
+	cacheEntry, err := readIncrementalCacheEntry(cacheEntryPath)
+	if err != nil {
+		return
+	}
+	if cacheEntry != nil && cacheEntry.Fingerprint == fingerprint {
+		b.logger.Info("The", technology, "project at", srcPath, "hasn't changed since the last collection. Using the cached dependencies.")
+		addCollectDuration(cacheEntry.Modules, collectionStarted)
+		b.statsCounter.addIncrementalCacheHit()
+		return b.SaveBuildInfo(&entities.BuildInfo{Modules: cacheEntry.Modules})
+	}
+
+	// Collect into a separate build, so that only the modules of this project are saved in the cache.
+	scratchDir, err := utils.CreateTempDir()
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, utils.RemoveTempDir(scratchDir))
+	}()
+	// The scratch build has all the collection settings of this build, including its collection listener and its stats, which receive the modules as they're collected.
+	scratchBuild := *b
+	scratchBuild.tempDirPath = scratchDir
+	scratchBuild.incrementalCacheDir = ""
+	scratchBuild.collectionStarted = collectionStarted
+	if err = collect(&scratchBuild); err != nil {
+		return
+	}
+	generatedBuildsInfo, err := scratchBuild.getGeneratedBuildsInfo()
+	if err != nil {
+		return
+	}
+	collectedBuildInfo := &entities.BuildInfo{}
+	for _, generatedBuildInfo := range generatedBuildsInfo {
+		collectedBuildInfo.Append(generatedBuildInfo)
+	}
+	// The listener and the stats already received the modules from the scratch build.
+	if err = b.saveBuildInfo(collectedBuildInfo, false); err != nil {
+		return
+	}
+	return writeIncrementalCacheEntry(cacheEntryPath, &incrementalCacheEntry{Fingerprint: fingerprint, Modules: collectedBuildInfo.Modules})
+}
+
+// Returns a copy of this build, which adds the duration since collectionStarted to the properties of the modules it saves.
+// The copy saves the modules in this build's directory, so they're part of this build.
+func (b *Build) withCollectionStarted(collectionStarted time.Time) *Build {
+	timedBuild := *b
+	timedBuild.collectionStarted = collectionStarted
+	return &timedBuild
+}
+
+// Calculates a fingerprint of the input files found in the project, including the input files of its submodules.
+// Returns an empty string if no input files were found.
+func calcInputsFingerprint(srcPath string, inputFileNames []string) (string, error) {
+	var inputFiles []string
+	err := filepath.WalkDir(srcPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != srcPath && (slices.Contains(workspaceExcludedDirs, entry.Name()) || entry.Name()[0] == '.') {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if slices.Contains(inputFileNames, entry.Name()) {
+			inputFiles = append(inputFiles, path)
+		}
+		return nil
+	})
+	if err != nil || len(inputFiles) == 0 {
+		return "", err
+	}
+	sort.Strings(inputFiles)
+	hash := sha256.New()
+	for _, inputFile := range inputFiles {
+		content, err := os.ReadFile(inputFile)
+		if err != nil {
+			return "", err
+		}
+		relativePath, err := filepath.Rel(srcPath, inputFile)
+		if err != nil {
+			return "", err
+		}
+		fileHash := sha256.Sum256(content)
+		hash.Write([]byte(filepath.ToSlash(relativePath) + ":" + hex.EncodeToString(fileHash[:]) + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Adds the inputs of the collection, and the settings of this build which determine the collected dependencies, to the fingerprint of the project's input files.
+func (b *Build) addInputsToFingerprint(fingerprint string, inputs IncrementalInputs) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(fingerprint + "\n"))
+	hash.Write([]byte("integrity:" + string(b.integrityVerification) + "\n"))
+	hash.Write([]byte("cache-timestamps:" + strconv.FormatBool(b.recordCacheTimestamps) + "\n"))
+	hash.Write([]byte("sub-artifacts:" + strings.Join(b.subArtifactsPatterns, ",") + "\n"))
+	for _, option := range inputs.Options {
+		hash.Write([]byte("option:" + option + "\n"))
+	}
+	for _, inputFile := range inputs.Files {
+		content, err := os.ReadFile(inputFile)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+			hash.Write([]byte("file:" + inputFile + ":missing\n"))
+			continue
+		}
+		fileHash := sha256.Sum256(content)
+		hash.Write([]byte("file:" + inputFile + ":" + hex.EncodeToString(fileHash[:]) + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func getIncrementalCacheKey(srcPath string, technology ProjectTechnology) string {
+	hash := sha256.Sum256([]byte(string(technology) + "_" + srcPath))
+	return hex.EncodeToString(hash[:])
+}
+
+func readIncrementalCacheEntry(cacheEntryPath string) (*incrementalCacheEntry, error) {
+	exists, err := utils.IsFileExists(cacheEntryPath, false)
+	if err != nil || !exists {
+		return nil, err
+	}
+	content, err := os.ReadFile(cacheEntryPath)
+	if err != nil {
+		return nil, err
+	}
+	cacheEntry := new(incrementalCacheEntry)
+	if err = json.Unmarshal(content, cacheEntry); err != nil {
+		// A corrupted cache entry is treated as a cache miss, and overridden after the collection.
+		return nil, nil
+	}
+	return cacheEntry, nil
+}
+
+func writeIncrementalCacheEntry(cacheEntryPath string, cacheEntry *incrementalCacheEntry) (err error) {
+	content, err := json.Marshal(cacheEntry)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(cacheEntryPath), 0777); err != nil {
+		return err
+	}
+	// The cache is shared by the builds of the project, which may run concurrently.
+	unlock, err := utils.LockFile(cacheEntryPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, unlock())
+	}()
+	return utils.WriteFileAtomically(cacheEntryPath, content, 0600)
+}
@@ -0,0 +1,145 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectIncrementally(t *testing.T) {
+	projectPath := t.TempDir()
+	goModPath := filepath.Join(projectPath, "go.mod")
+	require.NoError(t, os.WriteFile(goModPath, []byte("module example.com/project\n"), 0644))
+	cacheDir := t.TempDir()
+
+	collectCount := 0
+	collect := func(containingBuild *Build) error {
+		collectCount++
+		module := entities.Module{Id: "example.com/project", Type: entities.Go, Dependencies: []entities.Dependency{{Id: "dep:1.0.0"}}}
+		return containingBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{module}})
+	}
+	runCollection := func() *entities.BuildInfo {
+		bld := NewBuild("incremental-build", "1", time.Now(), "", t.TempDir(), &utils.NullLog{})
+		bld.SetIncrementalCacheDir(cacheDir)
+		require.NoError(t, bld.CollectIncrementally(projectPath, GoTechnology, collect))
+		generatedBuildsInfo, err := bld.getGeneratedBuildsInfo()
+		require.NoError(t, err)
+		require.Len(t, generatedBuildsInfo, 1)
+		return generatedBuildsInfo[0]
+	}
+
+	// The first collection populates the cache.
+	buildInfo := runCollection()
+	assert.Equal(t, 1, collectCount)
+	require.Len(t, buildInfo.Modules, 1)
+	assert.Equal(t, "example.com/project", buildInfo.Modules[0].Id)
+
+	// The inputs haven't changed, so the cached modules are used.
+	buildInfo = runCollection()
+	assert.Equal(t, 1, collectCount)
+	require.Len(t, buildInfo.Modules, 1)
+	assert.Len(t, buildInfo.Modules[0].Dependencies, 1)
+
+	// Changing a manifest triggers a new collection.
+	require.NoError(t, os.WriteFile(goModPath, []byte("module example.com/project\n\nrequire dep v1.0.0\n"), 0644))
+	runCollection()
+	assert.Equal(t, 2, collectCount)
+}
+
+func TestCalcInputsFingerprint(t *testing.T) {
+	projectPath := t.TempDir()
+	fingerprint, err := calcInputsFingerprint(projectPath, fingerprintInputFiles[MavenTechnology])
+	assert.NoError(t, err)
+	assert.Empty(t, fingerprint)
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "pom.xml"), []byte("<project/>"), 0644))
+	fingerprint, err = calcInputsFingerprint(projectPath, fingerprintInputFiles[MavenTechnology])
+	assert.NoError(t, err)
+	assert.NotEmpty(t, fingerprint)
+
+	// Files inside excluded directories don't affect the fingerprint.
+	require.NoError(t, os.MkdirAll(filepath.Join(projectPath, "target"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "target", "pom.xml"), []byte("<generated/>"), 0644))
+	targetFingerprint, err := calcInputsFingerprint(projectPath, fingerprintInputFiles[MavenTechnology])
+	assert.NoError(t, err)
+	assert.Equal(t, fingerprint, targetFingerprint)
+
+	// Submodules' manifests do.
+	require.NoError(t, os.MkdirAll(filepath.Join(projectPath, "core"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "core", "pom.xml"), []byte("<project/>"), 0644))
+	submoduleFingerprint, err := calcInputsFingerprint(projectPath, fingerprintInputFiles[MavenTechnology])
+	assert.NoError(t, err)
+	assert.NotEqual(t, fingerprint, submoduleFingerprint)
+}
+
+func TestCollectIncrementallyWithInputs(t *testing.T) {
+	projectPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "pom.xml"), []byte("<project/>"), 0644))
+	settingsPath := filepath.Join(t.TempDir(), "settings.xml")
+	cacheDir := t.TempDir()
+
+	collectCount := 0
+	collect := func(containingBuild *Build) error {
+		collectCount++
+		module := entities.Module{Id: "org.example:app:1.0.0", Type: entities.Maven, Dependencies: []entities.Dependency{{Id: "org.example:dep:1.0.0"}}}
+		return containingBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{module}})
+	}
+	var events []CollectionEvent
+	runCollection := func(integrityVerification utils.IntegrityVerificationMode, options ...string) {
+		bld := NewBuild("incremental-inputs-build", "1", time.Now(), "", t.TempDir(), &utils.NullLog{})
+		bld.SetIncrementalCacheDir(cacheDir)
+		bld.SetIntegrityVerification(integrityVerification)
+		bld.SetCollectionListener(func(event CollectionEvent) error {
+			events = append(events, event)
+			return nil
+		})
+		require.NoError(t, bld.CollectIncrementallyWithInputs(projectPath, MavenTechnology, IncrementalInputs{Options: options, Files: []string{settingsPath}}, collect))
+		// The modules of a cache miss are counted and sent to the listener once, by the scratch build.
+		assert.Equal(t, 1, bld.CollectionStats().Dependencies)
+	}
+
+	runCollection(utils.IntegrityVerificationOff, "-P", "dev")
+	assert.Equal(t, 1, collectCount)
+	assert.Len(t, events, 2)
+	runCollection(utils.IntegrityVerificationOff, "-P", "dev")
+	assert.Equal(t, 1, collectCount)
+
+	// Changing an option, creating an input file, or changing the build's collection settings, triggers a new collection.
+	runCollection(utils.IntegrityVerificationOff, "-P", "prod")
+	assert.Equal(t, 2, collectCount)
+	require.NoError(t, os.WriteFile(settingsPath, []byte("<settings/>"), 0644))
+	runCollection(utils.IntegrityVerificationOff, "-P", "prod")
+	assert.Equal(t, 3, collectCount)
+	runCollection(utils.IntegrityVerificationFail, "-P", "prod")
+	assert.Equal(t, 4, collectCount)
+	runCollection(utils.IntegrityVerificationFail, "-P", "prod")
+	assert.Equal(t, 4, collectCount)
+}
+
+func TestCollectIncrementallyScratchBuildSettings(t *testing.T) {
+	projectPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "go.mod"), []byte("module example.com/project\n"), 0644))
+	bld := NewBuild("incremental-settings-build", "1", time.Now(), "", t.TempDir(), &utils.NullLog{})
+	bld.SetIncrementalCacheDir(t.TempDir())
+	bld.SetSubArtifactsPatterns("*.jar")
+	bld.SetRecordCacheTimestamps(true)
+	checksumCache, err := utils.NewChecksumCache(t.TempDir(), 0)
+	require.NoError(t, err)
+	bld.SetChecksumCache(checksumCache)
+	err = bld.CollectIncrementally(projectPath, GoTechnology, func(containingBuild *Build) error {
+		assert.NotEqual(t, bld.tempDirPath, containingBuild.tempDirPath)
+		assert.Empty(t, containingBuild.incrementalCacheDir)
+		assert.Equal(t, []string{"*.jar"}, containingBuild.subArtifactsPatterns)
+		assert.True(t, containingBuild.recordCacheTimestamps)
+		assert.Same(t, checksumCache, containingBuild.checksumCache)
+		assert.Same(t, bld.statsCounter, containingBuild.statsCounter)
+		return nil
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,323 @@
+package build
+
+import (
+	"archive/zip"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// The properties which describe the contents of the analyzed jars, added to the modules and to their dependencies.
+const (
+	// A dependency property with the names of the jars in which the dependency is embedded.
+	JarEmbeddedInProperty = "jar.embeddedIn"
+	// A dependency property which marks the dependencies found embedded in a jar, which the module doesn't declare.
+	JarUndeclaredProperty = "jar.undeclared"
+	// A module property with the names of the Java modules declared by the module's jars.
+	JavaModuleProperty = "buildInfo.java.module"
+	// A module property with the names of the Java modules required by the module's jars.
+	JavaModuleRequiresProperty = "buildInfo.java.requires"
+	// A module property with the original names of the packages which were relocated when they were shaded into the module's jars.
+	JarRelocatedPackagesProperty = "buildInfo.jar.relocatedPackages"
+)
+
+// The package name segments under which the maven-shade-plugin, the Gradle Shadow plugin and similar tools are commonly configured to relocate shaded packages,
+// for example: org.example.shaded.com.google.common
+var relocatedPackageSegments = []string{"shaded", "shadow", "relocated", "repackaged", "thirdparty"}
+
+// The number of leading segments by which the relocated packages are recorded, so that the sub-packages of a shaded library are recorded once.
+const relocatedPackageDepth = 3
+
+// Built jars whose contents are reconciled with the dependencies of the build-info's modules.
+type jarAnalyses struct {
+	// The ID of the module which built the jars. If empty, each jar is reconciled with the module which has an artifact of the same name.
+	moduleId string
+	// Paths of the jars, which may contain wildcards, including '**'.
+	patterns []string
+}
+
+// JarContents is what a built jar, such as a fat jar or a shaded jar, reveals about the dependencies packaged in it.
+type JarContents struct {
+	// The group:artifact:version of the Maven artifacts whose pom.properties files were packaged in the jar, excluding the jar's own artifact.
+	EmbeddedMavenIds []string
+	// The Java module declared by the jar's module-info.class, or by the Automatic-Module-Name attribute of its manifest. Nil if the jar declares no module.
+	JavaModule *buildutils.JavaModuleInfo
+	// The original names of the packages which were relocated when they were shaded into the jar, for example: com.google.common
+	RelocatedPackages []string
+}
+
+// AnalyzeJars adds built jars, such as fat jars and shaded jars, whose contents are reconciled with the dependencies of the module with the given ID.
+// If moduleId is empty, each jar is reconciled with the module which has an artifact of the same name.
+// The Maven artifacts embedded in a jar, which the module already declares, get the JarEmbeddedInProperty property.
+// The rest are added to the module as dependencies, which are marked by the JarUndeclaredProperty property and have the "embedded" resolution source.
+// The Java module declared by the jar and the packages relocated into it are added to the module's properties.
+// The jars are read when creating the build-info using the ToBuildInfo() function, so they can be written by the build itself.
+func (b *Build) AnalyzeJars(moduleId string, jarPatterns ...string) {
+	if len(jarPatterns) == 0 {
+		return
+	}
+	b.jarAnalyses = append(b.jarAnalyses, jarAnalyses{moduleId: moduleId, patterns: jarPatterns})
+}
+
+func applyJarAnalyses(buildInfo *entities.BuildInfo, analyses []jarAnalyses, logger utils.Log) error {
+	// The properties of each module are collected from all of its jars, and added once they're all analyzed.
+	modulesProperties := make(map[int]map[string][]string)
+	for _, analysis := range analyses {
+		var jarPaths []string
+		for _, pattern := range analysis.patterns {
+			paths, err := globFiles(pattern)
+			if err != nil {
+				return err
+			}
+			jarPaths = append(jarPaths, paths...)
+		}
+		if len(jarPaths) == 0 {
+			logger.Warn(fmt.Sprintf("No jars were found at: %v", analysis.patterns))
+			continue
+		}
+		for _, jarPath := range jarPaths {
+			jarName := filepath.Base(jarPath)
+			moduleIndex := getJarModuleIndex(buildInfo, analysis.moduleId, jarName)
+			if moduleIndex < 0 {
+				if analysis.moduleId != "" {
+					return fmt.Errorf("the jar '%s' was added to the '%s' module, which isn't part of the build-info", jarPath, analysis.moduleId)
+				}
+				logger.Warn(fmt.Sprintf("Skipping the analysis of the jar '%s', since no module of the build-info has an artifact with the same name.", jarPath))
+				continue
+			}
+			contents, err := AnalyzeJar(jarPath)
+			if err != nil {
+				return err
+			}
+			if modulesProperties[moduleIndex] == nil {
+				modulesProperties[moduleIndex] = make(map[string][]string)
+			}
+			reconcileJarContents(&buildInfo.Modules[moduleIndex], jarName, contents, modulesProperties[moduleIndex], logger)
+		}
+	}
+	for moduleIndex, properties := range modulesProperties {
+		joinedProperties := make(map[string]string)
+		for key, values := range properties {
+			if len(values) > 0 {
+				joinedProperties[key] = strings.Join(values, ",")
+			}
+		}
+		buildInfo.Modules[moduleIndex].AddProperties(joinedProperties)
+	}
+	return nil
+}
+
+// Returns the index of the module with the given ID, or of the module with an artifact named jarName if moduleId is empty. Returns -1 if there's no such module.
+func getJarModuleIndex(buildInfo *entities.BuildInfo, moduleId, jarName string) int {
+	return slices.IndexFunc(buildInfo.Modules, func(module entities.Module) bool {
+		if moduleId != "" {
+			return module.Id == moduleId
+		}
+		return slices.ContainsFunc(module.Artifacts, func(artifact entities.Artifact) bool {
+			return artifact.Name == jarName
+		})
+	})
+}
+
+// Marks the module's dependencies which are embedded in the jar, adds the embedded artifacts the module doesn't declare,
+// and collects the jar's Java module and relocated packages into the module's properties.
+func reconcileJarContents(module *entities.Module, jarName string, contents *JarContents, properties map[string][]string, logger utils.Log) {
+	for _, mavenId := range contents.EmbeddedMavenIds {
+		// The versions are ignored, since the version of an embedded artifact may be resolved differently than the declared one.
+		dependencyIndex := slices.IndexFunc(module.Dependencies, func(dependency entities.Dependency) bool {
+			return getGroupArtifact(dependency.Id) == getGroupArtifact(mavenId)
+		})
+		if dependencyIndex >= 0 {
+			addDependencyPropertyValue(&module.Dependencies[dependencyIndex], JarEmbeddedInProperty, jarName)
+			continue
+		}
+		logger.Info(fmt.Sprintf("The '%s' artifact is embedded in the jar '%s', but isn't declared as a dependency of the '%s' module.", mavenId, jarName, module.Id))
+		module.Dependencies = append(module.Dependencies, entities.Dependency{
+			Id:         mavenId,
+			Type:       "jar",
+			Properties: map[string]string{JarEmbeddedInProperty: jarName, JarUndeclaredProperty: "true", entities.ResolutionSourceProperty: string(entities.EmbeddedSource)},
+		})
+	}
+	if contents.JavaModule != nil {
+		properties[JavaModuleProperty] = addSortedUnique(properties[JavaModuleProperty], contents.JavaModule.Name)
+		properties[JavaModuleRequiresProperty] = addSortedUnique(properties[JavaModuleRequiresProperty], contents.JavaModule.Requires...)
+	}
+	properties[JarRelocatedPackagesProperty] = addSortedUnique(properties[JarRelocatedPackagesProperty], contents.RelocatedPackages...)
+}
+
+// Returns the group:artifact prefix of a Maven dependency ID.
+func getGroupArtifact(mavenId string) string {
+	if parts := strings.SplitN(mavenId, ":", 3); len(parts) == 3 {
+		return parts[0] + ":" + parts[1]
+	}
+	return mavenId
+}
+
+// Adds a value to a comma-separated list of values in a dependency property.
+func addDependencyPropertyValue(dependency *entities.Dependency, key, value string) {
+	if dependency.Properties == nil {
+		dependency.Properties = make(map[string]string)
+	}
+	if existing := dependency.Properties[key]; existing != "" {
+		value = strings.Join(addSortedUnique(strings.Split(existing, ","), value), ",")
+	}
+	dependency.Properties[key] = value
+}
+
+func addSortedUnique(values []string, newValues ...string) []string {
+	for _, value := range newValues {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// AnalyzeJar reads a built jar and returns the Maven artifacts, the Java module and the relocated packages found in it.
+// The jar is read in memory, without extracting it to disk, and the jars nested in it aren't opened.
+func AnalyzeJar(jarPath string) (contents *JarContents, err error) {
+	jar, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, jar.Close())
+	}()
+	mavenIds, err := readJarPomProperties(&jar.Reader)
+	if err != nil {
+		return nil, err
+	}
+	contents = &JarContents{}
+	ownMavenId := getJarOwnMavenId(filepath.Base(jarPath), mavenIds)
+	for _, mavenId := range mavenIds {
+		if mavenId != ownMavenId {
+			contents.EmbeddedMavenIds = append(contents.EmbeddedMavenIds, mavenId)
+		}
+	}
+	sort.Strings(contents.EmbeddedMavenIds)
+	if contents.JavaModule, err = readJarJavaModule(&jar.Reader); err != nil {
+		return nil, err
+	}
+	contents.RelocatedPackages = getRelocatedPackages(&jar.Reader)
+	return contents, nil
+}
+
+// Returns the Java module declared by the jar's module-info.class, either at its root or under META-INF/versions/ in a multi-release jar.
+// Falls back to the name of the automatic module in the Automatic-Module-Name attribute of the jar's manifest.
+func readJarJavaModule(jar *zip.Reader) (*buildutils.JavaModuleInfo, error) {
+	var moduleInfoFile, manifestFile *zip.File
+	for _, file := range jar.File {
+		switch {
+		case file.Name == "module-info.class":
+			moduleInfoFile = file
+		case moduleInfoFile == nil && strings.HasPrefix(file.Name, "META-INF/versions/") && path.Base(file.Name) == "module-info.class":
+			moduleInfoFile = file
+		case file.Name == "META-INF/MANIFEST.MF":
+			manifestFile = file
+		}
+	}
+	if moduleInfoFile != nil {
+		content, err := readZipFileContent(moduleInfoFile)
+		if err != nil {
+			return nil, err
+		}
+		return buildutils.ParseJavaModuleInfo(content)
+	}
+	if manifestFile == nil {
+		return nil, nil
+	}
+	content, err := readZipFileContent(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	if moduleName := getManifestAttribute(string(content), "Automatic-Module-Name"); moduleName != "" {
+		return &buildutils.JavaModuleInfo{Name: moduleName}, nil
+	}
+	return nil, nil
+}
+
+func readZipFileContent(file *zip.File) (content []byte, err error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, reader.Close())
+	}()
+	return io.ReadAll(reader)
+}
+
+// Returns the value of a main attribute of a jar manifest. The manifest's lines are wrapped at 72 bytes, and continued in lines which start with a space.
+func getManifestAttribute(manifest, name string) string {
+	var value string
+	found := false
+	scanner := bufio.NewScanner(strings.NewReader(manifest))
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if found {
+			if !strings.HasPrefix(line, " ") {
+				break
+			}
+			value += line[1:]
+			continue
+		}
+		// The main attributes end at the first empty line.
+		if line == "" {
+			break
+		}
+		if key, attributeValue, ok := strings.Cut(line, ": "); ok && strings.EqualFold(key, name) {
+			value = attributeValue
+			found = true
+		}
+	}
+	return strings.TrimSpace(value)
+}
+
+// Returns the original names of the packages relocated into the jar, by the classes under packages with one of the relocatedPackageSegments.
+func getRelocatedPackages(jar *zip.Reader) []string {
+	packages := make(map[string]bool)
+	for _, file := range jar.File {
+		if path.Ext(file.Name) != ".class" {
+			continue
+		}
+		segments := strings.Split(path.Dir(stripMultiReleasePrefix(file.Name)), "/")
+		for i, segment := range segments {
+			// The relocation segment has to be under a package of the jar's own, and above the original package.
+			if i == 0 || i == len(segments)-1 || !slices.Contains(relocatedPackageSegments, segment) {
+				continue
+			}
+			original := segments[i+1:]
+			if len(original) > relocatedPackageDepth {
+				original = original[:relocatedPackageDepth]
+			}
+			packages[strings.Join(original, ".")] = true
+			break
+		}
+	}
+	relocatedPackages := maps.Keys(packages)
+	sort.Strings(relocatedPackages)
+	return relocatedPackages
+}
+
+// Returns the path of a class in a multi-release jar, without its META-INF/versions/<version>/ prefix.
+func stripMultiReleasePrefix(entryPath string) string {
+	if !strings.HasPrefix(entryPath, "META-INF/versions/") {
+		return entryPath
+	}
+	if parts := strings.SplitN(entryPath, "/", 4); len(parts) == 4 {
+		return parts[3]
+	}
+	return entryPath
+}
@@ -0,0 +1,75 @@
+package build
+
+import (
+	"path/filepath"
+	"testing"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeJar(t *testing.T) {
+	jarPath := filepath.Join(t.TempDir(), "app-1.0-all.jar")
+	writeZip(t, jarPath, map[string]string{
+		"META-INF/MANIFEST.MF":                                          "Manifest-Version: 1.0\r\nAutomatic-Module-Name: org.example.a\r\n pp\r\n\r\nName: org/example/\r\nAutomatic-Module-Name: other\r\n",
+		"META-INF/maven/org.example/app/pom.properties":                 "groupId=org.example\nartifactId=app\nversion=1.0\n",
+		"META-INF/maven/com.google.guava/guava/pom.properties":          "groupId=com.google.guava\nartifactId=guava\nversion=32.1.2-jre\n",
+		"org/example/App.class":                                         "class",
+		"org/example/shaded/com/google/common/collect/Lists.class":      "class",
+		"org/example/shaded/com/google/common/base/Strings.class":       "class",
+		"META-INF/versions/11/org/example/shadow/okio/Buffer.class":     "class",
+		"org/example/shaded/Marker.class":                               "class",
+		"shaded/org/Root.class":                                         "class",
+		"org/example/relocated/org/apache/commons/lang3/Validate.class": "class",
+	})
+
+	contents, err := AnalyzeJar(jarPath)
+	require.NoError(t, err)
+	assert.Equal(t, &JarContents{
+		EmbeddedMavenIds:  []string{"com.google.guava:guava:32.1.2-jre"},
+		JavaModule:        &buildutils.JavaModuleInfo{Name: "org.example.app"},
+		RelocatedPackages: []string{"com.google.common", "okio", "org.apache.commons"},
+	}, contents)
+
+	_, err = AnalyzeJar(filepath.Join(t.TempDir(), "missing.jar"))
+	assert.Error(t, err)
+}
+
+func TestApplyJarAnalyses(t *testing.T) {
+	jarsDir := t.TempDir()
+	writeZip(t, filepath.Join(jarsDir, "app-1.0.jar"), map[string]string{
+		"META-INF/maven/org.example/app/pom.properties":           "groupId=org.example\nartifactId=app\nversion=1.0\n",
+		"META-INF/maven/com.google.guava/guava/pom.properties":    "groupId=com.google.guava\nartifactId=guava\nversion=32.1.2-jre\n",
+		"META-INF/maven/org.slf4j/slf4j-api/pom.properties":       "groupId=org.slf4j\nartifactId=slf4j-api\nversion=2.0.9\n",
+		"org/example/shaded/com/google/common/base/Strings.class": "class",
+	})
+	writeZip(t, filepath.Join(jarsDir, "app-1.0-tests.jar"), map[string]string{
+		"META-INF/maven/org.example/app/pom.properties":        "groupId=org.example\nartifactId=app\nversion=1.0\n",
+		"META-INF/maven/com.google.guava/guava/pom.properties": "groupId=com.google.guava\nartifactId=guava\nversion=32.1.2-jre\n",
+	})
+	writeZip(t, filepath.Join(jarsDir, "unknown-1.0.jar"), map[string]string{})
+
+	buildInfo := &entities.BuildInfo{Modules: []entities.Module{{
+		Id:           "org.example:app:1.0",
+		Artifacts:    []entities.Artifact{{Name: "app-1.0.jar"}, {Name: "app-1.0-tests.jar"}},
+		Dependencies: []entities.Dependency{{Id: "com.google.guava:guava:31.0-jre", Type: "jar"}},
+	}}}
+	assert.NoError(t, applyJarAnalyses(buildInfo, []jarAnalyses{
+		// A jar which isn't an artifact of any module is skipped with a warning.
+		{patterns: []string{filepath.Join(jarsDir, "*.jar")}},
+		{patterns: []string{filepath.Join(jarsDir, "missing.jar")}},
+	}, logger))
+	assert.Equal(t, []entities.Dependency{
+		{Id: "com.google.guava:guava:31.0-jre", Type: "jar", Properties: map[string]string{JarEmbeddedInProperty: "app-1.0-tests.jar,app-1.0.jar"}},
+		{
+			Id:         "org.slf4j:slf4j-api:2.0.9",
+			Type:       "jar",
+			Properties: map[string]string{JarEmbeddedInProperty: "app-1.0.jar", JarUndeclaredProperty: "true", entities.ResolutionSourceProperty: string(entities.EmbeddedSource)},
+		},
+	}, buildInfo.Modules[0].Dependencies)
+	assert.Equal(t, map[string]any{JarRelocatedPackagesProperty: "com.google.common"}, buildInfo.Modules[0].Properties)
+
+	assert.ErrorContains(t, applyJarAnalyses(buildInfo, []jarAnalyses{{moduleId: "missing", patterns: []string{filepath.Join(jarsDir, "app-1.0.jar")}}}, logger), "isn't part of the build-info")
+}
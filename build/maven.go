@@ -11,7 +11,12 @@ import (
 	"regexp"
 	"strings"
 
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"golang.org/x/term"
 )
 
@@ -22,6 +27,19 @@ const (
 	PropertiesTempFolderName        = "properties"
 	MavenExtractorRemotePath        = "org/jfrog/buildinfo/build-info-extractor-maven3/%s"
 	MavenExtractorDependencyVersion = "2.41.24"
+	MavenPluginScope                = "plugin"
+	MavenExtensionScope             = "extension"
+	// The module property which holds the comma-separated URLs of the repositories Maven resolved from, after applying the mirrors of the settings files.
+	MavenRepositoriesProperty = "buildInfo.maven.repositories"
+	// The module property which holds the comma-separated IDs of the active profiles of the settings files.
+	MavenActiveProfilesProperty = "buildInfo.maven.activeProfiles"
+	// The value of the entities.LowFidelityProperty of the modules approximated from Maven's log.
+	mavenLogFidelity = "maven-log"
+	// The build properties which describe the Maven installation which built the project, and the distribution of the project's wrapper.
+	MavenHomeProperty               = ToolchainPropertyPrefix + "maven.home"
+	MavenHomeVersionProperty        = ToolchainPropertyPrefix + "maven.homeVersion"
+	MavenDistributionUrlProperty    = ToolchainPropertyPrefix + "maven.distributionUrl"
+	MavenDistributionSha256Property = ToolchainPropertyPrefix + "maven.distributionSha256"
 
 	ClassworldsConf = `main is org.apache.maven.cli.MavenCli from plexus.core
 
@@ -48,6 +66,11 @@ type MavenModule struct {
 	buildInfoPath string
 	// Path to the root project directory in maven multi-module project. May contain .mvn directory.
 	rootProjectDir string
+	// Add the build plugins and extensions of each module to the build-info.
+	collectBuildPlugins bool
+	// The number of parallel Maven invocations which collect the dependency trees of the reactor modules, and the threads of each invocation. See CalcDependenciesFromTree.
+	treeParallelism int
+	treeThreads     string
 }
 
 // Maven extractor is the engine for calculating the project dependencies.
@@ -66,6 +89,19 @@ type extractorDetails struct {
 	propsDir string
 	// Use the maven wrapper to build the project.
 	useWrapper bool
+	// Paths of the user and global settings files, passed to Maven with -s and -gs. Maven's defaults are used if empty.
+	settingsPath       string
+	globalSettingsPath string
+	// Profiles to activate, or deactivate with a '!' prefix, passed to Maven with -P.
+	profiles []string
+	// The path of the POM, or of its directory, passed to Maven with -f. Maven's default, pom.xml, is used if empty.
+	pomFile string
+	// System properties passed to Maven with -D.
+	systemProperties map[string]string
+	// The Maven installation which builds the project. If empty, it's found by the M2_HOME environment variable, or by 'mvn --version'.
+	mavenHome string
+	// The version which the Maven installation must be of. Any version is allowed if empty.
+	requiredVersion string
 }
 
 // Add a new Maven module to a given build.
@@ -108,10 +144,77 @@ func (mm *MavenModule) SetOutputWriter(outputWriter io.Writer) {
 	mm.outputWriter = outputWriter
 }
 
+// SetCollectBuildPlugins sets whether the plugins and extensions declared in the POMs should be added to the build-info.
+// They are added as dependencies with the 'plugin' and 'extension' scopes, so that the tools which ran during the build are audited as well.
+func (mm *MavenModule) SetCollectBuildPlugins(collectBuildPlugins bool) {
+	mm.collectBuildPlugins = collectBuildPlugins
+}
+
 func (mm *MavenModule) SetMavenGoals(goals ...string) {
 	mm.extractorDetails.goals = goals
 }
 
+// SetSettingsFiles sets the paths of the user and global settings files, which are passed to Maven with the -s and -gs options.
+// An empty path keeps Maven's default: ~/.m2/settings.xml for the user settings, and conf/settings.xml in Maven's home for the global settings.
+func (mm *MavenModule) SetSettingsFiles(settingsPath, globalSettingsPath string) {
+	mm.extractorDetails.settingsPath = settingsPath
+	mm.extractorDetails.globalSettingsPath = globalSettingsPath
+}
+
+// SetProfiles sets the profiles to activate, or to deactivate with a '!' prefix, which are passed to Maven with the -P option.
+func (mm *MavenModule) SetProfiles(profiles ...string) {
+	mm.extractorDetails.profiles = profiles
+}
+
+// SetPomFile sets the path of the POM, or of its directory, which is passed to Maven with the -f option.
+// A relative path is relative to the project's directory. An empty path keeps Maven's default, pom.xml.
+func (mm *MavenModule) SetPomFile(pomFile string) {
+	mm.extractorDetails.pomFile = pomFile
+}
+
+// SetSystemProperties sets the system properties which are passed to Maven with the -D option.
+func (mm *MavenModule) SetSystemProperties(systemProperties map[string]string) {
+	mm.extractorDetails.systemProperties = systemProperties
+}
+
+// SetUseWrapper sets whether the project is built with its Maven wrapper (mvnw) rather than with the Maven installation in the PATH.
+// When the wrapper is used, the URL and the SHA-256 checksum of its Maven distribution are added to the build properties.
+func (mm *MavenModule) SetUseWrapper(useWrapper bool) {
+	mm.extractorDetails.useWrapper = useWrapper
+}
+
+// SetMavenHome sets the home directory of the Maven installation which builds the project, overriding the M2_HOME environment variable,
+// the wrapper and the mvn executable in the PATH. A relative path is relative to the project's directory.
+func (mm *MavenModule) SetMavenHome(mavenHome string) {
+	mm.extractorDetails.mavenHome = mavenHome
+}
+
+// SetRequiredVersion sets the version which the Maven installation must be of. The collection fails before running Maven if it's of another version.
+func (mm *MavenModule) SetRequiredVersion(requiredVersion string) {
+	mm.extractorDetails.requiredVersion = requiredVersion
+}
+
+// SetConfig sets the POM, the profiles, the system properties and the Maven installation of the Maven build from the project's configuration file.
+func (mm *MavenModule) SetConfig(config MavenConfig) {
+	mm.SetPomFile(config.PomFile)
+	mm.SetProfiles(config.Profiles...)
+	mm.SetSystemProperties(config.Properties)
+	mm.SetMavenHome(config.Home)
+	mm.SetRequiredVersion(config.Version)
+}
+
+// SetTreeParallelism sets the number of parallel Maven invocations which run by CalcDependenciesFromTree, each of a batch of the reactor modules.
+// The default, 1, runs a single invocation of the whole reactor.
+func (mm *MavenModule) SetTreeParallelism(treeParallelism int) {
+	mm.treeParallelism = treeParallelism
+}
+
+// SetTreeThreads sets the threads of each Maven invocation which runs by CalcDependenciesFromTree, which are passed to Maven with the -T option,
+// for example: 4 or 1C. Maven builds the modules of the invocation sequentially if it's empty.
+func (mm *MavenModule) SetTreeThreads(treeThreads string) {
+	mm.treeThreads = treeThreads
+}
+
 func (mm *MavenModule) SetMavenOpts(mavenOpts ...string) {
 	mm.extractorDetails.mavenOpts = mavenOpts
 }
@@ -164,6 +267,11 @@ func (mm *MavenModule) createMvnRunConfig() (*mvnRunConfig, error) {
 		mavenOpts:           mm.extractorDetails.mavenOpts,
 		logger:              mm.containingBuild.logger,
 		rootProjectDir:      mm.rootProjectDir,
+		settingsPath:        mm.extractorDetails.settingsPath,
+		globalSettingsPath:  mm.extractorDetails.globalSettingsPath,
+		profiles:            mm.extractorDetails.profiles,
+		pomFile:             mm.extractorDetails.pomFile,
+		systemProperties:    mm.extractorDetails.systemProperties,
 	}, nil
 }
 
@@ -182,18 +290,358 @@ func (mm *MavenModule) CalcDependencies() (err error) {
 	if err != nil {
 		return
 	}
+	// The properties file is removed even if the collection fails before Maven runs.
 	defer func() {
 		fileExist, e := utils.IsFileExists(mvnRunConfig.buildInfoProperties, false)
 		if fileExist && e == nil {
 			err = errors.Join(err, os.Remove(mvnRunConfig.buildInfoProperties))
 		}
 	}()
+	if err = mm.addMavenDistribution(mvnRunConfig.mavenHome); err != nil {
+		return
+	}
+	defer func() {
+		if err == nil {
+			err = mm.containingBuild.addGeneratedResolutionTimes(mm.buildInfoPath)
+		}
+		if err == nil {
+			err = mm.containingBuild.notifyGeneratedModulesCollected(mm.buildInfoPath)
+		}
+	}()
 	mvnRunConfig.SetOutputWriter(mm.outputWriter)
 	mm.containingBuild.logger.Info("Running Mvn...")
-	return mvnRunConfig.runCmd()
+	if err = mvnRunConfig.runCmd(); err != nil {
+		return
+	}
+	if err = mm.addEffectiveRepositories(mvnRunConfig.mavenHome); err != nil || !mm.collectBuildPlugins {
+		return
+	}
+	return mm.addBuildPlugins(mvnRunConfig.mavenHome)
+}
+
+// Adds the repositories which Maven resolved from, according to the active profiles and the mirrors of the settings files,
+// to the properties of the modules in the build-info generated by the extractor.
+func (mm *MavenModule) addEffectiveRepositories(mavenHome string) error {
+	settingsPath, globalSettingsPath, err := mm.getSettingsPaths(mavenHome)
+	if err != nil {
+		return err
+	}
+	settings, err := buildutils.ReadMavenSettings(settingsPath, globalSettingsPath)
+	if err != nil {
+		return err
+	}
+	profiles := append(slices.Clone(mm.extractorDetails.profiles), getGoalsProfiles(mm.extractorDetails.goals)...)
+	properties := map[string]string{}
+	if activeProfiles := settings.ActiveProfiles(profiles); len(activeProfiles) > 0 {
+		properties[MavenActiveProfilesProperty] = strings.Join(activeProfiles, ",")
+	}
+	var repositoryUrls []string
+	// The URLs of the effective repositories, mapped by their IDs.
+	urlsById := map[string]string{}
+	for _, repository := range settings.EffectiveRepositories(profiles) {
+		repositoryUrls = append(repositoryUrls, repository.Url)
+		urlsById[repository.Id] = repository.Url
+	}
+	properties[MavenRepositoriesProperty] = strings.Join(repositoryUrls, ",")
+	localRepository, err := mm.getLocalRepository(mavenHome)
+	if err != nil {
+		return err
+	}
+	return mm.containingBuild.updateGeneratedBuildInfo(mm.buildInfoPath, func(buildInfo *entities.BuildInfo) {
+		for i := range buildInfo.Modules {
+			buildInfo.Modules[i].AddProperties(properties)
+			mm.setDependenciesRepositories(buildInfo.Modules[i].Dependencies, localRepository, urlsById)
+		}
+	})
+}
+
+// Returns the paths of the user and global settings files which Maven reads: the paths set by SetSettingsFiles, or Maven's defaults.
+// The default global settings file is skipped if the Maven home is unknown.
+func (mm *MavenModule) getSettingsPaths(mavenHome string) (settingsPath, globalSettingsPath string, err error) {
+	settingsPath, globalSettingsPath = mm.extractorDetails.settingsPath, mm.extractorDetails.globalSettingsPath
+	if settingsPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", err
+		}
+		settingsPath = filepath.Join(home, ".m2", "settings.xml")
+	}
+	if globalSettingsPath == "" && mavenHome != "" {
+		globalSettingsPath = filepath.Join(mavenHome, "conf", "settings.xml")
+	}
+	return
+}
+
+// Returns the local repository to which Maven downloads the dependencies, by the maven.repo.local system property, the settings files, or the default.
+// See buildutils.GetMavenLocalRepository. A relative path is relative to the project's directory.
+func (mm *MavenModule) getLocalRepository(mavenHome string) (string, error) {
+	settingsPath, globalSettingsPath, err := mm.getSettingsPaths(mavenHome)
+	if err != nil {
+		return "", err
+	}
+	localRepository, err := buildutils.GetMavenLocalRepository(mm.getRepoLocalProperty(), settingsPath, globalSettingsPath)
+	if err != nil || filepath.IsAbs(localRepository) {
+		return localRepository, err
+	}
+	return filepath.Join(mm.srcPath, localRepository), nil
+}
+
+// Returns the value of the maven.repo.local system property passed to Maven by SetSystemProperties, or else by the goals or the Maven options, if any.
+func (mm *MavenModule) getRepoLocalProperty() (repoLocal string) {
+	if repoLocal = mm.extractorDetails.systemProperties[buildutils.MavenRepoLocalProperty]; repoLocal != "" {
+		return
+	}
+	// Like Maven, the last occurrence of the property wins.
+	prefix := "-D" + buildutils.MavenRepoLocalProperty + "="
+	for _, arg := range append(slices.Clone(mm.extractorDetails.goals), mm.extractorDetails.mavenOpts...) {
+		if strings.HasPrefix(arg, prefix) {
+			repoLocal = strings.TrimPrefix(arg, prefix)
+		}
+	}
+	return
+}
+
+// Returns the Maven home set by SetMavenHome or by the M2_HOME environment variable, without running Maven to find it.
+// Returns an empty string if neither is set.
+func (mm *MavenModule) getConfiguredMavenHome() string {
+	mavenHome := mm.extractorDetails.mavenHome
+	if mavenHome == "" {
+		return os.Getenv(MavenHome)
+	}
+	if !filepath.IsAbs(mavenHome) {
+		mavenHome = filepath.Join(mm.srcPath, mavenHome)
+	}
+	return mavenHome
+}
+
+// Sets the remote repository of each dependency to the repository from which Maven downloaded it, as recorded in the local repository.
+// The repository's URL is set if it's one of the effective repositories, and its ID otherwise (for example, if it's declared in a POM).
+func (mm *MavenModule) setDependenciesRepositories(dependencies []entities.Dependency, localRepository string, urlsById map[string]string) {
+	for i := range dependencies {
+		dependency := &dependencies[i]
+		idParts := strings.Split(dependency.Id, ":")
+		if dependency.RemoteRepository != "" || len(idParts) < 3 {
+			continue
+		}
+		artifactId, version := idParts[1], idParts[2]
+		remoteRepositories, err := buildutils.ReadMavenRemoteRepositories(filepath.Join(localRepository, filepath.Join(strings.Split(idParts[0], ".")...), artifactId, version))
+		if err != nil {
+			mm.containingBuild.logger.Debug("Couldn't read the remote repositories of", dependency.Id+":", err.Error())
+			continue
+		}
+		extension := dependency.Type
+		if extension == "" {
+			extension = "jar"
+		}
+		repositoryId, ok := remoteRepositories[artifactId+"-"+version+"."+extension]
+		if !ok {
+			// The extension of some types differs from the type (for example, test-jar), but the POM is downloaded from the same repository.
+			if repositoryId, ok = remoteRepositories[artifactId+"-"+version+".pom"]; !ok {
+				continue
+			}
+		}
+		if repositoryUrl, ok := urlsById[repositoryId]; ok {
+			repositoryId = repositoryUrl
+		}
+		dependency.RemoteRepository = repositoryId
+	}
+}
+
+// Returns the profiles which are activated or deactivated by the -P and --activate-profiles options in the goals.
+func getGoalsProfiles(goals []string) (profiles []string) {
+	for i, goal := range goals {
+		var value string
+		switch {
+		case (goal == "-P" || goal == "--activate-profiles") && i+1 < len(goals):
+			value = goals[i+1]
+		case strings.HasPrefix(goal, "--activate-profiles="):
+			value = strings.TrimPrefix(goal, "--activate-profiles=")
+		case strings.HasPrefix(goal, "-P") && goal != "-P":
+			value = strings.TrimPrefix(goal, "-P")
+		default:
+			continue
+		}
+		profiles = append(profiles, strings.Split(value, ",")...)
+	}
+	return
+}
+
+// CalcDependenciesFromLog approximates the build-info from the log of a Maven build, written in batch mode (-B),
+// for environments in which the extractor can't be used. See buildutils.ParseMavenLog for the supported log lines.
+// The result is lower-fidelity than the extractor's: the dependencies have no requestedBy paths, and may include the modules' plugins.
+// Therefore, the modules are marked with the entities.LowFidelityProperty, and the dependencies' resolution source is entities.FallbackRegexSource.
+func (mm *MavenModule) CalcDependenciesFromLog(log io.Reader) error {
+	localRepository, err := mm.getLocalRepository(mm.getConfiguredMavenHome())
+	if err != nil {
+		return err
+	}
+	return mm.calcDependenciesFromLog(log, localRepository)
+}
+
+func (mm *MavenModule) calcDependenciesFromLog(log io.Reader, localRepository string) error {
+	logModules, err := buildutils.ParseMavenLog(log, localRepository)
+	if err != nil {
+		return err
+	}
+	if len(logModules) == 0 {
+		return utils.NewCategorizedError(utils.ParseFailure, errors.New("no Maven modules were found in the log. Make sure the log was written in batch mode (-B)"))
+	}
+	mm.containingBuild.logger.Warn("The build-info is approximated from Maven's log. Its dependencies may be incomplete, or include the build plugins.")
+	buildInfo := &entities.BuildInfo{}
+	for _, logModule := range logModules {
+		module := entities.Module{Id: logModule.Id, Type: entities.Maven}
+		module.AddProperties(map[string]string{entities.LowFidelityProperty: mavenLogFidelity})
+		for _, logDependency := range logModule.Dependencies {
+			dependency := entities.Dependency{Id: logDependency.Id, Type: logDependency.Type, RemoteRepository: logDependency.RepositoryUrl}
+			dependency.SetResolutionSource(entities.FallbackRegexSource)
+			if logDependency.Scope != "" {
+				dependency.Scopes = []string{logDependency.Scope}
+			}
+			// The checksums are calculated if the dependency's file exists in the local repository.
+			if idParts := strings.Split(logDependency.Id, ":"); len(idParts) == 3 {
+				filePath := filepath.Join(localRepository, filepath.Join(strings.Split(idParts[0], ".")...), idParts[1], idParts[2], idParts[1]+"-"+idParts[2]+"."+logDependency.Type)
+				if checksums, err := mm.containingBuild.checksumCache.GetFileChecksums(filePath); err == nil {
+					dependency.Checksum = entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}
+				}
+			}
+			module.Dependencies = append(module.Dependencies, dependency)
+		}
+		// The dependencies which weren't downloaded during the logged build were downloaded by previous builds.
+		mm.setDependenciesRepositories(module.Dependencies, localRepository, nil)
+		buildInfo.Modules = append(buildInfo.Modules, module)
+	}
+	return mm.containingBuild.SaveBuildInfo(buildInfo)
+}
+
+// Adds the build plugins and extensions of each module to the build-info generated by the extractor.
+func (mm *MavenModule) addBuildPlugins(mavenHome string) error {
+	modulesPlugins, err := buildutils.GetMavenPomBuildPlugins(mm.getPomPath())
+	if err != nil {
+		return err
+	}
+	localRepository, err := mm.getLocalRepository(mavenHome)
+	if err != nil {
+		return err
+	}
+	return mm.containingBuild.updateGeneratedBuildInfo(mm.buildInfoPath, func(buildInfo *entities.BuildInfo) {
+		for i, module := range buildInfo.Modules {
+			modulePlugins, ok := modulesPlugins[module.Id]
+			if !ok {
+				continue
+			}
+			for _, plugin := range modulePlugins.Plugins {
+				buildInfo.Modules[i].Dependencies = append(buildInfo.Modules[i].Dependencies, mm.createBuildPluginDependency(plugin, MavenPluginScope, localRepository))
+			}
+			for _, extension := range modulePlugins.Extensions {
+				buildInfo.Modules[i].Dependencies = append(buildInfo.Modules[i].Dependencies, mm.createBuildPluginDependency(extension, MavenExtensionScope, localRepository))
+			}
+		}
+	})
+}
+
+// Creates a build-info dependency for the plugin. The checksums are calculated if the plugin's jar exists in the local repository.
+func (mm *MavenModule) createBuildPluginDependency(plugin buildutils.MavenBuildPlugin, scope, localRepository string) entities.Dependency {
+	dependency := entities.Dependency{Id: plugin.Id(), Type: "jar", Scopes: []string{scope}}
+	jarPath := filepath.Join(localRepository, filepath.Join(strings.Split(plugin.GroupId, ".")...), plugin.ArtifactId, plugin.Version, plugin.ArtifactId+"-"+plugin.Version+".jar")
+	checksums, err := mm.containingBuild.checksumCache.GetFileChecksums(jarPath)
+	if err != nil {
+		mm.containingBuild.logger.Debug("Couldn't calculate the checksums of the", scope, plugin.Id()+":", err.Error())
+		return dependency
+	}
+	dependency.Checksum = entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}
+	return dependency
+}
+
+// Returns the path of the POM which Maven builds, according to the -f option.
+func (mm *MavenModule) getPomPath() string {
+	pomPath := mm.extractorDetails.pomFile
+	if pomPath == "" {
+		return filepath.Join(mm.srcPath, "pom.xml")
+	}
+	if !filepath.IsAbs(pomPath) {
+		pomPath = filepath.Join(mm.srcPath, pomPath)
+	}
+	if isDir, err := utils.IsDirExists(pomPath, true); err == nil && isDir {
+		pomPath = filepath.Join(pomPath, "pom.xml")
+	}
+	return pomPath
+}
+
+// Verifies the version of the Maven installation which builds the project, if a version is required, and adds its home and version to the build properties.
+// When the project is built with the wrapper, the URL and the SHA-256 checksum of the wrapper's distribution are added too.
+func (mm *MavenModule) addMavenDistribution(mavenHome string) error {
+	version, err := buildutils.GetMavenHomeVersion(mavenHome)
+	if err != nil {
+		return err
+	}
+	if requiredVersion := mm.extractorDetails.requiredVersion; requiredVersion != "" && version != requiredVersion {
+		if version == "" {
+			return utils.NewCategorizedError(utils.ToolNotFound, fmt.Errorf("the version of the Maven installation in %s, which must be %s, couldn't be determined", mavenHome, requiredVersion))
+		}
+		return utils.NewCategorizedError(utils.ToolNotFound, fmt.Errorf("the project requires Maven %s, but the Maven installation in %s is of version %s", requiredVersion, mavenHome, version))
+	}
+	properties := map[string]string{MavenHomeProperty: mavenHome}
+	if version != "" {
+		properties[MavenHomeVersionProperty] = version
+	}
+	if mm.extractorDetails.useWrapper {
+		if err = mm.addWrapperDistribution(properties); err != nil {
+			return err
+		}
+	}
+	return mm.containingBuild.SavePartialBuildInfo(&entities.Partial{Env: properties})
+}
+
+// Adds the URL and the SHA-256 checksum of the Maven distribution declared in the project's maven-wrapper.properties to the properties.
+// The checksum is calculated from the distribution's zip file, if the wrapper kept it in the Maven user home,
+// or is the distributionSha256Sum of the maven-wrapper.properties otherwise.
+func (mm *MavenModule) addWrapperDistribution(properties map[string]string) error {
+	projectDir := mm.rootProjectDir
+	if projectDir == "" {
+		projectDir = mm.srcPath
+	}
+	wrapperProperties, err := buildutils.ReadMavenWrapperProperties(projectDir)
+	if err != nil || wrapperProperties == nil || wrapperProperties.DistributionUrl == "" {
+		return err
+	}
+	properties[MavenDistributionUrlProperty] = wrapperProperties.DistributionUrl
+	checksum := strings.ToLower(wrapperProperties.DistributionSha256Sum)
+	mavenUserHome, err := getMavenUserHome()
+	if err != nil {
+		return err
+	}
+	checksums, err := mm.containingBuild.checksumCache.GetFileChecksums(wrapperProperties.GetDistributionPath(mavenUserHome))
+	switch {
+	case err == nil:
+		checksum = checksums[crypto.SHA256]
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+	if checksum != "" {
+		properties[MavenDistributionSha256Property] = checksum
+	}
+	return nil
+}
+
+func getMavenUserHome() (string, error) {
+	if mavenUserHome := os.Getenv("MAVEN_USER_HOME"); mavenUserHome != "" {
+		return mavenUserHome, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".m2"), nil
 }
 
 func (mm *MavenModule) loadMavenHome() (mavenHome string, err error) {
+	if mavenHome = mm.extractorDetails.mavenHome; mavenHome != "" {
+		if !filepath.IsAbs(mavenHome) {
+			mavenHome = filepath.Join(mm.srcPath, mavenHome)
+		}
+		mm.containingBuild.logger.Debug("Maven home location:", mavenHome)
+		return
+	}
 	mm.containingBuild.logger.Debug("Searching for Maven home.")
 	mavenHome = os.Getenv(MavenHome)
 	if mavenHome == "" {
@@ -266,7 +714,7 @@ func (mm *MavenModule) execMavenVersion(maven string) (stdout bytes.Buffer, err
 	mm.containingBuild.logger.Debug(MavenHome, "is not defined. Retrieving Maven home using 'mvn --version' command.")
 	cmd := exec.Command(maven, "--version")
 	cmd.Stdout = &stdout
-	err = cmd.Run()
+	_, err = runTimedCommand(cmd)
 	err = mm.determineError("mvn", stdout.String(), err)
 	if err != nil {
 		return stdout, err
@@ -325,6 +773,23 @@ func (config *mvnRunConfig) GetCmd() *exec.Cmd {
 		cmd = append(cmd, config.mavenOpts...)
 	}
 	cmd = append(cmd, "org.codehaus.plexus.classworlds.launcher.Launcher")
+	if config.settingsPath != "" {
+		cmd = append(cmd, "-s", config.settingsPath)
+	}
+	if config.globalSettingsPath != "" {
+		cmd = append(cmd, "-gs", config.globalSettingsPath)
+	}
+	if len(config.profiles) > 0 {
+		cmd = append(cmd, "-P", strings.Join(config.profiles, ","))
+	}
+	if config.pomFile != "" {
+		cmd = append(cmd, "-f", config.pomFile)
+	}
+	propertyNames := maps.Keys(config.systemProperties)
+	slices.Sort(propertyNames)
+	for _, propertyName := range propertyNames {
+		cmd = append(cmd, "-D"+propertyName+"="+config.systemProperties[propertyName])
+	}
 	cmd = append(cmd, config.goals...)
 	return exec.Command(cmd[0], cmd[1:]...)
 }
@@ -342,6 +807,11 @@ type mvnRunConfig struct {
 	logger              utils.Log
 	outputWriter        io.Writer
 	rootProjectDir      string
+	settingsPath        string
+	globalSettingsPath  string
+	profiles            []string
+	pomFile             string
+	systemProperties    map[string]string
 }
 
 func (config *mvnRunConfig) SetOutputWriter(outputWriter io.Writer) *mvnRunConfig {
@@ -363,7 +833,7 @@ func (config *mvnRunConfig) runCmd() (err error) {
 	addColorToCmdOutput(command)
 	config.logger.Info("Running mvn command:", strings.Join(command.Args, " "))
 
-	err = command.Run()
+	_, err = runTimedCommand(command)
 	if err != nil {
 		if utils.IsForbiddenOutput(utils.Maven, errBuffer.String()) {
 			err = errors.Join(utils.NewForbiddenError(), err)
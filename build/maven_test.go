@@ -7,6 +7,7 @@ import (
 	"github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/build-info-go/tests"
 	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -212,3 +213,203 @@ func TestCommandWithRootProjectDir(t *testing.T) {
 	assert.Contains(t, cmd.Args, "myMavenOpt2")
 	assert.Contains(t, cmd.Args, "-Dmaven.multiModuleProjectDirectory=myRootProjectDir")
 }
+
+func TestCommandWithSettingsAndProfiles(t *testing.T) {
+	mvnc := &mvnRunConfig{
+		java:               "myJava",
+		goals:              []string{"install"},
+		settingsPath:       "mySettings.xml",
+		globalSettingsPath: "myGlobalSettings.xml",
+		profiles:           []string{"release", "!snapshots"},
+	}
+	cmd := mvnc.GetCmd()
+	assert.Equal(t, []string{"org.codehaus.plexus.classworlds.launcher.Launcher", "-s", "mySettings.xml", "-gs", "myGlobalSettings.xml", "-P", "release,!snapshots", "install"}, cmd.Args[len(cmd.Args)-8:])
+
+	mvnc.settingsPath, mvnc.globalSettingsPath, mvnc.profiles = "", "", nil
+	cmd = mvnc.GetCmd()
+	assert.NotContains(t, cmd.Args, "-s")
+	assert.NotContains(t, cmd.Args, "-gs")
+	assert.NotContains(t, cmd.Args, "-P")
+	assert.NotContains(t, cmd.Args, "-f")
+}
+
+func TestCommandWithPomFileAndSystemProperties(t *testing.T) {
+	mvnc := &mvnRunConfig{
+		java:             "myJava",
+		goals:            []string{"install"},
+		pomFile:          "services/api/pom.xml",
+		systemProperties: map[string]string{"skipTests": "true", "revision": "1.2.0"},
+	}
+	cmd := mvnc.GetCmd()
+	assert.Equal(t, []string{"org.codehaus.plexus.classworlds.launcher.Launcher", "-f", "services/api/pom.xml", "-Drevision=1.2.0", "-DskipTests=true", "install"}, cmd.Args[len(cmd.Args)-6:])
+}
+
+func TestGetPomPath(t *testing.T) {
+	projectDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(projectDir, "services", "api"), 0755))
+	mavenModule := &MavenModule{srcPath: projectDir, extractorDetails: &extractorDetails{}}
+	assert.Equal(t, filepath.Join(projectDir, "pom.xml"), mavenModule.getPomPath())
+	mavenModule.SetPomFile("pom-ci.xml")
+	assert.Equal(t, filepath.Join(projectDir, "pom-ci.xml"), mavenModule.getPomPath())
+	// A directory is built by its pom.xml.
+	mavenModule.SetPomFile(filepath.Join("services", "api"))
+	assert.Equal(t, filepath.Join(projectDir, "services", "api", "pom.xml"), mavenModule.getPomPath())
+	mavenModule.SetPomFile(filepath.Join(projectDir, "pom-release.xml"))
+	assert.Equal(t, filepath.Join(projectDir, "pom-release.xml"), mavenModule.getPomPath())
+}
+
+func TestAddMavenDistribution(t *testing.T) {
+	t.Setenv("MAVEN_USER_HOME", t.TempDir())
+	mavenHome := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(mavenHome, "lib"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(mavenHome, "lib", "maven-core-3.9.6.jar"), []byte("jar"), 0644))
+	projectDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(projectDir, ".mvn", "wrapper"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, ".mvn", "wrapper", "maven-wrapper.properties"), []byte(`wrapperVersion=3.3.2
+distributionType=only-script
+distributionUrl=https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/3.9.6/apache-maven-3.9.6-bin.zip
+distributionSha256Sum=6EAF01C2E8CC1A3D2E2F9E3C2D5A4B6C7D8E9F0A1B2C3D4E5F60718293A4B5C6
+`), 0644))
+
+	bld := createTestBuild(t, "build-info-go-test-maven-distribution")
+	mavenModule, err := bld.AddMavenModule(projectDir)
+	assert.NoError(t, err)
+	mavenModule.SetConfig(MavenConfig{Home: mavenHome, Version: "3.8.8"})
+	err = mavenModule.addMavenDistribution(mavenHome)
+	assert.EqualError(t, err, "the project requires Maven 3.8.8, but the Maven installation in "+mavenHome+" is of version 3.9.6")
+	assert.Equal(t, utils.ToolNotFound, utils.GetErrorCategory(err))
+
+	mavenModule.SetRequiredVersion("3.9.6")
+	mavenModule.SetUseWrapper(true)
+	assert.NoError(t, mavenModule.addMavenDistribution(mavenHome))
+	buildInfo, err := bld.ToBuildInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, mavenHome, buildInfo.Properties[MavenHomeProperty])
+	assert.Equal(t, "3.9.6", buildInfo.Properties[MavenHomeVersionProperty])
+	assert.Equal(t, "https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/3.9.6/apache-maven-3.9.6-bin.zip", buildInfo.Properties[MavenDistributionUrlProperty])
+	// The script-only wrapper deletes the distribution's zip file, so the declared checksum is recorded.
+	assert.Equal(t, "6eaf01c2e8cc1a3d2e2f9e3c2d5a4b6c7d8e9f0a1b2c3d4e5f60718293a4b5c6", buildInfo.Properties[MavenDistributionSha256Property])
+}
+
+func TestLoadConfiguredMavenHome(t *testing.T) {
+	projectDir := t.TempDir()
+	mavenModule := &MavenModule{srcPath: projectDir, containingBuild: &Build{logger: &utils.NullLog{}}, extractorDetails: &extractorDetails{}}
+	mavenModule.SetMavenHome(filepath.Join("tools", "maven"))
+	mavenHome, err := mavenModule.loadMavenHome()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(projectDir, "tools", "maven"), mavenHome)
+}
+
+func TestGetLocalRepository(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	projectDir := t.TempDir()
+	mavenModule := &MavenModule{srcPath: projectDir, containingBuild: &Build{logger: &utils.NullLog{}}, extractorDetails: &extractorDetails{}}
+	localRepository, err := mavenModule.getLocalRepository("")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".m2", "repository"), localRepository)
+
+	// The global settings of the Maven home.
+	mavenHome := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(mavenHome, "conf"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(mavenHome, "conf", "settings.xml"), []byte("<settings><localRepository>/global/repository</localRepository></settings>"), 0644))
+	localRepository, err = mavenModule.getLocalRepository(mavenHome)
+	assert.NoError(t, err)
+	assert.Equal(t, "/global/repository", localRepository)
+
+	// The user settings set by SetSettingsFiles.
+	settingsPath := filepath.Join(t.TempDir(), "settings.xml")
+	assert.NoError(t, os.WriteFile(settingsPath, []byte("<settings><localRepository>/user/repository</localRepository></settings>"), 0644))
+	mavenModule.SetSettingsFiles(settingsPath, "")
+	localRepository, err = mavenModule.getLocalRepository(mavenHome)
+	assert.NoError(t, err)
+	assert.Equal(t, "/user/repository", localRepository)
+
+	// The system property in the goals, and then the one set by SetSystemProperties, which is relative to the project's directory.
+	mavenModule.SetMavenGoals("install", "-Dmaven.repo.local=/goals/repository")
+	localRepository, err = mavenModule.getLocalRepository(mavenHome)
+	assert.NoError(t, err)
+	assert.Equal(t, "/goals/repository", localRepository)
+	mavenModule.SetSystemProperties(map[string]string{"maven.repo.local": "repository"})
+	localRepository, err = mavenModule.getLocalRepository(mavenHome)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(projectDir, "repository"), localRepository)
+}
+
+func TestGetGoalsProfiles(t *testing.T) {
+	goals := []string{"clean", "-Prelease,!snapshots", "-P", "ci", "--activate-profiles=docker", "--activate-profiles", "it", "install"}
+	assert.Equal(t, []string{"release", "!snapshots", "ci", "docker", "it"}, getGoalsProfiles(goals))
+	assert.Empty(t, getGoalsProfiles([]string{"install", "-P"}))
+}
+
+func TestSetDependenciesRepositories(t *testing.T) {
+	localRepository := t.TempDir()
+	remoteRepositories := map[string]string{
+		filepath.Join("org", "slf4j", "slf4j-api", "2.0.9"):   "slf4j-api-2.0.9.jar>artifactory-mirror=\nslf4j-api-2.0.9.pom>artifactory-mirror=\n",
+		filepath.Join("junit", "junit", "4.13.2"):             "junit-4.13.2.pom>snapshots=\n",
+		filepath.Join("org", "example", "installed", "1.0.0"): "installed-1.0.0.jar>=\n",
+	}
+	for dir, content := range remoteRepositories {
+		assert.NoError(t, os.MkdirAll(filepath.Join(localRepository, dir), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(localRepository, dir, "_remote.repositories"), []byte(content), 0644))
+	}
+	dependencies := []entities.Dependency{
+		{Id: "org.slf4j:slf4j-api:2.0.9", Type: "jar"},
+		{Id: "junit:junit:4.13.2", Type: "test-jar"},
+		{Id: "org.example:installed:1.0.0", Type: "jar"},
+		{Id: "org.example:missing:1.0.0", Type: "jar"},
+		{Id: "org.example:known:1.0.0", Type: "jar", RemoteRepository: "https://repo.example.com"},
+	}
+	mavenModule := &MavenModule{containingBuild: &Build{logger: &utils.NullLog{}}}
+	mavenModule.setDependenciesRepositories(dependencies, localRepository, map[string]string{"artifactory-mirror": "https://acme.jfrog.io/artifactory/maven-remote"})
+	assert.Equal(t, []entities.Dependency{
+		{Id: "org.slf4j:slf4j-api:2.0.9", Type: "jar", RemoteRepository: "https://acme.jfrog.io/artifactory/maven-remote"},
+		{Id: "junit:junit:4.13.2", Type: "test-jar", RemoteRepository: "snapshots"},
+		{Id: "org.example:installed:1.0.0", Type: "jar"},
+		{Id: "org.example:missing:1.0.0", Type: "jar"},
+		{Id: "org.example:known:1.0.0", Type: "jar", RemoteRepository: "https://repo.example.com"},
+	}, dependencies)
+}
+
+func TestCalcDependenciesFromLog(t *testing.T) {
+	localRepository := t.TempDir()
+	jarDir := filepath.Join(localRepository, "org", "slf4j", "slf4j-api", "2.0.9")
+	assert.NoError(t, os.MkdirAll(jarDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(jarDir, "slf4j-api-2.0.9.jar"), []byte("jar"), 0644))
+	checksums, err := crypto.GetFileChecksums(filepath.Join(jarDir, "slf4j-api-2.0.9.jar"))
+	assert.NoError(t, err)
+	log := `[INFO] -------------------------< org.example:app >--------------------------
+[INFO] Building app 1.0.0
+[INFO] --------------------------------[ jar ]---------------------------------
+[INFO] Downloaded from central: https://repo.maven.apache.org/maven2/org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar (65 kB at 1.2 MB/s)
+[INFO] The following files have been resolved:
+[INFO]    org.slf4j:slf4j-api:jar:2.0.9:compile
+[INFO]    junit:junit:jar:4.13.2:test
+[INFO]
+`
+	mavenBuild := createTestBuild(t, "maven-log-test")
+	mavenBuild.SetResolutionAudit(true)
+	mavenModule, err := mavenBuild.AddMavenModule("")
+	assert.NoError(t, err)
+	assert.NoError(t, mavenModule.calcDependenciesFromLog(strings.NewReader(log), localRepository))
+	assert.Error(t, mavenModule.calcDependenciesFromLog(strings.NewReader("[INFO] BUILD SUCCESS\n"), localRepository))
+
+	buildInfo, err := mavenBuild.ToBuildInfo()
+	assert.NoError(t, err)
+	if assert.Len(t, buildInfo.Modules, 1) {
+		module := buildInfo.Modules[0]
+		assert.Equal(t, "org.example:app:1.0.0", module.Id)
+		assert.Equal(t, entities.Maven, module.Type)
+		assert.Equal(t, map[string]interface{}{entities.LowFidelityProperty: mavenLogFidelity}, module.Properties)
+		assert.Equal(t, []entities.Dependency{
+			{Id: "org.slf4j:slf4j-api:2.0.9", Type: "jar", Scopes: []string{"compile"}, RemoteRepository: "https://repo.maven.apache.org/maven2",
+				Purl: "pkg:maven/org.slf4j/slf4j-api@2.0.9", CorrelationIds: map[string]string{entities.XrayCorrelationKey: "gav://org.slf4j:slf4j-api:2.0.9"},
+				Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.FallbackRegexSource)},
+				Checksum:   entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}},
+			{Id: "junit:junit:4.13.2", Type: "jar", Scopes: []string{"test"},
+				Purl: "pkg:maven/junit/junit@4.13.2", CorrelationIds: map[string]string{entities.XrayCorrelationKey: "gav://junit:junit:4.13.2"},
+				Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.FallbackRegexSource)}},
+		}, module.Dependencies)
+	}
+}
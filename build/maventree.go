@@ -0,0 +1,211 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+	"github.com/jfrog/gofrog/parallel"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// CalcDependenciesFromTree collects the dependencies of the reactor modules by running Maven's dependency:tree goal, rather than the build-info extractor,
+// which is faster for large reactors, since the project isn't built. The reactor modules are split into batches, which are passed with -pl to parallel
+// Maven invocations, according to SetTreeParallelism and SetTreeThreads. The modules which are declared only in profiles aren't collected.
+// The checksums of the dependencies are calculated from their files in the local repository, which is found by the maven.repo.local system property,
+// the settings files, or the default ~/.m2/repository.
+func (mm *MavenModule) CalcDependenciesFromTree() (err error) {
+	if mm.srcPath == "" {
+		if mm.srcPath, err = os.Getwd(); err != nil {
+			return
+		}
+	}
+	localRepository, err := mm.getLocalRepository(mm.getConfiguredMavenHome())
+	if err != nil {
+		return
+	}
+	treeModules, err := mm.runDependencyTree()
+	if err != nil {
+		return
+	}
+	if len(treeModules) == 0 {
+		return errors.New("no Maven modules were found in the dependency trees of " + mm.getPomPath())
+	}
+	buildInfo := &entities.BuildInfo{}
+	for _, treeModule := range treeModules {
+		module := entities.Module{Id: treeModule.Id, Type: entities.Maven, Dependencies: mm.getTreeDependencies(treeModule, localRepository)}
+		mm.setDependenciesRepositories(module.Dependencies, localRepository, nil)
+		buildInfo.Modules = append(buildInfo.Modules, module)
+	}
+	return mm.containingBuild.SaveBuildInfo(buildInfo)
+}
+
+// Runs the dependency:tree goal of the reactor modules, in parallel batches if the parallelism is greater than 1, and returns their trees in the reactor's order.
+func (mm *MavenModule) runDependencyTree() ([]*buildutils.MavenTreeModule, error) {
+	mavenExecutable, err := mm.getTreeExecutable()
+	if err != nil {
+		return nil, err
+	}
+	moduleDirs, err := buildutils.GetMavenReactorModules(mm.getPomPath())
+	if err != nil {
+		return nil, err
+	}
+	batches := splitToBatches(moduleDirs, mm.treeParallelism)
+	if len(batches) == 1 {
+		mm.containingBuild.logger.Info("Running 'mvn dependency:tree' for", len(moduleDirs), "modules...")
+		return buildutils.RunMavenDependencyTree(mavenExecutable, mm.srcPath, mm.getTreeArgs(), moduleDirs)
+	}
+	mm.containingBuild.logger.Info("Running 'mvn dependency:tree' for", len(moduleDirs), "modules, in", len(batches), "parallel batches...")
+	batchesModules := make([][]*buildutils.MavenTreeModule, len(batches))
+	var batchesErrors []error
+	var errorsLock sync.Mutex
+	runner := parallel.NewBounedRunner(len(batches), false)
+	go func() {
+		defer runner.Done()
+		for i, batch := range batches {
+			i, batch := i, batch
+			_, _ = runner.AddTaskWithError(func(int) error {
+				projectList, err := mm.getProjectList(batch)
+				if err != nil {
+					return err
+				}
+				batchesModules[i], err = buildutils.RunMavenDependencyTree(mavenExecutable, mm.srcPath, append(mm.getTreeArgs(), "-pl", projectList), batch)
+				return err
+			}, func(err error) {
+				errorsLock.Lock()
+				defer errorsLock.Unlock()
+				batchesErrors = append(batchesErrors, fmt.Errorf("failed collecting the dependency trees of the Maven modules batch %d: %w", i+1, err))
+			})
+		}
+	}()
+	runner.Run()
+	if err = errors.Join(batchesErrors...); err != nil {
+		return nil, err
+	}
+	var treeModules []*buildutils.MavenTreeModule
+	for _, batchModules := range batchesModules {
+		treeModules = append(treeModules, batchModules...)
+	}
+	return treeModules, nil
+}
+
+// Returns the Maven executable which runs the dependency:tree goal: the executable of the Maven home if it's set, or the wrapper or the mvn executable in the PATH.
+func (mm *MavenModule) getTreeExecutable() (string, error) {
+	mavenHome := mm.extractorDetails.mavenHome
+	if mavenHome == "" {
+		return mm.getExecutableName()
+	}
+	if !filepath.IsAbs(mavenHome) {
+		mavenHome = filepath.Join(mm.srcPath, mavenHome)
+	}
+	if utils.IsWindows() {
+		return filepath.Join(mavenHome, "bin", "mvn.cmd"), nil
+	}
+	return filepath.Join(mavenHome, "bin", "mvn"), nil
+}
+
+// Returns the arguments of the Maven invocations which run the dependency:tree goal: the batch mode, and the settings, profiles, POM, system properties and threads of the module.
+func (mm *MavenModule) getTreeArgs() []string {
+	args := []string{"-B"}
+	if mm.extractorDetails.settingsPath != "" {
+		args = append(args, "-s", mm.extractorDetails.settingsPath)
+	}
+	if mm.extractorDetails.globalSettingsPath != "" {
+		args = append(args, "-gs", mm.extractorDetails.globalSettingsPath)
+	}
+	if len(mm.extractorDetails.profiles) > 0 {
+		args = append(args, "-P", strings.Join(mm.extractorDetails.profiles, ","))
+	}
+	if mm.extractorDetails.pomFile != "" {
+		args = append(args, "-f", mm.extractorDetails.pomFile)
+	}
+	propertyNames := maps.Keys(mm.extractorDetails.systemProperties)
+	slices.Sort(propertyNames)
+	for _, propertyName := range propertyNames {
+		args = append(args, "-D"+propertyName+"="+mm.extractorDetails.systemProperties[propertyName])
+	}
+	if mm.treeThreads != "" {
+		args = append(args, "-T", mm.treeThreads)
+	}
+	return args
+}
+
+// Returns the value of the -pl option which selects the modules in the directories: their paths relative to the project's directory, separated by commas.
+func (mm *MavenModule) getProjectList(moduleDirs []string) (string, error) {
+	projects := make([]string, 0, len(moduleDirs))
+	for _, moduleDir := range moduleDirs {
+		project, err := filepath.Rel(mm.srcPath, moduleDir)
+		if err != nil {
+			return "", err
+		}
+		projects = append(projects, filepath.ToSlash(project))
+	}
+	return strings.Join(projects, ","), nil
+}
+
+// Splits the values into up to batchesCount contiguous batches of similar sizes, which keep the values' order.
+func splitToBatches(values []string, batchesCount int) (batches [][]string) {
+	if batchesCount < 1 {
+		batchesCount = 1
+	}
+	batchesCount = min(batchesCount, max(len(values), 1))
+	batchSize, remainder := len(values)/batchesCount, len(values)%batchesCount
+	for i, start := 0, 0; i < batchesCount; i++ {
+		end := start + batchSize
+		if i < remainder {
+			end++
+		}
+		batches = append(batches, values[start:end])
+		start = end
+	}
+	return
+}
+
+// Flattens the tree of the module into build-info dependencies, each of which is requested by the paths to it from the module.
+// A dependency which is found in more than one path is added once, with all of its paths and scopes.
+func (mm *MavenModule) getTreeDependencies(treeModule *buildutils.MavenTreeModule, localRepository string) []entities.Dependency {
+	var dependencies []entities.Dependency
+	indexes := map[string]int{}
+	var addDependencies func(treeDependencies []*buildutils.MavenTreeDependency, requestedBy []string)
+	addDependencies = func(treeDependencies []*buildutils.MavenTreeDependency, requestedBy []string) {
+		for _, treeDependency := range treeDependencies {
+			index, exists := indexes[treeDependency.Id]
+			if !exists {
+				index = len(dependencies)
+				indexes[treeDependency.Id] = index
+				dependencies = append(dependencies, mm.createTreeDependency(treeDependency, localRepository))
+			}
+			dependency := &dependencies[index]
+			if treeDependency.Scope != "" && !slices.Contains(dependency.Scopes, treeDependency.Scope) {
+				dependency.Scopes = append(dependency.Scopes, treeDependency.Scope)
+			}
+			dependency.RequestedBy = append(dependency.RequestedBy, requestedBy)
+			addDependencies(treeDependency.Dependencies, append([]string{treeDependency.Id}, requestedBy...))
+		}
+	}
+	addDependencies(treeModule.Dependencies, []string{treeModule.Id})
+	return dependencies
+}
+
+// Creates a build-info dependency for a dependency in the tree. The checksums are calculated if the dependency's file exists in the local repository.
+func (mm *MavenModule) createTreeDependency(treeDependency *buildutils.MavenTreeDependency, localRepository string) entities.Dependency {
+	dependency := entities.Dependency{Id: treeDependency.Id, Type: treeDependency.Type}
+	dependency.SetResolutionSource(entities.CliTreeSource)
+	idParts := strings.Split(treeDependency.Id, ":")
+	filePath := filepath.Join(localRepository, filepath.Join(strings.Split(idParts[0], ".")...), idParts[1], idParts[2], treeDependency.FileName)
+	checksums, err := mm.containingBuild.checksumCache.GetFileChecksums(filePath)
+	if err != nil {
+		mm.containingBuild.logger.Debug("Couldn't calculate the checksums of the dependency", treeDependency.Id+":", err.Error())
+		return dependency
+	}
+	dependency.Checksum = entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}
+	return dependency
+}
@@ -0,0 +1,100 @@
+package build
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
+)
+
+func TestSplitToBatches(t *testing.T) {
+	values := []string{"a", "b", "c", "d", "e"}
+	assert.Equal(t, [][]string{{"a", "b", "c", "d", "e"}}, splitToBatches(values, 0))
+	assert.Equal(t, [][]string{{"a", "b", "c"}, {"d", "e"}}, splitToBatches(values, 2))
+	assert.Equal(t, [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}, splitToBatches(values, 8))
+	assert.Len(t, splitToBatches(nil, 4), 1)
+}
+
+func TestCalcDependenciesFromTree(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	// The local repository is configured by the user settings.
+	localRepository := filepath.Join(home, "repository")
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".m2"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".m2", "settings.xml"), []byte("<settings><localRepository>"+localRepository+"</localRepository></settings>"), 0644))
+	slf4jDir := filepath.Join(localRepository, "org", "slf4j", "slf4j-api", "2.0.9")
+	require.NoError(t, os.MkdirAll(slf4jDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(slf4jDir, "slf4j-api-2.0.9.jar"), []byte("slf4j"), 0644))
+
+	projectDir := t.TempDir()
+	for dir, content := range map[string]string{
+		".":        "<project><groupId>org.example</groupId><artifactId>parent</artifactId><version>1.0.0</version><modules><module>core</module><module>app</module></modules></project>",
+		"core":     "<project><artifactId>core</artifactId></project>",
+		"app":      "<project><artifactId>app</artifactId></project>",
+		"profiled": "<project><artifactId>profiled</artifactId></project>",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(projectDir, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(projectDir, dir, "pom.xml"), []byte(content), 0644))
+	}
+	trees := map[string]string{
+		".":    "org.example:parent:pom:1.0.0\n",
+		"core": "org.example:core:jar:1.0.0\n+- org.slf4j:slf4j-api:jar:2.0.9:compile\n\\- junit:junit:jar:4.13.2:test\n   \\- org.hamcrest:hamcrest-core:jar:1.3:test\n",
+		"app":  "org.example:app:jar:1.0.0\n\\- org.example:core:jar:1.0.0:compile\n   \\- org.slf4j:slf4j-api:jar:2.0.9:compile\n",
+	}
+	var projectLists []string
+	var lock sync.Mutex
+	restore := utils.SetCommandRunner(utils.CommandRunnerFunc(func(command *exec.Cmd) ([]byte, []byte, error) {
+		args := command.Args[1:]
+		assert.Equal(t, []string{"-B", "-P", "ci", "-T", "2"}, args[:5])
+		projectList := args[slices.Index(args, "-pl")+1]
+		lock.Lock()
+		projectLists = append(projectLists, projectList)
+		lock.Unlock()
+		for _, project := range strings.Split(projectList, ",") {
+			treePath := filepath.Join(command.Dir, project, "target", "build-info-dependency-tree.txt")
+			if err := os.MkdirAll(filepath.Dir(treePath), 0755); err != nil {
+				return nil, nil, err
+			}
+			if err := os.WriteFile(treePath, []byte(trees[project]), 0644); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, nil
+	}))
+	defer restore()
+
+	bld := createTestBuild(t, "build-info-go-test-maven-tree")
+	mavenModule, err := bld.AddMavenModule(projectDir)
+	require.NoError(t, err)
+	mavenModule.SetProfiles("ci")
+	mavenModule.SetTreeParallelism(2)
+	mavenModule.SetTreeThreads("2")
+	require.NoError(t, mavenModule.CalcDependenciesFromTree())
+	slices.Sort(projectLists)
+	assert.Equal(t, []string{".,core", "app"}, projectLists)
+
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	// The modules are in the reactor's order.
+	require.Len(t, buildInfo.Modules, 3)
+	assert.Equal(t, "org.example:parent:1.0.0", buildInfo.Modules[0].Id)
+	assert.Empty(t, buildInfo.Modules[0].Dependencies)
+	core, app := buildInfo.Modules[1], buildInfo.Modules[2]
+	assert.Equal(t, "org.example:core:1.0.0", core.Id)
+	require.Len(t, core.Dependencies, 3)
+	assert.Equal(t, "org.slf4j:slf4j-api:2.0.9", core.Dependencies[0].Id)
+	assert.Equal(t, []string{"compile"}, core.Dependencies[0].Scopes)
+	assert.NotEmpty(t, core.Dependencies[0].Checksum.Sha256)
+	assert.Equal(t, [][]string{{"junit:junit:4.13.2", "org.example:core:1.0.0"}}, core.Dependencies[2].RequestedBy)
+	assert.True(t, core.Dependencies[2].Checksum.IsEmpty())
+	assert.Equal(t, "org.example:app:1.0.0", app.Id)
+	require.Len(t, app.Dependencies, 2)
+	assert.Equal(t, [][]string{{"org.example:core:1.0.0", "org.example:app:1.0.0"}}, app.Dependencies[1].RequestedBy)
+}
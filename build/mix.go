@@ -0,0 +1,175 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type MixModule struct {
+	containingBuild *Build
+	name            string
+	srcPath         string
+	// The arguments of a mix task, such as 'deps.get', which runs before the dependencies are collected.
+	mixArgs []string
+	// The duration of the command, if it ran.
+	commandDuration time.Duration
+}
+
+// Pass an empty string for srcPath to find the Mix project in the working directory.
+func newMixModule(srcPath string, containingBuild *Build) (*MixModule, error) {
+	if srcPath == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		srcPath, err = utils.FindFileInDirAndParents(wd, buildutils.MixProjectFileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &MixModule{srcPath: srcPath, containingBuild: containingBuild}, nil
+}
+
+func (mm *MixModule) SetName(name string) {
+	mm.name = name
+}
+
+// SetMixArgs sets the arguments of a mix task, for example: deps.get --only prod
+// The task runs in the project's directory before the dependencies are collected, so that the mix.lock is written.
+func (mm *MixModule) SetMixArgs(mixArgs []string) {
+	mm.mixArgs = mixArgs
+}
+
+// Build runs the mix task set by SetMixArgs, if any, and then collects the project's dependencies.
+func (mm *MixModule) Build() error {
+	if len(mm.mixArgs) > 0 {
+		command := exec.Command("mix", mm.mixArgs...)
+		command.Dir = mm.srcPath
+		var err error
+		if mm.commandDuration, err = runModuleCommand(command); err != nil {
+			return fmt.Errorf("failed running 'mix %s': %w", strings.Join(mm.mixArgs, " "), err)
+		}
+	}
+	return mm.CalcDependencies()
+}
+
+// CalcDependencies collects the dependencies locked in the project's mix.lock.
+// The checksums of the Hex packages are the registry checksums recorded in the lockfile, so the packages aren't downloaded.
+// If Mix is installed, the scopes of the dependencies are the Mix environments (prod, dev and test) whose 'mix deps.tree' includes them.
+// Otherwise, the dependencies don't have scopes, and the project's direct dependencies are the locked dependencies which no other dependency requires.
+func (mm *MixModule) CalcDependencies() error {
+	if !mm.containingBuild.buildNameAndNumberProvided() {
+		return errors.New("a build name must be provided in order to collect the project's dependencies")
+	}
+	lockDependencies, err := buildutils.ReadMixLock(mm.srcPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("no mix.lock was found in " + mm.srcPath + ". Run 'mix deps.get' before collecting the dependencies")
+		}
+		return err
+	}
+	if err = mm.setModuleId(); err != nil {
+		return err
+	}
+	environmentsDependencies, directDependencies := mm.getEnvironmentsDependencies(lockDependencies)
+	dependenciesMap := make(map[string]entities.Dependency)
+	dependenciesGraph := make(map[string][]string)
+	for name, lockDependency := range lockDependencies {
+		dependency := entities.Dependency{
+			Id:               lockDependency.Id(),
+			Type:             lockDependency.Type,
+			RemoteRepository: lockDependency.Repository,
+			Checksum:         entities.Checksum{Sha256: lockDependency.Sha256},
+		}
+		dependency.SetResolutionSource(entities.LockfileSource)
+		if lockDependency.Branch != "" {
+			// The locked revision is updated to the branch's head by 'mix deps.update'.
+			dependency.SetMutable()
+		}
+		for _, environment := range buildutils.MixEnvironments {
+			if slices.Contains(environmentsDependencies[environment], name) {
+				dependency.Scopes = append(dependency.Scopes, environment)
+			}
+		}
+		dependenciesMap[dependency.Id] = dependency
+		for _, childName := range lockDependency.Dependencies {
+			if child, ok := lockDependencies[childName]; ok {
+				dependenciesGraph[dependency.Id] = append(dependenciesGraph[dependency.Id], child.Id())
+			}
+		}
+	}
+	for _, name := range directDependencies {
+		if lockDependency, ok := lockDependencies[name]; ok {
+			dependenciesGraph[mm.name] = append(dependenciesGraph[mm.name], lockDependency.Id())
+		}
+	}
+	emptyRequestedBy := [][]string{{}}
+	populateRequestedByField(mm.name, emptyRequestedBy, dependenciesMap, dependenciesGraph)
+	buildInfoModule := entities.Module{Id: mm.name, Type: entities.Hex, Dependencies: dependenciesMapToList(dependenciesMap)}
+	addCommandDuration(&buildInfoModule, mm.commandDuration)
+	return mm.containingBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{buildInfoModule}})
+}
+
+// If the module ID wasn't set, it's the app and the version declared in the project's mix.exs, or the name of the project's directory.
+func (mm *MixModule) setModuleId() error {
+	if mm.name != "" {
+		return nil
+	}
+	projectId, err := buildutils.ReadMixProjectId(mm.srcPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if projectId != "" {
+		mm.name = projectId
+		return nil
+	}
+	mm.name = filepath.Base(mm.srcPath)
+	mm.containingBuild.logger.Debug(fmt.Sprintf("The mix.exs doesn't declare the project's app. Using its directory name: %s as the module name.", mm.name))
+	return nil
+}
+
+// Returns the names of the dependencies of each Mix environment, and the names of the project's direct dependencies, sorted.
+// If Mix isn't installed or 'mix deps.tree' fails, the environments are unknown, and the direct dependencies are derived from the lockfile.
+func (mm *MixModule) getEnvironmentsDependencies(lockDependencies map[string]*buildutils.MixLockDependency) (map[string][]string, []string) {
+	mixExecutable, err := exec.LookPath("mix")
+	if err == nil {
+		var environmentsDependencies map[string][]string
+		var directDependencies []string
+		environmentsDependencies, directDependencies, err = buildutils.GetMixEnvironmentsDependencies(mixExecutable, mm.srcPath)
+		if err == nil {
+			sort.Strings(directDependencies)
+			return environmentsDependencies, directDependencies
+		}
+	}
+	mm.containingBuild.logger.Debug("Collecting the scopes of the Mix dependencies is skipped:", err.Error())
+	required := make(map[string]bool)
+	for _, lockDependency := range lockDependencies {
+		for _, childName := range lockDependency.Dependencies {
+			required[childName] = true
+		}
+	}
+	var directDependencies []string
+	for _, name := range maps.Keys(lockDependencies) {
+		if !required[name] {
+			directDependencies = append(directDependencies, name)
+		}
+	}
+	sort.Strings(directDependencies)
+	return nil, directDependencies
+}
+
+func (mm *MixModule) AddArtifacts(artifacts ...entities.Artifact) error {
+	return mm.containingBuild.AddArtifacts(mm.name, entities.Hex, artifacts...)
+}
@@ -0,0 +1,125 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMixExs = `defmodule MyApp.MixProject do
+  use Mix.Project
+
+  def project do
+    [app: :my_app, version: "0.1.0", deps: deps()]
+  end
+
+  defp deps do
+    [
+      {:plug_cowboy, "~> 2.6"},
+      {:credo, "~> 1.7", only: [:dev, :test], runtime: false}
+    ]
+  end
+end
+`
+
+const testMixLock = `%{
+  "bunt": {:hex, :bunt, "0.2.1", "e2d4792f7bc0ced7583ab54922808919518d0e57ee162901a16a1b6664ef3b14", [:mix], [], "hexpm", "a330bfb4245239787b15005e66ae6845c9cd524a288f0d141c148b02603777a5"},
+  "cowboy": {:hex, :cowboy, "2.10.0", "ff9ffeff91dae4ae270dd975642997afe2a1179d94b1887863e43f681a203e26", [:make, :rebar3], [], "hexpm", "3afdccb7183cc6f143cb14d3cf51fa00e53db9ec80cdcd525482f5e99bc41d6b"},
+  "credo": {:hex, :credo, "1.7.1", "6e26bbcc9e22eefbff7e43188e69924e78818e2fe6282487d0703652bc20fd62", [:mix], [{:bunt, "~> 0.2.1", [hex: :bunt, repo: "hexpm", optional: false]}], "hexpm", "e9871c6095a4c0381c89b6aa98bc6260a8ba6addccf7f6a53da8849c748a58a2"},
+  "plug_cowboy": {:hex, :plug_cowboy, "2.6.1", "9a3bbfceeb65eff5f39dab529e5cd79137ac36e913c02067dba3963a26efe9b2", [:mix], [{:cowboy, "~> 2.7", [hex: :cowboy, repo: "hexpm", optional: false]}], "hexpm", "de36e1a21f451a18b790f37765db198075c25875c64834bcc82d90b309eb6613"},
+}
+`
+
+// A fake mix executable, which prints the dependencies tree of the environment set in MIX_ENV.
+const testMixScript = `#!/bin/sh
+echo 'my_app'
+echo '|-- plug_cowboy ~> 2.6 (Hex package)'
+echo '|   ` + "`" + `-- cowboy ~> 2.7 (Hex package)'
+if [ "$MIX_ENV" != "prod" ]; then
+  echo '` + "`" + `-- credo ~> 1.7 (Hex package)'
+  echo '    ` + "`" + `-- bunt ~> 0.2.1 (Hex package)'
+fi
+`
+
+func TestGenerateBuildInfoForMixProject(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake mix executable is a shell script.")
+	}
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "mix"), []byte(testMixScript), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	buildInfo := collectMixProject(t, createMixProject(t))
+
+	require.Len(t, buildInfo.Modules, 1)
+	module := buildInfo.Modules[0]
+	assert.Equal(t, "my_app:0.1.0", module.Id)
+	assert.Equal(t, entities.Hex, module.Type)
+	dependencies := make(map[string]entities.Dependency)
+	for _, dependency := range module.Dependencies {
+		dependencies[dependency.Id] = dependency
+	}
+	assert.Len(t, dependencies, 4)
+	assert.Equal(t, entities.Dependency{
+		Id:               "cowboy:2.10.0",
+		Type:             "hex",
+		Scopes:           []string{"prod", "dev", "test"},
+		RequestedBy:      [][]string{{"plug_cowboy:2.6.1", "my_app:0.1.0"}},
+		RemoteRepository: "hexpm",
+		Purl:             "pkg:hex/cowboy@2.10.0",
+		Properties:       map[string]string{entities.ResolutionSourceProperty: string(entities.LockfileSource)},
+		Checksum:         entities.Checksum{Sha256: "3afdccb7183cc6f143cb14d3cf51fa00e53db9ec80cdcd525482f5e99bc41d6b"},
+	}, dependencies["cowboy:2.10.0"])
+	assert.Equal(t, []string{"dev", "test"}, dependencies["bunt:0.2.1"].Scopes)
+	assert.Equal(t, [][]string{{"credo:1.7.1", "my_app:0.1.0"}}, dependencies["bunt:0.2.1"].RequestedBy)
+}
+
+func TestGenerateBuildInfoForMixProjectWithoutMix(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	projectDir := createMixProject(t)
+	buildInfo := collectMixProject(t, projectDir)
+
+	require.Len(t, buildInfo.Modules, 1)
+	dependencies := make(map[string]entities.Dependency)
+	for _, dependency := range buildInfo.Modules[0].Dependencies {
+		dependencies[dependency.Id] = dependency
+	}
+	assert.Len(t, dependencies, 4)
+	// Without Mix, the scopes are unknown, and the direct dependencies are those which no other dependency requires.
+	assert.Empty(t, dependencies["plug_cowboy:2.6.1"].Scopes)
+	assert.Equal(t, [][]string{{"my_app:0.1.0"}}, dependencies["plug_cowboy:2.6.1"].RequestedBy)
+	assert.Equal(t, [][]string{{"credo:1.7.1", "my_app:0.1.0"}}, dependencies["bunt:0.2.1"].RequestedBy)
+
+	require.NoError(t, os.Remove(filepath.Join(projectDir, buildutils.MixLockName)))
+	mixBuild, err := NewBuildInfoService().GetOrCreateBuild("build-info-go-test-mix-no-lock", "1")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, mixBuild.Clean())
+	}()
+	mixModule, err := mixBuild.AddMixModule(projectDir)
+	require.NoError(t, err)
+	assert.ErrorContains(t, mixModule.CalcDependencies(), "no mix.lock was found")
+}
+
+func createMixProject(t *testing.T) string {
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, buildutils.MixProjectFileName), []byte(testMixExs), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, buildutils.MixLockName), []byte(testMixLock), 0644))
+	return projectDir
+}
+
+func collectMixProject(t *testing.T, projectDir string) *entities.BuildInfo {
+	mixBuild := createTestBuild(t, "build-info-go-test-mix")
+	mixBuild.SetResolutionAudit(true)
+	mixModule, err := mixBuild.AddMixModule(projectDir)
+	require.NoError(t, err)
+	require.NoError(t, mixModule.CalcDependencies())
+	buildInfo, err := mixBuild.ToBuildInfo()
+	require.NoError(t, err)
+	return buildInfo
+}
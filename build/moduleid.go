@@ -0,0 +1,156 @@
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+)
+
+// The placeholder of the module's type, which can be used in a module ID template, in addition to {group}, {name} and {version}.
+const TypePlaceholder = "{type}"
+
+// ModuleIdFields are the values which replace the placeholders of a module ID template.
+type ModuleIdFields struct {
+	Type string
+	// The group in its ID form, for example: org.jfrog for Maven, or jfrog for an npm scope.
+	Group   string
+	Name    string
+	Version string
+}
+
+// ValidateModuleIdTemplate returns an error if the template contains unknown placeholders, or doesn't contain the {name} placeholder.
+func ValidateModuleIdTemplate(template string) error {
+	for _, placeholder := range deployPathPlaceholderRegex.FindAllString(template, -1) {
+		switch placeholder {
+		case TypePlaceholder, GroupPlaceholder, NamePlaceholder, VersionPlaceholder:
+		default:
+			return fmt.Errorf("the module ID template '%s' contains the unknown placeholder '%s'", template, placeholder)
+		}
+	}
+	if !strings.Contains(template, NamePlaceholder) {
+		return fmt.Errorf("the module ID template '%s' must contain the '%s' placeholder", template, NamePlaceholder)
+	}
+	return nil
+}
+
+// ResolveModuleId replaces the placeholders of the template with the fields.
+// An empty field, such as a missing npm scope, is removed along with the separator before it, or after it if it starts the template.
+// For example, {group}:{name}:{version} is resolved to debug:4.3.4 for a package without a scope.
+func ResolveModuleId(template string, fields ModuleIdFields) string {
+	values := map[string]string{
+		TypePlaceholder:    fields.Type,
+		GroupPlaceholder:   fields.Group,
+		NamePlaceholder:    fields.Name,
+		VersionPlaceholder: fields.Version,
+	}
+	// The template is split to literals and to the values of the placeholders. Nil stands for an empty value.
+	var tokens []*string
+	lastEnd := 0
+	for _, location := range deployPathPlaceholderRegex.FindAllStringIndex(template, -1) {
+		literal := template[lastEnd:location[0]]
+		tokens = append(tokens, &literal)
+		if value := values[template[location[0]:location[1]]]; value != "" {
+			tokens = append(tokens, &value)
+		} else {
+			tokens = append(tokens, nil)
+		}
+		lastEnd = location[1]
+	}
+	literal := template[lastEnd:]
+	tokens = append(tokens, &literal)
+	for i, token := range tokens {
+		if token != nil {
+			continue
+		}
+		// The tokens before and after an empty value are always literals.
+		if before := tokens[i-1]; *before != "" {
+			*before = (*before)[:len(*before)-1]
+		} else if after := tokens[i+1]; *after != "" {
+			*after = (*after)[1:]
+		}
+	}
+	var resolved strings.Builder
+	for _, token := range tokens {
+		if token != nil {
+			resolved.WriteString(*token)
+		}
+	}
+	return resolved.String()
+}
+
+// Returns the fields of the module's ID, in the <group>:<name>:<version> or <name>:<version> format.
+func getModuleIdFields(module *entities.Module) ModuleIdFields {
+	fields := ModuleIdFields{Type: string(module.Type)}
+	parts := strings.Split(module.Id, ":")
+	switch len(parts) {
+	case 1:
+		fields.Name = parts[0]
+	case 2:
+		fields.Name, fields.Version = parts[0], parts[1]
+	default:
+		fields.Group, fields.Name, fields.Version = parts[0], parts[1], parts[2]
+	}
+	return fields
+}
+
+// Replaces the IDs of the modules, whose types have a module ID template, with the resolved templates.
+// The references to the renamed modules in the modules' project dependencies, and in the requestedBy paths of the modules' dependencies, are replaced as well.
+// An error is returned if the templates resolve different modules to the same ID.
+func applyModuleIdTemplates(buildInfo *entities.BuildInfo, templates map[entities.ModuleType]string) error {
+	if len(templates) == 0 {
+		return nil
+	}
+	renamedIds := make(map[string]string)
+	originalIds := make(map[string]string)
+	for i := range buildInfo.Modules {
+		module := &buildInfo.Modules[i]
+		newId := module.Id
+		if template, ok := templates[module.Type]; ok {
+			newId = ResolveModuleId(template, getModuleIdFields(module))
+		}
+		if originalId, exists := originalIds[newId]; exists && originalId != module.Id {
+			return fmt.Errorf("the module ID templates resolve both the '%s' and the '%s' modules to the ID '%s'", originalId, module.Id, newId)
+		}
+		originalIds[newId] = module.Id
+		if newId != module.Id {
+			renamedIds[module.Id] = newId
+		}
+	}
+	if len(renamedIds) == 0 {
+		return nil
+	}
+	for i := range buildInfo.Modules {
+		renameModuleReferences(&buildInfo.Modules[i], renamedIds)
+	}
+	return nil
+}
+
+// Renames the module and its project dependencies. In the requestedBy paths, only the IDs of the module and of its project dependencies are renamed,
+// since other dependencies may have the same IDs as renamed modules, such as the artifacts of other modules of a Maven project.
+func renameModuleReferences(module *entities.Module, renamedIds map[string]string) {
+	moduleRenamedIds := make(map[string]string)
+	if newId, ok := renamedIds[module.Id]; ok {
+		moduleRenamedIds[module.Id] = newId
+		module.Id = newId
+	}
+	for j := range module.Dependencies {
+		dependency := &module.Dependencies[j]
+		if newId, ok := renamedIds[dependency.Id]; ok && dependency.Type == entities.ProjectDependencyType {
+			moduleRenamedIds[dependency.Id] = newId
+			dependency.Id = newId
+		}
+	}
+	if len(moduleRenamedIds) == 0 {
+		return
+	}
+	for j := range module.Dependencies {
+		for _, path := range module.Dependencies[j].RequestedBy {
+			for k, id := range path {
+				if newId, ok := moduleRenamedIds[id]; ok {
+					path[k] = newId
+				}
+			}
+		}
+	}
+}
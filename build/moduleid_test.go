@@ -0,0 +1,69 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveModuleId(t *testing.T) {
+	testCases := []struct {
+		template string
+		fields   ModuleIdFields
+		expected string
+	}{
+		{"{group}:{name}:{version}", ModuleIdFields{Group: "org.jfrog", Name: "build-info", Version: "1.0.0"}, "org.jfrog:build-info:1.0.0"},
+		// Empty fields are removed along with the separator before them, or after them if they start the template.
+		{"{group}:{name}:{version}", ModuleIdFields{Name: "debug", Version: "4.3.4"}, "debug:4.3.4"},
+		{"{name}@{version}", ModuleIdFields{Name: "app"}, "app"},
+		{"{type}/{group}/{name}", ModuleIdFields{Type: "npm", Name: "debug"}, "npm/debug"},
+		{"acme-{group}:{name}", ModuleIdFields{Name: "debug"}, "acme:debug"},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, ResolveModuleId(testCase.template, testCase.fields), testCase.template)
+	}
+
+	assert.NoError(t, ValidateModuleIdTemplate("{type}:{group}:{name}:{version}"))
+	assert.ErrorContains(t, ValidateModuleIdTemplate("{group}:{name}:{classifier}"), "unknown placeholder '{classifier}'")
+	assert.ErrorContains(t, ValidateModuleIdTemplate("{group}:{version}"), "must contain the '{name}' placeholder")
+}
+
+func TestApplyModuleIdTemplates(t *testing.T) {
+	buildInfo := &entities.BuildInfo{Modules: []entities.Module{
+		{Id: "jfrog:web:1.0.0", Type: entities.Npm, Dependencies: []entities.Dependency{
+			{Id: "jfrog:lib:2.0.0", Type: entities.ProjectDependencyType, RequestedBy: [][]string{{"jfrog:web:1.0.0"}}},
+			{Id: "debug:4.3.4", RequestedBy: [][]string{{"jfrog:lib:2.0.0", "jfrog:web:1.0.0"}}},
+		}},
+		{Id: "jfrog:lib:2.0.0", Type: entities.Npm},
+		{Id: "org.jfrog:api:1.0.0", Type: entities.Maven},
+	}}
+	require.NoError(t, applyModuleIdTemplates(buildInfo, map[entities.ModuleType]string{entities.Npm: "{type}/{group}/{name}@{version}"}))
+	assert.Equal(t, "npm/jfrog/web@1.0.0", buildInfo.Modules[0].Id)
+	assert.Equal(t, []entities.Dependency{
+		{Id: "npm/jfrog/lib@2.0.0", Type: entities.ProjectDependencyType, RequestedBy: [][]string{{"npm/jfrog/web@1.0.0"}}},
+		{Id: "debug:4.3.4", RequestedBy: [][]string{{"npm/jfrog/lib@2.0.0", "npm/jfrog/web@1.0.0"}}},
+	}, buildInfo.Modules[0].Dependencies)
+	assert.Equal(t, "npm/jfrog/lib@2.0.0", buildInfo.Modules[1].Id)
+	// Modules without a template are left unchanged.
+	assert.Equal(t, "org.jfrog:api:1.0.0", buildInfo.Modules[2].Id)
+
+	// Different modules can't be resolved to the same ID.
+	buildInfo = &entities.BuildInfo{Modules: []entities.Module{{Id: "jfrog:web:1.0.0", Type: entities.Npm}, {Id: "web:1.0.0", Type: entities.Npm}}}
+	assert.EqualError(t, applyModuleIdTemplates(buildInfo, map[entities.ModuleType]string{entities.Npm: "{name}:{version}"}),
+		"the module ID templates resolve both the 'jfrog:web:1.0.0' and the 'web:1.0.0' modules to the ID 'web:1.0.0'")
+}
+
+func TestSetModuleIdTemplates(t *testing.T) {
+	bld := createTestBuild(t, "module-id-test")
+	require.NoError(t, bld.AddArtifacts("org.jfrog:build-info:1.0.0", entities.Maven, entities.Artifact{Name: "build-info-1.0.0.jar"}))
+	bld.SetDeployPaths(map[entities.ModuleType]DeployPathConfig{entities.Maven: {Repo: "libs-release-local"}})
+	bld.SetModuleIdTemplates(map[entities.ModuleType]string{entities.Maven: "{group}/{name}@{version}"})
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	require.Len(t, buildInfo.Modules, 1)
+	assert.Equal(t, "org.jfrog/build-info@1.0.0", buildInfo.Modules[0].Id)
+	// The deploy paths are resolved from the original module ID.
+	assert.Equal(t, "org/jfrog/build-info/1.0.0/build-info-1.0.0.jar", buildInfo.Modules[0].Artifacts[0].Path)
+}
@@ -0,0 +1,68 @@
+package build
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/slices"
+)
+
+// The build-info property which holds the number of the distinct dependencies resolved from mutable sources. See entities.MutableProperty.
+// The property is added only if there are such dependencies.
+const MutableDependenciesProperty = "buildInfo.mutableDependencies"
+
+// Marks the dependencies which the collectors can't recognize as resolved from mutable sources by themselves,
+// the SNAPSHOT versions of Maven and Gradle and the dependencies downloaded from file: URLs,
+// and summarizes the dependencies marked as resolved from mutable sources in a warning and in the MutableDependenciesProperty.
+func applyMutableDependencies(buildInfo *entities.BuildInfo, logger utils.Log) {
+	var mutableIds []string
+	for i := range buildInfo.Modules {
+		module := &buildInfo.Modules[i]
+		for j := range module.Dependencies {
+			dependency := &module.Dependencies[j]
+			if !isMutableDependency(module.Type, dependency) {
+				continue
+			}
+			dependency.SetMutable()
+			if !slices.Contains(mutableIds, dependency.Id) {
+				mutableIds = append(mutableIds, dependency.Id)
+			}
+		}
+	}
+	if len(mutableIds) == 0 {
+		return
+	}
+	slices.Sort(mutableIds)
+	logger.Warn(fmt.Sprintf("The build depends on %d dependencies resolved from mutable sources, such as local paths, SNAPSHOT versions or Git branches:\n%s",
+		len(mutableIds), strings.Join(mutableIds, "\n")))
+	buildInfo.AddProperties(map[string]string{MutableDependenciesProperty: strconv.Itoa(len(mutableIds))})
+}
+
+// Returns true if the dependency is marked as resolved from a mutable source, or if it's recognized as such by its ID or its remote repository.
+func isMutableDependency(moduleType entities.ModuleType, dependency *entities.Dependency) bool {
+	if dependency.Type == entities.ProjectDependencyType {
+		return false
+	}
+	if dependency.IsMutable() || strings.HasPrefix(dependency.RemoteRepository, "file:") {
+		return true
+	}
+	return (moduleType == entities.Maven || moduleType == entities.Gradle) && isSnapshotVersion(dependency.Id)
+}
+
+// The version of a SNAPSHOT deployed to a repository, which Maven resolves to its timestamp and build number, for example: 1.0-20240102.101010-3
+var timestampedSnapshotRegex = regexp.MustCompile(`-\d{8}\.\d{6}-\d+$`)
+
+// Returns true if the version in the ID of a Maven dependency, <group>:<artifact>:<version>[:<classifier>], is a SNAPSHOT version.
+func isSnapshotVersion(dependencyId string) bool {
+	segments := strings.Split(dependencyId, ":")
+	for _, segment := range segments[1:] {
+		if strings.HasSuffix(strings.ToUpper(segment), "SNAPSHOT") || timestampedSnapshotRegex.MatchString(segment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMutableDependencies(t *testing.T) {
+	buildInfo := &entities.BuildInfo{Modules: []entities.Module{
+		{Id: "com.example:app:1.0", Type: entities.Maven, Dependencies: []entities.Dependency{
+			{Id: "com.example:lib:1.1-SNAPSHOT"},
+			{Id: "com.example:util:2.0-20240102.101010-3"},
+			{Id: "junit:junit:4.13.2"},
+			// Modules of the build aren't dependencies on unpublished code.
+			{Id: "com.example:core:1.0-SNAPSHOT", Type: entities.ProjectDependencyType},
+		}},
+		{Id: "web:1.0.0", Type: entities.Npm, Dependencies: []entities.Dependency{
+			// Marked by the collector.
+			{Id: "utils:1.0.0", Properties: map[string]string{entities.MutableProperty: "true"}},
+			{Id: "tar:1.0.0", RemoteRepository: "file:/tmp/tar-1.0.0.tgz"},
+			// The SNAPSHOT versions are recognized only in Maven and Gradle modules.
+			{Id: "snapshot:1.0.0-SNAPSHOT"},
+		}},
+		{Id: "com.example:other:1.0", Type: entities.Gradle, Dependencies: []entities.Dependency{{Id: "com.example:lib:1.1-SNAPSHOT"}}},
+	}}
+	applyMutableDependencies(buildInfo, utils.NewDefaultLogger(utils.INFO))
+
+	var mutableIds []string
+	for _, module := range buildInfo.Modules {
+		for _, dependency := range module.Dependencies {
+			if dependency.IsMutable() {
+				mutableIds = append(mutableIds, dependency.Id)
+			}
+		}
+	}
+	assert.Equal(t, []string{"com.example:lib:1.1-SNAPSHOT", "com.example:util:2.0-20240102.101010-3", "utils:1.0.0", "tar:1.0.0", "com.example:lib:1.1-SNAPSHOT"}, mutableIds)
+	// The distinct dependencies are counted.
+	assert.Equal(t, "4", buildInfo.Properties[MutableDependenciesProperty])
+
+	// The property isn't added if there are no mutable dependencies.
+	buildInfo = &entities.BuildInfo{Modules: []entities.Module{{Id: "app", Type: entities.Maven, Dependencies: []entities.Dependency{{Id: "junit:junit:4.13.2"}}}}}
+	applyMutableDependencies(buildInfo, utils.NewDefaultLogger(utils.INFO))
+	assert.NotContains(t, buildInfo.Properties, MutableDependenciesProperty)
+	assert.Nil(t, buildInfo.Modules[0].Dependencies[0].Properties)
+}
@@ -2,15 +2,21 @@ package build
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	buildutils "github.com/jfrog/build-info-go/build/utils"
 	"github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/version"
 )
 
-const minSupportedNpmVersion = "5.4.0"
+const (
+	minSupportedNpmVersion = "5.4.0"
+	defaultNpmThreads      = 3
+)
 
 type NpmModule struct {
 	containingBuild  *Build
@@ -19,6 +25,16 @@ type NpmModule struct {
 	executablePath   string
 	npmArgs          []string
 	collectBuildInfo bool
+	// Collect each workspace of the monorepo as a separate module.
+	collectWorkspaces bool
+	// The number of workspaces collected in parallel.
+	threads int
+	// Build the dependencies tree from the lockfile, without running npm.
+	offline bool
+	// Parse the output of 'npm ls' while it's written, and collect the workspaces one at a time.
+	lowMemory bool
+	// The manifests of the project, if they were provided as a payload rather than read from srcPath.
+	manifests *buildutils.NpmManifests
 }
 
 // Pass an empty string for srcPath to find the npm project in the working directory.
@@ -31,32 +47,68 @@ func newNpmModule(srcPath string, containingBuild *Build) (*NpmModule, error) {
 		return nil, errors.New("npm CLI must have version " + minSupportedNpmVersion + " or higher. The current version is: " + npmVersion.GetVersion())
 	}
 
+	srcPath, name, err := findNpmProject(srcPath, npmVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &NpmModule{name: name, srcPath: srcPath, containingBuild: containingBuild, executablePath: executablePath, threads: defaultNpmThreads}, nil
+}
+
+// Pass an empty string for srcPath to find the npm project in the working directory.
+func newOfflineNpmModule(srcPath string, containingBuild *Build) (*NpmModule, error) {
+	srcPath, name, err := findNpmProject(srcPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &NpmModule{name: name, srcPath: srcPath, containingBuild: containingBuild, threads: defaultNpmThreads, offline: true}, nil
+}
+
+func newNpmModuleFromManifests(reader io.Reader, containingBuild *Build) (*NpmModule, error) {
+	manifests, err := buildutils.ReadNpmManifests(reader)
+	if err != nil {
+		return nil, err
+	}
+	packageInfo, err := manifests.ReadPackageInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &NpmModule{name: packageInfo.BuildInfoModuleId(), containingBuild: containingBuild, threads: defaultNpmThreads, offline: true, manifests: manifests}, nil
+}
+
+// Returns the directory of the npm project and its module ID. If srcPath is empty, the project is looked for in the working directory and its parents.
+func findNpmProject(srcPath string, npmVersion *version.Version) (projectPath, moduleId string, err error) {
 	if srcPath == "" {
 		wd, err := os.Getwd()
 		if err != nil {
-			return nil, err
+			return "", "", err
 		}
 		srcPath, err = utils.FindFileInDirAndParents(wd, "package.json")
 		if err != nil {
-			return nil, err
+			return "", "", err
 		}
 	}
 
 	// Read module name
 	packageInfo, err := buildutils.ReadPackageInfoFromPackageJsonIfExists(srcPath, npmVersion)
 	if err != nil {
-		return nil, err
+		return "", "", err
 	}
-	name := packageInfo.BuildInfoModuleId()
-
-	return &NpmModule{name: name, srcPath: srcPath, containingBuild: containingBuild, executablePath: executablePath}, nil
+	return srcPath, packageInfo.BuildInfoModuleId(), nil
 }
 
 func (nm *NpmModule) Build() error {
+	if len(nm.npmArgs) > 0 && nm.offline {
+		return errors.New("npm commands can't run when collecting the dependencies offline. Run the command before collecting the build-info")
+	}
 	if len(nm.npmArgs) > 0 {
 		output, _, err := buildutils.RunNpmCmd(nm.executablePath, nm.srcPath, nm.npmArgs, &utils.NullLog{})
 		if len(output) > 0 {
-			nm.containingBuild.logger.Output(strings.TrimSpace(string(output)))
+			if nm.containingBuild.commandOutput != nil {
+				_, writeErr := fmt.Fprintln(nm.containingBuild.commandOutput, strings.TrimSpace(string(output)))
+				err = errors.Join(err, writeErr)
+			} else {
+				nm.containingBuild.logger.Output(strings.TrimSpace(string(output)))
+			}
 		}
 		if err != nil {
 			return err
@@ -74,16 +126,68 @@ func (nm *NpmModule) CalcDependencies() error {
 	if !nm.containingBuild.buildNameAndNumberProvided() {
 		return errors.New("a build name must be provided in order to collect the project's dependencies")
 	}
-	buildInfoDependencies, err := buildutils.CalculateNpmDependenciesList(nm.executablePath, nm.srcPath, nm.name,
-		buildutils.NpmTreeDepListParam{Args: nm.npmArgs}, true, nm.containingBuild.logger)
+	if nm.collectWorkspaces {
+		if nm.manifests != nil {
+			return errors.New("the workspaces can't be collected from the provided manifests, since the workspaces' package.json files aren't available")
+		}
+		return nm.calcWorkspacesDependencies()
+	}
+	var buildInfoDependencies []entities.Dependency
+	var err error
+	if nm.manifests != nil {
+		buildInfoDependencies, err = buildutils.CalculateNpmDependenciesListFromManifests(nm.manifests, nm.name, nm.containingBuild.logger)
+	} else {
+		buildInfoDependencies, err = buildutils.CalculateNpmDependenciesList(nm.executablePath, nm.srcPath, nm.name,
+			buildutils.NpmTreeDepListParam{Args: nm.npmArgs, IntegrityVerification: nm.containingBuild.integrityVerification, Offline: nm.offline, LowMemory: nm.lowMemory}, true, nm.containingBuild.logger)
+	}
 	if err != nil {
 		return err
 	}
 	buildInfoModule := entities.Module{Id: nm.name, Type: entities.Npm, Dependencies: buildInfoDependencies}
 	buildInfo := &entities.BuildInfo{Modules: []entities.Module{buildInfoModule}}
+	if err = nm.applyScopeRegistries(buildInfo); err != nil {
+		return err
+	}
+	return nm.containingBuild.SaveBuildInfo(buildInfo)
+}
+
+// Collects the dependencies of each workspace declared in the package.json as a separate module, running 'npm ls' for the workspaces in parallel.
+func (nm *NpmModule) calcWorkspacesDependencies() error {
+	workspaces, err := buildutils.GetNpmWorkspaces(nm.srcPath)
+	if err != nil {
+		return err
+	}
+	if len(workspaces) == 0 {
+		return errors.New("no npm workspaces were found in " + nm.srcPath + ". Make sure the 'workspaces' field of its package.json matches the workspaces' directories")
+	}
+	workspacesDependencies, err := buildutils.CalculateNpmWorkspacesDependencies(nm.executablePath, nm.srcPath, workspaces,
+		buildutils.NpmTreeDepListParam{Args: nm.npmArgs, IntegrityVerification: nm.containingBuild.integrityVerification, Offline: nm.offline, LowMemory: nm.lowMemory}, nm.threads, nm.containingBuild.logger)
+	if err != nil {
+		return err
+	}
+	buildInfo := &entities.BuildInfo{}
+	for _, workspace := range workspaces {
+		buildInfo.Modules = append(buildInfo.Modules, entities.Module{Id: workspace.ModuleId, Type: entities.Npm, Dependencies: workspacesDependencies[workspace.ModuleId]})
+	}
+	if err = nm.applyScopeRegistries(buildInfo); err != nil {
+		return err
+	}
 	return nm.containingBuild.SaveBuildInfo(buildInfo)
 }
 
+// Completes the registries of the scoped dependencies with the scope registries configured in the .npmrc files,
+// and warns about the dependencies which were resolved from other registries. See buildutils.ApplyNpmScopeRegistries.
+func (nm *NpmModule) applyScopeRegistries(buildInfo *entities.BuildInfo) error {
+	scopeRegistries, err := buildutils.ReadNpmScopeRegistries(nm.srcPath)
+	if err != nil {
+		return err
+	}
+	for i := range buildInfo.Modules {
+		buildutils.ApplyNpmScopeRegistries(buildInfo.Modules[i].Dependencies, scopeRegistries, nm.containingBuild.logger)
+	}
+	return nil
+}
+
 func (nm *NpmModule) SetName(name string) {
 	nm.name = name
 }
@@ -96,6 +200,22 @@ func (nm *NpmModule) SetCollectBuildInfo(collectBuildInfo bool) {
 	nm.collectBuildInfo = collectBuildInfo
 }
 
+// SetCollectWorkspaces sets whether to collect each workspace of an npm monorepo as a separate module, rather than the root project.
+func (nm *NpmModule) SetCollectWorkspaces(collectWorkspaces bool) {
+	nm.collectWorkspaces = collectWorkspaces
+}
+
+// SetThreads sets the number of workspaces whose dependencies are collected in parallel. The default is 3.
+func (nm *NpmModule) SetThreads(threads int) {
+	nm.threads = threads
+}
+
+// SetLowMemory sets whether to reduce the memory used for collecting the dependencies, for environments with limited memory such as CI containers.
+// The output of 'npm ls' is parsed while it's written rather than read to memory, and the workspaces are collected one at a time.
+func (nm *NpmModule) SetLowMemory(lowMemory bool) {
+	nm.lowMemory = lowMemory
+}
+
 func (nm *NpmModule) AddArtifacts(artifacts ...entities.Artifact) error {
 	return nm.containingBuild.AddArtifacts(nm.name, entities.Npm, artifacts...)
 }
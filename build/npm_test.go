@@ -3,6 +3,7 @@ package build
 import (
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/jfrog/build-info-go/tests"
 	"github.com/jfrog/build-info-go/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var logger = utils.NewDefaultLogger(utils.INFO)
@@ -85,3 +87,25 @@ func TestFilterNpmArgsFlags(t *testing.T) {
 	expected = []string{"--json", "--all"}
 	assert.Equal(t, expected, npmModule.npmArgs)
 }
+
+func TestGenerateBuildInfoFromNpmManifests(t *testing.T) {
+	npmBuild := createTestBuild(t, "build-info-go-test-npm-manifests")
+	manifests := `{
+  "package.json": {"name": "app", "version": "1.0.0", "dependencies": {"ms": "^2.1.0"}},
+  "package-lock.json": {"lockfileVersion": 3, "packages": {"": {"name": "app", "version": "1.0.0", "dependencies": {"ms": "^2.1.0"}}, "node_modules/ms": {"version": "2.1.3", "integrity": "sha512-ms"}}}
+}`
+	npmModule, err := npmBuild.AddNpmModuleFromManifests(strings.NewReader(manifests))
+	require.NoError(t, err)
+	// The workspaces' package.json files aren't provided.
+	npmModule.SetCollectWorkspaces(true)
+	assert.Error(t, npmModule.CalcDependencies())
+	npmModule.SetCollectWorkspaces(false)
+	require.NoError(t, npmModule.CalcDependencies())
+	buildInfo, err := npmBuild.ToBuildInfo()
+	require.NoError(t, err)
+	require.Len(t, buildInfo.Modules, 1)
+	assert.Equal(t, "app:1.0.0", buildInfo.Modules[0].Id)
+	require.Len(t, buildInfo.Modules[0].Dependencies, 1)
+	assert.Equal(t, "ms:2.1.3", buildInfo.Modules[0].Dependencies[0].Id)
+	assert.Equal(t, [][]string{{"app:1.0.0"}}, buildInfo.Modules[0].Dependencies[0].RequestedBy)
+}
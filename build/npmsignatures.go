@@ -0,0 +1,91 @@
+package build
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/parallel"
+)
+
+// The properties of the npm dependencies, which record whether their versions are signed by their registry and have provenance attestations.
+// Their values are "true" or "false".
+const (
+	NpmSignedProperty     = "npm.signed"
+	NpmProvenanceProperty = "npm.provenance"
+)
+
+// NewNpmSignaturesPostProcessor returns a PostProcessor which adds the NpmSignedProperty and NpmProvenanceProperty properties to the dependencies of the npm modules,
+// by the metadata of their versions in the npm registry. Like the 'npm audit signatures' command, it records the signatures and the attestations
+// the registry provides, but it doesn't verify them.
+// The dependencies whose metadata can't be found, for example because they aren't published to the registry, are skipped with a warning.
+// threads - The number of registry requests to send in parallel.
+func NewNpmSignaturesPostProcessor(ctx context.Context, registry string, threads int, logger utils.Log) PostProcessor {
+	return func(buildInfo *entities.BuildInfo) error {
+		return addNpmSignaturesProperties(ctx, buildInfo, registry, threads, logger)
+	}
+}
+
+func addNpmSignaturesProperties(ctx context.Context, buildInfo *entities.BuildInfo, registry string, threads int, logger utils.Log) error {
+	metadata := make(map[string]*utils.PackageMetadata)
+	var dependencyIds []string
+	for _, module := range buildInfo.Modules {
+		if module.Type != entities.Npm {
+			continue
+		}
+		for _, dependency := range module.Dependencies {
+			if _, exist := metadata[dependency.Id]; !exist && dependency.Type != entities.ProjectDependencyType {
+				metadata[dependency.Id] = nil
+				dependencyIds = append(dependencyIds, dependency.Id)
+			}
+		}
+	}
+
+	var metadataLock sync.Mutex
+	endpoints := utils.RegistryEndpoints{Npm: registry}
+	runner := parallel.NewBounedRunner(threads, false)
+	go func() {
+		defer runner.Done()
+		for _, dependencyId := range dependencyIds {
+			dependencyId := dependencyId
+			_, _ = runner.AddTaskWithError(func(int) error {
+				dependencyMetadata, err := utils.GetPackageMetadata(ctx, entities.Npm, dependencyId, endpoints)
+				if err != nil {
+					return err
+				}
+				metadataLock.Lock()
+				defer metadataLock.Unlock()
+				metadata[dependencyId] = dependencyMetadata
+				return nil
+			}, func(err error) {
+				logger.Warn(err.Error())
+			})
+		}
+	}()
+	runner.Run()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for i := range buildInfo.Modules {
+		module := &buildInfo.Modules[i]
+		if module.Type != entities.Npm {
+			continue
+		}
+		for j := range module.Dependencies {
+			dependency := &module.Dependencies[j]
+			dependencyMetadata := metadata[dependency.Id]
+			if dependencyMetadata == nil {
+				continue
+			}
+			if dependency.Properties == nil {
+				dependency.Properties = make(map[string]string)
+			}
+			dependency.Properties[NpmSignedProperty] = strconv.FormatBool(dependencyMetadata.Signed)
+			dependency.Properties[NpmProvenanceProperty] = strconv.FormatBool(dependencyMetadata.ProvenanceAttested)
+		}
+	}
+	return nil
+}
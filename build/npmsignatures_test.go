@@ -0,0 +1,48 @@
+package build
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNpmSignaturesPostProcessor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.EscapedPath() {
+		case "/lodash":
+			body = `{"versions":{"4.17.21":{"dist":{"signatures":[{"keyid":"SHA256:jl3bwswu80PjjokCgh0o2w5c2U4LhQAE57gj9cz1kzA","sig":"MEUCIQ"}]}}}}`
+		case "/@sigstore%2Fcore":
+			body = `{"versions":{"1.1.0":{"dist":{"signatures":[{"keyid":"SHA256:jl3bwswu80PjjokCgh0o2w5c2U4LhQAE57gj9cz1kzA","sig":"MEQCIF"}],"attestations":{"url":"https://registry.npmjs.org/-/npm/v1/attestations/@sigstore%2fcore@1.1.0","provenance":{"predicateType":"https://slsa.dev/provenance/v1"}}}}}}`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	buildInfo := &entities.BuildInfo{Modules: []entities.Module{
+		{Id: "web:1.0.0", Type: entities.Npm, Dependencies: []entities.Dependency{
+			{Id: "lodash:4.17.21"},
+			{Id: "@sigstore/core:1.1.0", Properties: map[string]string{"existing": "value"}},
+			// Isn't published to the registry.
+			{Id: "internal-utils:1.0.0"},
+		}},
+		// The dependencies of other module types are skipped.
+		{Id: "service", Type: entities.Go, Dependencies: []entities.Dependency{{Id: "github.com/jfrog/gofrog:v1.0.0"}}},
+	}}
+	postProcessor := NewNpmSignaturesPostProcessor(context.Background(), server.URL, 2, utils.NewDefaultLogger(utils.INFO))
+	require.NoError(t, postProcessor(buildInfo))
+	dependencies := buildInfo.Modules[0].Dependencies
+	assert.Equal(t, map[string]string{NpmSignedProperty: "true", NpmProvenanceProperty: "false"}, dependencies[0].Properties)
+	assert.Equal(t, map[string]string{"existing": "value", NpmSignedProperty: "true", NpmProvenanceProperty: "true"}, dependencies[1].Properties)
+	assert.Nil(t, dependencies[2].Properties)
+	assert.Nil(t, buildInfo.Modules[1].Dependencies[0].Properties)
+}
@@ -0,0 +1,229 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/parallel"
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyRule is the name of a rule of a Policy.
+type PolicyRule string
+
+const (
+	MaxDependencyAgeRule  PolicyRule = "max-dependency-age"
+	BannedLicenseRule     PolicyRule = "banned-license"
+	BannedDependencyRule  PolicyRule = "banned-dependency"
+	RequiredChecksumsRule PolicyRule = "required-checksums"
+	NpmProvenanceRule     PolicyRule = "npm-provenance"
+	MutableDependencyRule PolicyRule = "mutable-dependency"
+	// Violated by the dependencies whose registry metadata, needed by the age, license or npm provenance rules, couldn't be fetched.
+	MissingMetadataRule PolicyRule = "missing-metadata"
+)
+
+// Policy is a set of rules which the dependencies of a build-info must meet, for example before it's published.
+// The empty rules aren't evaluated.
+type Policy struct {
+	// The maximal age, in days, of the dependencies' versions, from the time in which they were published to their registries.
+	MaxDependencyAgeDays int `yaml:"maxDependencyAgeDays,omitempty"`
+	// Licenses which the dependencies must not declare, for example: [GPL-3.0-only, AGPL-3.0-only]
+	// The licenses are compared case-insensitively to the licenses declared by the dependencies, and to the licenses in their SPDX expressions.
+	BannedLicenses []string `yaml:"bannedLicenses,omitempty"`
+	// Rules matching the dependencies which must not be used, with the same patterns as the rules which exclude dependencies, for example:
+	// bannedDependencies: [{id: "org.apache.logging.log4j:log4j-core:2.14.*"}, {group: "@evil"}]
+	BannedDependencies []DependencyExclusion `yaml:"bannedDependencies,omitempty"`
+	// If true, each dependency must have a SHA-1 or a SHA-256 checksum.
+	RequireChecksums bool `yaml:"requireChecksums,omitempty"`
+	// If true, each dependency of the npm modules must have a provenance attestation in the npm registry.
+	// The NpmProvenanceProperty property of the dependency is used if it was recorded, rather than querying the registry.
+	RequireNpmProvenance bool `yaml:"requireNpmProvenance,omitempty"`
+	// If true, the dependencies must not be resolved from mutable sources, such as local paths, SNAPSHOT versions or Git branches.
+	// See entities.MutableProperty.
+	ForbidMutableDependencies bool `yaml:"forbidMutableDependencies,omitempty"`
+}
+
+// PolicyViolationDetails describes a dependency which violates a rule of a Policy.
+type PolicyViolationDetails struct {
+	Rule         PolicyRule `json:"rule"`
+	ModuleId     string     `json:"module"`
+	DependencyId string     `json:"dependency"`
+	Message      string     `json:"message"`
+}
+
+// ReadPolicy reads a Policy from a YAML file.
+func ReadPolicy(policyPath string) (*Policy, error) {
+	content, err := os.ReadFile(policyPath)
+	if err != nil {
+		return nil, err
+	}
+	policy := &Policy{}
+	if err = yaml.Unmarshal(content, policy); err != nil {
+		return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed to parse the policy file '%s': %w", policyPath, err))
+	}
+	if policy.MaxDependencyAgeDays < 0 {
+		return nil, fmt.Errorf("the maximal dependency age in '%s' must not be negative", policyPath)
+	}
+	for _, bannedDependency := range policy.BannedDependencies {
+		if err = bannedDependency.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid banned dependency rule in '%s': %w", policyPath, err)
+		}
+	}
+	return policy, nil
+}
+
+// Evaluate evaluates the policy's rules against the dependencies of the build-info's modules, and returns the violations,
+// sorted by their modules. The dependencies on other modules of the project (see entities.ProjectDependencyType) are skipped.
+// The age, license and npm provenance rules are evaluated against the metadata of the dependencies in their registries (see utils.GetPackageMetadata),
+// so they're skipped for the dependencies of unsupported module types. The dependencies whose metadata can't be fetched violate the MissingMetadataRule,
+// so that the policy doesn't pass without evaluating them.
+// threads - The number of registry requests to send in parallel.
+func (p *Policy) Evaluate(ctx context.Context, buildInfo *entities.BuildInfo, endpoints utils.RegistryEndpoints, threads int, logger utils.Log) ([]PolicyViolationDetails, error) {
+	var bannedDependencies []dependencyExclusionMatcher
+	for _, bannedDependency := range p.BannedDependencies {
+		matcher, err := bannedDependency.compile()
+		if err != nil {
+			return nil, err
+		}
+		bannedDependencies = append(bannedDependencies, matcher)
+	}
+	metadata, metadataErrors, err := p.getPackagesMetadata(ctx, buildInfo, endpoints, threads, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []PolicyViolationDetails
+	addViolation := func(rule PolicyRule, module *entities.Module, dependency *entities.Dependency, message string) {
+		violations = append(violations, PolicyViolationDetails{Rule: rule, ModuleId: module.Id, DependencyId: dependency.Id, Message: message})
+	}
+	now := time.Now()
+	for i := range buildInfo.Modules {
+		module := &buildInfo.Modules[i]
+		for j := range module.Dependencies {
+			dependency := &module.Dependencies[j]
+			if dependency.Type == entities.ProjectDependencyType {
+				continue
+			}
+			for _, matcher := range bannedDependencies {
+				if matcher.matches(dependency) {
+					addViolation(BannedDependencyRule, module, dependency, "the dependency is banned")
+					break
+				}
+			}
+			if p.RequireChecksums && dependency.Sha1 == "" && dependency.Sha256 == "" {
+				addViolation(RequiredChecksumsRule, module, dependency, "the dependency has no SHA-1 or SHA-256 checksum")
+			}
+			if p.ForbidMutableDependencies && isMutableDependency(module.Type, dependency) {
+				addViolation(MutableDependencyRule, module, dependency, "the dependency is resolved from a mutable source")
+			}
+			key := packageMetadataKey{module.Type, dependency.Id}
+			if metadataErr, failed := metadataErrors[key]; failed {
+				addViolation(MissingMetadataRule, module, dependency, "the metadata of the dependency couldn't be fetched from its registry: "+metadataErr.Error())
+			}
+			dependencyMetadata := metadata[key]
+			if p.RequireNpmProvenance && module.Type == entities.Npm {
+				provenanceAttested, recorded := dependency.Properties[NpmProvenanceProperty]
+				if (recorded && provenanceAttested != "true") || (!recorded && dependencyMetadata != nil && !dependencyMetadata.ProvenanceAttested) {
+					addViolation(NpmProvenanceRule, module, dependency, "the dependency has no provenance attestation")
+				}
+			}
+			if dependencyMetadata == nil {
+				continue
+			}
+			if p.MaxDependencyAgeDays > 0 && !dependencyMetadata.PublishTime.IsZero() {
+				if ageDays := int(now.Sub(dependencyMetadata.PublishTime).Hours() / 24); ageDays > p.MaxDependencyAgeDays {
+					addViolation(MaxDependencyAgeRule, module, dependency, fmt.Sprintf("the dependency was published %d days ago, more than the maximum of %d days", ageDays, p.MaxDependencyAgeDays))
+				}
+			}
+			if bannedLicense := p.findBannedLicense(dependencyMetadata.Licenses); bannedLicense != "" {
+				addViolation(BannedLicenseRule, module, dependency, fmt.Sprintf("the dependency is licensed under the banned license %s", bannedLicense))
+			}
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].ModuleId < violations[j].ModuleId })
+	return violations, nil
+}
+
+// A dependency ID, and the type of the module which depends on it.
+type packageMetadataKey struct {
+	moduleType   entities.ModuleType
+	dependencyId string
+}
+
+// Returns the registry metadata of the build-info's dependencies, if the policy has rules which need it,
+// and the errors of the dependencies whose metadata couldn't be fetched.
+func (p *Policy) getPackagesMetadata(ctx context.Context, buildInfo *entities.BuildInfo, endpoints utils.RegistryEndpoints, threads int, logger utils.Log) (map[packageMetadataKey]*utils.PackageMetadata, map[packageMetadataKey]error, error) {
+	metadata := make(map[packageMetadataKey]*utils.PackageMetadata)
+	metadataErrors := make(map[packageMetadataKey]error)
+	if p.MaxDependencyAgeDays == 0 && len(p.BannedLicenses) == 0 && !p.RequireNpmProvenance {
+		return metadata, metadataErrors, nil
+	}
+	var keys []packageMetadataKey
+	for _, module := range buildInfo.Modules {
+		for _, dependency := range module.Dependencies {
+			key := packageMetadataKey{module.Type, dependency.Id}
+			if _, exist := metadata[key]; !exist && dependency.Type != entities.ProjectDependencyType && p.needsPackageMetadata(module.Type, &dependency) {
+				metadata[key] = nil
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	var metadataLock sync.Mutex
+	runner := parallel.NewBounedRunner(threads, false)
+	go func() {
+		defer runner.Done()
+		for _, key := range keys {
+			key := key
+			_, _ = runner.AddTaskWithError(func(int) error {
+				dependencyMetadata, err := utils.GetPackageMetadata(ctx, key.moduleType, key.dependencyId, endpoints)
+				metadataLock.Lock()
+				defer metadataLock.Unlock()
+				if err != nil {
+					metadataErrors[key] = err
+					return err
+				}
+				metadata[key] = dependencyMetadata
+				return nil
+			}, func(err error) {
+				logger.Debug(err.Error())
+			})
+		}
+	}()
+	runner.Run()
+	return metadata, metadataErrors, ctx.Err()
+}
+
+// Returns true if the rules of the policy which are evaluated against the dependency need its registry metadata.
+func (p *Policy) needsPackageMetadata(moduleType entities.ModuleType, dependency *entities.Dependency) bool {
+	if p.MaxDependencyAgeDays > 0 || len(p.BannedLicenses) > 0 {
+		return true
+	}
+	_, provenanceRecorded := dependency.Properties[NpmProvenanceProperty]
+	return moduleType == entities.Npm && !provenanceRecorded
+}
+
+// Returns the first banned license which is one of the licenses, or which is a part of one of their SPDX expressions, such as: (MIT OR GPL-3.0-only)
+func (p *Policy) findBannedLicense(licenses []string) string {
+	for _, license := range licenses {
+		identifiers := strings.FieldsFunc(license, func(r rune) bool { return r == '(' || r == ')' || r == ' ' })
+		for _, bannedLicense := range p.BannedLicenses {
+			if strings.EqualFold(license, bannedLicense) {
+				return bannedLicense
+			}
+			for _, identifier := range identifiers {
+				if strings.EqualFold(identifier, bannedLicense) {
+					return bannedLicense
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+package build
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPolicy(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(policyPath, []byte(`
+maxDependencyAgeDays: 365
+bannedLicenses: [GPL-3.0-only]
+bannedDependencies:
+  - id: "event-stream:*"
+requireChecksums: true
+`), 0644))
+	policy, err := ReadPolicy(policyPath)
+	require.NoError(t, err)
+	assert.Equal(t, &Policy{
+		MaxDependencyAgeDays: 365,
+		BannedLicenses:       []string{"GPL-3.0-only"},
+		BannedDependencies:   []DependencyExclusion{{Id: "event-stream:*"}},
+		RequireChecksums:     true,
+	}, policy)
+
+	require.NoError(t, os.WriteFile(policyPath, []byte(`bannedDependencies: [{regex: true}]`), 0644))
+	_, err = ReadPolicy(policyPath)
+	assert.ErrorContains(t, err, "invalid banned dependency rule")
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	recent := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.EscapedPath() {
+		case "/npm/lodash":
+			body = `{"time":{"4.17.21":"` + recent + `"},"versions":{"4.17.21":{"license":"MIT"}}}`
+		case "/npm/left-pad":
+			body = `{"time":{"1.3.0":"2018-04-09T00:00:00.000Z"},"versions":{"1.3.0":{"license":"(MIT OR GPL-3.0-only)"}}}`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	buildInfo := &entities.BuildInfo{Modules: []entities.Module{
+		{Id: "web:1.0.0", Type: entities.Npm, Dependencies: []entities.Dependency{
+			{Id: "lodash:4.17.21", Checksum: entities.Checksum{Sha1: "sha1"}},
+			{Id: "left-pad:1.3.0", Checksum: entities.Checksum{Sha256: "sha256"}},
+			// Isn't published to the registry, so its metadata can't be fetched.
+			{Id: "event-stream:3.3.6", Checksum: entities.Checksum{Md5: "md5"}},
+			// Dependencies on other modules are skipped.
+			{Id: "core:1.0.0", Type: entities.ProjectDependencyType},
+		}},
+	}}
+	policy := &Policy{
+		MaxDependencyAgeDays: 365,
+		BannedLicenses:       []string{"gpl-3.0-only"},
+		BannedDependencies:   []DependencyExclusion{{Id: "event-stream:*"}},
+		RequireChecksums:     true,
+	}
+	endpoints := utils.RegistryEndpoints{Npm: server.URL + "/npm"}
+	violations, err := policy.Evaluate(context.Background(), buildInfo, endpoints, 2, utils.NewDefaultLogger(utils.INFO))
+	require.NoError(t, err)
+	var rules []PolicyRule
+	for _, violation := range violations {
+		assert.Equal(t, "web:1.0.0", violation.ModuleId)
+		rules = append(rules, violation.Rule)
+		switch violation.Rule {
+		case MaxDependencyAgeRule, BannedLicenseRule:
+			assert.Equal(t, "left-pad:1.3.0", violation.DependencyId)
+		case BannedDependencyRule, RequiredChecksumsRule, MissingMetadataRule:
+			assert.Equal(t, "event-stream:3.3.6", violation.DependencyId)
+		}
+	}
+	assert.ElementsMatch(t, []PolicyRule{MaxDependencyAgeRule, BannedLicenseRule, BannedDependencyRule, RequiredChecksumsRule, MissingMetadataRule}, rules)
+
+	// The recorded provenance properties are used rather than the registry metadata, which has no attestations.
+	buildInfo.Modules[0].Dependencies[0].Properties = map[string]string{NpmProvenanceProperty: "true"}
+	violations, err = (&Policy{RequireNpmProvenance: true}).Evaluate(context.Background(), buildInfo, endpoints, 2, utils.NewDefaultLogger(utils.INFO))
+	require.NoError(t, err)
+	require.Len(t, violations, 2)
+	assert.Equal(t, PolicyViolationDetails{Rule: NpmProvenanceRule, ModuleId: "web:1.0.0", DependencyId: "left-pad:1.3.0", Message: "the dependency has no provenance attestation"}, violations[0])
+	// The policy fails for the dependencies whose metadata can't be fetched, rather than skipping their rules.
+	assert.Equal(t, MissingMetadataRule, violations[1].Rule)
+	assert.Equal(t, "event-stream:3.3.6", violations[1].DependencyId)
+	assert.Contains(t, violations[1].Message, "the metadata of the dependency couldn't be fetched from its registry")
+
+	// Dependencies marked as mutable by their collectors, and SNAPSHOT versions of Maven dependencies, are resolved from mutable sources.
+	buildInfo.Modules[0].Dependencies[1].SetMutable()
+	buildInfo.Modules = append(buildInfo.Modules, entities.Module{Id: "com.example:app:1.0", Type: entities.Maven, Dependencies: []entities.Dependency{
+		{Id: "com.example:lib:1.1-SNAPSHOT"},
+		{Id: "junit:junit:4.13.2"},
+	}})
+	violations, err = (&Policy{ForbidMutableDependencies: true}).Evaluate(context.Background(), buildInfo, utils.RegistryEndpoints{}, 2, utils.NewDefaultLogger(utils.INFO))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []PolicyViolationDetails{
+		{Rule: MutableDependencyRule, ModuleId: "web:1.0.0", DependencyId: "left-pad:1.3.0", Message: "the dependency is resolved from a mutable source"},
+		{Rule: MutableDependencyRule, ModuleId: "com.example:app:1.0", DependencyId: "com.example:lib:1.1-SNAPSHOT", Message: "the dependency is resolved from a mutable source"},
+	}, violations)
+
+	// An empty policy has no violations, and doesn't query the registries.
+	violations, err = (&Policy{}).Evaluate(context.Background(), buildInfo, utils.RegistryEndpoints{}, 2, utils.NewDefaultLogger(utils.INFO))
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
@@ -0,0 +1,79 @@
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+)
+
+// PostProcessor mutates the build-info created by ToBuildInfo(), before it's returned.
+type PostProcessor func(buildInfo *entities.BuildInfo) error
+
+func applyPostProcessors(buildInfo *entities.BuildInfo, postProcessors []PostProcessor) error {
+	for _, postProcessor := range postProcessors {
+		if err := postProcessor(buildInfo); err != nil {
+			return fmt.Errorf("failed post-processing the build-info: %w", err)
+		}
+	}
+	return nil
+}
+
+// NewScriptPostProcessor returns a PostProcessor which writes the build-info as JSON to the standard input of a script,
+// and replaces the build-info with the JSON the script writes to its standard output.
+// A .jq script is run with 'jq -f', a .go file is run with 'go run', and any other script is run as an executable.
+func NewScriptPostProcessor(scriptPath string) (PostProcessor, error) {
+	scriptPath, err := filepath.Abs(scriptPath)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := utils.IsFileExists(scriptPath, true)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("the post-processing script " + scriptPath + " doesn't exist")
+	}
+	return func(buildInfo *entities.BuildInfo) error {
+		return runPostProcessingScript(scriptPath, buildInfo)
+	}, nil
+}
+
+func runPostProcessingScript(scriptPath string, buildInfo *entities.BuildInfo) error {
+	input, err := json.Marshal(buildInfo)
+	if err != nil {
+		return err
+	}
+	var command *exec.Cmd
+	switch filepath.Ext(scriptPath) {
+	case ".jq":
+		command = exec.Command("jq", "-f", scriptPath)
+	case ".go":
+		command = exec.Command("go", "run", scriptPath)
+	default:
+		command = exec.Command(scriptPath)
+	}
+	var stdout, stderr bytes.Buffer
+	command.Stdin = bytes.NewReader(input)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err = command.Run(); err != nil {
+		return fmt.Errorf("the script %s failed: %w\n%s", scriptPath, err, strings.TrimSpace(stderr.String()))
+	}
+	output := bytes.TrimSpace(stdout.Bytes())
+	if len(output) == 0 {
+		return errors.New("the script " + scriptPath + " didn't write a build-info to its standard output")
+	}
+	processed := new(entities.BuildInfo)
+	if err = json.Unmarshal(output, processed); err != nil {
+		return fmt.Errorf("the output of the script %s isn't a valid build-info: %w", scriptPath, err)
+	}
+	*buildInfo = *processed
+	return nil
+}
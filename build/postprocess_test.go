@@ -0,0 +1,92 @@
+package build
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostProcessors(t *testing.T) {
+	bld := createTestBuild(t, "build-info-go-test-post-process")
+	require.NoError(t, bld.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{
+		{Id: "app", Type: entities.Npm, Dependencies: []entities.Dependency{{Id: "lodash:4.17.21"}, {Id: "internal-fixtures:1.0.0"}}},
+	}}))
+
+	// The post-processors are called in the order they were added.
+	bld.AddPostProcessors(func(buildInfo *entities.BuildInfo) error {
+		buildInfo.Modules[0].Id = "my-app"
+		return nil
+	}, func(buildInfo *entities.BuildInfo) error {
+		buildInfo.Modules[0].Id += ":1.0.0"
+		buildInfo.Modules[0].Dependencies = buildInfo.Modules[0].Dependencies[:1]
+		return nil
+	})
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	require.Len(t, buildInfo.Modules, 1)
+	assert.Equal(t, "my-app:1.0.0", buildInfo.Modules[0].Id)
+	require.Len(t, buildInfo.Modules[0].Dependencies, 1)
+	assert.Equal(t, "lodash:4.17.21", buildInfo.Modules[0].Dependencies[0].Id)
+
+	bld.AddPostProcessors(func(*entities.BuildInfo) error {
+		return errors.New("no modules to rename")
+	})
+	_, err = bld.ToBuildInfo()
+	assert.ErrorContains(t, err, "failed post-processing the build-info: no modules to rename")
+}
+
+func TestPostProcessorsCustomModuleType(t *testing.T) {
+	bld := createTestBuild(t, "build-info-go-test-custom-type")
+	require.NoError(t, bld.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{{Id: "app", Type: entities.Npm}}}))
+	bld.AddPostProcessors(func(buildInfo *entities.BuildInfo) error {
+		buildInfo.Modules[0].Type = "my-type"
+		return nil
+	})
+	_, err := bld.ToBuildInfo()
+	assert.ErrorContains(t, err, "the build-info has modules of unknown types: 'my-type' (module 'app')")
+
+	bld.SetAllowCustomModuleTypes(true)
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	assert.Equal(t, entities.ModuleType("my-type"), buildInfo.Modules[0].Type)
+}
+
+func TestScriptPostProcessor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test script is a shell script.")
+	}
+	scriptPath := filepath.Join(t.TempDir(), "post-process.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/sh\nsed 's/\"app\"/\"renamed-app\"/'\n"), 0755))
+	postProcessor, err := NewScriptPostProcessor(scriptPath)
+	require.NoError(t, err)
+	buildInfo := &entities.BuildInfo{Name: "build", Modules: []entities.Module{{Id: "app"}}}
+	require.NoError(t, postProcessor(buildInfo))
+	assert.Equal(t, &entities.BuildInfo{Name: "build", Modules: []entities.Module{{Id: "renamed-app"}}}, buildInfo)
+
+	// A script whose output isn't a build-info fails.
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/sh\necho done\n"), 0755))
+	assert.ErrorContains(t, postProcessor(buildInfo), "isn't a valid build-info")
+
+	_, err = NewScriptPostProcessor(filepath.Join(t.TempDir(), "missing.jq"))
+	assert.ErrorContains(t, err, "doesn't exist")
+}
+
+func TestJqPostProcessor(t *testing.T) {
+	if _, err := exec.LookPath("jq"); err != nil {
+		t.Skip("jq is not installed.")
+	}
+	scriptPath := filepath.Join(t.TempDir(), "post-process.jq")
+	require.NoError(t, os.WriteFile(scriptPath, []byte(`.modules[].properties["team"] = "platform"`), 0644))
+	postProcessor, err := NewScriptPostProcessor(scriptPath)
+	require.NoError(t, err)
+	buildInfo := &entities.BuildInfo{Modules: []entities.Module{{Id: "app"}}}
+	require.NoError(t, postProcessor(buildInfo))
+	assert.Equal(t, map[string]interface{}{"team": "platform"}, buildInfo.Modules[0].Properties)
+}
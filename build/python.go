@@ -1,11 +1,17 @@
 package build
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/build-info-go/utils/pythonutils"
+	"golang.org/x/exp/slices"
 )
 
 type PythonModule struct {
@@ -15,6 +21,8 @@ type PythonModule struct {
 	srcPath                    string
 	localDependenciesPath      string
 	updateDepsChecksumInfoFunc func(dependenciesMap map[string]entities.Dependency, srcPath string) error
+	// If true, each package of a repository with several packages is collected as a separate module.
+	collectPackages bool
 }
 
 func newPythonModule(srcPath string, tool pythonutils.PythonTool, containingBuild *Build) (*PythonModule, error) {
@@ -37,6 +45,11 @@ func (pm *PythonModule) RunInstallAndCollectDependencies(commandArgs []string) e
 	if err != nil {
 		return fmt.Errorf("failed while attempting to get %s dependencies graph: %s", pm.tool, err.Error())
 	}
+	if pm.tool == pythonutils.Pip {
+		if err = pythonutils.ApplyPipOrigins(pm.srcPath, dependenciesMap, pm.containingBuild.logger); err != nil {
+			return err
+		}
+	}
 
 	packageId := pm.SetModuleId()
 
@@ -46,13 +59,117 @@ func (pm *PythonModule) RunInstallAndCollectDependencies(commandArgs []string) e
 			return err
 		}
 	}
+	if err = pm.verifyPoetryLockIntegrity(dependenciesMap); err != nil {
+		return err
+	}
+	if pm.collectPackages {
+		return pm.buildPackages(dependenciesMap, dependenciesGraph)
+	}
 	pythonutils.UpdateDepsIdsAndRequestedBy(dependenciesMap, dependenciesGraph, topLevelPackagesList, packageId, pm.id)
 	buildInfoModule := entities.Module{Id: pm.id, Type: entities.Python, Dependencies: dependenciesMapToList(dependenciesMap)}
+	if buildInfoModule.Artifacts, err = pm.getDistributionArtifacts(pm.srcPath, packageId); err != nil {
+		return err
+	}
 	buildInfo := &entities.BuildInfo{Modules: []entities.Module{buildInfoModule}}
 
 	return pm.containingBuild.SaveBuildInfo(buildInfo)
 }
 
+// Returns the artifacts of the package's wheels and source distributions, which were built into its 'dist' directory, for example by 'python -m build'.
+// Like the artifacts uploaded by twine, their paths are their deploy paths in a PyPI repository.
+// The files ignored by the .biignore file in the package's directory are skipped.
+func (pm *PythonModule) getDistributionArtifacts(srcPath, packageId string) ([]entities.Artifact, error) {
+	packageName, packageVersion, found := strings.Cut(packageId, ":")
+	if !found {
+		return nil, nil
+	}
+	distributionFiles, err := pythonutils.GetDistributionFiles(srcPath, packageName, packageVersion)
+	if err != nil {
+		return nil, err
+	}
+	if distributionFiles, err = pm.containingBuild.filterIgnoredArtifactFiles(srcPath, distributionFiles); err != nil {
+		return nil, err
+	}
+	var artifacts []entities.Artifact
+	for _, distributionFile := range distributionFiles {
+		checksum, size, err := pm.containingBuild.getArtifactFileDetails(distributionFile)
+		if err != nil {
+			return nil, err
+		}
+		pm.containingBuild.logger.Debug("Adding the distribution", distributionFile, "to the artifacts of", packageId)
+		artifacts = append(artifacts, entities.Artifact{
+			Name:     filepath.Base(distributionFile),
+			Type:     strings.TrimPrefix(filepath.Ext(distributionFile), "."),
+			Path:     path.Join(packageName, packageVersion, filepath.Base(distributionFile)),
+			Size:     size,
+			Checksum: checksum,
+		})
+	}
+	return artifacts, nil
+}
+
+// Saves a module for each of the packages found under the source path, including the root package, if there is one.
+// The dependencies are installed and resolved once for the whole repository, and attributed to the packages which require them.
+// Since Poetry resolves each package separately, the graphs of the packages' poetry.lock files are merged.
+func (pm *PythonModule) buildPackages(dependenciesMap map[string]entities.Dependency, dependenciesGraph map[string][]string) error {
+	packages, err := pythonutils.FindPythonPackages(pm.tool, pm.srcPath, pm.containingBuild.logger)
+	if err != nil {
+		return err
+	}
+	if len(packages) == 0 {
+		return errors.New("no Python packages were found in " + pm.srcPath + ". Make sure each package has a pyproject.toml or a setup.py file")
+	}
+	if pm.tool == pythonutils.Poetry {
+		for _, pkg := range packages {
+			if pkg.Path == pm.srcPath {
+				continue
+			}
+			packageGraph, _, err := pythonutils.GetPythonDependencies(pm.tool, pkg.Path, pm.localDependenciesPath, pm.containingBuild.logger)
+			if err != nil {
+				return fmt.Errorf("failed while attempting to get the %s dependencies graph of %s: %s", pm.tool, pkg.Path, err.Error())
+			}
+			for node, children := range packageGraph {
+				for _, child := range children {
+					if !slices.Contains(dependenciesGraph[node], child) {
+						dependenciesGraph[node] = append(dependenciesGraph[node], child)
+					}
+				}
+			}
+		}
+	}
+	packagesDependencies := pythonutils.GetPackagesDependencies(packages, dependenciesMap, dependenciesGraph)
+	buildInfo := &entities.BuildInfo{}
+	for _, pkg := range packages {
+		artifacts, err := pm.getDistributionArtifacts(pkg.Path, pkg.Id)
+		if err != nil {
+			return err
+		}
+		buildInfo.Modules = append(buildInfo.Modules, entities.Module{Id: pkg.Id, Type: entities.Python, Artifacts: artifacts, Dependencies: dependenciesMapToList(packagesDependencies[pkg.Id])})
+	}
+	return pm.containingBuild.SaveBuildInfo(buildInfo)
+}
+
+// Verifies the SHA-256 checksums of the dependencies against the hashes in poetry.lock.
+// Dependencies without a checksum, or which aren't listed in poetry.lock, are skipped.
+func (pm *PythonModule) verifyPoetryLockIntegrity(dependenciesMap map[string]entities.Dependency) error {
+	if pm.tool != pythonutils.Poetry || pm.containingBuild.integrityVerification == utils.IntegrityVerificationOff {
+		return nil
+	}
+	lockHashes, err := pythonutils.GetPoetryLockHashes(pm.srcPath)
+	if err != nil {
+		return err
+	}
+	var mismatches []utils.IntegrityMismatchDetails
+	for name, dependency := range dependenciesMap {
+		expectedHashes, ok := lockHashes[strings.ToLower(name)]
+		if !ok || dependency.Sha256 == "" || slices.Contains(expectedHashes, dependency.Sha256) {
+			continue
+		}
+		mismatches = append(mismatches, utils.IntegrityMismatchDetails{DependencyId: dependency.Id, Lockfile: "poetry.lock", Expected: "sha256:" + strings.Join(expectedHashes, " or sha256:"), Actual: "sha256:" + dependency.Sha256})
+	}
+	return pm.containingBuild.integrityVerification.HandleMismatches(mismatches, pm.containingBuild.logger)
+}
+
 // Sets the module ID and returns the package ID (if found).
 func (pm *PythonModule) SetModuleId() (packageId string) {
 	packageId, pkgNameErr := pythonutils.GetPackageName(pm.tool, pm.srcPath)
@@ -85,6 +202,12 @@ func (pm *PythonModule) SetLocalDependenciesPath(localDependenciesPath string) {
 	pm.localDependenciesPath = localDependenciesPath
 }
 
+// SetCollectPackages sets whether to collect each package of a repository with several packages (each with its own pyproject.toml or setup.py)
+// as a separate module, rather than the project in the source path. The module ID set by SetName is ignored in that case.
+func (pm *PythonModule) SetCollectPackages(collectPackages bool) {
+	pm.collectPackages = collectPackages
+}
+
 func (pm *PythonModule) SetUpdateDepsChecksumInfoFunc(updateDepsChecksumInfoFunc func(dependenciesMap map[string]entities.Dependency, srcPath string) error) {
 	pm.updateDepsChecksumInfoFunc = updateDepsChecksumInfoFunc
 }
@@ -0,0 +1,79 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+	"golang.org/x/exp/slices"
+)
+
+// The source type of a Release Bundle v2 which is created from a list of artifacts.
+const releaseBundleArtifactsSource = "artifacts"
+
+// ReleaseBundleSpec is the specification of a Release Bundle v2 which is created from artifacts,
+// in the format of the request body of the JFrog Platform's 'Create Release Bundle v2 Version' API.
+type ReleaseBundleSpec struct {
+	Name       string              `json:"release_bundle_name"`
+	Version    string              `json:"release_bundle_version"`
+	SourceType string              `json:"source_type"`
+	Source     ReleaseBundleSource `json:"source"`
+}
+
+type ReleaseBundleSource struct {
+	Artifacts []ReleaseBundleArtifact `json:"artifacts"`
+}
+
+type ReleaseBundleArtifact struct {
+	// The artifact's path in Artifactory, including its repository, for example: libs-release-local/org/example/app/1.0/app-1.0.jar
+	Path   string `json:"path"`
+	Sha256 string `json:"sha256,omitempty"`
+}
+
+// NewReleaseBundleSpec converts the artifacts of the build-info's modules to the specification of a Release Bundle v2.
+// The artifacts' paths are relative to their original deployment repositories (see SetDeployPaths).
+// defaultRepo - The repository of the artifacts without an original deployment repository. If empty, such artifacts fail the conversion.
+// If the name or the version of the Release Bundle are empty, the name and the number of the build are used.
+func NewReleaseBundleSpec(buildInfo *entities.BuildInfo, name, version, defaultRepo string) (*ReleaseBundleSpec, error) {
+	if name == "" {
+		name = buildInfo.Name
+	}
+	if version == "" {
+		version = buildInfo.Number
+	}
+	if name == "" || version == "" {
+		return nil, errors.New("a name and a version must be provided in order to create a release bundle specification, since the build-info has no name or number")
+	}
+	spec := &ReleaseBundleSpec{Name: name, Version: version, SourceType: releaseBundleArtifactsSource, Source: ReleaseBundleSource{Artifacts: []ReleaseBundleArtifact{}}}
+	var missingRepoArtifacts []string
+	for _, module := range buildInfo.Modules {
+		for _, artifact := range module.Artifacts {
+			repo := artifact.OriginalDeploymentRepo
+			if repo == "" {
+				repo = defaultRepo
+			}
+			artifactPath := artifact.Path
+			if artifactPath == "" {
+				artifactPath = artifact.Name
+			}
+			if repo == "" {
+				missingRepoArtifacts = append(missingRepoArtifacts, artifactPath)
+				continue
+			}
+			releaseBundleArtifact := ReleaseBundleArtifact{Path: path.Join(repo, strings.TrimPrefix(artifactPath, "/")), Sha256: artifact.Sha256}
+			// The same artifact may be produced by several modules.
+			if !slices.ContainsFunc(spec.Source.Artifacts, func(existing ReleaseBundleArtifact) bool { return existing.Path == releaseBundleArtifact.Path }) {
+				spec.Source.Artifacts = append(spec.Source.Artifacts, releaseBundleArtifact)
+			}
+		}
+	}
+	if len(missingRepoArtifacts) > 0 {
+		return nil, fmt.Errorf("the repositories of the following artifacts are unknown, so a default repository must be provided: %s", strings.Join(missingRepoArtifacts, ", "))
+	}
+	if len(spec.Source.Artifacts) == 0 {
+		return nil, errors.New("the build-info has no artifacts to add to the release bundle")
+	}
+	return spec, nil
+}
@@ -0,0 +1,44 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReleaseBundleSpec(t *testing.T) {
+	buildInfo := &entities.BuildInfo{Name: "my-build", Number: "7", Modules: []entities.Module{
+		{Id: "org.example:app:1.0", Type: entities.Maven, Artifacts: []entities.Artifact{
+			{Name: "app-1.0.jar", Path: "org/example/app/1.0/app-1.0.jar", OriginalDeploymentRepo: "libs-release-local", Checksum: entities.Checksum{Sha256: "sha256-jar"}},
+			{Name: "app-1.0.pom", Path: "org/example/app/1.0/app-1.0.pom", OriginalDeploymentRepo: "libs-release-local"},
+		}},
+		{Id: "dist", Type: entities.Generic, Artifacts: []entities.Artifact{
+			{Name: "app.zip", Path: "dist/app.zip", Checksum: entities.Checksum{Sha256: "sha256-zip"}},
+			// The same artifact of another module.
+			{Name: "app-1.0.jar", Path: "org/example/app/1.0/app-1.0.jar", OriginalDeploymentRepo: "libs-release-local", Checksum: entities.Checksum{Sha256: "sha256-jar"}},
+		}},
+	}}
+	spec, err := NewReleaseBundleSpec(buildInfo, "", "", "generic-local")
+	require.NoError(t, err)
+	assert.Equal(t, &ReleaseBundleSpec{Name: "my-build", Version: "7", SourceType: "artifacts", Source: ReleaseBundleSource{Artifacts: []ReleaseBundleArtifact{
+		{Path: "libs-release-local/org/example/app/1.0/app-1.0.jar", Sha256: "sha256-jar"},
+		{Path: "libs-release-local/org/example/app/1.0/app-1.0.pom"},
+		{Path: "generic-local/dist/app.zip", Sha256: "sha256-zip"},
+	}}}, spec)
+
+	spec, err = NewReleaseBundleSpec(buildInfo, "my-bundle", "1.0.0", "generic-local")
+	require.NoError(t, err)
+	assert.Equal(t, "my-bundle", spec.Name)
+	assert.Equal(t, "1.0.0", spec.Version)
+
+	// The generic artifact has no repository.
+	_, err = NewReleaseBundleSpec(buildInfo, "", "", "")
+	assert.ErrorContains(t, err, "dist/app.zip")
+
+	_, err = NewReleaseBundleSpec(&entities.BuildInfo{Name: "my-build", Number: "7"}, "", "", "")
+	assert.ErrorContains(t, err, "no artifacts")
+	_, err = NewReleaseBundleSpec(&entities.BuildInfo{}, "", "", "")
+	assert.ErrorContains(t, err, "a name and a version must be provided")
+}
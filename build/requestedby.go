@@ -0,0 +1,97 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/slices"
+)
+
+// The build-info property which holds the total number of the requestedBy paths omitted by the RequestedByLimits.
+// The property is added only if paths were omitted. The number of the paths omitted from each dependency is in its entities.OmittedRequestedByProperty.
+const OmittedRequestedByPathsProperty = "buildInfo.omittedRequestedByPaths"
+
+// RequestedByLimits caps the requestedBy paths of each dependency, which may take most of the build-info of large projects,
+// since packages such as lodash are requested by thousands of parents. Zero means no limit.
+type RequestedByLimits struct {
+	// The maximal number of paths kept for each dependency. The shortest paths are kept, in lexical order among the paths of the same length.
+	MaxPaths int `yaml:"maxPaths,omitempty"`
+	// The maximal number of IDs in each path. Longer paths are truncated to the IDs nearest to the dependency.
+	MaxDepth int `yaml:"maxDepth,omitempty"`
+}
+
+// Validate returns an error if one of the limits is negative.
+func (l RequestedByLimits) Validate() error {
+	if l.MaxPaths < 0 || l.MaxDepth < 0 {
+		return errors.New("the requestedBy limits can't be negative")
+	}
+	return nil
+}
+
+func (l RequestedByLimits) isSet() bool {
+	return l.MaxPaths > 0 || l.MaxDepth > 0
+}
+
+// Applies the limits to the requestedBy paths of the modules' dependencies. The paths which become identical once truncated are kept once.
+// The number of the omitted paths is recorded in the dependency's entities.OmittedRequestedByProperty, and their total in the OmittedRequestedByPathsProperty.
+func applyRequestedByLimits(buildInfo *entities.BuildInfo, limits RequestedByLimits, logger utils.Log) {
+	if !limits.isSet() {
+		return
+	}
+	totalOmitted := 0
+	for i := range buildInfo.Modules {
+		for j := range buildInfo.Modules[i].Dependencies {
+			dependency := &buildInfo.Modules[i].Dependencies[j]
+			omitted := len(dependency.RequestedBy)
+			dependency.RequestedBy = limitRequestedBy(dependency.RequestedBy, limits)
+			if omitted -= len(dependency.RequestedBy); omitted == 0 {
+				continue
+			}
+			if dependency.Properties == nil {
+				dependency.Properties = make(map[string]string)
+			}
+			dependency.Properties[entities.OmittedRequestedByProperty] = strconv.Itoa(omitted)
+			totalOmitted += omitted
+		}
+	}
+	if totalOmitted == 0 {
+		return
+	}
+	logger.Debug(fmt.Sprintf("Omitted %d requestedBy paths of the dependencies, which exceeded the limits (max paths: %d, max depth: %d).", totalOmitted, limits.MaxPaths, limits.MaxDepth))
+	buildInfo.AddProperties(map[string]string{OmittedRequestedByPathsProperty: strconv.Itoa(totalOmitted)})
+}
+
+// Returns the paths truncated to the maximal depth, without duplicates, and sorted by their lengths and then lexically, up to the maximal number of paths.
+// Since the selection doesn't depend on the order of the paths, the same paths are kept whichever order the package manager listed them in.
+func limitRequestedBy(requestedBy [][]string, limits RequestedByLimits) [][]string {
+	var limited [][]string
+	keys := make(map[string]bool)
+	for _, path := range requestedBy {
+		if limits.MaxDepth > 0 && len(path) > limits.MaxDepth {
+			path = path[:limits.MaxDepth]
+		}
+		if key := requestedByPathKey(path); !keys[key] {
+			keys[key] = true
+			limited = append(limited, path)
+		}
+	}
+	slices.SortFunc(limited, func(a, b []string) int {
+		if len(a) != len(b) {
+			return len(a) - len(b)
+		}
+		return strings.Compare(requestedByPathKey(a), requestedByPathKey(b))
+	})
+	if limits.MaxPaths > 0 && len(limited) > limits.MaxPaths {
+		limited = limited[:limits.MaxPaths]
+	}
+	return limited
+}
+
+// The IDs can't contain the NUL character, so a path's key is unique.
+func requestedByPathKey(path []string) string {
+	return strings.Join(path, "\x00")
+}
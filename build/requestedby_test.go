@@ -0,0 +1,44 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitRequestedBy(t *testing.T) {
+	requestedBy := [][]string{
+		{"c:1", "b:1", "app:1"},
+		{"d:1", "app:1"},
+		{"c:1", "e:1", "app:1"},
+		{"a:1", "app:1"},
+		{"app:1"},
+	}
+	// The shortest paths are kept, in lexical order among the paths of the same length.
+	assert.Equal(t, [][]string{{"app:1"}, {"a:1", "app:1"}, {"d:1", "app:1"}}, limitRequestedBy(requestedBy, RequestedByLimits{MaxPaths: 3}))
+	// The paths which become identical once truncated are kept once.
+	assert.Equal(t, [][]string{{"app:1"}, {"a:1", "app:1"}, {"c:1", "b:1"}, {"c:1", "e:1"}, {"d:1", "app:1"}}, limitRequestedBy(requestedBy, RequestedByLimits{MaxDepth: 2}))
+	assert.Equal(t, [][]string{{"a:1"}, {"app:1"}}, limitRequestedBy(requestedBy, RequestedByLimits{MaxPaths: 2, MaxDepth: 1}))
+}
+
+func TestApplyRequestedByLimits(t *testing.T) {
+	buildInfo := &entities.BuildInfo{Modules: []entities.Module{{Id: "app:1", Type: entities.Npm, Dependencies: []entities.Dependency{
+		{Id: "lodash:4.17.21", RequestedBy: [][]string{{"b:1", "app:1"}, {"a:1", "app:1"}, {"c:1", "a:1", "app:1"}}},
+		{Id: "debug:4.3.4", RequestedBy: [][]string{{"app:1"}}},
+	}}}}
+	// Without limits, the paths are left unchanged.
+	applyRequestedByLimits(buildInfo, RequestedByLimits{}, &utils.NullLog{})
+	assert.Len(t, buildInfo.Modules[0].Dependencies[0].RequestedBy, 3)
+
+	applyRequestedByLimits(buildInfo, RequestedByLimits{MaxPaths: 1}, &utils.NullLog{})
+	lodash := buildInfo.Modules[0].Dependencies[0]
+	assert.Equal(t, [][]string{{"a:1", "app:1"}}, lodash.RequestedBy)
+	assert.Equal(t, map[string]string{entities.OmittedRequestedByProperty: "2"}, lodash.Properties)
+	assert.Empty(t, buildInfo.Modules[0].Dependencies[1].Properties)
+	assert.Equal(t, "2", buildInfo.Properties[OmittedRequestedByPathsProperty])
+
+	assert.NoError(t, RequestedByLimits{MaxPaths: 10}.Validate())
+	assert.Error(t, RequestedByLimits{MaxDepth: -1}.Validate())
+}
@@ -0,0 +1,267 @@
+package build
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gofrog/crypto"
+	"golang.org/x/exp/slices"
+)
+
+// The directories of the virtual filesystems of Linux, which may exist in the root filesystem of a container.
+var skippedRootfsDirs = []string{"proc", "sys", "dev"}
+
+// The installed packages found in a root filesystem, mapped by their IDs, for each module type.
+type rootfsPackages map[entities.ModuleType]map[string]entities.Dependency
+
+func (rp rootfsPackages) add(moduleType entities.ModuleType, dependency entities.Dependency) {
+	if rp[moduleType] == nil {
+		rp[moduleType] = make(map[string]entities.Dependency)
+	}
+	if _, exist := rp[moduleType][dependency.Id]; !exist {
+		rp[moduleType][dependency.Id] = dependency
+	}
+}
+
+// CollectRootfs collects the packages installed in a root filesystem, such as the filesystem of a container image extracted by 'docker export',
+// rather than running the package managers of a project. This allows attesting what's inside the deliverable images after they're built.
+// The packages are found by their metadata files:
+// npm packages - The package.json files of the packages in node_modules directories.
+// Python packages - The METADATA files of the .dist-info directories in site-packages and dist-packages directories.
+// Java libraries - The pom.properties files of the Maven artifacts packaged in .jar files, which are hashed.
+// A module is saved for each of these module types, whose ID is moduleId followed by the type, for example: my-image/npm
+// If moduleId is empty, the name of the root filesystem's directory is used.
+func (b *Build) CollectRootfs(rootfsPath, moduleId string) error {
+	rootfsPath, err := filepath.Abs(rootfsPath)
+	if err != nil {
+		return err
+	}
+	if moduleId == "" {
+		moduleId = filepath.Base(rootfsPath)
+	}
+	packages := make(rootfsPackages)
+	err = filepath.WalkDir(rootfsPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Files which can't be read, such as files owned by other users, don't fail the collection.
+			b.logger.Debug("Skipping", filePath+":", err.Error())
+			return nil
+		}
+		switch {
+		case entry.IsDir() && filepath.Dir(filePath) == rootfsPath && slices.Contains(skippedRootfsDirs, entry.Name()):
+			return filepath.SkipDir
+		case entry.IsDir() && entry.Name() == "node_modules":
+			b.addRootfsNpmPackages(filePath, packages)
+		case entry.IsDir() && strings.HasSuffix(entry.Name(), ".dist-info"):
+			if parentDir := filepath.Base(filepath.Dir(filePath)); parentDir == "site-packages" || parentDir == "dist-packages" {
+				b.addRootfsPythonPackage(filePath, packages)
+			}
+			return filepath.SkipDir
+		case entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), ".jar"):
+			b.addRootfsJar(filePath, packages)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(packages) == 0 {
+		return errors.New("no installed npm, Python or Java packages were found in " + rootfsPath)
+	}
+	buildInfo := &entities.BuildInfo{}
+	for _, moduleType := range []entities.ModuleType{entities.Npm, entities.Python, entities.Maven} {
+		if len(packages[moduleType]) == 0 {
+			continue
+		}
+		dependencies := dependenciesMapToList(packages[moduleType])
+		sort.Slice(dependencies, func(i, j int) bool { return dependencies[i].Id < dependencies[j].Id })
+		buildInfo.Modules = append(buildInfo.Modules, entities.Module{Id: moduleId + "/" + string(moduleType), Type: moduleType, Dependencies: dependencies})
+	}
+	return b.SaveBuildInfo(buildInfo)
+}
+
+// Adds the packages installed directly in a node_modules directory, including the packages of scopes.
+// The nested node_modules directories are visited separately.
+func (b *Build) addRootfsNpmPackages(nodeModulesPath string, packages rootfsPackages) {
+	entries, err := os.ReadDir(nodeModulesPath)
+	if err != nil {
+		b.logger.Debug("Skipping", nodeModulesPath+":", err.Error())
+		return
+	}
+	var packagesPaths []string
+	for _, entry := range entries {
+		// Hidden entries, such as .bin and .package-lock.json, aren't packages.
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if !strings.HasPrefix(entry.Name(), "@") {
+			packagesPaths = append(packagesPaths, filepath.Join(nodeModulesPath, entry.Name()))
+			continue
+		}
+		scopedEntries, err := os.ReadDir(filepath.Join(nodeModulesPath, entry.Name()))
+		if err != nil {
+			b.logger.Debug("Skipping the scope", entry.Name()+":", err.Error())
+			continue
+		}
+		for _, scopedEntry := range scopedEntries {
+			if scopedEntry.IsDir() {
+				packagesPaths = append(packagesPaths, filepath.Join(nodeModulesPath, entry.Name(), scopedEntry.Name()))
+			}
+		}
+	}
+	for _, packagePath := range packagesPaths {
+		content, err := os.ReadFile(filepath.Join(packagePath, "package.json"))
+		if err != nil {
+			b.logger.Debug("Skipping", packagePath+", since its package.json couldn't be read:", err.Error())
+			continue
+		}
+		var packageJson struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		}
+		if err = json.Unmarshal(content, &packageJson); err != nil || packageJson.Name == "" || packageJson.Version == "" {
+			b.logger.Debug("Skipping", packagePath+", since its package.json has no name or version.")
+			continue
+		}
+		dependency := entities.Dependency{Id: packageJson.Name + ":" + packageJson.Version}
+		dependency.SetResolutionSource(entities.FilesystemSource)
+		packages.add(entities.Npm, dependency)
+	}
+}
+
+// Adds the Python package whose metadata is in a .dist-info directory.
+func (b *Build) addRootfsPythonPackage(distInfoPath string, packages rootfsPackages) {
+	metadataFile, err := os.Open(filepath.Join(distInfoPath, "METADATA"))
+	if err != nil {
+		b.logger.Debug("Skipping", distInfoPath+":", err.Error())
+		return
+	}
+	defer func() {
+		_ = metadataFile.Close()
+	}()
+	var name, version string
+	// The metadata fields are email headers, followed by an empty line and the package's description.
+	scanner := bufio.NewScanner(metadataFile)
+	for scanner.Scan() && scanner.Text() != "" {
+		if value, found := strings.CutPrefix(scanner.Text(), "Name:"); found && name == "" {
+			name = strings.TrimSpace(value)
+		} else if value, found = strings.CutPrefix(scanner.Text(), "Version:"); found && version == "" {
+			version = strings.TrimSpace(value)
+		}
+	}
+	if name == "" || version == "" {
+		b.logger.Debug("Skipping", distInfoPath+", since its METADATA has no name or version.")
+		return
+	}
+	dependency := entities.Dependency{Id: strings.ToLower(name) + ":" + version}
+	dependency.SetResolutionSource(entities.FilesystemSource)
+	packages.add(entities.Python, dependency)
+}
+
+// Adds the Maven artifact packaged in a jar. Jars without a pom.properties file, such as most of the jars built by Gradle, are skipped.
+func (b *Build) addRootfsJar(jarPath string, packages rootfsPackages) {
+	dependencyId, err := readJarMavenId(jarPath)
+	if err != nil {
+		b.logger.Debug("Skipping", jarPath+":", err.Error())
+		return
+	}
+	if dependencyId == "" {
+		b.logger.Debug("Skipping", jarPath+", since it has no Maven coordinates.")
+		return
+	}
+	checksums, err := b.checksumCache.GetFileChecksums(jarPath)
+	if err != nil {
+		b.logger.Debug("Skipping", jarPath+":", err.Error())
+		return
+	}
+	dependency := entities.Dependency{
+		Id:       dependencyId,
+		Type:     "jar",
+		Checksum: entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]},
+	}
+	dependency.SetResolutionSource(entities.FilesystemSource)
+	packages.add(entities.Maven, dependency)
+}
+
+// Returns the group:artifact:version of the Maven artifact packaged in a jar, from its META-INF/maven/<group>/<artifact>/pom.properties file.
+func readJarMavenId(jarPath string) (string, error) {
+	jar, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = jar.Close()
+	}()
+	mavenIds, err := readJarPomProperties(&jar.Reader)
+	if err != nil {
+		return "", err
+	}
+	return getJarOwnMavenId(filepath.Base(jarPath), mavenIds), nil
+}
+
+// Returns the group:artifact:version of the Maven artifacts whose pom.properties files are in the jar.
+func readJarPomProperties(jar *zip.Reader) ([]string, error) {
+	var mavenIds []string
+	for _, file := range jar.File {
+		if !strings.HasPrefix(file.Name, "META-INF/maven/") || path.Base(file.Name) != "pom.properties" {
+			continue
+		}
+		mavenId, err := readPomProperties(file)
+		if err != nil {
+			return nil, err
+		}
+		if mavenId != "" {
+			mavenIds = append(mavenIds, mavenId)
+		}
+	}
+	return mavenIds, nil
+}
+
+// Returns the ID of the jar's own artifact, out of the IDs of the artifacts whose pom.properties files are in the jar.
+// Jars which shade other artifacts have several such files, so the artifact whose ID prefixes the jar's file name is preferred.
+// Returns an empty string if the jar's artifact can't be determined.
+func getJarOwnMavenId(jarName string, mavenIds []string) string {
+	for _, mavenId := range mavenIds {
+		if artifactId := strings.Split(mavenId, ":")[1]; strings.HasPrefix(jarName, artifactId+"-") {
+			return mavenId
+		}
+	}
+	if len(mavenIds) != 1 {
+		return ""
+	}
+	return mavenIds[0]
+}
+
+func readPomProperties(file *zip.File) (string, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	properties := make(map[string]string)
+	for _, line := range strings.Split(string(content), "\n") {
+		if key, value, found := strings.Cut(strings.TrimSpace(line), "="); found && !strings.HasPrefix(key, "#") {
+			properties[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if properties["groupId"] == "" || properties["artifactId"] == "" || properties["version"] == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("%s:%s:%s", properties["groupId"], properties["artifactId"], properties["version"]), nil
+}
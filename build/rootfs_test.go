@@ -0,0 +1,75 @@
+package build
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectRootfs(t *testing.T) {
+	rootfs := filepath.Join(t.TempDir(), "my-image")
+	writeFile := func(filePath, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(rootfs, filepath.Dir(filePath)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(rootfs, filePath), []byte(content), 0644))
+	}
+	writeFile("app/node_modules/express/package.json", `{"name":"express","version":"4.18.2"}`)
+	writeFile("app/node_modules/express/node_modules/debug/package.json", `{"name":"debug","version":"2.6.9"}`)
+	writeFile("app/node_modules/@types/node/package.json", `{"name":"@types/node","version":"20.8.0"}`)
+	writeFile("app/node_modules/.bin/express", "")
+	writeFile("usr/lib/python3/dist-packages/Requests-2.31.0.dist-info/METADATA", "Metadata-Version: 2.1\nName: Requests\nVersion: 2.31.0\n\nName: not-a-field\n")
+	writeFile("usr/lib/python3/dist-packages/broken-1.0.dist-info/METADATA", "Metadata-Version: 2.1\n")
+	// The virtual filesystems of the container are skipped.
+	writeFile("proc/1/root/node_modules/left-pad/package.json", `{"name":"left-pad","version":"1.3.0"}`)
+
+	writeJar := func(filePath string, pomProperties map[string]string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(rootfs, filepath.Dir(filePath)), 0755))
+		jarFile, err := os.Create(filepath.Join(rootfs, filePath))
+		require.NoError(t, err)
+		jar := zip.NewWriter(jarFile)
+		for name, content := range pomProperties {
+			writer, err := jar.Create(name)
+			require.NoError(t, err)
+			_, err = writer.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, jar.Close())
+		require.NoError(t, jarFile.Close())
+	}
+	writeJar("opt/app/lib/guava-32.1.2-jre.jar", map[string]string{
+		"META-INF/maven/com.google.guava/guava/pom.properties": "#Generated by Maven\ngroupId=com.google.guava\nartifactId=guava\nversion=32.1.2-jre\n",
+	})
+	// A jar which shades another artifact.
+	writeJar("opt/app/app-1.0.jar", map[string]string{
+		"META-INF/maven/org.example/app/pom.properties":   "groupId=org.example\nartifactId=app\nversion=1.0\n",
+		"META-INF/maven/org.shaded/shaded/pom.properties": "groupId=org.shaded\nartifactId=shaded\nversion=2.0\n",
+	})
+	// A jar without Maven coordinates.
+	writeJar("opt/app/lib/plain.jar", map[string]string{"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\n"})
+
+	bld := createTestBuild(t, "rootfs-test")
+	require.NoError(t, bld.CollectRootfs(rootfs, ""))
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+
+	dependenciesIds := make(map[string][]string)
+	for _, module := range buildInfo.Modules {
+		for _, dependency := range module.Dependencies {
+			dependenciesIds[module.Id] = append(dependenciesIds[module.Id], dependency.Id)
+			if module.Type == entities.Maven {
+				assert.NotEmpty(t, dependency.Sha256)
+			}
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"my-image/npm":    {"@types/node:20.8.0", "debug:2.6.9", "express:4.18.2"},
+		"my-image/python": {"requests:2.31.0"},
+		"my-image/maven":  {"com.google.guava:guava:32.1.2-jre", "org.example:app:1.0"},
+	}, dependenciesIds)
+
+	assert.ErrorContains(t, bld.CollectRootfs(t.TempDir(), "empty"), "no installed npm, Python or Java packages were found")
+}
@@ -0,0 +1,52 @@
+package build
+
+import (
+	"strconv"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+)
+
+// The prefix of the build properties, which describe the resources of the machine or the container which ran the build.
+const RunnerPropertyPrefix = "buildInfo.runner."
+
+// The build properties which describe the resources of the runner.
+const (
+	RunnerCpusProperty             = RunnerPropertyPrefix + "cpus"
+	RunnerCgroupVersionProperty    = RunnerPropertyPrefix + "cgroupVersion"
+	RunnerCpuLimitProperty         = RunnerPropertyPrefix + "cpuLimit"
+	RunnerMemoryLimitProperty      = RunnerPropertyPrefix + "memoryLimit"
+	RunnerContainerRuntimeProperty = RunnerPropertyPrefix + "containerRuntime"
+)
+
+// CollectRunnerLimits records the CPU and memory limits of the cgroup (v1 or v2) of the process, and the container runtime which runs it,
+// in the build properties, so that the build's performance can be correlated with the sizing of its runner.
+// The number of logical CPUs of the machine is always recorded. The limits are recorded only if they're set, in CPUs and in bytes,
+// and the container runtime only if it's detected.
+func (b *Build) CollectRunnerLimits() error {
+	if !b.buildNameAndNumberProvided() {
+		return nil
+	}
+	limits, err := buildutils.GetRunnerLimits()
+	if err != nil {
+		return err
+	}
+	return b.SavePartialBuildInfo(&entities.Partial{Env: getRunnerLimitsProperties(limits)})
+}
+
+func getRunnerLimitsProperties(limits *buildutils.RunnerLimits) map[string]string {
+	properties := map[string]string{RunnerCpusProperty: strconv.Itoa(limits.Cpus)}
+	if limits.CgroupVersion != 0 {
+		properties[RunnerCgroupVersionProperty] = strconv.Itoa(limits.CgroupVersion)
+	}
+	if limits.CpuLimit != 0 {
+		properties[RunnerCpuLimitProperty] = strconv.FormatFloat(limits.CpuLimit, 'f', -1, 64)
+	}
+	if limits.MemoryLimit != 0 {
+		properties[RunnerMemoryLimitProperty] = strconv.FormatInt(limits.MemoryLimit, 10)
+	}
+	if limits.ContainerRuntime != "" {
+		properties[RunnerContainerRuntimeProperty] = limits.ContainerRuntime
+	}
+	return properties
+}
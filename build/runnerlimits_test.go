@@ -0,0 +1,31 @@
+package build
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectRunnerLimits(t *testing.T) {
+	bld := createTestBuild(t, "runner-limits-build")
+
+	require.NoError(t, bld.CollectRunnerLimits())
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(runtime.NumCPU()), buildInfo.Properties[RunnerCpusProperty])
+}
+
+func TestGetRunnerLimitsProperties(t *testing.T) {
+	assert.Equal(t, map[string]string{RunnerCpusProperty: "8"}, getRunnerLimitsProperties(&buildutils.RunnerLimits{Cpus: 8}))
+	assert.Equal(t, map[string]string{
+		RunnerCpusProperty:             "8",
+		RunnerCgroupVersionProperty:    "2",
+		RunnerCpuLimitProperty:         "1.5",
+		RunnerMemoryLimitProperty:      "2147483648",
+		RunnerContainerRuntimeProperty: "docker",
+	}, getRunnerLimitsProperties(&buildutils.RunnerLimits{Cpus: 8, CgroupVersion: 2, CpuLimit: 1.5, MemoryLimit: 2147483648, ContainerRuntime: "docker"}))
+}
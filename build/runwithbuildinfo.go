@@ -0,0 +1,37 @@
+package build
+
+import (
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+)
+
+// RunAndGetBuildInfo runs the provided collection, such as building a project and collecting its dependencies, in the provided build,
+// which is created in the temp directory of the build service if it doesn't exist yet.
+// It returns the build-info of the build, including the modules which earlier collections saved in it, and the directory in which the build's files are saved.
+// The files are kept, so that the build-info can still be published by the JFrog CLI, or extended by further collections.
+// This is the common entrypoint of the Run<Tool>AndGetBuildInfo helpers, such as RunGradleAndGetBuildInfo,
+// which return the build-info to library consumers, rather than only saving it like the Run<Tool>WithBuildInfo helpers.
+func RunAndGetBuildInfo(buildName, buildNumber string, collect func(bld *Build) error) (*entities.BuildInfo, string, error) {
+	bld, err := collectIntoBuild(buildName, buildNumber, collect)
+	if err != nil {
+		return nil, "", err
+	}
+	buildInfo, err := bld.ToBuildInfo()
+	if err != nil {
+		return nil, "", err
+	}
+	buildDir, err := utils.GetBuildDir(bld.buildName, bld.buildNumber, bld.projectKey, bld.tempDirPath)
+	if err != nil {
+		return nil, "", err
+	}
+	return buildInfo, buildDir, nil
+}
+
+// Gets the build from the temp directory of the build service, or creates it, and runs the collection in it.
+func collectIntoBuild(buildName, buildNumber string, collect func(bld *Build) error) (*Build, error) {
+	bld, err := NewBuildInfoService().GetOrCreateBuild(buildName, buildNumber)
+	if err != nil {
+		return nil, err
+	}
+	return bld, collect(bld)
+}
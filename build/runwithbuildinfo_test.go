@@ -0,0 +1,43 @@
+package build
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAndGetBuildInfo(t *testing.T) {
+	// The build service saves the builds in the system's temp directory.
+	t.Setenv("TMPDIR", t.TempDir())
+	chartDir := t.TempDir()
+	writeHelmChartFile(t, chartDir, "Chart.yaml", "apiVersion: v2\nname: app\nversion: 1.0.0\n")
+	collectChart := func(bld *Build) error {
+		helmModule, err := bld.AddHelmModule(chartDir)
+		if err != nil {
+			return err
+		}
+		return helmModule.CalcDependencies()
+	}
+	buildInfo, buildDir, err := RunAndGetBuildInfo("build-info-go-test-run", "1", collectChart)
+	require.NoError(t, err)
+	assert.Equal(t, "build-info-go-test-run", buildInfo.Name)
+	assert.Equal(t, "1", buildInfo.Number)
+	require.Len(t, buildInfo.Modules, 1)
+	assert.Equal(t, entities.Helm, buildInfo.Modules[0].Type)
+	assert.DirExists(t, filepath.Join(buildDir, "partials"))
+
+	// The files of the build are kept, so the build-info read by later collections includes the module.
+	buildInfo, secondBuildDir, err := RunAndGetBuildInfo("build-info-go-test-run", "1", func(*Build) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, buildDir, secondBuildDir)
+	assert.Len(t, buildInfo.Modules, 1)
+
+	// A failed collection doesn't return a build-info.
+	buildInfo, buildDir, err = RunAndGetBuildInfo("build-info-go-test-run", "2", collectHelm(t.TempDir(), nil))
+	assert.Nil(t, buildInfo)
+	assert.Empty(t, buildDir)
+	assert.ErrorContains(t, err, "no Chart.yaml was found")
+}
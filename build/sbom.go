@@ -0,0 +1,69 @@
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/jfrog/build-info-go/entities"
+)
+
+type SbomFormat string
+
+const (
+	CycloneDxSbom SbomFormat = "cyclonedx"
+	SpdxSbom      SbomFormat = "spdx"
+)
+
+// ImportSbom converts an SBOM file to a build-info module, and adds it to the build.
+// sbomPath - The path of a CycloneDX (JSON or XML) or SPDX (JSON) file.
+// format - The format of the SBOM. If empty, the format is detected from the file's content.
+// moduleId - The ID of the module. If empty, the ID of the component which the SBOM describes is used.
+func (b *Build) ImportSbom(sbomPath string, format SbomFormat, moduleId string) error {
+	content, err := os.ReadFile(sbomPath)
+	if err != nil {
+		return err
+	}
+	content = bytes.TrimSpace(content)
+	if format == "" {
+		format = detectSbomFormat(content)
+	}
+	var module *entities.Module
+	switch format {
+	case CycloneDxSbom:
+		fileFormat := cdx.BOMFileFormatJSON
+		if bytes.HasPrefix(content, []byte("<")) {
+			fileFormat = cdx.BOMFileFormatXML
+		}
+		bom := new(cdx.BOM)
+		if err = cdx.NewBOMDecoder(bytes.NewReader(content), fileFormat).Decode(bom); err != nil {
+			return fmt.Errorf("failed to parse the CycloneDX SBOM %s: %w", sbomPath, err)
+		}
+		module, err = entities.NewModuleFromCycloneDxBom(bom)
+	case SpdxSbom:
+		module, err = entities.NewModuleFromSpdxJson(content)
+	default:
+		return fmt.Errorf("unsupported SBOM format '%s'. Supported formats are '%s' and '%s'", format, CycloneDxSbom, SpdxSbom)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to convert the SBOM %s: %w", sbomPath, err)
+	}
+	if moduleId != "" {
+		module.Id = moduleId
+	}
+	b.logger.Debug(fmt.Sprintf("Imported module %s with %d dependencies from %s", module.Id, len(module.Dependencies), sbomPath))
+	return b.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{*module}})
+}
+
+// SPDX JSON documents are identified by their spdxVersion field. Any other document is assumed to be a CycloneDX SBOM.
+func detectSbomFormat(content []byte) SbomFormat {
+	var document struct {
+		SpdxVersion string `json:"spdxVersion"`
+	}
+	if json.Unmarshal(content, &document) == nil && document.SpdxVersion != "" {
+		return SpdxSbom
+	}
+	return CycloneDxSbom
+}
@@ -0,0 +1,54 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportSbom(t *testing.T) {
+	sbomDir := t.TempDir()
+	cycloneDxPath := filepath.Join(sbomDir, "bom.xml")
+	require.NoError(t, os.WriteFile(cycloneDxPath, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.5" version="1">
+  <metadata>
+    <component type="application" bom-ref="app">
+      <name>app</name>
+      <version>1.0.0</version>
+    </component>
+  </metadata>
+  <components>
+    <component type="library" bom-ref="lib">
+      <name>lib</name>
+      <version>2.0.0</version>
+      <hashes><hash alg="SHA-1">lib-sha1</hash></hashes>
+    </component>
+  </components>
+  <dependencies>
+    <dependency ref="app"><dependency ref="lib"/></dependency>
+  </dependencies>
+</bom>`), 0644))
+	spdxPath := filepath.Join(sbomDir, "sbom.spdx.json")
+	require.NoError(t, os.WriteFile(spdxPath, []byte(`{"spdxVersion": "SPDX-2.3", "documentDescribes": ["SPDXRef-app"], "packages": [{"SPDXID": "SPDXRef-app", "name": "spdx-app", "versionInfo": "3.0.0"}]}`), 0644))
+
+	bld := createTestBuild(t, "import-sbom-test")
+	require.NoError(t, bld.ImportSbom(cycloneDxPath, "", ""))
+	require.NoError(t, bld.ImportSbom(spdxPath, "", "renamed:3.0.0"))
+	assert.Error(t, bld.ImportSbom(spdxPath, CycloneDxSbom, ""))
+	assert.Error(t, bld.ImportSbom(spdxPath, "swid", ""))
+
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	modules := map[string]entities.Module{}
+	for _, module := range buildInfo.Modules {
+		modules[module.Id] = module
+	}
+	require.Contains(t, modules, "app:1.0.0")
+	assert.Equal(t, []entities.Dependency{{Id: "lib:2.0.0", Checksum: entities.Checksum{Sha1: "lib-sha1"}, RequestedBy: [][]string{{"app:1.0.0"}}}}, modules["app:1.0.0"].Dependencies)
+	require.Contains(t, modules, "renamed:3.0.0")
+	assert.Equal(t, entities.Generic, modules["renamed:3.0.0"].Type)
+}
@@ -3,6 +3,7 @@ package build
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"time"
@@ -18,8 +19,10 @@ type BuildInfoService struct {
 	logger      utils.Log
 }
 
+// NewBuildInfoService creates a service whose builds are saved in the jfrog/builds directory, under the directory set by the BI_TMPDIR environment variable,
+// or under the system's temp directory if it isn't set.
 func NewBuildInfoService() *BuildInfoService {
-	return &BuildInfoService{tempDirPath: filepath.Join(os.TempDir(), BuildsTempPath), logger: &utils.NullLog{}}
+	return &BuildInfoService{tempDirPath: filepath.Join(utils.GetTempDirBase(), BuildsTempPath), logger: &utils.NullLog{}}
 }
 
 func (bis *BuildInfoService) SetTempDirPath(tempDirPath string) {
@@ -48,11 +51,19 @@ func (bis *BuildInfoService) GetOrCreateBuildWithProject(buildName, buildNumber,
 	return NewBuild(buildName, buildNumber, buildTime, projectKey, bis.tempDirPath, bis.logger), nil
 }
 
-func getOrCreateBuildGeneralDetails(buildName, buildNumber string, buildTime time.Time, projectKey, buildsDirPath string, log utils.Log) (time.Time, error) {
+func getOrCreateBuildGeneralDetails(buildName, buildNumber string, buildTime time.Time, projectKey, buildsDirPath string, log utils.Log) (_ time.Time, err error) {
 	partialsBuildDir, err := utils.GetPartialsBuildDir(buildName, buildNumber, projectKey, buildsDirPath)
 	if err != nil {
 		return buildTime, err
 	}
+	// Hold the build's lock, so that the bi processes which start collecting the same build concurrently share the build time.
+	unlock, err := utils.LockBuildDir(buildName, buildNumber, projectKey, buildsDirPath)
+	if err != nil {
+		return buildTime, err
+	}
+	defer func() {
+		err = errors.Join(err, unlock())
+	}()
 	detailsFilePath := filepath.Join(partialsBuildDir, BuildInfoDetails)
 	var exists bool
 	exists, err = utils.IsFileExists(detailsFilePath, true)
@@ -78,5 +89,35 @@ func getOrCreateBuildGeneralDetails(buildName, buildNumber string, buildTime tim
 	if err != nil {
 		return buildTime, err
 	}
-	return buildTime, os.WriteFile(detailsFilePath, content.Bytes(), 0600)
+	return buildTime, utils.WriteFileAtomically(detailsFilePath, content.Bytes(), 0600)
+}
+
+// CleanTempFiles removes the files which were left by bi processes that didn't clean up after themselves, for example because they were killed:
+// the directories of the builds in the service's temp directory, and the temporary directories created by utils.CreateTempDir.
+// Only the files which weren't modified in the last olderThan are removed, so that the files of the running processes are kept.
+// The lock files left by killed processes are removed too.
+// Returns the paths of the removed directories.
+func (bis *BuildInfoService) CleanTempFiles(olderThan time.Duration) (removed []string, err error) {
+	entries, err := os.ReadDir(bis.tempDirPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		buildDir := filepath.Join(bis.tempDirPath, entry.Name())
+		var isRemoved bool
+		if isRemoved, err = utils.RemoveDirIfOlder(buildDir, olderThan); err != nil {
+			return
+		}
+		if isRemoved {
+			removed = append(removed, buildDir)
+		}
+	}
+	if err = utils.RemoveOrphanLockFiles(bis.tempDirPath, olderThan); err != nil {
+		return
+	}
+	tempDirs, err := utils.RemoveTempDirs(olderThan)
+	return append(removed, tempDirs...), err
 }
@@ -0,0 +1,65 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBuildInfoServiceInCustomTempDir(t *testing.T) {
+	tempDirBase := t.TempDir()
+	t.Setenv(utils.TempDirEnv, tempDirBase)
+	bld, err := NewBuildInfoService().GetOrCreateBuild("build-info-go-test-custom-temp-dir", "1")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, bld.Clean())
+	}()
+	buildDir, err := utils.GetBuildDir(bld.buildName, bld.buildNumber, bld.projectKey, bld.tempDirPath)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tempDirBase, BuildsTempPath), filepath.Dir(buildDir))
+}
+
+func TestCleanTempFiles(t *testing.T) {
+	t.Setenv(utils.TempDirEnv, t.TempDir())
+	service := NewBuildInfoService()
+	staleBuild, err := service.GetOrCreateBuild("build-info-go-test-stale", "1")
+	require.NoError(t, err)
+	require.NoError(t, staleBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{{Id: "stale"}}}))
+	runningBuild, err := service.GetOrCreateBuild("build-info-go-test-running", "1")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, runningBuild.Clean())
+	}()
+	staleTempDir, err := utils.CreateTempDir()
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, utils.RemoveTempDir(staleTempDir))
+	}()
+
+	// The files of the stale build weren't modified for two hours.
+	staleBuildDir, err := utils.GetBuildDir(staleBuild.buildName, staleBuild.buildNumber, staleBuild.projectKey, staleBuild.tempDirPath)
+	require.NoError(t, err)
+	modified := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, filepath.Walk(staleBuildDir, func(path string, _ os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Chtimes(path, modified, modified)
+	}))
+
+	removed, err := service.CleanTempFiles(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{staleBuildDir}, removed)
+	assert.NoDirExists(t, staleBuildDir)
+	runningBuildDir, err := utils.GetBuildDir(runningBuild.buildName, runningBuild.buildNumber, runningBuild.projectKey, runningBuild.tempDirPath)
+	require.NoError(t, err)
+	assert.DirExists(t, runningBuildDir)
+	// The temporary directory was just created, so it's kept too.
+	assert.DirExists(t, staleTempDir)
+}
@@ -0,0 +1,102 @@
+package build
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+)
+
+// CollectionStats summarizes the work done by the collectors of a build, so that the effect of the cache and parallelism settings can be measured.
+type CollectionStats struct {
+	// The dependencies saved by the collectors, before the build-info is created. A dependency of several modules is counted once per module.
+	Dependencies int `json:"dependencies"`
+	// The dependencies saved with a SHA1 checksum, and those saved without one.
+	ChecksumsComputed int `json:"checksumsComputed"`
+	ChecksumsMissing  int `json:"checksumsMissing"`
+	// The files whose checksums were read from the checksum cache, rather than calculated.
+	ChecksumCacheHits int `json:"checksumCacheHits"`
+	// The projects whose modules were read from the incremental cache, rather than collected.
+	IncrementalCacheHits int `json:"incrementalCacheHits"`
+	// The external commands run by the collectors, such as 'npm ls' or 'mvn install', and their total duration.
+	// The commands run by the process since the build was created are counted, including those of other builds which collect concurrently.
+	Commands         int           `json:"commands"`
+	CommandsDuration time.Duration `json:"commandsDurationNanos"`
+}
+
+func (cs CollectionStats) String() string {
+	return fmt.Sprintf("Dependencies: %d, checksums computed: %d, checksums missing: %d, checksum cache hits: %d, incremental cache hits: %d, commands: %d (%s)",
+		cs.Dependencies, cs.ChecksumsComputed, cs.ChecksumsMissing, cs.ChecksumCacheHits, cs.IncrementalCacheHits, cs.Commands, cs.CommandsDuration.Round(time.Millisecond))
+}
+
+// The counters of a build, shared by the copies of the build which are passed to the collectors.
+type collectionStatsCounter struct {
+	stats CollectionStats
+	// The process-wide counters when the build was created, or when its checksum cache was set.
+	commandsBaseline          utils.CommandStats
+	checksumCacheHitsBaseline int
+	mutex                     sync.Mutex
+}
+
+func newCollectionStatsCounter() *collectionStatsCounter {
+	return &collectionStatsCounter{commandsBaseline: utils.GetCommandStats()}
+}
+
+// The counter is nil in builds which weren't created by NewBuild, whose stats aren't collected.
+func (csc *collectionStatsCounter) addDependencies(dependencies []entities.Dependency) {
+	if csc == nil {
+		return
+	}
+	csc.mutex.Lock()
+	defer csc.mutex.Unlock()
+	for _, dependency := range dependencies {
+		csc.stats.Dependencies++
+		if dependency.Sha1 != "" {
+			csc.stats.ChecksumsComputed++
+		} else {
+			csc.stats.ChecksumsMissing++
+		}
+	}
+}
+
+func (csc *collectionStatsCounter) addModules(modules []entities.Module) {
+	for i := range modules {
+		csc.addDependencies(modules[i].Dependencies)
+	}
+}
+
+func (csc *collectionStatsCounter) setChecksumCacheHitsBaseline(checksumCacheHits int) {
+	if csc == nil {
+		return
+	}
+	csc.mutex.Lock()
+	defer csc.mutex.Unlock()
+	csc.checksumCacheHitsBaseline = checksumCacheHits
+}
+
+func (csc *collectionStatsCounter) addIncrementalCacheHit() {
+	if csc == nil {
+		return
+	}
+	csc.mutex.Lock()
+	defer csc.mutex.Unlock()
+	csc.stats.IncrementalCacheHits++
+}
+
+// CollectionStats returns the statistics of the collectors which added their modules to the build so far.
+// The modules of generated build-info files, such as those of the Maven and Gradle extractors, are counted once their collection completes.
+func (b *Build) CollectionStats() CollectionStats {
+	if b.statsCounter == nil {
+		return CollectionStats{}
+	}
+	b.statsCounter.mutex.Lock()
+	defer b.statsCounter.mutex.Unlock()
+	stats := b.statsCounter.stats
+	stats.ChecksumCacheHits = b.checksumCache.Hits() - b.statsCounter.checksumCacheHitsBaseline
+	commandStats := utils.GetCommandStats()
+	stats.Commands = commandStats.Count - b.statsCounter.commandsBaseline.Count
+	stats.CommandsDuration = commandStats.Duration - b.statsCounter.commandsBaseline.Duration
+	return stats
+}
@@ -0,0 +1,66 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionStats(t *testing.T) {
+	bld := NewBuild("stats-build", "1", time.Now(), "", t.TempDir(), &utils.NullLog{})
+	checksumCache, err := utils.NewChecksumCache(t.TempDir(), 0)
+	require.NoError(t, err)
+	jarPath := filepath.Join(t.TempDir(), "app.jar")
+	require.NoError(t, os.WriteFile(jarPath, []byte("jar"), 0644))
+	// The hits before the cache is set to the build aren't counted.
+	_, err = checksumCache.GetFileChecksums(jarPath)
+	require.NoError(t, err)
+	_, err = checksumCache.GetFileChecksums(jarPath)
+	require.NoError(t, err)
+	bld.SetChecksumCache(checksumCache)
+	_, err = checksumCache.GetFileChecksums(jarPath)
+	require.NoError(t, err)
+
+	module := entities.Module{Id: "app:1.0.0", Dependencies: []entities.Dependency{
+		{Id: "debug:4.3.4", Checksum: entities.Checksum{Sha1: "sha1"}},
+		{Id: "ms:2.1.2"},
+	}}
+	require.NoError(t, bld.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{module}}))
+	require.NoError(t, bld.SavePartialBuildInfo(&entities.Partial{ModuleId: "generic", Dependencies: []entities.Dependency{{Id: "lib.so", Checksum: entities.Checksum{Sha1: "sha1"}}}}))
+	utils.RecordCommand(2 * time.Second)
+	utils.RecordCommand(time.Second)
+
+	stats := bld.CollectionStats()
+	assert.Equal(t, 3, stats.Dependencies)
+	assert.Equal(t, 2, stats.ChecksumsComputed)
+	assert.Equal(t, 1, stats.ChecksumsMissing)
+	assert.Equal(t, 1, stats.ChecksumCacheHits)
+	assert.Zero(t, stats.IncrementalCacheHits)
+	assert.Equal(t, 2, stats.Commands)
+	assert.Equal(t, 3*time.Second, stats.CommandsDuration)
+}
+
+func TestCollectionStatsIncremental(t *testing.T) {
+	projectPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "go.mod"), []byte("module example.com/project\n"), 0644))
+	cacheDir := t.TempDir()
+	collect := func(containingBuild *Build) error {
+		module := entities.Module{Id: "example.com/project", Type: entities.Go, Dependencies: []entities.Dependency{{Id: "dep:1.0.0"}}}
+		return containingBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{module}})
+	}
+	for _, expectedHits := range []int{0, 1} {
+		bld := NewBuild("stats-build", "1", time.Now(), "", t.TempDir(), &utils.NullLog{})
+		bld.SetIncrementalCacheDir(cacheDir)
+		require.NoError(t, bld.CollectIncrementally(projectPath, GoTechnology, collect))
+		// The modules collected by the scratch build of a cache miss are counted once.
+		stats := bld.CollectionStats()
+		assert.Equal(t, 1, stats.Dependencies)
+		assert.Equal(t, expectedHits, stats.IncrementalCacheHits)
+	}
+}
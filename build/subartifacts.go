@@ -0,0 +1,103 @@
+package build
+
+import (
+	"archive/zip"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gofrog/crypto"
+)
+
+// DefaultSubArtifactsPatterns are the patterns of the bundled artifacts in the common packaging formats:
+// the jars and WARs inside WARs, EARs and fat jars, and the wheels and tarballs inside zipped distributions.
+var DefaultSubArtifactsPatterns = []string{"*.jar", "*.war", "*.whl", "*.tgz", "*.tar.gz"}
+
+// GetSubArtifacts returns the entries of a zip-based archive, such as a WAR, an EAR, a jar or a zip, which match the patterns,
+// as artifacts with their checksums and sizes. The entries are read from the archive in memory, without extracting them to disk.
+// The paths of the returned artifacts are the entries' paths in the archive.
+// Patterns without '/' are matched against the entries' file names, and the rest against their paths, in which '**' matches any number of directories.
+// If no patterns are provided, DefaultSubArtifactsPatterns are used. Nested archives aren't opened, so only the entries of the archive itself are returned.
+func GetSubArtifacts(archivePath string, patterns ...string) (subArtifacts []entities.Artifact, err error) {
+	if len(patterns) == 0 {
+		patterns = DefaultSubArtifactsPatterns
+	}
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, archive.Close())
+	}()
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		matched, err := matchSubArtifactPatterns(patterns, file.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+		subArtifact, err := getSubArtifact(file)
+		if err != nil {
+			return nil, err
+		}
+		subArtifacts = append(subArtifacts, subArtifact)
+	}
+	return subArtifacts, nil
+}
+
+func matchSubArtifactPatterns(patterns []string, entryPath string) (bool, error) {
+	for _, pattern := range patterns {
+		var matched bool
+		var err error
+		if strings.Contains(pattern, "/") {
+			matched, err = matchGlobSegments(strings.Split(pattern, "/"), strings.Split(entryPath, "/"))
+		} else {
+			matched, err = path.Match(pattern, path.Base(entryPath))
+		}
+		if err != nil || matched {
+			return matched, err
+		}
+	}
+	return false, nil
+}
+
+// Calculates the checksums of an archive entry while it's decompressed.
+func getSubArtifact(file *zip.File) (subArtifact entities.Artifact, err error) {
+	reader, err := file.Open()
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, reader.Close())
+	}()
+	checksums, err := crypto.CalcChecksums(reader)
+	if err != nil {
+		return
+	}
+	return entities.Artifact{
+		Name:     path.Base(file.Name),
+		Type:     strings.TrimPrefix(path.Ext(file.Name), "."),
+		Path:     file.Name,
+		Size:     int64(file.UncompressedSize64),
+		Checksum: entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]},
+	}, nil
+}
+
+// Sets the sub-artifacts of an artifact whose file is an archive, according to the build's sub-artifacts patterns.
+// Files which aren't zip-based archives have no sub-artifacts.
+func (b *Build) setSubArtifacts(artifact *entities.Artifact, filePath string) error {
+	if len(b.subArtifactsPatterns) == 0 {
+		return nil
+	}
+	subArtifacts, err := GetSubArtifacts(filePath, b.subArtifactsPatterns...)
+	if errors.Is(err, zip.ErrFormat) {
+		return nil
+	}
+	artifact.SubArtifacts = subArtifacts
+	return err
+}
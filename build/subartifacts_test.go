@@ -0,0 +1,83 @@
+package build
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gofrog/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSubArtifacts(t *testing.T) {
+	warPath := filepath.Join(t.TempDir(), "app.war")
+	writeZip(t, warPath, map[string]string{
+		"WEB-INF/lib/guava-32.1.2-jre.jar": "guava",
+		"WEB-INF/lib/":                     "",
+		"WEB-INF/classes/org/App.class":    "class",
+		"META-INF/MANIFEST.MF":             "Manifest-Version: 1.0\n",
+	})
+	checksums, err := crypto.CalcChecksums(strings.NewReader("guava"))
+	require.NoError(t, err)
+
+	subArtifacts, err := GetSubArtifacts(warPath)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Artifact{{
+		Name:     "guava-32.1.2-jre.jar",
+		Type:     "jar",
+		Path:     "WEB-INF/lib/guava-32.1.2-jre.jar",
+		Size:     5,
+		Checksum: entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]},
+	}}, subArtifacts)
+
+	// Patterns with '/' are matched against the entries' paths.
+	subArtifacts, err = GetSubArtifacts(warPath, "WEB-INF/**/*.class", "*.MF")
+	require.NoError(t, err)
+	var paths []string
+	for _, subArtifact := range subArtifacts {
+		paths = append(paths, subArtifact.Path)
+	}
+	assert.ElementsMatch(t, []string{"WEB-INF/classes/org/App.class", "META-INF/MANIFEST.MF"}, paths)
+
+	_, err = GetSubArtifacts(warPath, "[")
+	assert.Error(t, err)
+}
+
+func TestAddGenericArtifactsWithSubArtifacts(t *testing.T) {
+	projectDir := t.TempDir()
+	writeZip(t, filepath.Join(projectDir, "dist.zip"), map[string]string{"wheels/app-1.0-py3-none-any.whl": "wheel", "README.md": "readme"})
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "notes.txt"), []byte("notes"), 0644))
+	bld := createTestBuild(t, "sub-artifacts-test")
+	bld.SetSubArtifactsPatterns(DefaultSubArtifactsPatterns...)
+
+	// Files which aren't archives have no sub-artifacts.
+	artifacts, err := bld.AddGenericArtifacts("dist", filepath.Join(projectDir, "*"))
+	require.NoError(t, err)
+	require.Len(t, artifacts, 2)
+	require.Len(t, artifacts[0].SubArtifacts, 1)
+	assert.Equal(t, "wheels/app-1.0-py3-none-any.whl", artifacts[0].SubArtifacts[0].Path)
+	assert.Empty(t, artifacts[1].SubArtifacts)
+
+	buildInfo, err := bld.ToBuildInfo()
+	require.NoError(t, err)
+	// The order of the artifacts in the build-info isn't kept.
+	assert.ElementsMatch(t, artifacts, buildInfo.Modules[0].Artifacts)
+}
+
+func writeZip(t *testing.T, zipPath string, files map[string]string) {
+	zipFile, err := os.Create(zipPath)
+	require.NoError(t, err)
+	zipWriter := zip.NewWriter(zipFile)
+	for name, content := range files {
+		writer, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	require.NoError(t, zipFile.Close())
+}
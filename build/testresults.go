@@ -0,0 +1,97 @@
+package build
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+)
+
+// The properties which hold the summary of the test results, added to the build-info or to its modules.
+const (
+	TestsPassedProperty         = "buildInfo.tests.passed"
+	TestsFailedProperty         = "buildInfo.tests.failed"
+	TestsSkippedProperty        = "buildInfo.tests.skipped"
+	TestsDurationMillisProperty = "buildInfo.tests.durationMillis"
+)
+
+// Test reports whose results are summarized in the build-info.
+type testReports struct {
+	// The ID of the module to whose properties the results are added. If empty, they're added to the build-info's properties.
+	moduleId string
+	// Paths of the reports, which may contain the wildcards supported by filepath.Glob.
+	patterns []string
+}
+
+// AddTestReports adds test reports, whose passed, failed and skipped tests counts and durations are summed
+// and added as properties to the module with the given ID, or to the build-info itself if moduleId is empty.
+// The supported formats are JUnit XML, the output of 'go test -json' and the output of pytest.
+// The report paths may contain wildcards, for example: target/surefire-reports/TEST-*.xml
+// The reports are read when creating the build-info using the ToBuildInfo() function, so they can be written by the build itself.
+func (b *Build) AddTestReports(moduleId string, reportPatterns ...string) {
+	if len(reportPatterns) == 0 {
+		return
+	}
+	b.testReports = append(b.testReports, testReports{moduleId: moduleId, patterns: reportPatterns})
+}
+
+func applyTestReports(buildInfo *entities.BuildInfo, reports []testReports, logger utils.Log) error {
+	for _, report := range reports {
+		results, found, err := readTestReports(report.patterns)
+		if err != nil {
+			return err
+		}
+		if !found {
+			logger.Warn(fmt.Sprintf("No test reports were found at: %v", report.patterns))
+			continue
+		}
+		properties := testResultsToProperties(results)
+		if report.moduleId == "" {
+			buildInfo.AddProperties(properties)
+			continue
+		}
+		moduleFound := false
+		for i := range buildInfo.Modules {
+			if buildInfo.Modules[i].Id == report.moduleId {
+				buildInfo.Modules[i].AddProperties(properties)
+				moduleFound = true
+			}
+		}
+		if !moduleFound {
+			return fmt.Errorf("the test reports %v were added to the '%s' module, which isn't part of the build-info", report.patterns, report.moduleId)
+		}
+	}
+	return nil
+}
+
+// Reads the reports matching the patterns, and returns the sum of their results.
+// found is false if none of the patterns matches any file.
+func readTestReports(patterns []string) (results buildutils.TestResults, found bool, err error) {
+	for _, pattern := range patterns {
+		var reportPaths []string
+		if reportPaths, err = filepath.Glob(pattern); err != nil {
+			return
+		}
+		for _, reportPath := range reportPaths {
+			var reportResults buildutils.TestResults
+			if reportResults, err = buildutils.ReadTestReport(reportPath); err != nil {
+				return
+			}
+			results.Add(reportResults)
+			found = true
+		}
+	}
+	return
+}
+
+func testResultsToProperties(results buildutils.TestResults) map[string]string {
+	return map[string]string{
+		TestsPassedProperty:         strconv.Itoa(results.Passed),
+		TestsFailedProperty:         strconv.Itoa(results.Failed),
+		TestsSkippedProperty:        strconv.Itoa(results.Skipped),
+		TestsDurationMillisProperty: strconv.FormatInt(results.Duration.Milliseconds(), 10),
+	}
+}
@@ -0,0 +1,44 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTestReports(t *testing.T) {
+	reportsDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(reportsDir, "TEST-com.example.AppTest.xml"),
+		[]byte(`<testsuite time="1.5"><testcase name="a"/><testcase name="b"><failure/></testcase></testsuite>`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(reportsDir, "TEST-com.example.LibTest.xml"),
+		[]byte(`<testsuite time="0.25"><testcase name="a"/><testcase name="b"><skipped/></testcase></testsuite>`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(reportsDir, "pytest.log"),
+		[]byte("===== 3 passed in 0.50s =====\n"), 0644))
+
+	buildInfo := &entities.BuildInfo{Modules: []entities.Module{{Id: "org.example:app:1.0"}, {Id: "org.example:lib:1.0"}}}
+	assert.NoError(t, applyTestReports(buildInfo, []testReports{
+		{moduleId: "org.example:app:1.0", patterns: []string{filepath.Join(reportsDir, "TEST-*.xml")}},
+		{patterns: []string{filepath.Join(reportsDir, "pytest.log")}},
+		// A pattern which doesn't match any report is skipped with a warning.
+		{moduleId: "org.example:lib:1.0", patterns: []string{filepath.Join(reportsDir, "missing-*.xml")}},
+	}, logger))
+	assert.Equal(t, entities.Env{
+		TestsPassedProperty:         "3",
+		TestsFailedProperty:         "0",
+		TestsSkippedProperty:        "0",
+		TestsDurationMillisProperty: "500",
+	}, buildInfo.Properties)
+	assert.Equal(t, map[string]any{
+		TestsPassedProperty:         "2",
+		TestsFailedProperty:         "1",
+		TestsSkippedProperty:        "1",
+		TestsDurationMillisProperty: "1750",
+	}, buildInfo.Modules[0].Properties)
+	assert.Nil(t, buildInfo.Modules[1].Properties)
+
+	assert.Error(t, applyTestReports(buildInfo, []testReports{{moduleId: "missing", patterns: []string{filepath.Join(reportsDir, "pytest.log")}}}, logger))
+}
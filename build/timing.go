@@ -0,0 +1,52 @@
+package build
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+)
+
+// The properties which hold the durations of the phases of each module's collection, in milliseconds.
+const (
+	// The duration of the collection of the module's dependencies, from the start of CollectIncrementally until the module was saved.
+	CollectDurationProperty = "buildInfo.timing.collectMillis"
+	// The duration of the build command run by the module's Build method, such as 'bundle install' or 'mix deps.get'.
+	CommandDurationProperty = "buildInfo.timing.commandMillis"
+)
+
+// Runs the build command of a module, with its output redirected to the standard error, and returns how long it ran.
+func runModuleCommand(command *exec.Cmd) (time.Duration, error) {
+	command.Stdout = os.Stderr
+	command.Stderr = os.Stderr
+	return runTimedCommand(command)
+}
+
+// Runs an external command of a collector with the CommandRunner, which records it in the CollectionStats. Returns how long the command ran.
+func runTimedCommand(command *exec.Cmd) (time.Duration, error) {
+	started := time.Now()
+	_, _, err := utils.RunCommand(command)
+	return time.Since(started), err
+}
+
+// Adds the duration of the module's build command to its properties, if the command ran.
+func addCommandDuration(module *entities.Module, commandDuration time.Duration) {
+	if commandDuration > 0 {
+		module.AddProperties(map[string]string{CommandDurationProperty: formatMillis(commandDuration)})
+	}
+}
+
+// Adds the duration since the collection started to the properties of the modules.
+func addCollectDuration(modules []entities.Module, collectionStarted time.Time) {
+	collectDuration := formatMillis(time.Since(collectionStarted))
+	for i := range modules {
+		modules[i].AddProperties(map[string]string{CollectDurationProperty: collectDuration})
+	}
+}
+
+func formatMillis(duration time.Duration) string {
+	return strconv.FormatInt(duration.Milliseconds(), 10)
+}
@@ -0,0 +1,66 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionTiming(t *testing.T) {
+	timedBuild := createTestBuild(t, "build-info-go-test-timing")
+
+	// The modules saved by the collection have its duration, and the modules saved directly don't.
+	err := timedBuild.CollectIncrementally("", GoTechnology, func(containingBuild *Build) error {
+		time.Sleep(5 * time.Millisecond)
+		module := entities.Module{Id: "collected", Type: entities.Go}
+		addCommandDuration(&module, 20*time.Millisecond)
+		return containingBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{module}})
+	})
+	require.NoError(t, err)
+	require.NoError(t, timedBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{{Id: "saved", Type: entities.Go}}}))
+
+	buildInfo, err := timedBuild.ToBuildInfo()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, buildInfo.DurationMillis, int64(5))
+	require.Len(t, buildInfo.Modules, 2)
+	modules := make(map[string]entities.Module)
+	for _, module := range buildInfo.Modules {
+		modules[module.Id] = module
+	}
+	properties := modules["collected"].Properties.(map[string]interface{})
+	assert.Equal(t, "20", properties[CommandDurationProperty])
+	assert.NotEqual(t, "0", properties[CollectDurationProperty])
+	assert.Nil(t, modules["saved"].Properties)
+}
+
+func TestCollectionTimingFromIncrementalCache(t *testing.T) {
+	projectPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "go.mod"), []byte("module example.com/project\n"), 0644))
+	cacheDir := t.TempDir()
+	collect := func(containingBuild *Build) error {
+		time.Sleep(50 * time.Millisecond)
+		return containingBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{{Id: "example.com/project", Type: entities.Go}}})
+	}
+	getCollectDuration := func() int64 {
+		incrementalBuild := createTestBuild(t, "build-info-go-test-timing")
+		incrementalBuild.SetIncrementalCacheDir(cacheDir)
+		require.NoError(t, incrementalBuild.CollectIncrementally(projectPath, GoTechnology, collect))
+		buildInfo, err := incrementalBuild.ToBuildInfo()
+		require.NoError(t, err)
+		require.Len(t, buildInfo.Modules, 1)
+		collectDuration, err := strconv.ParseInt(buildInfo.Modules[0].Properties.(map[string]interface{})[CollectDurationProperty].(string), 10, 64)
+		require.NoError(t, err)
+		return collectDuration
+	}
+
+	// The cached modules have the duration of reading them from the cache, rather than the duration of the original collection.
+	collectDuration := getCollectDuration()
+	assert.GreaterOrEqual(t, collectDuration, int64(50))
+	assert.Less(t, getCollectDuration(), collectDuration)
+}
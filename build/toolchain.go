@@ -0,0 +1,125 @@
+package build
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+)
+
+// The prefix of the build properties, which describe the toolchain used by the build.
+const ToolchainPropertyPrefix = "buildInfo.toolchain."
+
+const toolchainVersionTimeout = 30 * time.Second
+
+type Toolchain string
+
+const (
+	JavaToolchain    Toolchain = "java"
+	MavenToolchain   Toolchain = "maven"
+	NodeToolchain    Toolchain = "node"
+	NpmToolchain     Toolchain = "npm"
+	YarnToolchain    Toolchain = "yarn"
+	PythonToolchain  Toolchain = "python"
+	PipToolchain     Toolchain = "pip"
+	GoToolchain      Toolchain = "go"
+	DotnetToolchain  Toolchain = "dotnet"
+	RubyToolchain    Toolchain = "ruby"
+	BundlerToolchain Toolchain = "bundler"
+	ElixirToolchain  Toolchain = "elixir"
+	GhcToolchain     Toolchain = "ghc"
+	CabalToolchain   Toolchain = "cabal"
+	StackToolchain   Toolchain = "stack"
+	ZigToolchain     Toolchain = "zig"
+	CMakeToolchain   Toolchain = "cmake"
+	VcpkgToolchain   Toolchain = "vcpkg"
+)
+
+// The executables and arguments which print the version of each toolchain, ordered by their priority.
+var toolchainVersionCommands = map[Toolchain][][]string{
+	JavaToolchain:    {{"java", "-version"}},
+	MavenToolchain:   {{"mvn", "--version"}},
+	NodeToolchain:    {{"node", "--version"}},
+	NpmToolchain:     {{"npm", "--version"}},
+	YarnToolchain:    {{"yarn", "--version"}},
+	PythonToolchain:  {{"python3", "--version"}, {"python", "--version"}},
+	PipToolchain:     {{"pip3", "--version"}, {"pip", "--version"}},
+	GoToolchain:      {{"go", "version"}},
+	DotnetToolchain:  {{"dotnet", "--version"}},
+	RubyToolchain:    {{"ruby", "--version"}},
+	BundlerToolchain: {{"bundle", "--version"}},
+	ElixirToolchain:  {{"elixir", "--short-version"}},
+	GhcToolchain:     {{"ghc", "--numeric-version"}},
+	CabalToolchain:   {{"cabal", "--numeric-version"}},
+	StackToolchain:   {{"stack", "--numeric-version"}},
+	ZigToolchain:     {{"zig", "version"}},
+	CMakeToolchain:   {{"cmake", "--version"}},
+	VcpkgToolchain:   {{"vcpkg", "version"}},
+}
+
+// The toolchains used by each project technology.
+var technologyToolchains = map[ProjectTechnology][]Toolchain{
+	GoTechnology:      {GoToolchain},
+	MavenTechnology:   {JavaToolchain, MavenToolchain},
+	GradleTechnology:  {JavaToolchain},
+	NpmTechnology:     {NodeToolchain, NpmToolchain},
+	YarnTechnology:    {NodeToolchain, YarnToolchain},
+	PythonTechnology:  {PythonToolchain, PipToolchain},
+	BundlerTechnology: {RubyToolchain, BundlerToolchain},
+	MixTechnology:     {ElixirToolchain},
+	HaskellTechnology: {GhcToolchain, CabalToolchain, StackToolchain},
+	ZigTechnology:     {ZigToolchain},
+	CMakeTechnology:   {CMakeToolchain},
+	VcpkgTechnology:   {VcpkgToolchain},
+}
+
+// CollectToolchain records the version and the path of each of the provided toolchains in the build properties,
+// for example: 'buildInfo.toolchain.go.version' and 'buildInfo.toolchain.go.path'.
+// The versions are checked in srcPath, since some tools select their version by the project's configuration.
+// Pass srcPath as an empty string to use the working directory.
+// Toolchains which aren't installed are skipped.
+func (b *Build) CollectToolchain(srcPath string, toolchains ...Toolchain) error {
+	if !b.buildNameAndNumberProvided() {
+		return nil
+	}
+	properties := make(map[string]string)
+	for _, toolchain := range toolchains {
+		version, path := b.getToolchainVersion(srcPath, toolchain)
+		if version == "" {
+			continue
+		}
+		properties[ToolchainPropertyPrefix+string(toolchain)+".version"] = version
+		properties[ToolchainPropertyPrefix+string(toolchain)+".path"] = path
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	return b.SavePartialBuildInfo(&entities.Partial{Env: properties})
+}
+
+// Returns the first line of the toolchain's version output, and the path to its executable.
+func (b *Build) getToolchainVersion(srcPath string, toolchain Toolchain) (version, path string) {
+	for _, versionCommand := range toolchainVersionCommands[toolchain] {
+		var err error
+		if path, err = exec.LookPath(versionCommand[0]); err != nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), toolchainVersionTimeout)
+		command := exec.CommandContext(ctx, path, versionCommand[1:]...)
+		command.Dir = srcPath
+		// Some tools, such as java, print their version to the stderr.
+		stdout, stderr, err := utils.RunCommand(command)
+		cancel()
+		if err != nil {
+			b.logger.Debug("Failed to get the version of", toolchain+":", err.Error())
+			continue
+		}
+		version, _, _ = strings.Cut(strings.TrimSpace(string(stdout)+"\n"+string(stderr)), "\n")
+		return strings.TrimSpace(version), path
+	}
+	b.logger.Debug("Couldn't find", toolchain, "in the PATH. Skipping its version collection.")
+	return "", ""
+}
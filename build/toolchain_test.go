@@ -0,0 +1,20 @@
+package build
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectToolchain(t *testing.T) {
+	bld := createTestBuild(t, "toolchain-build")
+
+	// The tests run with Go, so its version is expected to be collected.
+	assert.NoError(t, bld.CollectToolchain("", GoToolchain, Toolchain("non-existing-tool")))
+	buildInfo, err := bld.ToBuildInfo()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(buildInfo.Properties[ToolchainPropertyPrefix+"go.version"], "go version go"))
+	assert.NotEmpty(t, buildInfo.Properties[ToolchainPropertyPrefix+"go.path"])
+	assert.Len(t, buildInfo.Properties, 2)
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// The name of the file which lists, in the gitignore syntax, the files that shouldn't be recorded as artifacts when directories are scanned for them.
+// Unlike .gitignore, which usually lists the build outputs themselves, it lists only the outputs which aren't artifacts, such as test dumps and temporary files.
+const BiIgnoreFileName = ".biignore"
+
+// IgnoreRules are the rules of an ignore file in the gitignore syntax:
+//   - Blank lines and lines starting with '#' are skipped. A leading '\' escapes a '#' or a '!'.
+//   - A pattern starting with '!' re-includes the paths excluded by the previous patterns.
+//   - A pattern ending with '/' matches directories only. Everything in an ignored directory is ignored, and can't be re-included.
+//   - A pattern with a '/' at its beginning or middle is relative to the directory of the ignore file. Otherwise, it matches at any level.
+//   - '*' and '?' match within a single path segment, and '**' matches any number of segments.
+//
+// The last pattern matching a path decides whether it's ignored. A nil IgnoreRules ignores nothing.
+type IgnoreRules struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+// ReadBiIgnoreFile reads the .biignore file in the provided directory. If the directory has no such file, nil is returned.
+func ReadBiIgnoreFile(dir string) (*IgnoreRules, error) {
+	content, err := os.ReadFile(filepath.Join(dir, BiIgnoreFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ParseIgnoreRules(string(content)), nil
+}
+
+// ParseIgnoreRules parses the content of an ignore file in the gitignore syntax.
+func ParseIgnoreRules(content string) *IgnoreRules {
+	ignoreRules := &IgnoreRules{}
+	for _, line := range strings.Split(content, "\n") {
+		pattern := strings.TrimRight(line, " \t\r")
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		rule := ignoreRule{}
+		if strings.HasPrefix(pattern, "!") {
+			rule.negate = true
+			pattern = pattern[1:]
+		} else if strings.HasPrefix(pattern, `\#`) || strings.HasPrefix(pattern, `\!`) {
+			pattern = pattern[1:]
+		}
+		if strings.HasSuffix(pattern, "/") {
+			rule.dirOnly = true
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+		if pattern == "" {
+			continue
+		}
+		// A pattern without a separator, except for a trailing one, matches at any level.
+		if !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+		rule.segments = strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+		ignoreRules.rules = append(ignoreRules.rules, rule)
+	}
+	return ignoreRules
+}
+
+// IsIgnored returns true if the file in the provided path, relative to the directory of the ignore file, or any of its parent directories, is ignored.
+func (ir *IgnoreRules) IsIgnored(relativePath string) bool {
+	if ir == nil {
+		return false
+	}
+	segments := strings.Split(path.Clean(filepath.ToSlash(relativePath)), "/")
+	if segments[0] == ".." {
+		// The path is outside the directory of the ignore file.
+		return false
+	}
+	for i := 1; i < len(segments); i++ {
+		if ir.match(segments[:i], true) {
+			return true
+		}
+	}
+	return ir.match(segments, false)
+}
+
+// Returns true if the last rule matching the path ignores it.
+func (ir *IgnoreRules) match(pathSegments []string, isDir bool) (ignored bool) {
+	for _, rule := range ir.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if matchIgnoreSegments(rule.segments, pathSegments) {
+			ignored = !rule.negate
+		}
+	}
+	return
+}
+
+// Returns true if the path segments match the pattern segments, in which '**' matches any number of segments.
+func matchIgnoreSegments(patternSegments, pathSegments []string) bool {
+	if len(patternSegments) == 0 {
+		return len(pathSegments) == 0
+	}
+	if patternSegments[0] == "**" {
+		// A trailing '**' matches everything inside a directory, but not the directory itself.
+		if len(patternSegments) == 1 {
+			return len(pathSegments) > 0
+		}
+		for i := 0; i <= len(pathSegments); i++ {
+			if matchIgnoreSegments(patternSegments[1:], pathSegments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(pathSegments) == 0 {
+		return false
+	}
+	// A malformed pattern matches nothing, like in git.
+	if matched, err := path.Match(patternSegments[0], pathSegments[0]); err != nil || !matched {
+		return false
+	}
+	return matchIgnoreSegments(patternSegments[1:], pathSegments[1:])
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	ignoreRules := ParseIgnoreRules(`# Test dumps and temporary files.
+*.tmp
+!/dist/keep.tmp
+test-dumps/
+/build/*.log
+docs/**/draft-*
+dist/cache/**
+!dist/cache/app.zip
+\#notes.txt
+`)
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"app.tmp", true},
+		{"dist/linux/app.tmp", true},
+		{"dist/keep.tmp", false},
+		{"other/dist/keep.tmp", true},
+		{"dist/app.zip", false},
+		{"test-dumps/core.zip", true},
+		{"dist/test-dumps/core.zip", true},
+		// A directory-only pattern doesn't match a file.
+		{"dist/test-dumps", false},
+		{"build/app.log", true},
+		{"build/logs/app.log", false},
+		{"src/build/app.log", false},
+		{"docs/draft-1.pdf", true},
+		{"docs/v1/v2/draft-1.pdf", true},
+		{"docs/final.pdf", false},
+		{"dist/cache/a/b.bin", true},
+		{"dist/cache/app.zip", false},
+		{"#notes.txt", true},
+		{"../app.tmp", false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.path, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, ignoreRules.IsIgnored(filepath.FromSlash(testCase.path)))
+		})
+	}
+	// The files in an ignored directory can't be re-included.
+	assert.True(t, ParseIgnoreRules("logs/\n!logs/app.log").IsIgnored("logs/app.log"))
+}
+
+func TestReadBiIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	ignoreRules, err := ReadBiIgnoreFile(dir)
+	require.NoError(t, err)
+	assert.Nil(t, ignoreRules)
+	assert.False(t, ignoreRules.IsIgnored("app.tmp"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, BiIgnoreFileName), []byte("*.tmp\r\n"), 0644))
+	ignoreRules, err = ReadBiIgnoreFile(dir)
+	require.NoError(t, err)
+	assert.True(t, ignoreRules.IsIgnored("app.tmp"))
+}
@@ -0,0 +1,306 @@
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	GemfileName     = "Gemfile"
+	GemfileLockName = "Gemfile.lock"
+	// The group of the gems which aren't declared in any group of the Gemfile.
+	DefaultGemGroup = "default"
+	// The group of the development dependencies of the gemspec loaded by the Gemfile's 'gemspec' method.
+	DevelopmentGemGroup = "development"
+)
+
+// The types of the sources of a Gemfile.lock, each with its own section.
+const (
+	GemServerSource = "GEM"
+	GitGemSource    = "GIT"
+	PathGemSource   = "PATH"
+)
+
+// The sections of a Gemfile.lock which aren't gem sources.
+const (
+	gemfileLockDependenciesSection = "DEPENDENCIES"
+	gemfileLockChecksumsSection    = "CHECKSUMS"
+)
+
+// GemSpec is a gem resolved in a Gemfile.lock.
+type GemSpec struct {
+	Name    string
+	Version string
+	// The platform of a gem with native extensions, for example: x86_64-linux. Empty for gems which run on any Ruby platform.
+	Platform string
+	// The type of the section of the gem's source: GemServerSource, GitGemSource or PathGemSource.
+	SourceType string
+	// The gem server URL, git repository URL or local path from which the gem is installed.
+	Remote string
+	// The branch of a gem installed from a git repository, when the Gemfile requires the gem by a branch rather than by a tag or a revision.
+	Branch string
+	// The names of the gems the gem depends on.
+	Dependencies []string
+	// The SHA-256 checksum of the gem's package, from the CHECKSUMS section which Bundler 2.5 and above write.
+	Sha256 string
+}
+
+// Id returns the ID of the gem in the build-info: <name>:<version>
+func (gs *GemSpec) Id() string {
+	return gs.Name + ":" + gs.Version
+}
+
+// FileName returns the name of the gem's package file, for example: nokogiri-1.15.4-x86_64-linux.gem
+func (gs *GemSpec) FileName() string {
+	if gs.Platform == "" {
+		return gs.Name + "-" + gs.Version + ".gem"
+	}
+	return gs.Name + "-" + gs.Version + "-" + gs.Platform + ".gem"
+}
+
+// GemfileLock is the resolution of a Bundler project, read from its Gemfile.lock.
+type GemfileLock struct {
+	// The resolved gems. A gem with native extensions may be resolved for several platforms, each with its own spec.
+	Specs []*GemSpec
+	// The names of the gems declared in the Gemfile, including the development dependencies of the gemspec it loads.
+	Dependencies []string
+}
+
+// GetProjectGem returns the gem built by the project itself, which the Gemfile loads by its 'gemspec' method, or nil if there is no such gem.
+func (gl *GemfileLock) GetProjectGem() *GemSpec {
+	for _, spec := range gl.Specs {
+		if spec.SourceType == PathGemSource && (spec.Remote == "." || spec.Remote == "./") {
+			return spec
+		}
+	}
+	return nil
+}
+
+// ReadGemfileLock reads and parses the Gemfile.lock of the Bundler project in the provided directory.
+func ReadGemfileLock(projectDir string) (*GemfileLock, error) {
+	content, err := os.ReadFile(filepath.Join(projectDir, GemfileLockName))
+	if err != nil {
+		return nil, err
+	}
+	return parseGemfileLock(string(content)), nil
+}
+
+// The format of the gems in the specs, DEPENDENCIES and CHECKSUMS sections: <name> [(<version>[-<platform>])][!]
+// The version of a dependency of a spec, or of a declared gem, is a requirement such as '~> 7.0', which is ignored.
+var gemfileLockEntryRegex = regexp.MustCompile(`^(\S+?)(?: \(([^)]*)\))?!?(?: (.*))?$`)
+
+func parseGemfileLock(content string) *GemfileLock {
+	lock := &GemfileLock{}
+	var section, remote, branch string
+	var currentSpec *GemSpec
+	checksums := make(map[string]string)
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indentation := len(line) - len(strings.TrimLeft(line, " "))
+		line = strings.TrimSpace(line)
+		if indentation == 0 {
+			section, remote, branch, currentSpec = line, "", "", nil
+			continue
+		}
+		switch section {
+		case GemServerSource, GitGemSource, PathGemSource:
+			switch indentation {
+			case 2:
+				if value, found := strings.CutPrefix(line, "remote: "); found {
+					remote = value
+				} else if value, found = strings.CutPrefix(line, "branch: "); found {
+					branch = value
+				}
+			case 4:
+				match := gemfileLockEntryRegex.FindStringSubmatch(line)
+				if match == nil {
+					continue
+				}
+				// The platform follows the first '-', since gem versions don't contain it.
+				version, platform, _ := strings.Cut(match[2], "-")
+				currentSpec = &GemSpec{Name: match[1], Version: version, Platform: platform, SourceType: section, Remote: remote, Branch: branch}
+				lock.Specs = append(lock.Specs, currentSpec)
+			case 6:
+				if match := gemfileLockEntryRegex.FindStringSubmatch(line); match != nil && currentSpec != nil && !slices.Contains(currentSpec.Dependencies, match[1]) {
+					currentSpec.Dependencies = append(currentSpec.Dependencies, match[1])
+				}
+			}
+		case gemfileLockDependenciesSection:
+			if match := gemfileLockEntryRegex.FindStringSubmatch(line); match != nil && indentation == 2 {
+				lock.Dependencies = append(lock.Dependencies, match[1])
+			}
+		case gemfileLockChecksumsSection:
+			match := gemfileLockEntryRegex.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			for _, checksum := range strings.Split(match[3], ",") {
+				if value, found := strings.CutPrefix(strings.TrimSpace(checksum), "sha256="); found {
+					checksums[match[1]+" "+match[2]] = value
+				}
+			}
+		}
+	}
+	for _, spec := range lock.Specs {
+		versionAndPlatform := spec.Version
+		if spec.Platform != "" {
+			versionAndPlatform += "-" + spec.Platform
+		}
+		spec.Sha256 = checksums[spec.Name+" "+versionAndPlatform]
+	}
+	return lock
+}
+
+// Gemfile declarations which group the gems.
+var (
+	gemfileGemRegex = regexp.MustCompile(`^gem\s*\(?\s*["']([^"']+)["'](.*)$`)
+	// The 'group:' or 'groups:' option of a gem, in the new or the hash rocket syntax.
+	gemfileGroupOptionRegex = regexp.MustCompile(`:?groups?:?\s*(?:=>\s*)?(\[[^\]]*]|:\w+|["'][^"']+["'])`)
+	gemfileGroupBlockRegex  = regexp.MustCompile(`^group\s*\(?(.*?)\)?\s+do(\s*\|.*\|)?$`)
+	gemfileGroupNameRegex   = regexp.MustCompile(`\w+`)
+	// The lines which open a block or a statement which is closed by 'end'.
+	gemfileBlockRegex = regexp.MustCompile(`(^(if|unless|case|begin|while|until)\b)|(\bdo(\s*\|.*\|)?$)`)
+)
+
+// ReadGemfileGroups reads the Gemfile of the Bundler project in the provided directory,
+// and returns the groups of the gems it declares, mapped by their names. Gems declared outside of any group are in the DefaultGemGroup group.
+// If the Gemfile loads a gemspec, the gems which aren't declared by the Gemfile itself are considered the gemspec's development dependencies.
+// The Gemfile is Ruby code. The common forms of the 'gem' and 'group' methods are recognized, and the rest of the code is ignored.
+func ReadGemfileGroups(projectDir string) (groups map[string][]string, loadsGemspec bool, err error) {
+	content, err := os.ReadFile(filepath.Join(projectDir, GemfileName))
+	if err != nil {
+		return nil, false, err
+	}
+	groups, loadsGemspec = parseGemfileGroups(string(content))
+	return groups, loadsGemspec, nil
+}
+
+func parseGemfileGroups(content string) (groups map[string][]string, loadsGemspec bool) {
+	groups = make(map[string][]string)
+	// The groups of each open block. Blocks other than 'group' blocks have no groups.
+	var blocksGroups [][]string
+	for _, line := range strings.Split(content, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "end" || strings.HasPrefix(line, "end "):
+			if len(blocksGroups) > 0 {
+				blocksGroups = blocksGroups[:len(blocksGroups)-1]
+			}
+		case gemfileGroupBlockRegex.MatchString(line):
+			blockGroups := gemfileGroupNameRegex.FindAllString(gemfileGroupBlockRegex.FindStringSubmatch(line)[1], -1)
+			blocksGroups = append(blocksGroups, slices.DeleteFunc(blockGroups, isGemfileOptionKey))
+		case gemfileGemRegex.MatchString(line):
+			match := gemfileGemRegex.FindStringSubmatch(line)
+			var gemGroups []string
+			for _, blockGroups := range blocksGroups {
+				gemGroups = appendUniqueGroups(gemGroups, blockGroups...)
+			}
+			if optionMatch := gemfileGroupOptionRegex.FindStringSubmatch(match[2]); optionMatch != nil {
+				gemGroups = appendUniqueGroups(gemGroups, gemfileGroupNameRegex.FindAllString(optionMatch[1], -1)...)
+			}
+			if len(gemGroups) == 0 {
+				gemGroups = []string{DefaultGemGroup}
+			}
+			groups[match[1]] = appendUniqueGroups(groups[match[1]], gemGroups...)
+			if gemfileBlockRegex.MatchString(line) {
+				blocksGroups = append(blocksGroups, nil)
+			}
+		case line == "gemspec" || strings.HasPrefix(line, "gemspec ") || strings.HasPrefix(line, "gemspec("):
+			loadsGemspec = true
+		case gemfileBlockRegex.MatchString(line):
+			blocksGroups = append(blocksGroups, nil)
+		}
+	}
+	return
+}
+
+// The options of the 'group' method, such as 'optional: true', aren't groups.
+func isGemfileOptionKey(name string) bool {
+	return name == "optional" || name == "true" || name == "false"
+}
+
+func appendUniqueGroups(groups []string, newGroups ...string) []string {
+	for _, group := range newGroups {
+		if !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// GetGemCacheDirs returns the directories in which the packages (.gem files) of the project's installed gems may be cached, by their priority:
+// the vendor/cache directory of 'bundle cache', the cache directories of the project's bundle path (BUNDLE_PATH, or vendor/bundle),
+// and the cache directories of the GEM_HOME and GEM_PATH gem directories, and of the directories which the gem command reports.
+// Directories which don't exist are omitted.
+func GetGemCacheDirs(projectDir string) ([]string, error) {
+	candidates := []string{filepath.Join(projectDir, "vendor", "cache")}
+	bundlePaths := []string{filepath.Join(projectDir, "vendor", "bundle")}
+	if bundlePath := os.Getenv("BUNDLE_PATH"); bundlePath != "" {
+		if !filepath.IsAbs(bundlePath) {
+			bundlePath = filepath.Join(projectDir, bundlePath)
+		}
+		bundlePaths = append([]string{bundlePath}, bundlePaths...)
+	}
+	for _, bundlePath := range bundlePaths {
+		// Bundler installs the gems under <bundle path>/<ruby engine>/<ruby ABI version>.
+		gemDirs, err := filepath.Glob(filepath.Join(bundlePath, "*", "*", "cache"))
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, gemDirs...)
+	}
+	gemDirs := filepath.SplitList(os.Getenv("GEM_HOME"))
+	gemDirs = append(gemDirs, filepath.SplitList(os.Getenv("GEM_PATH"))...)
+	gemDirs = append(gemDirs, getGemCommandPaths(projectDir)...)
+	for _, gemDir := range gemDirs {
+		if gemDir != "" {
+			candidates = append(candidates, filepath.Join(gemDir, "cache"))
+		}
+	}
+	var cacheDirs []string
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() && !slices.Contains(cacheDirs, candidate) {
+			cacheDirs = append(cacheDirs, candidate)
+		}
+	}
+	return cacheDirs, nil
+}
+
+// Returns the gem directories reported by 'gem env gempath', or nil if the gem command isn't installed.
+func getGemCommandPaths(projectDir string) []string {
+	gemExecutable, err := exec.LookPath("gem")
+	if err != nil {
+		return nil
+	}
+	command := exec.Command(gemExecutable, "env", "gempath")
+	command.Dir = projectDir
+	output, _, err := utils.RunCommand(command)
+	if err != nil {
+		return nil
+	}
+	return filepath.SplitList(strings.TrimSpace(string(output)))
+}
+
+// FindGemFile returns the path of the gem's package in the first of the cache directories which has it, or an empty string if none of them has it.
+func FindGemFile(spec *GemSpec, cacheDirs []string) (string, error) {
+	for _, cacheDir := range cacheDirs {
+		gemPath := filepath.Join(cacheDir, spec.FileName())
+		info, err := os.Stat(gemPath)
+		if err == nil && !info.IsDir() {
+			return gemPath, nil
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGemfileLock(t *testing.T) {
+	lock := parseGemfileLock(`GIT
+  remote: https://github.com/example/forked.git
+  revision: 2c1a4e0b5f1f3d4b1b0f8e2c7a0e6d1c9b8a7f6e
+  branch: main
+  specs:
+    forked (0.3.0)
+
+PATH
+  remote: .
+  specs:
+    app (1.0.0)
+      rack (>= 2.0)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.15.4-arm64-darwin)
+      racc (~> 1.4)
+    nokogiri (1.15.4-x86_64-linux)
+      racc (~> 1.4)
+    racc (1.7.1)
+    rack (3.0.8)
+
+PLATFORMS
+  arm64-darwin
+  x86_64-linux
+
+DEPENDENCIES
+  app!
+  forked!
+  nokogiri (~> 1.15)
+
+CHECKSUMS
+  app (1.0.0)
+  nokogiri (1.15.4-x86_64-linux) sha256=3f4d5d0bc2a0f5b3e1f4d0c2b6f1e0d9c8b7a6f5e4d3c2b1a0f9e8d7c6b5a4f3
+  rack (3.0.8) sha256=45c0a1a3fb6fd0f9e3c9f2c8b1d1b3e45b20f07d8a2d5e3c43e2b8f7a0c5e4d1
+
+BUNDLED WITH
+   2.5.3
+`)
+	assert.Equal(t, []*GemSpec{
+		{Name: "forked", Version: "0.3.0", SourceType: GitGemSource, Remote: "https://github.com/example/forked.git", Branch: "main"},
+		{Name: "app", Version: "1.0.0", SourceType: PathGemSource, Remote: ".", Dependencies: []string{"rack"}},
+		{Name: "nokogiri", Version: "1.15.4", Platform: "arm64-darwin", SourceType: GemServerSource, Remote: "https://rubygems.org/", Dependencies: []string{"racc"}},
+		{
+			Name:         "nokogiri",
+			Version:      "1.15.4",
+			Platform:     "x86_64-linux",
+			SourceType:   GemServerSource,
+			Remote:       "https://rubygems.org/",
+			Dependencies: []string{"racc"},
+			Sha256:       "3f4d5d0bc2a0f5b3e1f4d0c2b6f1e0d9c8b7a6f5e4d3c2b1a0f9e8d7c6b5a4f3",
+		},
+		{Name: "racc", Version: "1.7.1", SourceType: GemServerSource, Remote: "https://rubygems.org/"},
+		{Name: "rack", Version: "3.0.8", SourceType: GemServerSource, Remote: "https://rubygems.org/", Sha256: "45c0a1a3fb6fd0f9e3c9f2c8b1d1b3e45b20f07d8a2d5e3c43e2b8f7a0c5e4d1"},
+	}, lock.Specs)
+	assert.Equal(t, []string{"app", "forked", "nokogiri"}, lock.Dependencies)
+	assert.Equal(t, lock.Specs[1], lock.GetProjectGem())
+	assert.Equal(t, "nokogiri-1.15.4-x86_64-linux.gem", lock.Specs[3].FileName())
+	assert.Equal(t, "rack-3.0.8.gem", lock.Specs[5].FileName())
+}
+
+func TestParseGemfileGroups(t *testing.T) {
+	groups, loadsGemspec := parseGemfileGroups(`source "https://rubygems.org"
+
+gemspec
+
+gem "rails", "~> 7.0.8"
+gem 'pg', '~> 1.1' # The database
+gem "rubocop", require: false, group: :development
+gem "capybara", groups: [:test, :system]
+gem "bootsnap", :group => "production"
+
+platforms :mri, :windows do
+  gem "debug"
+end
+
+group :development, :test do
+  gem "rspec-rails"
+  if ENV["COVERAGE"]
+    gem "simplecov"
+  end
+end
+
+group :test, optional: true do
+  gem "webmock"
+  gem "rspec-rails"
+end
+`)
+	assert.True(t, loadsGemspec)
+	assert.Equal(t, map[string][]string{
+		"rails":       {DefaultGemGroup},
+		"pg":          {DefaultGemGroup},
+		"rubocop":     {"development"},
+		"capybara":    {"test", "system"},
+		"bootsnap":    {"production"},
+		"debug":       {DefaultGemGroup},
+		"rspec-rails": {"development", "test"},
+		"simplecov":   {"development", "test"},
+		"webmock":     {"test"},
+	}, groups)
+
+	groups, loadsGemspec = parseGemfileGroups("source 'https://rubygems.org'\ngem 'sinatra'\n")
+	assert.False(t, loadsGemspec)
+	assert.Equal(t, map[string][]string{"sinatra": {DefaultGemGroup}}, groups)
+}
+
+func TestFindGemFile(t *testing.T) {
+	t.Setenv("GEM_HOME", t.TempDir())
+	t.Setenv("GEM_PATH", "")
+	t.Setenv("BUNDLE_PATH", "")
+	projectDir := t.TempDir()
+	vendorCache := filepath.Join(projectDir, "vendor", "cache")
+	bundleCache := filepath.Join(projectDir, "vendor", "bundle", "ruby", "3.2.0", "cache")
+	require.NoError(t, os.MkdirAll(vendorCache, 0755))
+	require.NoError(t, os.MkdirAll(bundleCache, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleCache, "rack-3.0.8.gem"), []byte("rack"), 0644))
+
+	cacheDirs, err := GetGemCacheDirs(projectDir)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(cacheDirs), 2)
+	assert.Equal(t, []string{vendorCache, bundleCache}, cacheDirs[:2])
+
+	gemPath, err := FindGemFile(&GemSpec{Name: "rack", Version: "3.0.8"}, cacheDirs)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(bundleCache, "rack-3.0.8.gem"), gemPath)
+	gemPath, err = FindGemFile(&GemSpec{Name: "rack", Version: "3.0.9"}, cacheDirs)
+	assert.NoError(t, err)
+	assert.Empty(t, gemPath)
+}
@@ -0,0 +1,350 @@
+package utils
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	CMakeListsFileName = "CMakeLists.txt"
+	CMakeCacheFileName = "CMakeCache.txt"
+	// The directory of the build tree, into which FetchContent populates the dependencies.
+	FetchContentDepsDirName = "_deps"
+	DefaultCMakeBuildDir    = "build"
+)
+
+// The download methods of the external dependencies.
+const (
+	GitCMakeMethod = "git"
+	UrlCMakeMethod = "url"
+)
+
+// The maximal depth in the build tree of the prefix directories of ExternalProject, which are created in the binary directories of the CMakeLists.txt files which add them.
+const externalProjectMaxDepth = 4
+
+// The extensions of the archives which ExternalProject extracts, longest first.
+var cmakeArchiveExtensions = []string{".tar.gz", ".tar.bz2", ".tar.xz", ".tar.zst", ".tgz", ".tbz2", ".txz", ".tar", ".zip", ".7z"}
+
+// CMakeExternalDependency is a dependency downloaded by FetchContent or ExternalProject, during the configuration or the build of a CMake project.
+type CMakeExternalDependency struct {
+	Name string
+	// GitCMakeMethod or UrlCMakeMethod.
+	Method     string
+	Repository string
+	// The GIT_TAG of a Git dependency, which may be a branch, a tag or a commit.
+	Tag string
+	// The commit checked out in the source directory of a Git dependency.
+	Commit string
+	Url    string
+	// The expected hash of the archive of a URL dependency, in the <algorithm>=<value> format, for example: SHA256=3f4d...
+	Hash string
+	// The path of the downloaded archive of a URL dependency, if it's kept in the download directory.
+	ArchivePath  string
+	SourceDir    string
+	FetchContent bool
+}
+
+// ReadCMakeProjectName returns the name and the version declared by the project() command of the CMakeLists.txt in the project's directory.
+// If the project's CMakeLists.txt doesn't declare its name, the name is read from the CMakeCache.txt of the build directory.
+func ReadCMakeProjectName(projectDir, buildDir string) (name, version string, err error) {
+	content, err := os.ReadFile(filepath.Join(projectDir, CMakeListsFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", err
+	}
+	if match := cmakeProjectRegex.FindStringSubmatch(removeCMakeComments(string(content))); match != nil {
+		name = strings.Trim(match[1], `"`)
+		if versionMatch := cmakeProjectVersionRegex.FindStringSubmatch(match[2]); versionMatch != nil {
+			version = strings.Trim(versionMatch[1], `"`)
+		}
+		return name, version, nil
+	}
+	cache, err := readCMakeCache(buildDir)
+	if err != nil {
+		return "", "", err
+	}
+	return cache["CMAKE_PROJECT_NAME"], "", nil
+}
+
+var (
+	cmakeProjectRegex        = regexp.MustCompile(`(?is)\bproject\s*\(\s*("[^"]+"|[^\s)]+)([^)]*)\)`)
+	cmakeProjectVersionRegex = regexp.MustCompile(`(?i)\bVERSION\s+("[^"]+"|[^\s)]+)`)
+	cmakeCommentRegex        = regexp.MustCompile(`(?m)^\s*#.*$`)
+	// An entry of CMakeCache.txt, for example: CMAKE_PROJECT_NAME:STATIC=myapp
+	cmakeCacheEntryRegex = regexp.MustCompile(`^([^#/:=][^:=]*):[A-Z]+=(.*)$`)
+)
+
+// Removes the comment lines. The comments at the ends of lines are kept, since a # may be part of an argument, such as a URL.
+func removeCMakeComments(content string) string {
+	return cmakeCommentRegex.ReplaceAllString(content, "")
+}
+
+// Returns the entries of the CMakeCache.txt in the build directory, or an empty map if it doesn't exist.
+func readCMakeCache(buildDir string) (map[string]string, error) {
+	entries := make(map[string]string)
+	cacheFile, err := os.Open(filepath.Join(buildDir, CMakeCacheFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		_ = cacheFile.Close()
+	}()
+	scanner := bufio.NewScanner(cacheFile)
+	for scanner.Scan() {
+		if match := cmakeCacheEntryRegex.FindStringSubmatch(scanner.Text()); match != nil {
+			entries[match[1]] = match[2]
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// FindCMakeExternalDependencies returns the dependencies downloaded into the build directory by FetchContent (into its _deps directory)
+// and by ExternalProject (into the <name>-prefix directories), sorted by their names.
+// The details of each dependency are read from the repository info files which CMake writes into the dependency's stamp directory,
+// and, for FetchContent, from the ExternalProject_Add call in the CMakeLists.txt of the dependency's sub-build.
+func FindCMakeExternalDependencies(buildDir string) ([]*CMakeExternalDependency, error) {
+	patterns := []string{filepath.Join(buildDir, FetchContentDepsDirName, "*-subbuild", "*-prefix", "src", "*-stamp")}
+	prefixDir := buildDir
+	for depth := 0; depth < externalProjectMaxDepth; depth++ {
+		patterns = append(patterns, filepath.Join(prefixDir, "*-prefix", "src", "*-stamp"))
+		prefixDir = filepath.Join(prefixDir, "*")
+	}
+	dependencies := make(map[string]*CMakeExternalDependency)
+	for _, pattern := range patterns {
+		stampDirs, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, stampDir := range stampDirs {
+			dependency, err := readCMakeStampDir(buildDir, stampDir)
+			if err != nil {
+				return nil, err
+			}
+			if dependency != nil && dependencies[dependency.Name] == nil {
+				dependencies[dependency.Name] = dependency
+			}
+		}
+	}
+	var sorted []*CMakeExternalDependency
+	for _, dependency := range dependencies {
+		sorted = append(sorted, dependency)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted, nil
+}
+
+// Reads the <name>-gitinfo.txt or <name>-urlinfo.txt of an external project's stamp directory. Returns nil if the stamp directory has neither of them.
+func readCMakeStampDir(buildDir, stampDir string) (*CMakeExternalDependency, error) {
+	projectName := strings.TrimSuffix(filepath.Base(stampDir), "-stamp")
+	dependency := &CMakeExternalDependency{Name: projectName}
+	var info map[string]string
+	for _, method := range []string{GitCMakeMethod, UrlCMakeMethod} {
+		content, err := os.ReadFile(filepath.Join(stampDir, projectName+"-"+method+"info.txt"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		dependency.Method = method
+		info = parseCMakeRepositoryInfo(string(content))
+		break
+	}
+	if info == nil {
+		return nil, nil
+	}
+	dependency.Repository = info["repository"]
+	dependency.Tag = info["tag"]
+	dependency.Url = strings.Split(info["url(s)"], ";")[0]
+	dependency.Hash = info["hash"]
+	dependency.SourceDir = info["source_dir"]
+	// FetchContent names the external project of each dependency <name>-populate, in the dependency's sub-build.
+	if strings.HasPrefix(stampDir, filepath.Join(buildDir, FetchContentDepsDirName)+string(filepath.Separator)) {
+		dependency.FetchContent = true
+		dependency.Name = strings.TrimSuffix(projectName, "-populate")
+		subBuildDir := filepath.Join(buildDir, FetchContentDepsDirName, dependency.Name+"-subbuild")
+		if err := dependency.readFetchContentSubBuild(subBuildDir); err != nil {
+			return nil, err
+		}
+		if dependency.SourceDir == "" {
+			dependency.SourceDir = filepath.Join(buildDir, FetchContentDepsDirName, dependency.Name+"-src")
+		}
+	} else if dependency.SourceDir == "" {
+		dependency.SourceDir = filepath.Join(filepath.Dir(stampDir), projectName)
+	}
+	if dependency.Method == GitCMakeMethod {
+		commit, err := ReadGitHeadCommit(dependency.SourceDir)
+		if err != nil {
+			return nil, err
+		}
+		dependency.Commit = commit
+	} else if archiveName := GetUrlFileName(dependency.Url); archiveName != "" {
+		// The archive is downloaded into the src directory of the external project's prefix, next to the stamp directory.
+		archivePath := filepath.Join(filepath.Dir(stampDir), archiveName)
+		if _, err := os.Stat(archivePath); err == nil {
+			dependency.ArchivePath = archivePath
+		}
+	}
+	return dependency, nil
+}
+
+// Parses the key=value lines of a repository info file. The values of old CMake versions are quoted.
+func parseCMakeRepositoryInfo(content string) map[string]string {
+	info := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, found := strings.Cut(line, "="); found {
+			info[key] = strings.Trim(value, "'")
+		}
+	}
+	return info
+}
+
+// The arguments of the ExternalProject_Add call, which are followed by a value.
+var externalProjectValueArgs = map[string]bool{
+	"GIT_REPOSITORY": true, "GIT_TAG": true, "URL": true, "URL_HASH": true, "URL_MD5": true, "SOURCE_DIR": true, "DOWNLOAD_NAME": true,
+}
+
+var (
+	// The generated ExternalProject_Add call, whose closing parenthesis is at the end of a line.
+	externalProjectAddRegex = regexp.MustCompile(`(?ism)ExternalProject_Add\s*\((.*?)\)\s*$`)
+	cmakeArgumentRegex      = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"|[^\s"]+`)
+)
+
+// Reads the GIT_TAG, URL_HASH and other arguments of the ExternalProject_Add call in the CMakeLists.txt of a FetchContent sub-build.
+// FetchContent of CMake 3.30 and above may populate the dependencies without a sub-build, in which case the repository info files are the only source.
+func (dependency *CMakeExternalDependency) readFetchContentSubBuild(subBuildDir string) error {
+	content, err := os.ReadFile(filepath.Join(subBuildDir, CMakeListsFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	match := externalProjectAddRegex.FindStringSubmatch(removeCMakeComments(string(content)))
+	if match == nil {
+		return nil
+	}
+	args := splitCMakeArguments(match[1])
+	for i := 0; i < len(args)-1; i++ {
+		if !externalProjectValueArgs[args[i]] {
+			continue
+		}
+		value := args[i+1]
+		switch args[i] {
+		case "GIT_REPOSITORY":
+			dependency.Method, dependency.Repository = GitCMakeMethod, value
+		case "GIT_TAG":
+			dependency.Tag = value
+		case "URL":
+			dependency.Method, dependency.Url = UrlCMakeMethod, strings.Split(value, ";")[0]
+		case "URL_HASH":
+			dependency.Hash = value
+		case "URL_MD5":
+			dependency.Hash = "MD5=" + value
+		case "SOURCE_DIR":
+			dependency.SourceDir = value
+		}
+		i++
+	}
+	return nil
+}
+
+// Splits the arguments of a CMake command, which are separated by whitespace, and may be quoted.
+func splitCMakeArguments(content string) []string {
+	var args []string
+	for _, match := range cmakeArgumentRegex.FindAllStringSubmatch(content, -1) {
+		if strings.HasPrefix(match[0], `"`) {
+			args = append(args, match[1])
+		} else {
+			args = append(args, match[0])
+		}
+	}
+	return args
+}
+
+// ReadGitHeadCommit returns the commit checked out in a Git working tree, or an empty string if the directory isn't a Git working tree.
+func ReadGitHeadCommit(workTree string) (string, error) {
+	gitDir := filepath.Join(workTree, ".git")
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	ref, isRef := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: ")
+	if !isRef {
+		return ref, nil
+	}
+	commit, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref)))
+	if err == nil {
+		return strings.TrimSpace(string(commit)), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+	// The ref may only be in the packed refs.
+	packedRefs, err := os.ReadFile(filepath.Join(gitDir, "packed-refs"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, line := range strings.Split(string(packedRefs), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[1] == ref {
+			return fields[0], nil
+		}
+	}
+	return "", nil
+}
+
+// GetCMakeArchiveType returns the extension of an archive's name, for example: tar.gz, or an empty string if the name doesn't have a known archive extension.
+func GetCMakeArchiveType(archiveName string) string {
+	for _, extension := range cmakeArchiveExtensions {
+		if strings.HasSuffix(strings.ToLower(archiveName), extension) {
+			return extension[1:]
+		}
+	}
+	return ""
+}
+
+// GetCMakeArchiveVersion returns the version in the name of a dependency's archive, for example: 1.14.0 for googletest-1.14.0.tar.gz, or v1.14.0 for v1.14.0.zip
+func GetCMakeArchiveVersion(dependencyName, archiveName string) string {
+	version := archiveName
+	if archiveType := GetCMakeArchiveType(archiveName); archiveType != "" {
+		version = archiveName[:len(archiveName)-len(archiveType)-1]
+	}
+	if len(version) > len(dependencyName)+1 && strings.EqualFold(version[:len(dependencyName)+1], dependencyName+"-") {
+		version = version[len(dependencyName)+1:]
+	}
+	return version
+}
+
+// GetUrlFileName returns the last segment of a URL's path, without its query, for example: v1.14.0.tar.gz
+// Returns an empty string if the URL doesn't have a path.
+func GetUrlFileName(rawUrl string) string {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil || parsed.Path == "" {
+		return ""
+	}
+	fileName := path.Base(parsed.Path)
+	if fileName == "/" || fileName == "." {
+		return ""
+	}
+	return fileName
+}
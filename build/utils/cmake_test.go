@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGoogletestCommit = "f8d7d77c06936315286eb55f8de22cd23c188571"
+
+func TestFindCMakeExternalDependencies(t *testing.T) {
+	buildDir := t.TempDir()
+	depsDir := filepath.Join(buildDir, FetchContentDepsDirName)
+	// googletest is fetched by FetchContent from Git, and its tag is only in the sub-build's CMakeLists.txt.
+	writeCMakeTestFile(t, filepath.Join(depsDir, "googletest-subbuild", "googletest-populate-prefix", "src", "googletest-populate-stamp", "googletest-populate-gitinfo.txt"),
+		"# This is a generated file.\nrepository='https://github.com/google/googletest.git'\nmodule='googletest-populate'\ntag=''\n")
+	writeCMakeTestFile(t, filepath.Join(depsDir, "googletest-subbuild", CMakeListsFileName), `# Distributed under the OSI-approved BSD 3-Clause License.
+cmake_minimum_required(VERSION 3.25.1)
+project(googletest-populate NONE)
+
+include(ExternalProject)
+ExternalProject_Add(googletest-populate
+                     "UPDATE_DISCONNECTED" "False" "GIT_REPOSITORY" "https://github.com/google/googletest.git" "GIT_TAG" "v1.14.0"
+                    SOURCE_DIR          "`+filepath.ToSlash(filepath.Join(depsDir, "googletest-src"))+`"
+                    CONFIGURE_COMMAND   ""
+                    USES_TERMINAL_DOWNLOAD  YES
+)
+`)
+	writeCMakeTestFile(t, filepath.Join(depsDir, "googletest-src", ".git", "HEAD"), testGoogletestCommit+"\n")
+	// fmt is fetched by FetchContent from a URL, and its archive is kept in the download directory.
+	fmtStampDir := filepath.Join(depsDir, "fmt-subbuild", "fmt-populate-prefix", "src", "fmt-populate-stamp")
+	writeCMakeTestFile(t, filepath.Join(fmtStampDir, "fmt-populate-urlinfo.txt"),
+		"method=url\ncommand=\nsource_dir="+filepath.Join(depsDir, "fmt-src")+"\nwork_dir="+depsDir+"\nurl(s)=https://github.com/fmtlib/fmt/releases/download/10.2.1/fmt-10.2.1.zip\nhash=SHA256=312151a2d13c8327f5c9c586ac6cf7cddc1658e8f53edae0ec56509c8fa516c9\nno_extract=\n")
+	writeCMakeTestFile(t, filepath.Join(filepath.Dir(fmtStampDir), "fmt-10.2.1.zip"), "archive")
+	// zlib is added by ExternalProject_Add in a sub-directory of the build tree, and its branch is checked out.
+	zlibPrefix := filepath.Join(buildDir, "third_party", "zlib-prefix")
+	writeCMakeTestFile(t, filepath.Join(zlibPrefix, "src", "zlib-stamp", "zlib-gitinfo.txt"), "repository=https://github.com/madler/zlib.git\nmodule=zlib\ntag=develop\n")
+	writeCMakeTestFile(t, filepath.Join(zlibPrefix, "src", "zlib", ".git", "HEAD"), "ref: refs/heads/develop\n")
+	writeCMakeTestFile(t, filepath.Join(zlibPrefix, "src", "zlib", ".git", "packed-refs"), "# pack-refs with: peeled fully-peeled sorted\n51b7f2abdade71cd9bb0e7a373ef2610ec6f9daf refs/heads/develop\n")
+	// A stamp directory of an external project which wasn't downloaded.
+	require.NoError(t, os.MkdirAll(filepath.Join(buildDir, "local-prefix", "src", "local-stamp"), 0755))
+
+	dependencies, err := FindCMakeExternalDependencies(buildDir)
+	require.NoError(t, err)
+	assert.Equal(t, []*CMakeExternalDependency{
+		{
+			Name:         "fmt",
+			Method:       UrlCMakeMethod,
+			Url:          "https://github.com/fmtlib/fmt/releases/download/10.2.1/fmt-10.2.1.zip",
+			Hash:         "SHA256=312151a2d13c8327f5c9c586ac6cf7cddc1658e8f53edae0ec56509c8fa516c9",
+			ArchivePath:  filepath.Join(filepath.Dir(fmtStampDir), "fmt-10.2.1.zip"),
+			SourceDir:    filepath.Join(depsDir, "fmt-src"),
+			FetchContent: true,
+		},
+		{
+			Name:         "googletest",
+			Method:       GitCMakeMethod,
+			Repository:   "https://github.com/google/googletest.git",
+			Tag:          "v1.14.0",
+			Commit:       testGoogletestCommit,
+			SourceDir:    filepath.ToSlash(filepath.Join(depsDir, "googletest-src")),
+			FetchContent: true,
+		},
+		{
+			Name:       "zlib",
+			Method:     GitCMakeMethod,
+			Repository: "https://github.com/madler/zlib.git",
+			Tag:        "develop",
+			Commit:     "51b7f2abdade71cd9bb0e7a373ef2610ec6f9daf",
+			SourceDir:  filepath.Join(zlibPrefix, "src", "zlib"),
+		},
+	}, dependencies)
+
+	dependencies, err = FindCMakeExternalDependencies(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, dependencies)
+}
+
+func TestReadCMakeProjectName(t *testing.T) {
+	projectDir, buildDir := t.TempDir(), t.TempDir()
+	writeCMakeTestFile(t, filepath.Join(buildDir, CMakeCacheFileName), "# This is the CMakeCache file.\n//Value Computed by CMake\nCMAKE_PROJECT_NAME:STATIC=cached_app\n")
+	name, version, err := ReadCMakeProjectName(projectDir, buildDir)
+	require.NoError(t, err)
+	assert.Equal(t, "cached_app", name)
+	assert.Empty(t, version)
+
+	writeCMakeTestFile(t, filepath.Join(projectDir, CMakeListsFileName), `cmake_minimum_required(VERSION 3.24)
+# project(commented_out)
+project(my_app
+    VERSION 1.2.3
+    LANGUAGES CXX)
+`)
+	name, version, err = ReadCMakeProjectName(projectDir, buildDir)
+	require.NoError(t, err)
+	assert.Equal(t, "my_app", name)
+	assert.Equal(t, "1.2.3", version)
+}
+
+func TestGetCMakeArchiveVersion(t *testing.T) {
+	assert.Equal(t, "10.2.1", GetCMakeArchiveVersion("fmt", "fmt-10.2.1.zip"))
+	assert.Equal(t, "v1.14.0", GetCMakeArchiveVersion("googletest", "v1.14.0.tar.gz"))
+	assert.Equal(t, "tar.gz", GetCMakeArchiveType("v1.14.0.tar.gz"))
+	assert.Empty(t, GetCMakeArchiveType("archive"))
+	assert.Equal(t, "v1.14.0.tar.gz", GetUrlFileName("https://github.com/google/googletest/archive/refs/tags/v1.14.0.tar.gz?raw=true"))
+	assert.Empty(t, GetUrlFileName("https://example.com"))
+}
+
+func writeCMakeTestFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a runner which returns the stubbed standard output of each command, by the command's arguments, and records the directories the commands ran in.
+func stubCommandRunner(t *testing.T, outputs map[string]string, dirs *[]string) utils.CommandRunner {
+	return utils.CommandRunnerFunc(func(command *exec.Cmd) ([]byte, []byte, error) {
+		*dirs = append(*dirs, command.Dir)
+		args := strings.Join(command.Args[1:], " ")
+		output, stubbed := outputs[args]
+		if !stubbed {
+			t.Errorf("unexpected command: %s", args)
+			return nil, []byte("unexpected command"), errors.New("exit status 1")
+		}
+		return []byte(output), nil, nil
+	})
+}
+
+func TestYarnDependenciesWithStubbedCommands(t *testing.T) {
+	var dirs []string
+	restore := utils.SetCommandRunner(stubCommandRunner(t, map[string]string{
+		"--version": "4.1.0\n",
+		"info --all --recursive --json": `{"value":"my-app@workspace:.","children":{"Version":"0.0.0-use.local","Dependencies":[{"locator":"lodash@npm:4.17.21"}]}}
+{"value":"lodash@npm:4.17.21","children":{"Version":"4.17.21"}}`,
+	}, &dirs))
+	defer restore()
+
+	dependencies, root, err := GetYarnDependencies("yarn", "/projects/my-app", &PackageInfo{Name: "my-app", Version: "1.0.0"}, &utils.NullLog{}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", root.Details.Version)
+	assert.Len(t, dependencies, 2)
+	assert.Equal(t, "4.17.21", dependencies["lodash@npm:4.17.21"].Details.Version)
+	assert.Equal(t, []string{"/projects/my-app", "/projects/my-app"}, dirs)
+}
+
+func TestMixEnvironmentsDependenciesWithStubbedCommands(t *testing.T) {
+	var dirs []string
+	restore := utils.SetCommandRunner(stubCommandRunner(t, map[string]string{
+		"deps.tree --format plain --only prod": "my_app\n|-- jason ~> 1.4 (Hex package)\n`-- plug ~> 1.15 (Hex package)\n    `-- mime ~> 2.0 (Hex package)\n",
+		"deps.tree --format plain --only dev":  "my_app\n`-- credo ~> 1.7 (Hex package)\n",
+		"deps.tree --format plain --only test": "my_app\n",
+	}, &dirs))
+	defer restore()
+
+	environmentsDependencies, directDependencies, err := GetMixEnvironmentsDependencies("mix", "/projects/my_app")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"prod": {"jason", "plug", "mime"}, "dev": {"credo"}, "test": nil}, environmentsDependencies)
+	assert.Equal(t, []string{"jason", "plug", "credo"}, directDependencies)
+
+	// A failure of the command is reported with its standard error.
+	utils.SetCommandRunner(utils.CommandRunnerFunc(func(command *exec.Cmd) ([]byte, []byte, error) {
+		return nil, []byte("** (Mix) Could not find a Mix project"), &exec.ExitError{}
+	}))
+	_, _, err = GetMixEnvironmentsDependencies("mix", "/projects/my_app")
+	assert.ErrorContains(t, err, "Could not find a Mix project")
+}
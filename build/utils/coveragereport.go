@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CoverageFormat is the format of a code coverage report.
+type CoverageFormat string
+
+const (
+	LcovCoverage      CoverageFormat = "lcov"
+	CoberturaCoverage CoverageFormat = "cobertura"
+	GoCoverProfile    CoverageFormat = "go-cover"
+)
+
+// CoverageResults summarizes a code coverage report.
+// The coverage of lcov and Cobertura reports is measured in lines, and the coverage of Go cover profiles in statements.
+type CoverageResults struct {
+	Covered int
+	Total   int
+}
+
+// Add adds the results of another coverage report to these results.
+func (cr *CoverageResults) Add(other CoverageResults) {
+	cr.Covered += other.Covered
+	cr.Total += other.Total
+}
+
+// Percentage returns the percentage of the covered lines or statements, or 0 if there are none.
+func (cr *CoverageResults) Percentage() float64 {
+	if cr.Total == 0 {
+		return 0
+	}
+	return float64(cr.Covered) * 100 / float64(cr.Total)
+}
+
+// ReadCoverageReport reads a code coverage report, and returns the summary of its results and its format.
+// The supported formats are lcov, Cobertura XML and Go cover profiles (go test -coverprofile). The format is detected from the report's content.
+func ReadCoverageReport(reportPath string) (CoverageResults, CoverageFormat, error) {
+	content, err := os.ReadFile(reportPath)
+	if err != nil {
+		return CoverageResults{}, "", err
+	}
+	var results CoverageResults
+	var format CoverageFormat
+	switch trimmed := bytes.TrimSpace(content); {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		format = GoCoverProfile
+		results, err = ParseGoCoverProfile(bytes.NewReader(content))
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		format = CoberturaCoverage
+		results, err = ParseCoberturaReport(bytes.NewReader(content))
+	default:
+		format = LcovCoverage
+		results, err = ParseLcovReport(bytes.NewReader(content))
+	}
+	if err != nil {
+		return CoverageResults{}, "", fmt.Errorf("failed to parse the coverage report %s: %w", reportPath, err)
+	}
+	return results, format, nil
+}
+
+// ParseLcovReport parses an lcov tracefile, as written by Istanbul (nyc, Jest), coverage.py (coverage lcov), genhtml and others.
+// The lines of each file are taken from its LF and LH summary records, or counted from its DA records if they're missing.
+func ParseLcovReport(report io.Reader) (results CoverageResults, err error) {
+	var (
+		records                        int
+		found, hit, dataTotal, dataHit int
+		hasSummary                     bool
+	)
+	scanner := bufio.NewScanner(report)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		switch key {
+		case "LF":
+			if found, err = strconv.Atoi(value); err != nil {
+				return
+			}
+			hasSummary = true
+		case "LH":
+			if hit, err = strconv.Atoi(value); err != nil {
+				return
+			}
+			hasSummary = true
+		case "DA":
+			// DA:<line number>,<execution count>[,<checksum>]
+			fields := strings.Split(value, ",")
+			if len(fields) < 2 {
+				return results, fmt.Errorf("invalid DA record: %s", value)
+			}
+			dataTotal++
+			if fields[1] != "0" {
+				dataHit++
+			}
+		case "end_of_record":
+			if hasSummary {
+				results.Add(CoverageResults{Covered: hit, Total: found})
+			} else {
+				results.Add(CoverageResults{Covered: dataHit, Total: dataTotal})
+			}
+			records++
+			found, hit, dataTotal, dataHit, hasSummary = 0, 0, 0, 0, false
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return
+	}
+	if records == 0 {
+		return results, errors.New("the report isn't an lcov tracefile, a Cobertura XML report or a Go cover profile")
+	}
+	return
+}
+
+type coberturaReport struct {
+	XMLName      xml.Name `xml:"coverage"`
+	LinesValid   string   `xml:"lines-valid,attr"`
+	LinesCovered string   `xml:"lines-covered,attr"`
+	Packages     []struct {
+		Classes []struct {
+			Lines []struct {
+				Hits string `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// ParseCoberturaReport parses a Cobertura XML report, as written by coverage.py (coverage xml), gocover-cobertura, JaCoCo converters and others.
+// The lines are taken from the lines-valid and lines-covered attributes of the report, or counted from its classes if they're missing.
+func ParseCoberturaReport(report io.Reader) (results CoverageResults, err error) {
+	var coverage coberturaReport
+	if err = xml.NewDecoder(report).Decode(&coverage); err != nil {
+		return
+	}
+	if coverage.LinesValid != "" && coverage.LinesCovered != "" {
+		if results.Total, err = strconv.Atoi(coverage.LinesValid); err != nil {
+			return
+		}
+		results.Covered, err = strconv.Atoi(coverage.LinesCovered)
+		return
+	}
+	for _, coberturaPackage := range coverage.Packages {
+		for _, class := range coberturaPackage.Classes {
+			for _, line := range class.Lines {
+				results.Total++
+				if line.Hits != "" && line.Hits != "0" {
+					results.Covered++
+				}
+			}
+		}
+	}
+	return
+}
+
+// A block of a Go cover profile.
+type goCoverBlock struct {
+	statements int
+	covered    bool
+}
+
+// ParseGoCoverProfile parses a Go cover profile, written by 'go test -coverprofile'.
+// A block which appears several times, as in profiles merged from several test runs, is counted once, and is covered if any of its appearances is covered.
+func ParseGoCoverProfile(report io.Reader) (results CoverageResults, err error) {
+	blocks := make(map[string]*goCoverBlock)
+	var blockKeys []string
+	scanner := bufio.NewScanner(report)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		// <file>:<start line>.<start column>,<end line>.<end column> <number of statements> <count>
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return results, fmt.Errorf("invalid cover profile line: %s", line)
+		}
+		var statements, count int
+		if statements, err = strconv.Atoi(fields[1]); err != nil {
+			return
+		}
+		if count, err = strconv.Atoi(fields[2]); err != nil {
+			return
+		}
+		block, exists := blocks[fields[0]]
+		if !exists {
+			block = &goCoverBlock{statements: statements}
+			blocks[fields[0]] = block
+			blockKeys = append(blockKeys, fields[0])
+		}
+		block.covered = block.covered || count > 0
+	}
+	if err = scanner.Err(); err != nil {
+		return
+	}
+	for _, key := range blockKeys {
+		results.Total += blocks[key].statements
+		if blocks[key].covered {
+			results.Covered += blocks[key].statements
+		}
+	}
+	return
+}
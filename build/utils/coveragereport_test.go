@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCoverageReport(t *testing.T) {
+	tests := []struct {
+		name           string
+		report         string
+		expected       CoverageResults
+		expectedFormat CoverageFormat
+	}{
+		{
+			name: "lcov",
+			report: `TN:
+SF:src/app.js
+DA:1,1
+DA:2,0
+LF:10
+LH:8
+end_of_record
+SF:src/lib.js
+DA:1,5
+DA:2,0
+DA:3,1,abc
+end_of_record
+`,
+			expected:       CoverageResults{Covered: 10, Total: 13},
+			expectedFormat: LcovCoverage,
+		},
+		{
+			name: "cobertura",
+			report: `<?xml version="1.0" ?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage line-rate="0.75" lines-valid="40" lines-covered="30" version="7.3.2"></coverage>`,
+			expected:       CoverageResults{Covered: 30, Total: 40},
+			expectedFormat: CoberturaCoverage,
+		},
+		{
+			name: "cobertura-without-totals",
+			report: `<coverage line-rate="0.5">
+  <packages><package name="app"><classes><class name="app.py">
+    <methods><method name="run"><lines><line number="1" hits="1"/></lines></method></methods>
+    <lines><line number="1" hits="1"/><line number="2" hits="0"/><line number="3" hits="4"/></lines>
+  </class></classes></package></packages>
+</coverage>`,
+			expected:       CoverageResults{Covered: 2, Total: 3},
+			expectedFormat: CoberturaCoverage,
+		},
+		{
+			name: "go-cover",
+			report: `mode: set
+example.com/app/main.go:5.13,7.2 2 1
+example.com/app/main.go:9.20,11.2 3 0
+example.com/app/lib.go:3.10,5.2 1 0
+example.com/app/lib.go:3.10,5.2 1 1
+`,
+			expected:       CoverageResults{Covered: 3, Total: 6},
+			expectedFormat: GoCoverProfile,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reportPath := filepath.Join(t.TempDir(), "coverage")
+			require.NoError(t, os.WriteFile(reportPath, []byte(test.report), 0644))
+			results, format, err := ReadCoverageReport(reportPath)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, results)
+			assert.Equal(t, test.expectedFormat, format)
+		})
+	}
+
+	reportPath := filepath.Join(t.TempDir(), "coverage.txt")
+	require.NoError(t, os.WriteFile(reportPath, []byte("total: 75%\n"), 0644))
+	_, _, err := ReadCoverageReport(reportPath)
+	assert.Error(t, err)
+}
+
+func TestCoverageResultsPercentage(t *testing.T) {
+	results := CoverageResults{Covered: 1, Total: 8}
+	assert.Equal(t, 12.5, results.Percentage())
+	assert.Zero(t, (&CoverageResults{}).Percentage())
+}
@@ -119,6 +119,8 @@ func (assets *assets) getAllDependencies(log utils.Log) (map[string]*buildinfo.D
 
 		dependencyName := getDependencyName(dependencyId)
 		dependencies[dependencyName] = &buildinfo.Dependency{Id: getDependencyIdForBuildInfo(dependencyId), Checksum: buildinfo.Checksum{Sha1: fileDetails.Checksum.Sha1, Md5: fileDetails.Checksum.Md5}}
+		addNupkgSourceAndSignature(dependencies[dependencyName], nupkgFilePath, log)
+		dependencies[dependencyName].SetResolutionSource(buildinfo.LockfileSource)
 	}
 
 	return dependencies, nil
@@ -0,0 +1,116 @@
+package dependencies
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+)
+
+const (
+	// The dependency property which describes the signatures of a NuGet package.
+	NupkgSignatureProperty = "nuget.signature"
+	// The metadata file which NuGet writes next to each package it extracts to the global packages folder.
+	nupkgMetadataFileName = ".nupkg.metadata"
+	// The signature file in the root of a signed package.
+	nupkgSignatureFileName = ".signature.p7s"
+)
+
+// The values of NupkgSignatureProperty.
+const (
+	NupkgUnsigned = "unsigned"
+	// The package is signed by its author.
+	NupkgAuthorSigned = "author"
+	// The package is signed by the repository it was published to, such as nuget.org.
+	NupkgRepositorySigned = "repository"
+	// The package is signed by its author, and countersigned by the repository.
+	NupkgAuthorAndRepositorySigned = "author+repository"
+)
+
+// The DER encodings of the commitment types of the signatures, which distinguish the author signatures from the repository signatures.
+var (
+	// The proof of origin commitment type (1.2.840.113549.1.9.16.6.1), which is indicated by author signatures.
+	proofOfOriginOid = []byte{0x06, 0x0b, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d, 0x01, 0x09, 0x10, 0x06, 0x01}
+	// The proof of receipt commitment type (1.2.840.113549.1.9.16.6.2), which is indicated by repository signatures.
+	proofOfReceiptOid = []byte{0x06, 0x0b, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d, 0x01, 0x09, 0x10, 0x06, 0x02}
+)
+
+// Adds the feed from which a package in the global packages folder was restored, and the package's signatures, to its dependency.
+// The feed is read from the package's .nupkg.metadata file, and the signatures from the .nupkg file.
+// These details are optional, so failing to read them doesn't fail the collection.
+func addNupkgSourceAndSignature(dependency *buildinfo.Dependency, nupkgPath string, log utils.Log) {
+	source, err := readNupkgSource(filepath.Dir(nupkgPath))
+	if err != nil {
+		log.Debug("Couldn't read the source of", nupkgPath+":", err.Error())
+	}
+	dependency.RemoteRepository = source
+	signature, err := readNupkgSignature(nupkgPath)
+	if err != nil {
+		log.Debug("Couldn't read the signature of", nupkgPath+":", err.Error())
+		return
+	}
+	dependency.Properties = map[string]string{NupkgSignatureProperty: signature}
+}
+
+// Returns the feed from which the package in the directory was restored, or an empty string if it isn't recorded.
+func readNupkgSource(packageDir string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(packageDir, nupkgMetadataFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	var metadata struct {
+		Source string `json:"source"`
+	}
+	return metadata.Source, json.Unmarshal(content, &metadata)
+}
+
+// Returns the NupkgSignatureProperty value of a package, which is determined by the commitment types of the signatures in its signature file.
+func readNupkgSignature(nupkgPath string) (signature string, err error) {
+	nupkg, err := zip.OpenReader(nupkgPath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		err = errors.Join(err, nupkg.Close())
+	}()
+	for _, file := range nupkg.File {
+		if file.Name != nupkgSignatureFileName {
+			continue
+		}
+		content, err := readZipFile(file)
+		if err != nil {
+			return "", err
+		}
+		authorSigned, repositorySigned := bytes.Contains(content, proofOfOriginOid), bytes.Contains(content, proofOfReceiptOid)
+		switch {
+		case authorSigned && repositorySigned:
+			return NupkgAuthorAndRepositorySigned, nil
+		case authorSigned:
+			return NupkgAuthorSigned, nil
+		case repositorySigned:
+			return NupkgRepositorySigned, nil
+		}
+		return "", errors.New("the signature file doesn't indicate an author or a repository signature")
+	}
+	return NupkgUnsigned, nil
+}
+
+func readZipFile(file *zip.File) (content []byte, err error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, reader.Close())
+	}()
+	return io.ReadAll(reader)
+}
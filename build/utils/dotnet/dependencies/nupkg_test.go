@@ -0,0 +1,70 @@
+package dependencies
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddNupkgSourceAndSignature(t *testing.T) {
+	packageDir := filepath.Join(t.TempDir(), "newtonsoft.json", "13.0.3")
+	require.NoError(t, os.MkdirAll(packageDir, 0755))
+	writeNupkg := func(signature []byte) string {
+		nupkgPath := filepath.Join(packageDir, "newtonsoft.json.13.0.3.nupkg")
+		nupkgFile, err := os.Create(nupkgPath)
+		require.NoError(t, err)
+		nupkg := zip.NewWriter(nupkgFile)
+		files := map[string][]byte{"newtonsoft.json.nuspec": []byte("<package/>")}
+		if signature != nil {
+			files[nupkgSignatureFileName] = signature
+		}
+		for name, content := range files {
+			writer, err := nupkg.Create(name)
+			require.NoError(t, err)
+			_, err = writer.Write(content)
+			require.NoError(t, err)
+		}
+		require.NoError(t, nupkg.Close())
+		require.NoError(t, nupkgFile.Close())
+		return nupkgPath
+	}
+	// The commitment type indications are embedded in the signed attributes of the signatures, among other DER-encoded data.
+	signatureWith := func(oids ...[]byte) []byte {
+		signature := []byte{0x30, 0x82, 0x01, 0x00}
+		for _, oid := range oids {
+			signature = append(append(signature, 0x30, byte(len(oid))), oid...)
+		}
+		return signature
+	}
+
+	dependency := &buildinfo.Dependency{Id: "Newtonsoft.Json:13.0.3"}
+	addNupkgSourceAndSignature(dependency, writeNupkg(nil), logger)
+	assert.Empty(t, dependency.RemoteRepository)
+	assert.Equal(t, map[string]string{NupkgSignatureProperty: NupkgUnsigned}, dependency.Properties)
+
+	require.NoError(t, os.WriteFile(filepath.Join(packageDir, nupkgMetadataFileName), []byte(`{"version": 2, "contentHash": "HrC5BXdl00IP9zeV+0Z848QWPAoCr9P3bDEZguI+gkLcBKAOxix/tLEAAHC+UvDNPv4a2d18lOReHMOagPa+zQ==", "source": "https://api.nuget.org/v3/index.json"}`), 0644))
+	testCases := []struct {
+		signature []byte
+		expected  string
+	}{
+		{signatureWith(proofOfOriginOid), NupkgAuthorSigned},
+		{signatureWith(proofOfReceiptOid), NupkgRepositorySigned},
+		{signatureWith(proofOfOriginOid, proofOfReceiptOid), NupkgAuthorAndRepositorySigned},
+	}
+	for _, testCase := range testCases {
+		dependency = &buildinfo.Dependency{Id: "Newtonsoft.Json:13.0.3"}
+		addNupkgSourceAndSignature(dependency, writeNupkg(testCase.signature), logger)
+		assert.Equal(t, "https://api.nuget.org/v3/index.json", dependency.RemoteRepository)
+		assert.Equal(t, map[string]string{NupkgSignatureProperty: testCase.expected}, dependency.Properties)
+	}
+
+	// A signature without a commitment type isn't recorded.
+	dependency = &buildinfo.Dependency{Id: "Newtonsoft.Json:13.0.3"}
+	addNupkgSourceAndSignature(dependency, writeNupkg(signatureWith()), logger)
+	assert.Empty(t, dependency.Properties)
+}
@@ -10,7 +10,6 @@ import (
 	"unicode/utf16"
 
 	"github.com/jfrog/gofrog/crypto"
-	gofrogcmd "github.com/jfrog/gofrog/io"
 
 	"github.com/jfrog/build-info-go/build/utils/dotnet"
 	buildinfo "github.com/jfrog/build-info-go/entities"
@@ -218,6 +217,8 @@ func createNugetPackage(packagesPath string, nuget xmlPackage, nPackage *nugetPa
 		return nil, err
 	}
 	nPackage.dependency = &buildinfo.Dependency{Id: nuget.Id + ":" + nuget.Version, Checksum: buildinfo.Checksum{Sha1: fileDetails.Checksum.Sha1, Md5: fileDetails.Checksum.Md5}}
+	addNupkgSourceAndSignature(nPackage.dependency, nupkgPath, log)
+	nPackage.dependency.SetResolutionSource(buildinfo.LockfileSource)
 
 	// Nuspec file that holds the metadata for the package.
 	nuspecPath := filepath.Join(packagesPath, nPackage.id, nPackage.version, strings.Join([]string{nPackage.id, "nuspec"}, "."))
@@ -272,7 +273,7 @@ func (extractor *packagesExtractor) getGlobalPackagesCache() (string, error) {
 	localsCmd.Command = append(localsCmd.Command, []string{"locals", "global-packages"}...)
 	localsCmd.CommandFlags = []string{"-list"}
 
-	output, err := gofrogcmd.RunCmdOutput(localsCmd)
+	output, err := utils.RunCommandOutput(localsCmd)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/build-info-go/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// GenericDependency is an external input of the build, such as a firmware blob or a vendored SDK, declared in a dependencies file
+// with its expected checksums. Exactly one of Url and Path is set, and at least one of the checksums.
+type GenericDependency struct {
+	// The dependency's ID in the build-info. Defaults to the file name of the URL or the path.
+	Id  string `yaml:"id,omitempty"`
+	Url string `yaml:"url,omitempty"`
+	// The local path of the dependency. A relative path is relative to the directory of the dependencies file.
+	Path   string `yaml:"path,omitempty"`
+	Sha256 string `yaml:"sha256,omitempty"`
+	Sha1   string `yaml:"sha1,omitempty"`
+	Md5    string `yaml:"md5,omitempty"`
+}
+
+// FileName returns the name of the dependency's file, which is the last element of its URL or path.
+func (gd GenericDependency) FileName() string {
+	if gd.Url != "" {
+		urlPath, _, _ := strings.Cut(gd.Url, "?")
+		return path.Base(urlPath)
+	}
+	return filepath.Base(gd.Path)
+}
+
+// The content of a YAML dependencies file.
+type genericDependenciesYaml struct {
+	Dependencies []GenericDependency `yaml:"dependencies"`
+}
+
+// ReadGenericDependencies reads a dependencies file, which is either a YAML file (.yaml or .yml), for example:
+//
+//	dependencies:
+//	  - id: arm-sdk:2.1.0
+//	    url: https://downloads.example.com/arm-sdk-2.1.0.tar.gz
+//	    sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+//
+// or a CSV file with a header row, whose columns are any of: id, url, path, sha256, sha1 and md5.
+// The relative paths of the returned dependencies are resolved against the directory of the dependencies file.
+func ReadGenericDependencies(filePath string) ([]GenericDependency, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var dependencies []GenericDependency
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		var dependenciesYaml genericDependenciesYaml
+		if err = yaml.Unmarshal(content, &dependenciesYaml); err != nil {
+			return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing %s: %w", filePath, err))
+		}
+		dependencies = dependenciesYaml.Dependencies
+	case ".csv":
+		if dependencies, err = parseGenericDependenciesCsv(content); err != nil {
+			return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing %s: %w", filePath, err))
+		}
+	default:
+		return nil, fmt.Errorf("the dependencies file '%s' isn't supported. Supported formats are YAML (.yaml or .yml) and CSV (.csv)", filePath)
+	}
+	for i := range dependencies {
+		dependency := &dependencies[i]
+		if (dependency.Url == "") == (dependency.Path == "") {
+			return nil, fmt.Errorf("dependency %d in '%s' must have exactly one of 'url' and 'path'", i+1, filePath)
+		}
+		if dependency.Sha256 == "" && dependency.Sha1 == "" && dependency.Md5 == "" {
+			return nil, fmt.Errorf("dependency %d in '%s' must have at least one of the 'sha256', 'sha1' and 'md5' checksums", i+1, filePath)
+		}
+		if dependency.Path != "" && !filepath.IsAbs(dependency.Path) {
+			dependency.Path = filepath.Join(filepath.Dir(filePath), filepath.FromSlash(dependency.Path))
+		}
+		if dependency.Id == "" {
+			dependency.Id = dependency.FileName()
+		}
+	}
+	return dependencies, nil
+}
+
+func parseGenericDependenciesCsv(content []byte) (dependencies []GenericDependency, err error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+		return
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return dependencies, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var dependency GenericDependency
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			switch header[i] {
+			case "id":
+				dependency.Id = value
+			case "url":
+				dependency.Url = value
+			case "path":
+				dependency.Path = value
+			case "sha256":
+				dependency.Sha256 = value
+			case "sha1":
+				dependency.Sha1 = value
+			case "md5":
+				dependency.Md5 = value
+			default:
+				return nil, fmt.Errorf("unknown column '%s'. Supported columns are id, url, path, sha256, sha1 and md5", header[i])
+			}
+		}
+		dependencies = append(dependencies, dependency)
+	}
+}
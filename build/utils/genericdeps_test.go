@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadGenericDependencies(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "deps.yaml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte(`dependencies:
+  - id: arm-sdk:2.1.0
+    url: https://downloads.example.com/arm-sdk-2.1.0.tar.gz?token=secret
+    sha256: abc
+  - path: vendor/firmware/wifi.bin
+    sha1: def
+`), 0644))
+	dependencies, err := ReadGenericDependencies(yamlPath)
+	require.NoError(t, err)
+	assert.Equal(t, []GenericDependency{
+		{Id: "arm-sdk:2.1.0", Url: "https://downloads.example.com/arm-sdk-2.1.0.tar.gz?token=secret", Sha256: "abc"},
+		{Id: "wifi.bin", Path: filepath.Join(dir, "vendor", "firmware", "wifi.bin"), Sha1: "def"},
+	}, dependencies)
+	assert.Equal(t, "arm-sdk-2.1.0.tar.gz", dependencies[0].FileName())
+
+	csvPath := filepath.Join(dir, "deps.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("ID, Path, MD5\nsdk:1.0, sdk.zip, 123\n"), 0644))
+	dependencies, err = ReadGenericDependencies(csvPath)
+	require.NoError(t, err)
+	assert.Equal(t, []GenericDependency{{Id: "sdk:1.0", Path: filepath.Join(dir, "sdk.zip"), Md5: "123"}}, dependencies)
+}
+
+func TestReadGenericDependenciesInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"no-source.yaml":     "dependencies: [{id: sdk, sha256: abc}]",
+		"two-sources.yaml":   "dependencies: [{url: https://example.com/sdk.zip, path: sdk.zip, sha256: abc}]",
+		"no-checksum.yaml":   "dependencies: [{path: sdk.zip}]",
+		"unknown-column.csv": "path,sha512\nsdk.zip,abc\n",
+		"unsupported.json":   "{}",
+	} {
+		filePath := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+		_, err := ReadGenericDependencies(filePath)
+		assert.Error(t, err, name)
+	}
+}
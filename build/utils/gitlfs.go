@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The version line which starts the Git LFS pointer files.
+const gitLfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+
+// The Git LFS pointer files are smaller than this size, in bytes.
+const gitLfsMaxPointerSize = 1024
+
+var gitLfsOidRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// GitLfsPointer is the content of a Git LFS pointer file, which Git checks out instead of a large file whose object wasn't fetched.
+// See https://github.com/git-lfs/git-lfs/blob/main/docs/spec.md
+type GitLfsPointer struct {
+	// The SHA-256 of the large file.
+	Oid string
+	// The size of the large file in bytes.
+	Size int64
+}
+
+// ReadGitLfsPointer returns the Git LFS pointer in the file, or nil if the file isn't a Git LFS pointer.
+func ReadGitLfsPointer(filePath string) (*GitLfsPointer, error) {
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if !fileInfo.Mode().IsRegular() || fileInfo.Size() >= gitLfsMaxPointerSize {
+		return nil, nil
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGitLfsPointer(content), nil
+}
+
+// ParseGitLfsPointer parses the content of a Git LFS pointer file. Returns nil if the content isn't a valid pointer.
+func ParseGitLfsPointer(content []byte) *GitLfsPointer {
+	if !bytes.HasPrefix(content, []byte(gitLfsPointerVersion+"\n")) {
+		return nil
+	}
+	pointer := &GitLfsPointer{Size: -1}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+		switch key {
+		case "oid":
+			if oid, found := strings.CutPrefix(value, "sha256:"); found && gitLfsOidRegex.MatchString(oid) {
+				pointer.Oid = oid
+			}
+		case "size":
+			if size, err := strconv.ParseInt(value, 10, 64); err == nil && size >= 0 {
+				pointer.Size = size
+			}
+		}
+	}
+	if pointer.Oid == "" || pointer.Size < 0 {
+		return nil
+	}
+	return pointer
+}
+
+// FindGitLfsObject returns the path of the pointer's object in the local Git LFS storage of the repository which contains the pointer file,
+// or an empty string if the object wasn't fetched, or if the file isn't in a Git repository.
+// The storage is found in the Git directory of the repository, or in the common directory of the repository's worktrees.
+func FindGitLfsObject(pointerPath string, pointer *GitLfsPointer) (string, error) {
+	gitDir, err := findGitDir(pointerPath)
+	if err != nil || gitDir == "" {
+		return "", err
+	}
+	// A linked worktree shares the storage of its main repository.
+	if commonDir, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		gitDir = resolveGitPath(gitDir, strings.TrimSpace(string(commonDir)))
+	}
+	objectPath := filepath.Join(gitDir, "lfs", "objects", pointer.Oid[0:2], pointer.Oid[2:4], pointer.Oid)
+	if _, err = os.Stat(objectPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return objectPath, nil
+}
+
+// Returns the Git directory of the repository which contains the file, or an empty string if the file isn't in a repository.
+// In worktrees and submodules, '.git' is a file which points to the Git directory: gitdir: <path>
+func findGitDir(filePath string) (string, error) {
+	dir, err := filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return "", err
+	}
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		fileInfo, err := os.Stat(dotGit)
+		if err == nil {
+			if fileInfo.IsDir() {
+				return dotGit, nil
+			}
+			content, err := os.ReadFile(dotGit)
+			if err != nil {
+				return "", err
+			}
+			if gitDir, found := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir: "); found {
+				return resolveGitPath(dir, gitDir), nil
+			}
+			return "", nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+func resolveGitPath(baseDir, path string) string {
+	path = filepath.FromSlash(path)
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGitLfsOid = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+
+func TestParseGitLfsPointer(t *testing.T) {
+	pointer := ParseGitLfsPointer([]byte("version https://git-lfs.github.com/spec/v1\noid sha256:" + testGitLfsOid + "\nsize 12345\n"))
+	assert.Equal(t, &GitLfsPointer{Oid: testGitLfsOid, Size: 12345}, pointer)
+
+	for _, content := range []string{
+		"oid sha256:" + testGitLfsOid + "\nsize 12345\n",
+		"version https://git-lfs.github.com/spec/v1\noid sha256:" + testGitLfsOid + "\n",
+		"version https://git-lfs.github.com/spec/v1\noid sha256:abc\nsize 12345\n",
+		"version https://git-lfs.github.com/spec/v1\noid md5:" + testGitLfsOid + "\nsize 12345\n",
+	} {
+		assert.Nil(t, ParseGitLfsPointer([]byte(content)), content)
+	}
+}
+
+func TestFindGitLfsObject(t *testing.T) {
+	repoDir := t.TempDir()
+	pointerPath := filepath.Join(repoDir, "assets", "model.bin")
+	pointerContent := "version https://git-lfs.github.com/spec/v1\noid sha256:" + testGitLfsOid + "\nsize 5\n"
+	require.NoError(t, os.MkdirAll(filepath.Dir(pointerPath), 0755))
+	require.NoError(t, os.WriteFile(pointerPath, []byte(pointerContent), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, ".git"), 0755))
+
+	pointer, err := ReadGitLfsPointer(pointerPath)
+	require.NoError(t, err)
+	require.NotNil(t, pointer)
+	// The object wasn't fetched.
+	objectPath, err := FindGitLfsObject(pointerPath, pointer)
+	require.NoError(t, err)
+	assert.Empty(t, objectPath)
+
+	expectedPath := filepath.Join(repoDir, ".git", "lfs", "objects", "4d", "7a", testGitLfsOid)
+	require.NoError(t, os.MkdirAll(filepath.Dir(expectedPath), 0755))
+	require.NoError(t, os.WriteFile(expectedPath, []byte("model"), 0644))
+	objectPath, err = FindGitLfsObject(pointerPath, pointer)
+	require.NoError(t, err)
+	assert.Equal(t, expectedPath, objectPath)
+
+	// A linked worktree, whose .git file points to its Git directory, uses the storage of the main repository.
+	worktreeDir := t.TempDir()
+	worktreeGitDir := filepath.Join(repoDir, ".git", "worktrees", "feature")
+	require.NoError(t, os.MkdirAll(worktreeGitDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktreeGitDir, "commondir"), []byte("../..\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(worktreeDir, ".git"), []byte("gitdir: "+worktreeGitDir+"\n"), 0644))
+	worktreePointerPath := filepath.Join(worktreeDir, "model.bin")
+	require.NoError(t, os.WriteFile(worktreePointerPath, []byte(pointerContent), 0644))
+	objectPath, err = FindGitLfsObject(worktreePointerPath, pointer)
+	require.NoError(t, err)
+	assert.Equal(t, expectedPath, objectPath)
+
+	// Regular files aren't pointers.
+	pointer, err = ReadGitLfsPointer(expectedPath)
+	require.NoError(t, err)
+	assert.Nil(t, pointer)
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Matches a plugin applied with an ID and a version, for example: id("org.springframework.boot") version "3.1.0".
+	gradlePluginIdRegex = regexp.MustCompile(`^id\s*\(?\s*["']([^"']+)["']\s*\)?\s*version\s*\(?\s*["']([^"']+)["']`)
+	// Matches a Kotlin plugin applied with the 'kotlin' shortcut, for example: kotlin("jvm") version "1.9.0".
+	gradleKotlinPluginRegex = regexp.MustCompile(`^kotlin\s*\(\s*["']([^"']+)["']\s*\)\s*version\s*\(?\s*["']([^"']+)["']`)
+	// Matches the arguments of a dependency declared in the map notation, for example: group: 'com.example', name: 'lib', version: '1.0', classifier: 'natives'.
+	gradleMapNotationRegex = regexp.MustCompile(`\b(group|name|version|classifier|ext)\s*[:=]\s*["']([^"']+)["']`)
+)
+
+// GradleBuildScript holds the build tooling declared in a Gradle build script (build.gradle or build.gradle.kts).
+type GradleBuildScript struct {
+	// Dependencies declared with 'classpath' inside 'buildscript { dependencies {} }', in the group:name:version[:classifier][@extension] format.
+	Classpath []string
+	// Plugins applied inside the 'plugins {}' block with an explicit version.
+	Plugins []GradlePlugin
+}
+
+type GradlePlugin struct {
+	Id      string
+	Version string
+}
+
+// MarkerId returns the ID of the plugin marker artifact, which Gradle uses to resolve the plugin: <id>:<id>.gradle.plugin:<version>.
+func (gp GradlePlugin) MarkerId() string {
+	return gp.Id + ":" + gp.Id + ".gradle.plugin:" + gp.Version
+}
+
+// ReadGradleBuildScript reads and parses the Gradle build script in the provided directory.
+// The Kotlin DSL file (build.gradle.kts) is preferred over the Groovy DSL file (build.gradle).
+// If none of them exist, nil is returned.
+func ReadGradleBuildScript(projectDir string) (*GradleBuildScript, error) {
+	for _, fileName := range []string{"build.gradle.kts", "build.gradle"} {
+		content, err := os.ReadFile(filepath.Join(projectDir, fileName))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		return ParseGradleBuildScript(string(content)), nil
+	}
+	return nil, nil
+}
+
+// ParseGradleBuildScript parses the content of a Gradle build script, written in either the Groovy or the Kotlin DSL.
+// Plugins without a version, such as Gradle's core plugins, are ignored.
+func ParseGradleBuildScript(content string) *GradleBuildScript {
+	parser := &gradleBuildScriptParser{buildScript: &GradleBuildScript{}}
+	scanGradleScript(stripGradleComments(content), parser.openBlock, parser.handleStatement, parser.closeBlock)
+	return parser.buildScript
+}
+
+type gradleBuildScriptParser struct {
+	buildScript *GradleBuildScript
+	// The names of the currently open blocks, from the outermost to the innermost.
+	blocks []string
+}
+
+func (p *gradleBuildScriptParser) openBlock(header string) {
+	blockName := ""
+	if match := blockNameRegex.FindStringSubmatch(strings.TrimSpace(header)); match != nil {
+		blockName = match[1]
+	}
+	p.blocks = append(p.blocks, blockName)
+}
+
+func (p *gradleBuildScriptParser) closeBlock() {
+	if len(p.blocks) > 0 {
+		p.blocks = p.blocks[:len(p.blocks)-1]
+	}
+}
+
+func (p *gradleBuildScriptParser) handleStatement(statement string) {
+	statement = strings.TrimSpace(statement)
+	switch {
+	case statement == "":
+		return
+	case p.isInBlocks("buildscript", "dependencies") && hasGradleFunctionPrefix(statement, "classpath"):
+		if dependency := parseGradleDependencyNotation(statement); dependency != "" {
+			p.buildScript.Classpath = append(p.buildScript.Classpath, dependency)
+		}
+	case p.isInBlocks("plugins"):
+		if match := gradlePluginIdRegex.FindStringSubmatch(statement); match != nil {
+			p.buildScript.Plugins = append(p.buildScript.Plugins, GradlePlugin{Id: match[1], Version: match[2]})
+		} else if match = gradleKotlinPluginRegex.FindStringSubmatch(statement); match != nil {
+			p.buildScript.Plugins = append(p.buildScript.Plugins, GradlePlugin{Id: "org.jetbrains.kotlin." + match[1], Version: match[2]})
+		}
+	}
+}
+
+// Returns true if the open blocks are exactly the provided ones, from the outermost to the innermost.
+func (p *gradleBuildScriptParser) isInBlocks(blocks ...string) bool {
+	if len(p.blocks) != len(blocks) {
+		return false
+	}
+	for i := range blocks {
+		if p.blocks[i] != blocks[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the dependency declared in the statement in the group:name:version[:classifier][@extension] format,
+// for example: classpath("com.example:lib:1.0:natives@zip") or classpath group: 'com.example', name: 'lib', version: '1.0', classifier: 'natives', ext: 'zip'.
+// Returns an empty string if the dependency has no version.
+func parseGradleDependencyNotation(statement string) string {
+	if values := extractQuotedStrings(statement); len(values) == 1 && strings.Count(values[0], ":") >= 2 {
+		return values[0]
+	}
+	arguments := map[string]string{}
+	for _, match := range gradleMapNotationRegex.FindAllStringSubmatch(statement, -1) {
+		arguments[match[1]] = match[2]
+	}
+	if arguments["group"] == "" || arguments["name"] == "" || arguments["version"] == "" {
+		return ""
+	}
+	notation := arguments["group"] + ":" + arguments["name"] + ":" + arguments["version"]
+	if arguments["classifier"] != "" {
+		notation += ":" + arguments["classifier"]
+	}
+	if arguments["ext"] != "" {
+		notation += "@" + arguments["ext"]
+	}
+	return notation
+}
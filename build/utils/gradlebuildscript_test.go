@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGradleBuildScriptKotlinDsl(t *testing.T) {
+	content := `
+buildscript {
+    repositories {
+        mavenCentral()
+    }
+    dependencies {
+        classpath("com.google.protobuf:protobuf-gradle-plugin:0.9.4")
+        // classpath("org.example:commented:1.0")
+    }
+}
+
+plugins {
+    java
+    id("org.springframework.boot") version "3.1.0"
+    kotlin("jvm") version "1.9.0" apply false
+    id("io.spring.dependency-management")
+}
+
+dependencies {
+    implementation("org.example:not-build-tooling:1.0")
+}
+`
+	buildScript := ParseGradleBuildScript(content)
+	assert.Equal(t, []string{"com.google.protobuf:protobuf-gradle-plugin:0.9.4"}, buildScript.Classpath)
+	assert.Equal(t, []GradlePlugin{
+		{Id: "org.springframework.boot", Version: "3.1.0"},
+		{Id: "org.jetbrains.kotlin.jvm", Version: "1.9.0"},
+	}, buildScript.Plugins)
+}
+
+func TestParseGradleBuildScriptGroovyDsl(t *testing.T) {
+	content := `
+buildscript {
+    dependencies {
+        classpath 'org.jfrog.buildinfo:build-info-extractor-gradle:5.2.5'
+        classpath group: 'com.example', name: 'map-notation-plugin', version: '2.0'
+        classpath group: 'org.lwjgl', name: 'lwjgl', version: '3.3.3', classifier: 'natives-linux'
+        classpath 'com.example:native-tools:1.0:linux@zip'
+    }
+}
+
+plugins {
+    id 'java'
+    id 'com.github.johnrengelman.shadow' version '8.1.1'
+}
+
+dependencies {
+    classpath 'org.example:not-in-buildscript:1.0'
+}
+`
+	buildScript := ParseGradleBuildScript(content)
+	assert.Equal(t, []string{
+		"org.jfrog.buildinfo:build-info-extractor-gradle:5.2.5",
+		"com.example:map-notation-plugin:2.0",
+		"org.lwjgl:lwjgl:3.3.3:natives-linux",
+		"com.example:native-tools:1.0:linux@zip",
+	}, buildScript.Classpath)
+	assert.Equal(t, []GradlePlugin{{Id: "com.github.johnrengelman.shadow", Version: "8.1.1"}}, buildScript.Plugins)
+	assert.Equal(t, "com.github.johnrengelman.shadow:com.github.johnrengelman.shadow.gradle.plugin:8.1.1", buildScript.Plugins[0].MarkerId())
+}
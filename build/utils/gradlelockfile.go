@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+const GradleLockfileName = "gradle.lockfile"
+
+// ReadGradleLockfile reads the dependency locks of the Gradle project in the provided directory,
+// and returns the locked versions of each module, mapped by the module's group and name, for example: 'com.google.guava:guava'.
+// The project's gradle.lockfile is read, or the per-configuration lockfiles in gradle/dependency-locks, which older Gradle versions create.
+// If the project has no lockfiles, nil is returned.
+func ReadGradleLockfile(projectDir string) (map[string][]string, error) {
+	lockfiles := []string{filepath.Join(projectDir, GradleLockfileName)}
+	legacyLockfiles, err := filepath.Glob(filepath.Join(projectDir, "gradle", "dependency-locks", "*.lockfile"))
+	if err != nil {
+		return nil, err
+	}
+	lockfiles = append(lockfiles, legacyLockfiles...)
+	var lockedVersions map[string][]string
+	for _, lockfile := range lockfiles {
+		content, err := os.ReadFile(lockfile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if lockedVersions == nil {
+			lockedVersions = make(map[string][]string)
+		}
+		parseGradleLockfile(string(content), lockedVersions)
+	}
+	return lockedVersions, nil
+}
+
+// Adds the locked versions in the content of a lockfile to the provided map.
+// The expected syntax of each line: <group>:<name>:<version>[=<configurations>]
+// Comments and the 'empty=<configurations>' line, which lists the configurations without dependencies, are skipped.
+func parseGradleLockfile(content string, lockedVersions map[string][]string) {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		coordinates, _, _ := strings.Cut(line, "=")
+		parts := strings.Split(coordinates, ":")
+		if len(parts) != 3 {
+			continue
+		}
+		module := parts[0] + ":" + parts[1]
+		if !slices.Contains(lockedVersions[module], parts[2]) {
+			lockedVersions[module] = append(lockedVersions[module], parts[2])
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadGradleLockfile(t *testing.T) {
+	projectDir := t.TempDir()
+	lockedVersions, err := ReadGradleLockfile(projectDir)
+	assert.NoError(t, err)
+	assert.Nil(t, lockedVersions)
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, GradleLockfileName), []byte(`# This is a Gradle generated file for dependency locking.
+# Manual edits can break the build and are not advised.
+# This file is expected to be part of source control.
+com.google.guava:guava:32.1.2-jre=compileClasspath,runtimeClasspath
+com.google.guava:guava:31.0-jre=testRuntimeClasspath
+junit:junit:4.13.2=testCompileClasspath,testRuntimeClasspath
+empty=annotationProcessor
+`), 0644))
+	legacyDir := filepath.Join(projectDir, "gradle", "dependency-locks")
+	require.NoError(t, os.MkdirAll(legacyDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyDir, "runtimeClasspath.lockfile"), []byte("org.slf4j:slf4j-api:2.0.9\ncom.google.guava:guava:32.1.2-jre\n"), 0644))
+
+	lockedVersions, err = ReadGradleLockfile(projectDir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"com.google.guava:guava": {"32.1.2-jre", "31.0-jre"},
+		"junit:junit":            {"4.13.2"},
+		"org.slf4j:slf4j-api":    {"2.0.9"},
+	}, lockedVersions)
+}
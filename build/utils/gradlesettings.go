@@ -0,0 +1,308 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	pluginManagementBlock               = "pluginManagement"
+	dependencyResolutionManagementBlock = "dependencyResolutionManagement"
+	repositoriesBlock                   = "repositories"
+)
+
+var (
+	// Matches a single or double-quoted string literal.
+	quotedStringRegex = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+	// Matches the identifier which opens a block, for example: 'repositories' in 'repositories {' or 'maven' in 'maven("...") {'.
+	blockNameRegex = regexp.MustCompile(`([\w.]+)\s*(?:\([^)]*\))?\s*$`)
+	// Matches the repository shortcuts, for example: mavenCentral() or google().
+	repositoryShortcutRegex = regexp.MustCompile(`^(mavenCentral|mavenLocal|google|gradlePluginPortal|jcenter)\s*\(\s*\)$`)
+	// Matches the URL declaration inside a repository block, for example: url = uri("...") or setUrl("...").
+	repositoryUrlRegex   = regexp.MustCompile(`^(?:url\b|setUrl\s*\(|url\s*=)`)
+	rootProjectNameRegex = regexp.MustCompile(`^rootProject\.name\s*=`)
+)
+
+// GradleSettings holds the details declared in a Gradle settings file (settings.gradle or settings.gradle.kts).
+type GradleSettings struct {
+	RootProjectName string
+	// Projects declared with 'include'.
+	IncludedProjects []string
+	// Builds declared with 'includeBuild' outside the 'pluginManagement' block.
+	IncludedBuilds []string
+	// Builds declared with 'includeBuild' inside the 'pluginManagement' block.
+	PluginIncludedBuilds []string
+	// Repositories declared inside 'pluginManagement { repositories {} }'.
+	PluginRepositories []GradleRepository
+	// Repositories declared inside 'dependencyResolutionManagement { repositories {} }'.
+	DependencyRepositories []GradleRepository
+}
+
+type GradleRepository struct {
+	// The repository type or shortcut name, for example: maven, ivy or mavenCentral.
+	Name string
+	Url  string
+}
+
+// ReadGradleSettings reads and parses the Gradle settings file in the provided directory.
+// The Kotlin DSL file (settings.gradle.kts) is preferred over the Groovy DSL file (settings.gradle).
+// If none of them exist, nil is returned.
+func ReadGradleSettings(projectDir string) (*GradleSettings, error) {
+	for _, fileName := range []string{"settings.gradle.kts", "settings.gradle"} {
+		content, err := os.ReadFile(filepath.Join(projectDir, fileName))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		return ParseGradleSettings(string(content)), nil
+	}
+	return nil, nil
+}
+
+// ParseGradleSettings parses the content of a Gradle settings file, written in either the Groovy or the Kotlin DSL.
+// The parser is block-aware, so that declarations inside 'pluginManagement {}' and 'dependencyResolutionManagement {}'
+// aren't mistaken for the project's modules.
+func ParseGradleSettings(content string) *GradleSettings {
+	parser := &gradleSettingsParser{settings: &GradleSettings{}}
+	parser.parse(stripGradleComments(content))
+	return parser.settings
+}
+
+type gradleSettingsParser struct {
+	settings *GradleSettings
+	// The names of the currently open blocks, from the outermost to the innermost.
+	blocks []string
+	// The repository whose block is currently open, if any.
+	currentRepository *GradleRepository
+}
+
+func (p *gradleSettingsParser) parse(content string) {
+	scanGradleScript(content, p.openBlock, p.handleStatement, p.closeBlock)
+}
+
+func (p *gradleSettingsParser) openBlock(header string) {
+	header = strings.TrimSpace(header)
+	blockName := ""
+	if match := blockNameRegex.FindStringSubmatch(header); match != nil {
+		blockName = match[1]
+	}
+	if p.innerBlock() == repositoriesBlock && p.repositoriesOwner() != "" {
+		repository := GradleRepository{Name: blockName}
+		// For example: maven("https://...") { ... }
+		if urls := extractQuotedStrings(header); len(urls) > 0 {
+			repository.Url = urls[0]
+		}
+		p.currentRepository = p.addRepository(repository)
+	}
+	p.blocks = append(p.blocks, blockName)
+}
+
+func (p *gradleSettingsParser) closeBlock() {
+	if len(p.blocks) == 0 {
+		return
+	}
+	p.blocks = p.blocks[:len(p.blocks)-1]
+	if p.innerBlock() == repositoriesBlock {
+		p.currentRepository = nil
+	}
+}
+
+func (p *gradleSettingsParser) handleStatement(statement string) {
+	statement = strings.TrimSpace(statement)
+	if statement == "" {
+		return
+	}
+	switch {
+	case p.currentRepository != nil && p.innerBlockIsRepository():
+		if repositoryUrlRegex.MatchString(statement) {
+			if urls := extractQuotedStrings(statement); len(urls) > 0 {
+				p.currentRepository.Url = urls[0]
+			}
+		}
+	case p.innerBlock() == repositoriesBlock:
+		p.handleRepositoryStatement(statement)
+	case hasGradleFunctionPrefix(statement, "includeBuild"):
+		if slices.Contains(p.blocks, pluginManagementBlock) {
+			p.settings.PluginIncludedBuilds = append(p.settings.PluginIncludedBuilds, extractQuotedStrings(statement)...)
+		} else {
+			p.settings.IncludedBuilds = append(p.settings.IncludedBuilds, extractQuotedStrings(statement)...)
+		}
+	case hasGradleFunctionPrefix(statement, "include"):
+		if !slices.Contains(p.blocks, pluginManagementBlock) && !slices.Contains(p.blocks, dependencyResolutionManagementBlock) {
+			p.settings.IncludedProjects = append(p.settings.IncludedProjects, extractQuotedStrings(statement)...)
+		}
+	case len(p.blocks) == 0 && rootProjectNameRegex.MatchString(statement):
+		if names := extractQuotedStrings(statement); len(names) > 0 {
+			p.settings.RootProjectName = names[0]
+		}
+	}
+}
+
+// Handles a single-line repository declaration, for example: mavenCentral() or maven("https://...").
+func (p *gradleSettingsParser) handleRepositoryStatement(statement string) {
+	if p.repositoriesOwner() == "" {
+		return
+	}
+	if match := repositoryShortcutRegex.FindStringSubmatch(statement); match != nil {
+		p.addRepository(GradleRepository{Name: match[1]})
+		return
+	}
+	for _, repositoryType := range []string{"maven", "ivy"} {
+		if hasGradleFunctionPrefix(statement, repositoryType) {
+			repository := GradleRepository{Name: repositoryType}
+			if urls := extractQuotedStrings(statement); len(urls) > 0 {
+				repository.Url = urls[0]
+			}
+			p.addRepository(repository)
+			return
+		}
+	}
+}
+
+// Adds the repository to the list matching the block which declares it, and returns a pointer to the added repository.
+func (p *gradleSettingsParser) addRepository(repository GradleRepository) *GradleRepository {
+	switch p.repositoriesOwner() {
+	case pluginManagementBlock:
+		p.settings.PluginRepositories = append(p.settings.PluginRepositories, repository)
+		return &p.settings.PluginRepositories[len(p.settings.PluginRepositories)-1]
+	case dependencyResolutionManagementBlock:
+		p.settings.DependencyRepositories = append(p.settings.DependencyRepositories, repository)
+		return &p.settings.DependencyRepositories[len(p.settings.DependencyRepositories)-1]
+	}
+	return nil
+}
+
+func (p *gradleSettingsParser) innerBlock() string {
+	if len(p.blocks) == 0 {
+		return ""
+	}
+	return p.blocks[len(p.blocks)-1]
+}
+
+// Returns true if the innermost block is a repository declared directly inside a 'repositories' block.
+func (p *gradleSettingsParser) innerBlockIsRepository() bool {
+	return len(p.blocks) >= 2 && p.blocks[len(p.blocks)-2] == repositoriesBlock
+}
+
+// Returns the name of the block which contains the innermost 'repositories' block, if it's a supported one.
+func (p *gradleSettingsParser) repositoriesOwner() string {
+	for i := len(p.blocks) - 1; i > 0; i-- {
+		if p.blocks[i] == repositoriesBlock {
+			switch p.blocks[i-1] {
+			case pluginManagementBlock, dependencyResolutionManagementBlock:
+				return p.blocks[i-1]
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// Splits the content of a Gradle script (without comments) into statements and blocks.
+// openBlock is called with the header of each opened block, for example: 'repositories' or 'maven("https://...")'.
+// handleStatement is called with each statement, and closeBlock when a block is closed.
+func scanGradleScript(content string, openBlock, handleStatement func(string), closeBlock func()) {
+	var statement strings.Builder
+	parenthesesDepth := 0
+	var quote rune
+	for _, char := range content {
+		if quote != 0 {
+			statement.WriteRune(char)
+			if char == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch char {
+		case '"', '\'':
+			quote = char
+			statement.WriteRune(char)
+		case '(':
+			parenthesesDepth++
+			statement.WriteRune(char)
+		case ')':
+			parenthesesDepth--
+			statement.WriteRune(char)
+		case '{':
+			openBlock(statement.String())
+			statement.Reset()
+		case '}':
+			handleStatement(statement.String())
+			statement.Reset()
+			closeBlock()
+		case '\n', ';':
+			if parenthesesDepth > 0 {
+				statement.WriteRune(' ')
+				continue
+			}
+			handleStatement(statement.String())
+			statement.Reset()
+		default:
+			statement.WriteRune(char)
+		}
+	}
+	handleStatement(statement.String())
+}
+
+// Returns true if the statement is a call of the provided function, for example: include(":a") or include ':a'.
+func hasGradleFunctionPrefix(statement, functionName string) bool {
+	if !strings.HasPrefix(statement, functionName) {
+		return false
+	}
+	rest := strings.TrimPrefix(statement, functionName)
+	return strings.HasPrefix(rest, "(") || strings.HasPrefix(rest, " ") || strings.HasPrefix(rest, "\"") || strings.HasPrefix(rest, "'")
+}
+
+func extractQuotedStrings(statement string) (values []string) {
+	for _, match := range quotedStringRegex.FindAllStringSubmatch(statement, -1) {
+		if match[1] != "" {
+			values = append(values, match[1])
+		} else if match[2] != "" {
+			values = append(values, match[2])
+		}
+	}
+	return
+}
+
+// Removes line (//) and block (/* */) comments, while keeping the content of string literals.
+func stripGradleComments(content string) string {
+	var result strings.Builder
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		char := content[i]
+		if quote != 0 {
+			result.WriteByte(char)
+			if char == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case char == '"' || char == '\'':
+			quote = char
+			result.WriteByte(char)
+		case strings.HasPrefix(content[i:], "//"):
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+			if i < len(content) {
+				result.WriteByte('\n')
+			}
+		case strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end == -1 {
+				return result.String()
+			}
+			i += end + 3
+		default:
+			result.WriteByte(char)
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGradleSettingsKotlinDsl(t *testing.T) {
+	content := `
+pluginManagement {
+    includeBuild("build-logic")
+    repositories {
+        gradlePluginPortal()
+        maven {
+            url = uri("https://plugins.example.com/gradle") // Internal plugins
+        }
+    }
+}
+
+/* The repositories of all projects.
+include(":not-a-project") */
+dependencyResolutionManagement {
+    repositoriesMode.set(RepositoriesMode.FAIL_ON_PROJECT_REPOS)
+    repositories {
+        mavenCentral()
+        maven("https://repo.example.com/libs-release") {
+            content { includeGroup("com.example") }
+        }
+        ivy { setUrl("https://ivy.example.com") }
+    }
+}
+
+rootProject.name = "my-project"
+include(":app", ":lib")
+include(
+    ":feature:login",
+    ":feature:home"
+)
+includeBuild("../shared")
+`
+	settings := ParseGradleSettings(content)
+	assert.Equal(t, "my-project", settings.RootProjectName)
+	assert.Equal(t, []string{":app", ":lib", ":feature:login", ":feature:home"}, settings.IncludedProjects)
+	assert.Equal(t, []string{"../shared"}, settings.IncludedBuilds)
+	assert.Equal(t, []string{"build-logic"}, settings.PluginIncludedBuilds)
+	assert.Equal(t, []GradleRepository{{Name: "gradlePluginPortal"}, {Name: "maven", Url: "https://plugins.example.com/gradle"}}, settings.PluginRepositories)
+	assert.Equal(t, []GradleRepository{
+		{Name: "mavenCentral"},
+		{Name: "maven", Url: "https://repo.example.com/libs-release"},
+		{Name: "ivy", Url: "https://ivy.example.com"},
+	}, settings.DependencyRepositories)
+}
+
+func TestParseGradleSettingsGroovyDsl(t *testing.T) {
+	content := `
+pluginManagement {
+    repositories {
+        maven { url 'https://plugins.example.com/gradle' }
+    }
+}
+dependencyResolutionManagement {
+    repositories {
+        google()
+        maven { url "https://repo.example.com/libs-release" }
+    }
+}
+rootProject.name = 'my-project'
+include 'app', 'lib'
+`
+	settings := ParseGradleSettings(content)
+	assert.Equal(t, "my-project", settings.RootProjectName)
+	assert.Equal(t, []string{"app", "lib"}, settings.IncludedProjects)
+	assert.Equal(t, []GradleRepository{{Name: "maven", Url: "https://plugins.example.com/gradle"}}, settings.PluginRepositories)
+	assert.Equal(t, []GradleRepository{{Name: "google"}, {Name: "maven", Url: "https://repo.example.com/libs-release"}}, settings.DependencyRepositories)
+}
+
+func TestReadGradleSettings(t *testing.T) {
+	projectDir := t.TempDir()
+	settings, err := ReadGradleSettings(projectDir)
+	assert.NoError(t, err)
+	assert.Nil(t, settings)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, "settings.gradle.kts"), []byte(`include(":app")`), 0644))
+	settings, err = ReadGradleSettings(projectDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{":app"}, settings.IncludedProjects)
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5" // #nosec G501 -- The wrappers locate the distributions by MD5 digests, which aren't used for security.
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// The path of the Gradle wrapper's properties file, relative to the project's root directory.
+var GradleWrapperPropertiesPath = filepath.Join("gradle", "wrapper", "gradle-wrapper.properties")
+
+// The official Gradle distributions, whose SHA-256 checksums are published next to them, in <distribution>.sha256 files.
+const GradleDistributionsUrl = "https://services.gradle.org/distributions"
+
+// GradleWrapperProperties holds the distribution details declared in the gradle-wrapper.properties of a project.
+type GradleWrapperProperties struct {
+	// The URL from which the wrapper downloads the Gradle distribution, for example: https://services.gradle.org/distributions/gradle-8.5-bin.zip
+	DistributionUrl string
+	// The SHA-256 checksum which the wrapper verifies the downloaded distribution by, if it's declared.
+	DistributionSha256Sum string
+}
+
+// DistributionFileName returns the name of the distribution's zip file, for example: gradle-8.5-bin.zip
+func (gwp *GradleWrapperProperties) DistributionFileName() string {
+	return getDistributionFileName(gwp.DistributionUrl)
+}
+
+// GetDistributionPath returns the path in which the wrapper stores the downloaded distribution's zip file.
+func (gwp *GradleWrapperProperties) GetDistributionPath(gradleUserHome string) string {
+	return getWrapperDistributionPath(gradleUserHome, gwp.DistributionUrl)
+}
+
+func getDistributionFileName(distributionUrl string) string {
+	parsedUrl, err := url.Parse(distributionUrl)
+	if err != nil {
+		return path.Base(distributionUrl)
+	}
+	return path.Base(parsedUrl.Path)
+}
+
+// Returns the path in which the Gradle wrapper, and the Maven wrapper before its script-only version, store a downloaded distribution's zip file:
+// <user home>/wrapper/dists/<distribution name>/<hash of the distribution URL>/<distribution file name>
+// The hash is the MD5 digest of the URL in base 36, like the wrappers' PathAssembler calculates it.
+func getWrapperDistributionPath(userHome, distributionUrl string) string {
+	fileName := getDistributionFileName(distributionUrl)
+	digest := md5.Sum([]byte(distributionUrl)) // #nosec G401
+	urlHash := new(big.Int).SetBytes(digest[:]).Text(36)
+	return filepath.Join(userHome, "wrapper", "dists", strings.TrimSuffix(fileName, filepath.Ext(fileName)), urlHash, fileName)
+}
+
+// ReadGradleWrapperProperties reads the gradle-wrapper.properties of the Gradle project in the provided directory.
+// If the project has no such file, nil is returned.
+func ReadGradleWrapperProperties(projectDir string) (*GradleWrapperProperties, error) {
+	content, err := os.ReadFile(filepath.Join(projectDir, GradleWrapperPropertiesPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	properties := parseJavaProperties(string(content))
+	return &GradleWrapperProperties{DistributionUrl: properties["distributionUrl"], DistributionSha256Sum: properties["distributionSha256Sum"]}, nil
+}
+
+// Parses the lines of a Java properties file in the <key>=<value> or <key>:<value> formats, skipping the comments.
+// The backslash escapes, such as the escaped colon in 'https\://services.gradle.org', are removed. Multiline values aren't supported.
+func parseJavaProperties(content string) map[string]string {
+	properties := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		separatorIndex := strings.IndexAny(line, "=:")
+		if separatorIndex == -1 {
+			continue
+		}
+		key := strings.TrimSpace(line[:separatorIndex])
+		properties[key] = unescapeJavaProperty(strings.TrimSpace(line[separatorIndex+1:]))
+	}
+	return properties
+}
+
+func unescapeJavaProperty(value string) string {
+	var unescaped strings.Builder
+	escaped := false
+	for _, char := range value {
+		if char == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		unescaped.WriteRune(char)
+	}
+	return unescaped.String()
+}
+
+// GetOfficialGradleDistributionSha256 returns the SHA-256 checksum which Gradle publishes for the distribution with the provided file name,
+// from the <distribution>.sha256 file in the provided distributions URL, usually GradleDistributionsUrl.
+func GetOfficialGradleDistributionSha256(ctx context.Context, distributionsUrl, distributionFileName string) (checksum string, err error) {
+	checksumUrl := strings.TrimSuffix(distributionsUrl, "/") + "/" + distributionFileName + ".sha256"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checksumUrl, nil)
+	if err != nil {
+		return
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s returned status: %s", checksumUrl, resp.Status)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	// The file contains the checksum only, but some mirrors add the file name after it, like the sha256sum command.
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s is empty", checksumUrl)
+	}
+	return strings.ToLower(fields[0]), nil
+}
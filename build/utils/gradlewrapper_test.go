@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadGradleWrapperProperties(t *testing.T) {
+	projectDir := t.TempDir()
+	wrapperProperties, err := ReadGradleWrapperProperties(projectDir)
+	require.NoError(t, err)
+	assert.Nil(t, wrapperProperties)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, "gradle", "wrapper"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, GradleWrapperPropertiesPath), []byte(`#Mon Jan 08 10:00:00 UTC 2024
+distributionBase=GRADLE_USER_HOME
+distributionPath=wrapper/dists
+distributionUrl=https\://services.gradle.org/distributions/gradle-8.5-bin.zip
+distributionSha256Sum=9d926787066a081739e8200858338b4a69e837c3a821a33aca9db09dd4a41026
+networkTimeout=10000
+`), 0644))
+	wrapperProperties, err = ReadGradleWrapperProperties(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, &GradleWrapperProperties{
+		DistributionUrl:       "https://services.gradle.org/distributions/gradle-8.5-bin.zip",
+		DistributionSha256Sum: "9d926787066a081739e8200858338b4a69e837c3a821a33aca9db09dd4a41026",
+	}, wrapperProperties)
+	assert.Equal(t, "gradle-8.5-bin.zip", wrapperProperties.DistributionFileName())
+	// The directory is named by the hash which the wrapper calculates from the distribution URL.
+	assert.Equal(t, filepath.Join("home", "wrapper", "dists", "gradle-8.5-bin", "5t9huq95ubn472n8rpzujfbqh", "gradle-8.5-bin.zip"), wrapperProperties.GetDistributionPath("home"))
+}
+
+func TestGetOfficialGradleDistributionSha256(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gradle-8.5-bin.zip.sha256" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("9D926787066A081739E8200858338B4A69E837C3A821A33ACA9DB09DD4A41026  gradle-8.5-bin.zip\n"))
+	}))
+	defer server.Close()
+
+	checksum, err := GetOfficialGradleDistributionSha256(context.Background(), server.URL+"/", "gradle-8.5-bin.zip")
+	require.NoError(t, err)
+	assert.Equal(t, "9d926787066a081739e8200858338b4a69e837c3a821a33aca9db09dd4a41026", checksum)
+
+	_, err = GetOfficialGradleDistributionSha256(context.Background(), server.URL, "gradle-0.1-bin.zip")
+	assert.ErrorContains(t, err, "404 Not Found")
+}
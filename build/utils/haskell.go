@@ -0,0 +1,555 @@
+package utils
+
+import (
+	"archive/tar"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	StackFileName        = "stack.yaml"
+	StackLockFileName    = "stack.yaml.lock"
+	CabalProjectFileName = "cabal.project"
+	CabalFreezeFileName  = "cabal.project.freeze"
+	HpackFileName        = "package.yaml"
+	HackageIndexFileName = "01-index.tar"
+	HackageRepository    = "hackage.haskell.org"
+)
+
+// The build tools of Haskell projects.
+type HaskellTool string
+
+const (
+	Cabal HaskellTool = "cabal"
+	Stack HaskellTool = "stack"
+)
+
+// GetHaskellTool returns the build tool of the Haskell project in the provided directory: Stack if it has a stack.yaml, or Cabal otherwise.
+func GetHaskellTool(projectDir string) (HaskellTool, error) {
+	_, err := os.Stat(filepath.Join(projectDir, StackFileName))
+	if err == nil {
+		return Stack, nil
+	}
+	if os.IsNotExist(err) {
+		return Cabal, nil
+	}
+	return "", err
+}
+
+// HaskellPackage is a package and its version, as pinned by a cabal.project.freeze or a stack.yaml.lock.
+type HaskellPackage struct {
+	Name    string
+	Version string
+}
+
+// Id returns the ID of the package in the build-info: <name>:<version>
+func (hp *HaskellPackage) Id() string {
+	return hp.Name + ":" + hp.Version
+}
+
+// FileName returns the name of the package's source distribution on Hackage, for example: aeson-2.1.2.1.tar.gz
+func (hp *HaskellPackage) FileName() string {
+	return hp.Name + "-" + hp.Version + ".tar.gz"
+}
+
+// ReadHaskellPinnedPackages returns the packages pinned by the project's cabal.project.freeze (Cabal), or by its stack.yaml.lock (Stack).
+// A stack.yaml.lock only pins the extra-deps of the project, since the other packages are pinned by the project's snapshot.
+// Returns an empty list if the file doesn't exist.
+func ReadHaskellPinnedPackages(projectDir string, tool HaskellTool) ([]*HaskellPackage, error) {
+	fileName := CabalFreezeFileName
+	if tool == Stack {
+		fileName = StackLockFileName
+	}
+	content, err := os.ReadFile(filepath.Join(projectDir, fileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if tool == Stack {
+		return parseStackLock(content)
+	}
+	return parseCabalFreeze(string(content)), nil
+}
+
+// A version constraint of a cabal.project.freeze, for example: any.aeson ==2.1.2.1
+var cabalFreezeConstraintRegex = regexp.MustCompile(`^(?:any\.)?([A-Za-z0-9][A-Za-z0-9-]*)\s*==\s*([0-9][0-9.]*)$`)
+
+// The constraints of a cabal.project.freeze are a comma separated list, which starts in the 'constraints' field and continues in the indented lines below it.
+// Besides the versions, the constraints pin the packages' flags, for example: aeson -cffi +ordered-keymap
+func parseCabalFreeze(content string) []*HaskellPackage {
+	var packages []*HaskellPackage
+	inConstraints := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			field, value, found := strings.Cut(trimmed, ":")
+			inConstraints = found && strings.EqualFold(field, "constraints")
+			if !inConstraints {
+				continue
+			}
+			trimmed = value
+		}
+		if !inConstraints {
+			continue
+		}
+		for _, constraint := range strings.Split(trimmed, ",") {
+			if match := cabalFreezeConstraintRegex.FindStringSubmatch(strings.TrimSpace(constraint)); match != nil {
+				packages = append(packages, &HaskellPackage{Name: match[1], Version: match[2]})
+			}
+		}
+	}
+	return packages
+}
+
+type stackLock struct {
+	Packages []struct {
+		Completed map[string]any `yaml:"completed"`
+	} `yaml:"packages"`
+}
+
+// A Hackage package of a stack.yaml.lock, with the checksum and size of its cabal file revision, for example: acme-missiles-0.3@sha256:2ba6...,613
+var stackLockHackageRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*)-([0-9][0-9.]*)(?:@.*)?$`)
+
+// Returns the Hackage packages of a stack.yaml.lock. The packages from Git repositories and archives are skipped.
+func parseStackLock(content []byte) ([]*HaskellPackage, error) {
+	var lock stackLock
+	if err := yaml.Unmarshal(content, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", StackLockFileName, err)
+	}
+	var packages []*HaskellPackage
+	for _, lockPackage := range lock.Packages {
+		hackage, ok := lockPackage.Completed["hackage"].(string)
+		if !ok {
+			continue
+		}
+		if match := stackLockHackageRegex.FindStringSubmatch(hackage); match != nil {
+			packages = append(packages, &HaskellPackage{Name: match[1], Version: match[2]})
+		}
+	}
+	return packages, nil
+}
+
+// HaskellPackageDescription is the description of the project's package, from its .cabal file or its package.yaml (hpack).
+type HaskellPackageDescription struct {
+	Name    string
+	Version string
+	// The names of the packages which the package's components depend on, sorted.
+	BuildDepends []string
+}
+
+// ReadHaskellPackageDescription reads the description of the package in the provided directory, from its .cabal file, or from its package.yaml if it has no .cabal file.
+// Returns nil if the directory has neither of them, for example in a multi-package Cabal project.
+func ReadHaskellPackageDescription(projectDir string) (*HaskellPackageDescription, error) {
+	cabalFiles, err := filepath.Glob(filepath.Join(projectDir, "*.cabal"))
+	if err != nil {
+		return nil, err
+	}
+	if len(cabalFiles) > 0 {
+		content, err := os.ReadFile(cabalFiles[0])
+		if err != nil {
+			return nil, err
+		}
+		return parseCabalFile(string(content)), nil
+	}
+	content, err := os.ReadFile(filepath.Join(projectDir, HpackFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseHpackFile(content)
+}
+
+var (
+	// A dependency in a build-depends field, for example: aeson >= 2.0 && < 2.2
+	cabalDependencyRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*)`)
+	// The sublibraries of a dependency in a build-depends field, for example: mylib:{internal, extra}
+	cabalSublibrariesRegex = regexp.MustCompile(`:\s*\{[^}]*}`)
+	// The header of a named component, whose name may be used as a dependency of the package's other components, for example: library internal
+	cabalComponentRegex = regexp.MustCompile(`(?i)^(?:library|foreign-library|executable|test-suite|benchmark)\s+([A-Za-z0-9][A-Za-z0-9-]*)`)
+)
+
+// Parses the top level name and version fields of a .cabal file, and the build-depends fields of all its components.
+// Dependencies on the package itself and on its named components are excluded.
+func parseCabalFile(content string) *HaskellPackageDescription {
+	description := &HaskellPackageDescription{}
+	fields := parseCabalFields(content)
+	components := make(map[string]bool)
+	for _, field := range fields {
+		switch {
+		case field.indent == 0 && field.name == "name":
+			description.Name = field.value
+		case field.indent == 0 && field.name == "version":
+			description.Version = field.value
+		case field.name == "build-depends":
+			// Remove the sublibraries of dependencies, whose names are separated by commas.
+			value := cabalSublibrariesRegex.ReplaceAllString(field.value, "")
+			for _, dependency := range strings.Split(value, ",") {
+				if match := cabalDependencyRegex.FindStringSubmatch(strings.TrimSpace(dependency)); match != nil {
+					description.BuildDepends = append(description.BuildDepends, match[1])
+				}
+			}
+		case field.component != "":
+			components[field.component] = true
+		}
+	}
+	description.BuildDepends = filterHaskellDependencies(description.BuildDepends, description.Name, components)
+	return description
+}
+
+type cabalField struct {
+	name   string
+	value  string
+	indent int
+	// The name of a named component, whose header is the line.
+	component string
+}
+
+// Parses the fields of a .cabal file or of an installed package's registration file. A field's value continues in the lines which are indented more than the field.
+// The lines of conditionals and component headers are returned as fields without values.
+func parseCabalFields(content string) []cabalField {
+	var fields []cabalField
+	current := -1
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\t", "    "), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if current >= 0 && indent > fields[current].indent {
+			fields[current].value = strings.TrimSpace(fields[current].value + " " + trimmed)
+			continue
+		}
+		name, value, found := strings.Cut(trimmed, ":")
+		if !found || strings.ContainsAny(name, " ({") {
+			field := cabalField{indent: indent}
+			if match := cabalComponentRegex.FindStringSubmatch(trimmed); match != nil {
+				field.component = match[1]
+			}
+			fields = append(fields, field)
+			current = -1
+			continue
+		}
+		fields = append(fields, cabalField{name: strings.ToLower(name), value: strings.TrimSpace(value), indent: indent})
+		current = len(fields) - 1
+	}
+	return fields
+}
+
+type hpackFile struct {
+	Name         string                    `yaml:"name"`
+	Version      string                    `yaml:"version"`
+	Dependencies any                       `yaml:"dependencies"`
+	Library      *hpackComponent           `yaml:"library"`
+	Internal     map[string]hpackComponent `yaml:"internal-libraries"`
+	Executables  map[string]hpackComponent `yaml:"executables"`
+	Tests        map[string]hpackComponent `yaml:"tests"`
+	Benchmarks   map[string]hpackComponent `yaml:"benchmarks"`
+}
+
+type hpackComponent struct {
+	Dependencies any `yaml:"dependencies"`
+}
+
+// Parses the name, the version and the dependencies of the top level and of all the components of a package.yaml.
+func parseHpackFile(content []byte) (*HaskellPackageDescription, error) {
+	var file hpackFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", HpackFileName, err)
+	}
+	description := &HaskellPackageDescription{Name: file.Name, Version: file.Version}
+	components := make(map[string]bool)
+	dependencies := getHpackDependencies(file.Dependencies)
+	if file.Library != nil {
+		dependencies = append(dependencies, getHpackDependencies(file.Library.Dependencies)...)
+	}
+	for _, componentsMap := range []map[string]hpackComponent{file.Internal, file.Executables, file.Tests, file.Benchmarks} {
+		for name, component := range componentsMap {
+			components[name] = true
+			dependencies = append(dependencies, getHpackDependencies(component.Dependencies)...)
+		}
+	}
+	description.BuildDepends = filterHaskellDependencies(dependencies, description.Name, components)
+	return description, nil
+}
+
+// The dependencies of a package.yaml are a string, a list of strings, or a map from the packages' names to their constraints.
+func getHpackDependencies(dependencies any) (names []string) {
+	var values []string
+	switch typed := dependencies.(type) {
+	case string:
+		values = strings.Split(typed, ",")
+	case []any:
+		for _, item := range typed {
+			switch value := item.(type) {
+			case string:
+				values = append(values, value)
+			case map[string]any:
+				if name, ok := value["name"].(string); ok {
+					values = append(values, name)
+				}
+			}
+		}
+	case map[string]any:
+		for name := range typed {
+			values = append(values, name)
+		}
+	}
+	for _, value := range values {
+		if match := cabalDependencyRegex.FindStringSubmatch(strings.TrimSpace(value)); match != nil {
+			names = append(names, match[1])
+		}
+	}
+	return
+}
+
+// Returns the sorted and unique dependencies, without the package itself and its named components.
+func filterHaskellDependencies(dependencies []string, packageName string, components map[string]bool) []string {
+	var filtered []string
+	for _, dependency := range dependencies {
+		if dependency != packageName && !components[dependency] && !containsString(filtered, dependency) {
+			filtered = append(filtered, dependency)
+		}
+	}
+	sort.Strings(filtered)
+	return filtered
+}
+
+// InstalledHaskellPackage is a package registered in a package database, by its registration (.conf) file.
+type InstalledHaskellPackage struct {
+	// The package's unit ID, for example: aeson-2.1.2.1-3f9b0c1d, or base-4.17.2.0
+	Id      string
+	Name    string
+	Version string
+	// The unit IDs of the package's dependencies.
+	Depends []string
+}
+
+// A unit ID, whose name and version are followed by an optional hash.
+var haskellUnitIdRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*?)-([0-9][0-9.]*)(?:-[^-]+)?$`)
+
+// ParseHaskellUnitId returns the name and the version of the package of a unit ID, for the dependencies whose packages aren't in the read databases.
+func ParseHaskellUnitId(unitId string) (name, version string) {
+	if match := haskellUnitIdRegex.FindStringSubmatch(unitId); match != nil {
+		return match[1], match[2]
+	}
+	return unitId, ""
+}
+
+// GetHaskellPackageDbDirs returns the package databases in which the tool installs the project's dependencies, which exist:
+// Cabal: the project's dist-newstyle/packagedb, and the store of the Cabal directory ($CABAL_DIR, ~/.cabal, or ~/.local/state/cabal).
+// Stack: the project's .stack-work/install, and the snapshots of the Stack root ($STACK_ROOT or ~/.stack).
+func GetHaskellPackageDbDirs(projectDir string, tool HaskellTool) ([]string, error) {
+	var patterns []string
+	if tool == Stack {
+		stackRoot, err := getStackRoot()
+		if err != nil {
+			return nil, err
+		}
+		patterns = []string{
+			filepath.Join(projectDir, ".stack-work", "install", "*", "*", "*", "pkgdb"),
+			filepath.Join(stackRoot, "snapshots", "*", "*", "*", "pkgdb"),
+		}
+	} else {
+		storeDirs, err := getCabalDirs("store", "XDG_STATE_HOME", filepath.Join(".local", "state"))
+		if err != nil {
+			return nil, err
+		}
+		patterns = []string{filepath.Join(projectDir, "dist-newstyle", "packagedb", "ghc-*")}
+		for _, storeDir := range storeDirs {
+			patterns = append(patterns, filepath.Join(storeDir, "ghc-*", "package.db"))
+		}
+	}
+	var dbDirs []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		dbDirs = append(dbDirs, matches...)
+	}
+	return dbDirs, nil
+}
+
+// ReadHaskellPackageDbs reads the registration files of the packages in the provided package databases, and returns the packages by their unit IDs.
+func ReadHaskellPackageDbs(dbDirs []string) (map[string]*InstalledHaskellPackage, error) {
+	packages := make(map[string]*InstalledHaskellPackage)
+	for _, dbDir := range dbDirs {
+		confFiles, err := filepath.Glob(filepath.Join(dbDir, "*.conf"))
+		if err != nil {
+			return nil, err
+		}
+		for _, confFile := range confFiles {
+			content, err := os.ReadFile(confFile)
+			if err != nil {
+				return nil, err
+			}
+			if installedPackage := parseInstalledPackageInfo(string(content)); installedPackage.Id != "" {
+				packages[installedPackage.Id] = installedPackage
+			}
+		}
+	}
+	return packages, nil
+}
+
+func parseInstalledPackageInfo(content string) *InstalledHaskellPackage {
+	installedPackage := &InstalledHaskellPackage{}
+	for _, field := range parseCabalFields(content) {
+		switch field.name {
+		case "id":
+			installedPackage.Id = field.value
+		case "name":
+			installedPackage.Name = field.value
+		case "version":
+			installedPackage.Version = field.value
+		case "depends":
+			installedPackage.Depends = strings.Fields(field.value)
+		}
+	}
+	return installedPackage
+}
+
+// GetHackageIndexPaths returns the paths of the Hackage indexes downloaded by Cabal and Stack, which exist, starting with the index of the provided tool.
+func GetHackageIndexPaths(tool HaskellTool) ([]string, error) {
+	cabalPackagesDirs, err := getCabalDirs("packages", "XDG_CACHE_HOME", ".cache")
+	if err != nil {
+		return nil, err
+	}
+	var cabalIndexes []string
+	for _, packagesDir := range cabalPackagesDirs {
+		cabalIndexes = append(cabalIndexes, filepath.Join(packagesDir, HackageRepository, HackageIndexFileName))
+	}
+	stackRoot, err := getStackRoot()
+	if err != nil {
+		return nil, err
+	}
+	stackIndex := filepath.Join(stackRoot, "pantry", "hackage", HackageIndexFileName)
+	candidates := append(cabalIndexes, stackIndex)
+	if tool == Stack {
+		candidates = append([]string{stackIndex}, cabalIndexes...)
+	}
+	var indexPaths []string
+	for _, candidate := range candidates {
+		if _, err = os.Stat(candidate); err == nil {
+			indexPaths = append(indexPaths, candidate)
+		}
+	}
+	return indexPaths, nil
+}
+
+// HackageChecksums are the checksums of a package's source distribution, signed by Hackage.
+type HackageChecksums struct {
+	Sha256 string
+	Md5    string
+}
+
+type hackagePackageMetadata struct {
+	Signed struct {
+		Targets map[string]struct {
+			Hashes struct {
+				Sha256 string `json:"sha256"`
+				Md5    string `json:"md5"`
+			} `json:"hashes"`
+		} `json:"targets"`
+	} `json:"signed"`
+}
+
+// ReadHackageIndexChecksums reads the checksums of the provided packages' source distributions from the package.json metadata files of a Hackage index,
+// and returns them by the packages' IDs. The packages which aren't in the index are omitted.
+func ReadHackageIndexChecksums(indexPath string, packages []*HaskellPackage) (checksums map[string]HackageChecksums, err error) {
+	wanted := make(map[string]*HaskellPackage)
+	for _, haskellPackage := range packages {
+		wanted[haskellPackage.Name+"/"+haskellPackage.Version+"/package.json"] = haskellPackage
+	}
+	indexFile, err := os.Open(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, indexFile.Close())
+	}()
+	checksums = make(map[string]HackageChecksums)
+	reader := tar.NewReader(bufio.NewReaderSize(indexFile, 1024*1024))
+	for len(checksums) < len(wanted) {
+		var header *tar.Header
+		header, err = reader.Next()
+		if err == io.EOF {
+			return checksums, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the Hackage index %s: %w", indexPath, err)
+		}
+		haskellPackage, ok := wanted[header.Name]
+		if !ok {
+			continue
+		}
+		var metadata hackagePackageMetadata
+		if err = json.NewDecoder(reader).Decode(&metadata); err != nil {
+			return nil, fmt.Errorf("failed to parse %s in the Hackage index %s: %w", header.Name, indexPath, err)
+		}
+		if target, ok := metadata.Signed.Targets["<repo>/package/"+haskellPackage.FileName()]; ok {
+			checksums[haskellPackage.Id()] = HackageChecksums{Sha256: target.Hashes.Sha256, Md5: target.Hashes.Md5}
+		}
+	}
+	return checksums, nil
+}
+
+// FindHackageTarball returns the path of the package's source distribution in the packages cache of Cabal, or an empty string if it isn't cached.
+// Stack keeps the packages it downloads in its own database, so they aren't found.
+func FindHackageTarball(haskellPackage *HaskellPackage) (string, error) {
+	packagesDirs, err := getCabalDirs("packages", "XDG_CACHE_HOME", ".cache")
+	if err != nil {
+		return "", err
+	}
+	for _, packagesDir := range packagesDirs {
+		tarballPath := filepath.Join(packagesDir, HackageRepository, haskellPackage.Name, haskellPackage.Version, haskellPackage.FileName())
+		if _, err = os.Stat(tarballPath); err == nil {
+			return tarballPath, nil
+		}
+	}
+	return "", nil
+}
+
+// Returns the candidate paths of a directory of Cabal: <$CABAL_DIR>/<name> if CABAL_DIR is set.
+// Otherwise, ~/.cabal/<name>, and the XDG directory which Cabal 3.10 and above use, for example: ~/.cache/cabal/packages
+func getCabalDirs(name, xdgVariable, xdgDefault string) ([]string, error) {
+	if cabalDir := os.Getenv("CABAL_DIR"); cabalDir != "" {
+		return []string{filepath.Join(cabalDir, name)}, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	xdgDir := os.Getenv(xdgVariable)
+	if xdgDir == "" {
+		xdgDir = filepath.Join(home, xdgDefault)
+	}
+	return []string{filepath.Join(home, ".cabal", name), filepath.Join(xdgDir, "cabal", name)}, nil
+}
+
+func getStackRoot() (string, error) {
+	if stackRoot := os.Getenv("STACK_ROOT"); stackRoot != "" {
+		return stackRoot, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".stack"), nil
+}
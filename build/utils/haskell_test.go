@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCabalFreeze(t *testing.T) {
+	packages := parseCabalFreeze(`active-repositories: hackage.haskell.org:merge
+constraints: any.Cabal ==3.8.1.0,
+             any.aeson ==2.1.2.1,
+             aeson -cffi +ordered-keymap,
+             any.base ==4.17.2.0,
+             text-show ==3.10.4
+index-state: hackage.haskell.org 2023-10-10T00:00:00Z
+`)
+	assert.Equal(t, []*HaskellPackage{
+		{Name: "Cabal", Version: "3.8.1.0"},
+		{Name: "aeson", Version: "2.1.2.1"},
+		{Name: "base", Version: "4.17.2.0"},
+		{Name: "text-show", Version: "3.10.4"},
+	}, packages)
+	assert.Equal(t, "text-show:3.10.4", packages[3].Id())
+	assert.Equal(t, "text-show-3.10.4.tar.gz", packages[3].FileName())
+}
+
+func TestParseStackLock(t *testing.T) {
+	packages, err := parseStackLock([]byte(`# This file was autogenerated by Stack.
+packages:
+- completed:
+    hackage: acme-missiles-0.3@sha256:2ba66a092a32593880a87fb00f3213762d7bca65a687d45965778deb8694c5d1,613
+    pantry-tree:
+      sha256: 614bc0cca76937507ea0a5ccc17a504c997ce458d7f2f9e43b15a10c8eaeb033
+      size: 226
+  original:
+    hackage: acme-missiles-0.3
+- completed:
+    commit: 6a7c91b8f3a1e6c2e38a5c4b5f5e1c6d2b4f1a3e
+    git: https://github.com/example/forked.git
+    name: forked
+    version: 0.1.0
+  original:
+    commit: 6a7c91b8f3a1e6c2e38a5c4b5f5e1c6d2b4f1a3e
+    git: https://github.com/example/forked.git
+snapshots:
+- completed:
+    sha256: 9a5b5c1f7f0d1c8c6e1b3e5a1e1d2f0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e
+    size: 619403
+    url: https://raw.githubusercontent.com/commercialhaskell/stackage-snapshots/master/lts/21/13.yaml
+  original: lts-21.13
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []*HaskellPackage{{Name: "acme-missiles", Version: "0.3"}}, packages)
+
+	_, err = parseStackLock([]byte("packages: ["))
+	assert.ErrorContains(t, err, "failed to parse stack.yaml.lock")
+}
+
+func TestParseCabalFile(t *testing.T) {
+	description := parseCabalFile(`cabal-version:      3.0
+name:               my-app
+version:            0.2.0.1
+synopsis:           An example
+  with a long synopsis
+
+library
+    exposed-modules:  MyLib
+    build-depends:    base ^>=4.17.2.0,
+                      aeson >= 2.0 && < 2.2,
+                      my-app:internal
+    if os(windows)
+        build-depends: Win32
+
+library internal
+    build-depends:
+        base
+      , text
+
+executable my-app
+    main-is:          Main.hs
+    -- A comment
+    build-depends:    base, my-app, bytestring
+
+test-suite my-app-test
+    type:             exitcode-stdio-1.0
+    build-depends:    base, my-app, internal, hspec:{hspec, hspec-discover}
+`)
+	assert.Equal(t, &HaskellPackageDescription{
+		Name:         "my-app",
+		Version:      "0.2.0.1",
+		BuildDepends: []string{"Win32", "aeson", "base", "bytestring", "hspec", "text"},
+	}, description)
+}
+
+func TestParseHpackFile(t *testing.T) {
+	description, err := parseHpackFile([]byte(`name: my-app
+version: 0.1.0
+dependencies:
+- base >= 4.7 && < 5
+library:
+  source-dirs: src
+  dependencies:
+  - aeson
+executables:
+  my-app-exe:
+    main: Main.hs
+    dependencies: my-app, optparse-applicative
+tests:
+  my-app-test:
+    main: Spec.hs
+    dependencies:
+      hspec: ">= 2.10"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &HaskellPackageDescription{
+		Name:         "my-app",
+		Version:      "0.1.0",
+		BuildDepends: []string{"aeson", "base", "hspec", "optparse-applicative"},
+	}, description)
+}
+
+func TestReadHaskellPackageDbs(t *testing.T) {
+	dbDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dbDir, "aeson-2.1.2.1-3f9b0c1d.conf"), []byte(`name:                 aeson
+version:              2.1.2.1
+visibility:           public
+id:                   aeson-2.1.2.1-3f9b0c1d
+key:                  aeson-2.1.2.1-3f9b0c1d
+exposed-modules:
+    Data.Aeson Data.Aeson.Types
+depends:
+    base-4.17.2.0 text-2.0.2
+    vector-0.13.1.0-8e1a2b3c
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dbDir, "package.cache"), []byte("binary"), 0644))
+
+	packages, err := ReadHaskellPackageDbs([]string{dbDir})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*InstalledHaskellPackage{
+		"aeson-2.1.2.1-3f9b0c1d": {
+			Id:      "aeson-2.1.2.1-3f9b0c1d",
+			Name:    "aeson",
+			Version: "2.1.2.1",
+			Depends: []string{"base-4.17.2.0", "text-2.0.2", "vector-0.13.1.0-8e1a2b3c"},
+		},
+	}, packages)
+
+	for unitId, expected := range map[string][2]string{
+		"base-4.17.2.0":                           {"base", "4.17.2.0"},
+		"vector-0.13.1.0-8e1a2b3c":                {"vector", "0.13.1.0"},
+		"text-show-3.10.4-Ljw8Jx9R1dxKl0tG1W5q2T": {"text-show", "3.10.4"},
+		"rts": {"rts", ""},
+	} {
+		name, version := ParseHaskellUnitId(unitId)
+		assert.Equal(t, expected, [2]string{name, version}, unitId)
+	}
+}
+
+func TestReadHackageIndexChecksums(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), HackageIndexFileName)
+	writeTestHackageIndex(t, indexPath, map[string]string{
+		"aeson/2.1.2.1/aeson.cabal": "name: aeson",
+		"aeson/2.1.2.1/package.json": `{"signatures":[],"signed":{"_type":"Targets","expires":null,"targets":` +
+			`{"<repo>/package/aeson-2.1.2.1.tar.gz":{"hashes":{"md5":"0123456789abcdef0123456789abcdef","sha256":"5e7c5c1e2d0b1f6e8a1f2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6071"},"length":12345}},"version":0}}`,
+		"text/2.0.2/package.json": `{"signatures":[],"signed":{"targets":{"<repo>/package/text-2.0.2.tar.gz":{"hashes":{"sha256":"abcd"}}}}}`,
+	})
+	checksums, err := ReadHackageIndexChecksums(indexPath, []*HaskellPackage{{Name: "aeson", Version: "2.1.2.1"}, {Name: "missing", Version: "1.0"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]HackageChecksums{
+		"aeson:2.1.2.1": {Sha256: "5e7c5c1e2d0b1f6e8a1f2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6071", Md5: "0123456789abcdef0123456789abcdef"},
+	}, checksums)
+}
+
+func writeTestHackageIndex(t *testing.T, indexPath string, files map[string]string) {
+	indexFile, err := os.Create(indexPath)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, indexFile.Close())
+	}()
+	writer := tar.NewWriter(indexFile)
+	for name, content := range files {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	HelmChartFileName = "Chart.yaml"
+	HelmLockFileName  = "Chart.lock"
+	// The directory of a chart, which holds the archives of its dependencies, after they're fetched by 'helm dependency build' or 'helm dependency update'.
+	HelmChartsDirName = "charts"
+	// The prefix of the repositories of the dependencies which are charts in local directories.
+	helmFileRepositoryPrefix = "file://"
+)
+
+// HelmChart is the content of a chart's Chart.yaml, which is used to collect its dependencies.
+// Only charts of API version v2 declare their dependencies in the Chart.yaml.
+type HelmChart struct {
+	ApiVersion   string                 `yaml:"apiVersion"`
+	Name         string                 `yaml:"name"`
+	Version      string                 `yaml:"version"`
+	Dependencies []*HelmChartDependency `yaml:"dependencies"`
+}
+
+// HelmChartDependency is a dependency declared in a Chart.yaml, or pinned in a Chart.lock.
+// In the Chart.yaml, the version may be a range, such as ~1.2.0, while in the Chart.lock it's the resolved version.
+type HelmChartDependency struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository"`
+	Alias      string `yaml:"alias"`
+}
+
+// Id returns the ID of the dependency in the build-info: <name>:<version>
+func (hcd *HelmChartDependency) Id() string {
+	return hcd.Name + ":" + hcd.Version
+}
+
+// IsLocal returns true if the dependency is a chart in a local directory, whose repository is file://<path>
+func (hcd *HelmChartDependency) IsLocal() bool {
+	return strings.HasPrefix(hcd.Repository, helmFileRepositoryPrefix)
+}
+
+// GetLocalPath returns the directory of a local dependency, relative to the chart's directory, or an empty string if the dependency isn't local.
+func (hcd *HelmChartDependency) GetLocalPath() string {
+	if !hcd.IsLocal() {
+		return ""
+	}
+	return filepath.FromSlash(strings.TrimPrefix(hcd.Repository, helmFileRepositoryPrefix))
+}
+
+// GetRepositoryUrl returns the URL of the repository from which the dependency is fetched,
+// or an empty string if the repository is a local directory, or the name of a repository added by 'helm repo add', such as @bitnami.
+func (hcd *HelmChartDependency) GetRepositoryUrl() string {
+	if hcd.Repository == "" || hcd.IsLocal() || strings.HasPrefix(hcd.Repository, "@") || strings.HasPrefix(hcd.Repository, "alias:") {
+		return ""
+	}
+	return hcd.Repository
+}
+
+// GetArchivePath returns the path of the dependency's archive, which Helm fetches into the charts directory: charts/<name>-<version>.tgz
+func (hcd *HelmChartDependency) GetArchivePath(chartDir string) string {
+	return filepath.Join(chartDir, HelmChartsDirName, hcd.Name+"-"+hcd.Version+".tgz")
+}
+
+// HelmLock is the content of a chart's Chart.lock, which pins the versions of the dependencies.
+type HelmLock struct {
+	Dependencies []*HelmChartDependency `yaml:"dependencies"`
+	Digest       string                 `yaml:"digest"`
+}
+
+// ReadHelmChart reads and parses the Chart.yaml in the provided directory.
+func ReadHelmChart(chartDir string) (*HelmChart, error) {
+	chart := new(HelmChart)
+	if err := readHelmYaml(filepath.Join(chartDir, HelmChartFileName), chart); err != nil {
+		return nil, err
+	}
+	return chart, nil
+}
+
+// ReadHelmLock reads and parses the Chart.lock in the provided directory.
+func ReadHelmLock(chartDir string) (*HelmLock, error) {
+	lock := new(HelmLock)
+	if err := readHelmYaml(filepath.Join(chartDir, HelmLockFileName), lock); err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+func readHelmYaml(path string, out interface{}) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err = yaml.Unmarshal(content, out); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadHelmChartAndLock(t *testing.T) {
+	chartDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, HelmChartFileName), []byte(`apiVersion: v2
+name: app
+version: 1.2.3
+dependencies:
+  - name: redis
+    version: ~17.3.0
+    repository: https://charts.bitnami.com/bitnami
+    alias: cache
+  - name: common
+    version: 0.1.0
+    repository: file://../common
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, HelmLockFileName), []byte(`dependencies:
+- name: redis
+  repository: https://charts.bitnami.com/bitnami
+  version: 17.3.14
+- name: common
+  repository: file://../common
+  version: 0.1.0
+digest: sha256:5b2f0bd6c2d3d4e0
+generated: "2024-01-01T10:00:00.000000+00:00"
+`), 0644))
+
+	chart, err := ReadHelmChart(chartDir)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", chart.ApiVersion)
+	assert.Equal(t, "app", chart.Name)
+	assert.Equal(t, "1.2.3", chart.Version)
+	require.Len(t, chart.Dependencies, 2)
+	assert.Equal(t, "cache", chart.Dependencies[0].Alias)
+
+	lock, err := ReadHelmLock(chartDir)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:5b2f0bd6c2d3d4e0", lock.Digest)
+	require.Len(t, lock.Dependencies, 2)
+	redis, common := lock.Dependencies[0], lock.Dependencies[1]
+	assert.Equal(t, "redis:17.3.14", redis.Id())
+	assert.False(t, redis.IsLocal())
+	assert.Equal(t, "https://charts.bitnami.com/bitnami", redis.GetRepositoryUrl())
+	assert.Equal(t, filepath.Join(chartDir, "charts", "redis-17.3.14.tgz"), redis.GetArchivePath(chartDir))
+	assert.True(t, common.IsLocal())
+	assert.Equal(t, filepath.Join("..", "common"), common.GetLocalPath())
+	assert.Empty(t, common.GetRepositoryUrl())
+
+	_, err = ReadHelmLock(t.TempDir())
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGetHelmRepositoryUrl(t *testing.T) {
+	for repository, expected := range map[string]string{
+		"https://charts.example.com":        "https://charts.example.com",
+		"oci://registry.example.com/charts": "oci://registry.example.com/charts",
+		"@bitnami":                          "",
+		"alias:bitnami":                     "",
+		"file://../common":                  "",
+		"":                                  "",
+	} {
+		dependency := &HelmChartDependency{Name: "dep", Version: "1.0.0", Repository: repository}
+		assert.Equal(t, expected, dependency.GetRepositoryUrl(), repository)
+	}
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The constant pool tags of the class file format, which are handled when reading a module-info class.
+const (
+	classConstantUtf8   = 1
+	classConstantLong   = 5
+	classConstantDouble = 6
+	classConstantModule = 19
+)
+
+// The number of bytes which follow each of the constant pool tags with a fixed size. The sizes of CONSTANT_Utf8 entries are variable,
+// and CONSTANT_Long and CONSTANT_Double entries take two slots of the pool.
+var classConstantSizes = map[byte]int{3: 4, 4: 4, 5: 8, 6: 8, 7: 2, 8: 2, 9: 4, 10: 4, 11: 4, 12: 4, 15: 3, 16: 2, 17: 4, 18: 4, 19: 2, 20: 2}
+
+// JavaModuleInfo is the declaration of a Java module, compiled to a module-info.class file.
+type JavaModuleInfo struct {
+	Name string
+	// The names of the modules which the module requires, including java.base.
+	Requires []string
+}
+
+// ParseJavaModuleInfo parses the Module attribute of a compiled module-info.class file.
+func ParseJavaModuleInfo(content []byte) (*JavaModuleInfo, error) {
+	reader := bytes.NewReader(content)
+	var header struct {
+		Magic                      uint32
+		MinorVersion, MajorVersion uint16
+		ConstantPoolCount          uint16
+	}
+	if err := binary.Read(reader, binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse module-info.class: %w", err)
+	}
+	if header.Magic != 0xCAFEBABE {
+		return nil, errors.New("failed to parse module-info.class: not a class file")
+	}
+	utf8Constants, moduleConstants, err := readClassConstantPool(reader, header.ConstantPoolCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse module-info.class: %w", err)
+	}
+	moduleName := func(index uint16) string {
+		return utf8Constants[moduleConstants[index]]
+	}
+	// A module-info class has no interfaces, fields and methods, so its attributes follow their empty counts.
+	var classInfo struct {
+		AccessFlags, ThisClass, SuperClass         uint16
+		InterfacesCount, FieldsCount, MethodsCount uint16
+		AttributesCount                            uint16
+	}
+	if err = binary.Read(reader, binary.BigEndian, &classInfo); err != nil {
+		return nil, fmt.Errorf("failed to parse module-info.class: %w", err)
+	}
+	if classInfo.InterfacesCount != 0 || classInfo.FieldsCount != 0 || classInfo.MethodsCount != 0 {
+		return nil, errors.New("failed to parse module-info.class: the class isn't a module declaration")
+	}
+	for i := uint16(0); i < classInfo.AttributesCount; i++ {
+		var attribute struct {
+			NameIndex uint16
+			Length    uint32
+		}
+		if err = binary.Read(reader, binary.BigEndian, &attribute); err != nil {
+			return nil, fmt.Errorf("failed to parse module-info.class: %w", err)
+		}
+		if utf8Constants[attribute.NameIndex] != "Module" {
+			if _, err = reader.Seek(int64(attribute.Length), io.SeekCurrent); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		var module struct {
+			NameIndex, Flags, VersionIndex, RequiresCount uint16
+		}
+		if err = binary.Read(reader, binary.BigEndian, &module); err != nil {
+			return nil, fmt.Errorf("failed to parse module-info.class: %w", err)
+		}
+		moduleInfo := &JavaModuleInfo{Name: moduleName(module.NameIndex)}
+		for j := uint16(0); j < module.RequiresCount; j++ {
+			var requires struct {
+				Index, Flags, VersionIndex uint16
+			}
+			if err = binary.Read(reader, binary.BigEndian, &requires); err != nil {
+				return nil, fmt.Errorf("failed to parse module-info.class: %w", err)
+			}
+			moduleInfo.Requires = append(moduleInfo.Requires, moduleName(requires.Index))
+		}
+		return moduleInfo, nil
+	}
+	return nil, errors.New("failed to parse module-info.class: the class has no Module attribute")
+}
+
+// Reads the constant pool of a class file. Returns the CONSTANT_Utf8 values and the name indexes of the CONSTANT_Module entries, by their indexes in the pool.
+func readClassConstantPool(reader *bytes.Reader, count uint16) (utf8Constants map[uint16]string, moduleConstants map[uint16]uint16, err error) {
+	utf8Constants, moduleConstants = make(map[uint16]string), make(map[uint16]uint16)
+	// The pool's indexes start at 1.
+	for index := uint16(1); index < count; index++ {
+		tag, err := reader.ReadByte()
+		if err != nil {
+			return nil, nil, err
+		}
+		switch tag {
+		case classConstantUtf8:
+			var length uint16
+			if err = binary.Read(reader, binary.BigEndian, &length); err != nil {
+				return nil, nil, err
+			}
+			value := make([]byte, length)
+			if _, err = io.ReadFull(reader, value); err != nil {
+				return nil, nil, err
+			}
+			utf8Constants[index] = string(value)
+		case classConstantModule:
+			var nameIndex uint16
+			if err = binary.Read(reader, binary.BigEndian, &nameIndex); err != nil {
+				return nil, nil, err
+			}
+			moduleConstants[index] = nameIndex
+		default:
+			size, ok := classConstantSizes[tag]
+			if !ok {
+				return nil, nil, fmt.Errorf("unknown constant pool tag %d", tag)
+			}
+			if _, err = reader.Seek(int64(size), io.SeekCurrent); err != nil {
+				return nil, nil, err
+			}
+			if tag == classConstantLong || tag == classConstantDouble {
+				index++
+			}
+		}
+	}
+	return utf8Constants, moduleConstants, nil
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJavaModuleInfo(t *testing.T) {
+	moduleInfo, err := ParseJavaModuleInfo(newModuleInfoClass(t))
+	require.NoError(t, err)
+	assert.Equal(t, &JavaModuleInfo{Name: "org.example.app", Requires: []string{"java.base", "com.google.common"}}, moduleInfo)
+
+	_, err = ParseJavaModuleInfo([]byte("not a class"))
+	assert.ErrorContains(t, err, "failed to parse module-info.class")
+	_, err = ParseJavaModuleInfo(newModuleInfoClass(t)[:40])
+	assert.Error(t, err)
+}
+
+// Returns the compiled form of:
+//
+//	module org.example.app {
+//	    requires com.google.common;
+//	}
+//
+// with a CONSTANT_Long entry in its constant pool, which takes two of the pool's slots.
+func newModuleInfoClass(t *testing.T) []byte {
+	var class bytes.Buffer
+	write := func(values ...any) {
+		for _, value := range values {
+			require.NoError(t, binary.Write(&class, binary.BigEndian, value))
+		}
+	}
+	writeUtf8 := func(value string) {
+		write(uint8(classConstantUtf8), uint16(len(value)))
+		class.WriteString(value)
+	}
+	write(uint32(0xCAFEBABE), uint16(0), uint16(53), uint16(12))
+	writeUtf8("module-info")                            // 1
+	write(uint8(7), uint16(1))                          // 2: CONSTANT_Class module-info
+	writeUtf8("Module")                                 // 3
+	writeUtf8("org.example.app")                        // 4
+	write(uint8(classConstantModule), uint16(4))        // 5
+	writeUtf8("java.base")                              // 6
+	write(uint8(classConstantModule), uint16(6))        // 7
+	writeUtf8("com.google.common")                      // 8
+	write(uint8(classConstantModule), uint16(8))        // 9
+	write(uint8(classConstantLong), uint64(1234567890)) // 10 and 11
+
+	// ACC_MODULE, this_class, super_class, and no interfaces, fields and methods.
+	write(uint16(0x8000), uint16(2), uint16(0), uint16(0), uint16(0), uint16(0))
+	// A single Module attribute, with the module's name, flags and version, two requires entries, and no exports, opens, uses and provides.
+	write(uint16(1), uint16(3), uint32(28))
+	write(uint16(5), uint16(0), uint16(0), uint16(2))
+	write(uint16(7), uint16(0x8000), uint16(0))
+	write(uint16(9), uint16(0), uint16(0))
+	write(uint16(0), uint16(0), uint16(0), uint16(0))
+	return class.Bytes()
+}
@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/build-info-go/utils/purl"
+)
+
+// LocalCaches holds the directories of the package managers' local caches, in which the files of dependencies are looked up.
+// An empty directory skips its cache.
+type LocalCaches struct {
+	// The local Maven repository, for example: ~/.m2/repository
+	MavenRepository string
+	// The Gradle user home, whose caches/modules-2/files-2.1 directory holds the downloaded files, for example: ~/.gradle
+	GradleUserHome string
+	// The npm cache, whose _cacache directory holds the downloaded tarballs, for example: ~/.npm
+	NpmCache string
+	// The pip cache, whose wheels directory holds the wheels built by pip, for example: ~/.cache/pip
+	PipCache string
+	// The Conan 1.x user home, whose data directory holds the recipes and packages, for example: ~/.conan
+	ConanHome string
+	// The Helm repository cache, which holds the downloaded charts, for example: ~/.cache/helm/repository
+	HelmRepositoryCache string
+
+	// The wheels in the pip cache, by their normalized names and versions. Indexed on the first lookup.
+	pipWheels     map[string]string
+	pipWheelsOnce sync.Once
+}
+
+// GetDefaultLocalCaches returns the default directories of the local caches, as overridden by the package managers' environment variables:
+// GRADLE_USER_HOME, npm_config_cache, PIP_CACHE_DIR, CONAN_USER_HOME and HELM_REPOSITORY_CACHE.
+func GetDefaultLocalCaches() (*LocalCaches, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	npmCache, err := getDefaultNpmCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &LocalCaches{
+		MavenRepository:     filepath.Join(home, ".m2", "repository"),
+		GradleUserHome:      getEnvOrDefault("GRADLE_USER_HOME", filepath.Join(home, ".gradle")),
+		NpmCache:            npmCache,
+		PipCache:            getEnvOrDefault("PIP_CACHE_DIR", filepath.Join(userCacheDir, "pip")),
+		ConanHome:           filepath.Join(getEnvOrDefault("CONAN_USER_HOME", home), ".conan"),
+		HelmRepositoryCache: getEnvOrDefault("HELM_REPOSITORY_CACHE", filepath.Join(userCacheDir, "helm", "repository")),
+	}, nil
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// FindPackageFile returns the path of the package's file in the local caches, or an empty string if it isn't cached.
+// The supported package URL types are maven, npm, pypi, conan and helm. The file of each type is:
+// maven - The artifact of the package's type and classifier qualifiers (jar by default), in the local Maven repository or in the Gradle cache.
+// npm - The tarball of the package.
+// pypi - A wheel of the package, built by pip. The cached downloads of pip aren't indexed by the packages' names, so they aren't looked up.
+// conan - The archive of the package's recipe, since the cache may hold several binary packages of the recipe.
+// helm - The chart's archive.
+func (lc *LocalCaches) FindPackageFile(packageUrl *purl.PackageURL) (string, error) {
+	if packageUrl.Version == "" {
+		return "", nil
+	}
+	switch packageUrl.Type {
+	case purl.Maven:
+		return lc.findMavenFile(packageUrl)
+	case purl.Npm:
+		return lc.findNpmTarball(packageUrl)
+	case purl.Pypi:
+		return lc.findPipWheel(packageUrl), nil
+	case purl.Conan:
+		return lc.findConanRecipeArchive(packageUrl)
+	case purl.Helm:
+		return findExistingFile(lc.HelmRepositoryCache, packageUrl.Name+"-"+packageUrl.Version+".tgz")
+	}
+	return "", nil
+}
+
+func (lc *LocalCaches) findMavenFile(packageUrl *purl.PackageURL) (string, error) {
+	extension := packageUrl.Qualifiers["type"]
+	if extension == "" {
+		extension = "jar"
+	}
+	fileName := packageUrl.Name + "-" + packageUrl.Version
+	if classifier := packageUrl.Qualifiers["classifier"]; classifier != "" {
+		fileName += "-" + classifier
+	}
+	fileName += "." + extension
+	if lc.MavenRepository != "" {
+		groupPath := filepath.Join(strings.Split(packageUrl.Namespace, ".")...)
+		filePath, err := findExistingFile(filepath.Join(lc.MavenRepository, groupPath, packageUrl.Name, packageUrl.Version), fileName)
+		if filePath != "" || err != nil {
+			return filePath, err
+		}
+	}
+	if lc.GradleUserHome == "" {
+		return "", nil
+	}
+	// Gradle's cache layout: caches/modules-2/files-2.1/<group>/<name>/<version>/<sha1>/<file name>
+	filePaths, err := filepath.Glob(filepath.Join(lc.GradleUserHome, "caches", "modules-2", "files-2.1", packageUrl.Namespace, packageUrl.Name, packageUrl.Version, "*", fileName))
+	if err != nil || len(filePaths) == 0 {
+		return "", err
+	}
+	return filePaths[0], nil
+}
+
+func (lc *LocalCaches) findNpmTarball(packageUrl *purl.PackageURL) (string, error) {
+	if lc.NpmCache == "" {
+		return "", nil
+	}
+	name := packageUrl.Name
+	if packageUrl.Namespace != "" {
+		name = packageUrl.Namespace + "/" + name
+	}
+	cacache := NewNpmCacache(filepath.Join(lc.NpmCache, "_cacache"))
+	info, err := cacache.GetInfo(name + "@" + packageUrl.Version)
+	if err != nil || info.Integrity == "" {
+		return "", ignoreCacheMiss(err)
+	}
+	tarballPath, err := cacache.GetTarball(info.Integrity)
+	return tarballPath, ignoreCacheMiss(err)
+}
+
+func ignoreCacheMiss(err error) error {
+	if utils.GetErrorCategory(err) == utils.CacheMiss {
+		return nil
+	}
+	return err
+}
+
+func (lc *LocalCaches) findPipWheel(packageUrl *purl.PackageURL) string {
+	lc.pipWheelsOnce.Do(func() {
+		lc.pipWheels = map[string]string{}
+		if lc.PipCache == "" {
+			return
+		}
+		// Wheels are named <name>-<version>(-<build tag>)?-<python tag>-<abi tag>-<platform tag>.whl
+		_ = filepath.WalkDir(filepath.Join(lc.PipCache, "wheels"), func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !strings.HasSuffix(entry.Name(), ".whl") {
+				return nil
+			}
+			if parts := strings.Split(entry.Name(), "-"); len(parts) >= 5 {
+				lc.pipWheels[normalizePythonPackageName(parts[0])+":"+parts[1]] = path
+			}
+			return nil
+		})
+	})
+	return lc.pipWheels[normalizePythonPackageName(packageUrl.Name)+":"+packageUrl.Version]
+}
+
+// Normalizes the name of a Python package, as in PEP 503. The names in wheels' file names have their '-' replaced with '_'.
+func normalizePythonPackageName(name string) string {
+	return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+}
+
+// Conan 1.x cache layout: data/<name>/<version>/<user>/<channel>/dl/export/conan_export.tgz, with '_' for a missing user or channel.
+func (lc *LocalCaches) findConanRecipeArchive(packageUrl *purl.PackageURL) (string, error) {
+	if lc.ConanHome == "" {
+		return "", nil
+	}
+	user, channel := packageUrl.Qualifiers["user"], packageUrl.Qualifiers["channel"]
+	if user == "" {
+		user = "_"
+	}
+	if channel == "" {
+		channel = "_"
+	}
+	return findExistingFile(filepath.Join(lc.ConanHome, "data", packageUrl.Name, packageUrl.Version, user, channel, "dl", "export"), "conan_export.tgz")
+}
+
+// Returns the path of the file in the directory, or an empty string if it doesn't exist or if the directory is empty.
+func findExistingFile(dir, fileName string) (string, error) {
+	if dir == "" {
+		return "", nil
+	}
+	filePath := filepath.Join(dir, fileName)
+	exists, err := utils.IsFileExists(filePath, false)
+	if err != nil || !exists {
+		return "", err
+	}
+	return filePath, nil
+}
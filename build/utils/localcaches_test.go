@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/utils/purl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCachedFile(t *testing.T, path string) string {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(filepath.Base(path)), 0644))
+	return path
+}
+
+// Adds a tarball to a cacache directory, indexed by the pacote:tarball:<name>@<version> key.
+func writeCacacheTarball(t *testing.T, cacacheDir, nameVersion string) string {
+	content := []byte(nameVersion)
+	digest := sha512.Sum512(content)
+	hexDigest := hex.EncodeToString(digest[:])
+	tarballPath := writeCachedFile(t, filepath.Join(cacacheDir, "content-v2", "sha512", hexDigest[0:2], hexDigest[2:4], hexDigest[4:]))
+	require.NoError(t, os.WriteFile(tarballPath, content, 0644))
+	keyHash := sha256.Sum256([]byte("pacote:tarball:" + nameVersion))
+	hexKey := hex.EncodeToString(keyHash[:])
+	indexPath := writeCachedFile(t, filepath.Join(cacacheDir, "index-v5", hexKey[0:2], hexKey[2:4], hexKey[4:]))
+	integrity := "sha512-" + base64.StdEncoding.EncodeToString(digest[:])
+	require.NoError(t, os.WriteFile(indexPath, []byte("\nhash\t{\"integrity\":\""+integrity+"\"}"), 0644))
+	return tarballPath
+}
+
+func TestLocalCachesFindPackageFile(t *testing.T) {
+	cachesDir := t.TempDir()
+	localCaches := &LocalCaches{
+		MavenRepository:     filepath.Join(cachesDir, "m2"),
+		GradleUserHome:      filepath.Join(cachesDir, "gradle"),
+		NpmCache:            filepath.Join(cachesDir, "npm"),
+		PipCache:            filepath.Join(cachesDir, "pip"),
+		ConanHome:           filepath.Join(cachesDir, "conan"),
+		HelmRepositoryCache: filepath.Join(cachesDir, "helm"),
+	}
+	expected := map[string]string{
+		"pkg:maven/org.slf4j/slf4j-api@2.0.9":               writeCachedFile(t, filepath.Join(cachesDir, "m2", "org", "slf4j", "slf4j-api", "2.0.9", "slf4j-api-2.0.9.jar")),
+		"pkg:maven/org.jfrog/bom@1.0.0?type=pom":            writeCachedFile(t, filepath.Join(cachesDir, "m2", "org", "jfrog", "bom", "1.0.0", "bom-1.0.0.pom")),
+		"pkg:maven/com.google/guava@33.0.0":                 writeCachedFile(t, filepath.Join(cachesDir, "gradle", "caches", "modules-2", "files-2.1", "com.google", "guava", "33.0.0", "5e64ec7e", "guava-33.0.0.jar")),
+		"pkg:npm/%40jfrog/build-info@1.0.0":                 writeCacacheTarball(t, filepath.Join(cachesDir, "npm", "_cacache"), "@jfrog/build-info@1.0.0"),
+		"pkg:pypi/zope-interface@6.1":                       writeCachedFile(t, filepath.Join(cachesDir, "pip", "wheels", "1a", "2b", "zope.interface-6.1-cp312-cp312-linux_x86_64.whl")),
+		"pkg:conan/zlib@1.3.1":                              writeCachedFile(t, filepath.Join(cachesDir, "conan", "data", "zlib", "1.3.1", "_", "_", "dl", "export", "conan_export.tgz")),
+		"pkg:conan/openssl@3.2.0?channel=stable&user=jfrog": writeCachedFile(t, filepath.Join(cachesDir, "conan", "data", "openssl", "3.2.0", "jfrog", "stable", "dl", "export", "conan_export.tgz")),
+		"pkg:helm/nginx@15.4.0":                             writeCachedFile(t, filepath.Join(cachesDir, "helm", "nginx-15.4.0.tgz")),
+		"pkg:maven/org.slf4j/slf4j-api@1.7.36":              "",
+		"pkg:npm/debug@4.3.4":                               "",
+		"pkg:pypi/requests@2.31.0":                          "",
+		"pkg:helm/nginx":                                    "",
+		"pkg:golang/github.com/jfrog/gofrog@v1.7.6":         "",
+	}
+	for packageUrlString, expectedPath := range expected {
+		t.Run(packageUrlString, func(t *testing.T) {
+			packageUrl, err := purl.Parse(packageUrlString)
+			require.NoError(t, err)
+			filePath, err := localCaches.FindPackageFile(packageUrl)
+			require.NoError(t, err)
+			assert.Equal(t, expectedPath, filePath)
+		})
+	}
+
+	// Empty directories skip their caches.
+	packageUrl, err := purl.Parse("pkg:maven/org.slf4j/slf4j-api@2.0.9")
+	require.NoError(t, err)
+	filePath, err := (&LocalCaches{}).FindPackageFile(packageUrl)
+	require.NoError(t, err)
+	assert.Empty(t, filePath)
+}
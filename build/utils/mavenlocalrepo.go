@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The system property which overrides the local repository of Maven's settings files.
+const MavenRepoLocalProperty = "maven.repo.local"
+
+// GetMavenLocalRepository returns the path of the local Maven repository, in Maven's order of precedence: the maven.repo.local system property,
+// if repoLocalProperty isn't empty, the <localRepository> of the user settings file and then of the global settings file, and the default ~/.m2/repository.
+// A settings file which doesn't exist is ignored, and an empty path is skipped.
+func GetMavenLocalRepository(repoLocalProperty, userSettingsPath, globalSettingsPath string) (string, error) {
+	if repoLocalProperty != "" {
+		return repoLocalProperty, nil
+	}
+	settings, err := ReadMavenSettings(userSettingsPath, globalSettingsPath)
+	if err != nil {
+		return "", err
+	}
+	if localRepository := settings.LocalRepository(); localRepository != "" {
+		return localRepository, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".m2", "repository"), nil
+}
+
+// The file in which Maven Resolver records the repositories from which the files of an artifact's directory in the local repository were downloaded.
+const mavenRemoteRepositoriesFileName = "_remote.repositories"
+
+// ReadMavenRemoteRepositories reads the _remote.repositories file of an artifact's directory in the local Maven repository,
+// and returns the IDs of the repositories from which the directory's files were downloaded, mapped by the files' names.
+// Files which were installed locally aren't returned. If the file doesn't exist, an empty map is returned.
+func ReadMavenRemoteRepositories(artifactDir string) (repositories map[string]string, err error) {
+	repositories = map[string]string{}
+	file, err := os.Open(filepath.Join(artifactDir, mavenRemoteRepositoriesFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return repositories, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, file.Close())
+	}()
+	// Each line has the format: <file name>><repository ID>=
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fileName, repositoryId, found := strings.Cut(strings.TrimSuffix(line, "="), ">")
+		if found && repositoryId != "" {
+			repositories[fileName] = repositoryId
+		}
+	}
+	err = scanner.Err()
+	return
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMavenRemoteRepositories(t *testing.T) {
+	artifactDir := t.TempDir()
+	repositories, err := ReadMavenRemoteRepositories(artifactDir)
+	require.NoError(t, err)
+	assert.Empty(t, repositories)
+
+	content := `#NOTE: This is a Maven Resolver internal implementation file, its format can be changed without prior notice.
+#Mon Oct 16 10:00:00 IDT 2026
+slf4j-api-2.0.9.jar>central=
+slf4j-api-2.0.9.pom>artifactory-mirror=
+slf4j-api-2.0.9-sources.jar>=
+`
+	require.NoError(t, os.WriteFile(filepath.Join(artifactDir, "_remote.repositories"), []byte(content), 0644))
+	repositories, err = ReadMavenRemoteRepositories(artifactDir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"slf4j-api-2.0.9.jar": "central", "slf4j-api-2.0.9.pom": "artifactory-mirror"}, repositories)
+}
+
+func TestGetMavenLocalRepository(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("MAVEN_REPOSITORIES", filepath.Join(home, "repositories"))
+	settingsDir := t.TempDir()
+	userSettingsPath := filepath.Join(settingsDir, "settings.xml")
+	globalSettingsPath := filepath.Join(settingsDir, "global-settings.xml")
+
+	// Without settings, the default is used.
+	localRepository, err := GetMavenLocalRepository("", userSettingsPath, globalSettingsPath)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".m2", "repository"), localRepository)
+
+	require.NoError(t, os.WriteFile(globalSettingsPath, []byte("<settings><localRepository>${env.MAVEN_REPOSITORIES}/global</localRepository></settings>"), 0644))
+	localRepository, err = GetMavenLocalRepository("", userSettingsPath, globalSettingsPath)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "repositories")+"/global", localRepository)
+
+	// The user settings override the global settings.
+	require.NoError(t, os.WriteFile(userSettingsPath, []byte("<settings>\n  <localRepository>\n    ${user.home}/user-repository\n  </localRepository>\n</settings>"), 0644))
+	localRepository, err = GetMavenLocalRepository("", userSettingsPath, globalSettingsPath)
+	require.NoError(t, err)
+	assert.Equal(t, home+"/user-repository", localRepository)
+
+	// The system property overrides the settings.
+	localRepository, err = GetMavenLocalRepository("/opt/repository", userSettingsPath, globalSettingsPath)
+	require.NoError(t, err)
+	assert.Equal(t, "/opt/repository", localRepository)
+}
@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	// Matches the log level prefix of a line in Maven's batch mode output, for example: [INFO] or 2024-01-01 10:00:00 [INFO]
+	mavenLogLevelRegex = regexp.MustCompile(`^.*?\[(?:INFO|DEBUG|WARNING|ERROR)] ?`)
+	// Matches the header of a reactor module, for example: --------------------< org.example:app >--------------------
+	mavenModuleHeaderRegex = regexp.MustCompile(`^-+< ([^:\s]+):([^:\s]+) >-+$`)
+	// Matches the line which follows the module's header, for example: Building app 1.0.0   [2/3]
+	mavenModuleBuildingRegex = regexp.MustCompile(`^Building .* ([^\s\[]\S*)(?:\s+\[\d+/\d+])?$`)
+	// Matches a dependency printed by the dependency:list or dependency:resolve goals,
+	// for example: org.slf4j:slf4j-api:jar:2.0.9:compile -- module org.slf4j [auto]
+	mavenResolvedDependencyRegex = regexp.MustCompile(`^([^:\s]+):([^:\s]+):([^:\s]+):(?:([^:\s]+):)?([^:\s]+):([^:\s]+)(?:\s|$)`)
+	// Matches a download of a file from a remote repository, for example:
+	// Downloaded from central: https://repo.maven.apache.org/maven2/org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar (65 kB at 1.2 MB/s)
+	mavenDownloadedRegex = regexp.MustCompile(`^Downloaded from [^:]+: (\S+)`)
+)
+
+// MavenLogModule holds the details of a reactor module, as approximated from Maven's build log.
+type MavenLogModule struct {
+	// The module's ID in the build-info format: groupId:artifactId:version.
+	Id           string
+	Dependencies []MavenLogDependency
+	// True if the log contains the output of the dependency:list or dependency:resolve goals for the module.
+	resolved bool
+}
+
+// MavenLogDependency is a dependency approximated from Maven's build log.
+type MavenLogDependency struct {
+	// The dependency's ID in the build-info format: groupId:artifactId:version.
+	Id    string
+	Type  string
+	Scope string
+	// The URL of the remote repository from which the dependency was downloaded. Empty if it was already in the local repository.
+	RepositoryUrl string
+}
+
+type mavenLogParser struct {
+	localRepository string
+	modules         []*MavenLogModule
+	// The module whose log lines are currently parsed.
+	current *MavenLogModule
+	// The groupId and artifactId of the last module header, until the module's version is parsed.
+	pendingModule string
+	// True while parsing the files listed by the dependency:list or dependency:resolve goals.
+	inResolvedList bool
+}
+
+// ParseMavenLog approximates the modules and the dependencies of a Maven build from its log, written in batch mode (-B).
+// The dependencies of each module are taken from the output of the dependency:list or dependency:resolve goals if it exists,
+// and from the files downloaded while building the module otherwise, which may include the module's plugins as well.
+// The downloads are also used to find the URLs of the remote repositories from which the dependencies were downloaded.
+// localRepository - The local Maven repository to which the files were downloaded. It's used to separate the groupId from the repository's URL.
+func ParseMavenLog(log io.Reader, localRepository string) ([]MavenLogModule, error) {
+	parser := &mavenLogParser{localRepository: localRepository}
+	scanner := bufio.NewScanner(log)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		parser.parseLine(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	var modules []MavenLogModule
+	for _, module := range parser.modules {
+		if module.resolved {
+			// The downloads which aren't listed by the dependency goals belong to the module's plugins.
+			var dependencies []MavenLogDependency
+			for _, dependency := range module.Dependencies {
+				if dependency.Scope != "" {
+					dependencies = append(dependencies, dependency)
+				}
+			}
+			module.Dependencies = dependencies
+		}
+		modules = append(modules, *module)
+	}
+	return modules, nil
+}
+
+func (p *mavenLogParser) parseLine(line string) {
+	line = strings.TrimSpace(mavenLogLevelRegex.ReplaceAllString(line, ""))
+	if match := mavenModuleHeaderRegex.FindStringSubmatch(line); match != nil {
+		p.pendingModule = match[1] + ":" + match[2]
+		p.inResolvedList = false
+		return
+	}
+	if p.pendingModule != "" {
+		if match := mavenModuleBuildingRegex.FindStringSubmatch(line); match != nil {
+			p.current = &MavenLogModule{Id: p.pendingModule + ":" + match[1]}
+			p.modules = append(p.modules, p.current)
+			p.pendingModule = ""
+			return
+		}
+	}
+	// The lines before the first module, such as the downloads of the build extensions, aren't attributed to any module.
+	if p.current == nil {
+		return
+	}
+	if strings.HasPrefix(line, "The following files have been resolved:") {
+		p.inResolvedList = true
+		p.current.resolved = true
+		return
+	}
+	if p.inResolvedList {
+		match := mavenResolvedDependencyRegex.FindStringSubmatch(line)
+		if match == nil {
+			p.inResolvedList = line == "none"
+			return
+		}
+		dependency := p.getOrAddDependency(match[1] + ":" + match[2] + ":" + match[5])
+		dependency.Type = match[3]
+		dependency.Scope = match[6]
+		return
+	}
+	if match := mavenDownloadedRegex.FindStringSubmatch(line); match != nil {
+		p.parseDownload(match[1])
+	}
+}
+
+// Adds the dependency of a downloaded file to the current module, with the URL of the repository it was downloaded from.
+// The checksums, signatures, POMs and metadata files are skipped.
+func (p *mavenLogParser) parseDownload(fileUrl string) {
+	parsedUrl, err := url.Parse(fileUrl)
+	if err != nil {
+		return
+	}
+	segments := strings.Split(strings.Trim(parsedUrl.Path, "/"), "/")
+	if len(segments) < 4 {
+		return
+	}
+	fileName, version, artifactId := segments[len(segments)-1], segments[len(segments)-2], segments[len(segments)-3]
+	extension := path.Ext(fileName)
+	if !strings.HasPrefix(fileName, artifactId+"-"+version) || slices.Contains([]string{"", ".pom", ".xml", ".sha1", ".sha256", ".sha512", ".md5", ".asc"}, extension) {
+		return
+	}
+	// The groupId is the longest sequence of directories, preceding the artifactId, which exists in the local repository.
+	groupSegments := segments[:len(segments)-3]
+	for i := range groupSegments {
+		if _, err = os.Stat(filepath.Join(p.localRepository, filepath.Join(segments[i:len(segments)-1]...))); err != nil {
+			continue
+		}
+		dependency := p.getOrAddDependency(strings.Join(groupSegments[i:], ".") + ":" + artifactId + ":" + version)
+		if dependency.Type == "" {
+			dependency.Type = strings.TrimPrefix(extension, ".")
+		}
+		repositoryUrl := *parsedUrl
+		repositoryUrl.Path = "/" + strings.Join(segments[:i], "/")
+		dependency.RepositoryUrl = strings.TrimSuffix(repositoryUrl.String(), "/")
+		return
+	}
+}
+
+func (p *mavenLogParser) getOrAddDependency(id string) *MavenLogDependency {
+	for i := range p.current.Dependencies {
+		if p.current.Dependencies[i].Id == id {
+			return &p.current.Dependencies[i]
+		}
+	}
+	p.current.Dependencies = append(p.current.Dependencies, MavenLogDependency{Id: id})
+	return &p.current.Dependencies[len(p.current.Dependencies)-1]
+}
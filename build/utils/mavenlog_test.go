@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mavenLog = `[INFO] Scanning for projects...
+[INFO] Downloaded from central: https://repo.maven.apache.org/maven2/org/example/extension/1.0/extension-1.0.jar (10 kB at 100 kB/s)
+[INFO] ------------------------------------------------------------------------
+[INFO] Reactor Build Order:
+[INFO]
+[INFO] lib                                                                [jar]
+[INFO] app                                                                [jar]
+[INFO]
+[INFO] -------------------------< org.example:lib >--------------------------
+[INFO] Building lib 1.0.0                                                 [1/2]
+[INFO]   from lib/pom.xml
+[INFO] --------------------------------[ jar ]---------------------------------
+[INFO] Downloading from artifactory: https://acme.jfrog.io/artifactory/api/maven/libs/org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.pom
+[INFO] Downloaded from artifactory: https://acme.jfrog.io/artifactory/api/maven/libs/org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.pom (2 kB at 20 kB/s)
+[INFO] Downloaded from artifactory: https://acme.jfrog.io/artifactory/api/maven/libs/org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar (65 kB at 1.2 MB/s)
+[INFO] Downloaded from artifactory: https://acme.jfrog.io/artifactory/api/maven/libs/org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar.sha1 (40 B at 1 kB/s)
+[INFO]
+[INFO] -------------------------< org.example:app >--------------------------
+[INFO] Building app 1.0.0                                                 [2/2]
+[INFO] --------------------------------[ jar ]---------------------------------
+[INFO] Downloaded from central: https://repo.maven.apache.org/maven2/org/apache/maven/plugins/maven-dependency-plugin/3.6.0/maven-dependency-plugin-3.6.0.jar (200 kB at 1 MB/s)
+[INFO] Downloaded from central: https://repo.maven.apache.org/maven2/com/google/guava/guava/32.1.2-jre/guava-32.1.2-jre.jar (3 MB at 10 MB/s)
+[INFO]
+[INFO] --- dependency:3.6.0:list (default-cli) @ app ---
+[INFO]
+[INFO] The following files have been resolved:
+[INFO]    com.google.guava:guava:jar:32.1.2-jre:compile -- module com.google.common [auto]
+[INFO]    org.example:lib:jar:1.0.0:compile
+[INFO]    junit:junit:jar:tests:4.13.2:test (optional)
+[INFO]
+[INFO] BUILD SUCCESS
+`
+
+func TestParseMavenLog(t *testing.T) {
+	localRepository := t.TempDir()
+	for _, dir := range []string{"org/slf4j/slf4j-api/2.0.9", "com/google/guava/guava/32.1.2-jre", "org/apache/maven/plugins/maven-dependency-plugin/3.6.0"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(localRepository, dir), 0755))
+	}
+	modules, err := ParseMavenLog(strings.NewReader(mavenLog), localRepository)
+	require.NoError(t, err)
+	assert.Equal(t, []MavenLogModule{
+		{Id: "org.example:lib:1.0.0", Dependencies: []MavenLogDependency{
+			{Id: "org.slf4j:slf4j-api:2.0.9", Type: "jar", RepositoryUrl: "https://acme.jfrog.io/artifactory/api/maven/libs"},
+		}},
+		// The plugin download isn't listed by the dependency:list goal, so it's dropped.
+		{Id: "org.example:app:1.0.0", resolved: true, Dependencies: []MavenLogDependency{
+			{Id: "com.google.guava:guava:32.1.2-jre", Type: "jar", Scope: "compile", RepositoryUrl: "https://repo.maven.apache.org/maven2"},
+			{Id: "org.example:lib:1.0.0", Type: "jar", Scope: "compile"},
+			{Id: "junit:junit:4.13.2", Type: "jar", Scope: "test"},
+		}},
+	}, modules)
+}
@@ -0,0 +1,277 @@
+package utils
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/build-info-go/utils"
+)
+
+const defaultMavenPluginsGroupId = "org.apache.maven.plugins"
+
+// Matches a property reference, for example: ${maven.compiler.version}.
+var mavenPropertyRegex = regexp.MustCompile(`\$\{([^}]+)}`)
+
+// MavenBuildPlugin is a plugin or an extension, declared in the <build> section of a POM.
+type MavenBuildPlugin struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
+// Id returns the plugin's ID in the build-info format: groupId:artifactId:version.
+func (mbp MavenBuildPlugin) Id() string {
+	return mbp.GroupId + ":" + mbp.ArtifactId + ":" + mbp.Version
+}
+
+func (mbp MavenBuildPlugin) key() string {
+	return mbp.GroupId + ":" + mbp.ArtifactId
+}
+
+// MavenModuleBuildPlugins holds the effective build plugins and extensions of a Maven module, including the inherited ones.
+type MavenModuleBuildPlugins struct {
+	Plugins    []MavenBuildPlugin
+	Extensions []MavenBuildPlugin
+}
+
+type mavenPom struct {
+	GroupId    string          `xml:"groupId"`
+	ArtifactId string          `xml:"artifactId"`
+	Version    string          `xml:"version"`
+	Parent     mavenPomParent  `xml:"parent"`
+	Properties mavenProperties `xml:"properties"`
+	Modules    []string        `xml:"modules>module"`
+	Build      struct {
+		Plugins          []MavenBuildPlugin `xml:"plugins>plugin"`
+		PluginManagement []MavenBuildPlugin `xml:"pluginManagement>plugins>plugin"`
+		Extensions       []MavenBuildPlugin `xml:"extensions>extension"`
+	} `xml:"build"`
+}
+
+type mavenPomParent struct {
+	GroupId      string  `xml:"groupId"`
+	ArtifactId   string  `xml:"artifactId"`
+	Version      string  `xml:"version"`
+	RelativePath *string `xml:"relativePath"`
+}
+
+type mavenProperties map[string]string
+
+func (mp *mavenProperties) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var properties struct {
+		Values []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	}
+	if err := decoder.DecodeElement(&properties, &start); err != nil {
+		return err
+	}
+	*mp = mavenProperties{}
+	for _, property := range properties.Values {
+		(*mp)[property.XMLName.Local] = strings.TrimSpace(property.Value)
+	}
+	return nil
+}
+
+// The effective model of a POM, after applying the inheritance from its parents.
+type effectiveMavenPom struct {
+	id               string
+	properties       map[string]string
+	plugins          []MavenBuildPlugin
+	pluginManagement []MavenBuildPlugin
+	extensions       []MavenBuildPlugin
+}
+
+// GetMavenBuildPlugins reads the POMs of the Maven project in projectPath, including its modules,
+// and returns the build plugins and extensions of each module, mapped by the module ID (groupId:artifactId:version).
+// Plugins without a version in the POMs (for example, the default lifecycle plugins) are not returned,
+// since their version is determined by Maven itself.
+func GetMavenBuildPlugins(projectPath string) (map[string]MavenModuleBuildPlugins, error) {
+	return GetMavenPomBuildPlugins(filepath.Join(projectPath, "pom.xml"))
+}
+
+// GetMavenPomBuildPlugins is like GetMavenBuildPlugins, for a project whose POM isn't named pom.xml.
+func GetMavenPomBuildPlugins(pomPath string) (map[string]MavenModuleBuildPlugins, error) {
+	reader := &mavenPomsReader{effectivePoms: map[string]*effectiveMavenPom{}, modulesPlugins: map[string]MavenModuleBuildPlugins{}}
+	if err := reader.readModules(pomPath); err != nil {
+		return nil, err
+	}
+	return reader.modulesPlugins, nil
+}
+
+type mavenPomsReader struct {
+	// The effective POMs which were already read, mapped by the POM's absolute path.
+	effectivePoms  map[string]*effectiveMavenPom
+	modulesPlugins map[string]MavenModuleBuildPlugins
+}
+
+// Reads the POM in pomPath and recursively the POMs of its modules.
+func (mpr *mavenPomsReader) readModules(pomPath string) error {
+	pom, effectivePom, err := mpr.readEffectivePom(pomPath)
+	if err != nil || pom == nil {
+		return err
+	}
+	mpr.modulesPlugins[effectivePom.id] = MavenModuleBuildPlugins{
+		Plugins:    withVersion(effectivePom.plugins),
+		Extensions: withVersion(effectivePom.extensions),
+	}
+	for _, module := range pom.Modules {
+		modulePath := filepath.Join(filepath.Dir(pomPath), filepath.FromSlash(strings.TrimSpace(module)))
+		if !strings.HasSuffix(modulePath, ".xml") {
+			modulePath = filepath.Join(modulePath, "pom.xml")
+		}
+		if err = mpr.readModules(modulePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reads the POM in pomPath and calculates its effective model. If the POM doesn't exist, nil is returned.
+func (mpr *mavenPomsReader) readEffectivePom(pomPath string) (*mavenPom, *effectiveMavenPom, error) {
+	pomPath, err := filepath.Abs(pomPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := os.ReadFile(pomPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	pom := &mavenPom{}
+	if err = xml.Unmarshal(content, pom); err != nil {
+		return nil, nil, utils.NewCategorizedError(utils.ParseFailure, errors.New("failed parsing "+pomPath+": "+err.Error()))
+	}
+	if effectivePom, ok := mpr.effectivePoms[pomPath]; ok {
+		return pom, effectivePom, nil
+	}
+
+	parent, err := mpr.readParentPom(pomPath, pom)
+	if err != nil {
+		return nil, nil, err
+	}
+	effectivePom := &effectiveMavenPom{properties: map[string]string{}}
+	if parent != nil {
+		for key, value := range parent.properties {
+			effectivePom.properties[key] = value
+		}
+		effectivePom.pluginManagement = parent.pluginManagement
+		effectivePom.plugins = parent.plugins
+		effectivePom.extensions = parent.extensions
+	}
+	for key, value := range pom.Properties {
+		effectivePom.properties[key] = value
+	}
+	groupId, version := pom.GroupId, pom.Version
+	if groupId == "" {
+		groupId = pom.Parent.GroupId
+	}
+	if version == "" {
+		version = pom.Parent.Version
+	}
+	effectivePom.properties["project.groupId"] = groupId
+	effectivePom.properties["project.artifactId"] = pom.ArtifactId
+	effectivePom.properties["project.version"] = version
+	effectivePom.properties["project.parent.version"] = pom.Parent.Version
+	effectivePom.id = resolveMavenProperties(groupId+":"+pom.ArtifactId+":"+version, effectivePom.properties)
+
+	effectivePom.pluginManagement = mergeBuildPlugins(effectivePom.pluginManagement, pom.Build.PluginManagement, defaultMavenPluginsGroupId, effectivePom.properties, nil)
+	effectivePom.plugins = mergeBuildPlugins(effectivePom.plugins, pom.Build.Plugins, defaultMavenPluginsGroupId, effectivePom.properties, effectivePom.pluginManagement)
+	effectivePom.extensions = mergeBuildPlugins(effectivePom.extensions, pom.Build.Extensions, "", effectivePom.properties, nil)
+	mpr.effectivePoms[pomPath] = effectivePom
+	return pom, effectivePom, nil
+}
+
+// Returns the effective model of the parent POM, if it's part of the project.
+func (mpr *mavenPomsReader) readParentPom(pomPath string, pom *mavenPom) (*effectiveMavenPom, error) {
+	if pom.Parent.ArtifactId == "" {
+		return nil, nil
+	}
+	relativePath := ".."
+	if pom.Parent.RelativePath != nil {
+		relativePath = strings.TrimSpace(*pom.Parent.RelativePath)
+	}
+	if relativePath == "" {
+		return nil, nil
+	}
+	parentPath := filepath.Join(filepath.Dir(pomPath), filepath.FromSlash(relativePath))
+	if !strings.HasSuffix(parentPath, ".xml") {
+		parentPath = filepath.Join(parentPath, "pom.xml")
+	}
+	parentPom, parent, err := mpr.readEffectivePom(parentPath)
+	if err != nil || parentPom == nil || parentPom.ArtifactId != pom.Parent.ArtifactId {
+		return nil, err
+	}
+	return parent, nil
+}
+
+// Adds the declared plugins to the inherited ones. A declared plugin overrides the inherited plugin with the same groupId and artifactId.
+// If a plugin has no version, it's taken from pluginManagement or from the inherited plugin.
+func mergeBuildPlugins(inherited, declared []MavenBuildPlugin, defaultGroupId string, properties map[string]string, pluginManagement []MavenBuildPlugin) []MavenBuildPlugin {
+	merged := append([]MavenBuildPlugin{}, inherited...)
+	for _, plugin := range declared {
+		plugin.GroupId = strings.TrimSpace(plugin.GroupId)
+		plugin.ArtifactId = strings.TrimSpace(plugin.ArtifactId)
+		plugin.Version = strings.TrimSpace(plugin.Version)
+		if plugin.GroupId == "" {
+			plugin.GroupId = defaultGroupId
+		}
+		plugin.GroupId = resolveMavenProperties(plugin.GroupId, properties)
+		plugin.ArtifactId = resolveMavenProperties(plugin.ArtifactId, properties)
+		plugin.Version = resolveMavenProperties(plugin.Version, properties)
+		if plugin.Version == "" {
+			plugin.Version = findBuildPluginVersion(pluginManagement, plugin)
+		}
+		replaced := false
+		for i := range merged {
+			if merged[i].key() == plugin.key() {
+				if plugin.Version == "" {
+					plugin.Version = merged[i].Version
+				}
+				merged[i] = plugin
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, plugin)
+		}
+	}
+	return merged
+}
+
+func findBuildPluginVersion(plugins []MavenBuildPlugin, plugin MavenBuildPlugin) string {
+	for _, candidate := range plugins {
+		if candidate.key() == plugin.key() {
+			return candidate.Version
+		}
+	}
+	return ""
+}
+
+// Replaces the property references in value, with the properties' values. Unknown properties are kept as is.
+func resolveMavenProperties(value string, properties map[string]string) string {
+	return mavenPropertyRegex.ReplaceAllStringFunc(value, func(reference string) string {
+		if resolved, ok := properties[mavenPropertyRegex.FindStringSubmatch(reference)[1]]; ok {
+			return resolved
+		}
+		return reference
+	})
+}
+
+// Returns the plugins whose version is known.
+func withVersion(plugins []MavenBuildPlugin) (result []MavenBuildPlugin) {
+	for _, plugin := range plugins {
+		if plugin.Version != "" && !strings.Contains(plugin.Version, "${") {
+			result = append(result, plugin)
+		}
+	}
+	return
+}
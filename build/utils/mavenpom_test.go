@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMavenBuildPlugins(t *testing.T) {
+	modulesPlugins, err := GetMavenBuildPlugins(filepath.Join("..", "testdata", "maven", "project"))
+	require.NoError(t, err)
+	jarPlugin := MavenBuildPlugin{GroupId: "org.apache.maven.plugins", ArtifactId: "maven-jar-plugin", Version: "2.4"}
+	warPlugin := MavenBuildPlugin{GroupId: "org.apache.maven.plugins", ArtifactId: "maven-war-plugin", Version: "2.4"}
+	sourcePlugin := MavenBuildPlugin{GroupId: "org.apache.maven.plugins", ArtifactId: "maven-source-plugin", Version: "2.1.2"}
+	assert.Equal(t, map[string]MavenModuleBuildPlugins{
+		"org.jfrog.test:multi:3.7-SNAPSHOT":  {Plugins: []MavenBuildPlugin{jarPlugin, warPlugin}},
+		"org.jfrog.test:multi1:3.7-SNAPSHOT": {Plugins: []MavenBuildPlugin{jarPlugin, warPlugin, sourcePlugin}},
+		"org.jfrog.test:multi2:3.7-SNAPSHOT": {Plugins: []MavenBuildPlugin{jarPlugin, warPlugin}},
+		"org.jfrog.test:multi3:3.7-SNAPSHOT": {Plugins: []MavenBuildPlugin{jarPlugin, warPlugin}},
+	}, modulesPlugins)
+}
+
+func TestGetMavenBuildPluginsWithPropertiesAndExtensions(t *testing.T) {
+	projectPath := t.TempDir()
+	pom := `<project>
+    <groupId>org.example</groupId>
+    <artifactId>app</artifactId>
+    <version>1.0.0</version>
+    <properties>
+        <compiler.version>3.11.0</compiler.version>
+    </properties>
+    <build>
+        <extensions>
+            <extension>
+                <groupId>kr.motd.maven</groupId>
+                <artifactId>os-maven-plugin</artifactId>
+                <version>1.7.1</version>
+            </extension>
+        </extensions>
+        <plugins>
+            <plugin>
+                <artifactId>maven-compiler-plugin</artifactId>
+                <version>${compiler.version}</version>
+            </plugin>
+            <plugin>
+                <artifactId>maven-surefire-plugin</artifactId>
+            </plugin>
+        </plugins>
+    </build>
+</project>`
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "pom.xml"), []byte(pom), 0644))
+	modulesPlugins, err := GetMavenBuildPlugins(projectPath)
+	require.NoError(t, err)
+	// The surefire plugin has no version in the POM, so it's not returned.
+	assert.Equal(t, map[string]MavenModuleBuildPlugins{
+		"org.example:app:1.0.0": {
+			Plugins:    []MavenBuildPlugin{{GroupId: "org.apache.maven.plugins", ArtifactId: "maven-compiler-plugin", Version: "3.11.0"}},
+			Extensions: []MavenBuildPlugin{{GroupId: "kr.motd.maven", ArtifactId: "os-maven-plugin", Version: "1.7.1"}},
+		},
+	}, modulesPlugins)
+}
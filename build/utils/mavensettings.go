@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	mavenCentralId  = "central"
+	mavenCentralUrl = "https://repo.maven.apache.org/maven2"
+)
+
+// MavenRepository is a repository which Maven resolves artifacts from.
+type MavenRepository struct {
+	Id  string `xml:"id"`
+	Url string `xml:"url"`
+}
+
+type mavenMirror struct {
+	Id       string `xml:"id"`
+	Url      string `xml:"url"`
+	MirrorOf string `xml:"mirrorOf"`
+}
+
+type mavenSettingsProfile struct {
+	Id         string `xml:"id"`
+	Activation struct {
+		ActiveByDefault bool `xml:"activeByDefault"`
+	} `xml:"activation"`
+	Repositories       []MavenRepository `xml:"repositories>repository"`
+	PluginRepositories []MavenRepository `xml:"pluginRepositories>pluginRepository"`
+}
+
+// MavenSettings holds the local repository, mirrors, profiles and active profiles of Maven's settings files.
+type MavenSettings struct {
+	localRepository string
+	mirrors         []mavenMirror
+	profiles        []mavenSettingsProfile
+	activeProfiles  []string
+}
+
+type mavenSettingsXml struct {
+	LocalRepository string                 `xml:"localRepository"`
+	Mirrors         []mavenMirror          `xml:"mirrors>mirror"`
+	Profiles        []mavenSettingsProfile `xml:"profiles>profile"`
+	ActiveProfiles  []string               `xml:"activeProfiles>activeProfile"`
+}
+
+// ReadMavenSettings reads and merges the user settings file and the global settings file, in this order of precedence.
+// A settings file which doesn't exist is ignored, and an empty path is skipped.
+func ReadMavenSettings(userSettingsPath, globalSettingsPath string) (*MavenSettings, error) {
+	settings := &MavenSettings{}
+	for _, settingsPath := range []string{userSettingsPath, globalSettingsPath} {
+		if settingsPath == "" {
+			continue
+		}
+		content, err := os.ReadFile(settingsPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		var settingsXml mavenSettingsXml
+		if err = xml.Unmarshal(content, &settingsXml); err != nil {
+			return nil, utils.NewCategorizedError(utils.ParseFailure, errors.New("failed parsing "+settingsPath+": "+err.Error()))
+		}
+		// The local repository of the user settings overrides the global one.
+		if settings.localRepository == "" {
+			settings.localRepository = strings.TrimSpace(settingsXml.LocalRepository)
+		}
+		settings.mirrors = append(settings.mirrors, settingsXml.Mirrors...)
+		for _, profile := range settingsXml.Profiles {
+			// A profile of the user settings overrides the global profile with the same ID.
+			if !slices.ContainsFunc(settings.profiles, func(existing mavenSettingsProfile) bool { return existing.Id == profile.Id }) {
+				settings.profiles = append(settings.profiles, profile)
+			}
+		}
+		for _, activeProfile := range settingsXml.ActiveProfiles {
+			if activeProfile = strings.TrimSpace(activeProfile); !slices.Contains(settings.activeProfiles, activeProfile) {
+				settings.activeProfiles = append(settings.activeProfiles, activeProfile)
+			}
+		}
+	}
+	return settings, nil
+}
+
+// LocalRepository returns the path of the local repository configured by the <localRepository> element of the settings files,
+// with ${user.home} and ${env.NAME} expressions expanded, or an empty string if it isn't configured.
+func (ms *MavenSettings) LocalRepository() string {
+	return expandMavenSettingsExpressions(ms.localRepository)
+}
+
+var mavenSettingsExpressionRegex = regexp.MustCompile(`\$\{(user\.home|env\.[^}]+)}`)
+
+func expandMavenSettingsExpressions(value string) string {
+	return mavenSettingsExpressionRegex.ReplaceAllStringFunc(value, func(expression string) string {
+		name := expression[2 : len(expression)-1]
+		if name == "user.home" {
+			if home, err := os.UserHomeDir(); err == nil {
+				return home
+			}
+			return expression
+		}
+		return os.Getenv(strings.TrimPrefix(name, "env."))
+	})
+}
+
+// ActiveProfiles returns the IDs of the settings' profiles which are active, given the profiles activated (or deactivated, with a '!' or '-' prefix)
+// on the command line. The profiles which are active by default are active only if no other profile of the settings is active.
+func (ms *MavenSettings) ActiveProfiles(commandLineProfiles []string) (active []string) {
+	activated := slices.Clone(ms.activeProfiles)
+	var deactivated []string
+	for _, profile := range commandLineProfiles {
+		profile = strings.TrimSpace(profile)
+		switch {
+		case strings.HasPrefix(profile, "!"), strings.HasPrefix(profile, "-"):
+			deactivated = append(deactivated, profile[1:])
+		case profile != "":
+			activated = append(activated, strings.TrimPrefix(profile, "+"))
+		}
+	}
+	for _, profile := range ms.profiles {
+		if slices.Contains(activated, profile.Id) && !slices.Contains(deactivated, profile.Id) {
+			active = append(active, profile.Id)
+		}
+	}
+	if len(active) > 0 {
+		return
+	}
+	for _, profile := range ms.profiles {
+		if profile.Activation.ActiveByDefault && !slices.Contains(deactivated, profile.Id) {
+			active = append(active, profile.Id)
+		}
+	}
+	return
+}
+
+// EffectiveRepositories returns the repositories which Maven resolves artifacts and plugins from:
+// the repositories of the active profiles, followed by Maven Central, with each repository replaced by its mirror.
+// A mirror of several repositories is returned once.
+func (ms *MavenSettings) EffectiveRepositories(commandLineProfiles []string) (repositories []MavenRepository) {
+	activeProfiles := ms.ActiveProfiles(commandLineProfiles)
+	var declared []MavenRepository
+	for _, profile := range ms.profiles {
+		if slices.Contains(activeProfiles, profile.Id) {
+			declared = append(declared, profile.Repositories...)
+			declared = append(declared, profile.PluginRepositories...)
+		}
+	}
+	declared = append(declared, MavenRepository{Id: mavenCentralId, Url: mavenCentralUrl})
+	for _, repository := range declared {
+		repository.Id, repository.Url = strings.TrimSpace(repository.Id), strings.TrimSpace(repository.Url)
+		if mirror := ms.findMirror(repository); mirror != nil {
+			repository = MavenRepository{Id: strings.TrimSpace(mirror.Id), Url: strings.TrimSpace(mirror.Url)}
+		}
+		if !slices.ContainsFunc(repositories, func(existing MavenRepository) bool { return existing.Id == repository.Id }) {
+			repositories = append(repositories, repository)
+		}
+	}
+	return
+}
+
+// Returns the mirror of the repository, or nil if it isn't mirrored. As in Maven, a mirror of the repository's ID takes precedence over the patterns.
+func (ms *MavenSettings) findMirror(repository MavenRepository) *mavenMirror {
+	for i, mirror := range ms.mirrors {
+		if strings.TrimSpace(mirror.MirrorOf) == repository.Id {
+			return &ms.mirrors[i]
+		}
+	}
+	for i, mirror := range ms.mirrors {
+		if matchesMirrorOf(mirror.MirrorOf, repository) {
+			return &ms.mirrors[i]
+		}
+	}
+	return nil
+}
+
+// Returns true if the repository matches the mirrorOf patterns of a mirror, which is a comma-separated list of:
+// '*', 'external:*' (repositories which aren't on localhost or in the file system), a repository ID, or an excluded repository ID ('!repo').
+func matchesMirrorOf(mirrorOf string, repository MavenRepository) bool {
+	matched := false
+	for _, pattern := range strings.Split(mirrorOf, ",") {
+		pattern = strings.TrimSpace(pattern)
+		switch {
+		case strings.HasPrefix(pattern, "!") && pattern[1:] == repository.Id:
+			return false
+		case pattern == "*", pattern == repository.Id:
+			matched = true
+		case pattern == "external:*":
+			matched = matched || isExternalMavenRepository(repository.Url)
+		}
+	}
+	return matched
+}
+
+func isExternalMavenRepository(repositoryUrl string) bool {
+	parsed, err := url.Parse(repositoryUrl)
+	if err != nil {
+		return false
+	}
+	host := parsed.Hostname()
+	return parsed.Scheme != "file" && host != "localhost" && host != "127.0.0.1"
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMavenSettingsEffectiveRepositories(t *testing.T) {
+	settingsDir := t.TempDir()
+	userSettings := `<settings>
+    <mirrors>
+        <mirror>
+            <id>corporate</id>
+            <url>https://acme.jfrog.io/artifactory/maven-remote</url>
+            <mirrorOf>external:*,!snapshots</mirrorOf>
+        </mirror>
+    </mirrors>
+    <profiles>
+        <profile>
+            <id>snapshots</id>
+            <repositories>
+                <repository>
+                    <id>snapshots</id>
+                    <url>https://acme.jfrog.io/artifactory/maven-snapshots</url>
+                </repository>
+            </repositories>
+        </profile>
+        <profile>
+            <id>local</id>
+            <activation>
+                <activeByDefault>true</activeByDefault>
+            </activation>
+            <pluginRepositories>
+                <pluginRepository>
+                    <id>local-plugins</id>
+                    <url>http://localhost:8081/plugins</url>
+                </pluginRepository>
+            </pluginRepositories>
+        </profile>
+    </profiles>
+</settings>`
+	globalSettings := `<settings>
+    <mirrors>
+        <mirror>
+            <id>global</id>
+            <url>https://global.example.com/maven</url>
+            <mirrorOf>*</mirrorOf>
+        </mirror>
+    </mirrors>
+    <activeProfiles>
+        <activeProfile>snapshots</activeProfile>
+    </activeProfiles>
+</settings>`
+	userSettingsPath, globalSettingsPath := filepath.Join(settingsDir, "settings.xml"), filepath.Join(settingsDir, "global-settings.xml")
+	require.NoError(t, os.WriteFile(userSettingsPath, []byte(userSettings), 0644))
+	require.NoError(t, os.WriteFile(globalSettingsPath, []byte(globalSettings), 0644))
+	settings, err := ReadMavenSettings(userSettingsPath, globalSettingsPath)
+	require.NoError(t, err)
+
+	// The snapshots profile is activated by the global settings, so the profile which is active by default isn't active.
+	assert.Equal(t, []string{"snapshots"}, settings.ActiveProfiles(nil))
+	// The snapshots repository is excluded from the corporate mirror, so the global mirror is used.
+	assert.Equal(t, []MavenRepository{
+		{Id: "global", Url: "https://global.example.com/maven"},
+		{Id: "corporate", Url: "https://acme.jfrog.io/artifactory/maven-remote"},
+	}, settings.EffectiveRepositories(nil))
+
+	// Deactivating the snapshots profile on the command line activates the profile which is active by default.
+	assert.Equal(t, []string{"local"}, settings.ActiveProfiles([]string{"!snapshots"}))
+	assert.Equal(t, []MavenRepository{
+		{Id: "global", Url: "https://global.example.com/maven"},
+		{Id: "corporate", Url: "https://acme.jfrog.io/artifactory/maven-remote"},
+	}, settings.EffectiveRepositories([]string{"!snapshots"}))
+
+	// Without settings files, Maven resolves from Maven Central.
+	settings, err = ReadMavenSettings(filepath.Join(settingsDir, "missing.xml"), "")
+	require.NoError(t, err)
+	assert.Equal(t, []MavenRepository{{Id: "central", Url: "https://repo.maven.apache.org/maven2"}}, settings.EffectiveRepositories([]string{"snapshots"}))
+}
+
+func TestMatchesMirrorOf(t *testing.T) {
+	external := MavenRepository{Id: "central", Url: "https://repo.maven.apache.org/maven2"}
+	local := MavenRepository{Id: "local", Url: "file:///tmp/repository"}
+	assert.True(t, matchesMirrorOf("*", local))
+	assert.True(t, matchesMirrorOf("external:*", external))
+	assert.False(t, matchesMirrorOf("external:*", local))
+	assert.True(t, matchesMirrorOf("releases, central", external))
+	assert.False(t, matchesMirrorOf("*,!central", external))
+	assert.False(t, matchesMirrorOf("releases", external))
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/slices"
+)
+
+// The file to which the dependency:tree goal writes the tree of each reactor module, relative to the module's directory.
+// The trees are written to files rather than to the log, because the log lines of modules which are built in parallel (-T) are interleaved.
+const mavenDependencyTreeFile = "target/build-info-dependency-tree.txt"
+
+var (
+	// Matches the first line of the tree, which is the module itself, for example: org.example:app:jar:1.0.0
+	mavenTreeRootRegex = regexp.MustCompile(`^([^:\s]+):([^:\s]+):[^:\s]+:([^:\s]+)$`)
+	// Matches a dependency in the tree, for example: |  \- org.hamcrest:hamcrest-core:jar:1.3:test
+	// The prefix is the tree's branches, whose width is 3 characters in each level.
+	mavenTreeDependencyRegex = regexp.MustCompile(`^((?:[| ]  )*)[+\\]- (.+)$`)
+)
+
+// MavenTreeModule is a reactor module and its dependencies, as written by the dependency:tree goal.
+type MavenTreeModule struct {
+	// The module's ID in the build-info format: groupId:artifactId:version.
+	Id string
+	// The module's direct dependencies.
+	Dependencies []*MavenTreeDependency
+}
+
+// MavenTreeDependency is a dependency in the tree written by the dependency:tree goal.
+type MavenTreeDependency struct {
+	// The dependency's ID in the build-info format: groupId:artifactId:version, followed by the classifier if the dependency has one.
+	Id    string
+	Type  string
+	Scope string
+	// The name of the dependency's file in the local repository, for example: slf4j-api-2.0.9.jar
+	FileName     string
+	Dependencies []*MavenTreeDependency
+}
+
+// ParseMavenDependencyTree parses a tree written by the dependency:tree goal in its default (text) output type.
+func ParseMavenDependencyTree(tree io.Reader) (*MavenTreeModule, error) {
+	scanner := bufio.NewScanner(tree)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var module *MavenTreeModule
+	// The last dependency parsed in each level of the tree, which is the parent of the dependencies in the next level.
+	var parents []*MavenTreeDependency
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		if line == "" {
+			continue
+		}
+		if module == nil {
+			match := mavenTreeRootRegex.FindStringSubmatch(line)
+			if match == nil {
+				return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("unexpected first line of a Maven dependency tree: %s", line))
+			}
+			module = &MavenTreeModule{Id: match[1] + ":" + match[2] + ":" + match[3]}
+			continue
+		}
+		match := mavenTreeDependencyRegex.FindStringSubmatch(line)
+		if match == nil {
+			return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("unexpected line in the Maven dependency tree of %s: %s", module.Id, line))
+		}
+		depth := len(match[1]) / 3
+		if depth > len(parents) {
+			return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("unexpected indentation in the Maven dependency tree of %s: %s", module.Id, line))
+		}
+		dependency, err := parseMavenTreeDependency(match[2])
+		if err != nil {
+			return nil, err
+		}
+		if depth == 0 {
+			module.Dependencies = append(module.Dependencies, dependency)
+		} else {
+			parents[depth-1].Dependencies = append(parents[depth-1].Dependencies, dependency)
+		}
+		parents = append(parents[:depth], dependency)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if module == nil {
+		return nil, utils.NewCategorizedError(utils.ParseFailure, errors.New("the Maven dependency tree is empty"))
+	}
+	return module, nil
+}
+
+// Parses the coordinates of a dependency in the tree, for example: org.slf4j:slf4j-api:jar:2.0.9:compile
+func parseMavenTreeDependency(coordinates string) (*MavenTreeDependency, error) {
+	match := mavenResolvedDependencyRegex.FindStringSubmatch(coordinates)
+	if match == nil {
+		return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("unexpected dependency in a Maven dependency tree: %s", coordinates))
+	}
+	groupId, artifactId, extension, classifier, version, scope := match[1], match[2], match[3], match[4], match[5], match[6]
+	dependency := &MavenTreeDependency{
+		Id:       groupId + ":" + artifactId + ":" + version,
+		Type:     extension,
+		Scope:    scope,
+		FileName: artifactId + "-" + version,
+	}
+	if classifier != "" {
+		dependency.Id += ":" + classifier
+		dependency.FileName += "-" + classifier
+	}
+	dependency.FileName += "." + extension
+	return dependency, nil
+}
+
+// GetMavenReactorModules returns the directories of the POM in pomPath and, recursively, of the modules declared in its <modules> section, in their declaration order.
+// The modules which are declared only in profiles aren't returned.
+func GetMavenReactorModules(pomPath string) ([]string, error) {
+	var moduleDirs []string
+	if err := readMavenReactorModules(pomPath, &moduleDirs); err != nil {
+		return nil, err
+	}
+	return moduleDirs, nil
+}
+
+func readMavenReactorModules(pomPath string, moduleDirs *[]string) error {
+	content, err := os.ReadFile(pomPath)
+	if err != nil {
+		return err
+	}
+	pom := &mavenPom{}
+	if err = xml.Unmarshal(content, pom); err != nil {
+		return utils.NewCategorizedError(utils.ParseFailure, errors.New("failed parsing "+pomPath+": "+err.Error()))
+	}
+	*moduleDirs = append(*moduleDirs, filepath.Dir(pomPath))
+	for _, module := range pom.Modules {
+		modulePath := filepath.Join(filepath.Dir(pomPath), filepath.FromSlash(strings.TrimSpace(module)))
+		if !strings.HasSuffix(modulePath, ".xml") {
+			modulePath = filepath.Join(modulePath, "pom.xml")
+		}
+		if err = readMavenReactorModules(modulePath, moduleDirs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunMavenDependencyTree runs 'mvn dependency:tree' with the provided arguments in the project's directory, and returns the trees of the modules in moduleDirs,
+// in their order. The arguments should select the modules in moduleDirs, for example with -pl. The modules which Maven skipped have no tree, and aren't returned.
+func RunMavenDependencyTree(mavenExecutable, projectDir string, args []string, moduleDirs []string) (modules []*MavenTreeModule, err error) {
+	for _, moduleDir := range moduleDirs {
+		// Remove the trees left by previous runs, so that skipped modules aren't collected.
+		if err = os.Remove(filepath.Join(moduleDir, filepath.FromSlash(mavenDependencyTreeFile))); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	command := exec.Command(mavenExecutable, append(slices.Clone(args), "dependency:tree", "-DoutputFile="+mavenDependencyTreeFile)...)
+	command.Dir = projectDir
+	output, errOutput, err := utils.RunCommand(command)
+	if err != nil {
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			err = fmt.Errorf("'mvn dependency:tree' failed: %w: %s", err, strings.TrimSpace(string(output)+"\n"+string(errOutput)))
+		}
+		return nil, err
+	}
+	for _, moduleDir := range moduleDirs {
+		module, err := readMavenDependencyTree(filepath.Join(moduleDir, filepath.FromSlash(mavenDependencyTreeFile)))
+		if err != nil {
+			return nil, err
+		}
+		if module != nil {
+			modules = append(modules, module)
+		}
+	}
+	return modules, nil
+}
+
+// Reads and removes a tree written by the dependency:tree goal. If the tree doesn't exist, nil is returned.
+func readMavenDependencyTree(treePath string) (module *MavenTreeModule, err error) {
+	treeFile, err := os.Open(treePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, treeFile.Close(), os.Remove(treePath))
+	}()
+	return ParseMavenDependencyTree(treeFile)
+}
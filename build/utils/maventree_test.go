@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMavenDependencyTree = `org.example:app:jar:1.0.0
++- org.slf4j:slf4j-api:jar:2.0.9:compile
++- io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final:runtime
+|  \- io.netty:netty-common:jar:4.1.100.Final:runtime
+\- junit:junit:jar:4.13.2:test
+   \- org.hamcrest:hamcrest-core:jar:1.3:test
+`
+
+func TestParseMavenDependencyTree(t *testing.T) {
+	module, err := ParseMavenDependencyTree(strings.NewReader(testMavenDependencyTree))
+	require.NoError(t, err)
+	assert.Equal(t, "org.example:app:1.0.0", module.Id)
+	require.Len(t, module.Dependencies, 3)
+	assert.Equal(t, &MavenTreeDependency{Id: "org.slf4j:slf4j-api:2.0.9", Type: "jar", Scope: "compile", FileName: "slf4j-api-2.0.9.jar"}, module.Dependencies[0])
+	netty := module.Dependencies[1]
+	assert.Equal(t, "io.netty:netty-transport-native-epoll:4.1.100.Final:linux-x86_64", netty.Id)
+	assert.Equal(t, "netty-transport-native-epoll-4.1.100.Final-linux-x86_64.jar", netty.FileName)
+	require.Len(t, netty.Dependencies, 1)
+	assert.Equal(t, "io.netty:netty-common:4.1.100.Final", netty.Dependencies[0].Id)
+	junit := module.Dependencies[2]
+	assert.Equal(t, "test", junit.Scope)
+	require.Len(t, junit.Dependencies, 1)
+	assert.Equal(t, "org.hamcrest:hamcrest-core:1.3", junit.Dependencies[0].Id)
+
+	_, err = ParseMavenDependencyTree(strings.NewReader(""))
+	assert.ErrorContains(t, err, "empty")
+	_, err = ParseMavenDependencyTree(strings.NewReader("org.example:app:jar:1.0.0\n|  \\- org.hamcrest:hamcrest-core:jar:1.3:test\n"))
+	assert.ErrorContains(t, err, "unexpected indentation")
+}
+
+func TestGetMavenReactorModules(t *testing.T) {
+	projectDir := t.TempDir()
+	writeTestPom(t, projectDir, "<modules><module>core</module><module>services</module></modules>")
+	writeTestPom(t, filepath.Join(projectDir, "core"), "")
+	writeTestPom(t, filepath.Join(projectDir, "services"), "<modules><module>api</module></modules>")
+	writeTestPom(t, filepath.Join(projectDir, "services", "api"), "")
+
+	moduleDirs, err := GetMavenReactorModules(filepath.Join(projectDir, "pom.xml"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{projectDir, filepath.Join(projectDir, "core"), filepath.Join(projectDir, "services"), filepath.Join(projectDir, "services", "api")}, moduleDirs)
+}
+
+func TestRunMavenDependencyTreeWithStubbedCommand(t *testing.T) {
+	projectDir := t.TempDir()
+	coreDir, skippedDir := filepath.Join(projectDir, "core"), filepath.Join(projectDir, "skipped")
+	// A tree left by a previous run of a module which Maven skips isn't collected.
+	require.NoError(t, os.MkdirAll(filepath.Join(skippedDir, "target"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skippedDir, filepath.FromSlash(mavenDependencyTreeFile)), []byte(testMavenDependencyTree), 0644))
+
+	var args []string
+	restore := utils.SetCommandRunner(utils.CommandRunnerFunc(func(command *exec.Cmd) ([]byte, []byte, error) {
+		args = command.Args[1:]
+		assert.Equal(t, projectDir, command.Dir)
+		treePath := filepath.Join(coreDir, filepath.FromSlash(mavenDependencyTreeFile))
+		require.NoError(t, os.MkdirAll(filepath.Dir(treePath), 0755))
+		return nil, nil, os.WriteFile(treePath, []byte(testMavenDependencyTree), 0644)
+	}))
+	defer restore()
+
+	modules, err := RunMavenDependencyTree("mvn", projectDir, []string{"-B", "-pl", "core,skipped"}, []string{coreDir, skippedDir})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-B", "-pl", "core,skipped", "dependency:tree", "-DoutputFile=" + mavenDependencyTreeFile}, args)
+	require.Len(t, modules, 1)
+	assert.Equal(t, "org.example:app:1.0.0", modules[0].Id)
+	// The trees are removed after they're read.
+	assert.NoFileExists(t, filepath.Join(coreDir, filepath.FromSlash(mavenDependencyTreeFile)))
+	assert.NoFileExists(t, filepath.Join(skippedDir, filepath.FromSlash(mavenDependencyTreeFile)))
+
+	// A failure of Maven is reported with its output.
+	utils.SetCommandRunner(utils.CommandRunnerFunc(func(command *exec.Cmd) ([]byte, []byte, error) {
+		return []byte("[ERROR] Could not find the selected project in the reactor: missing"), nil, &exec.ExitError{}
+	}))
+	_, err = RunMavenDependencyTree("mvn", projectDir, []string{"-pl", "missing"}, nil)
+	assert.ErrorContains(t, err, "Could not find the selected project")
+}
+
+func writeTestPom(t *testing.T, dir, modules string) {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	pom := "<project><groupId>org.example</groupId><artifactId>" + filepath.Base(dir) + "</artifactId><version>1.0.0</version>" + modules + "</project>"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(pom), 0644))
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The path of the Maven wrapper's properties file, relative to the project's root directory.
+var MavenWrapperPropertiesPath = filepath.Join(".mvn", "wrapper", "maven-wrapper.properties")
+
+// MavenWrapperProperties holds the distribution details declared in the maven-wrapper.properties of a project.
+type MavenWrapperProperties struct {
+	// The URL from which the wrapper downloads the Maven distribution, for example:
+	// https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/3.9.6/apache-maven-3.9.6-bin.zip
+	DistributionUrl string
+	// The SHA-256 checksum which the wrapper verifies the downloaded distribution by, if it's declared.
+	DistributionSha256Sum string
+	// The version of the wrapper, which the wrapper's script declares since its version 3.3.0.
+	WrapperVersion string
+}
+
+// DistributionFileName returns the name of the distribution's zip file, for example: apache-maven-3.9.6-bin.zip
+func (mwp *MavenWrapperProperties) DistributionFileName() string {
+	return getDistributionFileName(mwp.DistributionUrl)
+}
+
+// GetDistributionPath returns the path in which the wrapper stores the downloaded distribution's zip file.
+// The script-only wrapper, which is the default since the wrapper's version 3.3.0, deletes the zip file after extracting it.
+func (mwp *MavenWrapperProperties) GetDistributionPath(mavenUserHome string) string {
+	return getWrapperDistributionPath(mavenUserHome, mwp.DistributionUrl)
+}
+
+// ReadMavenWrapperProperties reads the maven-wrapper.properties of the Maven project in the provided directory.
+// If the project has no such file, nil is returned.
+func ReadMavenWrapperProperties(projectDir string) (*MavenWrapperProperties, error) {
+	content, err := os.ReadFile(filepath.Join(projectDir, MavenWrapperPropertiesPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	properties := parseJavaProperties(string(content))
+	return &MavenWrapperProperties{
+		DistributionUrl:       properties["distributionUrl"],
+		DistributionSha256Sum: properties["distributionSha256Sum"],
+		WrapperVersion:        properties["wrapperVersion"],
+	}, nil
+}
+
+// GetMavenHomeVersion returns the version of the Maven installation in the provided home directory, by the name of its maven-core jar,
+// for example: lib/maven-core-3.9.6.jar
+// An empty string is returned if the jar isn't found.
+func GetMavenHomeVersion(mavenHome string) (string, error) {
+	coreJars, err := filepath.Glob(filepath.Join(mavenHome, "lib", "maven-core-*.jar"))
+	if err != nil || len(coreJars) == 0 {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(filepath.Base(coreJars[0]), "maven-core-"), ".jar"), nil
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMavenWrapperProperties(t *testing.T) {
+	projectDir := t.TempDir()
+	wrapperProperties, err := ReadMavenWrapperProperties(projectDir)
+	require.NoError(t, err)
+	assert.Nil(t, wrapperProperties)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, ".mvn", "wrapper"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, MavenWrapperPropertiesPath), []byte(`# Licensed to the Apache Software Foundation (ASF)
+wrapperVersion=3.3.2
+distributionType=only-script
+distributionUrl=https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/3.9.6/apache-maven-3.9.6-bin.zip
+`), 0644))
+	wrapperProperties, err = ReadMavenWrapperProperties(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, &MavenWrapperProperties{
+		DistributionUrl: "https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/3.9.6/apache-maven-3.9.6-bin.zip",
+		WrapperVersion:  "3.3.2",
+	}, wrapperProperties)
+	assert.Equal(t, "apache-maven-3.9.6-bin.zip", wrapperProperties.DistributionFileName())
+}
+
+func TestGetMavenHomeVersion(t *testing.T) {
+	mavenHome := t.TempDir()
+	version, err := GetMavenHomeVersion(mavenHome)
+	require.NoError(t, err)
+	assert.Empty(t, version)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(mavenHome, "lib"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(mavenHome, "lib", "maven-core-3.9.6.jar"), []byte("jar"), 0644))
+	version, err = GetMavenHomeVersion(mavenHome)
+	require.NoError(t, err)
+	assert.Equal(t, "3.9.6", version)
+}
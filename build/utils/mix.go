@@ -0,0 +1,442 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jfrog/build-info-go/utils"
+)
+
+const (
+	MixProjectFileName = "mix.exs"
+	MixLockName        = "mix.lock"
+)
+
+// The Mix environments whose dependencies trees determine the scopes of the dependencies.
+var MixEnvironments = []string{"prod", "dev", "test"}
+
+// The types of the dependencies in a mix.lock.
+const (
+	HexMixDependency = "hex"
+	GitMixDependency = "git"
+)
+
+// MixLockDependency is a dependency locked in a mix.lock.
+type MixLockDependency struct {
+	// The dependency's application name, which is its key in the mix.lock.
+	Name string
+	// HexMixDependency or GitMixDependency.
+	Type string
+	// The package name of a Hex dependency, which may differ from its application name.
+	Package string
+	// The version of a Hex dependency, or the locked revision of a Git dependency.
+	Version string
+	// The Hex repository of a Hex dependency, for example: hexpm, or hexpm:<organization>. The URL of a Git dependency.
+	Repository string
+	// The branch of a Git dependency, when mix.exs requires the dependency by a branch rather than by a tag or a revision.
+	Branch string
+	// The SHA-256 checksum of the Hex package's tarball (the outer checksum), which mix.lock files written by Hex 0.20.6 and above have.
+	Sha256 string
+	// The SHA-256 checksum of the Hex package's contents (the inner checksum).
+	InnerChecksum string
+	// The names of the dependencies the dependency depends on.
+	Dependencies []string
+}
+
+// Id returns the ID of the dependency in the build-info: <name>:<version>
+func (mld *MixLockDependency) Id() string {
+	return mld.Name + ":" + mld.Version
+}
+
+// ReadMixLock reads and parses the mix.lock of the Mix project in the provided directory, and returns its dependencies mapped by their names.
+func ReadMixLock(projectDir string) (map[string]*MixLockDependency, error) {
+	content, err := os.ReadFile(filepath.Join(projectDir, MixLockName))
+	if err != nil {
+		return nil, err
+	}
+	return parseMixLock(string(content))
+}
+
+// A mix.lock is an Elixir map, from the names of the dependencies to tuples in one of the formats:
+// {:hex, :<package>, "<version>", "<inner checksum>", [<managers>], [<dependencies>], "<repository>", "<outer checksum>"}
+// {:git, "<url>", "<revision>", [<options>]}
+// Each of the dependencies of a Hex dependency is a tuple: {:<name>, "<requirement>", [<options>]}
+func parseMixLock(content string) (map[string]*MixLockDependency, error) {
+	parser := &elixirTermParser{input: content}
+	term, err := parser.parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", MixLockName, err)
+	}
+	entries, ok := term.(elixirMap)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse %s: the file isn't a map", MixLockName)
+	}
+	dependencies := make(map[string]*MixLockDependency)
+	for name, value := range entries {
+		tuple, ok := value.(elixirTuple)
+		if !ok || len(tuple) < 3 {
+			continue
+		}
+		dependency := &MixLockDependency{Name: name}
+		switch tuple[0] {
+		case elixirAtom(HexMixDependency):
+			dependency.Type = HexMixDependency
+			dependency.Package = termToString(tuple[1])
+			dependency.Version = termToString(tuple[2])
+			if len(tuple) > 3 {
+				dependency.InnerChecksum = termToString(tuple[3])
+			}
+			if len(tuple) > 5 {
+				dependency.Dependencies = getMixLockDependencyNames(tuple[5])
+			}
+			if len(tuple) > 6 {
+				dependency.Repository = termToString(tuple[6])
+			}
+			if len(tuple) > 7 {
+				dependency.Sha256 = termToString(tuple[7])
+			}
+		case elixirAtom(GitMixDependency):
+			dependency.Type = GitMixDependency
+			dependency.Repository = termToString(tuple[1])
+			dependency.Version = termToString(tuple[2])
+			if len(tuple) > 3 {
+				dependency.Branch = getElixirKeywordValue(tuple[3], "branch")
+			}
+		default:
+			continue
+		}
+		dependencies[name] = dependency
+	}
+	return dependencies, nil
+}
+
+func getMixLockDependencyNames(term any) (names []string) {
+	list, _ := term.([]any)
+	for _, item := range list {
+		if tuple, ok := item.(elixirTuple); ok && len(tuple) > 0 {
+			names = append(names, termToString(tuple[0]))
+		}
+	}
+	return
+}
+
+// Returns the string value of the item with the provided key in a keyword list term, or an empty string if there is no such item.
+func getElixirKeywordValue(term any, key string) string {
+	list, _ := term.([]any)
+	for _, item := range list {
+		if keyword, ok := item.(elixirKeyword); ok && keyword.Key == key {
+			return termToString(keyword.Value)
+		}
+	}
+	return ""
+}
+
+// Returns the value of a string or an atom term, or an empty string for the other terms.
+func termToString(term any) string {
+	switch value := term.(type) {
+	case string:
+		return value
+	case elixirAtom:
+		return string(value)
+	}
+	return ""
+}
+
+// Declarations of a mix.exs which describe the project.
+var (
+	mixAppRegex             = regexp.MustCompile(`\bapp:\s*:(\w+)`)
+	mixVersionRegex         = regexp.MustCompile(`\bversion:\s*(?:"([^"]+)"|@(\w+))`)
+	mixModuleAttributeRegex = `(?m)^\s*@%s\s+"([^"]+)"`
+)
+
+// ReadMixProjectId returns the ID of the Mix project in the provided directory, <app>:<version>, from the project function of its mix.exs.
+// The version may be a string, or a module attribute which is set to a string. Returns an empty string if the project's app isn't declared.
+func ReadMixProjectId(projectDir string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(projectDir, MixProjectFileName))
+	if err != nil {
+		return "", err
+	}
+	app := mixAppRegex.FindStringSubmatch(string(content))
+	if app == nil {
+		return "", nil
+	}
+	version := mixVersionRegex.FindStringSubmatch(string(content))
+	switch {
+	case version == nil:
+		return app[1], nil
+	case version[1] != "":
+		return app[1] + ":" + version[1], nil
+	}
+	attribute := regexp.MustCompile(fmt.Sprintf(mixModuleAttributeRegex, regexp.QuoteMeta(version[2]))).FindStringSubmatch(string(content))
+	if attribute == nil {
+		return app[1], nil
+	}
+	return app[1] + ":" + attribute[1], nil
+}
+
+// GetMixEnvironmentsDependencies runs 'mix deps.tree --format plain --only <environment>' for each of the MixEnvironments,
+// and returns the names of the dependencies in each environment's tree, and the names of the project's direct dependencies.
+func GetMixEnvironmentsDependencies(mixExecutable, projectDir string) (environmentsDependencies map[string][]string, directDependencies []string, err error) {
+	environmentsDependencies = make(map[string][]string)
+	for _, environment := range MixEnvironments {
+		command := exec.Command(mixExecutable, "deps.tree", "--format", "plain", "--only", environment)
+		command.Dir = projectDir
+		command.Env = append(os.Environ(), "MIX_ENV="+environment)
+		output, errOutput, err := utils.RunCommand(command)
+		if err != nil {
+			var exitError *exec.ExitError
+			if errors.As(err, &exitError) {
+				err = fmt.Errorf("'mix deps.tree --only %s' failed: %w: %s", environment, err, strings.TrimSpace(string(errOutput)))
+			}
+			return nil, nil, err
+		}
+		names, direct := parseMixDepsTree(string(output))
+		environmentsDependencies[environment] = names
+		for _, name := range direct {
+			if !containsString(directDependencies, name) {
+				directDependencies = append(directDependencies, name)
+			}
+		}
+	}
+	return environmentsDependencies, directDependencies, nil
+}
+
+// The prefix of each dependency in the plain and pretty formats of 'mix deps.tree': the tree's branches, whose width is 4 characters in each level.
+var mixDepsTreeLineRegex = regexp.MustCompile("^([ |`│├└─-]*)([a-z_][a-z0-9_]*)")
+
+// Parses the output of 'mix deps.tree', and returns the names of all the dependencies in the tree, and of the project's direct dependencies.
+// The first line of the tree is the project itself.
+func parseMixDepsTree(output string) (names, directDependencies []string) {
+	for i, line := range strings.Split(output, "\n") {
+		match := mixDepsTreeLineRegex.FindStringSubmatch(line)
+		if i == 0 || match == nil || match[1] == "" {
+			continue
+		}
+		if !containsString(names, match[2]) {
+			names = append(names, match[2])
+		}
+		if len([]rune(match[1])) == 4 && !containsString(directDependencies, match[2]) {
+			directDependencies = append(directDependencies, match[2])
+		}
+	}
+	return
+}
+
+func containsString(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
+
+// The Elixir terms of a mix.lock: maps with string keys, tuples, lists, keyword list items, atoms, strings, numbers and booleans.
+type (
+	elixirMap   map[string]any
+	elixirTuple []any
+	elixirAtom  string
+	// An item of a keyword list, such as 'hex: :cowlib'.
+	elixirKeyword struct {
+		Key   string
+		Value any
+	}
+)
+
+// A parser of the subset of the Elixir term syntax which mix.lock files use.
+type elixirTermParser struct {
+	input    string
+	position int
+}
+
+func (p *elixirTermParser) parse() (any, error) {
+	term, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpaces(); p.position < len(p.input) {
+		return nil, p.errorf("unexpected content")
+	}
+	return term, nil
+}
+
+func (p *elixirTermParser) parseTerm() (any, error) {
+	p.skipSpaces()
+	if p.position >= len(p.input) {
+		return nil, p.errorf("unexpected end of the input")
+	}
+	switch current := p.input[p.position]; {
+	case strings.HasPrefix(p.input[p.position:], "%{"):
+		p.position += 2
+		return p.parseMap()
+	case current == '{':
+		p.position++
+		items, err := p.parseSequence('}')
+		return elixirTuple(items), err
+	case current == '[':
+		p.position++
+		return p.parseSequence(']')
+	case current == '"':
+		return p.parseString()
+	case current == ':':
+		p.position++
+		if p.position < len(p.input) && p.input[p.position] == '"' {
+			value, err := p.parseString()
+			return elixirAtom(value), err
+		}
+		return elixirAtom(p.parseIdentifier()), nil
+	case current == '-' || unicode.IsDigit(rune(current)):
+		start := p.position
+		p.position++
+		for p.position < len(p.input) && strings.ContainsRune("0123456789._eE+-", rune(p.input[p.position])) {
+			p.position++
+		}
+		return strconv.ParseFloat(strings.ReplaceAll(p.input[start:p.position], "_", ""), 64)
+	default:
+		identifier := p.parseIdentifier()
+		if identifier == "" {
+			return nil, p.errorf("unexpected character '%c'", current)
+		}
+		// A keyword list item, whose key is followed by a colon and a space.
+		if strings.HasPrefix(p.input[p.position:], ": ") || strings.HasPrefix(p.input[p.position:], ":\n") {
+			p.position++
+			value, err := p.parseTerm()
+			return elixirKeyword{Key: identifier, Value: value}, err
+		}
+		switch identifier {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "nil":
+			return nil, nil
+		}
+		return elixirAtom(identifier), nil
+	}
+}
+
+// Parses the items of a tuple or a list, separated by commas, until the closing character. A trailing comma is allowed.
+func (p *elixirTermParser) parseSequence(closing byte) ([]any, error) {
+	items := []any{}
+	for {
+		if p.skipSpaces(); p.position < len(p.input) && p.input[p.position] == closing {
+			p.position++
+			return items, nil
+		}
+		item, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if err = p.skipSeparator(closing); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Parses the entries of a map with string keys, in the "key": value or the "key" => value syntax.
+func (p *elixirTermParser) parseMap() (elixirMap, error) {
+	entries := make(elixirMap)
+	for {
+		if p.skipSpaces(); p.position < len(p.input) && p.input[p.position] == '}' {
+			p.position++
+			return entries, nil
+		}
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		switch {
+		case strings.HasPrefix(p.input[p.position:], "=>"):
+			p.position += 2
+		case strings.HasPrefix(p.input[p.position:], ":"):
+			p.position++
+		default:
+			return nil, p.errorf("expected ':' or '=>' after the map key \"%s\"", key)
+		}
+		if entries[key], err = p.parseTerm(); err != nil {
+			return nil, err
+		}
+		if err = p.skipSeparator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *elixirTermParser) parseString() (string, error) {
+	if p.skipSpaces(); p.position >= len(p.input) || p.input[p.position] != '"' {
+		return "", p.errorf("expected a string")
+	}
+	var builder strings.Builder
+	for p.position++; p.position < len(p.input); p.position++ {
+		switch current := p.input[p.position]; current {
+		case '"':
+			p.position++
+			return builder.String(), nil
+		case '\\':
+			p.position++
+			if p.position < len(p.input) {
+				builder.WriteByte(p.input[p.position])
+			}
+		default:
+			builder.WriteByte(current)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *elixirTermParser) parseIdentifier() string {
+	start := p.position
+	for p.position < len(p.input) {
+		current := rune(p.input[p.position])
+		if !unicode.IsLetter(current) && !unicode.IsDigit(current) && current != '_' && current != '?' && current != '!' && current != '.' {
+			break
+		}
+		p.position++
+	}
+	return p.input[start:p.position]
+}
+
+// Skips the comma which follows an item, unless the item is followed by the closing character.
+func (p *elixirTermParser) skipSeparator(closing byte) error {
+	p.skipSpaces()
+	if p.position >= len(p.input) {
+		return p.errorf("unexpected end of the input")
+	}
+	switch p.input[p.position] {
+	case ',':
+		p.position++
+	case closing:
+	default:
+		return p.errorf("expected ',' or '%c'", closing)
+	}
+	return nil
+}
+
+// Skips the whitespace and the comments.
+func (p *elixirTermParser) skipSpaces() {
+	for p.position < len(p.input) {
+		switch current := p.input[p.position]; {
+		case current == '#':
+			for p.position < len(p.input) && p.input[p.position] != '\n' {
+				p.position++
+			}
+		case unicode.IsSpace(rune(current)):
+			p.position++
+		default:
+			return
+		}
+	}
+}
+
+func (p *elixirTermParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s at offset %d", fmt.Sprintf(format, args...), p.position)
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMixLock(t *testing.T) {
+	dependencies, err := parseMixLock(`%{
+  "castore": {:hex, :castore, "1.0.4", "ff4d0fb2e6411c0479b1d965a814ea6d00e51eb2f58697446e9c41a97d940b28", [:mix], [], "hexpm", "9418c1b8144e11656f0be99943db4caf04612e3eaecefb5dae9a2a87565584f8"},
+  "cowboy": {:hex, :cowboy, "2.10.0", "ff9ffeff91dae4ae270dd975642997afe2a1179d94b1887863e43f681a203e26", [:make, :rebar3], [{:cowlib, "2.12.1", [hex: :cowlib, repo: "hexpm", optional: false]}, {:ranch, "1.8.0", [hex: :ranch, repo: "hexpm", optional: false]}], "hexpm", "3afdccb7183cc6f143cb14d3cf51fa00e53db9ec80cdcd525482f5e99bc41d6b"},
+  "private_lib": {:hex, :private_lib, "0.2.0", "0c1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8", [:mix], [{:castore, ">= 0.0.0", [hex: :castore, repo: "hexpm", optional: true]}], "hexpm:acme"},
+  "phoenix": {:git, "https://github.com/phoenixframework/phoenix.git", "7b1e6e0d6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d", [tag: "v1.7.10"]},
+  "plug": {:git, "https://github.com/elixir-plug/plug.git", "1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d", [branch: "main"]},
+  # A dependency of an unknown type is skipped.
+  "local": {:unknown, "1.0.0", []},
+}
+`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*MixLockDependency{
+		"castore": {
+			Name:          "castore",
+			Type:          HexMixDependency,
+			Package:       "castore",
+			Version:       "1.0.4",
+			Repository:    "hexpm",
+			Sha256:        "9418c1b8144e11656f0be99943db4caf04612e3eaecefb5dae9a2a87565584f8",
+			InnerChecksum: "ff4d0fb2e6411c0479b1d965a814ea6d00e51eb2f58697446e9c41a97d940b28",
+		},
+		"cowboy": {
+			Name:          "cowboy",
+			Type:          HexMixDependency,
+			Package:       "cowboy",
+			Version:       "2.10.0",
+			Repository:    "hexpm",
+			Sha256:        "3afdccb7183cc6f143cb14d3cf51fa00e53db9ec80cdcd525482f5e99bc41d6b",
+			InnerChecksum: "ff9ffeff91dae4ae270dd975642997afe2a1179d94b1887863e43f681a203e26",
+			Dependencies:  []string{"cowlib", "ranch"},
+		},
+		// A lockfile written by an old Hex version doesn't have the outer checksum.
+		"private_lib": {
+			Name:          "private_lib",
+			Type:          HexMixDependency,
+			Package:       "private_lib",
+			Version:       "0.2.0",
+			Repository:    "hexpm:acme",
+			InnerChecksum: "0c1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8",
+			Dependencies:  []string{"castore"},
+		},
+		"phoenix": {
+			Name:       "phoenix",
+			Type:       GitMixDependency,
+			Version:    "7b1e6e0d6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d",
+			Repository: "https://github.com/phoenixframework/phoenix.git",
+		},
+		"plug": {
+			Name:       "plug",
+			Type:       GitMixDependency,
+			Version:    "1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d",
+			Repository: "https://github.com/elixir-plug/plug.git",
+			Branch:     "main",
+		},
+	}, dependencies)
+	assert.Equal(t, "cowboy:2.10.0", dependencies["cowboy"].Id())
+
+	// The map syntax with arrows.
+	dependencies, err = parseMixLock(`%{"jason" => {:hex, :jason, "1.4.1", "af1504e35f629ddcdd6addb3513c3853991f694921b1b9368b0bd32beb9f1b63", [:mix], [], "hexpm", "fbb01ecdfd565b56261302f7e1fcc27c4fb8f32d56eab74db621fc154604a7a1"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "jason:1.4.1", dependencies["jason"].Id())
+
+	_, err = parseMixLock(`%{"jason": {:hex, :jason, "1.4.1"`)
+	assert.ErrorContains(t, err, "failed to parse mix.lock")
+	_, err = parseMixLock(`["jason"]`)
+	assert.ErrorContains(t, err, "the file isn't a map")
+}
+
+func TestReadMixProjectId(t *testing.T) {
+	testCases := []struct {
+		name       string
+		mixExs     string
+		expectedId string
+	}{
+		{"version", `
+defmodule MyApp.MixProject do
+  use Mix.Project
+
+  def project do
+    [
+      app: :my_app,
+      version: "0.1.0",
+      deps: deps()
+    ]
+  end
+end
+`, "my_app:0.1.0"},
+		{"moduleAttribute", `
+defmodule MyApp.MixProject do
+  use Mix.Project
+
+  @version "2.3.1"
+
+  def project do
+    [app: :my_app, version: @version]
+  end
+end
+`, "my_app:2.3.1"},
+		{"noVersion", "defmodule MyApp.MixProject do\n  def project, do: [app: :my_app]\nend\n", "my_app"},
+		{"noApp", "defmodule MyUmbrella.MixProject do\n  def project, do: [apps_path: \"apps\"]\nend\n", ""},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(projectDir, MixProjectFileName), []byte(testCase.mixExs), 0644))
+			projectId, err := ReadMixProjectId(projectDir)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedId, projectId)
+		})
+	}
+}
+
+func TestParseMixDepsTree(t *testing.T) {
+	// The plain format.
+	names, directDependencies := parseMixDepsTree("my_app\n" +
+		"|-- jason ~> 1.4 (Hex package)\n" +
+		"`-- plug_cowboy ~> 2.6 (Hex package)\n" +
+		"    |-- cowboy ~> 2.7 (Hex package)\n" +
+		"    |   `-- cowlib 2.12.1 (Hex package)\n" +
+		"    `-- plug ~> 1.14 (Hex package)\n" +
+		"        `-- jason ~> 1.0 (Hex package)\n")
+	assert.Equal(t, []string{"jason", "plug_cowboy", "cowboy", "cowlib", "plug"}, names)
+	assert.Equal(t, []string{"jason", "plug_cowboy"}, directDependencies)
+
+	// The pretty format.
+	names, directDependencies = parseMixDepsTree("my_app\n" +
+		"├── jason ~> 1.4 (Hex package)\n" +
+		"└── plug ~> 1.14 (Hex package)\n" +
+		"    └── mime ~> 1.0 or ~> 2.0 (Hex package)\n")
+	assert.Equal(t, []string{"jason", "plug", "mime"}, names)
+	assert.Equal(t, []string{"jason", "plug"}, directDependencies)
+}
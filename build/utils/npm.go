@@ -1,15 +1,16 @@
 package utils
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/jfrog/gofrog/crypto"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"golang.org/x/exp/slices"
 
@@ -31,17 +32,20 @@ func CalculateNpmDependenciesList(executablePath, srcPath, moduleId string, npmP
 	if err != nil {
 		return nil, err
 	}
-	var cacache *cacache
+	var checksums *npmChecksumsCalculator
 	if calculateChecksums {
-		// Get local npm cache.
-		cacheLocation, err := GetNpmConfigCache(srcPath, executablePath, npmParams.Args, log)
-		if err != nil {
+		if checksums, err = newNpmChecksumsCalculator(executablePath, srcPath, npmParams, log); err != nil {
 			return nil, err
 		}
-		cacache = NewNpmCacache(cacheLocation)
 	}
+	return toDependenciesList(dependenciesMap, checksums, npmParams.IntegrityVerification, log)
+}
+
+// Converts the dependencies map to a list of build-info dependencies. The checksums are calculated if checksums isn't nil.
+func toDependenciesList(dependenciesMap map[string]*dependencyInfo, checksums *npmChecksumsCalculator, integrityVerification utils.IntegrityVerificationMode, log utils.Log) ([]entities.Dependency, error) {
 	var dependenciesList []entities.Dependency
 	var missingPeerDeps, missingBundledDeps, missingOptionalDeps, otherMissingDeps []string
+	var mismatches []utils.IntegrityMismatchDetails
 	for _, dep := range dependenciesMap {
 		if dep.npmLsDependency.Integrity == "" && dep.npmLsDependency.InBundle {
 			missingBundledDeps = append(missingBundledDeps, dep.Id)
@@ -51,9 +55,9 @@ func CalculateNpmDependenciesList(executablePath, srcPath, moduleId string, npmP
 			missingPeerDeps = append(missingPeerDeps, dep.Id)
 			continue
 		}
-		if calculateChecksums {
-			dep.Md5, dep.Sha1, dep.Sha256, err = calculateChecksum(cacache, dep.Name, dep.Version, dep.Integrity)
-			if err != nil {
+		if checksums != nil {
+			result := checksums.calculate(dep)
+			if result.err != nil {
 				if dep.Optional {
 					missingOptionalDeps = append(missingOptionalDeps, dep.Id)
 					continue
@@ -63,9 +67,18 @@ func CalculateNpmDependenciesList(executablePath, srcPath, moduleId string, npmP
 				// Seems like the compatibility upgrades may result in dependencies losing their integrity.
 				// We use the integrity to get the dependencies tarball
 				otherMissingDeps = append(otherMissingDeps, dep.Id)
-				log.Debug("couldn't calculate checksum for " + dep.Id + ". Error: '" + err.Error() + "'.")
+				log.Debug("couldn't calculate checksum for " + dep.Id + ". Error: '" + result.err.Error() + "'.")
 				continue
 			}
+			dep.Checksum = result.checksum
+			if integrityVerification != utils.IntegrityVerificationOff && dep.Integrity != "" {
+				if result.integrityErr != nil {
+					return nil, result.integrityErr
+				}
+				if result.actualIntegrity != dep.Integrity {
+					mismatches = append(mismatches, utils.IntegrityMismatchDetails{DependencyId: dep.Id, Lockfile: "package-lock.json", Expected: dep.Integrity, Actual: result.actualIntegrity})
+				}
+			}
 		}
 
 		dependenciesList = append(dependenciesList, dep.Dependency)
@@ -82,9 +95,61 @@ func CalculateNpmDependenciesList(executablePath, srcPath, moduleId string, npmP
 	if len(otherMissingDeps) > 0 {
 		log.Warn("The following dependencies will not be included in the build-info, because they are missing in the npm cache: '" + strings.Join(otherMissingDeps, ",") + "'.\nHint: Try deleting 'node_modules' and/or 'package-lock.json'.")
 	}
+	if err := integrityVerification.HandleMismatches(mismatches, log); err != nil {
+		return nil, err
+	}
 	return dependenciesList, nil
 }
 
+// Calculates the checksums of the dependencies' tarballs in the local npm cache, once per dependency,
+// so that the dependencies shared by several workspaces are hashed once. Safe for concurrent use.
+type npmChecksumsCalculator struct {
+	cacache               *cacache
+	integrityVerification utils.IntegrityVerificationMode
+	// The results, mapped by the dependencies' IDs.
+	results sync.Map
+}
+
+type npmChecksumsResult struct {
+	once     sync.Once
+	checksum entities.Checksum
+	err      error
+	// The integrity of the tarball in the cache, calculated if the integrity is verified.
+	actualIntegrity string
+	integrityErr    error
+}
+
+func newNpmChecksumsCalculator(executablePath, srcPath string, npmParams NpmTreeDepListParam, log utils.Log) (*npmChecksumsCalculator, error) {
+	// Get local npm cache.
+	var cacheLocation string
+	var err error
+	if npmParams.Offline {
+		cacheLocation, err = getDefaultNpmCache()
+	} else {
+		cacheLocation, err = GetNpmConfigCache(srcPath, executablePath, npmParams.Args, log)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &npmChecksumsCalculator{cacache: NewNpmCacache(cacheLocation), integrityVerification: npmParams.IntegrityVerification}, nil
+}
+
+func (ncc *npmChecksumsCalculator) calculate(dep *dependencyInfo) *npmChecksumsResult {
+	value, _ := ncc.results.LoadOrStore(dep.Id, &npmChecksumsResult{})
+	result := value.(*npmChecksumsResult)
+	result.once.Do(func() {
+		var md5, sha1, sha256 string
+		if md5, sha1, sha256, result.err = calculateChecksum(ncc.cacache, dep.Name, dep.Version, dep.Integrity); result.err != nil {
+			return
+		}
+		result.checksum = entities.Checksum{Md5: md5, Sha1: sha1, Sha256: sha256}
+		if ncc.integrityVerification != utils.IntegrityVerificationOff && dep.Integrity != "" {
+			result.actualIntegrity, result.integrityErr = ncc.cacache.CalcTarballIntegrity(dep.Integrity)
+		}
+	})
+	return result
+}
+
 type dependencyInfo struct {
 	entities.Dependency
 	*npmLsDependency
@@ -93,6 +158,9 @@ type dependencyInfo struct {
 // Run 'npm list ...' command and parse the returned result to create a dependencies map of.
 // The dependencies map looks like name:version -> entities.Dependency.
 func CalculateDependenciesMap(executablePath, srcPath, moduleId string, npmListParams NpmTreeDepListParam, log utils.Log, skipInstall bool) (map[string]*dependencyInfo, error) {
+	if npmListParams.Offline {
+		return CalculateDependenciesMapFromLockfile(srcPath, moduleId, log)
+	}
 	dependenciesMap := make(map[string]*dependencyInfo)
 	// These arguments must be added at the end of the command, to override their other values (if existed in nm.npmArgs).
 	npmVersion, err := GetNpmVersion(executablePath, log)
@@ -103,30 +171,40 @@ func CalculateDependenciesMap(executablePath, srcPath, moduleId string, npmListP
 	if err != nil {
 		return nil, err
 	}
-	var data []byte
+	lsArgs := append(slices.Clone(npmListParams.Args), "--json", "--all", "--long")
+	resolutionSource := entities.CliTreeSource
 	// When `skipInstall` is true, we aim to rely on the dependencies specified in the package-lock, so Frogbot will not execute 'npm ls' on modules that are unbuilt and lack lock files (which may still have incomplete node_modules that could cause errors).
-	if nodeModulesExist && !npmListParams.IgnoreNodeModules && !skipInstall {
-		data = runNpmLsWithNodeModules(executablePath, srcPath, npmListParams.Args, log)
-	} else {
+	if !nodeModulesExist || npmListParams.IgnoreNodeModules || skipInstall {
 		// If we don't have node_modules, the function will use the package-lock dependencies.
-		data, err = runNpmLsWithoutNodeModules(executablePath, srcPath, npmListParams, log, npmVersion, skipInstall)
-		if err != nil {
+		if err = prepareNpmLsWithoutNodeModules(executablePath, srcPath, npmListParams, log, npmVersion, skipInstall); err != nil {
 			return nil, err
 		}
+		lsArgs = append(lsArgs, "--package-lock-only")
+		resolutionSource = entities.LockfileSource
 	}
 	parseFunc := parseNpmLsDependencyFunc(npmVersion)
-	// Parse the dependencies json object.
-	return dependenciesMap, jsonparser.ObjectEach(data, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) (err error) {
-		if string(key) == "dependencies" {
-			err = parseDependencies(value, []string{moduleId}, dependenciesMap, parseFunc, log)
-		}
-		return err
-	})
+	if npmListParams.LowMemory {
+		err = streamNpmLs(executablePath, srcPath, lsArgs, func(output io.Reader) error {
+			return parseNpmLsStream(output, []string{"dependencies"}, []string{moduleId}, dependenciesMap, parseFunc, log)
+		}, log)
+	} else {
+		data := runNpmLs(executablePath, srcPath, lsArgs, log)
+		// Parse the dependencies json object.
+		err = jsonparser.ObjectEach(data, func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) (err error) {
+			if string(key) == "dependencies" {
+				err = parseDependencies(value, []string{moduleId}, dependenciesMap, parseFunc, log)
+			}
+			return err
+		})
+	}
+	for _, dependency := range dependenciesMap {
+		dependency.SetResolutionSource(resolutionSource)
+	}
+	return dependenciesMap, err
 }
 
-func runNpmLsWithNodeModules(executablePath, srcPath string, npmArgs []string, log utils.Log) (data []byte) {
-	npmArgs = append(npmArgs, "--json", "--all", "--long")
-	data, errData, err := RunNpmCmd(executablePath, srcPath, AppendNpmCommand(npmArgs, "ls"), log)
+func runNpmLs(executablePath, srcPath string, lsArgs []string, log utils.Log) (data []byte) {
+	data, errData, err := RunNpmCmd(executablePath, srcPath, AppendNpmCommand(lsArgs, "ls"), log)
 	if err != nil {
 		// It is optional for the function to return this error.
 		log.Warn(err.Error())
@@ -136,26 +214,13 @@ func runNpmLsWithNodeModules(executablePath, srcPath string, npmArgs []string, l
 	return
 }
 
-func runNpmLsWithoutNodeModules(executablePath, srcPath string, npmListParams NpmTreeDepListParam, log utils.Log, npmVersion *version.Version, skipInstall bool) ([]byte, error) {
+// Generates the package-lock.json, which 'npm ls --package-lock-only' reads, if it's missing or outdated.
+func prepareNpmLsWithoutNodeModules(executablePath, srcPath string, npmListParams NpmTreeDepListParam, log utils.Log, npmVersion *version.Version, skipInstall bool) error {
 	installRequired, err := isInstallRequired(srcPath, npmListParams, log, skipInstall)
-	if err != nil {
-		return nil, err
-	}
-
-	if installRequired {
-		err = installPackageLock(executablePath, srcPath, npmListParams.InstallCommandArgs, npmListParams.Args, log, npmVersion)
-		if err != nil {
-			return nil, err
-		}
-	}
-	npmListParams.Args = append(npmListParams.Args, "--json", "--all", "--long", "--package-lock-only")
-	data, errData, err := RunNpmCmd(executablePath, srcPath, AppendNpmCommand(npmListParams.Args, "ls"), log)
-	if err != nil {
-		log.Warn(err.Error())
-	} else if len(errData) > 0 {
-		log.Warn("Encountered some issues while running 'npm ls' command:\n" + strings.TrimSpace(string(errData)))
+	if err != nil || !installRequired {
+		return err
 	}
-	return data, nil
+	return installPackageLock(executablePath, srcPath, npmListParams.InstallCommandArgs, npmListParams.Args, log, npmVersion)
 }
 
 // This function determines whether a project installation is required by evaluating the following criteria:
@@ -251,6 +316,13 @@ type NpmTreeDepListParam struct {
 	IgnoreNodeModules bool
 	// Rewrite package-lock.json, if exists.
 	OverwritePackageLock bool
+	// Verify the tarballs in the npm cache against the integrity declared in package-lock.json. Requires calculating the checksums.
+	IntegrityVerification utils.IntegrityVerificationMode
+	// Build the dependencies tree directly from the lockfile, without running npm. See CalculateDependenciesMapFromLockfile.
+	Offline bool
+	// Parse the output of 'npm ls' while it's written, rather than reading it to memory, and collect the workspaces one at a time.
+	// Intended for environments with limited memory, such as CI containers, at the cost of a slower collection.
+	LowMemory bool
 }
 
 // npm >=7 ls results for a single dependency
@@ -258,9 +330,11 @@ type npmLsDependency struct {
 	Name      string
 	Version   string
 	Integrity string
-	InBundle  bool
-	Dev       bool
-	Optional  bool
+	// The source the dependency was installed from, for example: file:../utils or https://registry.npmjs.org/ms/-/ms-2.1.3.tgz
+	Resolved string
+	InBundle bool
+	Dev      bool
+	Optional bool
 	// Missing peer dependency in npm version 7/8
 	Missing bool
 	// Problems with missing peer dependency in npm version 7/8
@@ -276,6 +350,7 @@ type legacyNpmLsDependency struct {
 	Version       string
 	Missing       bool
 	Integrity     string `json:"_integrity,omitempty"`
+	Resolved      string `json:"_resolved,omitempty"`
 	InBundle      bool   `json:"_inBundle,omitempty"`
 	Dev           bool   `json:"_development,omitempty"`
 	InnerOptional bool   `json:"_optional,omitempty"`
@@ -295,6 +370,7 @@ func (lnld *legacyNpmLsDependency) toNpmLsDependency() *npmLsDependency {
 		Name:        lnld.Name,
 		Version:     lnld.Version,
 		Integrity:   lnld.Integrity,
+		Resolved:    lnld.Resolved,
 		InBundle:    lnld.InBundle,
 		Dev:         lnld.Dev,
 		Optional:    lnld.optional(),
@@ -308,6 +384,16 @@ func (nld *npmLsDependency) id() string {
 	return nld.Name + ":" + nld.Version
 }
 
+// Returns true if the dependency's package belongs to a scope, for example: @company/utils
+func (nld *npmLsDependency) isScoped() bool {
+	return GetNpmPackageScope(nld.Name) != ""
+}
+
+// Returns true if the dependency is installed from a local directory or tarball, whose content may change without a change to its version.
+func (nld *npmLsDependency) isLocal() bool {
+	return strings.HasPrefix(nld.Resolved, "file:") || strings.HasPrefix(nld.Resolved, "link:")
+}
+
 func (nld *npmLsDependency) getScopes() (scopes []string) {
 	if nld.Dev {
 		scopes = append(scopes, "dev")
@@ -335,15 +421,9 @@ func parseDependencies(data []byte, pathToRoot []string, dependencies map[string
 		if err != nil {
 			return err
 		}
-		if npmLsDependency.Version == "" {
-			if npmLsDependency.Missing || npmLsDependency.Problems != nil {
-				// Skip missing peer dependency.
-				log.Debug(fmt.Sprintf("%s is missing, this may be the result of an peer dependency.", key))
-				return nil
-			}
-			return errors.New("failed to parse '" + string(value) + "' from npm ls output.")
+		if added, err := addNpmLsDependency(string(key), npmLsDependency, pathToRoot, dependencies, log); err != nil || !added {
+			return err
 		}
-		appendDependency(dependencies, npmLsDependency, pathToRoot)
 		transitive, _, _, err := jsonparser.Get(value, "dependencies")
 		if err != nil && err.Error() != "Key path not found" {
 			return err
@@ -357,6 +437,20 @@ func parseDependencies(data []byte, pathToRoot []string, dependencies map[string
 	})
 }
 
+// Adds a dependency parsed from the npm ls output to the dependencies map. Returns false if the dependency is skipped, since it's a missing peer dependency.
+func addNpmLsDependency(key string, npmLsDependency *npmLsDependency, pathToRoot []string, dependencies map[string]*dependencyInfo, log utils.Log) (bool, error) {
+	if npmLsDependency.Version == "" {
+		if npmLsDependency.Missing || npmLsDependency.Problems != nil {
+			// Skip missing peer dependency.
+			log.Debug(fmt.Sprintf("%s is missing, this may be the result of an peer dependency.", key))
+			return false, nil
+		}
+		return false, utils.NewCategorizedError(utils.ParseFailure, errors.New("failed to parse the dependency '"+key+"' from npm ls output."))
+	}
+	appendDependency(dependencies, npmLsDependency, pathToRoot)
+	return true, nil
+}
+
 func parseNpmLsDependencyFunc(npmVersion *version.Version) func(data []byte) (*npmLsDependency, error) {
 	// If npm older than v7, use legacy struct for npm ls output.
 	if npmVersion.Compare("7.0.0") > 0 {
@@ -390,6 +484,17 @@ func appendDependency(dependencies map[string]*dependencyInfo, dep *npmLsDepende
 
 		dependencies[depId] = dependency
 	}
+	if dep.isLocal() {
+		dependencies[depId].SetMutable()
+	}
+	if dep.isScoped() {
+		if registry := getNpmRegistryFromTarballUrl(dep.Name, dep.Resolved); registry != "" {
+			if dependencies[depId].Properties == nil {
+				dependencies[depId].Properties = make(map[string]string)
+			}
+			dependencies[depId].Properties[NpmRegistryProperty] = registry
+		}
+	}
 	if dependencies[depId].Integrity == "" {
 		dependencies[depId].Integrity = dep.Integrity
 	}
@@ -445,13 +550,7 @@ func RunNpmCmd(executablePath, srcPath string, npmArgs []string, log utils.Log)
 	log.Debug("Running 'npm " + strings.Join(npmArgs, " ") + "' command.")
 	command := exec.Command(executablePath, args...)
 	command.Dir = srcPath
-	outBuffer := bytes.NewBuffer([]byte{})
-	command.Stdout = outBuffer
-	errBuffer := bytes.NewBuffer([]byte{})
-	command.Stderr = errBuffer
-	err = command.Run()
-	errResult = errBuffer.Bytes()
-	stdResult = outBuffer.Bytes()
+	stdResult, errResult, err = utils.RunCommand(command)
 	if err != nil {
 		err = fmt.Errorf("error while running '%s %s': %s\n%s", executablePath, strings.Join(args, " "), err.Error(), strings.TrimSpace(string(errResult)))
 		return
@@ -579,13 +678,48 @@ func GetNpmConfigCache(srcPath, executablePath string, npmArgs []string, log uti
 		// Some warnings and messages of npm are printed to stderr. They don't cause the command to fail, but we'd want to show them to the user.
 		log.Warn("Encountered some issues while running 'npm get cache' command:\n" + string(errData))
 	}
-	cachePath := filepath.Join(strings.Trim(string(data), "\n"), "_cacache")
+	return getCacacheDir(strings.Trim(string(data), "\n"))
+}
+
+// Returns the npm cache path without running npm: the value of the npm_config_cache environment variable if it's set, or npm's default otherwise.
+// The cache configured in .npmrc files isn't read.
+func getDefaultNpmCache() (string, error) {
+	cacheDir, err := getDefaultNpmCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return getCacacheDir(cacheDir)
+}
+
+// Returns the directory of the npm cache without running npm, which contains its _cacache directory if it was populated.
+func getDefaultNpmCacheDir() (string, error) {
+	cacheDir := os.Getenv("npm_config_cache")
+	if cacheDir == "" {
+		cacheDir = os.Getenv("NPM_CONFIG_CACHE")
+	}
+	if cacheDir == "" {
+		if utils.IsWindows() {
+			cacheDir = filepath.Join(os.Getenv("LocalAppData"), "npm-cache")
+		} else {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			cacheDir = filepath.Join(home, ".npm")
+		}
+	}
+	return cacheDir, nil
+}
+
+// Returns the _cacache directory of the npm cache, or a CacheMiss error if it doesn't exist.
+func getCacacheDir(cacheDir string) (string, error) {
+	cachePath := filepath.Join(cacheDir, "_cacache")
 	found, err := utils.IsDirExists(cachePath, true)
 	if err != nil {
 		return "", err
 	}
 	if !found {
-		return "", errors.New("_cacache folder is not found in '" + cachePath + "'. Hint: Delete node_modules directory and run npm install or npm ci.")
+		return "", utils.NewCategorizedError(utils.CacheMiss, errors.New("_cacache folder is not found in '"+cachePath+"'. Hint: Delete node_modules directory and run npm install or npm ci."))
 	}
 	return cachePath, nil
 }
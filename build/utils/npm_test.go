@@ -151,6 +151,22 @@ func TestParseDependencies(t *testing.T) {
 	}
 }
 
+func TestParseLocalDependencies(t *testing.T) {
+	dependencies := make(map[string]*dependencyInfo)
+	err := parseDependencies([]byte(`{
+  "utils": {"name": "utils", "version": "1.0.0", "resolved": "file:../utils"},
+  "lodash": {"name": "lodash", "version": "4.17.21", "resolved": "https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz", "integrity": "sha512-v2kDEe57lecTulaDIuNTPy3Ry4gLGJ6Z1O3vE1krgXZNrsQ+LFTGHVxVjcXPs17LhbZVGedAJv8XZ1tvj5FvSg=="}
+}`), []string{"root"}, dependencies, npmLsDependencyParser, utils.NewDefaultLogger(utils.INFO))
+	require.NoError(t, err)
+	assert.True(t, dependencies["utils:1.0.0"].IsMutable())
+	assert.False(t, dependencies["lodash:4.17.21"].IsMutable())
+
+	// npm 6 reports the source in the _resolved field.
+	legacyDependency, err := legacyNpmLsDependencyParser([]byte(`{"version": "1.0.0", "_resolved": "file:../utils"}`))
+	require.NoError(t, err)
+	assert.True(t, legacyDependency.isLocal())
+}
+
 func TestAppendScopes(t *testing.T) {
 	var scopes = []struct {
 		a        []string
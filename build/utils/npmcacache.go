@@ -1,12 +1,18 @@
 package utils
 
 import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/gofrog/crypto"
+	"hash"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 )
@@ -46,11 +52,46 @@ func (c *cacache) GetTarball(integrity string) (string, error) {
 		return "", err
 	}
 	if !found {
-		return "", errors.New("failed to locate dependency integrity '" + integrity + "' tarball at " + tarballPath)
+		return "", utils.NewCategorizedError(utils.CacheMiss, errors.New("failed to locate dependency integrity '"+integrity+"' tarball at "+tarballPath))
 	}
 	return tarballPath, nil
 }
 
+// CalcTarballIntegrity calculates the digest of the tarball stored in the cache under the supplied integrity,
+// and returns it in the integrity format, using the same hash algorithm.
+// If the cache content is intact, the returned integrity is equal to the supplied one.
+func (c *cacache) CalcTarballIntegrity(integrity string) (actualIntegrity string, err error) {
+	tarballPath, err := c.GetTarball(integrity)
+	if err != nil {
+		return "", err
+	}
+	hashAlgorithm, _, _ := strings.Cut(integrity, "-")
+	var digest hash.Hash
+	switch hashAlgorithm {
+	case "sha512":
+		digest = sha512.New()
+	case "sha384":
+		digest = sha512.New384()
+	case "sha256":
+		digest = sha256.New()
+	case "sha1":
+		digest = sha1.New()
+	default:
+		return "", errors.New("the integrity '" + integrity + "' uses an unsupported hash algorithm")
+	}
+	tarball, err := os.Open(tarballPath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		err = errors.Join(err, tarball.Close())
+	}()
+	if _, err = io.Copy(digest, tarball); err != nil {
+		return "", err
+	}
+	return hashAlgorithm + "-" + base64.StdEncoding.EncodeToString(digest.Sum(nil)), nil
+}
+
 // Integrity string has the pattern of: hashAlgorithms-digest.
 // Return the hashAlgorithm and transform the diegest to the actual sha.
 func integrityToSha(integrity string) (hashAlgorithm string, sha string, err error) {
@@ -120,7 +161,7 @@ func (c *cacache) getIndexByHash(hash string) (string, error) {
 		return "", err
 	}
 	if !found {
-		return "", errors.New(hash + " is not found in " + path)
+		return "", utils.NewCategorizedError(utils.CacheMiss, errors.New(hash+" is not found in "+path))
 	}
 	return path, nil
 }
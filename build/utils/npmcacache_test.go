@@ -1,11 +1,13 @@
 package utils
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIntegrityToSha(t *testing.T) {
@@ -37,3 +39,21 @@ func TestGetDepInfo(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "sha512-bY6fj56OUQ0hU1KjFNDQuJFezqKdrAyFdIevADiqrWHwSlbmBNMHp5ak2f40Pm8JTFyM2mqxkG6ngkHO11f/lg==", info.Integrity)
 }
+
+func TestCalcTarballIntegrity(t *testing.T) {
+	integrity := "sha512-dWe4nWO/ruEOY7HkUJ5gFt1DCFV9zPRoJr8pV0/ASQermOZjtq8jMjOprC0Kd10GLN+l7xaUPvxzJFWtxGu8Fg=="
+	cacache := NewNpmCacache(filepath.Join("..", "testdata", "npm", "_cacache"))
+	actualIntegrity, err := cacache.CalcTarballIntegrity(integrity)
+	assert.NoError(t, err)
+	assert.Equal(t, integrity, actualIntegrity)
+
+	// Replace the tarball's content, as in a poisoned cache.
+	poisonedCachePath := t.TempDir()
+	tarballPath := filepath.Join(poisonedCachePath, "content-v2", "sha512", "75", "67", "b89d63bfaee10e63b1e4509e6016dd4308557dccf46826bf29574fc04907ab98e663b6af233233a9ac2d0a775d062cdfa5ef16943efc732455adc46bbc16")
+	require.NoError(t, os.MkdirAll(filepath.Dir(tarballPath), 0755))
+	require.NoError(t, os.WriteFile(tarballPath, []byte("poisoned"), 0644))
+	actualIntegrity, err = NewNpmCacache(poisonedCachePath).CalcTarballIntegrity(integrity)
+	assert.NoError(t, err)
+	assert.NotEqual(t, integrity, actualIntegrity)
+	assert.True(t, strings.HasPrefix(actualIntegrity, "sha512-"))
+}
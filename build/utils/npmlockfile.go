@@ -0,0 +1,279 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	npmLockfileName = "package-lock.json"
+	// The hidden lockfile, which npm 7 and above writes to node_modules, and which reflects the installed tree.
+	npmHiddenLockfilePath = "node_modules/.package-lock.json"
+	nodeModulesDir        = "node_modules"
+)
+
+// The content of a package-lock.json file of version 2 or 3. Version 1 lockfiles don't have the packages map.
+type npmLockfile struct {
+	LockfileVersion int `json:"lockfileVersion"`
+	// The packages of the tree, mapped by their locations relative to the project's root, for example: node_modules/debug
+	// The root project's location is an empty string.
+	Packages map[string]*npmLockfilePackage `json:"packages"`
+	// The root directory of the project.
+	srcPath string
+}
+
+type npmLockfilePackage struct {
+	// The package's name. Only set for the root project, workspaces and aliased packages, whose names differ from their locations.
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Resolved  string `json:"resolved"`
+	Integrity string `json:"integrity"`
+	// A link to a local package, such as a workspace, whose location is in the resolved field.
+	Link                 bool                         `json:"link"`
+	Dev                  bool                         `json:"dev"`
+	Optional             bool                         `json:"optional"`
+	InBundle             bool                         `json:"inBundle"`
+	Dependencies         map[string]string            `json:"dependencies"`
+	DevDependencies      map[string]string            `json:"devDependencies"`
+	OptionalDependencies map[string]string            `json:"optionalDependencies"`
+	PeerDependencies     map[string]string            `json:"peerDependencies"`
+	PeerDependenciesMeta map[string]npmPeerDependency `json:"peerDependenciesMeta"`
+}
+
+type npmPeerDependency struct {
+	Optional bool `json:"optional"`
+}
+
+// CalculateDependenciesMapFromLockfile builds the dependencies map of an npm project directly from its lockfile, without running npm.
+// The hidden lockfile in node_modules is used if it's newer than package-lock.json, since it reflects the installed tree,
+// and package-lock.json is used otherwise. Only lockfiles of version 2 and 3, written by npm 7 and above, are supported.
+// The dependencies are resolved the way Node.js resolves them: from the node_modules directory of the requiring package, and then of its ancestors.
+func CalculateDependenciesMapFromLockfile(srcPath, moduleId string, log utils.Log) (map[string]*dependencyInfo, error) {
+	if log == nil {
+		log = &utils.NullLog{}
+	}
+	lockfile, err := readNpmLockfile(srcPath, log)
+	if err != nil {
+		return nil, err
+	}
+	return lockfile.dependenciesMap("", moduleId, log)
+}
+
+func readNpmLockfile(srcPath string, log utils.Log) (*npmLockfile, error) {
+	lockfilePath, err := getNpmLockfilePath(srcPath)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("Building the npm dependencies tree from", lockfilePath)
+	lockfileReader, err := os.Open(lockfilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = lockfileReader.Close()
+	}()
+	return parseNpmLockfile(lockfileReader, lockfilePath, srcPath)
+}
+
+// Parses the lockfile from the reader. The lockfile's name is used in the error messages.
+func parseNpmLockfile(reader io.Reader, lockfileName, srcPath string) (*npmLockfile, error) {
+	lockfile := &npmLockfile{srcPath: srcPath}
+	if err := lockfile.decode(reader); err != nil {
+		return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing %s: %w", lockfileName, err))
+	}
+	if lockfile.Packages == nil {
+		return nil, fmt.Errorf("the lockfile %s of version %d doesn't list the installed packages. Lockfiles of version 2 or 3, written by npm 7 or above, are required", lockfileName, lockfile.LockfileVersion)
+	}
+	return lockfile, nil
+}
+
+// Decodes the lockfile incrementally, a package at a time, rather than reading the whole file to memory first, since the lockfiles of large projects may take tens of megabytes.
+// The other fields of the lockfile, such as the legacy dependencies tree of version 2 lockfiles, are skipped.
+func (nl *npmLockfile) decode(reader io.Reader) error {
+	decoder := json.NewDecoder(reader)
+	if err := expectJsonDelim(decoder, '{'); err != nil {
+		return err
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "lockfileVersion":
+			err = decoder.Decode(&nl.LockfileVersion)
+		case "packages":
+			err = nl.decodePackages(decoder)
+		default:
+			err = skipJsonValue(decoder)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return expectJsonDelim(decoder, '}')
+}
+
+func (nl *npmLockfile) decodePackages(decoder *json.Decoder) error {
+	if err := expectJsonDelim(decoder, '{'); err != nil {
+		return err
+	}
+	nl.Packages = make(map[string]*npmLockfilePackage)
+	for decoder.More() {
+		location, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		pkg := &npmLockfilePackage{}
+		if err = decoder.Decode(pkg); err != nil {
+			return err
+		}
+		nl.Packages[location.(string)] = pkg
+	}
+	return expectJsonDelim(decoder, '}')
+}
+
+// Returns the dependencies map of the root project (whose location is an empty string) or of a workspace (whose location is its path).
+func (nl *npmLockfile) dependenciesMap(location, moduleId string, log utils.Log) (map[string]*dependencyInfo, error) {
+	pkg, ok := nl.Packages[location]
+	if !ok {
+		// The hidden lockfile doesn't list the root project and the workspaces, so their dependencies are read from their package.json files.
+		content, err := os.ReadFile(filepath.Join(nl.srcPath, filepath.FromSlash(location), "package.json"))
+		if err != nil {
+			return nil, err
+		}
+		pkg = &npmLockfilePackage{}
+		if err = json.Unmarshal(content, pkg); err != nil {
+			return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing the package.json of %s: %w", moduleId, err))
+		}
+	}
+	dependencies := make(map[string]*dependencyInfo)
+	// The development dependencies of the root project and of the workspaces are installed.
+	nl.walk(location, pkg, true, []string{moduleId}, map[string]bool{}, dependencies, log)
+	for _, dependency := range dependencies {
+		dependency.SetResolutionSource(entities.LockfileSource)
+	}
+	return dependencies, nil
+}
+
+// Returns the path of the hidden lockfile if it's newer than package-lock.json, and of package-lock.json otherwise.
+func getNpmLockfilePath(srcPath string) (string, error) {
+	lockfilePath := filepath.Join(srcPath, npmLockfileName)
+	hiddenLockfilePath := filepath.Join(srcPath, filepath.FromSlash(npmHiddenLockfilePath))
+	lockfileInfo, lockfileErr := os.Stat(lockfilePath)
+	if lockfileErr != nil && !os.IsNotExist(lockfileErr) {
+		return "", lockfileErr
+	}
+	hiddenLockfileInfo, err := os.Stat(hiddenLockfilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	switch {
+	case hiddenLockfileInfo != nil && (lockfileInfo == nil || !hiddenLockfileInfo.ModTime().Before(lockfileInfo.ModTime())):
+		return hiddenLockfilePath, nil
+	case lockfileInfo != nil:
+		return lockfilePath, nil
+	}
+	return "", errors.New("no " + npmLockfileName + " was found in " + srcPath + ". Run 'npm install' to generate it")
+}
+
+// Adds the dependencies of the package in the location to the dependencies map, and walks their dependencies recursively.
+// Like 'npm ls', the dependencies of a package which was already walked through another path aren't walked again.
+func (nl *npmLockfile) walk(location string, pkg *npmLockfilePackage, withDevDependencies bool, pathToRoot []string, walked map[string]bool, dependencies map[string]*dependencyInfo, log utils.Log) {
+	walked[location] = true
+	for _, name := range pkg.dependencyNames(withDevDependencies) {
+		dependencyLocation, dependency := nl.resolve(location, name)
+		if dependency == nil {
+			if pkg.isOptionalOrPeer(name) {
+				log.Debug(fmt.Sprintf("%s is missing. This may be the result of an optional or a peer dependency.", name))
+			} else {
+				log.Warn(fmt.Sprintf("%s, required by %s, is missing from the lockfile.", name, pathToRoot[0]))
+			}
+			continue
+		}
+		if dependency.Link {
+			log.Debug(fmt.Sprintf("Skipping %s, which is a link to the local package at %s.", name, dependency.Resolved))
+			continue
+		}
+		npmLsDependency := &npmLsDependency{
+			Name:      dependency.packageName(dependencyLocation),
+			Version:   dependency.Version,
+			Integrity: dependency.Integrity,
+			Resolved:  dependency.Resolved,
+			InBundle:  dependency.InBundle,
+			Dev:       dependency.Dev,
+			Optional:  dependency.Optional,
+		}
+		// Skip cycles.
+		if slices.Contains(pathToRoot, npmLsDependency.id()) {
+			continue
+		}
+		appendDependency(dependencies, npmLsDependency, pathToRoot)
+		if !walked[dependencyLocation] {
+			nl.walk(dependencyLocation, dependency, false, append([]string{npmLsDependency.id()}, pathToRoot...), walked, dependencies, log)
+		}
+	}
+}
+
+// Resolves the location of a dependency of the package in the location, by looking for it in the node_modules directory
+// of the package, and then of its ancestors, up to the root project. Returns a nil package if the dependency isn't found.
+func (nl *npmLockfile) resolve(location, name string) (string, *npmLockfilePackage) {
+	for {
+		candidate := path.Join(location, nodeModulesDir, name)
+		if pkg, ok := nl.Packages[candidate]; ok {
+			return candidate, pkg
+		}
+		if location == "" {
+			return "", nil
+		}
+		// The parent of node_modules/a/node_modules/b is node_modules/a. The parent of a workspace, such as packages/a, is the root.
+		parentEnd := strings.LastIndex(location, "/"+nodeModulesDir+"/")
+		if parentEnd < 0 {
+			location = ""
+		} else {
+			location = location[:parentEnd]
+		}
+	}
+}
+
+// Returns the sorted names of the package's dependencies.
+func (nlp *npmLockfilePackage) dependencyNames(withDevDependencies bool) (names []string) {
+	dependencyMaps := []map[string]string{nlp.Dependencies, nlp.OptionalDependencies, nlp.PeerDependencies}
+	if withDevDependencies {
+		dependencyMaps = append(dependencyMaps, nlp.DevDependencies)
+	}
+	for _, dependencyMap := range dependencyMaps {
+		for name := range dependencyMap {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+func (nlp *npmLockfilePackage) isOptionalOrPeer(name string) bool {
+	_, optional := nlp.OptionalDependencies[name]
+	_, peer := nlp.PeerDependencies[name]
+	return optional || peer || nlp.PeerDependenciesMeta[name].Optional
+}
+
+// Returns the package's name, which is the last part of its location, unless the package is aliased.
+func (nlp *npmLockfilePackage) packageName(location string) string {
+	if nlp.Name != "" {
+		return nlp.Name
+	}
+	return location[strings.LastIndex(location, nodeModulesDir+"/")+len(nodeModulesDir+"/"):]
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNpmLockfile = `{
+  "name": "app",
+  "version": "1.0.0",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {
+      "name": "app",
+      "version": "1.0.0",
+      "dependencies": {"debug": "^4.3.0", "local-lib": "file:libs/local-lib", "fsevents-alias": "npm:fsevents@^2.3.0"},
+      "devDependencies": {"mocha": "^10.0.0"},
+      "optionalDependencies": {"not-installed": "^1.0.0"}
+    },
+    "node_modules/debug": {
+      "version": "4.3.4",
+      "integrity": "sha512-debug",
+      "dependencies": {"ms": "2.1.2"}
+    },
+    "node_modules/ms": {
+      "version": "2.1.3",
+      "integrity": "sha512-ms-2.1.3",
+      "dev": true
+    },
+    "node_modules/debug/node_modules/ms": {
+      "version": "2.1.2",
+      "integrity": "sha512-ms-2.1.2"
+    },
+    "node_modules/mocha": {
+      "version": "10.2.0",
+      "integrity": "sha512-mocha",
+      "dev": true,
+      "dependencies": {"debug": "4.3.4", "ms": "2.1.3", "missing": "1.0.0"},
+      "peerDependencies": {"chai": "*"}
+    },
+    "node_modules/fsevents-alias": {
+      "name": "fsevents",
+      "version": "2.3.3",
+      "integrity": "sha512-fsevents",
+      "optional": true
+    },
+    "node_modules/local-lib": {
+      "resolved": "libs/local-lib",
+      "link": true
+    }
+  }
+}`
+
+func TestCalculateDependenciesMapFromLockfile(t *testing.T) {
+	srcPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, "package-lock.json"), []byte(testNpmLockfile), 0644))
+	dependencies, err := CalculateDependenciesMapFromLockfile(srcPath, "app:1.0.0", &utils.NullLog{})
+	require.NoError(t, err)
+
+	expected := map[string]struct {
+		scopes      []string
+		requestedBy [][]string
+	}{
+		"debug:4.3.4":    {[]string{"prod"}, [][]string{{"app:1.0.0"}, {"mocha:10.2.0", "app:1.0.0"}}},
+		"ms:2.1.2":       {[]string{"prod"}, [][]string{{"debug:4.3.4", "app:1.0.0"}}},
+		"ms:2.1.3":       {[]string{"dev"}, [][]string{{"mocha:10.2.0", "app:1.0.0"}}},
+		"mocha:10.2.0":   {[]string{"dev"}, [][]string{{"app:1.0.0"}}},
+		"fsevents:2.3.3": {[]string{"prod"}, [][]string{{"app:1.0.0"}}},
+	}
+	assert.Len(t, dependencies, len(expected))
+	for id, expectedDependency := range expected {
+		if assert.Contains(t, dependencies, id) {
+			assert.Equal(t, expectedDependency.scopes, dependencies[id].Scopes, id)
+			assert.Equal(t, expectedDependency.requestedBy, dependencies[id].RequestedBy, id)
+			assert.Equal(t, entities.LockfileSource, dependencies[id].GetResolutionSource(), id)
+		}
+	}
+	assert.Equal(t, "sha512-ms-2.1.2", dependencies["ms:2.1.2"].Integrity)
+	assert.True(t, dependencies["fsevents:2.3.3"].Optional)
+}
+
+func TestCalculateDependenciesMapFromHiddenLockfile(t *testing.T) {
+	srcPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, "package-lock.json"), []byte(testNpmLockfile), 0644))
+	// The hidden lockfile doesn't list the root project, whose dependencies are read from package.json.
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, "package.json"), []byte(`{"name": "app", "version": "1.0.0", "dependencies": {"debug": "^4.3.0"}}`), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(srcPath, "node_modules"), 0755))
+	hiddenLockfilePath := filepath.Join(srcPath, "node_modules", ".package-lock.json")
+	hiddenLockfile := `{"name": "app", "lockfileVersion": 3, "packages": {"node_modules/debug": {"version": "4.3.5", "integrity": "sha512-debug-4.3.5"}}}`
+	require.NoError(t, os.WriteFile(hiddenLockfilePath, []byte(hiddenLockfile), 0644))
+	dependencies, err := CalculateDependenciesMapFromLockfile(srcPath, "app:1.0.0", &utils.NullLog{})
+	require.NoError(t, err)
+	assert.Len(t, dependencies, 1)
+	assert.Contains(t, dependencies, "debug:4.3.5")
+
+	// A hidden lockfile which is older than package-lock.json is stale.
+	require.NoError(t, os.Chtimes(hiddenLockfilePath, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)))
+	dependencies, err = CalculateDependenciesMapFromLockfile(srcPath, "app:1.0.0", &utils.NullLog{})
+	require.NoError(t, err)
+	assert.Contains(t, dependencies, "debug:4.3.4")
+}
+
+func TestCalculateDependenciesMapFromLockfileUnsupported(t *testing.T) {
+	srcPath := t.TempDir()
+	_, err := CalculateDependenciesMapFromLockfile(srcPath, "app:1.0.0", &utils.NullLog{})
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, "package-lock.json"), []byte(`{"lockfileVersion": 1, "dependencies": {"debug": {"version": "4.3.4"}}}`), 0644))
+	_, err = CalculateDependenciesMapFromLockfile(srcPath, "app:1.0.0", &utils.NullLog{})
+	assert.ErrorContains(t, err, "version 1")
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+)
+
+const npmPackageJsonName = "package.json"
+
+// NpmManifests holds the manifests of an npm project which were provided as a payload, rather than read from the project's directory,
+// so that the build-info can be generated without materializing the project on disk.
+type NpmManifests struct {
+	PackageJson []byte
+	Lockfile    []byte
+}
+
+// ReadNpmManifests reads the package.json and package-lock.json of an npm project from a payload in one of the following formats:
+// 1. A JSON object, which maps the files' names to their contents: {"package.json": {...}, "package-lock.json": {...}}
+// 2. A tar archive, optionally gzipped, which contains the files in its root.
+func ReadNpmManifests(reader io.Reader) (*NpmManifests, error) {
+	bufferedReader := bufio.NewReader(reader)
+	firstByte, err := peekFirstNonSpaceByte(bufferedReader)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("the npm manifests payload is empty")
+		}
+		return nil, err
+	}
+	var manifests *NpmManifests
+	if firstByte == '{' {
+		manifests, err = readNpmManifestsJson(bufferedReader)
+	} else {
+		manifests, err = readNpmManifestsTar(bufferedReader)
+	}
+	if err != nil {
+		return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed reading the npm manifests payload: %w", err))
+	}
+	if len(manifests.PackageJson) == 0 || len(manifests.Lockfile) == 0 {
+		return nil, fmt.Errorf("the npm manifests payload must contain both %s and %s", npmPackageJsonName, npmLockfileName)
+	}
+	return manifests, nil
+}
+
+func peekFirstNonSpaceByte(reader *bufio.Reader) (byte, error) {
+	for {
+		nextByte, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch nextByte {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return nextByte, reader.UnreadByte()
+	}
+}
+
+func readNpmManifestsJson(reader io.Reader) (*NpmManifests, error) {
+	var payload map[string]json.RawMessage
+	if err := json.NewDecoder(reader).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return &NpmManifests{PackageJson: payload[npmPackageJsonName], Lockfile: payload[npmLockfileName]}, nil
+}
+
+// Reads the manifests from the root of a tar archive. The archive is decompressed first if it's gzipped.
+func readNpmManifestsTar(reader *bufio.Reader) (*NpmManifests, error) {
+	var archiveReader io.Reader = reader
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = gzipReader.Close()
+		}()
+		archiveReader = gzipReader
+	}
+	manifests := &NpmManifests{}
+	tarReader := tar.NewReader(archiveReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return manifests, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		var content *[]byte
+		switch path.Clean(header.Name) {
+		case npmPackageJsonName:
+			content = &manifests.PackageJson
+		case npmLockfileName:
+			content = &manifests.Lockfile
+		default:
+			continue
+		}
+		if *content, err = io.ReadAll(tarReader); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ReadPackageInfo reads the name, version and scope of the project from its package.json.
+func (nm *NpmManifests) ReadPackageInfo() (*PackageInfo, error) {
+	packageInfo, err := ReadPackageInfo(nm.PackageJson, nil)
+	if err != nil {
+		return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing %s: %w", npmPackageJsonName, err))
+	}
+	return packageInfo, nil
+}
+
+// CalculateNpmDependenciesListFromManifests gets the dependencies of an npm project from its manifests, like the offline collection does from its lockfile.
+// Since the project's directory and the npm cache aren't available, the dependencies' checksums aren't calculated.
+func CalculateNpmDependenciesListFromManifests(manifests *NpmManifests, moduleId string, log utils.Log) ([]entities.Dependency, error) {
+	if log == nil {
+		log = &utils.NullLog{}
+	}
+	log.Debug("Building the npm dependencies tree from the provided manifests")
+	lockfile, err := parseNpmLockfile(bytes.NewReader(manifests.Lockfile), npmLockfileName, "")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := lockfile.Packages[""]; !ok {
+		// Since the lockfile may not list the root project, its dependencies are read from the provided package.json rather than from the disk.
+		rootPackage := &npmLockfilePackage{}
+		if err = json.Unmarshal(manifests.PackageJson, rootPackage); err != nil {
+			return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing %s: %w", npmPackageJsonName, err))
+		}
+		lockfile.Packages[""] = rootPackage
+	}
+	dependenciesMap, err := lockfile.dependenciesMap("", moduleId, log)
+	if err != nil {
+		return nil, err
+	}
+	return toDependenciesList(dependenciesMap, nil, utils.IntegrityVerificationOff, log)
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNpmPackageJson = `{"name": "@jfrog/app", "version": "1.0.0", "dependencies": {"debug": "^4.3.0"}}`
+
+func createNpmManifestsTar(t *testing.T, writer io.Writer, files map[string]string) {
+	tarWriter := tar.NewWriter(writer)
+	for name, content := range files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+}
+
+func TestReadNpmManifests(t *testing.T) {
+	jsonPayload := `  {"package.json": ` + testNpmPackageJson + `, "package-lock.json": ` + testNpmLockfile + `}`
+	var tarPayload bytes.Buffer
+	createNpmManifestsTar(t, &tarPayload, map[string]string{"./package.json": testNpmPackageJson, "package-lock.json": testNpmLockfile, "README.md": "app"})
+	var gzipPayload bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipPayload)
+	createNpmManifestsTar(t, gzipWriter, map[string]string{"package.json": testNpmPackageJson, "package-lock.json": testNpmLockfile})
+	require.NoError(t, gzipWriter.Close())
+
+	for name, payload := range map[string]io.Reader{"json": strings.NewReader(jsonPayload), "tar": &tarPayload, "gzip": &gzipPayload} {
+		t.Run(name, func(t *testing.T) {
+			manifests, err := ReadNpmManifests(payload)
+			require.NoError(t, err)
+			assert.JSONEq(t, testNpmPackageJson, string(manifests.PackageJson))
+			assert.JSONEq(t, testNpmLockfile, string(manifests.Lockfile))
+			packageInfo, err := manifests.ReadPackageInfo()
+			require.NoError(t, err)
+			assert.Equal(t, "jfrog:app:1.0.0", packageInfo.BuildInfoModuleId())
+		})
+	}
+}
+
+func TestReadNpmManifestsErrors(t *testing.T) {
+	_, err := ReadNpmManifests(strings.NewReader(" \n"))
+	assert.EqualError(t, err, "the npm manifests payload is empty")
+
+	_, err = ReadNpmManifests(strings.NewReader(`{"package.json": ` + testNpmPackageJson + `}`))
+	assert.EqualError(t, err, "the npm manifests payload must contain both package.json and package-lock.json")
+
+	_, err = ReadNpmManifests(strings.NewReader(`{"package.json": `))
+	assert.Equal(t, utils.ParseFailure, utils.GetErrorCategory(err))
+}
+
+func TestCalculateNpmDependenciesListFromManifests(t *testing.T) {
+	manifests := &NpmManifests{PackageJson: []byte(testNpmPackageJson), Lockfile: []byte(testNpmLockfile)}
+	dependencies, err := CalculateNpmDependenciesListFromManifests(manifests, "app:1.0.0", &utils.NullLog{})
+	require.NoError(t, err)
+	var ids []string
+	for _, dependency := range dependencies {
+		ids = append(ids, dependency.Id)
+		assert.Empty(t, dependency.Checksum)
+	}
+	assert.ElementsMatch(t, []string{"debug:4.3.4", "ms:2.1.2", "ms:2.1.3", "mocha:10.2.0", "fsevents:2.3.3"}, ids)
+
+	// A lockfile which doesn't list the root project, such as the hidden lockfile, is completed by the package.json.
+	manifests.Lockfile = []byte(`{"lockfileVersion": 3, "packages": {"node_modules/debug": {"version": "4.3.5", "integrity": "sha512-debug-4.3.5"}}}`)
+	dependencies, err = CalculateNpmDependenciesListFromManifests(manifests, "app:1.0.0", &utils.NullLog{})
+	require.NoError(t, err)
+	require.Len(t, dependencies, 1)
+	assert.Equal(t, "debug:4.3.5", dependencies[0].Id)
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+)
+
+// The property of the scoped npm dependencies, which holds the URL of the registry their tarballs were resolved from,
+// as recorded in the lockfile or in the 'npm ls' output, for example: https://npm.company.com/
+const NpmRegistryProperty = "npm.registry"
+
+const npmrcFileName = ".npmrc"
+
+// Matches the environment variables in the values of .npmrc files, which npm expands: ${NAME}
+var npmrcEnvVarRegex = regexp.MustCompile(`\$\{([^}]+)}`)
+
+// ReadNpmScopeRegistries returns the registries of the scopes, configured by the '@<scope>:registry' keys of the project's .npmrc file,
+// and of the user's .npmrc file (NPM_CONFIG_USERCONFIG, or ~/.npmrc), whose keys the project's file overrides.
+// The registries are mapped by their scopes, for example: @company -> https://npm.company.com/
+// The environment variables in the values, in the ${NAME} format, are expanded like npm does. The global .npmrc file isn't read.
+func ReadNpmScopeRegistries(srcPath string) (map[string]string, error) {
+	userConfig := os.Getenv("NPM_CONFIG_USERCONFIG")
+	if userConfig == "" {
+		userConfig = os.Getenv("npm_config_userconfig")
+	}
+	if userConfig == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		userConfig = filepath.Join(home, npmrcFileName)
+	}
+	registries := make(map[string]string)
+	for _, npmrcPath := range []string{userConfig, filepath.Join(srcPath, npmrcFileName)} {
+		if err := readNpmrcScopeRegistries(npmrcPath, registries); err != nil {
+			return nil, err
+		}
+	}
+	return registries, nil
+}
+
+// Adds the scope registries of the .npmrc file to the registries map. A missing file is skipped.
+func readNpmrcScopeRegistries(npmrcPath string, registries map[string]string) error {
+	npmrcFile, err := os.Open(npmrcPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer func() {
+		_ = npmrcFile.Close()
+	}()
+	scanner := bufio.NewScanner(npmrcFile)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		scope, found := strings.CutSuffix(strings.TrimSpace(key), ":registry")
+		if !found || !strings.HasPrefix(scope, "@") {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		registries[scope] = npmrcEnvVarRegex.ReplaceAllStringFunc(value, func(envVar string) string {
+			return os.Getenv(envVar[2 : len(envVar)-1])
+		})
+	}
+	return scanner.Err()
+}
+
+// GetNpmPackageScope returns the scope of the npm package's name, for example: @company for @company/utils
+// Returns an empty string if the package isn't scoped.
+func GetNpmPackageScope(packageName string) string {
+	if !strings.HasPrefix(packageName, "@") {
+		return ""
+	}
+	scope, _, found := strings.Cut(packageName, "/")
+	if !found {
+		return ""
+	}
+	return scope
+}
+
+// Returns the URL of the registry which the package's tarball URL belongs to, with a trailing slash.
+// The tarball URLs of registries are in the <registry>/<name>/-/<file name> format, in which the '/' of a scoped name may be escaped as %2f.
+// Returns an empty string if the URL isn't an HTTP URL of a registry's tarball, for example if it's a Git or a local URL.
+func getNpmRegistryFromTarballUrl(packageName, tarballUrl string) string {
+	if !strings.HasPrefix(tarballUrl, "https://") && !strings.HasPrefix(tarballUrl, "http://") {
+		return ""
+	}
+	lowerTarballUrl := strings.ToLower(tarballUrl)
+	for _, name := range []string{packageName, strings.Replace(packageName, "/", "%2f", 1)} {
+		if index := strings.Index(lowerTarballUrl, "/"+strings.ToLower(name)+"/-/"); index >= 0 {
+			return tarballUrl[:index+1]
+		}
+	}
+	return ""
+}
+
+// IsSameNpmRegistry returns true if the URLs are of the same registry, regardless of the case of their hosts and of their trailing slashes.
+func IsSameNpmRegistry(registry, otherRegistry string) bool {
+	return strings.EqualFold(strings.TrimSuffix(registry, "/"), strings.TrimSuffix(otherRegistry, "/"))
+}
+
+// ApplyNpmScopeRegistries completes the registry property of the scoped dependencies which weren't resolved from a registry's tarball URL,
+// with the registry configured for their scopes. A warning is logged for each scoped dependency which was resolved from a different registry
+// than the one configured for its scope, since it may have been substituted by a package with the same name from another registry (dependency confusion).
+func ApplyNpmScopeRegistries(dependencies []entities.Dependency, scopeRegistries map[string]string, log utils.Log) {
+	for i := range dependencies {
+		// The IDs of the dependencies are in the <name>:<version> format.
+		configuredRegistry := scopeRegistries[GetNpmPackageScope(dependencies[i].Id)]
+		if configuredRegistry == "" {
+			continue
+		}
+		resolvedRegistry := dependencies[i].Properties[NpmRegistryProperty]
+		if resolvedRegistry == "" {
+			if dependencies[i].Properties == nil {
+				dependencies[i].Properties = make(map[string]string)
+			}
+			dependencies[i].Properties[NpmRegistryProperty] = configuredRegistry
+			continue
+		}
+		if !IsSameNpmRegistry(resolvedRegistry, configuredRegistry) {
+			log.Warn(fmt.Sprintf("%s was resolved from %s, while the registry of its scope is %s. Make sure it isn't a package with the same name from another registry.",
+				dependencies[i].Id, resolvedRegistry, configuredRegistry))
+		}
+	}
+}
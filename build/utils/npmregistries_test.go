@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadNpmScopeRegistries(t *testing.T) {
+	userConfig := filepath.Join(t.TempDir(), "npmrc")
+	require.NoError(t, os.WriteFile(userConfig, []byte("@company:registry=https://user.company.com/\n@other:registry=https://other.com/\n"), 0644))
+	t.Setenv("NPM_CONFIG_USERCONFIG", userConfig)
+	t.Setenv("NPM_REGISTRY_HOST", "npm.company.com")
+	srcPath := t.TempDir()
+	npmrc := "; comment\n# comment\nregistry=https://registry.npmjs.org/\n@company:registry = \"https://${NPM_REGISTRY_HOST}/api/npm/npm/\"\n//npm.company.com/:_authToken=token\n"
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, ".npmrc"), []byte(npmrc), 0644))
+
+	// The project's .npmrc file overrides the user's.
+	registries, err := ReadNpmScopeRegistries(srcPath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"@company": "https://npm.company.com/api/npm/npm/", "@other": "https://other.com/"}, registries)
+
+	registries, err = ReadNpmScopeRegistries(t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"@company": "https://user.company.com/", "@other": "https://other.com/"}, registries)
+}
+
+func TestGetNpmRegistryFromTarballUrl(t *testing.T) {
+	tests := []struct {
+		name       string
+		tarballUrl string
+		expected   string
+	}{
+		{"@company/utils", "https://npm.company.com/api/npm/npm/@company/utils/-/utils-1.0.0.tgz", "https://npm.company.com/api/npm/npm/"},
+		{"@company/utils", "https://npm.company.com/@company%2futils/-/utils-1.0.0.tgz", "https://npm.company.com/"},
+		{"@company/utils", "https://npm.company.com/@company%2Futils/-/utils-1.0.0.tgz", "https://npm.company.com/"},
+		{"ms", "https://registry.npmjs.org/ms/-/ms-2.1.3.tgz", "https://registry.npmjs.org/"},
+		{"@company/utils", "git+ssh://git@github.com/company/utils.git#abc", ""},
+		{"@company/utils", "file:../utils", ""},
+		{"@company/utils", "https://github.com/company/utils/archive/main.tar.gz", ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, getNpmRegistryFromTarballUrl(test.name, test.tarballUrl), test.tarballUrl)
+	}
+}
+
+func TestNpmRegistryPropertyFromLockfile(t *testing.T) {
+	srcPath := t.TempDir()
+	lockfile := `{"name": "app", "version": "1.0.0", "lockfileVersion": 3, "packages": {
+  "": {"name": "app", "version": "1.0.0", "dependencies": {"@company/utils": "^1.0.0", "ms": "^2.1.0"}},
+  "node_modules/@company/utils": {"version": "1.0.0", "resolved": "https://npm.company.com/@company/utils/-/utils-1.0.0.tgz", "integrity": "sha512-utils"},
+  "node_modules/ms": {"version": "2.1.3", "resolved": "https://registry.npmjs.org/ms/-/ms-2.1.3.tgz", "integrity": "sha512-ms"}
+}}`
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, "package-lock.json"), []byte(lockfile), 0644))
+	dependencies, err := CalculateDependenciesMapFromLockfile(srcPath, "app:1.0.0", &utils.NullLog{})
+	require.NoError(t, err)
+	require.Contains(t, dependencies, "@company/utils:1.0.0")
+	assert.Equal(t, "https://npm.company.com/", dependencies["@company/utils:1.0.0"].Properties[NpmRegistryProperty])
+	// The registries of unscoped dependencies aren't recorded.
+	require.Contains(t, dependencies, "ms:2.1.3")
+	assert.NotContains(t, dependencies["ms:2.1.3"].Properties, NpmRegistryProperty)
+}
+
+func TestApplyNpmScopeRegistries(t *testing.T) {
+	dependencies := []entities.Dependency{
+		{Id: "@company/utils:1.0.0", Properties: map[string]string{NpmRegistryProperty: "https://NPM.company.com"}},
+		{Id: "@company/linked:1.0.0"},
+		{Id: "@company/confused:1.0.0", Properties: map[string]string{NpmRegistryProperty: "https://registry.npmjs.org/"}},
+		{Id: "@other/lib:1.0.0"},
+		{Id: "ms:2.1.3"},
+	}
+	ApplyNpmScopeRegistries(dependencies, map[string]string{"@company": "https://npm.company.com/"}, &utils.NullLog{})
+	// The resolved registries are kept, and the missing ones are completed with the registry of the scope.
+	assert.Equal(t, "https://NPM.company.com", dependencies[0].Properties[NpmRegistryProperty])
+	assert.Equal(t, "https://npm.company.com/", dependencies[1].Properties[NpmRegistryProperty])
+	assert.Equal(t, "https://registry.npmjs.org/", dependencies[2].Properties[NpmRegistryProperty])
+	assert.Nil(t, dependencies[3].Properties)
+	assert.Nil(t, dependencies[4].Properties)
+
+	assert.True(t, IsSameNpmRegistry("https://NPM.company.com", "https://npm.company.com/"))
+	assert.False(t, IsSameNpmRegistry("https://registry.npmjs.org/", "https://npm.company.com/"))
+}
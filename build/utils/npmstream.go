@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/jfrog/build-info-go/utils"
+)
+
+// Runs 'npm ls' and passes its standard output to parseOutput while it's written, rather than reading the whole output to memory first.
+// The output of 'npm ls --all --long' may take hundreds of megabytes in large projects.
+// Like when the output is read to memory, a failure of 'npm ls' is only logged, since its output is still valid.
+func streamNpmLs(executablePath, srcPath string, lsArgs []string, parseOutput func(output io.Reader) error, log utils.Log) error {
+	args := AppendNpmCommand(lsArgs, "ls")
+	if !utils.IsProcessCommandRunner() {
+		// The output of a runner set with SetCommandRunner isn't streamed.
+		output, _, err := RunNpmCmd(executablePath, srcPath, args, log)
+		if err != nil {
+			log.Warn(err.Error())
+		}
+		return parseOutput(bytes.NewReader(output))
+	}
+	log.Debug("Running 'npm " + strings.Join(args, " ") + "' command.")
+	command := exec.Command(executablePath, args...)
+	command.Dir = srcPath
+	errBuffer := bytes.NewBuffer([]byte{})
+	command.Stderr = errBuffer
+	output, err := command.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err = command.Start(); err != nil {
+		return err
+	}
+	parseErr := parseOutput(output)
+	// Drain the rest of the output, so that npm doesn't block on a full pipe.
+	_, _ = io.Copy(io.Discard, output)
+	if err = command.Wait(); err != nil {
+		log.Warn(fmt.Sprintf("error while running '%s %s': %s\n%s", executablePath, strings.Join(args, " "), err.Error(), strings.TrimSpace(errBuffer.String())))
+	} else if errBuffer.Len() > 0 {
+		log.Warn("Encountered some issues while running 'npm ls' command:\n" + strings.TrimSpace(errBuffer.String()))
+	}
+	return parseErr
+}
+
+// Parses the output of 'npm ls --json' incrementally, and adds the dependencies under keyPath to the given dependencies map.
+// Only the fields of a single dependency are held in memory at a time, rather than the whole output.
+// If keyPath doesn't exist in the output, no dependencies are added.
+func parseNpmLsStream(output io.Reader, keyPath []string, pathToRoot []string, dependencies map[string]*dependencyInfo, parseFunc func(data []byte) (*npmLsDependency, error), log utils.Log) error {
+	decoder := json.NewDecoder(output)
+	found, err := seekJsonKeyPath(decoder, keyPath)
+	if err == nil && found {
+		err = parseDependenciesStream(decoder, pathToRoot, dependencies, parseFunc, log)
+	}
+	var categorizedError *utils.CategorizedError
+	if err != nil && !errors.As(err, &categorizedError) {
+		err = utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed to parse the npm ls output: %w", err))
+	}
+	return err
+}
+
+// The streaming equivalent of parseDependencies. Reads a dependencies object from the decoder, including its closing delimiter.
+func parseDependenciesStream(decoder *json.Decoder, pathToRoot []string, dependencies map[string]*dependencyInfo, parseFunc func(data []byte) (*npmLsDependency, error), log utils.Log) error {
+	if err := expectJsonDelim(decoder, '{'); err != nil {
+		return err
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if err = parseDependencyStream(decoder, key.(string), pathToRoot, dependencies, parseFunc, log); err != nil {
+			return err
+		}
+	}
+	return expectJsonDelim(decoder, '}')
+}
+
+// Reads a single dependency from the decoder. Its transitive dependencies are parsed as a stream once the dependency's ID is known.
+// npm writes the 'dependencies' field after the name and version, so buffering the transitive dependencies is only a fallback.
+func parseDependencyStream(decoder *json.Decoder, key string, pathToRoot []string, dependencies map[string]*dependencyInfo, parseFunc func(data []byte) (*npmLsDependency, error), log utils.Log) error {
+	if err := expectJsonDelim(decoder, '{'); err != nil {
+		return err
+	}
+	fields := make(map[string]json.RawMessage)
+	var bufferedTransitive json.RawMessage
+	hasTransitive := false
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		field := token.(string)
+		if field != "dependencies" {
+			var value json.RawMessage
+			if err = decoder.Decode(&value); err != nil {
+				return err
+			}
+			fields[field] = value
+			continue
+		}
+		hasTransitive = true
+		dependency, err := parseNpmLsFields(fields, parseFunc)
+		if err != nil {
+			return err
+		}
+		if dependency.Name == "" || dependency.Version == "" {
+			if err = decoder.Decode(&bufferedTransitive); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = parseDependenciesStream(decoder, append([]string{dependency.id()}, pathToRoot...), dependencies, parseFunc, log); err != nil {
+			return err
+		}
+	}
+	if err := expectJsonDelim(decoder, '}'); err != nil {
+		return err
+	}
+	if len(fields) == 0 && !hasTransitive {
+		// Skip missing optional dependency.
+		log.Debug(fmt.Sprintf("%s is missing. This may be the result of an optional dependency.", key))
+		return nil
+	}
+	dependency, err := parseNpmLsFields(fields, parseFunc)
+	if err != nil {
+		return err
+	}
+	if added, err := addNpmLsDependency(key, dependency, pathToRoot, dependencies, log); err != nil || !added {
+		return err
+	}
+	if len(bufferedTransitive) > 0 {
+		return parseDependencies(bufferedTransitive, append([]string{dependency.id()}, pathToRoot...), dependencies, parseFunc, log)
+	}
+	return nil
+}
+
+// Parses the fields of a dependency, other than its transitive dependencies, which were read from the npm ls output.
+func parseNpmLsFields(fields map[string]json.RawMessage, parseFunc func(data []byte) (*npmLsDependency, error)) (*npmLsDependency, error) {
+	content, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	return parseFunc(content)
+}
+
+// Advances the decoder to the value of keyPath, skipping the values of the other keys without reading them to memory.
+// Returns false if keyPath doesn't exist.
+func seekJsonKeyPath(decoder *json.Decoder, keyPath []string) (bool, error) {
+	for _, key := range keyPath {
+		if err := expectJsonDelim(decoder, '{'); err != nil {
+			return false, err
+		}
+		found := false
+		for !found && decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return false, err
+			}
+			if token == key {
+				found = true
+			} else if err = skipJsonValue(decoder); err != nil {
+				return false, err
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Skips the next value of the decoder, token by token.
+func skipJsonValue(decoder *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func expectJsonDelim(decoder *json.Decoder, expected json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != expected {
+		return fmt.Errorf("expected '%s' but found '%v'", expected, token)
+	}
+	return nil
+}
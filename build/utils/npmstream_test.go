@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNpmLsStream(t *testing.T) {
+	dependenciesJsonList, err := os.ReadFile(filepath.Join("..", "testdata", "npm", "dependenciesList.json"))
+	require.NoError(t, err)
+	expectedDependencies := make(map[string]*dependencyInfo)
+	require.NoError(t, parseDependencies(dependenciesJsonList, []string{"root"}, expectedDependencies, npmLsDependencyParser, &utils.NullLog{}))
+
+	output := `{"name": "root", "version": "1.0.0", "problems": ["extraneous: some-package"], "dependencies": ` + string(dependenciesJsonList) + `}`
+	dependencies := make(map[string]*dependencyInfo)
+	require.NoError(t, parseNpmLsStream(strings.NewReader(output), []string{"dependencies"}, []string{"root"}, dependencies, npmLsDependencyParser, &utils.NullLog{}))
+	assert.Len(t, dependencies, len(expectedDependencies))
+	for id, expected := range expectedDependencies {
+		if assert.Contains(t, dependencies, id) {
+			assert.ElementsMatch(t, expected.RequestedBy, dependencies[id].RequestedBy, id)
+			assert.Equal(t, expected.Integrity, dependencies[id].Integrity, id)
+			assert.Equal(t, expected.Scopes, dependencies[id].Scopes, id)
+		}
+	}
+}
+
+func TestParseNpmLsStreamWorkspace(t *testing.T) {
+	output := `{
+  "name": "monorepo",
+  "dependencies": {
+    "@acme/core": {
+      "version": "1.2.0",
+      "dependencies": {
+        "lodash": {"name": "lodash", "version": "4.17.21", "integrity": "sha512-lodash"},
+        "debug": {"dependencies": {"ms": {"name": "ms", "version": "2.1.2"}}, "name": "debug", "version": "4.3.4", "integrity": "sha512-debug"},
+        "fsevents": {},
+        "react": {"missing": true, "problems": ["missing: react@^18.0.0, required by @acme/core@1.2.0"]}
+      }
+    }
+  }
+}`
+	workspaceKeyPath := []string{"dependencies", "@acme/core", "dependencies"}
+	dependencies := make(map[string]*dependencyInfo)
+	require.NoError(t, parseNpmLsStream(strings.NewReader(output), workspaceKeyPath, []string{"acme:core:1.2.0"}, dependencies, npmLsDependencyParser, &utils.NullLog{}))
+	assert.Len(t, dependencies, 3)
+	assert.Equal(t, [][]string{{"acme:core:1.2.0"}}, dependencies["lodash:4.17.21"].RequestedBy)
+	assert.Equal(t, "sha512-debug", dependencies["debug:4.3.4"].Integrity)
+	// The transitive dependencies which precede the dependency's version are parsed after it.
+	assert.Equal(t, [][]string{{"debug:4.3.4", "acme:core:1.2.0"}}, dependencies["ms:2.1.2"].RequestedBy)
+
+	// A workspace without dependencies.
+	dependencies = make(map[string]*dependencyInfo)
+	require.NoError(t, parseNpmLsStream(bytes.NewBufferString(`{"name": "monorepo", "dependencies": {"@acme/core": {"version": "1.2.0"}}}`), workspaceKeyPath, []string{"acme:core:1.2.0"}, dependencies, npmLsDependencyParser, &utils.NullLog{}))
+	assert.Empty(t, dependencies)
+
+	// A truncated output.
+	err := parseNpmLsStream(strings.NewReader(output[:len(output)/2]), workspaceKeyPath, []string{"acme:core:1.2.0"}, map[string]*dependencyInfo{}, npmLsDependencyParser, &utils.NullLog{})
+	assert.ErrorContains(t, err, "failed to parse the npm ls output")
+	assert.Equal(t, utils.ParseFailure, utils.GetErrorCategory(err))
+}
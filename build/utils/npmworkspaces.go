@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/buger/jsonparser"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/parallel"
+	"golang.org/x/exp/slices"
+)
+
+const minNpmWorkspacesVersion = "7.0.0"
+
+// NpmWorkspace is a workspace of an npm monorepo, declared in the 'workspaces' field of the root package.json.
+type NpmWorkspace struct {
+	// The workspace's directory, relative to the root of the monorepo.
+	Path string
+	// The full name of the workspace's package, including its scope.
+	Name string
+	// The ID of the workspace's build-info module.
+	ModuleId string
+}
+
+// GetNpmWorkspaces returns the workspaces matching the patterns of the 'workspaces' field of the package.json in srcPath,
+// sorted by their paths. The directories matching the patterns without a package.json aren't workspaces, and are skipped.
+func GetNpmWorkspaces(srcPath string) ([]NpmWorkspace, error) {
+	content, err := os.ReadFile(filepath.Join(srcPath, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	var packageJson struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err = json.Unmarshal(content, &packageJson); err != nil {
+		return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing the package.json in %s: %w", srcPath, err))
+	}
+	patterns, err := parseNpmWorkspacesField(packageJson.Workspaces)
+	if err != nil {
+		return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing the 'workspaces' field of the package.json in %s: %w", srcPath, err))
+	}
+	var workspaces []NpmWorkspace
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(srcPath, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			relativePath, err := filepath.Rel(srcPath, match)
+			if err != nil {
+				return nil, err
+			}
+			if slices.ContainsFunc(workspaces, func(existing NpmWorkspace) bool { return existing.Path == relativePath }) {
+				continue
+			}
+			packageInfo, err := ReadPackageInfoFromPackageJsonIfExists(match, nil)
+			if err != nil {
+				return nil, err
+			}
+			if packageInfo.Name == "" {
+				continue
+			}
+			moduleId := packageInfo.BuildInfoModuleId()
+			if moduleId == "" {
+				// Workspaces which are never published may have no version.
+				moduleId = strings.TrimPrefix(packageInfo.FullName(), "@")
+			}
+			workspaces = append(workspaces, NpmWorkspace{Path: relativePath, Name: packageInfo.FullName(), ModuleId: moduleId})
+		}
+	}
+	sort.Slice(workspaces, func(i, j int) bool { return workspaces[i].Path < workspaces[j].Path })
+	return workspaces, nil
+}
+
+// The 'workspaces' field is either a list of patterns, or an object with the patterns in its 'packages' field.
+func parseNpmWorkspacesField(field json.RawMessage) (patterns []string, err error) {
+	if len(field) == 0 {
+		return
+	}
+	if err = json.Unmarshal(field, &patterns); err == nil {
+		return
+	}
+	var workspacesObject struct {
+		Packages []string `json:"packages"`
+	}
+	if err = json.Unmarshal(field, &workspacesObject); err != nil {
+		return nil, err
+	}
+	return workspacesObject.Packages, nil
+}
+
+// CalculateNpmWorkspacesDependencies runs 'npm ls' for each of the workspaces of the monorepo in srcPath, running up to threads commands in parallel,
+// and returns the dependencies of each workspace, mapped by the workspaces' module IDs.
+// The package-lock.json of the monorepo is shared by all the workspaces, so if it needs to be generated, it's generated once rather than per workspace.
+// The checksums of the dependencies shared by several workspaces, such as the dependencies hoisted to the root node_modules, are calculated once.
+// If npmParams.LowMemory is true, the workspaces are collected one at a time, so that the dependencies map of a single workspace is held in memory at a time.
+func CalculateNpmWorkspacesDependencies(executablePath, srcPath string, workspaces []NpmWorkspace, npmParams NpmTreeDepListParam, threads int, log utils.Log) (map[string][]entities.Dependency, error) {
+	if log == nil {
+		log = &utils.NullLog{}
+	}
+	if npmParams.Offline {
+		return calculateNpmWorkspacesDependenciesFromLockfile(srcPath, workspaces, npmParams, log)
+	}
+	npmVersion, err := GetNpmVersion(executablePath, log)
+	if err != nil {
+		return nil, err
+	}
+	if npmVersion.Compare(minNpmWorkspacesVersion) > 0 {
+		return nil, errors.New("collecting npm workspaces requires npm " + minNpmWorkspacesVersion + " or higher. The current version is: " + npmVersion.GetVersion())
+	}
+	nodeModulesExist, err := utils.IsDirExists(filepath.Join(srcPath, "node_modules"), false)
+	if err != nil {
+		return nil, err
+	}
+	lsArgs := append(slices.Clone(npmParams.Args), "--json", "--all", "--long")
+	resolutionSource := entities.CliTreeSource
+	if !nodeModulesExist || npmParams.IgnoreNodeModules {
+		installRequired, err := isInstallRequired(srcPath, npmParams, log, false)
+		if err != nil {
+			return nil, err
+		}
+		if installRequired {
+			if err = installPackageLock(executablePath, srcPath, npmParams.InstallCommandArgs, npmParams.Args, log, npmVersion); err != nil {
+				return nil, err
+			}
+		}
+		lsArgs = append(lsArgs, "--package-lock-only")
+		resolutionSource = entities.LockfileSource
+	}
+	checksums, err := newNpmChecksumsCalculator(executablePath, srcPath, npmParams, log)
+	if err != nil {
+		return nil, err
+	}
+	parseFunc := parseNpmLsDependencyFunc(npmVersion)
+
+	if threads < 1 || npmParams.LowMemory {
+		threads = 1
+	}
+	dependencies := make(map[string][]entities.Dependency, len(workspaces))
+	var resultsLock sync.Mutex
+	var workspacesErrors []error
+	runner := parallel.NewBounedRunner(threads, false)
+	go func() {
+		defer runner.Done()
+		for _, workspace := range workspaces {
+			workspace := workspace
+			_, _ = runner.AddTaskWithError(func(int) error {
+				workspaceArgs := append(slices.Clone(lsArgs), "--workspace="+filepath.ToSlash(workspace.Path))
+				dependenciesMap, err := runNpmWorkspaceLs(executablePath, srcPath, workspaceArgs, workspace, npmParams.LowMemory, parseFunc, log)
+				if err != nil {
+					return err
+				}
+				for _, dependency := range dependenciesMap {
+					dependency.SetResolutionSource(resolutionSource)
+				}
+				workspaceDependencies, err := toDependenciesList(dependenciesMap, checksums, npmParams.IntegrityVerification, log)
+				if err != nil {
+					return err
+				}
+				resultsLock.Lock()
+				defer resultsLock.Unlock()
+				dependencies[workspace.ModuleId] = workspaceDependencies
+				return nil
+			}, func(err error) {
+				resultsLock.Lock()
+				defer resultsLock.Unlock()
+				workspacesErrors = append(workspacesErrors, fmt.Errorf("failed calculating the dependencies of the npm workspace at %s: %w", workspace.Path, err))
+			})
+		}
+	}()
+	runner.Run()
+	return dependencies, errors.Join(workspacesErrors...)
+}
+
+// Runs 'npm ls' for a workspace and returns its dependencies map. If lowMemory is true, the output is parsed while it's written.
+func runNpmWorkspaceLs(executablePath, srcPath string, workspaceArgs []string, workspace NpmWorkspace, lowMemory bool, parseFunc func(data []byte) (*npmLsDependency, error), log utils.Log) (map[string]*dependencyInfo, error) {
+	if lowMemory {
+		dependenciesMap := make(map[string]*dependencyInfo)
+		return dependenciesMap, streamNpmLs(executablePath, srcPath, workspaceArgs, func(output io.Reader) error {
+			return parseNpmLsStream(output, []string{"dependencies", workspace.Name, "dependencies"}, []string{workspace.ModuleId}, dependenciesMap, parseFunc, log)
+		}, log)
+	}
+	data, errData, err := RunNpmCmd(executablePath, srcPath, AppendNpmCommand(workspaceArgs, "ls"), log)
+	if err != nil {
+		// 'npm ls' fails on problems in the tree, such as extraneous packages, but its output is still valid.
+		log.Warn(err.Error())
+	} else if len(errData) > 0 {
+		log.Warn("Encountered some issues while running 'npm ls' command:\n" + strings.TrimSpace(string(errData)))
+	}
+	return parseNpmWorkspaceLsOutput(data, workspace, parseFunc, log)
+}
+
+// Parses the output of 'npm ls --workspace', in which the workspace is a dependency of the monorepo's root, into a dependencies map.
+func parseNpmWorkspaceLsOutput(data []byte, workspace NpmWorkspace, parseFunc func(data []byte) (*npmLsDependency, error), log utils.Log) (map[string]*dependencyInfo, error) {
+	dependenciesMap := make(map[string]*dependencyInfo)
+	workspaceDependencies, _, _, err := jsonparser.Get(data, "dependencies", workspace.Name, "dependencies")
+	if errors.Is(err, jsonparser.KeyPathNotFoundError) {
+		return dependenciesMap, nil
+	}
+	if err != nil {
+		return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed to parse the npm ls output of the workspace %s: %w", workspace.Name, err))
+	}
+	return dependenciesMap, parseDependencies(workspaceDependencies, []string{workspace.ModuleId}, dependenciesMap, parseFunc, log)
+}
+
+// Builds the dependencies of the workspaces directly from the monorepo's lockfile, in which the workspaces' locations are their paths.
+func calculateNpmWorkspacesDependenciesFromLockfile(srcPath string, workspaces []NpmWorkspace, npmParams NpmTreeDepListParam, log utils.Log) (map[string][]entities.Dependency, error) {
+	lockfile, err := readNpmLockfile(srcPath, log)
+	if err != nil {
+		return nil, err
+	}
+	checksums, err := newNpmChecksumsCalculator("", srcPath, npmParams, log)
+	if err != nil {
+		return nil, err
+	}
+	dependencies := make(map[string][]entities.Dependency, len(workspaces))
+	for _, workspace := range workspaces {
+		dependenciesMap, err := lockfile.dependenciesMap(filepath.ToSlash(workspace.Path), workspace.ModuleId, log)
+		if err != nil {
+			return nil, err
+		}
+		if dependencies[workspace.ModuleId], err = toDependenciesList(dependenciesMap, checksums, npmParams.IntegrityVerification, log); err != nil {
+			return nil, err
+		}
+	}
+	return dependencies, nil
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNpmWorkspaces(t *testing.T) {
+	for _, workspacesField := range []string{`["packages/*", "apps/web"]`, `{"packages": ["packages/*", "apps/web"]}`} {
+		srcPath := t.TempDir()
+		files := map[string]string{
+			"package.json": `{"name": "monorepo", "private": true, "workspaces": ` + workspacesField + `}`,
+			filepath.Join("packages", "core", "package.json"):  `{"name": "@acme/core", "version": "1.2.0"}`,
+			filepath.Join("packages", "utils", "package.json"): `{"name": "utils"}`,
+			filepath.Join("packages", "docs", "README.md"):     "",
+			filepath.Join("apps", "web", "package.json"):       `{"name": "web", "version": "0.1.0"}`,
+		}
+		for path, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(srcPath, path)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(srcPath, path), []byte(content), 0644))
+		}
+		workspaces, err := GetNpmWorkspaces(srcPath)
+		require.NoError(t, err)
+		assert.Equal(t, []NpmWorkspace{
+			{Path: filepath.Join("apps", "web"), Name: "web", ModuleId: "web:0.1.0"},
+			{Path: filepath.Join("packages", "core"), Name: "@acme/core", ModuleId: "acme:core:1.2.0"},
+			{Path: filepath.Join("packages", "utils"), Name: "utils", ModuleId: "utils"},
+		}, workspaces)
+	}
+
+	srcPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, "package.json"), []byte(`{"name": "app", "version": "1.0.0"}`), 0644))
+	workspaces, err := GetNpmWorkspaces(srcPath)
+	require.NoError(t, err)
+	assert.Empty(t, workspaces)
+}
+
+func TestParseNpmWorkspaceLsOutput(t *testing.T) {
+	output := `{
+  "name": "monorepo",
+  "dependencies": {
+    "@acme/core": {
+      "version": "1.2.0",
+      "resolved": "file:../packages/core",
+      "dependencies": {
+        "lodash": {"name": "lodash", "version": "4.17.21", "integrity": "sha512-lodash"},
+        "debug": {"name": "debug", "version": "4.3.4", "integrity": "sha512-debug", "dependencies": {"ms": {"name": "ms", "version": "2.1.2", "integrity": "sha512-ms"}}}
+      }
+    }
+  }
+}`
+	workspace := NpmWorkspace{Path: filepath.Join("packages", "core"), Name: "@acme/core", ModuleId: "acme:core:1.2.0"}
+	dependencies, err := parseNpmWorkspaceLsOutput([]byte(output), workspace, npmLsDependencyParser, &utils.NullLog{})
+	require.NoError(t, err)
+	assert.Len(t, dependencies, 3)
+	assert.Equal(t, [][]string{{"acme:core:1.2.0"}}, dependencies["lodash:4.17.21"].RequestedBy)
+	assert.Equal(t, [][]string{{"debug:4.3.4", "acme:core:1.2.0"}}, dependencies["ms:2.1.2"].RequestedBy)
+
+	// A workspace without dependencies.
+	dependencies, err = parseNpmWorkspaceLsOutput([]byte(`{"name": "monorepo", "dependencies": {"@acme/core": {"version": "1.2.0"}}}`), workspace, npmLsDependencyParser, &utils.NullLog{})
+	require.NoError(t, err)
+	assert.Empty(t, dependencies)
+}
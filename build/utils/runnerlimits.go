@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/build-info-go/utils"
+)
+
+// The memory limits of cgroups v1 above this value stand for an unlimited memory, which is the maximal int64 rounded down to the page size.
+const cgroupV1UnlimitedMemory = 1 << 62
+
+// RunnerLimits are the resources of the machine or the container which runs the build.
+type RunnerLimits struct {
+	// The number of logical CPUs of the machine.
+	Cpus int
+	// The version of the cgroup hierarchy, 1 or 2. Zero if no cgroups are mounted, for example on macOS and Windows.
+	CgroupVersion int
+	// The CPU quota of the cgroup, in CPUs, for example 1.5. Zero if it's unlimited.
+	CpuLimit float64
+	// The memory limit of the cgroup, in bytes. Zero if it's unlimited.
+	MemoryLimit int64
+	// The container runtime, for example: docker, podman, containerd, lxc or kubernetes. Empty if the build doesn't run in a container.
+	ContainerRuntime string
+}
+
+// GetRunnerLimits detects the CPU and memory limits of the cgroup of the process, and the container runtime which runs it.
+// With cgroups v2, the effective limits are the lowest limits of the process's cgroup and its ancestors.
+// With cgroups v1, the limits of the hierarchies' mount points are read, which are the container's cgroups in a container.
+func GetRunnerLimits() (*RunnerLimits, error) {
+	return readRunnerLimits(string(filepath.Separator))
+}
+
+// Reads the runner limits from the cgroup and proc file systems under the root directory.
+func readRunnerLimits(root string) (limits *RunnerLimits, err error) {
+	limits = &RunnerLimits{Cpus: runtime.NumCPU()}
+	cgroupRoot := filepath.Join(root, "sys", "fs", "cgroup")
+	selfCgroup, err := readOptionalFile(filepath.Join(root, "proc", "self", "cgroup"))
+	if err != nil {
+		return nil, err
+	}
+	if exists, _ := utils.IsFileExists(filepath.Join(cgroupRoot, "cgroup.controllers"), false); exists {
+		limits.CgroupVersion = 2
+		err = readCgroupV2Limits(limits, cgroupRoot, selfCgroup)
+	} else if exists, _ = utils.IsDirExists(filepath.Join(cgroupRoot, "memory"), true); exists {
+		limits.CgroupVersion = 1
+		err = readCgroupV1Limits(limits, cgroupRoot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	limits.ContainerRuntime = detectContainerRuntime(root, selfCgroup)
+	return limits, nil
+}
+
+// Reads the cpu.max and memory.max files of the process's cgroup and its ancestors.
+// If the process's cgroup isn't found in the mount, for example in a container without a cgroup namespace, the root cgroup of the mount is read.
+func readCgroupV2Limits(limits *RunnerLimits, cgroupRoot, selfCgroup string) error {
+	dir := cgroupRoot
+	for _, line := range strings.Split(selfCgroup, "\n") {
+		if cgroupPath, found := strings.CutPrefix(line, "0::"); found {
+			candidate := filepath.Join(cgroupRoot, cgroupPath)
+			if exists, _ := utils.IsDirExists(candidate, true); exists && strings.HasPrefix(candidate, cgroupRoot) {
+				dir = candidate
+			}
+			break
+		}
+	}
+	for {
+		cpuMax, err := readOptionalFile(filepath.Join(dir, "cpu.max"))
+		if err != nil {
+			return err
+		}
+		// The format is '<quota> <period>', in which the quota is 'max' if it's unlimited.
+		if quota, period, found := strings.Cut(cpuMax, " "); found && quota != "max" {
+			cpuLimit, err := parseCpuQuota(quota, period, filepath.Join(dir, "cpu.max"))
+			if err != nil {
+				return err
+			}
+			limits.CpuLimit = lowestLimit(limits.CpuLimit, cpuLimit)
+		}
+		memoryMax, err := readOptionalFile(filepath.Join(dir, "memory.max"))
+		if err != nil {
+			return err
+		}
+		if memoryMax != "" && memoryMax != "max" {
+			memoryLimit, err := strconv.ParseInt(memoryMax, 10, 64)
+			if err != nil {
+				return utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing the memory limit in '%s': %w", filepath.Join(dir, "memory.max"), err))
+			}
+			limits.MemoryLimit = lowestLimit(limits.MemoryLimit, memoryLimit)
+		}
+		if dir == cgroupRoot {
+			return nil
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// Reads the CFS quota of the cpu hierarchy and the memory limit of the memory hierarchy.
+func readCgroupV1Limits(limits *RunnerLimits, cgroupRoot string) error {
+	quota, err := readOptionalFile(filepath.Join(cgroupRoot, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		return err
+	}
+	// The quota is -1 if it's unlimited.
+	if quota != "" && !strings.HasPrefix(quota, "-") {
+		period, err := readOptionalFile(filepath.Join(cgroupRoot, "cpu", "cpu.cfs_period_us"))
+		if err != nil {
+			return err
+		}
+		if limits.CpuLimit, err = parseCpuQuota(quota, period, filepath.Join(cgroupRoot, "cpu")); err != nil {
+			return err
+		}
+	}
+	memoryLimitPath := filepath.Join(cgroupRoot, "memory", "memory.limit_in_bytes")
+	memoryLimit, err := readOptionalFile(memoryLimitPath)
+	if err != nil || memoryLimit == "" {
+		return err
+	}
+	if limits.MemoryLimit, err = strconv.ParseInt(memoryLimit, 10, 64); err != nil {
+		return utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing the memory limit in '%s': %w", memoryLimitPath, err))
+	}
+	if limits.MemoryLimit >= cgroupV1UnlimitedMemory {
+		limits.MemoryLimit = 0
+	}
+	return nil
+}
+
+// Returns the CPU quota in CPUs, which is the quota divided by the period.
+func parseCpuQuota(quota, period, path string) (float64, error) {
+	quotaValue, err := strconv.ParseFloat(quota, 64)
+	if err != nil {
+		return 0, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing the CPU quota in '%s': %w", path, err))
+	}
+	periodValue, err := strconv.ParseFloat(period, 64)
+	if err != nil || periodValue <= 0 {
+		return 0, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing the CPU period in '%s': '%s'", path, period))
+	}
+	return quotaValue / periodValue, nil
+}
+
+// Returns the lower of the limits, in which zero stands for an unlimited resource.
+func lowestLimit[T int64 | float64](current, limit T) T {
+	if current == 0 || limit < current {
+		return limit
+	}
+	return current
+}
+
+// Detects the container runtime by the files which the runtimes create in their containers, by the 'container' environment variable
+// which some of them set, and by the names of the process's cgroups.
+func detectContainerRuntime(root, selfCgroup string) string {
+	if exists, _ := utils.IsFileExists(filepath.Join(root, "run", ".containerenv"), false); exists {
+		return "podman"
+	}
+	if exists, _ := utils.IsFileExists(filepath.Join(root, ".dockerenv"), false); exists {
+		return "docker"
+	}
+	if container := os.Getenv("container"); container != "" {
+		return container
+	}
+	scanner := bufio.NewScanner(strings.NewReader(selfCgroup))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.Contains(line, "kubepods"):
+			return "kubernetes"
+		case strings.Contains(line, "libpod"):
+			return "podman"
+		case strings.Contains(line, "docker"):
+			return "docker"
+		case strings.Contains(line, "containerd"):
+			return "containerd"
+		case strings.Contains(line, "lxc"):
+			return "lxc"
+		}
+	}
+	// Kubernetes sets this variable in the containers of its pods, whose cgroups are hidden by the cgroup namespace with cgroups v2.
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return "kubernetes"
+	}
+	return ""
+}
+
+// Returns the trimmed content of the file, or an empty string if it doesn't exist.
+func readOptionalFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
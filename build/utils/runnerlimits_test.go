@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRunnerFiles(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for path, content := range files {
+		filePath := filepath.Join(root, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+	}
+	return root
+}
+
+func TestReadRunnerLimitsCgroupV2(t *testing.T) {
+	t.Setenv("container", "")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	// The effective limits are the lowest limits of the process's cgroup and its ancestors.
+	root := writeRunnerFiles(t, map[string]string{
+		"proc/self/cgroup":                                  "0::/kubepods/pod1/container1\n",
+		"sys/fs/cgroup/cgroup.controllers":                  "cpu memory\n",
+		"sys/fs/cgroup/kubepods/pod1/cpu.max":               "max 100000\n",
+		"sys/fs/cgroup/kubepods/pod1/memory.max":            "2147483648\n",
+		"sys/fs/cgroup/kubepods/pod1/container1/cpu.max":    "150000 100000\n",
+		"sys/fs/cgroup/kubepods/pod1/container1/memory.max": "max\n",
+	})
+	limits, err := readRunnerLimits(root)
+	require.NoError(t, err)
+	assert.Equal(t, &RunnerLimits{Cpus: runtime.NumCPU(), CgroupVersion: 2, CpuLimit: 1.5, MemoryLimit: 2147483648, ContainerRuntime: "kubernetes"}, limits)
+
+	// Without a cgroup namespace, the process's cgroup isn't found in the mount, and its root cgroup is read.
+	root = writeRunnerFiles(t, map[string]string{
+		".dockerenv":                       "",
+		"proc/self/cgroup":                 "0::/system.slice/docker-123.scope\n",
+		"sys/fs/cgroup/cgroup.controllers": "cpu memory\n",
+		"sys/fs/cgroup/memory.max":         "1073741824\n",
+	})
+	limits, err = readRunnerLimits(root)
+	require.NoError(t, err)
+	assert.Equal(t, &RunnerLimits{Cpus: runtime.NumCPU(), CgroupVersion: 2, MemoryLimit: 1073741824, ContainerRuntime: "docker"}, limits)
+}
+
+func TestReadRunnerLimitsCgroupV1(t *testing.T) {
+	t.Setenv("container", "")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	root := writeRunnerFiles(t, map[string]string{
+		"proc/self/cgroup":                           "4:memory:/docker/abc\n3:cpu,cpuacct:/docker/abc\n",
+		"sys/fs/cgroup/cpu/cpu.cfs_quota_us":         "200000\n",
+		"sys/fs/cgroup/cpu/cpu.cfs_period_us":        "100000\n",
+		"sys/fs/cgroup/memory/memory.limit_in_bytes": "536870912\n",
+	})
+	limits, err := readRunnerLimits(root)
+	require.NoError(t, err)
+	assert.Equal(t, &RunnerLimits{Cpus: runtime.NumCPU(), CgroupVersion: 1, CpuLimit: 2, MemoryLimit: 536870912, ContainerRuntime: "docker"}, limits)
+
+	// Unlimited resources.
+	root = writeRunnerFiles(t, map[string]string{
+		"sys/fs/cgroup/cpu/cpu.cfs_quota_us":         "-1\n",
+		"sys/fs/cgroup/cpu/cpu.cfs_period_us":        "100000\n",
+		"sys/fs/cgroup/memory/memory.limit_in_bytes": "9223372036854771712\n",
+	})
+	limits, err = readRunnerLimits(root)
+	require.NoError(t, err)
+	assert.Equal(t, &RunnerLimits{Cpus: runtime.NumCPU(), CgroupVersion: 1}, limits)
+
+	root = writeRunnerFiles(t, map[string]string{"sys/fs/cgroup/memory/memory.limit_in_bytes": "unlimited\n"})
+	_, err = readRunnerLimits(root)
+	assert.Equal(t, utils.ParseFailure, utils.GetErrorCategory(err))
+}
+
+func TestReadRunnerLimitsWithoutCgroups(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("container", "lxc")
+	limits, err := readRunnerLimits(t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, &RunnerLimits{Cpus: runtime.NumCPU(), ContainerRuntime: "lxc"}, limits)
+
+	t.Setenv("container", "")
+	limits, err = readRunnerLimits(t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, &RunnerLimits{Cpus: runtime.NumCPU()}, limits)
+}
@@ -0,0 +1,203 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// Matches the summary line of pytest, for example: ===== 3 passed, 1 failed, 2 skipped, 1 warning in 1.23s =====
+	pytestSummaryRegex = regexp.MustCompile(`^=+ (.+) in ([\d.]+)s(?: \([^)]*\))? =+$`)
+	// Matches a count in the summary line of pytest, for example: 3 passed
+	pytestCountRegex = regexp.MustCompile(`(\d+) (passed|failed|skipped|errors?|xfailed|xpassed)\b`)
+)
+
+// TestResults summarizes the results of a test run.
+type TestResults struct {
+	Passed   int
+	Failed   int
+	Skipped  int
+	Duration time.Duration
+}
+
+// Add adds the results of another test run to these results.
+func (tr *TestResults) Add(other TestResults) {
+	tr.Passed += other.Passed
+	tr.Failed += other.Failed
+	tr.Skipped += other.Skipped
+	tr.Duration += other.Duration
+}
+
+// ReadTestReport reads a test report, and returns the summary of its results.
+// The supported formats are JUnit XML, the output of 'go test -json', and the output of pytest, which ends with its summary line.
+// The format is detected from the report's content.
+func ReadTestReport(reportPath string) (TestResults, error) {
+	content, err := os.ReadFile(reportPath)
+	if err != nil {
+		return TestResults{}, err
+	}
+	var results TestResults
+	switch trimmed := bytes.TrimSpace(content); {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		results, err = ParseJUnitXmlReport(bytes.NewReader(content))
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		results, err = ParseGoTestJsonReport(bytes.NewReader(content))
+	default:
+		results, err = ParsePytestReport(bytes.NewReader(content))
+	}
+	if err != nil {
+		return TestResults{}, fmt.Errorf("failed to parse the test report %s: %w", reportPath, err)
+	}
+	return results, nil
+}
+
+// A test suite in a JUnit XML report. The report's root element may be either <testsuites> or <testsuite>, and the suites may be nested.
+type junitTestSuite struct {
+	Time       string           `xml:"time,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+	TestCases  []junitTestCase  `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Time    string    `xml:"time,attr"`
+	Failure *struct{} `xml:"failure"`
+	Error   *struct{} `xml:"error"`
+	Skipped *struct{} `xml:"skipped"`
+}
+
+// ParseJUnitXmlReport parses a JUnit XML report, as written by Maven Surefire, Gradle, pytest (--junitxml), go-junit-report and others.
+// Test cases with a failure or an error are counted as failed.
+func ParseJUnitXmlReport(report io.Reader) (TestResults, error) {
+	var root junitTestSuite
+	if err := xml.NewDecoder(report).Decode(&root); err != nil {
+		return TestResults{}, err
+	}
+	return root.results(), nil
+}
+
+// Returns the results of the suite's test cases and nested suites.
+// The suite's duration is its time attribute, or the total duration of its test cases and nested suites if it has none.
+func (ts *junitTestSuite) results() (results TestResults) {
+	for _, testCase := range ts.TestCases {
+		switch {
+		case testCase.Failure != nil || testCase.Error != nil:
+			results.Failed++
+		case testCase.Skipped != nil:
+			results.Skipped++
+		default:
+			results.Passed++
+		}
+		results.Duration += parseJUnitTime(testCase.Time)
+	}
+	for _, testSuite := range ts.TestSuites {
+		results.Add(testSuite.results())
+	}
+	if duration := parseJUnitTime(ts.Time); duration > 0 {
+		results.Duration = duration
+	}
+	return
+}
+
+// Parses a time attribute, in seconds. Some tools write the thousands separator, for example: 1,234.5
+func parseJUnitTime(value string) time.Duration {
+	seconds, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// An event in the output of 'go test -json'.
+type goTestEvent struct {
+	Action  string  `json:"Action"`
+	Package string  `json:"Package"`
+	Test    string  `json:"Test"`
+	Elapsed float64 `json:"Elapsed"`
+}
+
+// ParseGoTestJsonReport parses the output of 'go test -json'.
+// Only the top-level tests are counted, since the result of a test already reflects the results of its subtests.
+// The duration is the total duration of the tested packages.
+func ParseGoTestJsonReport(report io.Reader) (results TestResults, err error) {
+	scanner := bufio.NewScanner(report)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		// The output may contain lines which aren't events, such as build errors.
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var event goTestEvent
+		if err = json.Unmarshal(line, &event); err != nil {
+			return TestResults{}, err
+		}
+		if event.Test == "" {
+			if event.Action == "pass" || event.Action == "fail" {
+				results.Duration += time.Duration(event.Elapsed * float64(time.Second))
+			}
+			continue
+		}
+		if strings.Contains(event.Test, "/") {
+			continue
+		}
+		switch event.Action {
+		case "pass":
+			results.Passed++
+		case "fail":
+			results.Failed++
+		case "skip":
+			results.Skipped++
+		}
+	}
+	return results, scanner.Err()
+}
+
+// ParsePytestReport parses the output of pytest, using its last summary line, for example: ===== 3 passed, 1 failed in 1.23s =====
+// The errors are counted as failed, the expected failures (xfailed) as skipped, and the unexpected passes (xpassed) as passed.
+func ParsePytestReport(report io.Reader) (TestResults, error) {
+	var summary []string
+	scanner := bufio.NewScanner(report)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if match := pytestSummaryRegex.FindStringSubmatch(strings.TrimSpace(scanner.Text())); match != nil {
+			summary = match
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return TestResults{}, err
+	}
+	if summary == nil {
+		return TestResults{}, errors.New("the report isn't a JUnit XML report, the output of 'go test -json' or the output of pytest")
+	}
+	var results TestResults
+	for _, count := range pytestCountRegex.FindAllStringSubmatch(summary[1], -1) {
+		value, err := strconv.Atoi(count[1])
+		if err != nil {
+			return TestResults{}, err
+		}
+		switch count[2] {
+		case "passed", "xpassed":
+			results.Passed += value
+		case "failed", "error", "errors":
+			results.Failed += value
+		case "skipped", "xfailed":
+			results.Skipped += value
+		}
+	}
+	seconds, err := strconv.ParseFloat(summary[2], 64)
+	if err != nil {
+		return TestResults{}, err
+	}
+	results.Duration = time.Duration(seconds * float64(time.Second))
+	return results, nil
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTestReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		report   string
+		expected TestResults
+	}{
+		{
+			name: "junit-testsuites",
+			report: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="com.example.AppTest" tests="3" time="1.5">
+    <testcase name="passes" time="0.5"/>
+    <testcase name="fails" time="0.5"><failure message="expected"/></testcase>
+    <testcase name="skipped"><skipped/></testcase>
+  </testsuite>
+  <testsuite name="com.example.LibTest" tests="2">
+    <testcase name="passes" time="0.25"/>
+    <testcase name="errors" time="0.25"><error message="boom"/></testcase>
+  </testsuite>
+</testsuites>`,
+			expected: TestResults{Passed: 2, Failed: 2, Skipped: 1, Duration: 2 * time.Second},
+		},
+		{
+			name:     "junit-testsuite",
+			report:   `<testsuite name="pytest" time="1,000.5"><testcase name="a"/><testcase name="b"/></testsuite>`,
+			expected: TestResults{Passed: 2, Duration: 1000500 * time.Millisecond},
+		},
+		{
+			name: "go-test-json",
+			report: `{"Action":"run","Package":"example.com/app","Test":"TestA"}
+{"Action":"pass","Package":"example.com/app","Test":"TestA/sub","Elapsed":0.1}
+{"Action":"pass","Package":"example.com/app","Test":"TestA","Elapsed":0.1}
+{"Action":"fail","Package":"example.com/app","Test":"TestB","Elapsed":0.2}
+{"Action":"skip","Package":"example.com/app","Test":"TestC","Elapsed":0}
+{"Action":"fail","Package":"example.com/app","Elapsed":0.5}
+{"Action":"pass","Package":"example.com/lib","Elapsed":1.5}
+`,
+			expected: TestResults{Passed: 1, Failed: 1, Skipped: 1, Duration: 2 * time.Second},
+		},
+		{
+			name: "pytest",
+			report: `============================= test session starts ==============================
+collected 8 items
+
+tests/test_app.py ..F.sxE.                                               [100%]
+
+=========== 1 failed, 4 passed, 1 skipped, 1 xfailed, 1 error, 2 warnings in 1.25s ===========
+`,
+			expected: TestResults{Passed: 4, Failed: 2, Skipped: 2, Duration: 1250 * time.Millisecond},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reportPath := filepath.Join(t.TempDir(), "report")
+			require.NoError(t, os.WriteFile(reportPath, []byte(test.report), 0644))
+			results, err := ReadTestReport(reportPath)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, results)
+		})
+	}
+
+	reportPath := filepath.Join(t.TempDir(), "report.txt")
+	require.NoError(t, os.WriteFile(reportPath, []byte("ok  \texample.com/app\t0.5s\n"), 0644))
+	_, err := ReadTestReport(reportPath)
+	assert.Error(t, err)
+}
@@ -0,0 +1,254 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jfrog/build-info-go/utils"
+)
+
+const (
+	VcpkgManifestFileName = "vcpkg.json"
+	VcpkgLockFileName     = "vcpkg-lock.json"
+	// The directory into which vcpkg installs the dependencies of a manifest, in the project's directory or in the CMake build directory.
+	VcpkgInstalledDirName = "vcpkg_installed"
+)
+
+// VcpkgManifest is a parsed vcpkg.json.
+type VcpkgManifest struct {
+	Name string `json:"name,omitempty"`
+	// The version of the project, in one of the version fields of vcpkg.json.
+	Version         string `json:"version,omitempty"`
+	VersionSemver   string `json:"version-semver,omitempty"`
+	VersionDate     string `json:"version-date,omitempty"`
+	VersionString   string `json:"version-string,omitempty"`
+	BuiltinBaseline string `json:"builtin-baseline,omitempty"`
+	// The declared dependencies, each either a port's name, or an object with the port's name and constraints.
+	Dependencies []json.RawMessage `json:"dependencies,omitempty"`
+	Overrides    []VcpkgOverride   `json:"overrides,omitempty"`
+}
+
+type VcpkgOverride struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// VcpkgManifestDependency is a dependency declared in a vcpkg.json.
+type VcpkgManifestDependency struct {
+	Name string `json:"name,omitempty"`
+	// The minimum version of the port, for example: 10.2.1
+	MinimumVersion string `json:"version>=,omitempty"`
+	// A host dependency is installed for the host triplet, since it's used by the build, such as a build tool.
+	Host bool `json:"host,omitempty"`
+}
+
+// ReadVcpkgManifest reads and parses the vcpkg.json in the provided directory.
+func ReadVcpkgManifest(dir string) (*VcpkgManifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, VcpkgManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &VcpkgManifest{}
+	if err = json.Unmarshal(content, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// GetVersion returns the version of the project declared in its vcpkg.json, in any of the version fields.
+func (manifest *VcpkgManifest) GetVersion() string {
+	for _, version := range []string{manifest.Version, manifest.VersionSemver, manifest.VersionDate, manifest.VersionString} {
+		if version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// GetDependencies returns the dependencies declared in the vcpkg.json.
+func (manifest *VcpkgManifest) GetDependencies() ([]VcpkgManifestDependency, error) {
+	var dependencies []VcpkgManifestDependency
+	for _, rawDependency := range manifest.Dependencies {
+		var dependency VcpkgManifestDependency
+		var name string
+		if err := json.Unmarshal(rawDependency, &name); err == nil {
+			dependency.Name = name
+		} else if err = json.Unmarshal(rawDependency, &dependency); err != nil {
+			return nil, err
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, nil
+}
+
+// ReadVcpkgLockedRegistries reads the vcpkg-lock.json in the provided directory, and returns the commit locked for each Git registry, by the registry's URL.
+// Returns an empty map if the project doesn't have a vcpkg-lock.json.
+func ReadVcpkgLockedRegistries(dir string) (map[string]string, error) {
+	registries := make(map[string]string)
+	content, err := os.ReadFile(filepath.Join(dir, VcpkgLockFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return registries, nil
+		}
+		return nil, err
+	}
+	// The commits are locked by the registry's URL and the Git reference, for example: {"https://github.com/microsoft/vcpkg": {"HEAD": "<commit>"}}
+	var lock map[string]map[string]string
+	if err = json.Unmarshal(content, &lock); err != nil {
+		return nil, err
+	}
+	for registry, references := range lock {
+		for _, commit := range references {
+			registries[registry] = commit
+			break
+		}
+	}
+	return registries, nil
+}
+
+// InstalledVcpkgPackage is a package installed in a vcpkg installed tree, with the dependencies of its features.
+type InstalledVcpkgPackage struct {
+	Name        string
+	Version     string
+	PortVersion string
+	Triplet     string
+	// The hash of the package's ABI, which is the key of its archive in the binary cache.
+	Abi string
+	// The dependencies of the package, each in the <name>:<triplet> format.
+	Depends []string
+}
+
+// Id returns the ID of the package: <name>:<version>, or <name>:<version>#<port version> if the port was revised.
+func (pkg *InstalledVcpkgPackage) Id() string {
+	if pkg.PortVersion == "" || pkg.PortVersion == "0" {
+		return pkg.Name + ":" + pkg.Version
+	}
+	return pkg.Name + ":" + pkg.Version + "#" + pkg.PortVersion
+}
+
+// ReadVcpkgInstalledPackages reads the status database of a vcpkg installed tree, and returns the installed packages, mapped by <name>:<triplet>.
+func ReadVcpkgInstalledPackages(installedDir string) (map[string]*InstalledVcpkgPackage, error) {
+	content, err := os.ReadFile(filepath.Join(installedDir, "vcpkg", "status"))
+	if err != nil {
+		return nil, err
+	}
+	return parseVcpkgStatus(string(content)), nil
+}
+
+// Parses the paragraphs of the status database, each of a package or of one of its features.
+// The paragraphs of the features only add dependencies to their packages.
+func parseVcpkgStatus(content string) map[string]*InstalledVcpkgPackage {
+	packages := make(map[string]*InstalledVcpkgPackage)
+	var features []map[string]string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		fields := parseVcpkgStatusParagraph(paragraph)
+		if fields["Package"] == "" || !strings.HasSuffix(fields["Status"], " installed") {
+			continue
+		}
+		if fields["Feature"] != "" {
+			features = append(features, fields)
+			continue
+		}
+		pkg := &InstalledVcpkgPackage{Name: fields["Package"], Version: fields["Version"], PortVersion: fields["Port-Version"], Triplet: fields["Architecture"], Abi: fields["Abi"]}
+		pkg.Depends = parseVcpkgDepends(fields["Depends"], pkg.Triplet)
+		packages[pkg.Name+":"+pkg.Triplet] = pkg
+	}
+	for _, feature := range features {
+		if pkg := packages[feature["Package"]+":"+feature["Architecture"]]; pkg != nil {
+			for _, dependency := range parseVcpkgDepends(feature["Depends"], pkg.Triplet) {
+				// A feature may depend on other features of its package, for example: curl[openssl]
+				if dependency != pkg.Name+":"+pkg.Triplet && !containsString(pkg.Depends, dependency) {
+					pkg.Depends = append(pkg.Depends, dependency)
+				}
+			}
+		}
+	}
+	return packages
+}
+
+// Parses the fields of a paragraph. A line which starts with a space continues the value of the previous field.
+func parseVcpkgStatusParagraph(paragraph string) map[string]string {
+	fields := make(map[string]string)
+	var lastKey string
+	for _, line := range strings.Split(paragraph, "\n") {
+		if strings.HasPrefix(line, " ") {
+			if lastKey != "" {
+				fields[lastKey] += "\n" + strings.TrimSpace(line)
+			}
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		lastKey = strings.TrimSpace(key)
+		fields[lastKey] = strings.TrimSpace(value)
+	}
+	return fields
+}
+
+// Parses the comma-separated dependencies of a package, for example: vcpkg-cmake:x64-linux, zlib
+// Returns each dependency in the <name>:<triplet> format. The dependencies without a triplet are of the package's triplet.
+func parseVcpkgDepends(depends, triplet string) []string {
+	var dependencies []string
+	for _, dependency := range strings.Split(depends, ",") {
+		dependency = strings.TrimSpace(dependency)
+		// Remove the features of the dependency, for example: curl[ssl]
+		if index := strings.Index(dependency, "["); index >= 0 {
+			if end := strings.Index(dependency, "]"); end > index {
+				dependency = dependency[:index] + dependency[end+1:]
+			}
+		}
+		if dependency == "" {
+			continue
+		}
+		if !strings.Contains(dependency, ":") {
+			dependency += ":" + triplet
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies
+}
+
+// FindVcpkgInstalledDir returns the installed tree of a project in manifest mode: the vcpkg_installed directory of the project,
+// or of the project's 'build' directory, in which the CMake toolchain of vcpkg installs the dependencies.
+// Returns an empty string if neither exists.
+func FindVcpkgInstalledDir(projectDir string) (string, error) {
+	for _, installedDir := range []string{filepath.Join(projectDir, VcpkgInstalledDirName), filepath.Join(projectDir, "build", VcpkgInstalledDirName)} {
+		exists, err := utils.IsDirExists(installedDir, true)
+		if err != nil || exists {
+			return installedDir, err
+		}
+	}
+	return "", nil
+}
+
+// GetVcpkgBinaryCacheDir returns the default binary cache of vcpkg: $VCPKG_DEFAULT_BINARY_CACHE,
+// or the vcpkg/archives directory of the user's cache directory (%LOCALAPPDATA% on Windows).
+func GetVcpkgBinaryCacheDir() (string, error) {
+	if cacheDir := os.Getenv("VCPKG_DEFAULT_BINARY_CACHE"); cacheDir != "" {
+		return cacheDir, nil
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("LOCALAPPDATA"), "vcpkg", "archives"), nil
+	}
+	if cacheDir := os.Getenv("XDG_CACHE_HOME"); cacheDir != "" {
+		return filepath.Join(cacheDir, "vcpkg", "archives"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache", "vcpkg", "archives"), nil
+}
+
+// GetVcpkgArchivePath returns the path of a package's archive in a binary cache, which is <cache>/<first two characters of the ABI hash>/<ABI hash>.zip
+func GetVcpkgArchivePath(binaryCacheDir, abi string) string {
+	if len(abi) < 2 {
+		return ""
+	}
+	return filepath.Join(binaryCacheDir, abi[:2], abi+".zip")
+}
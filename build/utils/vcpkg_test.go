@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVcpkgStatus(t *testing.T) {
+	packages := parseVcpkgStatus(`Package: vcpkg-cmake
+Version: 2024-04-23
+Architecture: x64-linux
+Multi-Arch: same
+Abi: 8b0ed4d4e0d7d8f8b1a2a1a1d5b3a7e7f0c1c2d3e4f5a6b7c8d9e0f1a2b3c4d5
+Type: Port
+Status: install ok installed
+
+Package: fmt
+Version: 10.2.1
+Port-Version: 2
+Depends: vcpkg-cmake:x64-linux
+Architecture: x64-linux
+Multi-Arch: same
+Abi: 4f3c2b1a0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b
+Description: {fmt} is an open-source formatting library
+  providing a fast and safe alternative to C stdio and C++ iostreams.
+Type: Port
+Status: install ok installed
+
+Package: curl
+Version: 8.8.0
+Depends: zlib
+Architecture: x64-linux
+Abi: 1a2b
+Status: install ok installed
+
+Package: curl
+Feature: ssl
+Depends: curl[openssl], openssl
+Architecture: x64-linux
+Status: install ok installed
+
+Package: zlib
+Version: 1.3.1
+Architecture: x64-linux
+Status: purge ok not-installed
+`)
+	assert.Equal(t, map[string]*InstalledVcpkgPackage{
+		"vcpkg-cmake:x64-linux": {Name: "vcpkg-cmake", Version: "2024-04-23", Triplet: "x64-linux", Abi: "8b0ed4d4e0d7d8f8b1a2a1a1d5b3a7e7f0c1c2d3e4f5a6b7c8d9e0f1a2b3c4d5"},
+		"fmt:x64-linux": {Name: "fmt", Version: "10.2.1", PortVersion: "2", Triplet: "x64-linux", Abi: "4f3c2b1a0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b",
+			Depends: []string{"vcpkg-cmake:x64-linux"}},
+		// The dependencies of curl's features are added to its dependencies, and zlib isn't installed.
+		"curl:x64-linux": {Name: "curl", Version: "8.8.0", Triplet: "x64-linux", Abi: "1a2b", Depends: []string{"zlib:x64-linux", "openssl:x64-linux"}},
+	}, packages)
+	assert.Equal(t, "fmt:10.2.1#2", packages["fmt:x64-linux"].Id())
+	assert.Equal(t, "curl:8.8.0", packages["curl:x64-linux"].Id())
+}
+
+func TestReadVcpkgManifest(t *testing.T) {
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, VcpkgManifestFileName), []byte(`{
+  "name": "my-app",
+  "version-semver": "1.0.0",
+  "builtin-baseline": "3426db05b996481ca31e95fff3734cf23e0f51bc",
+  "dependencies": [
+    "fmt",
+    { "name": "vcpkg-cmake", "host": true },
+    { "name": "zlib", "version>=": "1.3.1", "features": ["core"] }
+  ],
+  "overrides": [ { "name": "fmt", "version": "10.1.1" } ]
+}`), 0644))
+	manifest, err := ReadVcpkgManifest(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, "my-app", manifest.Name)
+	assert.Equal(t, "1.0.0", manifest.GetVersion())
+	assert.Equal(t, []VcpkgOverride{{Name: "fmt", Version: "10.1.1"}}, manifest.Overrides)
+	dependencies, err := manifest.GetDependencies()
+	require.NoError(t, err)
+	assert.Equal(t, []VcpkgManifestDependency{{Name: "fmt"}, {Name: "vcpkg-cmake", Host: true}, {Name: "zlib", MinimumVersion: "1.3.1"}}, dependencies)
+
+	registries, err := ReadVcpkgLockedRegistries(projectDir)
+	require.NoError(t, err)
+	assert.Empty(t, registries)
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, VcpkgLockFileName), []byte(`{"https://github.com/microsoft/vcpkg": {"HEAD": "3426db05b996481ca31e95fff3734cf23e0f51bc"}}`), 0644))
+	registries, err = ReadVcpkgLockedRegistries(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"https://github.com/microsoft/vcpkg": "3426db05b996481ca31e95fff3734cf23e0f51bc"}, registries)
+}
+
+func TestGetVcpkgBinaryCacheDir(t *testing.T) {
+	t.Setenv("VCPKG_DEFAULT_BINARY_CACHE", "/tmp/vcpkg-cache")
+	cacheDir, err := GetVcpkgBinaryCacheDir()
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/vcpkg-cache", cacheDir)
+	assert.Equal(t, filepath.Join("/tmp/vcpkg-cache", "4f", "4f3c.zip"), GetVcpkgArchivePath(cacheDir, "4f3c"))
+	assert.Empty(t, GetVcpkgArchivePath(cacheDir, ""))
+}
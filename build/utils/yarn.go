@@ -2,7 +2,6 @@ package utils
 
 import (
 	"bufio"
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -140,15 +139,11 @@ func GetYarnDependencies(executablePath, srcPath string, packageInfo *PackageInf
 func GetVersion(executablePath, srcPath string) (string, error) {
 	command := exec.Command(executablePath, "--version")
 	command.Dir = srcPath
-	outBuffer := bytes.NewBuffer([]byte{})
-	command.Stdout = outBuffer
-	errBuffer := bytes.NewBuffer([]byte{})
-	command.Stderr = errBuffer
-	err := command.Run()
+	output, _, err := utils.RunCommand(command)
 	if _, ok := err.(*exec.ExitError); ok {
 		err = errors.New("An error occurred while attempting to run the 'yarn --version' command. The executed yarn version could not be retrieved:\n" + err.Error())
 	}
-	return strings.TrimSpace(outBuffer.String()), err
+	return strings.TrimSpace(string(output)), err
 }
 
 // Builds a map of dependencies for Yarn versions < 2.0.0.
@@ -164,7 +159,7 @@ func buildYarnV1DependencyMap(packageInfo *PackageInfo, responseStr string, allo
 	var depTree Yarn1Data
 	err = json.Unmarshal([]byte(responseStr), &depTree)
 	if err != nil {
-		err = errors.New("couldn't parse 'yarn list' results in order to create the dependencyMap:\n" + err.Error())
+		err = utils.NewCategorizedError(utils.ParseFailure, errors.New("couldn't parse 'yarn list' results in order to create the dependencyMap:\n"+err.Error()))
 		return
 	}
 
@@ -272,13 +267,9 @@ func runYarnInfoOrList(executablePath string, srcPath string, v2AndAbove bool) (
 		command = exec.Command(executablePath, "list", "--json", "--flat", "--no-progress")
 	}
 	command.Dir = srcPath
-	outBuffer := bytes.NewBuffer([]byte{})
-	command.Stdout = outBuffer
-	errBuffer := bytes.NewBuffer([]byte{})
-	command.Stderr = errBuffer
-	err = command.Run()
-	outResult = strings.TrimSpace(outBuffer.String())
-	errResult = errBuffer.String()
+	output, errOutput, err := utils.RunCommand(command)
+	outResult = strings.TrimSpace(string(output))
+	errResult = string(errOutput)
 
 	if err != nil {
 		// urfave/cli (aka codegangsta) exits when an ExitError is returned, so if it's an ExitError we'll convert it to a regular error.
@@ -378,6 +369,17 @@ type YarnDependency struct {
 	Details YarnDepDetails `json:"children,omitempty"`
 }
 
+// IsLocal returns true if the dependency is installed from a local directory or tarball, by the file:, link: or portal: protocols of Yarn 2 and above,
+// whose content may change without a change to its version.
+func (yd *YarnDependency) IsLocal() bool {
+	name, err := yd.Name()
+	if err != nil {
+		return false
+	}
+	reference := strings.TrimPrefix(yd.Value, name+"@")
+	return strings.HasPrefix(reference, "file:") || strings.HasPrefix(reference, "link:") || strings.HasPrefix(reference, "portal:")
+}
+
 func (yd *YarnDependency) Name() (string, error) {
 	if yd.Value == "" {
 		return "", fmt.Errorf("got an empty name yarn dependency: %+v", yd)
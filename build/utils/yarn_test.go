@@ -214,6 +214,23 @@ func TestYarnDependency_Name(t *testing.T) {
 	}
 }
 
+func TestYarnDependency_IsLocal(t *testing.T) {
+	testCases := []struct {
+		packageFullName string
+		expectedLocal   bool
+	}{
+		{"json@npm:1.2.3", false},
+		{"utils@file:../utils::locator=app%40workspace%3A.", true},
+		{"@jfrog/utils@link:../utils::locator=app%40workspace%3A.", true},
+		{"@jfrog/utils@portal:../utils::locator=app%40workspace%3A.", true},
+		{"app", false},
+	}
+	for _, testCase := range testCases {
+		yarnDep := YarnDependency{Value: testCase.packageFullName}
+		assert.Equal(t, testCase.expectedLocal, yarnDep.IsLocal(), testCase.packageFullName)
+	}
+}
+
 func TestSplitNameAndVersion(t *testing.T) {
 	testCases := []struct {
 		packageFullName string
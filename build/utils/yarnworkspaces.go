@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/build-info-go/utils"
+)
+
+const yarnWorkspaceProtocol = "@workspace:"
+
+// YarnWorkspace is a workspace of a Yarn project, as listed by the 'yarn workspaces list' command.
+// The root of the project is a workspace too, whose location is '.'.
+type YarnWorkspace struct {
+	// The workspace's directory, relative to the root of the project, with forward slashes.
+	Location string `json:"location"`
+	// The full name of the workspace's package, including its scope.
+	Name string `json:"name"`
+}
+
+// Key returns the key of the workspace in the dependencies map returned from GetYarnDependencies, for example: @scope/package-name@workspace:packages/package-name
+func (yw *YarnWorkspace) Key() string {
+	return yw.Name + yarnWorkspaceProtocol + yw.Location
+}
+
+// ModuleId returns the ID of the workspace's build-info module, read from the package.json in the workspace's directory.
+func (yw *YarnWorkspace) ModuleId(srcPath string) (string, error) {
+	packageInfo, err := ReadPackageInfoFromPackageJsonIfExists(filepath.Join(srcPath, filepath.FromSlash(yw.Location)), nil)
+	if err != nil {
+		return "", err
+	}
+	moduleId := packageInfo.BuildInfoModuleId()
+	if moduleId == "" {
+		// Workspaces which are never published may have no version.
+		moduleId = strings.TrimPrefix(packageInfo.FullName(), "@")
+	}
+	return moduleId, nil
+}
+
+// GetYarnWorkspaces runs 'yarn workspaces list --json' in the Yarn project in srcPath, and returns its workspaces, including the project's root.
+// The command is supported by Yarn 2.0.0 and above.
+func GetYarnWorkspaces(executablePath, srcPath string) ([]YarnWorkspace, error) {
+	command := exec.Command(executablePath, "workspaces", "list", "--json")
+	command.Dir = srcPath
+	output, errOutput, err := utils.RunCommand(command)
+	if err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok {
+			err = fmt.Errorf("failed running 'yarn workspaces list': %s\n%s", exitError.Error(), strings.TrimSpace(string(errOutput)))
+		}
+		return nil, err
+	}
+	return parseYarnWorkspacesList(string(output))
+}
+
+// Parses the output of 'yarn workspaces list --json', in which each line is a JSON object of a workspace.
+func parseYarnWorkspacesList(output string) (workspaces []YarnWorkspace, err error) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var workspace YarnWorkspace
+		if err = json.Unmarshal([]byte(line), &workspace); err != nil {
+			return nil, utils.NewCategorizedError(utils.ParseFailure, fmt.Errorf("failed parsing the output of 'yarn workspaces list': %w", err))
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	return workspaces, scanner.Err()
+}
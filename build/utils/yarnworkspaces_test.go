@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseYarnWorkspacesList(t *testing.T) {
+	output := `{"location":".","name":"monorepo"}
+{"location":"packages/core","name":"@acme/core"}
+{"location":"packages/app","name":"app"}
+`
+	workspaces, err := parseYarnWorkspacesList(output)
+	require.NoError(t, err)
+	assert.Equal(t, []YarnWorkspace{{Location: ".", Name: "monorepo"}, {Location: "packages/core", Name: "@acme/core"}, {Location: "packages/app", Name: "app"}}, workspaces)
+	assert.Equal(t, "@acme/core@workspace:packages/core", workspaces[1].Key())
+
+	_, err = parseYarnWorkspacesList("not json")
+	assert.Error(t, err)
+}
+
+func TestYarnWorkspaceModuleId(t *testing.T) {
+	srcPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcPath, "packages", "core"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(srcPath, "packages", "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, "packages", "core", "package.json"), []byte(`{"name": "@acme/core", "version": "2.0.0"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, "packages", "app", "package.json"), []byte(`{"name": "app", "private": true}`), 0644))
+
+	moduleId, err := (&YarnWorkspace{Location: "packages/core", Name: "@acme/core"}).ModuleId(srcPath)
+	require.NoError(t, err)
+	assert.Equal(t, "acme:core:2.0.0", moduleId)
+	moduleId, err = (&YarnWorkspace{Location: "packages/app", Name: "app"}).ModuleId(srcPath)
+	require.NoError(t, err)
+	assert.Equal(t, "app", moduleId)
+}
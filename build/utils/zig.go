@@ -0,0 +1,321 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"unicode"
+)
+
+const ZigManifestFileName = "build.zig.zon"
+
+// ZigManifest is a parsed build.zig.zon.
+type ZigManifest struct {
+	Name    string
+	Version string
+	// The manifest's dependencies, by their names.
+	Dependencies map[string]*ZigDependency
+}
+
+// ZigDependency is a dependency declared in a build.zig.zon, fetched from a URL, or found in a local path.
+type ZigDependency struct {
+	Url string
+	// The hash of the package's contents, which identifies it in the global cache, for example: 1220<sha-256> (Zig 0.13 and below),
+	// or <name>-<version>-<hash> (Zig 0.14 and above).
+	Hash string
+	// The path of a local package, relative to the manifest's directory.
+	Path string
+	// A lazy dependency is only fetched when the build needs it.
+	Lazy bool
+}
+
+// ReadZigManifest reads and parses the build.zig.zon in the provided directory.
+func ReadZigManifest(dir string) (*ZigManifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, ZigManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	return parseZigManifest(string(content))
+}
+
+func parseZigManifest(content string) (*ZigManifest, error) {
+	parser := &zonParser{input: content}
+	value, err := parser.parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ZigManifestFileName, err)
+	}
+	fields, ok := value.(zonStruct)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse %s: the manifest isn't a struct", ZigManifestFileName)
+	}
+	manifest := &ZigManifest{Name: zonToString(fields["name"]), Version: zonToString(fields["version"]), Dependencies: make(map[string]*ZigDependency)}
+	dependencies, _ := fields["dependencies"].(zonStruct)
+	for name, value := range dependencies {
+		dependencyFields, ok := value.(zonStruct)
+		if !ok {
+			continue
+		}
+		lazy, _ := dependencyFields["lazy"].(bool)
+		manifest.Dependencies[name] = &ZigDependency{
+			Url:  zonToString(dependencyFields["url"]),
+			Hash: zonToString(dependencyFields["hash"]),
+			Path: zonToString(dependencyFields["path"]),
+			Lazy: lazy,
+		}
+	}
+	return manifest, nil
+}
+
+// The hash format of Zig 0.14 and above: the package's name and version, followed by 33 bytes encoded in base64url, which may contain dashes.
+var zigNamedHashRegex = regexp.MustCompile(`^(.+)-([0-9]+\.[0-9]+\.[0-9]+(?:[-+][0-9A-Za-z.+-]*)?)-[A-Za-z0-9_-]{44}$`)
+
+// GetZigHashVersion returns the version of the package in a Zig 0.14 hash, or an empty string for hashes of other formats.
+func GetZigHashVersion(hash string) string {
+	if match := zigNamedHashRegex.FindStringSubmatch(hash); match != nil {
+		return match[2]
+	}
+	return ""
+}
+
+// GetZigGlobalCacheDir returns the global cache directory of Zig: $ZIG_GLOBAL_CACHE_DIR, or the zig directory of the user's cache directory.
+// The fetched packages are extracted into its 'p/<hash>' directories.
+func GetZigGlobalCacheDir() (string, error) {
+	if cacheDir := os.Getenv("ZIG_GLOBAL_CACHE_DIR"); cacheDir != "" {
+		return cacheDir, nil
+	}
+	if runtime.GOOS == "windows" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(cacheDir, "zig"), nil
+	}
+	// On the other systems, Zig uses XDG_CACHE_HOME, or ~/.cache, even on macOS.
+	if cacheDir := os.Getenv("XDG_CACHE_HOME"); cacheDir != "" {
+		return filepath.Join(cacheDir, "zig"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache", "zig"), nil
+}
+
+// The values of a ZON file: structs with named fields, tuples, strings, enum literals, numbers, booleans and null.
+type (
+	zonStruct map[string]any
+	zonTuple  []any
+	zonEnum   string
+)
+
+// Returns the value of a string or an enum literal. Zig 0.14 and above declare the package's name as an enum literal, for example: .name = .my_package
+func zonToString(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case zonEnum:
+		return string(typed)
+	}
+	return ""
+}
+
+// A parser of ZON, the Zig Object Notation of build.zig.zon.
+type zonParser struct {
+	input    string
+	position int
+}
+
+func (p *zonParser) parse() (any, error) {
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpaces(); p.position < len(p.input) {
+		return nil, p.errorf("unexpected content")
+	}
+	return value, nil
+}
+
+func (p *zonParser) parseValue() (any, error) {
+	p.skipSpaces()
+	if p.position >= len(p.input) {
+		return nil, p.errorf("unexpected end of the input")
+	}
+	switch current := p.input[p.position]; {
+	case strings.HasPrefix(p.input[p.position:], ".{"):
+		p.position += 2
+		return p.parseStructOrTuple()
+	case current == '.':
+		p.position++
+		return zonEnum(p.parseIdentifier()), nil
+	case current == '"':
+		return p.parseString()
+	case strings.HasPrefix(p.input[p.position:], `\\`):
+		return p.parseMultilineString(), nil
+	default:
+		identifier := p.parseIdentifier()
+		switch identifier {
+		case "":
+			return nil, p.errorf("unexpected character '%c'", current)
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		// A number, which is kept as it's written, for example: 0x2fd8c2b6f1a9c8e4
+		return identifier, nil
+	}
+}
+
+// Parses the fields of a struct (.{ .name = value, ... }), or the values of a tuple (.{ value, ... }), until the closing brace.
+func (p *zonParser) parseStructOrTuple() (any, error) {
+	fields := zonStruct{}
+	var values zonTuple
+	for {
+		if p.skipSpaces(); p.position < len(p.input) && p.input[p.position] == '}' {
+			p.position++
+			if values != nil {
+				return values, nil
+			}
+			return fields, nil
+		}
+		if name, isField := p.parseFieldName(); isField {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			fields[name] = value
+		} else {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		p.skipSpaces()
+		if p.position >= len(p.input) {
+			return nil, p.errorf("unexpected end of the input")
+		}
+		switch p.input[p.position] {
+		case ',':
+			p.position++
+		case '}':
+		default:
+			return nil, p.errorf("expected ',' or '}'")
+		}
+	}
+}
+
+// Parses a field's name and the following equals sign: .name = or .@"quoted name" =
+// If the next value isn't a field, the position is restored.
+func (p *zonParser) parseFieldName() (string, bool) {
+	start := p.position
+	if p.position >= len(p.input) || p.input[p.position] != '.' {
+		return "", false
+	}
+	p.position++
+	var name string
+	if strings.HasPrefix(p.input[p.position:], `@"`) {
+		p.position++
+		quoted, err := p.parseString()
+		if err != nil {
+			p.position = start
+			return "", false
+		}
+		name = quoted
+	} else {
+		name = p.parseIdentifier()
+	}
+	p.skipSpaces()
+	if name == "" || p.position >= len(p.input) || p.input[p.position] != '=' {
+		p.position = start
+		return "", false
+	}
+	p.position++
+	return name, true
+}
+
+func (p *zonParser) parseString() (string, error) {
+	if p.position >= len(p.input) || p.input[p.position] != '"' {
+		return "", p.errorf("expected a string")
+	}
+	var builder strings.Builder
+	for p.position++; p.position < len(p.input); p.position++ {
+		switch current := p.input[p.position]; current {
+		case '"':
+			p.position++
+			return builder.String(), nil
+		case '\\':
+			p.position++
+			if p.position < len(p.input) {
+				switch escaped := p.input[p.position]; escaped {
+				case 'n':
+					builder.WriteByte('\n')
+				case 't':
+					builder.WriteByte('\t')
+				case 'r':
+					builder.WriteByte('\r')
+				default:
+					builder.WriteByte(escaped)
+				}
+			}
+		default:
+			builder.WriteByte(current)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// Parses consecutive lines which start with \\, joined by newlines.
+func (p *zonParser) parseMultilineString() string {
+	var lines []string
+	for {
+		p.skipSpaces()
+		if !strings.HasPrefix(p.input[p.position:], `\\`) {
+			return strings.Join(lines, "\n")
+		}
+		end := strings.IndexByte(p.input[p.position:], '\n')
+		if end < 0 {
+			end = len(p.input) - p.position
+		}
+		lines = append(lines, p.input[p.position+2:p.position+end])
+		p.position += end
+	}
+}
+
+func (p *zonParser) parseIdentifier() string {
+	start := p.position
+	for p.position < len(p.input) {
+		current := rune(p.input[p.position])
+		if !unicode.IsLetter(current) && !unicode.IsDigit(current) && current != '_' {
+			break
+		}
+		p.position++
+	}
+	return p.input[start:p.position]
+}
+
+// Skips the whitespace and the comments.
+func (p *zonParser) skipSpaces() {
+	for p.position < len(p.input) {
+		switch {
+		case strings.HasPrefix(p.input[p.position:], "//"):
+			for p.position < len(p.input) && p.input[p.position] != '\n' {
+				p.position++
+			}
+		case unicode.IsSpace(rune(p.input[p.position])):
+			p.position++
+		default:
+			return
+		}
+	}
+}
+
+func (p *zonParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s at offset %d", fmt.Sprintf(format, args...), p.position)
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseZigManifest(t *testing.T) {
+	manifest, err := parseZigManifest(`// The package's manifest.
+.{
+    .name = .my_app,
+    .version = "0.1.0",
+    .fingerprint = 0x2fd8c2b6f1a9c8e4,
+    .minimum_zig_version = "0.14.0",
+    .dependencies = .{
+        .zap = .{
+            .url = "https://github.com/zigzap/zap/archive/refs/tags/v0.8.0.tar.gz",
+            .hash = "12209936c3333b53b53edcf453b1670babb9ae8c2197b1ca627c01e72670e20c1a21",
+        },
+        .@"zig-clap" = .{
+            .url = "git+https://github.com/Hejsil/zig-clap?ref=0.10.0#e47028deaefc2fb396d3d9e9f7bd776ae0b2a43a",
+            .hash = "clap-0.10.0-oBajB434AQBDh-Ei3YtoKIRxZacVPF1iSwp3IX_ZB8f0",
+            .lazy = true,
+        },
+        .local = .{ .path = "libs/local" },
+    },
+    .paths = .{
+        "build.zig",
+        "build.zig.zon",
+        "src",
+    },
+    .description =
+        \\A multiline
+        \\description.
+    ,
+}
+`)
+	require.NoError(t, err)
+	assert.Equal(t, &ZigManifest{
+		Name:    "my_app",
+		Version: "0.1.0",
+		Dependencies: map[string]*ZigDependency{
+			"zap": {
+				Url:  "https://github.com/zigzap/zap/archive/refs/tags/v0.8.0.tar.gz",
+				Hash: "12209936c3333b53b53edcf453b1670babb9ae8c2197b1ca627c01e72670e20c1a21",
+			},
+			"zig-clap": {
+				Url:  "git+https://github.com/Hejsil/zig-clap?ref=0.10.0#e47028deaefc2fb396d3d9e9f7bd776ae0b2a43a",
+				Hash: "clap-0.10.0-oBajB434AQBDh-Ei3YtoKIRxZacVPF1iSwp3IX_ZB8f0",
+				Lazy: true,
+			},
+			"local": {Path: "libs/local"},
+		},
+	}, manifest)
+
+	// Zig 0.13 and below declare the package's name as a string.
+	manifest, err = parseZigManifest(`.{ .name = "legacy", .version = "1.2.3", .dependencies = .{}, .paths = .{""} }`)
+	require.NoError(t, err)
+	assert.Equal(t, &ZigManifest{Name: "legacy", Version: "1.2.3", Dependencies: map[string]*ZigDependency{}}, manifest)
+
+	_, err = parseZigManifest(`.{ .name = "broken"`)
+	assert.ErrorContains(t, err, "failed to parse build.zig.zon")
+}
+
+func TestGetZigHashVersion(t *testing.T) {
+	assert.Equal(t, "0.10.0", GetZigHashVersion("clap-0.10.0-oBajB434AQBDh-Ei3YtoKIRxZacVPF1iSwp3IX_ZB8f0"))
+	assert.Equal(t, "1.0.0-rc.1", GetZigHashVersion("my-pkg-1.0.0-rc.1-AAAAAbcdAAAAAbcdAAAAAbcdAAAAAbcdAAAAAbcd-AAA"))
+	assert.Empty(t, GetZigHashVersion("12209936c3333b53b53edcf453b1670babb9ae8c2197b1ca627c01e72670e20c1a21"))
+}
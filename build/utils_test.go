@@ -5,8 +5,21 @@ import (
 
 	"github.com/jfrog/build-info-go/entities"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// Creates a build whose files are kept in a temporary directory, and are removed when the test ends.
+func createTestBuild(t *testing.T, buildName string) *Build {
+	service := NewBuildInfoService()
+	service.SetTempDirPath(t.TempDir())
+	bld, err := service.GetOrCreateBuild(buildName, "1")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, bld.Clean())
+	})
+	return bld
+}
+
 func validateModule(t *testing.T, module entities.Module, expectedDependencies, expectedArtifacts int, moduleName string, moduleType entities.ModuleType, depsContainChecksums bool) {
 	assert.Equal(t, moduleName, module.Id, "Unexpected module name")
 	assert.Len(t, module.Dependencies, expectedDependencies, "Incorrect number of dependencies found in the build-info")
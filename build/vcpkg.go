@@ -0,0 +1,294 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/crypto"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// The property of a vcpkg dependency, whose value is the hash of the package's ABI, which identifies its archive in the binary cache.
+	VcpkgAbiProperty = "vcpkg.abi"
+	// The module property which holds the builtin-baseline declared in the vcpkg.json.
+	VcpkgBaselineProperty = "buildInfo.vcpkg.baseline"
+	// The module property which holds the comma-separated Git registries locked in the vcpkg-lock.json, each in the <url>@<commit> format.
+	VcpkgRegistriesProperty = "buildInfo.vcpkg.registries"
+	// The value of the entities.LowFidelityProperty of the modules whose dependencies are taken from the vcpkg.json, since they weren't installed.
+	vcpkgManifestFidelity = "vcpkg-manifest"
+)
+
+type VcpkgModule struct {
+	containingBuild *Build
+	name            string
+	srcPath         string
+	installedDir    string
+	// The arguments of a vcpkg command, such as 'install', which runs before the dependencies are collected.
+	vcpkgArgs []string
+	// The duration of the command, if it ran.
+	commandDuration time.Duration
+}
+
+// Pass an empty string for srcPath to find the vcpkg manifest in the working directory.
+func newVcpkgModule(srcPath string, containingBuild *Build) (*VcpkgModule, error) {
+	if srcPath == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		srcPath, err = utils.FindFileInDirAndParents(wd, buildutils.VcpkgManifestFileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &VcpkgModule{srcPath: srcPath, containingBuild: containingBuild}, nil
+}
+
+func (vm *VcpkgModule) SetName(name string) {
+	vm.name = name
+}
+
+// SetInstalledDir sets the vcpkg installed tree of the project. A relative path is relative to the project's directory.
+// The default is the vcpkg_installed directory of the project, or of its 'build' directory.
+func (vm *VcpkgModule) SetInstalledDir(installedDir string) {
+	if installedDir != "" && !filepath.IsAbs(installedDir) {
+		installedDir = filepath.Join(vm.srcPath, installedDir)
+	}
+	vm.installedDir = installedDir
+}
+
+// SetVcpkgArgs sets the arguments of a vcpkg command, for example: install --triplet x64-linux
+// The command runs in the project's directory before the dependencies are collected, so that the dependencies are installed.
+func (vm *VcpkgModule) SetVcpkgArgs(vcpkgArgs []string) {
+	vm.vcpkgArgs = vcpkgArgs
+}
+
+// Build runs the vcpkg command set by SetVcpkgArgs, if any, and then collects the project's dependencies.
+func (vm *VcpkgModule) Build() error {
+	if len(vm.vcpkgArgs) > 0 {
+		command := exec.Command("vcpkg", vm.vcpkgArgs...)
+		command.Dir = vm.srcPath
+		var err error
+		if vm.commandDuration, err = runModuleCommand(command); err != nil {
+			return fmt.Errorf("failed running 'vcpkg %s': %w", strings.Join(vm.vcpkgArgs, " "), err)
+		}
+	}
+	return vm.CalcDependencies()
+}
+
+// CalcDependencies collects the packages installed for the project's vcpkg.json, from the status database of the project's installed tree.
+// The scopes of each dependency are the triplets it was installed for, and its checksums are calculated from its archive in the vcpkg binary cache, if it's cached.
+// If the dependencies weren't installed, the dependencies declared in the vcpkg.json are collected, with the versions of their overrides or their minimum versions.
+func (vm *VcpkgModule) CalcDependencies() error {
+	if !vm.containingBuild.buildNameAndNumberProvided() {
+		return errors.New("a build name must be provided in order to collect the project's dependencies")
+	}
+	manifest, err := buildutils.ReadVcpkgManifest(vm.srcPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("no vcpkg.json was found in " + vm.srcPath)
+		}
+		return err
+	}
+	vm.setModuleId(manifest)
+	declaredDependencies, err := manifest.GetDependencies()
+	if err != nil {
+		return err
+	}
+	buildInfoModule := entities.Module{Id: vm.name, Type: entities.Vcpkg}
+	installedPackages, err := vm.readInstalledPackages()
+	if err != nil {
+		return err
+	}
+	if installedPackages == nil {
+		vm.containingBuild.logger.Warn("The dependencies of the vcpkg.json weren't installed. Only the dependencies declared in the vcpkg.json are collected. Run 'vcpkg install' to collect their resolved versions.")
+		buildInfoModule.Dependencies = vm.getDeclaredDependencies(manifest, declaredDependencies)
+		buildInfoModule.AddProperties(map[string]string{entities.LowFidelityProperty: vcpkgManifestFidelity})
+	} else if buildInfoModule.Dependencies, err = vm.getInstalledDependencies(declaredDependencies, installedPackages); err != nil {
+		return err
+	}
+	moduleProperties, err := vm.getModuleProperties(manifest)
+	if err != nil {
+		return err
+	}
+	buildInfoModule.AddProperties(moduleProperties)
+	addCommandDuration(&buildInfoModule, vm.commandDuration)
+	return vm.containingBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{buildInfoModule}})
+}
+
+// If the module ID wasn't set, it's the name and the version declared in the vcpkg.json, or the name of the project's directory.
+func (vm *VcpkgModule) setModuleId(manifest *buildutils.VcpkgManifest) {
+	if vm.name != "" {
+		return
+	}
+	if manifest.Name != "" {
+		vm.name = manifest.Name
+		if version := manifest.GetVersion(); version != "" {
+			vm.name += ":" + version
+		}
+		return
+	}
+	vm.name = filepath.Base(vm.srcPath)
+	vm.containingBuild.logger.Debug(fmt.Sprintf("The vcpkg.json doesn't declare the project's name. Using its directory name: %s as the module name.", vm.name))
+}
+
+// Returns the packages of the installed tree, or nil if the dependencies weren't installed.
+func (vm *VcpkgModule) readInstalledPackages() (map[string]*buildutils.InstalledVcpkgPackage, error) {
+	installedDir := vm.installedDir
+	if installedDir == "" {
+		var err error
+		if installedDir, err = buildutils.FindVcpkgInstalledDir(vm.srcPath); err != nil || installedDir == "" {
+			return nil, err
+		}
+	}
+	installedPackages, err := buildutils.ReadVcpkgInstalledPackages(installedDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return installedPackages, err
+}
+
+// Returns the installed packages which the declared dependencies require, with their dependencies.
+func (vm *VcpkgModule) getInstalledDependencies(declaredDependencies []buildutils.VcpkgManifestDependency, installedPackages map[string]*buildutils.InstalledVcpkgPackage) ([]entities.Dependency, error) {
+	binaryCacheDir, err := buildutils.GetVcpkgBinaryCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	// A declared dependency is installed for the target triplet, or for the host triplet if it's a host dependency, so it's matched by its name.
+	installedKeys := maps.Keys(installedPackages)
+	sort.Strings(installedKeys)
+	dependenciesMap := make(map[string]entities.Dependency)
+	dependenciesGraph := make(map[string][]string)
+	for _, declaredDependency := range declaredDependencies {
+		for _, key := range installedKeys {
+			if installedPackages[key].Name != declaredDependency.Name {
+				continue
+			}
+			if err = vm.addInstalledPackage(vm.name, installedPackages[key], installedPackages, binaryCacheDir, dependenciesMap, dependenciesGraph); err != nil {
+				return nil, err
+			}
+		}
+	}
+	emptyRequestedBy := [][]string{{}}
+	populateRequestedByField(vm.name, emptyRequestedBy, dependenciesMap, dependenciesGraph)
+	return dependenciesMapToList(dependenciesMap), nil
+}
+
+// Adds an installed package and its dependencies to the dependencies map and graph.
+// A package installed for several triplets is recorded once, and its scopes are the triplets.
+func (vm *VcpkgModule) addInstalledPackage(parentId string, installedPackage *buildutils.InstalledVcpkgPackage, installedPackages map[string]*buildutils.InstalledVcpkgPackage, binaryCacheDir string, dependenciesMap map[string]entities.Dependency, dependenciesGraph map[string][]string) error {
+	id := installedPackage.Id()
+	if !slices.Contains(dependenciesGraph[parentId], id) {
+		dependenciesGraph[parentId] = append(dependenciesGraph[parentId], id)
+	}
+	dependency, exists := dependenciesMap[id]
+	if exists && slices.Contains(dependency.Scopes, installedPackage.Triplet) {
+		return nil
+	}
+	// The ABI and the checksums are of the package's archive for one of its triplets, preferably a cached archive.
+	if !exists || dependency.GetResolutionSource() != entities.CacheSource {
+		scopes := append(dependency.Scopes, installedPackage.Triplet)
+		var err error
+		if dependency, err = vm.getInstalledPackageDetails(installedPackage, binaryCacheDir); err != nil {
+			return err
+		}
+		dependency.Scopes = scopes
+	} else {
+		dependency.Scopes = append(dependency.Scopes, installedPackage.Triplet)
+	}
+	sort.Strings(dependency.Scopes)
+	dependenciesMap[id] = dependency
+	for _, depend := range installedPackage.Depends {
+		dependencyPackage := installedPackages[depend]
+		if dependencyPackage == nil {
+			vm.containingBuild.logger.Debug("The vcpkg package", depend, "which", id, "depends on, isn't installed.")
+			continue
+		}
+		if err := vm.addInstalledPackage(id, dependencyPackage, installedPackages, binaryCacheDir, dependenciesMap, dependenciesGraph); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the dependency of an installed package, with the checksums of its archive in the binary cache, if it's cached.
+func (vm *VcpkgModule) getInstalledPackageDetails(installedPackage *buildutils.InstalledVcpkgPackage, binaryCacheDir string) (entities.Dependency, error) {
+	dependency := entities.Dependency{Id: installedPackage.Id()}
+	dependency.SetResolutionSource(entities.FilesystemSource)
+	if installedPackage.Abi == "" {
+		return dependency, nil
+	}
+	dependency.Properties[VcpkgAbiProperty] = installedPackage.Abi
+	archivePath := buildutils.GetVcpkgArchivePath(binaryCacheDir, installedPackage.Abi)
+	if _, err := os.Stat(archivePath); err != nil {
+		if os.IsNotExist(err) {
+			return dependency, nil
+		}
+		return dependency, err
+	}
+	checksums, err := vm.containingBuild.checksumCache.GetFileChecksums(archivePath)
+	if err != nil {
+		return dependency, err
+	}
+	dependency.Type = "zip"
+	dependency.Checksum = entities.Checksum{Sha1: checksums[crypto.SHA1], Md5: checksums[crypto.MD5], Sha256: checksums[crypto.SHA256]}
+	dependency.SetResolutionSource(entities.CacheSource)
+	return dependency, nil
+}
+
+// Returns the dependencies declared in the vcpkg.json, with the versions of their overrides, or their minimum versions.
+func (vm *VcpkgModule) getDeclaredDependencies(manifest *buildutils.VcpkgManifest, declaredDependencies []buildutils.VcpkgManifestDependency) []entities.Dependency {
+	overrides := make(map[string]string)
+	for _, override := range manifest.Overrides {
+		overrides[override.Name] = override.Version
+	}
+	var dependencies []entities.Dependency
+	for _, declaredDependency := range declaredDependencies {
+		id := declaredDependency.Name
+		if version := overrides[declaredDependency.Name]; version != "" {
+			id += ":" + version
+		} else if declaredDependency.MinimumVersion != "" {
+			id += ":" + declaredDependency.MinimumVersion
+		}
+		dependency := entities.Dependency{Id: id, RequestedBy: [][]string{{vm.name}}}
+		dependency.SetResolutionSource(entities.UnknownSource)
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies
+}
+
+// Returns the builtin-baseline of the vcpkg.json and the Git registries locked in the vcpkg-lock.json, as module properties.
+func (vm *VcpkgModule) getModuleProperties(manifest *buildutils.VcpkgManifest) (map[string]string, error) {
+	properties := make(map[string]string)
+	if manifest.BuiltinBaseline != "" {
+		properties[VcpkgBaselineProperty] = manifest.BuiltinBaseline
+	}
+	lockedRegistries, err := buildutils.ReadVcpkgLockedRegistries(vm.srcPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(lockedRegistries) > 0 {
+		registries := maps.Keys(lockedRegistries)
+		sort.Strings(registries)
+		for i, registry := range registries {
+			registries[i] = registry + "@" + lockedRegistries[registry]
+		}
+		properties[VcpkgRegistriesProperty] = strings.Join(registries, ",")
+	}
+	return properties, nil
+}
+
+func (vm *VcpkgModule) AddArtifacts(artifacts ...entities.Artifact) error {
+	return vm.containingBuild.AddArtifacts(vm.name, entities.Vcpkg, artifacts...)
+}
@@ -0,0 +1,108 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testVcpkgStatus = `Package: vcpkg-cmake
+Version: 2024-04-23
+Architecture: x64-linux
+Abi: aa11
+Type: Port
+Status: install ok installed
+
+Package: fmt
+Version: 10.2.1
+Port-Version: 1
+Depends: vcpkg-cmake:x64-linux
+Architecture: x64-linux
+Abi: bb22
+Type: Port
+Status: install ok installed
+
+Package: fmt
+Version: 10.2.1
+Port-Version: 1
+Depends: vcpkg-cmake:x64-linux
+Architecture: arm64-android
+Abi: cc33
+Type: Port
+Status: install ok installed
+`
+
+func TestGenerateBuildInfoForVcpkgProject(t *testing.T) {
+	binaryCacheDir := t.TempDir()
+	t.Setenv("VCPKG_DEFAULT_BINARY_CACHE", binaryCacheDir)
+	projectDir := t.TempDir()
+	writeVcpkgTestFile(t, filepath.Join(projectDir, buildutils.VcpkgManifestFileName), `{
+  "name": "my-app",
+  "version": "1.0.0",
+  "builtin-baseline": "3426db05b996481ca31e95fff3734cf23e0f51bc",
+  "dependencies": ["fmt", { "name": "zlib", "version>=": "1.3.1" }]
+}`)
+	writeVcpkgTestFile(t, filepath.Join(projectDir, buildutils.VcpkgLockFileName), `{"https://github.com/microsoft/vcpkg": {"HEAD": "3426db05b996481ca31e95fff3734cf23e0f51bc"}}`)
+
+	vcpkgBuild := createTestBuild(t, "build-info-go-test-vcpkg")
+	vcpkgBuild.SetResolutionAudit(true)
+	vcpkgModule, err := vcpkgBuild.AddVcpkgModule(projectDir)
+	require.NoError(t, err)
+
+	// Before the dependencies are installed, the declared dependencies are collected.
+	require.NoError(t, vcpkgModule.CalcDependencies())
+	buildInfo, err := vcpkgBuild.ToBuildInfo()
+	require.NoError(t, err)
+	require.Len(t, buildInfo.Modules, 1)
+	assert.Equal(t, "my-app:1.0.0", buildInfo.Modules[0].Id)
+	assert.Equal(t, entities.Vcpkg, buildInfo.Modules[0].Type)
+	assert.Equal(t, []entities.Dependency{
+		{Id: "fmt", RequestedBy: [][]string{{"my-app:1.0.0"}}, Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.UnknownSource)}},
+		{Id: "zlib:1.3.1", RequestedBy: [][]string{{"my-app:1.0.0"}}, Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.UnknownSource)}},
+	}, buildInfo.Modules[0].Dependencies)
+	assert.Equal(t, map[string]interface{}{
+		entities.LowFidelityProperty: vcpkgManifestFidelity,
+		VcpkgBaselineProperty:        "3426db05b996481ca31e95fff3734cf23e0f51bc",
+		VcpkgRegistriesProperty:      "https://github.com/microsoft/vcpkg@3426db05b996481ca31e95fff3734cf23e0f51bc",
+	}, buildInfo.Modules[0].Properties)
+
+	// fmt is installed for two triplets, and its archive for x64-linux is in the binary cache.
+	writeVcpkgTestFile(t, filepath.Join(projectDir, "build", buildutils.VcpkgInstalledDirName, "vcpkg", "status"), testVcpkgStatus)
+	writeVcpkgTestFile(t, buildutils.GetVcpkgArchivePath(binaryCacheDir, "bb22"), "fmt archive")
+	vcpkgBuild = createTestBuild(t, "build-info-go-test-vcpkg")
+	vcpkgBuild.SetResolutionAudit(true)
+	vcpkgModule, err = vcpkgBuild.AddVcpkgModule(projectDir)
+	require.NoError(t, err)
+	require.NoError(t, vcpkgModule.CalcDependencies())
+	buildInfo, err = vcpkgBuild.ToBuildInfo()
+	require.NoError(t, err)
+	require.Len(t, buildInfo.Modules, 1)
+	dependencies := make(map[string]entities.Dependency)
+	for _, dependency := range buildInfo.Modules[0].Dependencies {
+		dependencies[dependency.Id] = dependency
+	}
+	assert.Len(t, dependencies, 2)
+	fmtDependency := dependencies["fmt:10.2.1#1"]
+	assert.Equal(t, []string{"arm64-android", "x64-linux"}, fmtDependency.Scopes)
+	assert.Equal(t, "zip", fmtDependency.Type)
+	assert.NotEmpty(t, fmtDependency.Sha256)
+	assert.Equal(t, map[string]string{VcpkgAbiProperty: "bb22", entities.ResolutionSourceProperty: string(entities.CacheSource)}, fmtDependency.Properties)
+	assert.Equal(t, [][]string{{"my-app:1.0.0"}}, fmtDependency.RequestedBy)
+	assert.Equal(t, entities.Dependency{
+		Id:          "vcpkg-cmake:2024-04-23",
+		Scopes:      []string{"x64-linux"},
+		RequestedBy: [][]string{{"fmt:10.2.1#1", "my-app:1.0.0"}},
+		Properties:  map[string]string{VcpkgAbiProperty: "aa11", entities.ResolutionSourceProperty: string(entities.FilesystemSource)},
+	}, dependencies["vcpkg-cmake:2024-04-23"])
+	assert.NotContains(t, buildInfo.Modules[0].Properties, entities.LowFidelityProperty)
+}
+
+func writeVcpkgTestFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
@@ -0,0 +1,97 @@
+package build
+
+import (
+	"context"
+	"time"
+
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	defaultWatchPollInterval = time.Second
+	defaultWatchDebounce     = 2 * time.Second
+)
+
+// ProjectWatcher monitors the manifests and lockfiles of the projects in a workspace, and calls a function whenever they change.
+type ProjectWatcher struct {
+	workspacePath string
+	pollInterval  time.Duration
+	debounce      time.Duration
+	logger        utils.Log
+}
+
+func NewProjectWatcher(workspacePath string, logger utils.Log) *ProjectWatcher {
+	return &ProjectWatcher{workspacePath: workspacePath, pollInterval: defaultWatchPollInterval, debounce: defaultWatchDebounce, logger: logger}
+}
+
+// SetPollInterval sets how often the manifests and lockfiles are checked for changes.
+func (pw *ProjectWatcher) SetPollInterval(pollInterval time.Duration) {
+	pw.pollInterval = pollInterval
+}
+
+// SetDebounce sets how long the manifests and lockfiles must stay unchanged before onChange is called.
+// This prevents running a collection for each file written by a single 'install' command.
+func (pw *ProjectWatcher) SetDebounce(debounce time.Duration) {
+	pw.debounce = debounce
+}
+
+// Watch calls onChange once at the beginning, and then whenever the manifests or lockfiles in the workspace change, until the context is done.
+// Errors returned by onChange are logged, and don't stop the watch.
+func (pw *ProjectWatcher) Watch(ctx context.Context, onChange func() error) error {
+	inputFileNames := getAllFingerprintInputFiles()
+	lastFingerprint, err := calcInputsFingerprint(pw.workspacePath, inputFileNames)
+	if err != nil {
+		return err
+	}
+	pw.runOnChange(onChange)
+
+	var pendingFingerprint string
+	var pendingSince time.Time
+	ticker := time.NewTicker(pw.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		fingerprint, err := calcInputsFingerprint(pw.workspacePath, inputFileNames)
+		if err != nil {
+			pw.logger.Warn("Failed to check the manifests and lockfiles for changes:", err.Error())
+			continue
+		}
+		if fingerprint == lastFingerprint {
+			pendingFingerprint = ""
+			continue
+		}
+		if fingerprint != pendingFingerprint {
+			pendingFingerprint = fingerprint
+			pendingSince = time.Now()
+		}
+		if time.Since(pendingSince) < pw.debounce {
+			continue
+		}
+		pw.logger.Info("Detected changes in the manifests or lockfiles of", pw.workspacePath)
+		lastFingerprint, pendingFingerprint = fingerprint, ""
+		pw.runOnChange(onChange)
+	}
+}
+
+func (pw *ProjectWatcher) runOnChange(onChange func() error) {
+	if err := onChange(); err != nil {
+		pw.logger.Error(err.Error())
+	}
+}
+
+func getAllFingerprintInputFiles() (inputFileNames []string) {
+	for _, fileNames := range maps.Values(fingerprintInputFiles) {
+		for _, fileName := range fileNames {
+			if !slices.Contains(inputFileNames, fileName) {
+				inputFileNames = append(inputFileNames, fileName)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,53 @@
+package build
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectWatcher(t *testing.T) {
+	workspace := t.TempDir()
+	packageJsonPath := filepath.Join(workspace, "package.json")
+	require.NoError(t, os.WriteFile(packageJsonPath, []byte(`{"name": "project"}`), 0644))
+
+	watcher := NewProjectWatcher(workspace, &utils.NullLog{})
+	watcher.SetPollInterval(10 * time.Millisecond)
+	watcher.SetDebounce(50 * time.Millisecond)
+	var changesCount atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	watchDone := make(chan error)
+	go func() {
+		watchDone <- watcher.Watch(ctx, func() error {
+			changesCount.Add(1)
+			return nil
+		})
+	}()
+
+	// onChange is called once at the beginning.
+	assert.Eventually(t, func() bool { return changesCount.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	// Files which aren't manifests or lockfiles are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "index.js"), []byte("console.log()"), 0644))
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(1), changesCount.Load())
+
+	// Several quick changes trigger a single call.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, os.WriteFile(packageJsonPath, []byte(`{"name": "project", "version": "1.0.`+string(rune('0'+i))+`"}`), 0644))
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Eventually(t, func() bool { return changesCount.Load() == 2 }, time.Second, 10*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(2), changesCount.Load())
+
+	cancel()
+	assert.NoError(t, <-watchDone)
+}
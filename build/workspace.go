@@ -0,0 +1,290 @@
+package build
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sync"
+
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/parallel"
+	"golang.org/x/exp/slices"
+)
+
+type ProjectTechnology string
+
+const (
+	GoTechnology      ProjectTechnology = "go"
+	MavenTechnology   ProjectTechnology = "maven"
+	GradleTechnology  ProjectTechnology = "gradle"
+	NpmTechnology     ProjectTechnology = "npm"
+	YarnTechnology    ProjectTechnology = "yarn"
+	PythonTechnology  ProjectTechnology = "python"
+	HelmTechnology    ProjectTechnology = "helm"
+	BundlerTechnology ProjectTechnology = "bundler"
+	MixTechnology     ProjectTechnology = "mix"
+	HaskellTechnology ProjectTechnology = "haskell"
+	ZigTechnology     ProjectTechnology = "zig"
+	CMakeTechnology   ProjectTechnology = "cmake"
+	VcpkgTechnology   ProjectTechnology = "vcpkg"
+)
+
+// The files which identify the root of a project, ordered by their priority.
+var projectDescriptors = []struct {
+	fileNames  []string
+	technology ProjectTechnology
+}{
+	{[]string{"pom.xml"}, MavenTechnology},
+	{[]string{"settings.gradle.kts", "settings.gradle", "build.gradle.kts", "build.gradle"}, GradleTechnology},
+	{[]string{"go.mod"}, GoTechnology},
+	{[]string{"yarn.lock", ".yarnrc.yml"}, YarnTechnology},
+	{[]string{"package.json"}, NpmTechnology},
+	{[]string{"pyproject.toml", "setup.py", "requirements.txt", "Pipfile"}, PythonTechnology},
+	{[]string{"Chart.yaml"}, HelmTechnology},
+	{[]string{"Gemfile", "Gemfile.lock"}, BundlerTechnology},
+	{[]string{"mix.exs"}, MixTechnology},
+	{[]string{"stack.yaml", "cabal.project"}, HaskellTechnology},
+	{[]string{"build.zig.zon"}, ZigTechnology},
+	{[]string{"vcpkg.json"}, VcpkgTechnology},
+	{[]string{"CMakeLists.txt"}, CMakeTechnology},
+}
+
+// Directories which never contain independent projects.
+var workspaceExcludedDirs = []string{"node_modules", "target", "build", "vendor", "dist", "venv"}
+
+// WorkspaceProject is an independent project, discovered inside a workspace (a repository containing several projects).
+type WorkspaceProject struct {
+	// The project's path, relative to the workspace root.
+	Path       string
+	Technology ProjectTechnology
+}
+
+// DiscoverWorkspaceProjects walks the workspace and returns the independent projects found in it.
+// Once a project is found in a directory, its subdirectories are considered part of it, and aren't searched for other projects,
+// except for the Go modules nested in a Go project, which are independent modules.
+func DiscoverWorkspaceProjects(workspacePath string) ([]WorkspaceProject, error) {
+	var projects []WorkspaceProject
+	err := filepath.WalkDir(workspacePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != workspacePath && (slices.Contains(workspaceExcludedDirs, entry.Name()) || entry.Name()[0] == '.') {
+			return filepath.SkipDir
+		}
+		technology, err := detectProjectTechnology(path)
+		if err != nil || technology == "" {
+			return err
+		}
+		relativePath, err := filepath.Rel(workspacePath, path)
+		if err != nil {
+			return err
+		}
+		projects = append(projects, WorkspaceProject{Path: relativePath, Technology: technology})
+		if technology == GoTechnology {
+			nestedModules, err := DiscoverGoModules(path, 0)
+			if err != nil {
+				return err
+			}
+			for _, nestedModule := range nestedModules {
+				if nestedModule != "." {
+					projects = append(projects, WorkspaceProject{Path: filepath.Join(relativePath, nestedModule), Technology: GoTechnology})
+				}
+			}
+		}
+		return filepath.SkipDir
+	})
+	return projects, err
+}
+
+func detectProjectTechnology(dirPath string) (ProjectTechnology, error) {
+	for _, descriptor := range projectDescriptors {
+		for _, fileName := range descriptor.fileNames {
+			exists, err := utils.IsFileExists(filepath.Join(dirPath, fileName), false)
+			if err != nil {
+				return "", err
+			}
+			if exists {
+				return descriptor.technology, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// CollectWorkspace discovers the projects in the workspace, and collects the build-info of each of them using the matching collector.
+// The projects are collected in parallel, except for Gradle projects, which are collected one after the other since the Gradle collector changes the working directory.
+// Projects of unsupported technologies are skipped.
+// The collected modules are saved in this build, so that ToBuildInfo() returns one merged multi-module build-info.
+func (b *Build) CollectWorkspace(workspacePath string, threads int) error {
+	if !b.buildNameAndNumberProvided() {
+		return errors.New("a build name must be provided in order to collect the workspace's dependencies")
+	}
+	workspacePath, err := filepath.Abs(workspacePath)
+	if err != nil {
+		return err
+	}
+	projects, err := DiscoverWorkspaceProjects(workspacePath)
+	if err != nil {
+		return err
+	}
+	var parallelProjects, sequentialProjects []WorkspaceProject
+	for _, project := range projects {
+		switch project.Technology {
+		case GradleTechnology:
+			sequentialProjects = append(sequentialProjects, project)
+		case GoTechnology, MavenTechnology, NpmTechnology, YarnTechnology, BundlerTechnology, MixTechnology, HaskellTechnology, ZigTechnology, VcpkgTechnology, CMakeTechnology, HelmTechnology:
+			parallelProjects = append(parallelProjects, project)
+		default:
+			b.logger.Warn("Skipping the", project.Technology, "project at", project.Path+": collecting", project.Technology, "projects in a workspace is not supported.")
+		}
+	}
+
+	var toolchains []Toolchain
+	for _, project := range append(slices.Clone(parallelProjects), sequentialProjects...) {
+		for _, toolchain := range technologyToolchains[project.Technology] {
+			if !slices.Contains(toolchains, toolchain) {
+				toolchains = append(toolchains, toolchain)
+			}
+		}
+	}
+	if err = b.CollectToolchain(workspacePath, toolchains...); err != nil {
+		return err
+	}
+
+	var errorsLock sync.Mutex
+	var collectErrors []error
+	runner := parallel.NewBounedRunner(threads, false)
+	go func() {
+		defer runner.Done()
+		for _, project := range parallelProjects {
+			project := project
+			_, _ = runner.AddTaskWithError(func(int) error {
+				return b.collectWorkspaceProject(workspacePath, project)
+			}, func(err error) {
+				errorsLock.Lock()
+				defer errorsLock.Unlock()
+				collectErrors = append(collectErrors, err)
+			})
+		}
+	}()
+	runner.Run()
+	for _, project := range sequentialProjects {
+		collectErrors = append(collectErrors, b.collectWorkspaceProject(workspacePath, project))
+	}
+	return errors.Join(collectErrors...)
+}
+
+func (b *Build) collectWorkspaceProject(workspacePath string, project WorkspaceProject) (err error) {
+	b.logger.Info("Collecting build-info for the", project.Technology, "project at", project.Path)
+	projectPath := filepath.Join(workspacePath, project.Path)
+	err = b.CollectIncrementally(projectPath, project.Technology, func(containingBuild *Build) error {
+		return containingBuild.collectProject(projectPath, project)
+	})
+	if err != nil {
+		err = errors.New("failed collecting the build-info of the " + string(project.Technology) + " project at " + project.Path + ": " + err.Error())
+	}
+	return
+}
+
+// CollectProject collects the dependencies of a single project, using the collector of the provided technology, and saves them in this build.
+// Pass srcPath as an empty string if the root of the project is the working directory.
+// Python projects are not supported.
+func (b *Build) CollectProject(srcPath string, technology ProjectTechnology) error {
+	switch technology {
+	case GoTechnology, MavenTechnology, GradleTechnology, NpmTechnology, YarnTechnology, BundlerTechnology, MixTechnology, HaskellTechnology, ZigTechnology, VcpkgTechnology, CMakeTechnology, HelmTechnology:
+	default:
+		return errors.New("collecting " + string(technology) + " projects is not supported")
+	}
+	absPath, err := filepath.Abs(srcPath)
+	if err != nil {
+		return err
+	}
+	return b.collectProject(srcPath, WorkspaceProject{Path: filepath.Base(absPath), Technology: technology})
+}
+
+func (b *Build) collectProject(projectPath string, project WorkspaceProject) error {
+	switch project.Technology {
+	case GoTechnology:
+		goModule, err := b.AddGoModule(projectPath)
+		if err != nil {
+			return err
+		}
+		return goModule.CalcDependencies()
+	case MavenTechnology:
+		mavenModule, err := b.AddMavenModule(projectPath)
+		if err != nil {
+			return err
+		}
+		return mavenModule.CalcDependencies()
+	case GradleTechnology:
+		gradleModule, err := b.AddGradleModule(projectPath)
+		if err != nil {
+			return err
+		}
+		return gradleModule.CalcDependencies()
+	case NpmTechnology:
+		npmModule, err := b.AddNpmModule(projectPath)
+		if err != nil {
+			return err
+		}
+		if npmModule.name == "" {
+			npmModule.SetName(project.Path)
+		}
+		return npmModule.CalcDependencies()
+	case YarnTechnology:
+		yarnModule, err := b.AddYarnModule(projectPath)
+		if err != nil {
+			return err
+		}
+		if yarnModule.name == "" {
+			yarnModule.SetName(project.Path)
+		}
+		return yarnModule.Build()
+	case BundlerTechnology:
+		bundlerModule, err := b.AddBundlerModule(projectPath)
+		if err != nil {
+			return err
+		}
+		return bundlerModule.CalcDependencies()
+	case MixTechnology:
+		mixModule, err := b.AddMixModule(projectPath)
+		if err != nil {
+			return err
+		}
+		return mixModule.CalcDependencies()
+	case HaskellTechnology:
+		haskellModule, err := b.AddHaskellModule(projectPath)
+		if err != nil {
+			return err
+		}
+		return haskellModule.CalcDependencies()
+	case ZigTechnology:
+		zigModule, err := b.AddZigModule(projectPath)
+		if err != nil {
+			return err
+		}
+		return zigModule.CalcDependencies()
+	case VcpkgTechnology:
+		vcpkgModule, err := b.AddVcpkgModule(projectPath)
+		if err != nil {
+			return err
+		}
+		return vcpkgModule.CalcDependencies()
+	case CMakeTechnology:
+		cmakeModule, err := b.AddCMakeModule(projectPath)
+		if err != nil {
+			return err
+		}
+		return cmakeModule.CalcDependencies()
+	case HelmTechnology:
+		helmModule, err := b.AddHelmModule(projectPath)
+		if err != nil {
+			return err
+		}
+		return helmModule.CalcDependencies()
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverWorkspaceProjects(t *testing.T) {
+	workspace := t.TempDir()
+	files := []string{
+		filepath.Join("service", "pom.xml"),
+		// Maven submodules are part of the service project.
+		filepath.Join("service", "core", "pom.xml"),
+		filepath.Join("frontend", "package.json"),
+		filepath.Join("frontend", "node_modules", "dep", "package.json"),
+		filepath.Join("web", "package.json"),
+		filepath.Join("web", "yarn.lock"),
+		filepath.Join("libs", "gradle-lib", "settings.gradle.kts"),
+		filepath.Join("libs", "go-lib", "go.mod"),
+		// Nested Go modules are independent projects, unless the go command ignores them.
+		filepath.Join("libs", "go-lib", "tools", "go.mod"),
+		filepath.Join("libs", "go-lib", "testdata", "go.mod"),
+		filepath.Join("charts", "app", "Chart.yaml"),
+		filepath.Join(".github", "package.json"),
+	}
+	for _, file := range files {
+		path := filepath.Join(workspace, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte{}, 0644))
+	}
+
+	projects, err := DiscoverWorkspaceProjects(workspace)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []WorkspaceProject{
+		{Path: "service", Technology: MavenTechnology},
+		{Path: "frontend", Technology: NpmTechnology},
+		{Path: "web", Technology: YarnTechnology},
+		{Path: filepath.Join("libs", "gradle-lib"), Technology: GradleTechnology},
+		{Path: filepath.Join("libs", "go-lib"), Technology: GoTechnology},
+		{Path: filepath.Join("libs", "go-lib", "tools"), Technology: GoTechnology},
+		{Path: filepath.Join("charts", "app"), Technology: HelmTechnology},
+	}, projects)
+}
+
+func TestCollectWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	files := map[string]string{
+		filepath.Join("charts", "app", "Chart.yaml"):       "apiVersion: v2\nname: app\nversion: 1.0.0\n",
+		filepath.Join("native", "CMakeLists.txt"):          "project(native VERSION 2.0.0 LANGUAGES CXX)\n",
+		filepath.Join("native", "build", "CMakeCache.txt"): "CMAKE_PROJECT_NAME:STATIC=native\n",
+	}
+	for file, content := range files {
+		path := filepath.Join(workspace, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	projects, err := DiscoverWorkspaceProjects(workspace)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []WorkspaceProject{
+		{Path: filepath.Join("charts", "app"), Technology: HelmTechnology},
+		{Path: "native", Technology: CMakeTechnology},
+	}, projects)
+
+	workspaceBuild := createTestBuild(t, "build-info-go-test-workspace")
+	require.NoError(t, workspaceBuild.CollectWorkspace(workspace, 2))
+	buildInfo, err := workspaceBuild.ToBuildInfo()
+	require.NoError(t, err)
+	var modules []string
+	for _, module := range buildInfo.Modules {
+		modules = append(modules, string(module.Type)+" "+module.Id)
+	}
+	assert.ElementsMatch(t, []string{"helm app:1.0.0", "cmake native:2.0.0"}, modules)
+}
@@ -24,6 +24,7 @@ type YarnModule struct {
 	traverseDependenciesFunc func(dependency *entities.Dependency) (bool, error)
 	threads                  int
 	packageInfo              *buildutils.PackageInfo
+	collectWorkspaces        bool
 }
 
 // Pass an empty string for srcPath to find the Yarn project in the working directory.
@@ -68,6 +69,9 @@ func (ym *YarnModule) Build() error {
 	if !ym.containingBuild.buildNameAndNumberProvided() {
 		return nil
 	}
+	if ym.collectWorkspaces {
+		return ym.buildWorkspaces()
+	}
 	dependenciesMap, err := ym.getDependenciesMap()
 	if err != nil {
 		return err
@@ -81,29 +85,89 @@ func (ym *YarnModule) Build() error {
 	return ym.containingBuild.SaveBuildInfo(buildInfo)
 }
 
+// Saves a module for each of the project's workspaces, including its root, in which the dependencies on other workspaces are project dependencies.
+func (ym *YarnModule) buildWorkspaces() error {
+	workspaces, err := buildutils.GetYarnWorkspaces(ym.executablePath, ym.srcPath)
+	if err != nil {
+		return err
+	}
+	dependenciesMap, _, err := buildutils.GetYarnDependencies(ym.executablePath, ym.srcPath, ym.packageInfo, ym.containingBuild.logger, false)
+	if err != nil {
+		return err
+	}
+	workspacesModules, err := getYarnWorkspacesModules(ym.srcPath, workspaces)
+	if err != nil {
+		return err
+	}
+	buildInfo := &entities.BuildInfo{}
+	for _, workspace := range workspaces {
+		workspaceDependency, exist := dependenciesMap[workspace.Key()]
+		if !exist {
+			return fmt.Errorf("the workspace %s at %s was not found in the dependencies tree", workspace.Name, workspace.Location)
+		}
+		buildInfoDependenciesMap := make(map[string]*entities.Dependency)
+		if err = ym.appendDependencyRecursively(workspaceDependency, []string{}, dependenciesMap, workspacesModules, buildInfoDependenciesMap); err != nil {
+			return err
+		}
+		buildInfoDependencies, err := buildutils.TraverseDependencies(buildInfoDependenciesMap, ym.traverseDependenciesFunc, ym.threads)
+		if err != nil {
+			return err
+		}
+		buildInfo.Modules = append(buildInfo.Modules, entities.Module{Id: workspacesModules[workspace.Key()], Type: entities.Npm, Dependencies: buildInfoDependencies})
+	}
+	return ym.containingBuild.SaveBuildInfo(buildInfo)
+}
+
+// Returns the module IDs of the workspaces, mapped by their keys in the dependencies map.
+func getYarnWorkspacesModules(srcPath string, workspaces []buildutils.YarnWorkspace) (map[string]string, error) {
+	workspacesModules := make(map[string]string, len(workspaces))
+	for _, workspace := range workspaces {
+		moduleId, err := workspace.ModuleId(srcPath)
+		if err != nil {
+			return nil, err
+		}
+		if moduleId == "" {
+			return nil, fmt.Errorf("the workspace at %s must have a name in its package.json", workspace.Location)
+		}
+		workspacesModules[workspace.Key()] = moduleId
+	}
+	return workspacesModules, nil
+}
+
 func (ym *YarnModule) getDependenciesMap() (map[string]*entities.Dependency, error) {
 	dependenciesMap, root, err := buildutils.GetYarnDependencies(ym.executablePath, ym.srcPath, ym.packageInfo, ym.containingBuild.logger, false)
 	if err != nil {
 		return nil, err
 	}
 	buildInfoDependencies := make(map[string]*entities.Dependency)
-	err = ym.appendDependencyRecursively(root, []string{}, dependenciesMap, buildInfoDependencies)
+	err = ym.appendDependencyRecursively(root, []string{}, dependenciesMap, nil, buildInfoDependencies)
 	return buildInfoDependencies, err
 }
 
+// The dependencies on the workspaces in workspacesModules (mapped by their keys in yarnDependenciesMap to their module IDs) are added as project dependencies,
+// without their own dependencies, which are collected in the workspaces' modules.
 func (ym *YarnModule) appendDependencyRecursively(yarnDependency *buildutils.YarnDependency, pathToRoot []string, yarnDependenciesMap map[string]*buildutils.YarnDependency,
-	buildInfoDependencies map[string]*entities.Dependency) error {
+	workspacesModules map[string]string, buildInfoDependencies map[string]*entities.Dependency) error {
 	depName, err := yarnDependency.Name()
 	if err != nil {
 		return err
 	}
 	id := depName + ":" + yarnDependency.Details.Version
+	moduleId, isWorkspace := workspacesModules[yarnDependency.Value]
+	if isWorkspace {
+		id = moduleId
+	}
 
 	// To avoid infinite loops in case of circular dependencies, the dependency won't be added if it's already in pathToRoot
 	if slices.Contains(pathToRoot, id) {
 		return nil
 	}
 
+	if isWorkspace && len(pathToRoot) > 0 {
+		addRequestedBy(buildInfoDependencies, id, entities.ProjectDependencyType, pathToRoot)
+		return nil
+	}
+
 	for _, dependencyPtr := range yarnDependency.Details.Dependencies {
 		innerDepKey := buildutils.GetYarnDependencyKeyFromLocator(dependencyPtr.Locator)
 		innerYarnDep, exist := yarnDependenciesMap[innerDepKey]
@@ -111,7 +175,7 @@ func (ym *YarnModule) appendDependencyRecursively(yarnDependency *buildutils.Yar
 			return fmt.Errorf("an error occurred while creating dependencies tree: dependency %s was not found", dependencyPtr.Locator)
 		}
 		err = ym.appendDependencyRecursively(innerYarnDep, append([]string{id}, pathToRoot...), yarnDependenciesMap,
-			buildInfoDependencies)
+			workspacesModules, buildInfoDependencies)
 		if err != nil {
 			return err
 		}
@@ -122,14 +186,21 @@ func (ym *YarnModule) appendDependencyRecursively(yarnDependency *buildutils.Yar
 		return nil
 	}
 
+	addRequestedBy(buildInfoDependencies, id, "", pathToRoot)
+	if yarnDependency.IsLocal() {
+		buildInfoDependencies[id].SetMutable()
+	}
+	return nil
+}
+
+func addRequestedBy(buildInfoDependencies map[string]*entities.Dependency, id, dependencyType string, pathToRoot []string) {
 	buildInfoDependency, exist := buildInfoDependencies[id]
 	if !exist {
-		buildInfoDependency = &entities.Dependency{Id: id}
+		buildInfoDependency = &entities.Dependency{Id: id, Type: dependencyType}
+		buildInfoDependency.SetResolutionSource(entities.CliTreeSource)
 		buildInfoDependencies[id] = buildInfoDependency
 	}
-
 	buildInfoDependency.RequestedBy = append(buildInfoDependency.RequestedBy, pathToRoot)
-	return nil
 }
 
 func (ym *YarnModule) SetName(name string) {
@@ -144,6 +215,13 @@ func (ym *YarnModule) SetThreads(threads int) {
 	ym.threads = threads
 }
 
+// SetCollectWorkspaces sets whether to save a module for each of the project's workspaces, including its root, instead of a single module.
+// The dependencies of a workspace on other workspaces, declared with the 'workspace:' protocol, are saved as project dependencies
+// (see entities.ProjectDependencyType), whose IDs are the IDs of the workspaces' modules.
+func (ym *YarnModule) SetCollectWorkspaces(collectWorkspaces bool) {
+	ym.collectWorkspaces = collectWorkspaces
+}
+
 // SetTraverseDependenciesFunc gets a function to execute on all dependencies after their collection in Build(), before they're saved.
 // This function needs to return a boolean value indicating whether to save this dependency in the build-info or not.
 // This function might run asynchronously with different dependencies (if the threads amount setting is bigger than 1).
@@ -173,7 +251,7 @@ func RunYarnCommand(executablePath, srcPath string, args ...string) error {
 	command.Dir = srcPath
 	command.Stdout = os.Stderr
 	command.Stderr = os.Stderr
-	err := command.Run()
+	_, err := runTimedCommand(command)
 	if _, ok := err.(*exec.ExitError); ok {
 		err = errors.New(err.Error())
 	}
@@ -54,16 +54,16 @@ func TestAppendDependencyRecursively(t *testing.T) {
 		{
 			dependenciesMap["pack3@npm:1.0.0"],
 			map[string]*entities.Dependency{
-				"pack1:1.0.0": {Id: "pack1:1.0.0", RequestedBy: [][]string{{"pack3:1.0.0", "rootpack:1.0.0"}}},
-				"pack2:1.0.0": {Id: "pack2:1.0.0", RequestedBy: [][]string{{"pack3:1.0.0", "rootpack:1.0.0"}}},
-				"pack3:1.0.0": {Id: "pack3:1.0.0", RequestedBy: [][]string{{"rootpack:1.0.0"}}},
+				"pack1:1.0.0": {Id: "pack1:1.0.0", RequestedBy: [][]string{{"pack3:1.0.0", "rootpack:1.0.0"}}, Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.CliTreeSource)}},
+				"pack2:1.0.0": {Id: "pack2:1.0.0", RequestedBy: [][]string{{"pack3:1.0.0", "rootpack:1.0.0"}}, Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.CliTreeSource)}},
+				"pack3:1.0.0": {Id: "pack3:1.0.0", RequestedBy: [][]string{{"rootpack:1.0.0"}}, Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.CliTreeSource)}},
 			},
 		}, {
 			dependenciesMap["pack6@npm:1.0.0"],
 			map[string]*entities.Dependency{
-				"pack4:1.0.0": {Id: "pack4:1.0.0", RequestedBy: [][]string{{"pack6:1.0.0", "rootpack:1.0.0"}}},
-				"pack5:1.0.0": {Id: "pack5:1.0.0", RequestedBy: [][]string{{"pack4:1.0.0", "pack6:1.0.0", "rootpack:1.0.0"}}},
-				"pack6:1.0.0": {Id: "pack6:1.0.0", RequestedBy: [][]string{{"rootpack:1.0.0"}}},
+				"pack4:1.0.0": {Id: "pack4:1.0.0", RequestedBy: [][]string{{"pack6:1.0.0", "rootpack:1.0.0"}}, Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.CliTreeSource)}},
+				"pack5:1.0.0": {Id: "pack5:1.0.0", RequestedBy: [][]string{{"pack4:1.0.0", "pack6:1.0.0", "rootpack:1.0.0"}}, Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.CliTreeSource)}},
+				"pack6:1.0.0": {Id: "pack6:1.0.0", RequestedBy: [][]string{{"rootpack:1.0.0"}}, Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.CliTreeSource)}},
 			},
 		},
 	}
@@ -73,7 +73,7 @@ func TestAppendDependencyRecursively(t *testing.T) {
 		biDependencies := make(map[string]*entities.Dependency)
 		go func() {
 			defer producerConsumer.Done()
-			err := yarnModule.appendDependencyRecursively(testCase.dependency, []string{"rootpack:1.0.0"}, dependenciesMap, biDependencies)
+			err := yarnModule.appendDependencyRecursively(testCase.dependency, []string{"rootpack:1.0.0"}, dependenciesMap, nil, biDependencies)
 			assert.NoError(t, err)
 		}()
 		producerConsumer.Run()
@@ -81,6 +81,31 @@ func TestAppendDependencyRecursively(t *testing.T) {
 	}
 }
 
+func TestAppendDependencyRecursivelyWithWorkspaces(t *testing.T) {
+	dependenciesMap := map[string]*buildutils.YarnDependency{
+		"app@workspace:packages/app":         {Value: "app@workspace:packages/app", Details: buildutils.YarnDepDetails{Version: "0.0.0-use.local", Dependencies: []buildutils.YarnDependencyPointer{{Locator: "@acme/core@virtual:2c5a8e#workspace:packages/core"}, {Locator: "pack1@npm:1.0.0"}}}},
+		"@acme/core@workspace:packages/core": {Value: "@acme/core@workspace:packages/core", Details: buildutils.YarnDepDetails{Version: "0.0.0-use.local", Dependencies: []buildutils.YarnDependencyPointer{{Locator: "pack2@npm:1.0.0"}}}},
+		"pack1@npm:1.0.0":                    {Value: "pack1@npm:1.0.0", Details: buildutils.YarnDepDetails{Version: "1.0.0"}},
+		"pack2@npm:1.0.0":                    {Value: "pack2@npm:1.0.0", Details: buildutils.YarnDepDetails{Version: "1.0.0"}},
+	}
+	workspacesModules := map[string]string{"app@workspace:packages/app": "app:1.0.0", "@acme/core@workspace:packages/core": "acme:core:2.0.0"}
+	yarnModule := &YarnModule{}
+
+	biDependencies := make(map[string]*entities.Dependency)
+	assert.NoError(t, yarnModule.appendDependencyRecursively(dependenciesMap["app@workspace:packages/app"], []string{}, dependenciesMap, workspacesModules, biDependencies))
+	// The dependencies of the other workspace aren't collected in this workspace's module.
+	assert.Equal(t, map[string]*entities.Dependency{
+		"acme:core:2.0.0": {Id: "acme:core:2.0.0", Type: entities.ProjectDependencyType, RequestedBy: [][]string{{"app:1.0.0"}}, Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.CliTreeSource)}},
+		"pack1:1.0.0":     {Id: "pack1:1.0.0", RequestedBy: [][]string{{"app:1.0.0"}}, Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.CliTreeSource)}},
+	}, biDependencies)
+
+	biDependencies = make(map[string]*entities.Dependency)
+	assert.NoError(t, yarnModule.appendDependencyRecursively(dependenciesMap["@acme/core@workspace:packages/core"], []string{}, dependenciesMap, workspacesModules, biDependencies))
+	assert.Equal(t, map[string]*entities.Dependency{
+		"pack2:1.0.0": {Id: "pack2:1.0.0", RequestedBy: [][]string{{"acme:core:2.0.0"}}, Properties: map[string]string{entities.ResolutionSourceProperty: string(entities.CliTreeSource)}},
+	}, biDependencies)
+}
+
 func TestGenerateBuildInfoForYarnProject(t *testing.T) {
 	// Copy the project directory to a temporary directory
 	tempDirPath, createTempDirCallback := tests.CreateTempDirWithCallbackAndAssert(t)
@@ -0,0 +1,182 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/maps"
+)
+
+// The property of a Zig dependency, whose value is the hash of the package's contents declared in the build.zig.zon.
+// The hash isn't a checksum of a file, so it isn't recorded as the dependency's checksum.
+const ZigHashProperty = "zig.hash"
+
+type ZigModule struct {
+	containingBuild *Build
+	name            string
+	srcPath         string
+	// The arguments of a zig command, such as 'build --fetch', which runs before the dependencies are collected.
+	zigArgs []string
+	// The duration of the command, if it ran.
+	commandDuration time.Duration
+}
+
+// Pass an empty string for srcPath to find the Zig package in the working directory.
+func newZigModule(srcPath string, containingBuild *Build) (*ZigModule, error) {
+	if srcPath == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		srcPath, err = utils.FindFileInDirAndParents(wd, buildutils.ZigManifestFileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &ZigModule{srcPath: srcPath, containingBuild: containingBuild}, nil
+}
+
+func (zm *ZigModule) SetName(name string) {
+	zm.name = name
+}
+
+// SetZigArgs sets the arguments of a zig command, for example: build --fetch
+// The command runs in the package's directory before the dependencies are collected, so that the dependencies are fetched into the global cache.
+func (zm *ZigModule) SetZigArgs(zigArgs []string) {
+	zm.zigArgs = zigArgs
+}
+
+// Build runs the zig command set by SetZigArgs, if any, and then collects the package's dependencies.
+func (zm *ZigModule) Build() error {
+	if len(zm.zigArgs) > 0 {
+		command := exec.Command("zig", zm.zigArgs...)
+		command.Dir = zm.srcPath
+		var err error
+		if zm.commandDuration, err = runModuleCommand(command); err != nil {
+			return fmt.Errorf("failed running 'zig %s': %w", strings.Join(zm.zigArgs, " "), err)
+		}
+	}
+	return zm.CalcDependencies()
+}
+
+// CalcDependencies collects the dependencies declared in the package's build.zig.zon, without running Zig.
+// The transitive dependencies are declared in the manifests of the dependencies, which are read from the Zig global cache,
+// or from the local paths of the path dependencies. The dependencies which weren't fetched, such as lazy dependencies, don't have transitive dependencies.
+func (zm *ZigModule) CalcDependencies() error {
+	if !zm.containingBuild.buildNameAndNumberProvided() {
+		return errors.New("a build name must be provided in order to collect the project's dependencies")
+	}
+	manifest, err := buildutils.ReadZigManifest(zm.srcPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("no build.zig.zon was found in " + zm.srcPath)
+		}
+		return err
+	}
+	zm.setModuleId(manifest)
+	cacheDir, err := buildutils.GetZigGlobalCacheDir()
+	if err != nil {
+		return err
+	}
+	dependenciesMap := make(map[string]entities.Dependency)
+	dependenciesGraph := make(map[string][]string)
+	if err = zm.addDependencies(zm.name, zm.srcPath, manifest, cacheDir, dependenciesMap, dependenciesGraph); err != nil {
+		return err
+	}
+	emptyRequestedBy := [][]string{{}}
+	populateRequestedByField(zm.name, emptyRequestedBy, dependenciesMap, dependenciesGraph)
+	buildInfoModule := entities.Module{Id: zm.name, Type: entities.Zig, Dependencies: dependenciesMapToList(dependenciesMap)}
+	addCommandDuration(&buildInfoModule, zm.commandDuration)
+	return zm.containingBuild.SaveBuildInfo(&entities.BuildInfo{Modules: []entities.Module{buildInfoModule}})
+}
+
+// If the module ID wasn't set, it's the name and the version declared in the build.zig.zon, or the name of the package's directory.
+func (zm *ZigModule) setModuleId(manifest *buildutils.ZigManifest) {
+	if zm.name != "" {
+		return
+	}
+	if manifest.Name != "" {
+		zm.name = getZigPackageId(manifest.Name, manifest.Version)
+		return
+	}
+	zm.name = filepath.Base(zm.srcPath)
+	zm.containingBuild.logger.Debug(fmt.Sprintf("The build.zig.zon doesn't declare the package's name. Using its directory name: %s as the module name.", zm.name))
+}
+
+// Adds the dependencies of the manifest in the provided directory, and their dependencies, to the dependencies map and graph.
+func (zm *ZigModule) addDependencies(parentId, dir string, manifest *buildutils.ZigManifest, cacheDir string, dependenciesMap map[string]entities.Dependency, dependenciesGraph map[string][]string) error {
+	names := maps.Keys(manifest.Dependencies)
+	sort.Strings(names)
+	for _, name := range names {
+		zigDependency := manifest.Dependencies[name]
+		var dependency entities.Dependency
+		dependency.SetResolutionSource(entities.LockfileSource)
+		var packageDir string
+		if zigDependency.Path != "" {
+			packageDir = filepath.Join(dir, zigDependency.Path)
+			dependency.SetResolutionSource(entities.FilesystemSource)
+			dependency.SetMutable()
+		} else if zigDependency.Hash != "" {
+			dependency.RemoteRepository = zigDependency.Url
+			dependency.Properties[ZigHashProperty] = zigDependency.Hash
+			packageDir = filepath.Join(cacheDir, "p", zigDependency.Hash)
+		} else {
+			zm.containingBuild.logger.Debug("The Zig dependency", name, "doesn't have a hash. Skipping it.")
+			continue
+		}
+		dependencyManifest, err := buildutils.ReadZigManifest(packageDir)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		version := buildutils.GetZigHashVersion(zigDependency.Hash)
+		if dependencyManifest != nil {
+			version = dependencyManifest.Version
+			if zigDependency.Hash != "" {
+				dependency.SetResolutionSource(entities.CacheSource)
+			}
+		} else if zigDependency.Hash != "" {
+			if zigDependency.Lazy {
+				zm.containingBuild.logger.Debug("The lazy Zig dependency", name, "wasn't fetched into the global cache.")
+			} else {
+				zm.containingBuild.logger.Debug("The Zig dependency", name, "wasn't found in the global cache.")
+			}
+		}
+		if version == "" {
+			// The hash identifies the dependency's contents, when its version is unknown.
+			version = zigDependency.Hash
+		}
+		dependency.Id = getZigPackageId(name, version)
+		dependenciesGraph[parentId] = append(dependenciesGraph[parentId], dependency.Id)
+		if _, exists := dependenciesMap[dependency.Id]; exists {
+			continue
+		}
+		dependenciesMap[dependency.Id] = dependency
+		if dependencyManifest != nil {
+			if err = zm.addDependencies(dependency.Id, packageDir, dependencyManifest, cacheDir, dependenciesMap, dependenciesGraph); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the ID of a Zig package: <name>:<version>, or <name> if its version is unknown.
+func getZigPackageId(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + ":" + version
+}
+
+func (zm *ZigModule) AddArtifacts(artifacts ...entities.Artifact) error {
+	return zm.containingBuild.AddArtifacts(zm.name, entities.Zig, artifacts...)
+}
@@ -0,0 +1,79 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testZapHash  = "12209936c3333b53b53edcf453b1670babb9ae8c2197b1ca627c01e72670e20c1a21"
+	testClapHash = "clap-0.10.0-oBajB434AQBDh-Ei3YtoKIRxZacVPF1iSwp3IX_ZB8f0"
+	testHttpHash = "1220aaaa3333b53b53edcf453b1670babb9ae8c2197b1ca627c01e72670e20c1a21"
+)
+
+func TestGenerateBuildInfoForZigProject(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("ZIG_GLOBAL_CACHE_DIR", cacheDir)
+	projectDir := t.TempDir()
+	writeZigManifest(t, projectDir, `.{
+    .name = .my_app,
+    .version = "0.1.0",
+    .dependencies = .{
+        .zap = .{ .url = "https://github.com/zigzap/zap/archive/refs/tags/v0.8.0.tar.gz", .hash = "`+testZapHash+`" },
+        .clap = .{ .url = "git+https://github.com/Hejsil/zig-clap#e47028de", .hash = "`+testClapHash+`", .lazy = true },
+        .local = .{ .path = "libs/local" },
+    },
+    .paths = .{""},
+}`)
+	// zap is fetched into the global cache, and depends on http, which isn't fetched.
+	writeZigManifest(t, filepath.Join(cacheDir, "p", testZapHash), `.{
+    .name = "zap",
+    .version = "0.8.0",
+    .dependencies = .{ .http = .{ .url = "https://example.com/http.tar.gz", .hash = "`+testHttpHash+`" } },
+    .paths = .{""},
+}`)
+	writeZigManifest(t, filepath.Join(projectDir, "libs", "local"), `.{ .name = .local, .version = "0.0.1", .paths = .{""} }`)
+
+	zigBuild := createTestBuild(t, "build-info-go-test-zig")
+	zigBuild.SetResolutionAudit(true)
+	zigModule, err := zigBuild.AddZigModule(projectDir)
+	require.NoError(t, err)
+	require.NoError(t, zigModule.CalcDependencies())
+	buildInfo, err := zigBuild.ToBuildInfo()
+	require.NoError(t, err)
+
+	require.Len(t, buildInfo.Modules, 1)
+	module := buildInfo.Modules[0]
+	assert.Equal(t, "my_app:0.1.0", module.Id)
+	assert.Equal(t, entities.Zig, module.Type)
+	dependencies := make(map[string]entities.Dependency)
+	for _, dependency := range module.Dependencies {
+		dependencies[dependency.Id] = dependency
+	}
+	assert.Len(t, dependencies, 4)
+	assert.Equal(t, entities.Dependency{
+		Id:               "zap:0.8.0",
+		RequestedBy:      [][]string{{"my_app:0.1.0"}},
+		RemoteRepository: "https://github.com/zigzap/zap/archive/refs/tags/v0.8.0.tar.gz",
+		Properties:       map[string]string{ZigHashProperty: testZapHash, entities.ResolutionSourceProperty: string(entities.CacheSource)},
+	}, dependencies["zap:0.8.0"])
+	// The version of a dependency which isn't fetched is taken from its hash, or is the hash itself if the hash doesn't contain it.
+	assert.Equal(t, string(entities.LockfileSource), dependencies["clap:0.10.0"].Properties[entities.ResolutionSourceProperty])
+	assert.Equal(t, [][]string{{"zap:0.8.0", "my_app:0.1.0"}}, dependencies["http:"+testHttpHash].RequestedBy)
+	// A dependency on a local path may change without a change to its version.
+	assert.Equal(t, map[string]string{entities.MutableProperty: "true", entities.ResolutionSourceProperty: string(entities.FilesystemSource)}, dependencies["local:0.0.1"].Properties)
+
+	require.NoError(t, os.Remove(filepath.Join(projectDir, buildutils.ZigManifestFileName)))
+	assert.ErrorContains(t, zigModule.CalcDependencies(), "no build.zig.zon was found")
+}
+
+func writeZigManifest(t *testing.T, dir, content string) {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, buildutils.ZigManifestFileName), []byte(content), 0644))
+}
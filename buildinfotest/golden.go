@@ -0,0 +1,153 @@
+// Package buildinfotest provides utilities for testing the build-info generated with this library:
+// synthetic Maven, Gradle and npm projects, and the comparison of build-info with golden JSON files, whose volatile fields are masked.
+package buildinfotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The value which replaces the masked fields and properties.
+const MaskedValue = "<masked>"
+
+// Set this environment variable to true to write the golden files with the build-info of the tests, instead of comparing them.
+const UpdateGoldenEnv = "BUILD_INFO_UPDATE_GOLDEN"
+
+// The build properties which change between runs and machines, and are masked by default.
+var DefaultMaskedProperties = []string{"buildInfo.env.*", "buildInfo.toolchain.*", "buildInfo.runner.*"}
+
+type goldenOptions struct {
+	maskedProperties []string
+	maskChecksums    bool
+}
+
+// GoldenOption customizes the normalization of the build-info before it's compared with the golden file.
+type GoldenOption func(*goldenOptions)
+
+// MaskProperties masks the values of the build-info's, the modules' and the dependencies' properties whose keys match the patterns,
+// in addition to the DefaultMaskedProperties. The patterns are matched like in path.Match, for example: vault.*
+func MaskProperties(patterns ...string) GoldenOption {
+	return func(options *goldenOptions) {
+		options.maskedProperties = append(options.maskedProperties, patterns...)
+	}
+}
+
+// MaskChecksums masks the checksums of the dependencies and the artifacts, for example when they're calculated from files which aren't deterministic.
+func MaskChecksums() GoldenOption {
+	return func(options *goldenOptions) {
+		options.maskChecksums = true
+	}
+}
+
+// Normalize returns a copy of the build-info, which can be compared between runs:
+//   - The start time, the duration and the versions of the agents are masked.
+//   - The properties which match the DefaultMaskedProperties and the MaskProperties patterns are masked.
+//   - The modules, and their dependencies and artifacts, are sorted by their IDs and names, as are the dependencies' scopes and requestedBy paths.
+func Normalize(buildInfo *entities.BuildInfo, options ...GoldenOption) (*entities.BuildInfo, error) {
+	goldenOptions := &goldenOptions{maskedProperties: append([]string{}, DefaultMaskedProperties...)}
+	for _, option := range options {
+		option(goldenOptions)
+	}
+	// The build-info is copied through its JSON, which is also what's compared.
+	content, err := json.Marshal(buildInfo)
+	if err != nil {
+		return nil, err
+	}
+	normalized := new(entities.BuildInfo)
+	if err = json.Unmarshal(content, normalized); err != nil {
+		return nil, err
+	}
+	if normalized.Started != "" {
+		normalized.Started = MaskedValue
+	}
+	normalized.DurationMillis = 0
+	for _, agent := range []*entities.Agent{normalized.Agent, normalized.BuildAgent} {
+		if agent != nil && agent.Version != "" {
+			agent.Version = MaskedValue
+		}
+	}
+	goldenOptions.maskProperties(normalized.Properties)
+	for i := range normalized.Modules {
+		module := &normalized.Modules[i]
+		if properties, ok := module.Properties.(map[string]interface{}); ok {
+			for key := range properties {
+				if goldenOptions.isMasked(key) {
+					properties[key] = MaskedValue
+				}
+			}
+		}
+		for j := range module.Dependencies {
+			dependency := &module.Dependencies[j]
+			goldenOptions.maskProperties(dependency.Properties)
+			if goldenOptions.maskChecksums && !dependency.Checksum.IsEmpty() {
+				dependency.Checksum = entities.Checksum{Sha1: MaskedValue, Md5: MaskedValue, Sha256: MaskedValue}
+			}
+			sort.Strings(dependency.Scopes)
+			sort.Slice(dependency.RequestedBy, func(k, l int) bool {
+				return strings.Join(dependency.RequestedBy[k], " ") < strings.Join(dependency.RequestedBy[l], " ")
+			})
+		}
+		for j := range module.Artifacts {
+			if goldenOptions.maskChecksums && !module.Artifacts[j].Checksum.IsEmpty() {
+				module.Artifacts[j].Checksum = entities.Checksum{Sha1: MaskedValue, Md5: MaskedValue, Sha256: MaskedValue}
+			}
+		}
+		sort.SliceStable(module.Dependencies, func(j, k int) bool { return module.Dependencies[j].Id < module.Dependencies[k].Id })
+		sort.SliceStable(module.Artifacts, func(j, k int) bool {
+			return module.Artifacts[j].Path+module.Artifacts[j].Name < module.Artifacts[k].Path+module.Artifacts[k].Name
+		})
+	}
+	sort.SliceStable(normalized.Modules, func(i, j int) bool { return normalized.Modules[i].Id < normalized.Modules[j].Id })
+	return normalized, nil
+}
+
+// AssertGolden normalizes the build-info (see Normalize) and compares its JSON with the golden file.
+// If the UpdateGoldenEnv environment variable is set to true, the golden file is written instead, so that it can be reviewed and committed.
+func AssertGolden(t testing.TB, goldenPath string, buildInfo *entities.BuildInfo, options ...GoldenOption) {
+	t.Helper()
+	normalized, err := Normalize(buildInfo, options...)
+	require.NoError(t, err)
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	require.NoError(t, encoder.Encode(normalized))
+	content := buffer.Bytes()
+	if update, _ := strconv.ParseBool(os.Getenv(UpdateGoldenEnv)); update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(goldenPath), 0755))
+		require.NoError(t, os.WriteFile(goldenPath, content, 0644))
+		t.Logf("Updated the golden file %s", goldenPath)
+		return
+	}
+	golden, err := os.ReadFile(goldenPath)
+	require.NoError(t, err, "failed reading the golden file. Run the test with %s=true to create it", UpdateGoldenEnv)
+	assert.JSONEq(t, string(golden), string(content), "the build-info doesn't match the golden file %s. Run the test with %s=true to update it", goldenPath, UpdateGoldenEnv)
+}
+
+func (options *goldenOptions) maskProperties(properties map[string]string) {
+	for key := range properties {
+		if options.isMasked(key) {
+			properties[key] = MaskedValue
+		}
+	}
+}
+
+func (options *goldenOptions) isMasked(key string) bool {
+	for _, pattern := range options.maskedProperties {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package buildinfotest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	buildInfo := &entities.BuildInfo{
+		Name:           "build",
+		Started:        "2024-01-01T10:00:00.000+0000",
+		DurationMillis: 1000,
+		Agent:          &entities.Agent{Name: "bi", Version: "1.2.3"},
+		Properties:     entities.Env{"buildInfo.env.PATH": "/usr/bin", "vault.token": "secret", "team": "core"},
+		Modules: []entities.Module{
+			{Id: "b", Dependencies: []entities.Dependency{
+				{Id: "z:1", Scopes: []string{"test", "compile"}, RequestedBy: [][]string{{"y:1", "b"}, {"b"}}, Checksum: entities.Checksum{Sha1: "123"}},
+				{Id: "a:1", Properties: map[string]string{"vault.key": "secret"}},
+			}},
+			{Id: "a", Properties: map[string]interface{}{"vault.module": "secret"}},
+		},
+	}
+	normalized, err := Normalize(buildInfo, MaskProperties("vault.*"), MaskChecksums())
+	require.NoError(t, err)
+	assert.Equal(t, &entities.BuildInfo{
+		Name:       "build",
+		Started:    MaskedValue,
+		Agent:      &entities.Agent{Name: "bi", Version: MaskedValue},
+		Properties: entities.Env{"buildInfo.env.PATH": MaskedValue, "vault.token": MaskedValue, "team": "core"},
+		Modules: []entities.Module{
+			{Id: "a", Properties: map[string]interface{}{"vault.module": MaskedValue}},
+			{Id: "b", Dependencies: []entities.Dependency{
+				{Id: "a:1", Properties: map[string]string{"vault.key": MaskedValue}},
+				{Id: "z:1", Scopes: []string{"compile", "test"}, RequestedBy: [][]string{{"b"}, {"y:1", "b"}},
+					Checksum: entities.Checksum{Sha1: MaskedValue, Md5: MaskedValue, Sha256: MaskedValue}},
+			}},
+		},
+	}, normalized)
+	// The build-info itself isn't changed.
+	assert.Equal(t, "secret", buildInfo.Properties["vault.token"])
+	assert.Equal(t, "b", buildInfo.Modules[0].Id)
+}
+
+func TestAssertGoldenUpdate(t *testing.T) {
+	goldenPath := filepath.Join(t.TempDir(), "golden", "build-info.json")
+	buildInfo := &entities.BuildInfo{Name: "build", Number: "1", Started: "2024-01-01T10:00:00.000+0000"}
+	t.Setenv(UpdateGoldenEnv, "true")
+	AssertGolden(t, goldenPath, buildInfo)
+	content, err := os.ReadFile(goldenPath)
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"name\": \"build\",\n  \"number\": \"1\",\n  \"started\": \"<masked>\"\n}\n", string(content))
+
+	t.Setenv(UpdateGoldenEnv, "")
+	buildInfo.Started = "2025-01-01T10:00:00.000+0000"
+	AssertGolden(t, goldenPath, buildInfo)
+}
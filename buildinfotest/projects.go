@@ -0,0 +1,296 @@
+package buildinfotest
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/require"
+)
+
+// MavenProject describes a synthetic single-module Maven project.
+type MavenProject struct {
+	GroupId    string
+	ArtifactId string
+	Version    string
+	// The resolved dependencies of the project, direct and transitive.
+	Dependencies []MavenDependency
+	// The local Maven repository to which the dependencies' files are written. A temporary directory is used if it's empty.
+	LocalRepository string
+}
+
+// MavenDependency is a dependency of a synthetic Maven project.
+type MavenDependency struct {
+	GroupId    string
+	ArtifactId string
+	Version    string
+	// The type of the dependency's file. The default is jar.
+	Type string
+	// The scope of the dependency. The default is compile.
+	Scope string
+	// The URL of the remote repository from which the dependency was downloaded.
+	// If it's empty, the dependency is logged as if it was already in the local repository.
+	RepositoryUrl string
+}
+
+// MavenFixture is a synthetic Maven project created on the disk.
+type MavenFixture struct {
+	// The directory which contains the project's pom.xml.
+	Dir string
+	// The local Maven repository, which contains the dependencies' files.
+	LocalRepository string
+	// The log of building the project in batch mode with the dependency:list goal, for example for MavenModule.CalcDependenciesFromLog.
+	Log string
+}
+
+// CreateMavenProject writes the project's pom.xml and its dependencies' files in the local repository, and generates its build log.
+// The content of each dependency's file is its ID, so its checksums are deterministic.
+func CreateMavenProject(t testing.TB, project MavenProject) *MavenFixture {
+	fixture := &MavenFixture{Dir: t.TempDir(), LocalRepository: project.LocalRepository}
+	if fixture.LocalRepository == "" {
+		fixture.LocalRepository = t.TempDir()
+	}
+	var pomDependencies, downloads, resolved strings.Builder
+	for _, dependency := range project.Dependencies {
+		dependencyType, scope := defaultIfEmpty(dependency.Type, "jar"), defaultIfEmpty(dependency.Scope, "compile")
+		fmt.Fprintf(&pomDependencies, "    <dependency>\n      <groupId>%s</groupId>\n      <artifactId>%s</artifactId>\n      <version>%s</version>\n      <type>%s</type>\n      <scope>%s</scope>\n    </dependency>\n",
+			dependency.GroupId, dependency.ArtifactId, dependency.Version, dependencyType, scope)
+		relativePath := strings.Join(append(strings.Split(dependency.GroupId, "."), dependency.ArtifactId, dependency.Version, dependency.ArtifactId+"-"+dependency.Version+"."+dependencyType), "/")
+		writeFile(t, filepath.Join(fixture.LocalRepository, filepath.FromSlash(relativePath)), strings.Join([]string{dependency.GroupId, dependency.ArtifactId, dependency.Version}, ":"))
+		if dependency.RepositoryUrl != "" {
+			fmt.Fprintf(&downloads, "[INFO] Downloaded from remote: %s/%s (1 kB at 10 kB/s)\n", strings.TrimSuffix(dependency.RepositoryUrl, "/"), relativePath)
+		}
+		fmt.Fprintf(&resolved, "[INFO]    %s:%s:%s:%s:%s\n", dependency.GroupId, dependency.ArtifactId, dependencyType, dependency.Version, scope)
+	}
+	writeFile(t, filepath.Join(fixture.Dir, "pom.xml"), fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>%s</groupId>
+  <artifactId>%s</artifactId>
+  <version>%s</version>
+  <dependencies>
+%s  </dependencies>
+</project>
+`, project.GroupId, project.ArtifactId, project.Version, pomDependencies.String()))
+	fixture.Log = fmt.Sprintf(`[INFO] Scanning for projects...
+[INFO]
+[INFO] -------------------------< %s:%s >--------------------------
+[INFO] Building %s %s
+[INFO] --------------------------------[ jar ]---------------------------------
+%s[INFO]
+[INFO] --- dependency:3.6.0:list (default-cli) @ %s ---
+[INFO]
+[INFO] The following files have been resolved:
+%s[INFO]
+[INFO] BUILD SUCCESS
+`, project.GroupId, project.ArtifactId, project.ArtifactId, project.Version, downloads.String(), project.ArtifactId, resolved.String())
+	return fixture
+}
+
+// GradleProject describes a synthetic Gradle project, written in the Groovy DSL.
+type GradleProject struct {
+	Group   string
+	Name    string
+	Version string
+	// The dependencies of the project, in the group:name:version format, mapped by their configurations, for example: implementation
+	Dependencies map[string][]string
+	// The plugins applied by the root project in its 'plugins {}' block, with their versions.
+	Plugins []GradlePlugin
+	// The subprojects included by the settings file, each with its own build script.
+	Subprojects []GradleProject
+}
+
+// GradlePlugin is a plugin applied by a synthetic Gradle project.
+type GradlePlugin struct {
+	Id      string
+	Version string
+}
+
+// GradleFixture is a synthetic Gradle project created on the disk.
+type GradleFixture struct {
+	// The root project's directory, which contains its settings.gradle and build.gradle.
+	Dir string
+	// A build-info file with a module for each project and its declared dependencies, like the one generated by the Gradle extractor.
+	ExtractorBuildInfoPath string
+}
+
+// CreateGradleProject writes the project's settings and build scripts, and a build-info like the one the Gradle extractor generates for it.
+// The subprojects are written to directories named after them, and they inherit the root project's group and version.
+func CreateGradleProject(t testing.TB, project GradleProject) *GradleFixture {
+	fixture := &GradleFixture{Dir: t.TempDir()}
+	settings := fmt.Sprintf("rootProject.name = '%s'\n", project.Name)
+	buildInfo := entities.BuildInfo{Modules: []entities.Module{createGradleModule(project, project)}}
+	writeFile(t, filepath.Join(fixture.Dir, "build.gradle"), createGradleBuildScript(project, true))
+	for _, subproject := range project.Subprojects {
+		settings += fmt.Sprintf("include ':%s'\n", subproject.Name)
+		writeFile(t, filepath.Join(fixture.Dir, subproject.Name, "build.gradle"), createGradleBuildScript(subproject, false))
+		buildInfo.Modules = append(buildInfo.Modules, createGradleModule(project, subproject))
+	}
+	writeFile(t, filepath.Join(fixture.Dir, "settings.gradle"), settings)
+	content, err := json.MarshalIndent(buildInfo, "", "  ")
+	require.NoError(t, err)
+	fixture.ExtractorBuildInfoPath = filepath.Join(t.TempDir(), "build-info.json")
+	writeFile(t, fixture.ExtractorBuildInfoPath, string(content))
+	return fixture
+}
+
+func createGradleBuildScript(project GradleProject, root bool) string {
+	var buildScript strings.Builder
+	if len(project.Plugins) > 0 {
+		buildScript.WriteString("plugins {\n")
+		for _, plugin := range project.Plugins {
+			fmt.Fprintf(&buildScript, "    id '%s' version '%s'\n", plugin.Id, plugin.Version)
+		}
+		buildScript.WriteString("}\n\n")
+	}
+	if root {
+		fmt.Fprintf(&buildScript, "group = '%s'\nversion = '%s'\n\n", project.Group, project.Version)
+	}
+	buildScript.WriteString("repositories {\n    mavenCentral()\n}\n\ndependencies {\n")
+	for _, configuration := range sortedKeys(project.Dependencies) {
+		for _, dependency := range project.Dependencies[configuration] {
+			fmt.Fprintf(&buildScript, "    %s '%s'\n", configuration, dependency)
+		}
+	}
+	buildScript.WriteString("}\n")
+	return buildScript.String()
+}
+
+func createGradleModule(root, project GradleProject) entities.Module {
+	module := entities.Module{Id: root.Group + ":" + project.Name + ":" + root.Version, Type: entities.Gradle}
+	for _, configuration := range sortedKeys(project.Dependencies) {
+		for _, dependency := range project.Dependencies[configuration] {
+			module.Dependencies = append(module.Dependencies, entities.Dependency{Id: dependency, Type: "jar", Scopes: []string{configuration}})
+		}
+	}
+	return module
+}
+
+// NpmProject describes a synthetic npm project, whose dependencies are installed from its package-lock.json (lockfile version 3).
+type NpmProject struct {
+	Name    string
+	Version string
+	// The names of the project's direct dependencies and dev dependencies, which must be listed in the packages.
+	Dependencies    []string
+	DevDependencies []string
+	// The installed packages, direct and transitive, which are all hoisted to the root node_modules directory.
+	Packages []NpmPackage
+}
+
+// NpmPackage is an installed package of a synthetic npm project.
+type NpmPackage struct {
+	Name    string
+	Version string
+	// The names of the packages which this package depends on, which must be listed in the project's packages.
+	Dependencies []string
+	// True if the package is needed only by the project's dev dependencies.
+	Dev bool
+	// The URL of the package's tarball. The default is the package's tarball in the npm registry.
+	Resolved string
+	// The integrity of the package's tarball. The default is the SHA-512 of the package's ID.
+	Integrity string
+}
+
+// NpmFixture is a synthetic npm project created on the disk.
+type NpmFixture struct {
+	// The directory which contains the project's package.json and package-lock.json.
+	Dir string
+}
+
+// CreateNpmProject writes the project's package.json and package-lock.json.
+func CreateNpmProject(t testing.TB, project NpmProject) *NpmFixture {
+	fixture := &NpmFixture{Dir: t.TempDir()}
+	versions := make(map[string]string)
+	for _, pkg := range project.Packages {
+		versions[pkg.Name] = pkg.Version
+	}
+	requirements := func(names []string) map[string]string {
+		if len(names) == 0 {
+			return nil
+		}
+		required := make(map[string]string)
+		for _, name := range names {
+			require.Contains(t, versions, name, "the package %s isn't listed in the project's packages", name)
+			required[name] = versions[name]
+		}
+		return required
+	}
+	packageJson := map[string]interface{}{"name": project.Name, "version": project.Version}
+	rootPackage := map[string]interface{}{"name": project.Name, "version": project.Version}
+	if dependencies := requirements(project.Dependencies); dependencies != nil {
+		packageJson["dependencies"], rootPackage["dependencies"] = dependencies, dependencies
+	}
+	if devDependencies := requirements(project.DevDependencies); devDependencies != nil {
+		packageJson["devDependencies"], rootPackage["devDependencies"] = devDependencies, devDependencies
+	}
+	packages := map[string]interface{}{"": rootPackage}
+	for _, pkg := range project.Packages {
+		entry := map[string]interface{}{
+			"version":   pkg.Version,
+			"resolved":  defaultIfEmpty(pkg.Resolved, fmt.Sprintf("https://registry.npmjs.org/%s/-/%s-%s.tgz", pkg.Name, pkg.Name[strings.LastIndex(pkg.Name, "/")+1:], pkg.Version)),
+			"integrity": defaultIfEmpty(pkg.Integrity, npmIntegrity(pkg.Name+"@"+pkg.Version)),
+		}
+		if dependencies := requirements(pkg.Dependencies); dependencies != nil {
+			entry["dependencies"] = dependencies
+		}
+		if pkg.Dev {
+			entry["dev"] = true
+		}
+		packages["node_modules/"+pkg.Name] = entry
+	}
+	lockfile := map[string]interface{}{"name": project.Name, "version": project.Version, "lockfileVersion": 3, "requires": true, "packages": packages}
+	for fileName, content := range map[string]interface{}{"package.json": packageJson, "package-lock.json": lockfile} {
+		marshaled, err := json.MarshalIndent(content, "", "  ")
+		require.NoError(t, err)
+		writeFile(t, filepath.Join(fixture.Dir, fileName), string(marshaled)+"\n")
+	}
+	return fixture
+}
+
+// Manifests returns the project's package.json and package-lock.json as a JSON payload, for example for Build.AddNpmModuleFromManifests.
+func (nf *NpmFixture) Manifests(t testing.TB) io.Reader {
+	payload := make(map[string]json.RawMessage)
+	for _, fileName := range []string{"package.json", "package-lock.json"} {
+		content, err := os.ReadFile(filepath.Join(nf.Dir, fileName))
+		require.NoError(t, err)
+		payload[fileName] = content
+	}
+	content, err := json.Marshal(payload)
+	require.NoError(t, err)
+	return bytes.NewReader(content)
+}
+
+func npmIntegrity(id string) string {
+	checksum := sha512.Sum512([]byte(id))
+	return "sha512-" + base64.StdEncoding.EncodeToString(checksum[:])
+}
+
+func writeFile(t testing.TB, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func defaultIfEmpty(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -2,40 +2,231 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
-	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/jfrog/build-info-go/api"
 	"github.com/jfrog/build-info-go/build"
+	buildutils "github.com/jfrog/build-info-go/build/utils"
+	"github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/build-info-go/utils/pythonutils"
 	clitool "github.com/urfave/cli/v2"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 const (
-	formatFlag    = "format"
-	cycloneDxXml  = "cyclonedx/xml"
-	cycloneDxJson = "cyclonedx/json"
+	formatFlag            = "format"
+	resolutionAuditFlag   = "resolution-audit"
+	cacheTimestampsFlag   = "cache-timestamps"
+	threadsFlag           = "threads"
+	incrementalFlag       = "incremental"
+	outputFlag            = "output"
+	debounceFlag          = "debounce"
+	tempFlag              = "temp"
+	olderThanFlag         = "older-than"
+	buildPluginsFlag      = "build-plugins"
+	verifyIntegrityFlag   = "verify-integrity"
+	requireSumDbFlag      = "require-sumdb"
+	buildPropFlag         = "build-prop"
+	modulePropFlag        = "module-prop"
+	compressFlag          = "compress"
+	forceFlag             = "force"
+	sbomTypeFlag          = "type"
+	moduleIdFlag          = "module"
+	fromLogFlag           = "from-log"
+	settingsFlag          = "settings"
+	globalSettingsFlag    = "global-settings"
+	profilesFlag          = "profiles"
+	pomFileFlag           = "pom-file"
+	mavenPropFlag         = "maven-prop"
+	treeParallelismFlag   = "tree-parallelism"
+	treeThreadsFlag       = "tree-threads"
+	useWrapperFlag        = "use-wrapper"
+	verifyWrapperFlag     = "verify-wrapper"
+	noDaemonFlag          = "no-daemon"
+	recursiveFlag         = "recursive"
+	maxDepthFlag          = "max-depth"
+	excludeDepFlag        = "exclude-dep"
+	testReportFlag        = "test-report"
+	coverageReportFlag    = "coverage-report"
+	coverageArtsFlag      = "coverage-artifacts"
+	checksumCacheFlag     = "checksum-cache"
+	maxEntriesFlag        = "max-entries"
+	patternFlag           = "pattern"
+	subArtifactsFlag      = "sub-artifacts"
+	analyzeJarFlag        = "analyze-jar"
+	collectWorkspacesFlag = "collect-workspaces"
+	collectPackagesFlag   = "collect-packages"
+	offlineFlag           = "offline"
+	lowMemoryFlag         = "low-memory"
+	manifestFlag          = "manifest"
+	dependenciesFileFlag  = "file"
+	policyFlag            = "policy"
+	schemaFlag            = "schema"
+	bundleNameFlag        = "name"
+	bundleVersionFlag     = "version"
+	repoFlag              = "repo"
+	buildDirFlag          = "build-dir"
+	installedDirFlag      = "installed-dir"
+	postProcessFlag       = "post-process"
+	auditSignaturesFlag   = "audit-signatures"
+	buildNameFlag         = "build-name"
+	buildNumberFlag       = "build-number"
+	projectFlag           = "project"
+	npmRegistryFlag       = "npm-registry"
+	mavenRepoFlag         = "maven-repository"
+	pypiIndexFlag         = "pypi-index"
+	errorFormatFlag       = "error-format"
+	statsFlag             = "stats"
+	allowCustomTypeFlag   = "allow-custom-type"
+	errorFormatText       = "text"
+	errorFormatJson       = "json"
+	outputJsonl           = "jsonl"
+
+	// The default number of registry requests sent in parallel.
+	defaultRegistryThreads = 5
+	// The environment variable of the base64 encoded key which encrypts and decrypts the properties matching the encryptProperties patterns of bi.yaml.
+	encryptionKeyEnv = "BUILD_INFO_ENCRYPTION_KEY"
 )
 
+// GetGlobalFlags returns the flags which are shared by all the commands. They should be placed before the command name.
+// errorFormat - The value of the '--error-format' flag is written to this destination.
+func GetGlobalFlags(errorFormat *string) []clitool.Flag {
+	return []clitool.Flag{
+		&clitool.StringFlag{
+			Name:        errorFormatFlag,
+			Value:       errorFormatText,
+			Destination: errorFormat,
+			Usage:       fmt.Sprintf("[Default: %s] Set the format of the printed errors. Supported values are '%s' and '%s'.` `", errorFormatText, errorFormatText, errorFormatJson),
+		},
+	}
+}
+
+// HandleError prints the error in the requested format and returns the process exit code matching the error's category.
+func HandleError(err error, errorFormat string, logger utils.Log) int {
+	errorDetails := utils.NewErrorDetails(err)
+	if errorFormat == errorFormatJson {
+		content, marshalErr := json.Marshal(errorDetails)
+		if marshalErr == nil {
+			fmt.Fprintln(os.Stderr, string(content))
+			return errorDetails.ExitCode
+		}
+		logger.Debug("Failed to format the error as JSON:", marshalErr.Error())
+	}
+	logger.Error(err)
+	return errorDetails.ExitCode
+}
+
 func GetCommands(logger utils.Log) []*clitool.Command {
 	flags := []clitool.Flag{
 		&clitool.StringFlag{
 			Name:  formatFlag,
-			Usage: fmt.Sprintf("[Optional] Set to convert the build-info to a different format. Supported values are '%s' and '%s'.` `", cycloneDxXml, cycloneDxJson),
+			Usage: fmt.Sprintf("[Optional] Set to convert the build-info to a different format. Supported values are '%s' and '%s', or '%s' for a human-readable summary of the modules.` `", api.CycloneDxXmlFormat, api.CycloneDxJsonFormat, api.TableFormat),
+		},
+		&clitool.BoolFlag{
+			Name:  resolutionAuditFlag,
+			Usage: "[Default: false] Set to record how each dependency was resolved (lockfile, CLI tree, cache, etc.) and print a summary at the end.` `",
+		},
+		&clitool.BoolFlag{
+			Name:  cacheTimestampsFlag,
+			Usage: "[Default: false] Set to record the time in which each dependency was resolved, and the modification time of its file in the local caches, in the dependency's properties.` `",
+		},
+		&clitool.StringSliceFlag{
+			Name:  buildPropFlag,
+			Usage: "[Optional] A property to add to the build-info, in the key=value format. Can be repeated.` `",
+		},
+		&clitool.StringSliceFlag{
+			Name:  modulePropFlag,
+			Usage: "[Optional] A property to add to each of the build-info modules, in the key=value format. Can be repeated.` `",
+		},
+		&clitool.StringSliceFlag{
+			Name:  excludeDepFlag,
+			Usage: "[Optional] A wildcard pattern of the IDs of dependencies to exclude from the build-info, or a comma-separated list of id=, group=, scope= and regex= rule fields, for example: 'group=org.example,scope=test'. Can be repeated.` `",
+		},
+		&clitool.StringSliceFlag{
+			Name:  testReportFlag,
+			Usage: "[Optional] A path of a test report whose passed, failed and skipped tests counts and duration are added to the build-info properties. Supported formats are JUnit XML, 'go test -json' output and pytest output. The path may contain wildcards, for example: 'target/surefire-reports/TEST-*.xml'. Can be repeated.` `",
+		},
+		&clitool.StringSliceFlag{
+			Name:  coverageReportFlag,
+			Usage: "[Optional] A path of a code coverage report whose covered and total lines (or statements, for Go cover profiles) are added to the build-info properties. Supported formats are lcov, Cobertura XML and Go cover profiles. The path may contain wildcards. Can be repeated.` `",
+		},
+		&clitool.BoolFlag{
+			Name:  coverageArtsFlag,
+			Usage: fmt.Sprintf("[Default: false] Set to also add the coverage reports as artifacts, with their checksums, to the '%s' module.` `", build.CoverageReportsModuleId),
+		},
+		&clitool.BoolFlag{
+			Name:  compressFlag,
+			Usage: "[Default: false] Set to compress the build-info output with gzip.` `",
+		},
+		&clitool.StringSliceFlag{
+			Name:  postProcessFlag,
+			Usage: "[Optional] A path of a script which receives the build-info JSON in its standard input and writes the modified build-info to its standard output, for example to rename modules, add properties or remove dependencies. A .jq script runs with 'jq -f', a .go file with 'go run', and any other script as an executable. Can be repeated.` `",
+		},
+		&clitool.StringFlag{
+			Name:  outputFlag,
+			Usage: fmt.Sprintf("[Optional] Set to '%s' to stream one JSON event per line to the standard output for each dependency and module as soon as it's collected, followed by an event with the build-info.` `", outputJsonl),
+		},
+		&clitool.BoolFlag{
+			Name:  statsFlag,
+			Usage: "[Default: false] Print the statistics of the collection to the standard error: the dependencies found, the checksums computed and missing, the cache hits, and the external commands run and their duration.` `",
+		},
+		&clitool.BoolFlag{
+			Name:  allowCustomTypeFlag,
+			Usage: "[Default: false] Set to allow modules of types which aren't known module types, such as types set by post-processors. By default, the command fails if a module has an unknown type.` `",
 		},
 	}
+	incrementalFlags := append(slices.Clone(flags), &clitool.BoolFlag{
+		Name:  incrementalFlag,
+		Usage: "[Default: false] Set to skip the dependencies resolution of projects whose manifests and lockfiles haven't changed since the last run.` `",
+	}, &clitool.BoolFlag{
+		Name:  checksumCacheFlag,
+		Usage: "[Default: false] Set to keep the checksums of the dependencies' files in a cache in the user's cache directory, so that files which haven't changed since the last run aren't hashed again.` `",
+	})
+	integrityFlag := &clitool.StringFlag{
+		Name:  verifyIntegrityFlag,
+		Usage: fmt.Sprintf("[Optional] Set to verify the dependencies' checksums against the hashes in the project's lockfile. Supported values are '%s', to log a warning for each mismatch, and '%s', to fail the collection.` `", utils.IntegrityVerificationWarn, utils.IntegrityVerificationFail),
+	}
+	jarAnalysisFlag := &clitool.StringSliceFlag{
+		Name:  analyzeJarFlag,
+		Usage: "[Optional] A path of a built jar, such as a fat jar or a shaded jar, whose embedded Maven artifacts are reconciled with the dependencies of the module which has an artifact of the same name. The artifacts the module doesn't declare are added as dependencies. The path may contain wildcards. Can be repeated.` `",
+	}
+	buildPluginsFlags := append(slices.Clone(incrementalFlags), &clitool.BoolFlag{
+		Name:  buildPluginsFlag,
+		Usage: "[Default: false] Set to add the build plugins (and Maven extensions or Gradle buildscript classpath) to the build-info.` `",
+	})
+	pythonFlags := append(slices.Clone(flags), &clitool.BoolFlag{
+		Name:  collectPackagesFlag,
+		Usage: "[Default: false] Set to collect each package of a repository with several packages, which have their own pyproject.toml or setup.py files, as a separate module.` `",
+	})
 
 	return []*clitool.Command{
 		{
 			Name:      "go",
 			Usage:     "Generate build-info for a Go project",
 			UsageText: "bi go",
-			Flags:     flags,
+			Flags: append(slices.Clone(incrementalFlags), integrityFlag, &clitool.BoolFlag{
+				Name:  requireSumDbFlag,
+				Usage: "[Default: false] Set to fail if the checksum database verification of the Go modules is disabled, for example by GOSUMDB=off, GONOSUMCHECK=1 or GOFLAGS=-insecure.` `",
+			}, &clitool.BoolFlag{
+				Name:  recursiveFlag,
+				Usage: "[Default: false] Set to collect all the Go modules under the working directory, such as tools/ or examples/ modules, rather than only the module of the working directory. The vendor and testdata directories are skipped.` `",
+			}, &clitool.IntFlag{
+				Name:  maxDepthFlag,
+				Usage: fmt.Sprintf("[Optional] The maximal depth of the directories searched for Go modules when '--%s' is set. If not set, the depth isn't limited.` `", recursiveFlag),
+			}),
 			Action: func(context *clitool.Context) (err error) {
 				service := build.NewBuildInfoService()
 				service.SetLogger(logger)
@@ -46,22 +237,99 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 				defer func() {
 					err = errors.Join(err, bld.Clean())
 				}()
-				goModule, err := bld.AddGoModule("")
-				if err != nil {
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
 					return
 				}
-				err = goModule.CalcDependencies()
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.GoToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
+				setIncrementalCacheDir(bld, context.Bool(incrementalFlag))
+				checksumCache, err := setChecksumCache(bld, context.Bool(checksumCacheFlag))
 				if err != nil {
 					return
 				}
-				return printBuild(bld, context.String(formatFlag))
+				defer func() {
+					err = errors.Join(err, checksumCache.Save())
+				}()
+				if err = setIntegrityVerification(bld, context.String(verifyIntegrityFlag)); err != nil {
+					return
+				}
+				modulePaths := []string{""}
+				if context.Bool(recursiveFlag) {
+					if modulePaths, err = build.DiscoverGoModules(".", context.Int(maxDepthFlag)); err != nil {
+						return
+					}
+					if len(modulePaths) == 0 {
+						return errors.New("no Go modules were found in the working directory")
+					}
+				}
+				for _, modulePath := range modulePaths {
+					if modulePath == "." {
+						modulePath = ""
+					}
+					inputs := build.IncrementalInputs{Options: []string{requireSumDbFlag + "=" + strconv.FormatBool(context.Bool(requireSumDbFlag))}}
+					err = bld.CollectIncrementallyWithInputs(modulePath, build.GoTechnology, inputs, func(containingBuild *build.Build) error {
+						goModule, err := containingBuild.AddGoModule(modulePath)
+						if err != nil {
+							return err
+						}
+						goModule.SetRequireSumDbVerification(context.Bool(requireSumDbFlag))
+						return goModule.CalcDependencies()
+					})
+					if err != nil {
+						return
+					}
+				}
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
 			},
 		},
 		{
 			Name:      "mvn",
 			Usage:     "Generate build-info for a Maven project",
 			UsageText: "bi mvn",
-			Flags:     flags,
+			Flags: append(slices.Clone(buildPluginsFlags), &clitool.StringFlag{
+				Name:  fromLogFlag,
+				Usage: "[Optional] The path of a Maven build log, written in batch mode (-B), or '-' to read it from the standard input. Set to approximate the build-info from the log, rather than running Maven with the build-info extractor. The modules approximated from the log are marked as lower-fidelity.` `",
+			}, &clitool.StringFlag{
+				Name:  settingsFlag,
+				Usage: "[Optional] The path of the user settings file, passed to Maven with the -s option. Defaults to ~/.m2/settings.xml.` `",
+			}, &clitool.StringFlag{
+				Name:  globalSettingsFlag,
+				Usage: "[Optional] The path of the global settings file, passed to Maven with the -gs option. Defaults to conf/settings.xml in Maven's home.` `",
+			}, &clitool.StringFlag{
+				Name:  profilesFlag,
+				Usage: "[Optional] A comma-separated list of profiles to activate, or to deactivate with a '!' prefix, passed to Maven with the -P option. Overrides the profiles of the configuration file.` `",
+			}, &clitool.StringFlag{
+				Name:  pomFileFlag,
+				Usage: "[Optional] The path of the POM, or of its directory, passed to Maven with the -f option. Overrides the POM of the configuration file. Defaults to pom.xml.` `",
+			}, &clitool.StringSliceFlag{
+				Name:  mavenPropFlag,
+				Usage: "[Optional] A system property passed to Maven with the -D option, in the key=value format. Added to the properties of the configuration file. Can be repeated.` `",
+			}, &clitool.BoolFlag{
+				Name:  useWrapperFlag,
+				Usage: "[Default: false] Set to build the project with the Maven distribution of its wrapper (mvnw) rather than with the Maven installation in the PATH. The URL and the SHA-256 checksum of the wrapper's distribution are added to the build properties.` `",
+			}, &clitool.IntFlag{
+				Name:  treeParallelismFlag,
+				Usage: "[Optional] Set to collect the dependencies with Maven's dependency:tree goal rather than with the build-info extractor, by this number of parallel Maven invocations, each of a batch of the reactor modules passed with the -pl option. The project isn't built.` `",
+			}, &clitool.StringFlag{
+				Name:  treeThreadsFlag,
+				Usage: "[Optional] The threads of each Maven invocation which collects the dependencies with the dependency:tree goal, passed to Maven with the -T option, for example: 4 or 1C. Requires --" + treeParallelismFlag + ".` `",
+			}, jarAnalysisFlag),
 			Action: func(context *clitool.Context) (err error) {
 				service := build.NewBuildInfoService()
 				service.SetLogger(logger)
@@ -72,23 +340,90 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 				defer func() {
 					err = errors.Join(err, bld.Clean())
 				}()
-				mavenModule, err := bld.AddMavenModule("")
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
+				bld.AnalyzeJars("", context.StringSlice(analyzeJarFlag)...)
+				if logPath := context.String(fromLogFlag); logPath != "" {
+					if err = calcMavenDependenciesFromLog(bld, logPath); err != nil {
+						return
+					}
+					return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
+				}
+				if err = bld.CollectToolchain("", build.JavaToolchain, build.MavenToolchain); err != nil {
+					return
+				}
+				setIncrementalCacheDir(bld, context.Bool(incrementalFlag))
+				checksumCache, err := setChecksumCache(bld, context.Bool(checksumCacheFlag))
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, checksumCache.Save())
+				}()
+				mavenConfig, err := getMavenConfig(context)
 				if err != nil {
 					return
 				}
-				err = mavenModule.CalcDependencies()
+				inputs, err := getMavenIncrementalInputs(context, mavenConfig)
+				if err != nil {
+					return
+				}
+				err = bld.CollectIncrementallyWithInputs("", build.MavenTechnology, inputs, func(containingBuild *build.Build) error {
+					mavenModule, err := containingBuild.AddMavenModule("")
+					if err != nil {
+						return err
+					}
+					mavenModule.SetCollectBuildPlugins(context.Bool(buildPluginsFlag))
+					mavenModule.SetSettingsFiles(context.String(settingsFlag), context.String(globalSettingsFlag))
+					mavenModule.SetConfig(mavenConfig)
+					mavenModule.SetUseWrapper(context.Bool(useWrapperFlag))
+					if treeParallelism := context.Int(treeParallelismFlag); treeParallelism > 0 {
+						mavenModule.SetTreeParallelism(treeParallelism)
+						mavenModule.SetTreeThreads(context.String(treeThreadsFlag))
+						return mavenModule.CalcDependenciesFromTree()
+					}
+					return mavenModule.CalcDependencies()
+				})
 				if err != nil {
 					return
 				}
-				return printBuild(bld, context.String(formatFlag))
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
 			},
 		},
 		{
 			Name:      "gradle",
 			Usage:     "Generate build-info for a Gradle project",
 			UsageText: "bi gradle",
-			Flags:     flags,
+			Flags: append(slices.Clone(buildPluginsFlags), &clitool.BoolFlag{
+				Name:  useWrapperFlag,
+				Usage: "[Default: false] Set to build the project with its Gradle wrapper (gradlew) rather than with the gradle executable in the PATH. The URL and the SHA-256 checksum of the wrapper's distribution are added to the build properties.` `",
+			}, &clitool.BoolFlag{
+				Name:  verifyWrapperFlag,
+				Usage: "[Default: false] Set to verify the Gradle distribution of the wrapper by the checksum published in the official Gradle distributions, and fail if they don't match. Requires --" + useWrapperFlag + ".` `",
+			}, &clitool.BoolFlag{
+				Name:  noDaemonFlag,
+				Usage: "[Default: false] Set to run Gradle without its daemon. Overrides the noDaemon option of the configuration file.` `",
+			}, integrityFlag, jarAnalysisFlag),
 			Action: func(context *clitool.Context) (err error) {
+				if context.Bool(verifyWrapperFlag) && !context.Bool(useWrapperFlag) {
+					return fmt.Errorf("the '%s' option can't be used without the '%s' option", verifyWrapperFlag, useWrapperFlag)
+				}
 				service := build.NewBuildInfoService()
 				service.SetLogger(logger)
 				bld, err := service.GetOrCreateBuild("gradle-build", "1")
@@ -98,22 +433,94 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 				defer func() {
 					err = errors.Join(err, bld.Clean())
 				}()
-				gradleModule, err := bld.AddGradleModule("")
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.JavaToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
+				bld.AnalyzeJars("", context.StringSlice(analyzeJarFlag)...)
+				setIncrementalCacheDir(bld, context.Bool(incrementalFlag))
+				checksumCache, err := setChecksumCache(bld, context.Bool(checksumCacheFlag))
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, checksumCache.Save())
+				}()
+				if err = setIntegrityVerification(bld, context.String(verifyIntegrityFlag)); err != nil {
+					return
+				}
+				config, err := build.ReadConfig(".")
+				if err != nil {
+					return
+				}
+				gradleConfig := config.Gradle
+				gradleConfig.NoDaemon = gradleConfig.NoDaemon || context.Bool(noDaemonFlag)
+				inputs, err := getGradleIncrementalInputs(context, gradleConfig)
 				if err != nil {
 					return
 				}
-				err = gradleModule.CalcDependencies()
+				err = bld.CollectIncrementallyWithInputs("", build.GradleTechnology, inputs, func(containingBuild *build.Build) error {
+					gradleModule, err := containingBuild.AddGradleModule("")
+					if err != nil {
+						return err
+					}
+					gradleModule.SetConfig(gradleConfig)
+					gradleModule.SetCollectBuildPlugins(context.Bool(buildPluginsFlag))
+					gradleModule.SetUseWrapper(context.Bool(useWrapperFlag))
+					gradleModule.SetVerifyWrapperDistribution(context.Bool(verifyWrapperFlag))
+					return gradleModule.CalcDependencies()
+				})
 				if err != nil {
 					return
 				}
-				return printBuild(bld, context.String(formatFlag))
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
 			},
 		},
 		{
 			Name:      "npm",
 			Usage:     "Generate build-info for an npm project",
 			UsageText: "bi npm",
-			Flags:     flags,
+			Flags: append(slices.Clone(flags), integrityFlag, &clitool.StringFlag{
+				Name:  manifestFlag,
+				Usage: "[Optional] A path of a JSON object or a tar archive with the project's package.json and package-lock.json, or '-' to read it from the standard input. The dependencies are built from the lockfile, without a project directory.` `",
+			}, &clitool.BoolFlag{
+				Name:  offlineFlag,
+				Usage: "[Default: false] Set to build the dependencies tree from the lockfile, without running npm.` `",
+			}, &clitool.BoolFlag{
+				Name:  collectWorkspacesFlag,
+				Usage: "[Default: false] Set to collect each workspace declared in the package.json as a separate module, instead of the root project.` `",
+			}, &clitool.IntFlag{
+				Name:  threadsFlag,
+				Value: 3,
+				Usage: "[Default: 3] Number of workspaces to collect in parallel.` `",
+			}, &clitool.BoolFlag{
+				Name:  lowMemoryFlag,
+				Usage: "[Default: false] Set to parse the output of 'npm ls' while it's written, rather than reading it to memory first, and to collect the workspaces one at a time.` `",
+			}, &clitool.BoolFlag{
+				Name:  auditSignaturesFlag,
+				Usage: "[Default: false] Set to record whether each dependency's version is signed by the npm registry and has a provenance attestation.` `",
+			}, &clitool.StringFlag{
+				Name:  npmRegistryFlag,
+				Value: utils.DefaultRegistryEndpoints().Npm,
+				Usage: fmt.Sprintf("[Default: %s] The npm registry queried for the signatures of the dependencies.` `", utils.DefaultRegistryEndpoints().Npm),
+			}),
 			Action: func(context *clitool.Context) (err error) {
 				service := build.NewBuildInfoService()
 				service.SetLogger(logger)
@@ -124,19 +531,105 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 				defer func() {
 					err = errors.Join(err, bld.Clean())
 				}()
-				npmModule, err := bld.AddNpmModule("")
+				commandArgs := newToolArgs(context)
+				offline := commandArgs.boolValue(offlineFlag)
+				manifestPath, err := commandArgs.stringValue(manifestFlag)
+				if err != nil {
+					return
+				}
+				formatValue, err := commandArgs.stringValue(formatFlag)
+				if err != nil {
+					return
+				}
+				integrityValue, err := commandArgs.stringValue(verifyIntegrityFlag)
+				if err != nil {
+					return
+				}
+				if err = setIntegrityVerification(bld, integrityValue); err != nil {
+					return
+				}
+				buildProps, err := commandArgs.stringValues(buildPropFlag)
+				if err != nil {
+					return
+				}
+				moduleProps, err := commandArgs.stringValues(modulePropFlag)
+				if err != nil {
+					return
+				}
+				if err = setProperties(bld, buildProps, moduleProps); err != nil {
+					return
+				}
+				excludeDeps, err := commandArgs.stringValues(excludeDepFlag)
+				if err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, excludeDeps); err != nil {
+					return
+				}
+				// The signatures are recorded before the post-processing scripts run, so that the scripts can use them.
+				auditSignatures := commandArgs.boolValue(auditSignaturesFlag)
+				npmRegistry, err := commandArgs.stringValue(npmRegistryFlag)
+				if err != nil {
+					return
+				}
+				if auditSignatures {
+					bld.AddPostProcessors(build.NewNpmSignaturesPostProcessor(context.Context, npmRegistry, defaultRegistryThreads, logger))
+				}
+				postProcessScripts, err := commandArgs.stringValues(postProcessFlag)
+				if err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, postProcessScripts); err != nil {
+					return
+				}
+				testReports, err := commandArgs.stringValues(testReportFlag)
+				if err != nil {
+					return
+				}
+				bld.AddTestReports("", testReports...)
+				coverageReports, err := commandArgs.stringValues(coverageReportFlag)
+				if err != nil {
+					return
+				}
+				bld.AddCoverageReports("", commandArgs.boolValue(coverageArtsFlag), coverageReports...)
+				bld.SetResolutionAudit(commandArgs.boolValue(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(commandArgs.boolValue(cacheTimestampsFlag))
+				setStats(bld, commandArgs.boolValue(statsFlag))
+				bld.SetAllowCustomModuleTypes(commandArgs.boolValue(allowCustomTypeFlag))
+				compress := commandArgs.boolValue(compressFlag)
+				outputValue, err := commandArgs.stringValue(outputFlag)
+				if err != nil {
+					return
+				}
+				if err = setOutput(bld, outputValue, formatValue, compress); err != nil {
+					return
+				}
+				var npmModule *build.NpmModule
+				if manifestPath != "" {
+					npmModule, err = addNpmModuleFromManifests(bld, manifestPath)
+				} else if offline {
+					npmModule, err = bld.AddOfflineNpmModule("")
+				} else {
+					if err = bld.CollectToolchain("", build.NodeToolchain, build.NpmToolchain); err != nil {
+						return
+					}
+					npmModule, err = bld.AddNpmModule("")
+				}
 				if err != nil {
 					return
 				}
-				formatValue, filteredArgs, err := extractStringFlag(context.Args().Slice(), formatFlag)
+				npmModule.SetCollectWorkspaces(commandArgs.boolValue(collectWorkspacesFlag))
+				threads, err := commandArgs.intValue(threadsFlag)
 				if err != nil {
 					return
 				}
-				npmModule.SetNpmArgs(filteredArgs)
+				npmModule.SetThreads(threads)
+				npmModule.SetLowMemory(commandArgs.boolValue(lowMemoryFlag))
+				npmModule.SetNpmArgs(commandArgs.args)
 				if err = npmModule.Build(); err != nil {
 					return err
 				}
-				return printBuild(bld, formatValue)
+				return printBuild(bld, formatValue, outputValue, compress)
 			},
 		},
 		{
@@ -154,6 +647,27 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 				defer func() {
 					err = errors.Join(err, bld.Clean())
 				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.DotnetToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
 				nugetModule, err := bld.AddNugetModules("")
 				if err != nil {
 					return
@@ -162,7 +676,7 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 				if err != nil {
 					return
 				}
-				return printBuild(bld, context.String(formatFlag))
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
 			},
 		},
 		{
@@ -180,6 +694,27 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 				defer func() {
 					err = errors.Join(err, bld.Clean())
 				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.DotnetToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
 				dotnetModule, err := bld.AddDotnetModules("")
 				if err != nil {
 					return
@@ -188,13 +723,13 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 				if err != nil {
 					return
 				}
-				return printBuild(bld, context.String(formatFlag))
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
 			},
 		},
 		{
 			Name:            "yarn",
 			Usage:           "Build a Yarn project and generate build-info for it",
-			UsageText:       "bi yarn [yarn command] [command options]",
+			UsageText:       "bi yarn [yarn command] [command options] [--collect-workspaces]",
 			Flags:           flags,
 			SkipFlagParsing: true,
 			Action: func(context *clitool.Context) (err error) {
@@ -207,27 +742,79 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 				defer func() {
 					err = errors.Join(err, bld.Clean())
 				}()
-				yarnModule, err := bld.AddYarnModule("")
+				commandArgs := newToolArgs(context)
+				formatValue, err := commandArgs.stringValue(formatFlag)
+				if err != nil {
+					return
+				}
+				buildProps, err := commandArgs.stringValues(buildPropFlag)
+				if err != nil {
+					return
+				}
+				moduleProps, err := commandArgs.stringValues(modulePropFlag)
+				if err != nil {
+					return
+				}
+				if err = setProperties(bld, buildProps, moduleProps); err != nil {
+					return
+				}
+				excludeDeps, err := commandArgs.stringValues(excludeDepFlag)
+				if err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, excludeDeps); err != nil {
+					return
+				}
+				postProcessScripts, err := commandArgs.stringValues(postProcessFlag)
+				if err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, postProcessScripts); err != nil {
+					return
+				}
+				testReports, err := commandArgs.stringValues(testReportFlag)
+				if err != nil {
+					return
+				}
+				bld.AddTestReports("", testReports...)
+				coverageReports, err := commandArgs.stringValues(coverageReportFlag)
+				if err != nil {
+					return
+				}
+				bld.AddCoverageReports("", commandArgs.boolValue(coverageArtsFlag), coverageReports...)
+				bld.SetResolutionAudit(commandArgs.boolValue(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(commandArgs.boolValue(cacheTimestampsFlag))
+				setStats(bld, commandArgs.boolValue(statsFlag))
+				bld.SetAllowCustomModuleTypes(commandArgs.boolValue(allowCustomTypeFlag))
+				compress := commandArgs.boolValue(compressFlag)
+				outputValue, err := commandArgs.stringValue(outputFlag)
 				if err != nil {
 					return
 				}
-				formatValue, filteredArgs, err := extractStringFlag(context.Args().Slice(), formatFlag)
+				if err = setOutput(bld, outputValue, formatValue, compress); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.NodeToolchain, build.YarnToolchain); err != nil {
+					return
+				}
+				yarnModule, err := bld.AddYarnModule("")
 				if err != nil {
 					return
 				}
-				yarnModule.SetArgs(filteredArgs)
+				yarnModule.SetCollectWorkspaces(commandArgs.boolValue(collectWorkspacesFlag))
+				yarnModule.SetArgs(commandArgs.args)
 				err = yarnModule.Build()
 				if err != nil {
 					return
 				}
-				return printBuild(bld, formatValue)
+				return printBuild(bld, formatValue, outputValue, compress)
 			},
 		},
 		{
 			Name:      "pip",
 			Usage:     "Generate build-info for a pip project",
 			UsageText: "bi pip",
-			Flags:     flags,
+			Flags:     pythonFlags,
 			Action: func(context *clitool.Context) (err error) {
 				service := build.NewBuildInfoService()
 				service.SetLogger(logger)
@@ -238,17 +825,39 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 				defer func() {
 					err = errors.Join(err, bld.Clean())
 				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.PythonToolchain, build.PipToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
 				pythonModule, err := bld.AddPythonModule("", pythonutils.Pip)
 				if err != nil {
 					return
 				}
-				filteredArgs := filterCliFlags(context.Args().Slice(), flags)
+				pythonModule.SetCollectPackages(context.Bool(collectPackagesFlag))
+				filteredArgs := filterCliFlags(context.Args().Slice(), pythonFlags)
 				if filteredArgs[0] == "install" {
 					err = pythonModule.RunInstallAndCollectDependencies(filteredArgs[1:])
 					if err != nil {
 						return
 					}
-					return printBuild(bld, context.String(formatFlag))
+					return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
 				} else {
 					return exec.Command("pip", filteredArgs[1:]...).Run()
 				}
@@ -258,7 +867,7 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 			Name:      "pipenv",
 			Usage:     "Generate build-info for a pipenv project",
 			UsageText: "bi pipenv",
-			Flags:     flags,
+			Flags:     pythonFlags,
 			Action: func(context *clitool.Context) (err error) {
 				service := build.NewBuildInfoService()
 				service.SetLogger(logger)
@@ -269,17 +878,39 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 				defer func() {
 					err = errors.Join(err, bld.Clean())
 				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.PythonToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
 				pythonModule, err := bld.AddPythonModule("", pythonutils.Pipenv)
 				if err != nil {
 					return
 				}
-				filteredArgs := filterCliFlags(context.Args().Slice(), flags)
+				pythonModule.SetCollectPackages(context.Bool(collectPackagesFlag))
+				filteredArgs := filterCliFlags(context.Args().Slice(), pythonFlags)
 				if filteredArgs[0] == "install" {
 					err = pythonModule.RunInstallAndCollectDependencies(filteredArgs[1:])
 					if err != nil {
 						return
 					}
-					return printBuild(bld, context.String(formatFlag))
+					return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
 				} else {
 					return exec.Command("pipenv", filteredArgs[1:]...).Run()
 				}
@@ -300,6 +931,27 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 				defer func() {
 					err = errors.Join(err, bld.Clean())
 				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.PythonToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
 				pythonModule, err := bld.AddPythonModule("", pythonutils.Twine)
 				if err != nil {
 					return
@@ -309,72 +961,1500 @@ func GetCommands(logger utils.Log) []*clitool.Command {
 					if err := pythonModule.TwineUploadAndGenerateBuild(filteredArgs[1:]); err != nil {
 						return err
 					}
-					return printBuild(bld, context.String(formatFlag))
+					return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
 				} else {
 					return exec.Command("twine", filteredArgs[1:]...).Run()
 				}
 			},
 		},
-	}
-}
-
-func printBuild(bld *build.Build, format string) error {
-	buildInfo, err := bld.ToBuildInfo()
-	if err != nil {
-		return err
-	}
-
-	switch format {
-	case cycloneDxXml:
-		cdxBom, err := buildInfo.ToCycloneDxBom()
-		if err != nil {
-			return err
-		}
-		encoder := cdx.NewBOMEncoder(os.Stdout, cdx.BOMFileFormatXML)
-		encoder.SetPretty(true)
-		if err = encoder.Encode(cdxBom); err != nil {
-			return err
-		}
-	case cycloneDxJson:
-		cdxBom, err := buildInfo.ToCycloneDxBom()
-		if err != nil {
-			return err
-		}
-		encoder := cdx.NewBOMEncoder(os.Stdout, cdx.BOMFileFormatJSON)
-		encoder.SetPretty(true)
-		if err = encoder.Encode(cdxBom); err != nil {
-			return err
-		}
-	case "":
-		b, err := json.Marshal(buildInfo)
-		if err != nil {
-			return err
+		{
+			Name:      "bundler",
+			Usage:     "Generate build-info for a Bundler (RubyGems) project",
+			UsageText: "bi bundler [bundle command] [command options]",
+			Flags:     append(slices.Clone(incrementalFlags), integrityFlag),
+			Action: func(context *clitool.Context) (err error) {
+				service := build.NewBuildInfoService()
+				service.SetLogger(logger)
+				bld, err := service.GetOrCreateBuild("bundler-build", "1")
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, bld.Clean())
+				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.RubyToolchain, build.BundlerToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
+				setIncrementalCacheDir(bld, context.Bool(incrementalFlag))
+				checksumCache, err := setChecksumCache(bld, context.Bool(checksumCacheFlag))
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, checksumCache.Save())
+				}()
+				if err = setIntegrityVerification(bld, context.String(verifyIntegrityFlag)); err != nil {
+					return
+				}
+				err = bld.CollectIncrementallyWithInputs("", build.BundlerTechnology, build.IncrementalInputs{Options: context.Args().Slice()}, func(containingBuild *build.Build) error {
+					bundlerModule, err := containingBuild.AddBundlerModule("")
+					if err != nil {
+						return err
+					}
+					bundlerModule.SetBundlerArgs(context.Args().Slice())
+					return bundlerModule.Build()
+				})
+				if err != nil {
+					return
+				}
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
+			},
+		},
+		{
+			Name:      "mix",
+			Usage:     "Generate build-info for an Elixir Mix (Hex) project",
+			UsageText: "bi mix [mix task] [task options]",
+			Flags:     slices.Clone(incrementalFlags),
+			Action: func(context *clitool.Context) (err error) {
+				service := build.NewBuildInfoService()
+				service.SetLogger(logger)
+				bld, err := service.GetOrCreateBuild("mix-build", "1")
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, bld.Clean())
+				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.ElixirToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
+				setIncrementalCacheDir(bld, context.Bool(incrementalFlag))
+				err = bld.CollectIncrementallyWithInputs("", build.MixTechnology, build.IncrementalInputs{Options: context.Args().Slice()}, func(containingBuild *build.Build) error {
+					mixModule, err := containingBuild.AddMixModule("")
+					if err != nil {
+						return err
+					}
+					mixModule.SetMixArgs(context.Args().Slice())
+					return mixModule.Build()
+				})
+				if err != nil {
+					return
+				}
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
+			},
+		},
+		{
+			Name:      "haskell",
+			Usage:     "Generate build-info for a Haskell (Cabal or Stack) project",
+			UsageText: "bi haskell [cabal or stack command] [command options]",
+			Flags:     append(slices.Clone(incrementalFlags), integrityFlag),
+			Action: func(context *clitool.Context) (err error) {
+				service := build.NewBuildInfoService()
+				service.SetLogger(logger)
+				bld, err := service.GetOrCreateBuild("haskell-build", "1")
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, bld.Clean())
+				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.GhcToolchain, build.CabalToolchain, build.StackToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
+				setIncrementalCacheDir(bld, context.Bool(incrementalFlag))
+				checksumCache, err := setChecksumCache(bld, context.Bool(checksumCacheFlag))
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, checksumCache.Save())
+				}()
+				if err = setIntegrityVerification(bld, context.String(verifyIntegrityFlag)); err != nil {
+					return
+				}
+				err = bld.CollectIncrementallyWithInputs("", build.HaskellTechnology, build.IncrementalInputs{Options: context.Args().Slice()}, func(containingBuild *build.Build) error {
+					haskellModule, err := containingBuild.AddHaskellModule("")
+					if err != nil {
+						return err
+					}
+					haskellModule.SetToolArgs(context.Args().Slice())
+					return haskellModule.Build()
+				})
+				if err != nil {
+					return
+				}
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
+			},
+		},
+		{
+			Name:      "zig",
+			Usage:     "Generate build-info for a Zig package",
+			UsageText: "bi zig [zig command] [command options]",
+			Flags:     slices.Clone(incrementalFlags),
+			Action: func(context *clitool.Context) (err error) {
+				service := build.NewBuildInfoService()
+				service.SetLogger(logger)
+				bld, err := service.GetOrCreateBuild("zig-build", "1")
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, bld.Clean())
+				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.ZigToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
+				setIncrementalCacheDir(bld, context.Bool(incrementalFlag))
+				err = bld.CollectIncrementallyWithInputs("", build.ZigTechnology, build.IncrementalInputs{Options: context.Args().Slice()}, func(containingBuild *build.Build) error {
+					zigModule, err := containingBuild.AddZigModule("")
+					if err != nil {
+						return err
+					}
+					zigModule.SetZigArgs(context.Args().Slice())
+					return zigModule.Build()
+				})
+				if err != nil {
+					return
+				}
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
+			},
+		},
+		{
+			Name:      "cmake",
+			Usage:     "Generate build-info for the dependencies a CMake project downloads with FetchContent or ExternalProject",
+			UsageText: "bi cmake [cmake command options]",
+			Flags: append(slices.Clone(incrementalFlags), integrityFlag, &clitool.StringFlag{
+				Name:  buildDirFlag,
+				Value: "build",
+				Usage: "[Default: build] The project's build directory, into which the dependencies are downloaded. A relative path is relative to the working directory.` `",
+			}),
+			Action: func(context *clitool.Context) (err error) {
+				service := build.NewBuildInfoService()
+				service.SetLogger(logger)
+				bld, err := service.GetOrCreateBuild("cmake-build", "1")
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, bld.Clean())
+				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.CMakeToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
+				setIncrementalCacheDir(bld, context.Bool(incrementalFlag))
+				checksumCache, err := setChecksumCache(bld, context.Bool(checksumCacheFlag))
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, checksumCache.Save())
+				}()
+				if err = setIntegrityVerification(bld, context.String(verifyIntegrityFlag)); err != nil {
+					return
+				}
+				inputs := build.IncrementalInputs{Options: append([]string{buildDirFlag + "=" + context.String(buildDirFlag)}, context.Args().Slice()...)}
+				err = bld.CollectIncrementallyWithInputs("", build.CMakeTechnology, inputs, func(containingBuild *build.Build) error {
+					cmakeModule, err := containingBuild.AddCMakeModule("")
+					if err != nil {
+						return err
+					}
+					cmakeModule.SetBuildDir(context.String(buildDirFlag))
+					cmakeModule.SetCMakeArgs(context.Args().Slice())
+					return cmakeModule.Build()
+				})
+				if err != nil {
+					return
+				}
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
+			},
+		},
+		{
+			Name:      "vcpkg",
+			Usage:     "Generate build-info for a vcpkg project in manifest mode",
+			UsageText: "bi vcpkg [vcpkg command] [command options]",
+			Flags: append(slices.Clone(incrementalFlags), &clitool.StringFlag{
+				Name:  installedDirFlag,
+				Usage: "[Optional] The vcpkg installed tree of the project. A relative path is relative to the working directory. If not set, the vcpkg_installed directory of the project, or of its 'build' directory, is used.` `",
+			}),
+			Action: func(context *clitool.Context) (err error) {
+				service := build.NewBuildInfoService()
+				service.SetLogger(logger)
+				bld, err := service.GetOrCreateBuild("vcpkg-build", "1")
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, bld.Clean())
+				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectToolchain("", build.VcpkgToolchain); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
+				setIncrementalCacheDir(bld, context.Bool(incrementalFlag))
+				checksumCache, err := setChecksumCache(bld, context.Bool(checksumCacheFlag))
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, checksumCache.Save())
+				}()
+				installedDir := context.String(installedDirFlag)
+				if installedDir != "" {
+					if installedDir, err = filepath.Abs(installedDir); err != nil {
+						return
+					}
+				}
+				inputs := build.IncrementalInputs{Options: append([]string{installedDirFlag + "=" + installedDir}, context.Args().Slice()...)}
+				err = bld.CollectIncrementallyWithInputs("", build.VcpkgTechnology, inputs, func(containingBuild *build.Build) error {
+					vcpkgModule, err := containingBuild.AddVcpkgModule("")
+					if err != nil {
+						return err
+					}
+					vcpkgModule.SetInstalledDir(installedDir)
+					vcpkgModule.SetVcpkgArgs(context.Args().Slice())
+					return vcpkgModule.Build()
+				})
+				if err != nil {
+					return
+				}
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
+			},
+		},
+		{
+			Name:      "workspace",
+			Usage:     "Discover the projects in a repository and generate one build-info for all of them",
+			UsageText: "bi workspace [workspace path]",
+			Flags: append(slices.Clone(incrementalFlags), integrityFlag, &clitool.IntFlag{
+				Name:  threadsFlag,
+				Value: 3,
+				Usage: "[Default: 3] Number of projects to collect in parallel.` `",
+			}),
+			Action: func(context *clitool.Context) (err error) {
+				service := build.NewBuildInfoService()
+				service.SetLogger(logger)
+				bld, err := service.GetOrCreateBuild("workspace-build", "1")
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, bld.Clean())
+				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
+				setIncrementalCacheDir(bld, context.Bool(incrementalFlag))
+				checksumCache, err := setChecksumCache(bld, context.Bool(checksumCacheFlag))
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, checksumCache.Save())
+				}()
+				if err = setIntegrityVerification(bld, context.String(verifyIntegrityFlag)); err != nil {
+					return
+				}
+				workspacePath := "."
+				if context.Args().Present() {
+					workspacePath = context.Args().First()
+				}
+				if err = bld.CollectWorkspace(workspacePath, context.Int(threadsFlag)); err != nil {
+					return
+				}
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
+			},
+		},
+		{
+			Name:      "import-sbom",
+			Usage:     "Convert a CycloneDX or SPDX SBOM to build-info",
+			UsageText: "bi import-sbom <sbom path>",
+			Flags: append(slices.Clone(flags), &clitool.StringFlag{
+				Name:  sbomTypeFlag,
+				Usage: fmt.Sprintf("[Optional] The format of the SBOM. Supported values are '%s' (JSON or XML) and '%s' (JSON). If not set, the format is detected from the file's content.` `", build.CycloneDxSbom, build.SpdxSbom),
+			}, &clitool.StringFlag{
+				Name:  moduleIdFlag,
+				Usage: "[Optional] The ID of the build-info module. If not set, the ID of the component which the SBOM describes is used.` `",
+			}),
+			Action: func(context *clitool.Context) (err error) {
+				if context.NArg() != 1 {
+					return fmt.Errorf("wrong number of arguments. Usage: %s", context.Command.UsageText)
+				}
+				service := build.NewBuildInfoService()
+				service.SetLogger(logger)
+				bld, err := service.GetOrCreateBuild("import-sbom-build", "1")
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, bld.Clean())
+				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+				bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
+				if err = bld.ImportSbom(context.Args().First(), build.SbomFormat(context.String(sbomTypeFlag)), context.String(moduleIdFlag)); err != nil {
+					return
+				}
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
+			},
+		},
+		{
+			Name:      "rootfs",
+			Usage:     "Generate build-info for the packages installed in a root filesystem, such as an extracted container image",
+			UsageText: "bi rootfs <rootfs path> [--module=<id>]",
+			Flags: append(slices.Clone(flags), &clitool.StringFlag{
+				Name:  moduleIdFlag,
+				Usage: "[Optional] The prefix of the IDs of the build-info modules, which are followed by the modules' types. If not set, the name of the root filesystem's directory is used.` `",
+			}),
+			Action: func(context *clitool.Context) (err error) {
+				if context.NArg() != 1 {
+					return fmt.Errorf("wrong number of arguments. Usage: %s", context.Command.UsageText)
+				}
+				service := build.NewBuildInfoService()
+				service.SetLogger(logger)
+				bld, err := service.GetOrCreateBuild("rootfs-build", "1")
+				if err != nil {
+					return
+				}
+				defer func() {
+					err = errors.Join(err, bld.Clean())
+				}()
+				bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+				bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+				setStats(bld, context.Bool(statsFlag))
+				bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+				if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+					return
+				}
+				if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+					return
+				}
+				if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+					return
+				}
+				if err = setOutput(bld, context.String(outputFlag), context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return
+				}
+				if err = bld.CollectRootfs(context.Args().First(), context.String(moduleIdFlag)); err != nil {
+					return
+				}
+				return printBuild(bld, context.String(formatFlag), context.String(outputFlag), context.Bool(compressFlag))
+			},
+		},
+		{
+			Name:  "artifacts",
+			Usage: "Add artifacts to an in-progress build",
+			Subcommands: []*clitool.Command{
+				{
+					Name:      "add",
+					Usage:     "Add the files matching patterns as artifacts of a generic module, to an in-progress build in the local builds cache",
+					UsageText: "bi artifacts add --pattern=<pattern> --module=<module ID> --build-name=<name> --build-number=<number>",
+					Flags: []clitool.Flag{
+						&clitool.StringSliceFlag{
+							Name:  patternFlag,
+							Usage: "[Mandatory] A pattern of the files to add, in which '*' and '?' match within a single directory and '**' matches any number of directories, for example: 'dist/**/*.zip'. Can be repeated.` `",
+						},
+						&clitool.StringFlag{
+							Name:  moduleIdFlag,
+							Usage: "[Mandatory] The ID of the generic module to which the artifacts are added.` `",
+						},
+						&clitool.StringFlag{
+							Name:  buildNameFlag,
+							Usage: "[Mandatory] The name of the in-progress build.` `",
+						},
+						&clitool.StringFlag{
+							Name:  buildNumberFlag,
+							Usage: "[Mandatory] The number of the in-progress build.` `",
+						},
+						&clitool.StringFlag{
+							Name:  projectFlag,
+							Usage: "[Optional] The key of the project of the in-progress build.` `",
+						},
+						&clitool.BoolFlag{
+							Name:  checksumCacheFlag,
+							Usage: "[Default: false] Set to keep the checksums of the artifacts in a cache in the user's cache directory, so that files which haven't changed since the last run aren't hashed again.` `",
+						},
+						&clitool.StringSliceFlag{
+							Name:  subArtifactsFlag,
+							Usage: "[Optional] A pattern of the entries of archive artifacts, such as WARs and zips, which are recorded as their sub-artifacts, for example: 'WEB-INF/lib/*.jar' or '*.whl'. Can be repeated.` `",
+						},
+					},
+					Action: func(context *clitool.Context) (err error) {
+						for _, flagName := range []string{patternFlag, moduleIdFlag, buildNameFlag, buildNumberFlag} {
+							if !context.IsSet(flagName) {
+								return fmt.Errorf("the '--%s' option is mandatory. Usage: %s", flagName, context.Command.UsageText)
+							}
+						}
+						service := build.NewBuildInfoService()
+						service.SetLogger(logger)
+						bld, err := service.GetOrCreateBuildWithProject(context.String(buildNameFlag), context.String(buildNumberFlag), context.String(projectFlag))
+						if err != nil {
+							return
+						}
+						checksumCache, err := setChecksumCache(bld, context.Bool(checksumCacheFlag))
+						if err != nil {
+							return
+						}
+						defer func() {
+							err = errors.Join(err, checksumCache.Save())
+						}()
+						bld.SetSubArtifactsPatterns(context.StringSlice(subArtifactsFlag)...)
+						artifacts, err := bld.AddGenericArtifacts(context.String(moduleIdFlag), context.StringSlice(patternFlag)...)
+						if err != nil {
+							return
+						}
+						logger.Info("Added", len(artifacts), "artifacts to the", context.String(moduleIdFlag), "module of build", context.String(buildNameFlag)+"/"+context.String(buildNumberFlag)+".")
+						return
+					},
+				},
+			},
+		},
+		{
+			Name:  "dependencies",
+			Usage: "Add dependencies to an in-progress build",
+			Subcommands: []*clitool.Command{
+				{
+					Name:      "add",
+					Usage:     "Verify the checksums of the dependencies declared in a YAML or CSV file, and add them as dependencies of a generic module, to an in-progress build in the local builds cache",
+					UsageText: "bi dependencies add --file=<dependencies file path> --module=<module ID> --build-name=<name> --build-number=<number>",
+					Flags: []clitool.Flag{
+						&clitool.StringFlag{
+							Name:  dependenciesFileFlag,
+							Usage: "[Mandatory] The path of a YAML (.yaml or .yml) or CSV (.csv) file declaring the dependencies by URL or path, with their expected checksums.` `",
+						},
+						&clitool.StringFlag{
+							Name:  moduleIdFlag,
+							Usage: "[Mandatory] The ID of the generic module to which the dependencies are added.` `",
+						},
+						&clitool.StringFlag{
+							Name:  buildNameFlag,
+							Usage: "[Mandatory] The name of the in-progress build.` `",
+						},
+						&clitool.StringFlag{
+							Name:  buildNumberFlag,
+							Usage: "[Mandatory] The number of the in-progress build.` `",
+						},
+						&clitool.StringFlag{
+							Name:  projectFlag,
+							Usage: "[Optional] The key of the project of the in-progress build.` `",
+						},
+						&clitool.BoolFlag{
+							Name:  checksumCacheFlag,
+							Usage: "[Default: false] Set to keep the checksums of the dependencies declared by path in a cache in the user's cache directory, so that files which haven't changed since the last run aren't hashed again.` `",
+						},
+					},
+					Action: func(context *clitool.Context) (err error) {
+						for _, flagName := range []string{dependenciesFileFlag, moduleIdFlag, buildNameFlag, buildNumberFlag} {
+							if !context.IsSet(flagName) {
+								return fmt.Errorf("the '--%s' option is mandatory. Usage: %s", flagName, context.Command.UsageText)
+							}
+						}
+						service := build.NewBuildInfoService()
+						service.SetLogger(logger)
+						bld, err := service.GetOrCreateBuildWithProject(context.String(buildNameFlag), context.String(buildNumberFlag), context.String(projectFlag))
+						if err != nil {
+							return
+						}
+						checksumCache, err := setChecksumCache(bld, context.Bool(checksumCacheFlag))
+						if err != nil {
+							return
+						}
+						defer func() {
+							err = errors.Join(err, checksumCache.Save())
+						}()
+						dependencies, err := bld.AddGenericDependencies(context.String(moduleIdFlag), context.String(dependenciesFileFlag))
+						if err != nil {
+							return
+						}
+						logger.Info("Added", len(dependencies), "dependencies to the", context.String(moduleIdFlag), "module of build", context.String(buildNameFlag)+"/"+context.String(buildNumberFlag)+".")
+						return
+					},
+				},
+			},
+		},
+		{
+			Name:      "outdated",
+			Usage:     "Report the dependencies of a build-info which have newer versions in their registries",
+			UsageText: "bi outdated <build-info path>",
+			Flags:     getRegistryFlags("the latest versions"),
+			Action: func(context *clitool.Context) (err error) {
+				if context.NArg() != 1 {
+					return fmt.Errorf("wrong number of arguments. Usage: %s", context.Command.UsageText)
+				}
+				buildInfo, err := build.ReadBuildInfo(context.Args().First())
+				if err != nil {
+					return
+				}
+				outdated, err := utils.FindOutdatedDependencies(context.Context, buildInfo, getRegistryEndpoints(context), context.Int(threadsFlag), logger)
+				if err != nil {
+					return
+				}
+				if outdated == nil {
+					outdated = []utils.OutdatedDependency{}
+				}
+				content, err := json.MarshalIndent(outdated, "", "  ")
+				if err != nil {
+					return
+				}
+				_, err = fmt.Fprintln(os.Stdout, string(content))
+				return
+			},
+		},
+		{
+			Name:      "scan",
+			Usage:     "Evaluate the rules of a policy file against the dependencies of a build-info, and fail if any of them are violated",
+			UsageText: "bi scan <build-info path> --policy=<policy file>",
+			Flags: append(getRegistryFlags("the publish times and licenses"), &clitool.StringFlag{
+				Name:     policyFlag,
+				Usage:    "[Mandatory] The path of a YAML policy file.` `",
+				Required: true,
+			}),
+			Action: func(context *clitool.Context) (err error) {
+				if context.NArg() != 1 {
+					return fmt.Errorf("wrong number of arguments. Usage: %s", context.Command.UsageText)
+				}
+				buildInfo, err := build.ReadBuildInfo(context.Args().First())
+				if err != nil {
+					return
+				}
+				policy, err := build.ReadPolicy(context.String(policyFlag))
+				if err != nil {
+					return
+				}
+				violations, err := policy.Evaluate(context.Context, buildInfo, getRegistryEndpoints(context), context.Int(threadsFlag), logger)
+				if err != nil {
+					return
+				}
+				if violations == nil {
+					violations = []build.PolicyViolationDetails{}
+				}
+				content, err := json.MarshalIndent(violations, "", "  ")
+				if err != nil {
+					return
+				}
+				if _, err = fmt.Fprintln(os.Stdout, string(content)); err != nil || len(violations) == 0 {
+					return
+				}
+				return utils.NewCategorizedError(utils.PolicyViolation, fmt.Errorf("the build-info violates the policy in %d places", len(violations)))
+			},
+		},
+		{
+			Name:      "convert",
+			Usage:     "Convert a build-info to a different version of the build-info schema, so it can be published to Artifactory instances which don't accept newer versions",
+			UsageText: "bi convert <build-info path> --schema=<version> [--output=<path>]",
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{
+					Name:     schemaFlag,
+					Usage:    fmt.Sprintf("[Mandatory] The version of the build-info schema to convert to. Supported values are: %s.` `", strings.Join(entities.SchemaVersions, ", ")),
+					Required: true,
+				},
+				&clitool.StringFlag{
+					Name:  outputFlag,
+					Usage: "[Optional] Path to a file to which the converted build-info is written. If not set, the build-info is printed to the standard output.` `",
+				},
+			},
+			Action: func(context *clitool.Context) (err error) {
+				if context.NArg() != 1 {
+					return fmt.Errorf("wrong number of arguments. Usage: %s", context.Command.UsageText)
+				}
+				buildInfo, err := build.ReadBuildInfo(context.Args().First())
+				if err != nil {
+					return
+				}
+				if err = buildInfo.ConvertSchema(context.String(schemaFlag)); err != nil {
+					return
+				}
+				var content bytes.Buffer
+				if _, err = buildInfo.WriteTo(&content); err != nil {
+					return
+				}
+				outputPath := context.String(outputFlag)
+				if outputPath == "" {
+					_, err = os.Stdout.Write(content.Bytes())
+					return
+				}
+				if err = utils.WriteFileAtomically(outputPath, content.Bytes(), 0644); err != nil {
+					return
+				}
+				logger.Info("The converted build-info was written to", outputPath)
+				return
+			},
+		},
+		{
+			Name:      "decrypt-properties",
+			Usage:     fmt.Sprintf("Decrypt the encrypted properties of a build-info, with the key in the %s environment variable", encryptionKeyEnv),
+			UsageText: "bi decrypt-properties <build-info path> [--output=<path>]",
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{
+					Name:  outputFlag,
+					Usage: "[Optional] Path to a file to which the decrypted build-info is written. If not set, the build-info is printed to the standard output.` `",
+				},
+			},
+			Action: func(context *clitool.Context) (err error) {
+				if context.NArg() != 1 {
+					return fmt.Errorf("wrong number of arguments. Usage: %s", context.Command.UsageText)
+				}
+				buildInfo, err := build.ReadBuildInfo(context.Args().First())
+				if err != nil {
+					return
+				}
+				propertyEncrypter, err := newPropertyEncrypter()
+				if err != nil {
+					return
+				}
+				decrypted, err := propertyEncrypter.DecryptProperties(buildInfo)
+				if err != nil {
+					return
+				}
+				logger.Info("Decrypted", decrypted, "properties.")
+				var content bytes.Buffer
+				if _, err = buildInfo.WriteTo(&content); err != nil {
+					return
+				}
+				outputPath := context.String(outputFlag)
+				if outputPath == "" {
+					_, err = os.Stdout.Write(content.Bytes())
+					return
+				}
+				// The decrypted build-info may contain restricted values, so only its owner can read it.
+				if err = utils.WriteFileAtomically(outputPath, content.Bytes(), 0600); err != nil {
+					return
+				}
+				logger.Info("The decrypted build-info was written to", outputPath)
+				return
+			},
+		},
+		{
+			Name:      "backfill-checksums",
+			Usage:     "Fill in the missing checksums of the dependencies of a build-info, from their files in the local caches of Maven, Gradle, npm, pip, Conan and Helm",
+			UsageText: "bi backfill-checksums <build-info path> [--output=<path>]",
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{
+					Name:  outputFlag,
+					Usage: "[Optional] Path to a file to which the build-info is written. If not set, the build-info is printed to the standard output.` `",
+				},
+				&clitool.BoolFlag{
+					Name:  checksumCacheFlag,
+					Usage: "[Default: false] Read the checksums of the cached files which didn't change since their checksums were calculated from the checksum cache in the user's cache directory.` `",
+				},
+			},
+			Action: func(context *clitool.Context) (err error) {
+				if context.NArg() != 1 {
+					return fmt.Errorf("wrong number of arguments. Usage: %s", context.Command.UsageText)
+				}
+				buildInfo, err := build.ReadBuildInfo(context.Args().First())
+				if err != nil {
+					return
+				}
+				localCaches, err := buildutils.GetDefaultLocalCaches()
+				if err != nil {
+					return
+				}
+				var checksumCache *utils.ChecksumCache
+				if context.Bool(checksumCacheFlag) {
+					cacheDir, err := utils.GetDefaultChecksumCacheDir()
+					if err != nil {
+						return err
+					}
+					if checksumCache, err = utils.NewChecksumCache(cacheDir, 0); err != nil {
+						return err
+					}
+					defer func() {
+						err = errors.Join(err, checksumCache.Save())
+					}()
+				}
+				filled, err := build.BackfillChecksums(buildInfo, localCaches, checksumCache, logger)
+				if err != nil {
+					return
+				}
+				logger.Info("Filled in the checksums of", filled, "dependencies.")
+				var content bytes.Buffer
+				if _, err = buildInfo.WriteTo(&content); err != nil {
+					return
+				}
+				outputPath := context.String(outputFlag)
+				if outputPath == "" {
+					_, err = os.Stdout.Write(content.Bytes())
+					return
+				}
+				if err = utils.WriteFileAtomically(outputPath, content.Bytes(), 0644); err != nil {
+					return
+				}
+				logger.Info("The build-info was written to", outputPath)
+				return
+			},
+		},
+		{
+			Name:  "release-bundle",
+			Usage: "Create Release Bundle v2 specifications from build-info",
+			Subcommands: []*clitool.Command{
+				{
+					Name:      "create",
+					Usage:     "Convert the artifacts of a build-info to the specification of a Release Bundle v2, which can be sent to the JFrog Platform to create the release bundle",
+					UsageText: "bi release-bundle create <build-info path> [--name=<name>] [--version=<version>] [--repo=<repository>] [--output=<path>]",
+					Flags: []clitool.Flag{
+						&clitool.StringFlag{
+							Name:  bundleNameFlag,
+							Usage: "[Optional] The name of the release bundle. If not set, the name of the build is used.` `",
+						},
+						&clitool.StringFlag{
+							Name:  bundleVersionFlag,
+							Usage: "[Optional] The version of the release bundle. If not set, the number of the build is used.` `",
+						},
+						&clitool.StringFlag{
+							Name:  repoFlag,
+							Usage: "[Optional] The repository of the artifacts whose original deployment repository isn't recorded in the build-info, such as generic artifacts.` `",
+						},
+						&clitool.StringFlag{
+							Name:  outputFlag,
+							Usage: "[Optional] Path to a file to which the specification is written. If not set, the specification is printed to the standard output.` `",
+						},
+					},
+					Action: func(context *clitool.Context) (err error) {
+						if context.NArg() != 1 {
+							return fmt.Errorf("wrong number of arguments. Usage: %s", context.Command.UsageText)
+						}
+						buildInfo, err := build.ReadBuildInfo(context.Args().First())
+						if err != nil {
+							return
+						}
+						spec, err := build.NewReleaseBundleSpec(buildInfo, context.String(bundleNameFlag), context.String(bundleVersionFlag), context.String(repoFlag))
+						if err != nil {
+							return
+						}
+						content, err := json.MarshalIndent(spec, "", "  ")
+						if err != nil {
+							return
+						}
+						outputPath := context.String(outputFlag)
+						if outputPath == "" {
+							_, err = fmt.Fprintln(os.Stdout, string(content))
+							return
+						}
+						if err = utils.WriteFileAtomically(outputPath, content, 0644); err != nil {
+							return
+						}
+						logger.Info("The release bundle specification was written to", outputPath)
+						return
+					},
+				},
+			},
+		},
+		{
+			Name:      "init",
+			Usage:     "Detect the projects in the working directory, and create a starter " + build.ConfigFileName + " configuration file",
+			UsageText: "bi init",
+			Flags: []clitool.Flag{
+				&clitool.BoolFlag{
+					Name:  forceFlag,
+					Usage: "[Default: false] Set to overwrite an existing configuration file.` `",
+				},
+			},
+			Action: func(context *clitool.Context) error {
+				return runInitWizard(".", context.Bool(forceFlag), os.Stdin, os.Stdout)
+			},
+		},
+		{
+			Name:  "cache",
+			Usage: "Manage the checksum cache in the user's cache directory",
+			Subcommands: []*clitool.Command{
+				{
+					Name:      "prune",
+					Usage:     "Remove the cached checksums of files which were deleted or modified, and of the least recently used files beyond the maximal number of entries",
+					UsageText: "bi cache prune",
+					Flags: []clitool.Flag{
+						&clitool.IntFlag{
+							Name:  maxEntriesFlag,
+							Value: utils.DefaultChecksumCacheMaxEntries,
+							Usage: fmt.Sprintf("[Default: %d] The maximal number of files whose checksums are kept in the cache.` `", utils.DefaultChecksumCacheMaxEntries),
+						},
+					},
+					Action: func(context *clitool.Context) error {
+						cacheDir, err := utils.GetDefaultChecksumCacheDir()
+						if err != nil {
+							return err
+						}
+						checksumCache, err := utils.NewChecksumCache(cacheDir, context.Int(maxEntriesFlag))
+						if err != nil {
+							return err
+						}
+						removed := checksumCache.Prune(context.Int(maxEntriesFlag))
+						if err = checksumCache.Save(); err != nil {
+							return err
+						}
+						logger.Info("Removed", removed, "entries from the checksum cache.", checksumCache.Len(), "entries are left.")
+						return nil
+					},
+				},
+			},
+		},
+		{
+			Name:      "clean",
+			Usage:     "Remove the temporary files left by bi commands which didn't clean up after themselves, for example because they were killed",
+			UsageText: "bi clean --temp [--older-than=<duration>]",
+			Flags: []clitool.Flag{
+				&clitool.BoolFlag{
+					Name:  tempFlag,
+					Usage: "[Default: false] Set to remove the build directories and the temporary directories in the temp directory, which is set by the " + utils.TempDirEnv + " environment variable, or is the system's temp directory.` `",
+				},
+				&clitool.DurationFlag{
+					Name:  olderThanFlag,
+					Value: time.Hour,
+					Usage: "[Default: 1h] Remove only the files which weren't modified for this long, so that the files of the bi commands which are still running are kept.` `",
+				},
+			},
+			Action: func(context *clitool.Context) error {
+				if !context.Bool(tempFlag) {
+					return fmt.Errorf("nothing to clean. Usage: %s", context.Command.UsageText)
+				}
+				service := build.NewBuildInfoService()
+				service.SetLogger(logger)
+				removed, err := service.CleanTempFiles(context.Duration(olderThanFlag))
+				for _, removedPath := range removed {
+					logger.Debug("Removed", removedPath)
+				}
+				logger.Info("Removed", len(removed), "temporary directories.")
+				return err
+			},
+		},
+		{
+			Name:      "completion",
+			Usage:     "Print the shell completion script of the CLI",
+			UsageText: "bi completion bash|zsh|fish|powershell\n\n   For example, to enable the completion in bash: source <(bi completion bash)",
+			Action: func(context *clitool.Context) error {
+				if context.NArg() != 1 {
+					return fmt.Errorf("wrong number of arguments. Usage: %s", context.Command.UsageText)
+				}
+				return writeCompletionScript(context.App, context.Args().First(), os.Stdout)
+			},
+		},
+		{
+			Name:      "watch",
+			Usage:     "Watch the manifests and lockfiles of the projects in a repository, and generate their build-info whenever they change",
+			UsageText: "bi watch [workspace path]",
+			// The shared output flag is replaced by the path of the written build-info.
+			Flags: append(slices.DeleteFunc(slices.Clone(incrementalFlags), isOutputFlag), integrityFlag,
+				&clitool.IntFlag{
+					Name:  threadsFlag,
+					Value: 3,
+					Usage: "[Default: 3] Number of projects to collect in parallel.` `",
+				},
+				&clitool.StringFlag{
+					Name:  outputFlag,
+					Usage: "[Optional] Path to a file to which the build-info is written. If not set, the build-info is printed to the standard output.` `",
+				},
+				&clitool.DurationFlag{
+					Name:  debounceFlag,
+					Value: 2 * time.Second,
+					Usage: "[Default: 2s] How long the manifests and lockfiles must stay unchanged before the build-info is generated again.` `",
+				},
+			),
+			Action: func(context *clitool.Context) error {
+				if err := validateFormat(context.String(formatFlag), context.Bool(compressFlag)); err != nil {
+					return err
+				}
+				workspacePath := "."
+				if context.Args().Present() {
+					workspacePath = context.Args().First()
+				}
+				watcher := build.NewProjectWatcher(workspacePath, logger)
+				watcher.SetDebounce(context.Duration(debounceFlag))
+				watchContext, stop := signal.NotifyContext(context.Context, os.Interrupt)
+				defer stop()
+				return watcher.Watch(watchContext, func() (err error) {
+					service := build.NewBuildInfoService()
+					service.SetLogger(logger)
+					bld, err := service.GetOrCreateBuild("watch-build", "1")
+					if err != nil {
+						return
+					}
+					defer func() {
+						err = errors.Join(err, bld.Clean())
+					}()
+					bld.SetResolutionAudit(context.Bool(resolutionAuditFlag))
+					bld.SetRecordCacheTimestamps(context.Bool(cacheTimestampsFlag))
+					setStats(bld, context.Bool(statsFlag))
+					bld.SetAllowCustomModuleTypes(context.Bool(allowCustomTypeFlag))
+					if err = setProperties(bld, context.StringSlice(buildPropFlag), context.StringSlice(modulePropFlag)); err != nil {
+						return
+					}
+					if err = setDependencyExclusions(bld, context.StringSlice(excludeDepFlag)); err != nil {
+						return
+					}
+					if err = setPostProcessors(bld, context.StringSlice(postProcessFlag)); err != nil {
+						return
+					}
+					bld.AddTestReports("", context.StringSlice(testReportFlag)...)
+					bld.AddCoverageReports("", context.Bool(coverageArtsFlag), context.StringSlice(coverageReportFlag)...)
+					setIncrementalCacheDir(bld, context.Bool(incrementalFlag))
+					checksumCache, err := setChecksumCache(bld, context.Bool(checksumCacheFlag))
+					if err != nil {
+						return
+					}
+					defer func() {
+						err = errors.Join(err, checksumCache.Save())
+					}()
+					if err = setIntegrityVerification(bld, context.String(verifyIntegrityFlag)); err != nil {
+						return
+					}
+					if err = bld.CollectWorkspace(workspacePath, context.Int(threadsFlag)); err != nil {
+						return
+					}
+					outputPath := context.String(outputFlag)
+					if outputPath == "" {
+						return printBuild(bld, context.String(formatFlag), "", context.Bool(compressFlag))
+					}
+					var content bytes.Buffer
+					if err = writeBuild(bld, context.String(formatFlag), context.Bool(compressFlag), &content); err != nil {
+						return
+					}
+					if err = utils.WriteFileAtomically(outputPath, content.Bytes(), 0644); err != nil {
+						return
+					}
+					logger.Info("The build-info was written to", outputPath)
+					return
+				})
+			},
+		},
+	}
+}
+
+// Loads the checksum cache from the user's cache directory and sets it in the build, if enabled.
+// The returned cache should be saved after the collection. It's nil if the checksum cache isn't enabled.
+func setChecksumCache(bld *build.Build, enabled bool) (*utils.ChecksumCache, error) {
+	if !enabled {
+		return nil, nil
+	}
+	cacheDir, err := utils.GetDefaultChecksumCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	checksumCache, err := utils.NewChecksumCache(cacheDir, 0)
+	if err != nil {
+		return nil, err
+	}
+	bld.SetChecksumCache(checksumCache)
+	return checksumCache, nil
+}
+
+func setIncrementalCacheDir(bld *build.Build, incremental bool) {
+	if incremental {
+		bld.SetIncrementalCacheDir(filepath.Join(utils.GetTempDirBase(), build.IncrementalCachePath))
+	}
+}
+
+// Parses the '--build-prop' and '--module-prop' values, in the key=value format, and sets them in the build.
+func setProperties(bld *build.Build, buildProps, moduleProps []string) error {
+	buildProperties, err := parseProperties(buildProps, buildPropFlag)
+	if err != nil {
+		return err
+	}
+	moduleProperties, err := parseProperties(moduleProps, modulePropFlag)
+	if err != nil {
+		return err
+	}
+	bld.SetBuildProperties(buildProperties)
+	bld.SetModuleProperties(moduleProperties)
+	return nil
+}
+
+func parseProperties(values []string, flagName string) (map[string]string, error) {
+	properties := make(map[string]string)
+	for _, value := range values {
+		key, propertyValue, found := strings.Cut(value, "=")
+		if !found || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("'%s' is not a valid value for '%s'. The expected format is key=value", value, flagName)
+		}
+		properties[strings.TrimSpace(key)] = propertyValue
+	}
+	return properties, nil
+}
+
+// Parses the '--exclude-dep' values and adds them to the build's dependency exclusion rules.
+// A value is either a wildcard pattern of the dependencies' IDs, or a comma-separated list of key=value rule fields.
+// Returns the Maven configuration of the configuration file in the working directory, overridden by the options of the mvn command.
+func getMavenConfig(context *clitool.Context) (mavenConfig build.MavenConfig, err error) {
+	config, err := build.ReadConfig(".")
+	if err != nil {
+		return
+	}
+	mavenConfig = config.Maven
+	if pomFile := context.String(pomFileFlag); pomFile != "" {
+		mavenConfig.PomFile = pomFile
+	}
+	if profiles := context.String(profilesFlag); profiles != "" {
+		mavenConfig.Profiles = strings.Split(profiles, ",")
+	}
+	properties, err := parseProperties(context.StringSlice(mavenPropFlag), mavenPropFlag)
+	if err != nil || len(properties) == 0 {
+		return
+	}
+	if mavenConfig.Properties == nil {
+		mavenConfig.Properties = map[string]string{}
+	}
+	maps.Copy(mavenConfig.Properties, properties)
+	return
+}
+
+// Returns the options and the files which determine the dependencies collected by 'bi mvn', in addition to the project's POMs,
+// so that the incremental cache isn't used if any of them changed.
+func getMavenIncrementalInputs(context *clitool.Context, mavenConfig build.MavenConfig) (inputs build.IncrementalInputs, err error) {
+	configContent, err := json.Marshal(mavenConfig)
+	if err != nil {
+		return
+	}
+	settingsPath, globalSettingsPath := context.String(settingsFlag), context.String(globalSettingsFlag)
+	inputs.Options = []string{
+		"config=" + string(configContent),
+		settingsFlag + "=" + settingsPath,
+		globalSettingsFlag + "=" + globalSettingsPath,
+		buildPluginsFlag + "=" + strconv.FormatBool(context.Bool(buildPluginsFlag)),
+		useWrapperFlag + "=" + strconv.FormatBool(context.Bool(useWrapperFlag)),
+		treeParallelismFlag + "=" + strconv.Itoa(context.Int(treeParallelismFlag)),
+		treeThreadsFlag + "=" + context.String(treeThreadsFlag),
+	}
+	if settingsPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return inputs, err
+		}
+		settingsPath = filepath.Join(home, ".m2", "settings.xml")
+	}
+	// The files of the .mvn directory aren't found by the project's fingerprint, which skips hidden directories.
+	inputs.Files = []string{settingsPath, filepath.Join(".mvn", "maven.config"), filepath.Join(".mvn", "extensions.xml"), filepath.Join(".mvn", "wrapper", "maven-wrapper.properties")}
+	if globalSettingsPath != "" {
+		inputs.Files = append(inputs.Files, globalSettingsPath)
+	}
+	// The alternate POM may be outside the project's directory, or have a name other than pom.xml.
+	if pomPath := mavenConfig.PomFile; pomPath != "" {
+		if isDir, err := utils.IsDirExists(pomPath, true); err != nil {
+			return inputs, err
+		} else if isDir {
+			pomPath = filepath.Join(pomPath, "pom.xml")
+		}
+		inputs.Files = append(inputs.Files, pomPath)
+	}
+	return
+}
+
+// Returns the options and the files which determine the dependencies collected by 'bi gradle', in addition to the project's build scripts,
+// so that the incremental cache isn't used if any of them changed.
+func getGradleIncrementalInputs(context *clitool.Context, gradleConfig build.GradleConfig) (inputs build.IncrementalInputs, err error) {
+	configContent, err := json.Marshal(gradleConfig)
+	if err != nil {
+		return
+	}
+	inputs.Options = []string{
+		"config=" + string(configContent),
+		buildPluginsFlag + "=" + strconv.FormatBool(context.Bool(buildPluginsFlag)),
+		useWrapperFlag + "=" + strconv.FormatBool(context.Bool(useWrapperFlag)),
+		verifyWrapperFlag + "=" + strconv.FormatBool(context.Bool(verifyWrapperFlag)),
+	}
+	inputs.Files = []string{filepath.Join("gradle", "wrapper", "gradle-wrapper.properties")}
+	return
+}
+
+// Adds an npm module whose manifests are read from the payload in the provided path, or from the standard input if the path is '-'.
+// Since no npm command runs, the module's dependencies are collected when it's built.
+func addNpmModuleFromManifests(bld *build.Build, manifestPath string) (npmModule *build.NpmModule, err error) {
+	manifestReader := os.Stdin
+	if manifestPath != "-" {
+		if manifestReader, err = os.Open(manifestPath); err != nil {
+			return
+		}
+		defer func() {
+			err = errors.Join(err, manifestReader.Close())
+		}()
+	}
+	if npmModule, err = bld.AddNpmModuleFromManifests(manifestReader); err != nil {
+		return
+	}
+	npmModule.SetCollectBuildInfo(true)
+	return
+}
+
+// Approximates the build-info of a Maven build from its log, which is read from the standard input if the path is '-'.
+func calcMavenDependenciesFromLog(bld *build.Build, logPath string) (err error) {
+	mavenModule, err := bld.AddMavenModule("")
+	if err != nil {
+		return
+	}
+	if logPath == "-" {
+		return mavenModule.CalcDependenciesFromLog(os.Stdin)
+	}
+	logFile, err := os.Open(logPath)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, logFile.Close())
+	}()
+	return mavenModule.CalcDependenciesFromLog(logFile)
+}
+
+func setDependencyExclusions(bld *build.Build, values []string) error {
+	for _, value := range values {
+		exclusion, err := parseDependencyExclusion(value)
+		if err != nil {
+			return err
 		}
-		var content bytes.Buffer
-		err = json.Indent(&content, b, "", "  ")
+		bld.AddDependencyExclusions(exclusion)
+	}
+	return nil
+}
+
+func setPostProcessors(bld *build.Build, scriptPaths []string) error {
+	for _, scriptPath := range scriptPaths {
+		postProcessor, err := build.NewScriptPostProcessor(scriptPath)
 		if err != nil {
 			return err
 		}
-		fmt.Println(content.String())
+		bld.AddPostProcessors(postProcessor)
+	}
+	return nil
+}
+
+// Sets the build to print its CollectionStats to the standard error, once the build-info is created.
+func setStats(bld *build.Build, printStats bool) {
+	if !printStats {
+		return
+	}
+	bld.AddPostProcessors(func(*entities.BuildInfo) error {
+		_, err := fmt.Fprintln(os.Stderr, "Collection stats:", bld.CollectionStats())
+		return err
+	})
+}
+
+// Sets the build to stream its collection events to the standard output as JSON lines, if the output is jsonl.
+// The build-info is then sent as the last event, so it can't be converted to a different format or compressed.
+func setOutput(bld *build.Build, output, format string, compress bool) error {
+	if err := validateFormat(format, compress); err != nil {
+		return err
+	}
+	switch output {
+	case "":
+		return nil
+	case outputJsonl:
+		if format != "" || compress {
+			return fmt.Errorf("the '%s' and '%s' options can't be used with '--%s=%s'", formatFlag, compressFlag, outputFlag, outputJsonl)
+		}
+		bld.SetCollectionListener(build.NewJsonLinesListener(os.Stdout))
+		// The output of the build tools would corrupt the JSON Lines.
+		bld.SetCommandOutput(os.Stderr)
+		return nil
 	default:
-		return fmt.Errorf("'%s' is not a valid value for '%s'", format, formatFlag)
+		return fmt.Errorf("'%s' is not a valid value for '%s'. The supported value is '%s'", output, outputFlag, outputJsonl)
+	}
+}
+
+func isOutputFlag(flag clitool.Flag) bool {
+	return slices.Contains(flag.Names(), outputFlag)
+}
+
+func parseDependencyExclusion(value string) (exclusion build.DependencyExclusion, err error) {
+	if !strings.Contains(value, "=") {
+		exclusion.Id = value
+	} else {
+		for _, field := range strings.Split(value, ",") {
+			key, fieldValue, _ := strings.Cut(field, "=")
+			switch strings.TrimSpace(key) {
+			case "id":
+				exclusion.Id = fieldValue
+			case "group":
+				exclusion.Group = fieldValue
+			case "scope":
+				exclusion.Scope = fieldValue
+			case "regex":
+				exclusion.Regex = fieldValue == "true"
+			default:
+				return exclusion, fmt.Errorf("'%s' is not a valid value for '%s'. The supported rule fields are id, group, scope and regex", value, excludeDepFlag)
+			}
+		}
+	}
+	if err = exclusion.Validate(); err != nil {
+		return exclusion, fmt.Errorf("'%s' is not a valid value for '%s': %w", value, excludeDepFlag, err)
+	}
+	return
+}
+
+func setIntegrityVerification(bld *build.Build, value string) error {
+	integrityVerification, err := utils.ParseIntegrityVerificationMode(value)
+	if err != nil {
+		return err
+	}
+	bld.SetIntegrityVerification(integrityVerification)
+	return nil
+}
+
+// Returns the flags of the registries queried for the dependencies' details, and of the number of requests to send in parallel.
+// queriedFor - The details queried from the registries, for example: the latest versions
+func getRegistryFlags(queriedFor string) []clitool.Flag {
+	defaultEndpoints := utils.DefaultRegistryEndpoints()
+	return []clitool.Flag{
+		&clitool.StringFlag{
+			Name:  npmRegistryFlag,
+			Value: defaultEndpoints.Npm,
+			Usage: fmt.Sprintf("[Default: %s] The npm registry queried for %s of the npm dependencies.` `", defaultEndpoints.Npm, queriedFor),
+		},
+		&clitool.StringFlag{
+			Name:  mavenRepoFlag,
+			Value: defaultEndpoints.Maven,
+			Usage: fmt.Sprintf("[Default: %s] The Maven repository queried for %s of the Maven and Gradle dependencies.` `", defaultEndpoints.Maven, queriedFor),
+		},
+		&clitool.StringFlag{
+			Name:  pypiIndexFlag,
+			Value: defaultEndpoints.Pypi,
+			Usage: fmt.Sprintf("[Default: %s] The JSON API of the PyPI index queried for %s of the Python dependencies.` `", defaultEndpoints.Pypi, queriedFor),
+		},
+		&clitool.IntFlag{
+			Name:  threadsFlag,
+			Value: defaultRegistryThreads,
+			Usage: fmt.Sprintf("[Default: %d] Number of registry requests to send in parallel.` `", defaultRegistryThreads),
+		},
+	}
+}
+
+func getRegistryEndpoints(context *clitool.Context) utils.RegistryEndpoints {
+	return utils.RegistryEndpoints{Npm: context.String(npmRegistryFlag), Maven: context.String(mavenRepoFlag), Pypi: context.String(pypiIndexFlag)}
+}
+
+// printBuild prints the build-info to the standard output, unless the output is jsonl,
+// in which case the build-info is printed as the last event by the collection listener set by setOutput.
+func printBuild(bld *build.Build, format, output string, compress bool) error {
+	if output == outputJsonl {
+		_, err := createBuildInfo(bld)
+		return err
 	}
+	return writeBuild(bld, format, compress, os.Stdout)
+}
 
+// Returns an error if the build-info can't be written in the format, so that the command fails before the collection runs.
+// The table format is meant to be read in the terminal, so it can't be compressed.
+func validateFormat(format string, compress bool) error {
+	if _, err := api.NewFormatter(format); err != nil {
+		return fmt.Errorf("'%s' is not a valid value for '%s'", format, formatFlag)
+	}
+	if compress && format == api.TableFormat {
+		return fmt.Errorf("the '%s' option can't be used with '--%s=%s'", compressFlag, formatFlag, api.TableFormat)
+	}
 	return nil
 }
 
+// writeBuild writes the build-info to the writer, converted to the provided format, and compressed with gzip if requested.
+func writeBuild(bld *build.Build, format string, compress bool, writer io.Writer) error {
+	if err := validateFormat(format, compress); err != nil {
+		return err
+	}
+	formatter, err := api.NewFormatter(format)
+	if err != nil {
+		return err
+	}
+	if compress {
+		formatter = api.NewGzipFormatter(formatter)
+	}
+	buildInfo, err := createBuildInfo(bld)
+	if err != nil {
+		return err
+	}
+	return api.NewWriterPublisher(writer, formatter).Publish(context.Background(), buildInfo)
+}
+
+// Creates the build-info, with the configuration of the working directory.
+func createBuildInfo(bld *build.Build) (*entities.BuildInfo, error) {
+	config, err := build.ReadConfig(".")
+	if err != nil {
+		return nil, err
+	}
+	bld.SetDeployPaths(config.DeployPaths)
+	bld.SetModuleIdTemplates(config.ModuleIds)
+	bld.SetShareDependencies(config.ShareDependencies)
+	bld.AddDependencyExclusions(config.ExcludeDependencies...)
+	bld.SetRequestedByLimits(config.RequestedBy)
+	if config.CollectRunnerLimits {
+		if err = bld.CollectRunnerLimits(); err != nil {
+			return nil, err
+		}
+	}
+	if len(config.EncryptProperties) > 0 {
+		propertyEncrypter, err := newPropertyEncrypter(config.EncryptProperties...)
+		if err != nil {
+			return nil, err
+		}
+		bld.SetPropertyEncrypter(propertyEncrypter)
+	}
+	return bld.ToBuildInfo()
+}
+
+// Creates a property encrypter with the key in the BUILD_INFO_ENCRYPTION_KEY environment variable.
+func newPropertyEncrypter(patterns ...string) (*build.PropertyEncrypter, error) {
+	encodedKey := os.Getenv(encryptionKeyEnv)
+	if encodedKey == "" {
+		return nil, fmt.Errorf("the %s environment variable must be set to a base64 encoded 32 bytes key, to encrypt or decrypt the build-info's properties", encryptionKeyEnv)
+	}
+	key, err := build.ParsePropertyEncryptionKey(encodedKey)
+	if err != nil {
+		return nil, err
+	}
+	return build.NewPropertyEncrypter(key, patterns...)
+}
+
 func extractStringFlag(args []string, flagName string) (flagValue string, filteredArgs []string, err error) {
+	flagValues, filteredArgs, err := extractStringFlagValues(args, flagName)
+	if len(flagValues) > 0 {
+		flagValue = flagValues[len(flagValues)-1]
+	}
+	return
+}
+
+// Extracts the values of a string flag, which may be repeated, from the args.
+func extractStringFlagValues(args []string, flagName string) (flagValues []string, filteredArgs []string, err error) {
 	filteredArgs = []string{}
 	for argIndex := 0; argIndex < len(args); argIndex++ {
 		fullFlagName := "--" + flagName
 		if args[argIndex] == fullFlagName {
-			if len(args) <= argIndex+1 || strings.HasPrefix(args[argIndex+1], "-") {
-				return "", nil, errors.New("Failed extracting value of provided flag: " + flagName)
+			// A single dash is a value, which stands for the standard input.
+			if len(args) <= argIndex+1 || (strings.HasPrefix(args[argIndex+1], "-") && args[argIndex+1] != "-") {
+				return nil, nil, errors.New("Failed extracting value of provided flag: " + flagName)
 			}
-			flagValue = args[argIndex+1]
+			flagValues = append(flagValues, args[argIndex+1])
 			argIndex++
 		} else if argPrefix := fullFlagName + "="; strings.HasPrefix(args[argIndex], argPrefix) {
-			flagValue = strings.TrimPrefix(args[argIndex], argPrefix)
+			flagValues = append(flagValues, strings.TrimPrefix(args[argIndex], argPrefix))
 		} else {
 			filteredArgs = append(filteredArgs, args[argIndex])
 		}
@@ -382,6 +2462,81 @@ func extractStringFlag(args []string, flagName string) (flagValue string, filter
 	return
 }
 
+// Extracts a boolean flag from the args. The flag is considered set if it appears as '--flagName' or '--flagName=true'.
+func extractBoolFlag(args []string, flagName string) (flagValue bool, filteredArgs []string) {
+	filteredArgs = []string{}
+	fullFlagName := "--" + flagName
+	for _, arg := range args {
+		switch {
+		case arg == fullFlagName:
+			flagValue = true
+		case strings.HasPrefix(arg, fullFlagName+"="):
+			flagValue = strings.TrimPrefix(arg, fullFlagName+"=") == "true"
+		default:
+			filteredArgs = append(filteredArgs, arg)
+		}
+	}
+	return
+}
+
+// toolArgs holds the arguments of a command which passes them to a build tool, such as npm and yarn.
+// The command's options may be set as flags, before the arguments, or among the arguments, from which they're removed.
+type toolArgs struct {
+	context *clitool.Context
+	args    []string
+}
+
+func newToolArgs(context *clitool.Context) *toolArgs {
+	return &toolArgs{context: context, args: context.Args().Slice()}
+}
+
+// Returns true if the boolean option is set either as a flag or among the arguments.
+func (ta *toolArgs) boolValue(flagName string) bool {
+	flagValue, filteredArgs := extractBoolFlag(ta.args, flagName)
+	ta.args = filteredArgs
+	return flagValue || ta.context.Bool(flagName)
+}
+
+// Returns the value of the string option. A value set among the arguments overrides the flag's value.
+func (ta *toolArgs) stringValue(flagName string) (string, error) {
+	flagValue, filteredArgs, err := extractStringFlag(ta.args, flagName)
+	if err != nil {
+		return "", err
+	}
+	ta.args = filteredArgs
+	if flagValue == "" {
+		flagValue = ta.context.String(flagName)
+	}
+	return flagValue, nil
+}
+
+// Returns the values of the repeatable option, set either as flags or among the arguments.
+func (ta *toolArgs) stringValues(flagName string) ([]string, error) {
+	flagValues, filteredArgs, err := extractStringFlagValues(ta.args, flagName)
+	if err != nil {
+		return nil, err
+	}
+	ta.args = filteredArgs
+	return append(slices.Clone(ta.context.StringSlice(flagName)), flagValues...), nil
+}
+
+// Returns the value of the integer option. A value set among the arguments overrides the flag's value.
+func (ta *toolArgs) intValue(flagName string) (int, error) {
+	flagValue, filteredArgs, err := extractStringFlag(ta.args, flagName)
+	if err != nil {
+		return 0, err
+	}
+	ta.args = filteredArgs
+	if flagValue == "" {
+		return ta.context.Int(flagName), nil
+	}
+	intValue, err := strconv.Atoi(flagValue)
+	if err != nil {
+		return 0, fmt.Errorf("the value of the '--%s' option must be a number: %w", flagName, err)
+	}
+	return intValue, nil
+}
+
 func filterCliFlags(allArgs []string, cliFlags []clitool.Flag) []string {
 	var filteredArgs []string
 	for _, arg := range allArgs {
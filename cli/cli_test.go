@@ -1,8 +1,23 @@
 package cli
 
 import (
-	"github.com/stretchr/testify/assert"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/jfrog/build-info-go/api"
+	"github.com/jfrog/build-info-go/build"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	clitool "github.com/urfave/cli/v2"
 )
 
 func TestExtractStringFlag(t *testing.T) {
@@ -18,6 +33,7 @@ func TestExtractStringFlag(t *testing.T) {
 		{args: []string{"a", "--b=c"}, flagName: "a", expectedFlagValue: "", expectedFilteredArgs: []string{"a", "--b=c"}, expectedError: false},
 		{args: []string{"a", "--b"}, flagName: "b", expectedFlagValue: "", expectedFilteredArgs: []string{}, expectedError: true},
 		{args: []string{"a", "--b", "--c", "d"}, flagName: "b", expectedFlagValue: "", expectedFilteredArgs: []string{}, expectedError: true},
+		{args: []string{"a", "--b", "-", "d"}, flagName: "b", expectedFlagValue: "-", expectedFilteredArgs: []string{"a", "d"}, expectedError: false},
 	}
 
 	for _, testCase := range testCases {
@@ -31,3 +47,286 @@ func TestExtractStringFlag(t *testing.T) {
 		assert.Equal(t, testCase.expectedFilteredArgs, actualFilteredArgs)
 	}
 }
+
+func TestExtractBoolFlag(t *testing.T) {
+	testCases := []struct {
+		args                 []string
+		expectedFlagValue    bool
+		expectedFilteredArgs []string
+	}{
+		{args: []string{"install", "--a"}, expectedFlagValue: true, expectedFilteredArgs: []string{"install"}},
+		{args: []string{"--a=true", "install"}, expectedFlagValue: true, expectedFilteredArgs: []string{"install"}},
+		{args: []string{"--a=false", "install"}, expectedFlagValue: false, expectedFilteredArgs: []string{"install"}},
+		{args: []string{"install", "--ab"}, expectedFlagValue: false, expectedFilteredArgs: []string{"install", "--ab"}},
+	}
+
+	for _, testCase := range testCases {
+		actualFlagValue, actualFilteredArgs := extractBoolFlag(testCase.args, "a")
+		assert.Equal(t, testCase.expectedFlagValue, actualFlagValue)
+		assert.Equal(t, testCase.expectedFilteredArgs, actualFilteredArgs)
+	}
+}
+
+func TestExtractStringFlagValues(t *testing.T) {
+	flagValues, filteredArgs, err := extractStringFlagValues([]string{"install", "--prop", "a=b", "--prop=c=d,e", "--other"}, "prop")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a=b", "c=d,e"}, flagValues)
+	assert.Equal(t, []string{"install", "--other"}, filteredArgs)
+}
+
+func TestNpmManifestFlag(t *testing.T) {
+	manifestPath := writeNpmTestManifests(t)
+	manifestFile, err := os.Open(manifestPath)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, manifestFile.Close())
+	}()
+	oldStdin := os.Stdin
+	defer func() {
+		os.Stdin = oldStdin
+	}()
+	os.Stdin = manifestFile
+
+	// The flag is read whether it's parsed before the npm arguments, or is one of them. A dash reads the manifests from the standard input.
+	for _, args := range [][]string{{"--manifest", manifestPath}, {"--", "--manifest", manifestPath}, {"--", "--manifest=" + manifestPath}, {"--", "--manifest", "-"}} {
+		output, err := runTestCommand(t, "", append([]string{"npm"}, args...)...)
+		assert.NoError(t, err, args)
+		assert.Contains(t, output, `"id": "ms:2.1.3"`, args)
+	}
+}
+
+func TestNpmOfflineFlag(t *testing.T) {
+	projectDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, "package.json"), []byte(testNpmPackageJson), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, "package-lock.json"), []byte(testNpmPackageLock), 0644))
+	// npm doesn't run offline, whether the flag is set before the npm arguments or among them.
+	for _, args := range [][]string{{"--offline", "install"}, {"--", "install", "--offline"}} {
+		_, err := runTestCommand(t, projectDir, append([]string{"npm"}, args...)...)
+		assert.ErrorContains(t, err, "npm commands can't run when collecting the dependencies offline", args)
+	}
+}
+
+func TestNpmLowMemoryFlag(t *testing.T) {
+	manifestPath := writeNpmTestManifests(t)
+	for _, args := range [][]string{{"--low-memory", "--manifest", manifestPath}, {"--manifest", manifestPath, "--", "--low-memory"}} {
+		output, err := runTestCommand(t, "", append([]string{"npm"}, args...)...)
+		assert.NoError(t, err, args)
+		assert.Contains(t, output, `"id": "ms:2.1.3"`, args)
+	}
+}
+
+func TestNpmWorkspacesFlags(t *testing.T) {
+	manifestPath := writeNpmTestManifests(t)
+	for _, args := range [][]string{{"--collect-workspaces", "--threads", "2", "--manifest", manifestPath}, {"--manifest", manifestPath, "--", "--collect-workspaces", "--threads=2"}} {
+		_, err := runTestCommand(t, "", append([]string{"npm"}, args...)...)
+		assert.ErrorContains(t, err, "the workspaces can't be collected from the provided manifests", args)
+	}
+	_, err := runTestCommand(t, "", "npm", "--manifest", manifestPath, "--", "--threads=many")
+	assert.ErrorContains(t, err, "the value of the '--threads' option must be a number")
+}
+
+func TestNpmAuditSignaturesFlags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ms" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"versions":{"2.1.3":{"dist":{"signatures":[{"keyid":"SHA256:jl3bwswu80PjjokCgh0o2w5c2U4LhQAE57gj9cz1kzA","sig":"MEUCIQ"}]}}}}`))
+	}))
+	defer server.Close()
+
+	manifestPath := writeNpmTestManifests(t)
+	for _, args := range [][]string{{"--audit-signatures", "--npm-registry", server.URL, "--manifest", manifestPath}, {"--manifest", manifestPath, "--", "--audit-signatures", "--npm-registry=" + server.URL}} {
+		output, err := runTestCommand(t, "", append([]string{"npm"}, args...)...)
+		assert.NoError(t, err, args)
+		assert.Contains(t, output, `"`+build.NpmSignedProperty+`": "true"`, args)
+	}
+}
+
+func TestNpmPropertiesFlags(t *testing.T) {
+	manifestPath := writeNpmTestManifests(t)
+	// The properties may be set both before the npm arguments and among them.
+	output, err := runTestCommand(t, "", "npm", "--build-prop", "ticket=X", "--module-prop", "team=web", "--manifest", manifestPath, "--", "--build-prop", "stage=test")
+	assert.NoError(t, err)
+	assert.Contains(t, output, `"ticket": "X"`)
+	assert.Contains(t, output, `"stage": "test"`)
+	assert.Contains(t, output, `"team": "web"`)
+}
+
+func TestNpmOutputFlags(t *testing.T) {
+	manifestPath := writeNpmTestManifests(t)
+	for _, args := range [][]string{{"--compress", "--manifest", manifestPath}, {"--manifest", manifestPath, "--", "--compress"}} {
+		output, err := runTestCommand(t, "", append([]string{"npm"}, args...)...)
+		assert.NoError(t, err, args)
+		gzipReader, err := gzip.NewReader(strings.NewReader(output))
+		if assert.NoError(t, err, args) {
+			content, err := io.ReadAll(gzipReader)
+			assert.NoError(t, err, args)
+			assert.Contains(t, string(content), `"id": "ms:2.1.3"`, args)
+		}
+	}
+	for _, args := range [][]string{{"--output", "jsonl", "--manifest", manifestPath}, {"--manifest", manifestPath, "--", "--output=jsonl"}} {
+		output, err := runTestCommand(t, "", append([]string{"npm"}, args...)...)
+		assert.NoError(t, err, args)
+		assert.Contains(t, output, `{"event":"dependency","moduleId":"app:1.0.0"`, args)
+	}
+}
+
+func TestNpmSharedFlags(t *testing.T) {
+	manifestPath := writeNpmTestManifests(t)
+	testReportPath := filepath.Join(t.TempDir(), "TEST-app.xml")
+	assert.NoError(t, os.WriteFile(testReportPath, []byte(`<testsuite tests="2" failures="0" skipped="0" time="1.5"><testcase name="a"/><testcase name="b"/></testsuite>`), 0644))
+	testCases := []struct {
+		name   string
+		flags  []string
+		assert func(t *testing.T, output string, err error)
+	}{
+		{"format", []string{"--format", api.CycloneDxJsonFormat}, func(t *testing.T, output string, err error) {
+			assert.NoError(t, err)
+			assert.Contains(t, output, `"bomFormat": "CycloneDX"`)
+		}},
+		{"excludeDep", []string{"--exclude-dep", "ms:*"}, func(t *testing.T, output string, err error) {
+			assert.NoError(t, err)
+			assert.NotContains(t, output, `"id": "ms:2.1.3"`)
+		}},
+		{"testReport", []string{"--test-report", testReportPath}, func(t *testing.T, output string, err error) {
+			assert.NoError(t, err)
+			assert.Contains(t, output, `"`+build.TestsPassedProperty+`": "2"`)
+		}},
+		{"resolutionAudit", []string{"--resolution-audit"}, func(t *testing.T, output string, err error) {
+			assert.NoError(t, err)
+			assert.Contains(t, output, `"`+entities.ResolutionSourceProperty+`": "lockfile"`)
+		}},
+		{"integrity", []string{"--verify-integrity", "sometimes"}, func(t *testing.T, output string, err error) {
+			assert.ErrorContains(t, err, "sometimes")
+		}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// The options may be set both before the npm arguments and among them.
+			output, err := runTestCommand(t, "", append(append([]string{"npm"}, testCase.flags...), "--manifest", manifestPath)...)
+			testCase.assert(t, output, err)
+			output, err = runTestCommand(t, "", append([]string{"npm", "--manifest", manifestPath, "--"}, testCase.flags...)...)
+			testCase.assert(t, output, err)
+		})
+	}
+}
+
+const (
+	testNpmPackageJson = `{"name": "app", "version": "1.0.0", "dependencies": {"ms": "^2.1.0"}}`
+	testNpmPackageLock = `{"lockfileVersion": 3, "packages": {"": {"name": "app", "version": "1.0.0", "dependencies": {"ms": "^2.1.0"}}, "node_modules/ms": {"version": "2.1.3", "integrity": "sha512-ms"}}}`
+)
+
+// Writes the package.json and package-lock.json of an npm project, in the payload read by the '--manifest' option, and returns the payload's path.
+func writeNpmTestManifests(t *testing.T) string {
+	manifestPath := filepath.Join(t.TempDir(), "manifests.json")
+	assert.NoError(t, os.WriteFile(manifestPath, []byte(`{"package.json": `+testNpmPackageJson+`, "package-lock.json": `+testNpmPackageLock+`}`), 0644))
+	return manifestPath
+}
+
+// Runs the 'bi' command with the provided arguments in the provided directory, or in the current directory if it's empty, and returns its standard output.
+func runTestCommand(t *testing.T, dir string, args ...string) (string, error) {
+	if dir != "" {
+		wd, err := os.Getwd()
+		assert.NoError(t, err)
+		assert.NoError(t, os.Chdir(dir))
+		defer func() {
+			assert.NoError(t, os.Chdir(wd))
+		}()
+	}
+	oldStdout := os.Stdout
+	commandOutput, err := os.Create(filepath.Join(t.TempDir(), "output"))
+	assert.NoError(t, err)
+	os.Stdout = commandOutput
+	app := &clitool.App{Name: "Build-Info CLI", Commands: GetCommands(&utils.NullLog{})}
+	err = app.Run(append([]string{"bi"}, args...))
+	os.Stdout = oldStdout
+	assert.NoError(t, commandOutput.Close())
+	content, readErr := os.ReadFile(commandOutput.Name())
+	assert.NoError(t, readErr)
+	return string(content), err
+}
+
+func TestParseProperties(t *testing.T) {
+	properties, err := parseProperties([]string{"ticket=JIRA-123", "pipeline.url=https://ci.example.com/run?id=1", "empty="}, buildPropFlag)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"ticket": "JIRA-123", "pipeline.url": "https://ci.example.com/run?id=1", "empty": ""}, properties)
+
+	_, err = parseProperties([]string{"no-value"}, buildPropFlag)
+	assert.Error(t, err)
+	_, err = parseProperties([]string{"=value"}, modulePropFlag)
+	assert.Error(t, err)
+}
+
+func TestWriteCompletionScript(t *testing.T) {
+	app := &clitool.App{Name: "Build-Info CLI", Commands: GetCommands(&utils.NullLog{})}
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		var script bytes.Buffer
+		assert.NoError(t, writeCompletionScript(app, shell, &script), shell)
+		assert.Contains(t, script.String(), completionProgramName, shell)
+	}
+	var script bytes.Buffer
+	assert.NoError(t, writeCompletionScript(app, "fish", &script))
+	assert.Contains(t, script.String(), "complete -c bi")
+	assert.Error(t, writeCompletionScript(app, "tcsh", &script))
+}
+
+func TestRunInitWizard(t *testing.T) {
+	projectDir := t.TempDir()
+	for _, project := range []string{"backend", "frontend"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(projectDir, project), 0755))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, "backend", "go.mod"), []byte("module example.com/backend\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, "frontend", "package.json"), []byte("{}"), 0644))
+
+	var out bytes.Buffer
+	// Deploy the Go artifacts to go-local, skip the npm artifacts, and share the dependencies.
+	assert.NoError(t, runInitWizard(projectDir, false, strings.NewReader("go-local\n\ny\n"), &out))
+	assert.Contains(t, out.String(), "backend (go)")
+	assert.Contains(t, out.String(), "frontend (npm)")
+	config, err := build.ReadConfig(projectDir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[entities.ModuleType]build.DeployPathConfig{entities.Go: {Repo: "go-local", Template: build.DefaultDeployPathTemplates[entities.Go]}}, config.DeployPaths)
+	assert.True(t, config.ShareDependencies)
+
+	// An existing configuration is overwritten only with --force. Once the input ends, the defaults are used.
+	assert.Error(t, runInitWizard(projectDir, false, strings.NewReader(""), &out))
+	assert.NoError(t, runInitWizard(projectDir, true, strings.NewReader(""), &out))
+	config, err = build.ReadConfig(projectDir)
+	assert.NoError(t, err)
+	assert.Empty(t, config.DeployPaths)
+	assert.False(t, config.ShareDependencies)
+}
+
+func TestParseDependencyExclusion(t *testing.T) {
+	exclusion, err := parseDependencyExclusion("org.example:test-fixtures:*")
+	assert.NoError(t, err)
+	assert.Equal(t, build.DependencyExclusion{Id: "org.example:test-fixtures:*"}, exclusion)
+
+	exclusion, err = parseDependencyExclusion("group=org.example,scope=test")
+	assert.NoError(t, err)
+	assert.Equal(t, build.DependencyExclusion{Group: "org.example", Scope: "test"}, exclusion)
+
+	exclusion, err = parseDependencyExclusion("id=.*-bom:.*,regex=true")
+	assert.NoError(t, err)
+	assert.Equal(t, build.DependencyExclusion{Id: ".*-bom:.*", Regex: true}, exclusion)
+
+	_, err = parseDependencyExclusion("name=lib")
+	assert.Error(t, err)
+	_, err = parseDependencyExclusion("id=(,regex=true")
+	assert.Error(t, err)
+}
+
+func TestSetOutput(t *testing.T) {
+	bld := build.NewBuild("build-info-go-test-output", "1", time.Now(), "", t.TempDir(), &utils.NullLog{})
+	assert.NoError(t, setOutput(bld, "", "", false))
+	assert.NoError(t, setOutput(bld, outputJsonl, "", false))
+	assert.ErrorContains(t, setOutput(bld, outputJsonl, "cyclonedx/json", false), "can't be used with '--output=jsonl'")
+	assert.ErrorContains(t, setOutput(bld, outputJsonl, "", true), "can't be used with '--output=jsonl'")
+	assert.ErrorContains(t, setOutput(bld, "xml", "", false), "is not a valid value for 'output'")
+	// The format is validated before the collection runs.
+	assert.NoError(t, setOutput(bld, "", api.TableFormat, false))
+	assert.ErrorContains(t, setOutput(bld, "", "yaml", false), "'yaml' is not a valid value for 'format'")
+	assert.ErrorContains(t, setOutput(bld, "", api.TableFormat, true), "can't be used with '--format=table'")
+	assert.ErrorContains(t, writeBuild(bld, api.TableFormat, true, &bytes.Buffer{}), "can't be used with '--format=table'")
+}
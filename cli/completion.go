@@ -0,0 +1,47 @@
+package cli
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+
+	clitool "github.com/urfave/cli/v2"
+)
+
+// The name of the CLI executable, which the completion scripts complete.
+const completionProgramName = "bi"
+
+//go:embed completion/bi.bash
+var bashCompletionScript string
+
+//go:embed completion/bi.zsh
+var zshCompletionScript string
+
+//go:embed completion/bi.ps1
+var powershellCompletionScript string
+
+// Writes the completion script of the shell. The bash, zsh and PowerShell scripts query the CLI for the completion candidates,
+// while the fish script lists the commands and flags of the app.
+func writeCompletionScript(app *clitool.App, shell string, writer io.Writer) error {
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletionScript
+	case "zsh":
+		script = zshCompletionScript
+	case "powershell":
+		script = powershellCompletionScript
+	case "fish":
+		// The fish completion is generated for the app's name, which should be the name of the executable.
+		fishApp := *app
+		fishApp.Name = completionProgramName
+		var err error
+		if script, err = fishApp.ToFishCompletion(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("'%s' is not a supported shell. Supported shells are 'bash', 'zsh', 'fish' and 'powershell'", shell)
+	}
+	_, err := io.WriteString(writer, script)
+	return err
+}
@@ -0,0 +1,11 @@
+Register-ArgumentCompleter -Native -CommandName bi -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $arguments = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+    # The word being completed is passed only if it's a flag, as done by the bash completion.
+    if ($wordToComplete -ne '' -and -not $wordToComplete.StartsWith('-')) {
+        $arguments = @($arguments | Select-Object -SkipLast 1)
+    }
+    & bi @arguments --generate-bash-completion 2>$null | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
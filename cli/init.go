@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/build-info-go/build"
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// The package types of the artifacts produced by the projects of each technology.
+var technologyModuleTypes = map[build.ProjectTechnology]entities.ModuleType{
+	build.GoTechnology:     entities.Go,
+	build.MavenTechnology:  entities.Maven,
+	build.GradleTechnology: entities.Gradle,
+	build.NpmTechnology:    entities.Npm,
+	build.YarnTechnology:   entities.Npm,
+	build.PythonTechnology: entities.Python,
+}
+
+// Detects the projects in the directory, asks the user for the configuration of their artifacts,
+// and writes a starter configuration file to the directory.
+func runInitWizard(projectDir string, force bool, in io.Reader, out io.Writer) error {
+	configPath := filepath.Join(projectDir, build.ConfigFileName)
+	exists, err := utils.IsFileExists(configPath, false)
+	if err != nil {
+		return err
+	}
+	if exists && !force {
+		return fmt.Errorf("%s already exists. Use the '--%s' option to overwrite it", configPath, forceFlag)
+	}
+	projects, err := build.DiscoverWorkspaceProjects(projectDir)
+	if err != nil {
+		return err
+	}
+	var header strings.Builder
+	header.WriteString("# build-info-go configuration, created by 'bi init'.\n")
+	var moduleTypes []entities.ModuleType
+	if len(projects) == 0 {
+		fmt.Fprintln(out, "No supported projects were detected in", projectDir)
+	} else {
+		fmt.Fprintln(out, "Detected projects:")
+		header.WriteString("# Detected projects:\n")
+		for _, project := range projects {
+			fmt.Fprintf(out, "  %s (%s)\n", project.Path, project.Technology)
+			fmt.Fprintf(&header, "#   %s (%s)\n", project.Path, project.Technology)
+			if moduleType, ok := technologyModuleTypes[project.Technology]; ok && !slices.Contains(moduleTypes, moduleType) {
+				moduleTypes = append(moduleTypes, moduleType)
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(in)
+	config := &build.Config{}
+	for _, moduleType := range moduleTypes {
+		repo, err := prompt(scanner, out, fmt.Sprintf("Repository to which the %s artifacts are deployed (leave empty to skip): ", moduleType))
+		if err != nil {
+			return err
+		}
+		if repo == "" {
+			continue
+		}
+		if config.DeployPaths == nil {
+			config.DeployPaths = map[entities.ModuleType]build.DeployPathConfig{}
+		}
+		config.DeployPaths[moduleType] = build.DeployPathConfig{Repo: repo, Template: build.DefaultDeployPathTemplates[moduleType]}
+	}
+	if len(projects) > 1 {
+		answer, err := prompt(scanner, out, "Move the dependencies shared by several modules to shared dependencies modules? (y/N): ")
+		if err != nil {
+			return err
+		}
+		config.ShareDependencies = strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes")
+	}
+
+	content := bytes.NewBufferString(header.String())
+	encoder := yaml.NewEncoder(content)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(config); err != nil {
+		return err
+	}
+	if err = encoder.Close(); err != nil {
+		return err
+	}
+	if err = os.WriteFile(configPath, content.Bytes(), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "The configuration was written to", configPath)
+	return nil
+}
+
+// Prints the question and returns the trimmed answer. An empty answer is returned once the input ends.
+func prompt(scanner *bufio.Scanner, out io.Writer, question string) (string, error) {
+	fmt.Fprint(out, question)
+	if !scanner.Scan() {
+		fmt.Fprintln(out)
+		return "", scanner.Err()
+	}
+	return strings.TrimSpace(scanner.Text()), nil
+}
@@ -1,10 +1,13 @@
 package entities
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/jfrog/build-info-go/utils/compareutils"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
+	"io"
 	"regexp"
 	"sort"
 	"strings"
@@ -20,6 +23,20 @@ const (
 	TimeFormat           = "2006-01-02T15:04:05.000-0700"
 	BuildInfoEnvPrefix   = "buildInfo.env."
 	RequestedByMaxLength = 15
+	// The module property which lists the IDs of the shared dependencies modules, whose dependencies belong to the module as well.
+	SharedDependenciesProperty = "buildInfo.sharedDependencies"
+	// The prefix of the IDs of the modules which hold the dependencies shared by several modules.
+	SharedDependenciesModulePrefix = "shared-dependencies-"
+	// The module property which marks a module whose dependencies were approximated, rather than resolved by the build tool.
+	// Its value describes the source of the approximation, for example: maven-log.
+	LowFidelityProperty = "buildInfo.lowFidelity"
+	// The dependency property which marks a dependency resolved from a mutable source, such as a local path, a file: URL,
+	// a SNAPSHOT version or a Git branch, whose content may change without a change to its version. Its value is "true".
+	MutableProperty = "mutable"
+	// The dependency property which holds the number of the dependency's requestedBy paths omitted to limit the size of the build-info.
+	OmittedRequestedByProperty = "omittedRequestedBy"
+	// The dependency property which describes how the dependency was resolved by the collector. Its value is a ResolutionSource, for example: lockfile.
+	ResolutionSourceProperty = "resolutionSource"
 
 	// Build type
 	Build ModuleType = "build"
@@ -34,25 +51,86 @@ const (
 	Go        ModuleType = "go"
 	Python    ModuleType = "python"
 	Terraform ModuleType = "terraform"
+	Gem       ModuleType = "gem"
+	Hex       ModuleType = "hex"
+	Hackage   ModuleType = "hackage"
+	Zig       ModuleType = "zig"
+	CMake     ModuleType = "cmake"
+	Vcpkg     ModuleType = "vcpkg"
+	Cargo     ModuleType = "cargo"
+	Composer  ModuleType = "composer"
+	Cocoapods ModuleType = "cocoapods"
+	Swift     ModuleType = "swift"
+	Conda     ModuleType = "conda"
+	Conan     ModuleType = "conan"
+	Helm      ModuleType = "helm"
+)
+
+// The module types which Artifactory expects in the published build-info.
+var knownModuleTypes = []ModuleType{Build, Generic, Maven, Gradle, Docker, Npm, Nuget, Go, Python, Terraform, Gem, Hex, Hackage, Zig, CMake, Vcpkg,
+	Cargo, Composer, Cocoapods, Swift, Conda, Conan, Helm}
+
+// IsKnown returns true if the module type is one of the types which Artifactory expects, for example: maven or npm.
+func (moduleType ModuleType) IsKnown() bool {
+	return slices.Contains(knownModuleTypes, moduleType)
+}
+
+// ResolutionSource describes how a dependency was resolved by the collector, indicating how trustworthy its details are.
+type ResolutionSource string
+
+const (
+	LockfileSource      ResolutionSource = "lockfile"
+	CliTreeSource       ResolutionSource = "cli-tree"
+	CacheSource         ResolutionSource = "cache"
+	RemoteApiSource     ResolutionSource = "remote-api"
+	FallbackRegexSource ResolutionSource = "fallback-regex"
+	FilesystemSource    ResolutionSource = "filesystem"
+	EmbeddedSource      ResolutionSource = "embedded"
+	UnknownSource       ResolutionSource = "unknown"
+)
+
+// ProjectDependencyType is the type of dependencies on other modules of the same project, such as the other workspaces of a monorepo.
+// The ID of such a dependency is the ID of the module it depends on.
+const ProjectDependencyType = "project"
+
+// GoSumDbStatus describes whether the go.sum hash of a Go module was verified against the Go checksum database (sumdb).
+type GoSumDbStatus string
+
+const (
+	// The checksum database record of the module, cached by the go command, matches its go.sum hash.
+	GoSumDbVerified GoSumDbStatus = "verified"
+	// No checksum database record of the module is cached. Its go.sum hash may have been verified on another machine.
+	GoSumDbUnverified GoSumDbStatus = "unverified"
+	// The checksum database record of the module doesn't match its go.sum hash.
+	GoSumDbMismatch GoSumDbStatus = "mismatch"
+	// The module matches GONOSUMDB or GOPRIVATE, so the go command doesn't verify it.
+	GoSumDbExcluded GoSumDbStatus = "excluded"
+	// The verification is disabled for all modules, for example by GOSUMDB=off.
+	GoSumDbDisabled GoSumDbStatus = "disabled"
 )
 
 type BuildInfo struct {
-	Name          string   `json:"name,omitempty"`
-	Number        string   `json:"number,omitempty"`
-	Agent         *Agent   `json:"agent,omitempty"`
-	BuildAgent    *Agent   `json:"buildAgent,omitempty"`
-	Modules       []Module `json:"modules,omitempty"`
-	Started       string   `json:"started,omitempty"`
-	Properties    Env      `json:"properties,omitempty"`
-	Principal     string   `json:"artifactoryPrincipal,omitempty"`
-	BuildUrl      string   `json:"url,omitempty"`
-	Issues        *Issues  `json:"issues,omitempty"`
-	PluginVersion string   `json:"artifactoryPluginVersion,omitempty"`
-	VcsList       []Vcs    `json:"vcs,omitempty"`
+	// The version of the build-info schema. See SchemaVersions.
+	Version    string   `json:"version,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Number     string   `json:"number,omitempty"`
+	Agent      *Agent   `json:"agent,omitempty"`
+	BuildAgent *Agent   `json:"buildAgent,omitempty"`
+	Modules    []Module `json:"modules,omitempty"`
+	Started    string   `json:"started,omitempty"`
+	// The time from the start of the build until the build-info was generated.
+	DurationMillis int64   `json:"durationMillis,omitempty"`
+	Properties     Env     `json:"properties,omitempty"`
+	Principal      string  `json:"artifactoryPrincipal,omitempty"`
+	BuildUrl       string  `json:"url,omitempty"`
+	Issues         *Issues `json:"issues,omitempty"`
+	PluginVersion  string  `json:"artifactoryPluginVersion,omitempty"`
+	VcsList        []Vcs   `json:"vcs,omitempty"`
 }
 
 func New() *BuildInfo {
 	return &BuildInfo{
+		Version:    CurrentSchemaVersion,
 		Agent:      &Agent{},
 		BuildAgent: &Agent{Name: "GENERIC"},
 		Modules:    make([]Module, 0),
@@ -142,6 +220,216 @@ func (targetBuildInfo *BuildInfo) ExcludeEnv(patterns ...string) error {
 	return nil
 }
 
+// ResolutionSourcesSummary counts the dependencies of all modules by their resolution source.
+// Dependencies without a resolution source are counted as UnknownSource.
+func (targetBuildInfo *BuildInfo) ResolutionSourcesSummary() map[ResolutionSource]int {
+	summary := make(map[ResolutionSource]int)
+	for _, module := range targetBuildInfo.Modules {
+		for _, dependency := range module.Dependencies {
+			source := dependency.GetResolutionSource()
+			if source == "" {
+				source = UnknownSource
+			}
+			summary[source]++
+		}
+	}
+	return summary
+}
+
+// AddProperties adds the properties to the build-info's properties, overriding existing properties with the same keys.
+func (targetBuildInfo *BuildInfo) AddProperties(properties map[string]string) {
+	if len(properties) == 0 {
+		return
+	}
+	if targetBuildInfo.Properties == nil {
+		targetBuildInfo.Properties = make(Env)
+	}
+	for key, value := range properties {
+		targetBuildInfo.Properties[key] = value
+	}
+}
+
+// ValidateModuleTypes returns an error if the type of any of the modules isn't a known module type, such as a type set by a post-processor,
+// so that the published build-info is consistent with the types which Artifactory expects. Modules without a type are valid.
+func (targetBuildInfo *BuildInfo) ValidateModuleTypes() error {
+	var unknownTypes []string
+	for _, module := range targetBuildInfo.Modules {
+		if module.Type != "" && !module.Type.IsKnown() {
+			unknownTypes = append(unknownTypes, fmt.Sprintf("'%s' (module '%s')", module.Type, module.Id))
+		}
+	}
+	if len(unknownTypes) == 0 {
+		return nil
+	}
+	return fmt.Errorf("the build-info has modules of unknown types: %s", strings.Join(unknownTypes, ", "))
+}
+
+// ClearResolutionSources removes the resolution source annotations from the dependencies of all modules.
+func (targetBuildInfo *BuildInfo) ClearResolutionSources() {
+	for i := range targetBuildInfo.Modules {
+		for j := range targetBuildInfo.Modules[i].Dependencies {
+			targetBuildInfo.Modules[i].Dependencies[j].removeProperty(ResolutionSourceProperty)
+		}
+	}
+}
+
+// ShareDependencies moves the dependencies which are identical in several modules to shared dependencies modules,
+// to reduce the size of builds with many modules. Each group of modules which share dependencies gets its own shared dependencies module,
+// and the IDs of the shared dependencies modules of each module are listed in its SharedDependenciesProperty.
+// The result is a valid build-info, which can be restored with ExpandSharedDependencies.
+func (targetBuildInfo *BuildInfo) ShareDependencies() error {
+	// The indexes of the modules which contain each dependency, by the dependency's JSON representation.
+	modulesByDependency := make(map[string][]int)
+	dependenciesByKey := make(map[string]Dependency)
+	var dependencyKeys []string
+	for moduleIndex, module := range targetBuildInfo.Modules {
+		for _, dependency := range module.Dependencies {
+			content, err := json.Marshal(dependency)
+			if err != nil {
+				return err
+			}
+			key := string(content)
+			moduleIndexes, ok := modulesByDependency[key]
+			if !ok {
+				dependencyKeys = append(dependencyKeys, key)
+				dependenciesByKey[key] = dependency
+			}
+			if !slices.Contains(moduleIndexes, moduleIndex) {
+				modulesByDependency[key] = append(moduleIndexes, moduleIndex)
+			}
+		}
+	}
+	// Group the shared dependencies by the modules which contain them.
+	var groupKeys []string
+	sharedModules := make(map[string]*Module)
+	sharedDependencies := make(map[string]bool)
+	for _, key := range dependencyKeys {
+		moduleIndexes := modulesByDependency[key]
+		if len(moduleIndexes) < 2 {
+			continue
+		}
+		groupKey := fmt.Sprint(moduleIndexes)
+		sharedModule, ok := sharedModules[groupKey]
+		if !ok {
+			sharedModule = &Module{
+				Id:   fmt.Sprintf("%s%d", SharedDependenciesModulePrefix, len(groupKeys)+1),
+				Type: targetBuildInfo.Modules[moduleIndexes[0]].Type,
+			}
+			sharedModules[groupKey] = sharedModule
+			groupKeys = append(groupKeys, groupKey)
+			for _, moduleIndex := range moduleIndexes {
+				module := &targetBuildInfo.Modules[moduleIndex]
+				sharedModuleIds := append(module.getSharedDependenciesModuleIds(), sharedModule.Id)
+				module.AddProperties(map[string]string{SharedDependenciesProperty: strings.Join(sharedModuleIds, ",")})
+			}
+		}
+		sharedModule.Dependencies = append(sharedModule.Dependencies, dependenciesByKey[key])
+		sharedDependencies[key] = true
+	}
+	if len(groupKeys) == 0 {
+		return nil
+	}
+	for i := range targetBuildInfo.Modules {
+		module := &targetBuildInfo.Modules[i]
+		var dependencies []Dependency
+		for _, dependency := range module.Dependencies {
+			content, err := json.Marshal(dependency)
+			if err != nil {
+				return err
+			}
+			if !sharedDependencies[string(content)] {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+		module.Dependencies = dependencies
+	}
+	for _, groupKey := range groupKeys {
+		targetBuildInfo.Modules = append(targetBuildInfo.Modules, *sharedModules[groupKey])
+	}
+	return nil
+}
+
+// ExpandSharedDependencies reverts ShareDependencies. The dependencies of the shared dependencies modules are added back
+// to the modules which list them in their SharedDependenciesProperty, and the shared dependencies modules are removed.
+func (targetBuildInfo *BuildInfo) ExpandSharedDependencies() {
+	sharedModules := make(map[string]Module)
+	var modules []Module
+	for _, module := range targetBuildInfo.Modules {
+		if strings.HasPrefix(module.Id, SharedDependenciesModulePrefix) {
+			sharedModules[module.Id] = module
+		} else {
+			modules = append(modules, module)
+		}
+	}
+	if len(sharedModules) == 0 {
+		return
+	}
+	for i := range modules {
+		module := &modules[i]
+		for _, sharedModuleId := range module.getSharedDependenciesModuleIds() {
+			module.Dependencies = append(module.Dependencies, sharedModules[sharedModuleId].Dependencies...)
+		}
+		module.removeProperty(SharedDependenciesProperty)
+	}
+	targetBuildInfo.Modules = modules
+}
+
+// The indentation of the modules in the indented build-info JSON: they're elements of the modules array, which is a field of the build-info.
+const modulesIndentPrefix = "    "
+
+// WriteTo writes the build-info to the writer as indented JSON, followed by a newline.
+// The modules are serialized and written one after the other, so that the formatted document is never held in memory as a whole.
+// The output is identical to the output of json.MarshalIndent with two-space indentation.
+func (targetBuildInfo *BuildInfo) WriteTo(writer io.Writer) (written int64, err error) {
+	write := func(content []byte) error {
+		n, err := writer.Write(content)
+		written += int64(n)
+		return err
+	}
+	withoutModules := *targetBuildInfo
+	if len(targetBuildInfo.Modules) == 0 {
+		withoutModules.Modules = nil
+		content, err := json.MarshalIndent(withoutModules, "", "  ")
+		if err != nil {
+			return written, err
+		}
+		return written, write(append(content, '\n'))
+	}
+	// Serialize the build-info with a placeholder module, and replace the placeholder with the modules.
+	placeholder := Module{Id: "build-info-go-modules-placeholder"}
+	withoutModules.Modules = []Module{placeholder}
+	content, err := json.MarshalIndent(withoutModules, "", "  ")
+	if err != nil {
+		return
+	}
+	placeholderContent, err := json.MarshalIndent(placeholder, modulesIndentPrefix, "  ")
+	if err != nil {
+		return
+	}
+	before, after, found := strings.Cut(string(content), string(placeholderContent))
+	if !found {
+		return written, errors.New("failed to serialize the build-info modules")
+	}
+	if err = write([]byte(before)); err != nil {
+		return
+	}
+	for i, module := range targetBuildInfo.Modules {
+		if i > 0 {
+			if err = write([]byte(",\n" + modulesIndentPrefix)); err != nil {
+				return
+			}
+		}
+		if content, err = json.MarshalIndent(module, modulesIndentPrefix, "  "); err != nil {
+			return
+		}
+		if err = write(content); err != nil {
+			return
+		}
+	}
+	err = write([]byte(after + "\n"))
+	return
+}
+
 func (targetBuildInfo *BuildInfo) ToCycloneDxBom() (*cdx.BOM, error) {
 	var biDependencies []Dependency
 	moduleIds := make(map[string]bool)
@@ -153,10 +441,17 @@ func (targetBuildInfo *BuildInfo) ToCycloneDxBom() (*cdx.BOM, error) {
 		}
 
 		moduleDep := Dependency{Id: module.Id}
+		// The ID of a module may not include its version, in which case it has no package URL.
+		moduleDep.Purl, _ = PackageIdToPurl(module.Type, module.Id)
 		moduleIds[module.Id] = true
 
 		dependenciesToAdd := []Dependency{moduleDep}
-		dependenciesToAdd = append(dependenciesToAdd, module.Dependencies...)
+		for _, dependency := range module.Dependencies {
+			if dependency.Purl == "" {
+				dependency.Purl = dependencyPurl(module.Type, &dependency)
+			}
+			dependenciesToAdd = append(dependenciesToAdd, dependency)
+		}
 		mergeDependenciesLists(&dependenciesToAdd, &biDependencies)
 	}
 
@@ -168,6 +463,7 @@ func (targetBuildInfo *BuildInfo) ToCycloneDxBom() (*cdx.BOM, error) {
 			return nil, err
 		}
 		newComp.BOMRef = biDep.Id
+		newComp.PackageURL = biDep.Purl
 		if _, exist := moduleIds[biDep.Id]; exist {
 			newComp.Type = cdx.ComponentTypeApplication
 		} else {
@@ -284,11 +580,15 @@ func mergeDependenciesLists(dependenciesToAdd, intoDependencies *[]Dependency) {
 
 func mergeDependencies(dep1, dep2 Dependency) Dependency {
 	return Dependency{
-		Id:          dep1.Id,
-		Type:        dep1.Type,
-		Scopes:      mergeStringSlices(dep1.Scopes, dep2.Scopes),
-		RequestedBy: mergeRequestedBySlices(dep1.RequestedBy, dep2.RequestedBy),
-		Checksum:    dep1.Checksum,
+		Id:             dep1.Id,
+		Type:           dep1.Type,
+		Scopes:         mergeStringSlices(dep1.Scopes, dep2.Scopes),
+		RequestedBy:    mergeRequestedBySlices(dep1.RequestedBy, dep2.RequestedBy),
+		Checksum:       dep1.Checksum,
+		Purl:           dep1.Purl,
+		CorrelationIds: dep1.CorrelationIds,
+		// Keep the properties, such as the resolution source, of the dependency that was collected first.
+		Properties: dep1.Properties,
 	}
 }
 
@@ -362,6 +662,57 @@ type Module struct {
 	Checksum
 }
 
+// AddProperties adds the properties to the module's properties, overriding existing properties with the same keys.
+// Existing properties which aren't key-value pairs are replaced.
+func (m *Module) AddProperties(properties map[string]string) {
+	if len(properties) == 0 {
+		return
+	}
+	merged, ok := m.Properties.(map[string]interface{})
+	if !ok {
+		merged = make(map[string]interface{})
+		if existing, ok := m.Properties.(map[string]string); ok {
+			for key, value := range existing {
+				merged[key] = value
+			}
+		}
+	}
+	for key, value := range properties {
+		merged[key] = value
+	}
+	m.Properties = merged
+}
+
+// Returns the IDs of the shared dependencies modules listed in the module's SharedDependenciesProperty.
+func (m *Module) getSharedDependenciesModuleIds() []string {
+	var value string
+	switch properties := m.Properties.(type) {
+	case map[string]interface{}:
+		value, _ = properties[SharedDependenciesProperty].(string)
+	case map[string]string:
+		value = properties[SharedDependenciesProperty]
+	}
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func (m *Module) removeProperty(key string) {
+	switch properties := m.Properties.(type) {
+	case map[string]interface{}:
+		delete(properties, key)
+		if len(properties) == 0 {
+			m.Properties = nil
+		}
+	case map[string]string:
+		delete(properties, key)
+		if len(properties) == 0 {
+			m.Properties = nil
+		}
+	}
+}
+
 // If the 'other' Module matches the current one, return true.
 // 'other' Module may contain regex values for Id, Artifacts, ExcludedArtifacts, Dependencies and Checksum.
 func (m *Module) isEqual(other Module) (bool, error) {
@@ -427,6 +778,14 @@ type Artifact struct {
 	// Named 'original' because the repository might change throughout the lifecycle of the build.
 	// This field is not recognized by Artifactory, and is used for internal purposes only.
 	OriginalDeploymentRepo string `json:"originalDeploymentRepo,omitempty"`
+	// The path of the artifact in Artifactory, including the repository, for example: libs-release-local/org/jfrog/app/1.0/app-1.0.jar
+	// Only set when the repository is known.
+	RemotePath string `json:"remotePath,omitempty"`
+	// The size of the artifact's file in bytes.
+	Size int64 `json:"size,omitempty"`
+	// The artifacts bundled inside the artifact, such as the jars inside a WAR. Their paths are relative to the root of the artifact.
+	// This field is not recognized by Artifactory.
+	SubArtifacts []Artifact `json:"subArtifacts,omitempty"`
 	Checksum
 }
 
@@ -481,9 +840,36 @@ type Dependency struct {
 	Type        string     `json:"type,omitempty"`
 	Scopes      []string   `json:"scopes,omitempty"`
 	RequestedBy [][]string `json:"requestedBy,omitempty"`
+	// GoIntegrity holds the go.sum hash and the checksum database verification status of a Go module.
+	// This field is not recognized by Artifactory.
+	GoIntegrity *GoModuleIntegrity `json:"goIntegrity,omitempty"`
+	// RemoteRepository is the URL of the remote repository from which the dependency was downloaded, when it's known,
+	// or the repository's ID (or name) when only the ID is known.
+	// This field is not recognized by Artifactory.
+	RemoteRepository string `json:"remoteRepository,omitempty"`
+	// Purl is the package URL of the dependency, for example: pkg:npm/lodash@4.17.21
+	// This field is not recognized by Artifactory.
+	Purl string `json:"purl,omitempty"`
+	// CorrelationIds are the IDs of the dependency's component in security scanners, by the scanners' names, for example: xray -> npm://lodash:4.17.21
+	// This field is not recognized by Artifactory.
+	CorrelationIds map[string]string `json:"correlationIds,omitempty"`
+	// Vulnerabilities is a placeholder for the vulnerabilities of the dependency, which may be filled by downstream scanners.
+	// This field is not recognized by Artifactory.
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+	// Properties are additional details of the dependency, by their names, for example: nuget.signature -> author
+	Properties map[string]string `json:"properties,omitempty"`
 	Checksum
 }
 
+type GoModuleIntegrity struct {
+	// The hash of the module's content, as recorded in go.sum.
+	GoSumHash   string        `json:"goSumHash,omitempty"`
+	SumDbStatus GoSumDbStatus `json:"sumDbStatus,omitempty"`
+	// The checksum database and the ID of the module's record in it, which can be used to request the record's inclusion proof.
+	SumDbName     string `json:"sumDbName,omitempty"`
+	SumDbRecordId int64  `json:"sumDbRecordId,omitempty"`
+}
+
 // If the 'other' Dependency matches the current one, return true.
 // 'other' Dependency may contain regex values for Id and Checksum.
 func (d *Dependency) IsEqual(other Dependency) (bool, error) {
@@ -528,6 +914,40 @@ func isEqualDependencySlices(actual, other []Dependency) (bool, error) {
 	}
 	return true, nil
 }
+
+// SetMutable marks the dependency as resolved from a mutable source. See MutableProperty.
+func (d *Dependency) SetMutable() {
+	if d.Properties == nil {
+		d.Properties = make(map[string]string)
+	}
+	d.Properties[MutableProperty] = "true"
+}
+
+// IsMutable returns true if the dependency is marked as resolved from a mutable source. See MutableProperty.
+func (d *Dependency) IsMutable() bool {
+	return d.Properties[MutableProperty] == "true"
+}
+
+// SetResolutionSource records how the dependency was resolved by the collector. See ResolutionSourceProperty.
+func (d *Dependency) SetResolutionSource(source ResolutionSource) {
+	if d.Properties == nil {
+		d.Properties = make(map[string]string)
+	}
+	d.Properties[ResolutionSourceProperty] = string(source)
+}
+
+// GetResolutionSource returns how the dependency was resolved by the collector, or an empty string if it isn't recorded. See ResolutionSourceProperty.
+func (d *Dependency) GetResolutionSource() ResolutionSource {
+	return ResolutionSource(d.Properties[ResolutionSourceProperty])
+}
+
+func (d *Dependency) removeProperty(key string) {
+	delete(d.Properties, key)
+	if len(d.Properties) == 0 {
+		d.Properties = nil
+	}
+}
+
 func (d *Dependency) UpdateRequestedBy(parentId string, parentRequestedBy [][]string) {
 	// Filter all existing paths from parent
 	var filteredChildRequestedBy [][]string
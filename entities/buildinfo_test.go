@@ -1,11 +1,14 @@
 package entities
 
 import (
+	"bytes"
+	"encoding/json"
 	"reflect"
 	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slices"
 )
 
 func TestIsEqualModuleSlices(t *testing.T) {
@@ -273,3 +276,161 @@ func TestToCycloneDxBOM(t *testing.T) {
 		assert.True(t, dependsOnIsSorted)
 	}
 }
+
+func TestToCycloneDxBomPackageUrls(t *testing.T) {
+	buildInfo := BuildInfo{
+		Modules: []Module{{
+			Id:   "org.example:app:1.0",
+			Type: Maven,
+			Dependencies: []Dependency{
+				{Id: "org.slf4j:slf4j-api:2.0.9", Type: "jar"},
+				{Id: "org.example:bom:1.0", Type: "pom"},
+				{Id: "org.example:custom:1.0", Purl: "pkg:maven/org.example/custom@1.0?repository_url=repo.example.com"},
+			},
+		}, {
+			// Go modules IDs don't include their versions.
+			Id:           "github.com/jfrog/app",
+			Type:         Go,
+			Dependencies: []Dependency{{Id: "github.com/jfrog/gofrog:v1.7.6"}},
+		}},
+	}
+	cdxBom, err := buildInfo.ToCycloneDxBom()
+	assert.NoError(t, err)
+	packageUrls := make(map[string]string)
+	for _, component := range *cdxBom.Components {
+		packageUrls[component.BOMRef] = component.PackageURL
+	}
+	assert.Equal(t, map[string]string{
+		"org.example:app:1.0":            "pkg:maven/org.example/app@1.0",
+		"org.slf4j:slf4j-api:2.0.9":      "pkg:maven/org.slf4j/slf4j-api@2.0.9",
+		"org.example:bom:1.0":            "pkg:maven/org.example/bom@1.0?type=pom",
+		"org.example:custom:1.0":         "pkg:maven/org.example/custom@1.0?repository_url=repo.example.com",
+		"github.com/jfrog/app":           "",
+		"github.com/jfrog/gofrog:v1.7.6": "pkg:golang/github.com/jfrog/gofrog@v1.7.6",
+	}, packageUrls)
+}
+
+func TestResolutionSourcesSummary(t *testing.T) {
+	buildInfo := BuildInfo{
+		Modules: []Module{
+			{Id: "module-1", Dependencies: []Dependency{{Id: "dep-a"}, {Id: "dep-b", Properties: map[string]string{MutableProperty: "true"}}}},
+			{Id: "module-2", Dependencies: []Dependency{{Id: "dep-c"}, {Id: "dep-d"}}},
+		},
+	}
+	buildInfo.Modules[0].Dependencies[0].SetResolutionSource(LockfileSource)
+	buildInfo.Modules[0].Dependencies[1].SetResolutionSource(CliTreeSource)
+	buildInfo.Modules[1].Dependencies[0].SetResolutionSource(LockfileSource)
+	assert.Equal(t, map[string]string{ResolutionSourceProperty: "lockfile"}, buildInfo.Modules[0].Dependencies[0].Properties)
+	assert.Equal(t, map[ResolutionSource]int{LockfileSource: 2, CliTreeSource: 1, UnknownSource: 1}, buildInfo.ResolutionSourcesSummary())
+
+	// Only the resolution source properties are removed.
+	buildInfo.ClearResolutionSources()
+	assert.Equal(t, map[ResolutionSource]int{UnknownSource: 4}, buildInfo.ResolutionSourcesSummary())
+	assert.Nil(t, buildInfo.Modules[0].Dependencies[0].Properties)
+	assert.Equal(t, map[string]string{MutableProperty: "true"}, buildInfo.Modules[0].Dependencies[1].Properties)
+}
+
+func TestValidateModuleTypes(t *testing.T) {
+	assert.True(t, Cargo.IsKnown())
+	assert.True(t, Build.IsKnown())
+	assert.False(t, ModuleType("my-type").IsKnown())
+
+	// Modules without a type are valid.
+	buildInfo := BuildInfo{Modules: []Module{{Id: "module-1", Type: Maven}, {Id: "module-2"}, {Id: "module-3", Type: Helm}}}
+	assert.NoError(t, buildInfo.ValidateModuleTypes())
+
+	buildInfo.Modules = append(buildInfo.Modules, Module{Id: "module-4", Type: "my-type"}, Module{Id: "module-5", Type: "Maven"})
+	assert.EqualError(t, buildInfo.ValidateModuleTypes(), "the build-info has modules of unknown types: 'my-type' (module 'module-4'), 'Maven' (module 'module-5')")
+}
+
+func TestAddProperties(t *testing.T) {
+	buildInfo := BuildInfo{
+		Properties: Env{"buildInfo.env.PATH": "/usr/bin"},
+		Modules: []Module{
+			{Id: "module-1"},
+			{Id: "module-2", Properties: map[string]interface{}{"existing": "value", "ticket": "old"}},
+			{Id: "module-3", Properties: map[string]string{"existing": "value"}},
+		},
+	}
+	buildInfo.AddProperties(map[string]string{"pipeline": "42"})
+	assert.Equal(t, Env{"buildInfo.env.PATH": "/usr/bin", "pipeline": "42"}, buildInfo.Properties)
+
+	for i := range buildInfo.Modules {
+		buildInfo.Modules[i].AddProperties(map[string]string{"ticket": "JIRA-123"})
+	}
+	assert.Equal(t, map[string]interface{}{"ticket": "JIRA-123"}, buildInfo.Modules[0].Properties)
+	assert.Equal(t, map[string]interface{}{"existing": "value", "ticket": "JIRA-123"}, buildInfo.Modules[1].Properties)
+	assert.Equal(t, map[string]interface{}{"existing": "value", "ticket": "JIRA-123"}, buildInfo.Modules[2].Properties)
+
+	// Adding no properties leaves the module untouched.
+	module := Module{Id: "module-4"}
+	module.AddProperties(nil)
+	assert.Nil(t, module.Properties)
+}
+
+func TestShareDependencies(t *testing.T) {
+	shared := Dependency{Id: "shared:1.0", Checksum: Checksum{Sha1: "1"}}
+	sharedByTwo := Dependency{Id: "shared-by-two:1.0", Checksum: Checksum{Sha1: "2"}}
+	// Dependencies with the same ID, but different requesters, aren't shared.
+	requestedByA := Dependency{Id: "requested:1.0", RequestedBy: [][]string{{"a"}}}
+	requestedByB := Dependency{Id: "requested:1.0", RequestedBy: [][]string{{"b"}}}
+	original := []Module{
+		{Id: "a", Type: Maven, Dependencies: []Dependency{shared, sharedByTwo, requestedByA}},
+		{Id: "b", Type: Maven, Properties: map[string]string{"team": "platform"}, Dependencies: []Dependency{shared, sharedByTwo, requestedByB}},
+		{Id: "c", Type: Maven, Dependencies: []Dependency{shared}},
+	}
+	buildInfo := &BuildInfo{Modules: slices.Clone(original)}
+	for i := range buildInfo.Modules {
+		buildInfo.Modules[i].Dependencies = slices.Clone(original[i].Dependencies)
+	}
+	assert.NoError(t, buildInfo.ShareDependencies())
+
+	assert.Len(t, buildInfo.Modules, 5)
+	assert.Equal(t, []Dependency{requestedByA}, buildInfo.Modules[0].Dependencies)
+	assert.Equal(t, []Dependency{requestedByB}, buildInfo.Modules[1].Dependencies)
+	assert.Empty(t, buildInfo.Modules[2].Dependencies)
+	assert.Equal(t, Module{Id: "shared-dependencies-1", Type: Maven, Dependencies: []Dependency{shared}}, buildInfo.Modules[3])
+	assert.Equal(t, Module{Id: "shared-dependencies-2", Type: Maven, Dependencies: []Dependency{sharedByTwo}}, buildInfo.Modules[4])
+	assert.Equal(t, map[string]interface{}{SharedDependenciesProperty: "shared-dependencies-1,shared-dependencies-2"}, buildInfo.Modules[0].Properties)
+	assert.Equal(t, map[string]interface{}{"team": "platform", SharedDependenciesProperty: "shared-dependencies-1,shared-dependencies-2"}, buildInfo.Modules[1].Properties)
+	assert.Equal(t, map[string]interface{}{SharedDependenciesProperty: "shared-dependencies-1"}, buildInfo.Modules[2].Properties)
+
+	// The shared dependencies are restored after the build-info is serialized.
+	content, err := json.Marshal(buildInfo)
+	assert.NoError(t, err)
+	restored := &BuildInfo{}
+	assert.NoError(t, json.Unmarshal(content, restored))
+	restored.ExpandSharedDependencies()
+	assert.Len(t, restored.Modules, 3)
+	for i, module := range restored.Modules {
+		assert.Equal(t, original[i].Id, module.Id)
+		assert.ElementsMatch(t, original[i].Dependencies, module.Dependencies)
+	}
+	assert.Nil(t, restored.Modules[0].Properties)
+	assert.Equal(t, map[string]interface{}{"team": "platform"}, restored.Modules[1].Properties)
+}
+
+func TestWriteTo(t *testing.T) {
+	buildInfo := &BuildInfo{
+		Name:       "write-to",
+		Number:     "1",
+		Agent:      &Agent{Name: "agent"},
+		Started:    "2024-01-01T00:00:00.000+0000",
+		Properties: Env{"key": "<value>"},
+		Modules: []Module{
+			{Id: "a", Type: Go, Dependencies: []Dependency{{Id: "dep:1.0", Scopes: []string{"compile"}}}},
+			{Id: "b", Type: Npm, Artifacts: []Artifact{{Name: "b.tgz"}}},
+		},
+		VcsList: []Vcs{{Url: "https://github.com/jfrog/build-info-go"}},
+	}
+	for _, modules := range [][]Module{buildInfo.Modules, nil} {
+		buildInfo.Modules = modules
+		expected, err := json.MarshalIndent(buildInfo, "", "  ")
+		assert.NoError(t, err)
+		var content bytes.Buffer
+		written, err := buildInfo.WriteTo(&content)
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected)+"\n", content.String())
+		assert.Equal(t, int64(content.Len()), written)
+	}
+}
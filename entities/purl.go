@@ -0,0 +1,139 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jfrog/build-info-go/utils/purl"
+)
+
+// The key of the Xray component ID in the correlation IDs of a dependency.
+const XrayCorrelationKey = "xray"
+
+// Vulnerability is a vulnerability of a dependency, reported by a security scanner.
+type Vulnerability struct {
+	// The vulnerability's ID, for example: CVE-2021-23337
+	Id       string `json:"id,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	// The scanner which reported the vulnerability, for example: xray
+	Source        string   `json:"source,omitempty"`
+	FixedVersions []string `json:"fixedVersions,omitempty"`
+}
+
+// The package URL types of the module types.
+var modulePurlTypes = map[ModuleType]string{
+	Maven:   purl.Maven,
+	Gradle:  purl.Maven,
+	Npm:     purl.Npm,
+	Go:      purl.Golang,
+	Python:  purl.Pypi,
+	Nuget:   purl.Nuget,
+	Docker:  purl.Docker,
+	Gem:     purl.Gem,
+	Hex:     purl.Hex,
+	Hackage: purl.Hackage,
+}
+
+// The module types of the package URL types.
+var purlModuleTypes = map[string]ModuleType{
+	purl.Maven:   Maven,
+	purl.Npm:     Npm,
+	purl.Golang:  Go,
+	purl.Pypi:    Python,
+	purl.Nuget:   Nuget,
+	purl.Docker:  Docker,
+	purl.Gem:     Gem,
+	purl.Hex:     Hex,
+	purl.Hackage: Hackage,
+}
+
+// PackageIdToPurl converts the ID of a module, or of a dependency of a module of the given type, to its canonical package URL,
+// for example: org.slf4j:slf4j-api:2.0.9 of a Maven module to pkg:maven/org.slf4j/slf4j-api@2.0.9
+// The package ID formats are described in purl.FromPackageId.
+func PackageIdToPurl(moduleType ModuleType, packageId string) (string, error) {
+	purlType, ok := modulePurlTypes[moduleType]
+	if !ok {
+		return "", errors.New("the '" + string(moduleType) + "' module type doesn't have a package URL type")
+	}
+	packageUrl, err := purl.FromPackageId(purlType, packageId)
+	if err != nil {
+		return "", err
+	}
+	return packageUrl.String(), nil
+}
+
+// PurlToPackageId converts a package URL to a build-info package ID, and returns the module type of its package URL type.
+// The module type is empty if the package URL type has no matching module type, for example: conan
+func PurlToPackageId(packageUrl string) (moduleType ModuleType, packageId string, err error) {
+	parsed, err := purl.Parse(packageUrl)
+	if err != nil {
+		return
+	}
+	return purlModuleTypes[parsed.Type], parsed.ToPackageId(), nil
+}
+
+// SetComponentIds sets the package URL and the Xray component ID of each dependency, by the type of the module which depends on it,
+// so that security scanners can match the dependencies to their components without deriving the coordinates from the IDs.
+// Dependencies of module types without a package URL type, and dependencies which already have a package URL, are skipped.
+func (targetBuildInfo *BuildInfo) SetComponentIds() {
+	for i := range targetBuildInfo.Modules {
+		module := &targetBuildInfo.Modules[i]
+		for j := range module.Dependencies {
+			dependency := &module.Dependencies[j]
+			if dependency.Purl != "" {
+				continue
+			}
+			dependency.Purl = dependencyPurl(module.Type, dependency)
+			if xrayId := xrayComponentId(module.Type, dependency.Id); xrayId != "" {
+				if dependency.CorrelationIds == nil {
+					dependency.CorrelationIds = make(map[string]string)
+				}
+				dependency.CorrelationIds[XrayCorrelationKey] = xrayId
+			}
+		}
+	}
+}
+
+// Returns the package URL of the dependency, or an empty string if it can't be converted,
+// for example: pkg:maven/org.slf4j/slf4j-api@2.0.9, pkg:npm/%40jfrog/build-info@1.0.0 or pkg:golang/github.com/jfrog/gofrog@v1.7.6
+// The image layers of Docker modules aren't converted.
+func dependencyPurl(moduleType ModuleType, dependency *Dependency) string {
+	purlType, ok := modulePurlTypes[moduleType]
+	if !ok || moduleType == Docker {
+		return ""
+	}
+	packageUrl, err := purl.FromPackageId(purlType, dependency.Id)
+	if err != nil {
+		return ""
+	}
+	if purlType == purl.Maven && dependency.Type != "" && dependency.Type != "jar" {
+		packageUrl.Qualifiers = map[string]string{"type": dependency.Type}
+	}
+	return packageUrl.String()
+}
+
+// Returns the ID of the dependency's component in Xray, or an empty string if the module type isn't supported,
+// for example: gav://org.slf4j:slf4j-api:2.0.9 or npm://lodash:4.17.21
+func xrayComponentId(moduleType ModuleType, dependencyId string) string {
+	var prefix string
+	switch moduleType {
+	case Maven, Gradle:
+		prefix = "gav://"
+	case Npm:
+		prefix = "npm://"
+	case Go:
+		prefix = "go://"
+	case Python:
+		prefix = "pypi://"
+	case Nuget:
+		prefix = "nuget://"
+	case Gem:
+		prefix = "rubygems://"
+	default:
+		return ""
+	}
+	if index := strings.LastIndex(dependencyId, ":"); index <= 0 || index == len(dependencyId)-1 {
+		return ""
+	}
+	return prefix + dependencyId
+}
@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetComponentIds(t *testing.T) {
+	buildInfo := &BuildInfo{Modules: []Module{
+		{Id: "org.example:app:1.0", Type: Maven, Dependencies: []Dependency{
+			{Id: "org.slf4j:slf4j-api:2.0.9", Type: "jar"},
+			{Id: "org.example:bom:1.0", Type: "pom"},
+		}},
+		{Id: "app:1.0.0", Type: Npm, Dependencies: []Dependency{{Id: "@jfrog/build-info:1.0.0+build.1"}}},
+		{Id: "github.com/jfrog/app", Type: Go, Dependencies: []Dependency{{Id: "github.com/jfrog/gofrog:v1.7.6"}}},
+		{Id: "service", Type: Python, Dependencies: []Dependency{
+			{Id: "Typing_Extensions:4.8.0"},
+			// A package URL which is already set isn't overridden.
+			{Id: "requests:2.31.0", Purl: "pkg:pypi/requests@2.31.0?repository_url=https://example.com"},
+		}},
+		{Id: "App", Type: Nuget, Dependencies: []Dependency{{Id: "Newtonsoft.Json:13.0.3"}}},
+		{Id: "app:1.0.0", Type: Gem, Dependencies: []Dependency{{Id: "rack:3.0.8"}}},
+		{Id: "my_app:0.1.0", Type: Hex, Dependencies: []Dependency{{Id: "plug_cowboy:2.6.1"}}},
+		{Id: "image", Type: Docker, Dependencies: []Dependency{{Id: "sha256__1234"}}},
+	}}
+	buildInfo.SetComponentIds()
+
+	expected := []struct{ purl, xrayId string }{
+		{"pkg:maven/org.slf4j/slf4j-api@2.0.9", "gav://org.slf4j:slf4j-api:2.0.9"},
+		{"pkg:maven/org.example/bom@1.0?type=pom", "gav://org.example:bom:1.0"},
+		{"pkg:npm/%40jfrog/build-info@1.0.0%2Bbuild.1", "npm://@jfrog/build-info:1.0.0+build.1"},
+		{"pkg:golang/github.com/jfrog/gofrog@v1.7.6", "go://github.com/jfrog/gofrog:v1.7.6"},
+		{"pkg:pypi/typing-extensions@4.8.0", "pypi://Typing_Extensions:4.8.0"},
+		{"pkg:pypi/requests@2.31.0?repository_url=https://example.com", ""},
+		{"pkg:nuget/Newtonsoft.Json@13.0.3", "nuget://Newtonsoft.Json:13.0.3"},
+		{"pkg:gem/rack@3.0.8", "rubygems://rack:3.0.8"},
+		{"pkg:hex/plug_cowboy@2.6.1", ""},
+		{"", ""},
+	}
+	var actual []struct{ purl, xrayId string }
+	for _, module := range buildInfo.Modules {
+		for _, dependency := range module.Dependencies {
+			actual = append(actual, struct{ purl, xrayId string }{dependency.Purl, dependency.CorrelationIds[XrayCorrelationKey]})
+		}
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestPurlToPackageId(t *testing.T) {
+	tests := []struct {
+		purl, expectedId string
+		expectedType     ModuleType
+	}{
+		{"pkg:maven/org.jfrog/build-info@1.0.0?type=jar", "org.jfrog:build-info:1.0.0", Maven},
+		{"pkg:npm/%40jfrog/build-info@1.0.0", "@jfrog/build-info:1.0.0", Npm},
+		{"pkg:npm/@jfrog/build-info", "@jfrog/build-info", Npm},
+		{"pkg:golang/github.com/jfrog/gofrog@v1.7.6#subpath", "github.com/jfrog/gofrog:v1.7.6", Go},
+		{"pkg:pypi/requests@2.31.0", "requests:2.31.0", Python},
+		{"pkg:hex/jason@1.4.1", "jason:1.4.1", Hex},
+		{"pkg:hackage/aeson@2.1.2.1", "aeson:2.1.2.1", Hackage},
+		{"pkg:conan/zlib@1.3?user=conan&channel=stable", "zlib/1.3@conan/stable", ""},
+	}
+	for _, test := range tests {
+		moduleType, id, err := PurlToPackageId(test.purl)
+		assert.NoError(t, err, test.purl)
+		assert.Equal(t, test.expectedId, id, test.purl)
+		assert.Equal(t, test.expectedType, moduleType, test.purl)
+	}
+	_, _, err := PurlToPackageId("npm/build-info@1.0.0")
+	assert.Error(t, err)
+}
+
+func TestPackageIdToPurl(t *testing.T) {
+	packageUrl, err := PackageIdToPurl(Gradle, "org.jfrog:build-info:1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "pkg:maven/org.jfrog/build-info@1.0.0", packageUrl)
+	packageUrl, err = PackageIdToPurl(Docker, "acme.jfrog.io/app:1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "pkg:docker/app@1.0?repository_url=acme.jfrog.io", packageUrl)
+	_, err = PackageIdToPurl(Terraform, "module:1.0")
+	assert.Error(t, err)
+}
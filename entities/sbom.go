@@ -0,0 +1,255 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"golang.org/x/exp/slices"
+)
+
+// A component of an SBOM, which is converted to a build-info module or dependency.
+type sbomComponent struct {
+	ref        string
+	id         string
+	moduleType ModuleType
+	checksum   Checksum
+}
+
+// The components of an SBOM and the dependencies between them.
+type sbomGraph struct {
+	root       string
+	components []sbomComponent
+	// The refs of the direct dependencies of each component, by its ref.
+	dependencies map[string][]string
+}
+
+// NewModuleFromCycloneDxBom converts a CycloneDX SBOM to a build-info module.
+// The module is the BOM's metadata component, or its only application component, and the rest of the components are its dependencies.
+// The IDs of the module and the dependencies are taken from the components' package URLs if they exist, and from their group, name and version otherwise.
+func NewModuleFromCycloneDxBom(bom *cdx.BOM) (*Module, error) {
+	graph := sbomGraph{dependencies: make(map[string][]string)}
+	var applications []string
+	addComponent := func(component cdx.Component) {
+		sbomComp := sbomComponent{ref: component.BOMRef, id: cycloneDxComponentId(component)}
+		sbomComp.setPurl(component.PackageURL)
+		if sbomComp.ref == "" {
+			sbomComp.ref = sbomComp.id
+		}
+		if component.Hashes != nil {
+			for _, hash := range *component.Hashes {
+				switch hash.Algorithm {
+				case cdx.HashAlgoSHA1:
+					sbomComp.checksum.Sha1 = hash.Value
+				case cdx.HashAlgoMD5:
+					sbomComp.checksum.Md5 = hash.Value
+				case cdx.HashAlgoSHA256:
+					sbomComp.checksum.Sha256 = hash.Value
+				}
+			}
+		}
+		if component.Type == cdx.ComponentTypeApplication {
+			applications = append(applications, sbomComp.ref)
+		}
+		graph.components = append(graph.components, sbomComp)
+	}
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
+		addComponent(*bom.Metadata.Component)
+		graph.root = graph.components[0].ref
+	}
+	if bom.Components != nil {
+		for _, component := range *bom.Components {
+			addComponent(component)
+		}
+	}
+	if graph.root == "" {
+		if len(applications) != 1 {
+			return nil, errors.New("the CycloneDX SBOM should have a metadata component, or a single application component, which describes the module")
+		}
+		graph.root = applications[0]
+	}
+	if bom.Dependencies != nil {
+		for _, dependency := range *bom.Dependencies {
+			if dependency.Dependencies != nil {
+				graph.dependencies[dependency.Ref] = append(graph.dependencies[dependency.Ref], *dependency.Dependencies...)
+			}
+		}
+	}
+	return graph.toModule()
+}
+
+func cycloneDxComponentId(component cdx.Component) string {
+	var parts []string
+	for _, part := range []string{component.Group, component.Name, component.Version} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+// The subset of the SPDX 2.x JSON document which is converted to a build-info module.
+type spdxDocument struct {
+	SpdxVersion       string             `json:"spdxVersion"`
+	DocumentDescribes []string           `json:"documentDescribes"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxPackage struct {
+	SpdxId      string `json:"SPDXID"`
+	Name        string `json:"name"`
+	VersionInfo string `json:"versionInfo"`
+	Checksums   []struct {
+		Algorithm     string `json:"algorithm"`
+		ChecksumValue string `json:"checksumValue"`
+	} `json:"checksums"`
+	ExternalRefs []struct {
+		ReferenceType    string `json:"referenceType"`
+		ReferenceLocator string `json:"referenceLocator"`
+	} `json:"externalRefs"`
+}
+
+type spdxRelationship struct {
+	SpdxElementId      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
+// NewModuleFromSpdxJson converts an SPDX 2.x JSON document to a build-info module.
+// The module is the package which the document describes, and the rest of the packages are its dependencies.
+// The IDs of the module and the dependencies are taken from the packages' package URLs if they exist, and from their name and version otherwise.
+func NewModuleFromSpdxJson(content []byte) (*Module, error) {
+	var document spdxDocument
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	if document.SpdxVersion == "" {
+		return nil, errors.New("the document is not an SPDX JSON document")
+	}
+	graph := sbomGraph{dependencies: make(map[string][]string)}
+	if len(document.DocumentDescribes) > 0 {
+		graph.root = document.DocumentDescribes[0]
+	}
+	for _, relationship := range document.Relationships {
+		from, to := relationship.SpdxElementId, relationship.RelatedSpdxElement
+		switch relationship.RelationshipType {
+		case "DESCRIBES":
+			if graph.root == "" {
+				graph.root = to
+			}
+			continue
+		case "DESCRIBED_BY":
+			if graph.root == "" {
+				graph.root = from
+			}
+			continue
+		case "DEPENDENCY_OF", "DEV_DEPENDENCY_OF", "BUILD_DEPENDENCY_OF", "TEST_DEPENDENCY_OF", "RUNTIME_DEPENDENCY_OF", "OPTIONAL_DEPENDENCY_OF":
+			from, to = to, from
+		case "DEPENDS_ON", "CONTAINS":
+		default:
+			continue
+		}
+		graph.dependencies[from] = append(graph.dependencies[from], to)
+	}
+	if graph.root == "" {
+		return nil, errors.New("the SPDX document doesn't describe a package")
+	}
+	for _, spdxPkg := range document.Packages {
+		sbomComp := sbomComponent{ref: spdxPkg.SpdxId, id: spdxPkg.Name}
+		if spdxPkg.VersionInfo != "" {
+			sbomComp.id += ":" + spdxPkg.VersionInfo
+		}
+		for _, externalRef := range spdxPkg.ExternalRefs {
+			if externalRef.ReferenceType == "purl" {
+				sbomComp.setPurl(externalRef.ReferenceLocator)
+			}
+		}
+		for _, checksum := range spdxPkg.Checksums {
+			switch checksum.Algorithm {
+			case "SHA1":
+				sbomComp.checksum.Sha1 = checksum.ChecksumValue
+			case "MD5":
+				sbomComp.checksum.Md5 = checksum.ChecksumValue
+			case "SHA256":
+				sbomComp.checksum.Sha256 = checksum.ChecksumValue
+			}
+		}
+		graph.components = append(graph.components, sbomComp)
+	}
+	return graph.toModule()
+}
+
+// Sets the component's ID and module type from its package URL. Invalid package URLs are ignored.
+func (component *sbomComponent) setPurl(packageUrl string) {
+	if packageUrl == "" {
+		return
+	}
+	if moduleType, id, err := PurlToPackageId(packageUrl); err == nil {
+		component.id, component.moduleType = id, moduleType
+	}
+}
+
+// Converts the graph to a module, whose dependencies are the components other than the root.
+// Each dependency is requested by each of its parents, through the parent's shortest path from the root.
+func (graph *sbomGraph) toModule() (*Module, error) {
+	componentsByRef := make(map[string]sbomComponent, len(graph.components))
+	for _, component := range graph.components {
+		componentsByRef[component.ref] = component
+	}
+	root, ok := componentsByRef[graph.root]
+	if !ok {
+		return nil, errors.New("the SBOM doesn't contain the component which describes the module: " + graph.root)
+	}
+	module := &Module{Id: root.id, Type: root.moduleType}
+
+	// The shortest path of each component from the root, in the RequestedBy format, which starts with the component's parent.
+	paths := map[string][]string{graph.root: nil}
+	queue := []string{graph.root}
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		for _, child := range graph.dependencies[ref] {
+			if _, visited := paths[child]; visited {
+				continue
+			}
+			paths[child] = append([]string{componentsByRef[ref].id}, paths[ref]...)
+			queue = append(queue, child)
+		}
+	}
+
+	// The parents of each component, in the order of the components.
+	parents := make(map[string][]string)
+	for _, component := range graph.components {
+		for _, child := range graph.dependencies[component.ref] {
+			if !slices.Contains(parents[child], component.ref) {
+				parents[child] = append(parents[child], component.ref)
+			}
+		}
+	}
+
+	for _, component := range graph.components {
+		if component.ref == graph.root {
+			continue
+		}
+		if module.Type == "" {
+			module.Type = component.moduleType
+		}
+		dependency := Dependency{Id: component.id, Checksum: component.checksum}
+		for _, parentRef := range parents[component.ref] {
+			if parentPath, reachable := paths[parentRef]; reachable {
+				requestedBy := append([]string{componentsByRef[parentRef].id}, parentPath...)
+				if len(requestedBy) > RequestedByMaxLength {
+					requestedBy = requestedBy[:RequestedByMaxLength]
+				}
+				dependency.RequestedBy = append(dependency.RequestedBy, requestedBy)
+			}
+		}
+		module.Dependencies = append(module.Dependencies, dependency)
+	}
+	if module.Type == "" {
+		module.Type = Generic
+	}
+	return module, nil
+}
@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewModuleFromCycloneDxBom(t *testing.T) {
+	dependencyA := Dependency{Id: "org.jfrog:a:1.0", Checksum: Checksum{Sha1: "a-sha1", Md5: "a-md5", Sha256: "a-sha256"}, RequestedBy: [][]string{{"org.jfrog:module:1.0"}}}
+	dependencyB := Dependency{Id: "org.jfrog:b:1.0", RequestedBy: [][]string{{"org.jfrog:a:1.0", "org.jfrog:module:1.0"}, {"org.jfrog:module:1.0"}}}
+	buildInfo := BuildInfo{Modules: []Module{{Id: "org.jfrog:module:1.0", Dependencies: []Dependency{dependencyA, dependencyB}}}}
+	bom, err := buildInfo.ToCycloneDxBom()
+	require.NoError(t, err)
+
+	module, err := NewModuleFromCycloneDxBom(bom)
+	require.NoError(t, err)
+	assert.Equal(t, "org.jfrog:module:1.0", module.Id)
+	assert.Equal(t, Generic, module.Type)
+	assert.ElementsMatch(t, []Dependency{dependencyA, dependencyB}, module.Dependencies)
+
+	// Without a metadata component or a single application component, the module can't be determined.
+	for i := range *bom.Components {
+		(*bom.Components)[i].Type = cdx.ComponentTypeLibrary
+	}
+	_, err = NewModuleFromCycloneDxBom(bom)
+	assert.Error(t, err)
+}
+
+func TestNewModuleFromCycloneDxBomWithPackageUrls(t *testing.T) {
+	bom := cdx.NewBOM()
+	bom.Metadata = &cdx.Metadata{Component: &cdx.Component{BOMRef: "root", Name: "my-app", Version: "2.0.0", PackageURL: "pkg:npm/my-app@2.0.0"}}
+	bom.Components = &[]cdx.Component{
+		{BOMRef: "scoped", PackageURL: "pkg:npm/%40jfrog/build-info@1.0.0"},
+		{BOMRef: "nested", Name: "nested", Version: "3.0.0"},
+	}
+	bom.Dependencies = &[]cdx.Dependency{
+		{Ref: "root", Dependencies: &[]string{"scoped"}},
+		{Ref: "scoped", Dependencies: &[]string{"nested"}},
+	}
+	module, err := NewModuleFromCycloneDxBom(bom)
+	require.NoError(t, err)
+	assert.Equal(t, "my-app:2.0.0", module.Id)
+	assert.Equal(t, Npm, module.Type)
+	assert.Equal(t, []Dependency{
+		{Id: "@jfrog/build-info:1.0.0", RequestedBy: [][]string{{"my-app:2.0.0"}}},
+		{Id: "nested:3.0.0", RequestedBy: [][]string{{"@jfrog/build-info:1.0.0", "my-app:2.0.0"}}},
+	}, module.Dependencies)
+}
+
+func TestNewModuleFromSpdxJson(t *testing.T) {
+	document := `{
+  "spdxVersion": "SPDX-2.3",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "packages": [
+    {"SPDXID": "SPDXRef-root", "name": "github.com/jfrog/build-info-go", "versionInfo": "v1.10.0",
+     "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:golang/github.com/jfrog/build-info-go@v1.10.0"}]},
+    {"SPDXID": "SPDXRef-gofrog", "name": "gofrog", "versionInfo": "v1.7.6",
+     "checksums": [{"algorithm": "SHA1", "checksumValue": "gofrog-sha1"}, {"algorithm": "SHA256", "checksumValue": "gofrog-sha256"}],
+     "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:golang/github.com/jfrog/gofrog@v1.7.6"}]},
+    {"SPDXID": "SPDXRef-color", "name": "color", "versionInfo": "v1.5.4"}
+  ],
+  "relationships": [
+    {"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-root"},
+    {"spdxElementId": "SPDXRef-root", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-gofrog"},
+    {"spdxElementId": "SPDXRef-color", "relationshipType": "DEPENDENCY_OF", "relatedSpdxElement": "SPDXRef-gofrog"}
+  ]
+}`
+	module, err := NewModuleFromSpdxJson([]byte(document))
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/jfrog/build-info-go:v1.10.0", module.Id)
+	assert.Equal(t, Go, module.Type)
+	assert.Equal(t, []Dependency{
+		{Id: "github.com/jfrog/gofrog:v1.7.6", Checksum: Checksum{Sha1: "gofrog-sha1", Sha256: "gofrog-sha256"}, RequestedBy: [][]string{{"github.com/jfrog/build-info-go:v1.10.0"}}},
+		{Id: "color:v1.5.4", RequestedBy: [][]string{{"github.com/jfrog/gofrog:v1.7.6", "github.com/jfrog/build-info-go:v1.10.0"}}},
+	}, module.Dependencies)
+
+	_, err = NewModuleFromSpdxJson([]byte(`{"bomFormat": "CycloneDX"}`))
+	assert.Error(t, err)
+}
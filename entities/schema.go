@@ -0,0 +1,99 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// The versions of the build-info schema, which are recorded in the build-info's version field.
+const (
+	// The base schema: the artifacts and dependencies have SHA1 and MD5 checksums only.
+	SchemaVersion20 = "2.0"
+	// Adds the SHA256 checksums, the requestedBy paths of the dependencies, and the remote paths and sizes of the artifacts.
+	SchemaVersion21 = "2.1"
+	// Adds the fields recorded by build-info-go which aren't recognized by Artifactory, such as the purls and remote repositories of the dependencies.
+	SchemaVersion22 = "2.2"
+	// The schema of the build-info generated by this version of build-info-go.
+	CurrentSchemaVersion = SchemaVersion22
+)
+
+// The supported schema versions, from the oldest to the newest.
+var SchemaVersions = []string{SchemaVersion20, SchemaVersion21, SchemaVersion22}
+
+// The functions which convert a build-info of each schema version to the previous version, by removing the fields the previous version doesn't have.
+var schemaDowngrades = map[string]func(buildInfo *BuildInfo){
+	SchemaVersion21: downgradeToSchema20,
+	SchemaVersion22: downgradeToSchema21,
+}
+
+// ConvertSchema converts the build-info to the given schema version, so it can be published to Artifactory instances which don't accept newer versions.
+// Converting to an older version removes the fields which the older version doesn't have. Converting to a newer version only updates the version,
+// since the new fields can't be restored.
+// A build-info without a version is treated as a build-info of the current version. So is a build-info of a 1.x version, such as the 1.0.1 version
+// written by the build-info extractors of Maven and Gradle, which may have the fields of any later version, so all the fields the target version doesn't have are removed.
+func (targetBuildInfo *BuildInfo) ConvertSchema(version string) error {
+	targetIndex := slices.Index(SchemaVersions, version)
+	if targetIndex < 0 {
+		return fmt.Errorf("unsupported build-info schema version '%s'. Supported versions are: %s", version, strings.Join(SchemaVersions, ", "))
+	}
+	sourceVersion := targetBuildInfo.Version
+	if sourceVersion == "" || strings.HasPrefix(sourceVersion, "1.") {
+		sourceVersion = CurrentSchemaVersion
+	}
+	sourceIndex := slices.Index(SchemaVersions, sourceVersion)
+	if sourceIndex < 0 {
+		return fmt.Errorf("the build-info has an unknown schema version '%s'. Supported versions are: %s", sourceVersion, strings.Join(SchemaVersions, ", "))
+	}
+	for i := sourceIndex; i > targetIndex; i-- {
+		schemaDowngrades[SchemaVersions[i]](targetBuildInfo)
+	}
+	targetBuildInfo.Version = version
+	return nil
+}
+
+func downgradeToSchema21(buildInfo *BuildInfo) {
+	for i := range buildInfo.Modules {
+		module := &buildInfo.Modules[i]
+		module.Parent = ""
+		forEachArtifact(module, func(artifact *Artifact) {
+			artifact.OriginalDeploymentRepo = ""
+			artifact.SubArtifacts = nil
+		})
+		for j := range module.Dependencies {
+			dependency := &module.Dependencies[j]
+			dependency.GoIntegrity = nil
+			dependency.RemoteRepository = ""
+			dependency.Purl = ""
+			dependency.CorrelationIds = nil
+			dependency.Vulnerabilities = nil
+		}
+	}
+}
+
+func downgradeToSchema20(buildInfo *BuildInfo) {
+	for i := range buildInfo.Modules {
+		module := &buildInfo.Modules[i]
+		module.Sha256 = ""
+		forEachArtifact(module, func(artifact *Artifact) {
+			artifact.Sha256 = ""
+			artifact.RemotePath = ""
+			artifact.Size = 0
+		})
+		for j := range module.Dependencies {
+			module.Dependencies[j].Sha256 = ""
+			module.Dependencies[j].RequestedBy = nil
+		}
+	}
+}
+
+// Calls the given function with each of the module's artifacts and excluded artifacts.
+func forEachArtifact(module *Module, update func(artifact *Artifact)) {
+	for i := range module.Artifacts {
+		update(&module.Artifacts[i])
+	}
+	for i := range module.ExcludedArtifacts {
+		update(&module.ExcludedArtifacts[i])
+	}
+}
@@ -0,0 +1,80 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertSchema(t *testing.T) {
+	newBuildInfo := func() *BuildInfo {
+		return &BuildInfo{Name: "build", Number: "1", Modules: []Module{{
+			Id:     "org.example:app:1.0",
+			Type:   Maven,
+			Parent: "org.example:parent:1.0",
+			Artifacts: []Artifact{{
+				Name:                   "app-1.0.jar",
+				Path:                   "org/example/app/1.0/app-1.0.jar",
+				OriginalDeploymentRepo: "libs-release-local",
+				RemotePath:             "libs-release-local/org/example/app/1.0/app-1.0.jar",
+				Size:                   3,
+				SubArtifacts:           []Artifact{{Name: "guava-32.1.2-jre.jar", Path: "BOOT-INF/lib/guava-32.1.2-jre.jar"}},
+				Checksum:               Checksum{Sha1: "sha1", Md5: "md5", Sha256: "sha256"},
+			}},
+			Dependencies: []Dependency{{
+				Id:              "org.example:lib:1.0",
+				Scopes:          []string{"compile"},
+				RequestedBy:     [][]string{{"org.example:app:1.0"}},
+				Purl:            "pkg:maven/org.example/lib@1.0",
+				Vulnerabilities: []Vulnerability{{Id: "CVE-2021-23337"}},
+				Properties:      map[string]string{ResolutionSourceProperty: string(LockfileSource)},
+				Checksum:        Checksum{Sha1: "sha1", Md5: "md5", Sha256: "sha256"},
+			}},
+		}}}
+	}
+
+	buildInfo := newBuildInfo()
+	assert.NoError(t, buildInfo.ConvertSchema(SchemaVersion21))
+	assert.Equal(t, SchemaVersion21, buildInfo.Version)
+	assert.Empty(t, buildInfo.Modules[0].Parent)
+	assert.Equal(t, Artifact{
+		Name:       "app-1.0.jar",
+		Path:       "org/example/app/1.0/app-1.0.jar",
+		RemotePath: "libs-release-local/org/example/app/1.0/app-1.0.jar",
+		Size:       3,
+		Checksum:   Checksum{Sha1: "sha1", Md5: "md5", Sha256: "sha256"},
+	}, buildInfo.Modules[0].Artifacts[0])
+	assert.Equal(t, Dependency{
+		Id:          "org.example:lib:1.0",
+		Scopes:      []string{"compile"},
+		RequestedBy: [][]string{{"org.example:app:1.0"}},
+		// The resolution source is a property, which all the versions have.
+		Properties: map[string]string{ResolutionSourceProperty: string(LockfileSource)},
+		Checksum:   Checksum{Sha1: "sha1", Md5: "md5", Sha256: "sha256"},
+	}, buildInfo.Modules[0].Dependencies[0])
+
+	// Converting to the oldest version goes through each of the versions in between.
+	buildInfo = newBuildInfo()
+	assert.NoError(t, buildInfo.ConvertSchema(SchemaVersion20))
+	assert.Equal(t, SchemaVersion20, buildInfo.Version)
+	assert.Equal(t, Artifact{Name: "app-1.0.jar", Path: "org/example/app/1.0/app-1.0.jar", Checksum: Checksum{Sha1: "sha1", Md5: "md5"}}, buildInfo.Modules[0].Artifacts[0])
+	assert.Equal(t, Dependency{Id: "org.example:lib:1.0", Scopes: []string{"compile"}, Properties: map[string]string{ResolutionSourceProperty: string(LockfileSource)},
+		Checksum: Checksum{Sha1: "sha1", Md5: "md5"}}, buildInfo.Modules[0].Dependencies[0])
+
+	// Converting to a newer version only updates the version.
+	assert.NoError(t, buildInfo.ConvertSchema(CurrentSchemaVersion))
+	assert.Equal(t, CurrentSchemaVersion, buildInfo.Version)
+	assert.Empty(t, buildInfo.Modules[0].Dependencies[0].Sha256)
+
+	assert.ErrorContains(t, buildInfo.ConvertSchema("1.0.1"), "unsupported build-info schema version '1.0.1'")
+
+	// A build-info of a 1.x version, as written by the build-info extractors, is converted as a build-info of the current version.
+	buildInfo = newBuildInfo()
+	buildInfo.Version = "1.0.1"
+	assert.NoError(t, buildInfo.ConvertSchema(SchemaVersion20))
+	assert.Equal(t, SchemaVersion20, buildInfo.Version)
+	assert.Empty(t, buildInfo.Modules[0].Parent)
+	assert.Empty(t, buildInfo.Modules[0].Dependencies[0].Sha256)
+	buildInfo.Version = "3.0"
+	assert.ErrorContains(t, buildInfo.ConvertSchema(SchemaVersion20), "unknown schema version '3.0'")
+}
@@ -12,19 +12,24 @@ const logLevelEnv = "BUILD_INFO_LOG_LEVEL"
 
 var log utils.Log
 var cliVersion = "dev"
+var errorFormat string
 
 func main() {
 	log = utils.NewDefaultLogger(getCliLogLevel())
 	app := &clitool.App{
 		Name:     "Build-Info CLI",
 		Usage:    "Generate build-info for your source code",
+		Flags:    cli.GetGlobalFlags(&errorFormat),
 		Commands: cli.GetCommands(log),
 		Version:  cliVersion,
+		// Used by the scripts of the 'completion' command.
+		EnableBashCompletion: true,
+		// Allow commas in the values of repeatable flags, such as '--build-prop'.
+		DisableSliceFlagSeparator: true,
 	}
 	err := app.Run(os.Args)
 	if err != nil {
-		log.Error(err)
-		os.Exit(1)
+		os.Exit(cli.HandleError(err, errorFormat, log))
 	}
 }
 
@@ -43,3 +43,14 @@ func CreateTempBuildFile(buildName, buildNumber, projectKey, buildsDirPath strin
 	}
 	return tempFile, nil
 }
+
+// LockBuildDir locks the directory of a build, so that the bi processes which collect the same build concurrently
+// don't read its files while they're written, or remove them. See LockFile.
+func LockBuildDir(buildName, buildNumber, projectKey, buildsDirPath string) (unlock func() error, err error) {
+	buildDir, err := GetBuildDir(buildName, buildNumber, projectKey, buildsDirPath)
+	if err != nil {
+		return nil, err
+	}
+	// The lock file is placed next to the directory, so that it isn't read as a build file, and isn't removed with the directory.
+	return LockFile(buildDir)
+}
@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jfrog/gofrog/crypto"
+)
+
+const (
+	// The path of the checksum cache, relative to the user's cache directory.
+	ChecksumCachePath = "jfrog/build-info-go/checksums"
+	// The default number of files whose checksums are kept in the cache. The least recently used files are evicted first.
+	DefaultChecksumCacheMaxEntries = 10000
+	checksumCacheFileName          = "checksums.json"
+)
+
+// The cached checksums of a file, which are valid as long as the file's size and modification time don't change.
+type checksumCacheEntry struct {
+	Size    int64 `json:"size"`
+	ModTime int64 `json:"modTime"`
+	// The time the entry was last used, in Unix seconds. Used for the LRU eviction.
+	LastUsed int64  `json:"lastUsed"`
+	Sha1     string `json:"sha1,omitempty"`
+	Md5      string `json:"md5,omitempty"`
+	Sha256   string `json:"sha256,omitempty"`
+}
+
+// ChecksumCache persists the checksums of files between runs, keyed by the files' absolute paths,
+// so that large files which didn't change since the last run (for example, fat jars) aren't hashed again.
+// A file is hashed again if its size or modification time changed. The cache is safe for concurrent use.
+// A nil cache is valid, and calculates the checksums of every file.
+type ChecksumCache struct {
+	cacheDir   string
+	maxEntries int
+	entries    map[string]*checksumCacheEntry
+	modified   bool
+	// The number of checksums read from the cache since it was loaded.
+	hits  int
+	mutex sync.Mutex
+}
+
+// GetDefaultChecksumCacheDir returns the directory of the machine-level checksum cache, in the user's cache directory.
+func GetDefaultChecksumCacheDir() (string, error) {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(userCacheDir, ChecksumCachePath), nil
+}
+
+// NewChecksumCache loads the checksum cache saved in cacheDir. The cache is empty if it wasn't saved before, or if it's corrupted.
+// maxEntries is the number of files whose checksums are kept when the cache is saved. Pass 0 for DefaultChecksumCacheMaxEntries.
+func NewChecksumCache(cacheDir string, maxEntries int) (*ChecksumCache, error) {
+	if maxEntries <= 0 {
+		maxEntries = DefaultChecksumCacheMaxEntries
+	}
+	cache := &ChecksumCache{cacheDir: cacheDir, maxEntries: maxEntries, entries: map[string]*checksumCacheEntry{}}
+	content, err := os.ReadFile(filepath.Join(cacheDir, checksumCacheFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cache, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(content, &cache.entries); err != nil {
+		// A corrupted cache is treated as an empty cache, and overridden when it's saved.
+		cache.entries = map[string]*checksumCacheEntry{}
+		cache.modified = true
+	}
+	return cache, nil
+}
+
+// GetFileChecksums returns the SHA1, MD5 and SHA256 checksums of the file, from the cache if the file didn't change since they were cached.
+func (cc *ChecksumCache) GetFileChecksums(filePath string) (map[crypto.Algorithm]string, error) {
+	if cc == nil {
+		return crypto.GetFileChecksums(filePath)
+	}
+	absolutePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, err
+	}
+	fileInfo, err := os.Stat(absolutePath)
+	if err != nil {
+		return nil, err
+	}
+	cc.mutex.Lock()
+	entry, ok := cc.entries[absolutePath]
+	if ok && entry.Size == fileInfo.Size() && entry.ModTime == fileInfo.ModTime().UnixNano() {
+		entry.LastUsed = time.Now().Unix()
+		cc.modified = true
+		cc.hits++
+		cc.mutex.Unlock()
+		return map[crypto.Algorithm]string{crypto.SHA1: entry.Sha1, crypto.MD5: entry.Md5, crypto.SHA256: entry.Sha256}, nil
+	}
+	cc.mutex.Unlock()
+
+	checksums, err := crypto.GetFileChecksums(absolutePath)
+	if err != nil {
+		return nil, err
+	}
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	cc.entries[absolutePath] = &checksumCacheEntry{
+		Size:     fileInfo.Size(),
+		ModTime:  fileInfo.ModTime().UnixNano(),
+		LastUsed: time.Now().Unix(),
+		Sha1:     checksums[crypto.SHA1],
+		Md5:      checksums[crypto.MD5],
+		Sha256:   checksums[crypto.SHA256],
+	}
+	cc.modified = true
+	return checksums, nil
+}
+
+// Save evicts the least recently used entries beyond the maximal number of entries, and writes the cache to its directory.
+// The cache isn't written if it wasn't modified since it was loaded.
+func (cc *ChecksumCache) Save() (err error) {
+	if cc == nil {
+		return nil
+	}
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	cc.evict(cc.maxEntries)
+	if !cc.modified {
+		return nil
+	}
+	content, err := json.Marshal(cc.entries)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(cc.cacheDir, 0777); err != nil {
+		return err
+	}
+	// The cache is shared by all the builds on the machine, which may be saving it concurrently.
+	unlock, err := LockFile(filepath.Join(cc.cacheDir, checksumCacheFileName))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, unlock())
+	}()
+	// Concurrent builds on the same machine never read a partially written cache.
+	if err = WriteFileAtomically(filepath.Join(cc.cacheDir, checksumCacheFileName), content, 0600); err != nil {
+		return err
+	}
+	cc.modified = false
+	return nil
+}
+
+// Prune removes the entries of files which no longer exist or were modified since they were cached,
+// and the least recently used entries beyond maxEntries. Returns the number of removed entries.
+// The cache should be saved afterwards.
+func (cc *ChecksumCache) Prune(maxEntries int) (removed int) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	for filePath, entry := range cc.entries {
+		fileInfo, err := os.Stat(filePath)
+		if err != nil || fileInfo.Size() != entry.Size || fileInfo.ModTime().UnixNano() != entry.ModTime {
+			delete(cc.entries, filePath)
+			removed++
+		}
+	}
+	removed += cc.evict(maxEntries)
+	if removed > 0 {
+		cc.modified = true
+	}
+	return
+}
+
+// Len returns the number of files whose checksums are cached.
+func (cc *ChecksumCache) Len() int {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	return len(cc.entries)
+}
+
+// Hits returns the number of files whose checksums were read from the cache, rather than calculated, since the cache was loaded.
+func (cc *ChecksumCache) Hits() int {
+	if cc == nil {
+		return 0
+	}
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	return cc.hits
+}
+
+// Removes the least recently used entries beyond maxEntries, and returns the number of removed entries.
+func (cc *ChecksumCache) evict(maxEntries int) int {
+	if maxEntries <= 0 || len(cc.entries) <= maxEntries {
+		return 0
+	}
+	filePaths := make([]string, 0, len(cc.entries))
+	for filePath := range cc.entries {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Slice(filePaths, func(i, j int) bool {
+		if cc.entries[filePaths[i]].LastUsed != cc.entries[filePaths[j]].LastUsed {
+			return cc.entries[filePaths[i]].LastUsed < cc.entries[filePaths[j]].LastUsed
+		}
+		return filePaths[i] < filePaths[j]
+	})
+	evicted := filePaths[:len(filePaths)-maxEntries]
+	for _, filePath := range evicted {
+		delete(cc.entries, filePath)
+	}
+	cc.modified = true
+	return len(evicted)
+}
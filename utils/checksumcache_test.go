@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/gofrog/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksumCache(t *testing.T) {
+	cacheDir, filesDir := t.TempDir(), t.TempDir()
+	filePath := filepath.Join(filesDir, "app.jar")
+	require.NoError(t, os.WriteFile(filePath, []byte("jar"), 0644))
+	expected, err := crypto.GetFileChecksums(filePath)
+	require.NoError(t, err)
+
+	checksumCache, err := NewChecksumCache(cacheDir, 0)
+	require.NoError(t, err)
+	checksums, err := checksumCache.GetFileChecksums(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, expected, checksums)
+	require.NoError(t, checksumCache.Save())
+
+	// The cached checksums are returned as long as the file's size and modification time don't change.
+	checksumCache, err = NewChecksumCache(cacheDir, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, checksumCache.Len())
+	checksumCache.entries[filePath].Sha1 = "cached"
+	checksums, err = checksumCache.GetFileChecksums(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", checksums[crypto.SHA1])
+	assert.Equal(t, 1, checksumCache.Hits())
+
+	require.NoError(t, os.WriteFile(filePath, []byte("modified jar"), 0644))
+	checksums, err = checksumCache.GetFileChecksums(filePath)
+	require.NoError(t, err)
+	assert.NotEqual(t, "cached", checksums[crypto.SHA1])
+	assert.NotEqual(t, expected, checksums)
+	assert.Equal(t, 1, checksumCache.Hits())
+
+	// A nil cache calculates the checksums.
+	var nilCache *ChecksumCache
+	checksums, err = nilCache.GetFileChecksums(filePath)
+	require.NoError(t, err)
+	assert.NotEmpty(t, checksums[crypto.SHA256])
+	assert.NoError(t, nilCache.Save())
+	assert.Zero(t, nilCache.Hits())
+
+	_, err = checksumCache.GetFileChecksums(filepath.Join(filesDir, "missing.jar"))
+	assert.Error(t, err)
+}
+
+func TestChecksumCachePrune(t *testing.T) {
+	cacheDir, filesDir := t.TempDir(), t.TempDir()
+	checksumCache, err := NewChecksumCache(cacheDir, 0)
+	require.NoError(t, err)
+	var filePaths []string
+	for _, name := range []string{"a.jar", "b.jar", "c.jar", "deleted.jar"} {
+		filePath := filepath.Join(filesDir, name)
+		require.NoError(t, os.WriteFile(filePath, []byte(name), 0644))
+		_, err = checksumCache.GetFileChecksums(filePath)
+		require.NoError(t, err)
+		filePaths = append(filePaths, filePath)
+	}
+	require.NoError(t, os.Remove(filePaths[3]))
+	// b.jar and c.jar were used more recently than a.jar.
+	checksumCache.entries[filePaths[0]].LastUsed = time.Now().Add(-time.Hour).Unix()
+
+	assert.Equal(t, 2, checksumCache.Prune(2))
+	require.NoError(t, checksumCache.Save())
+	checksumCache, err = NewChecksumCache(cacheDir, 0)
+	require.NoError(t, err)
+	assert.Len(t, checksumCache.entries, 2)
+	assert.Contains(t, checksumCache.entries, filePaths[1])
+	assert.Contains(t, checksumCache.entries, filePaths[2])
+
+	// The least recently used entries beyond the maximal number of entries are evicted when the cache is saved.
+	checksumCache, err = NewChecksumCache(cacheDir, 1)
+	require.NoError(t, err)
+	checksumCache.entries[filePaths[1]].LastUsed--
+	require.NoError(t, checksumCache.Save())
+	checksumCache, err = NewChecksumCache(cacheDir, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filePaths[2]}, mapKeys(checksumCache.entries))
+}
+
+func TestNewChecksumCacheCorrupted(t *testing.T) {
+	cacheDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, checksumCacheFileName), []byte("{corrupted"), 0644))
+	checksumCache, err := NewChecksumCache(cacheDir, 0)
+	require.NoError(t, err)
+	assert.Zero(t, checksumCache.Len())
+}
+
+func mapKeys(entries map[string]*checksumCacheEntry) (keys []string) {
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	return
+}
@@ -0,0 +1,229 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	gofrogcmd "github.com/jfrog/gofrog/io"
+)
+
+// CommandRunner runs the external commands of the collectors, such as 'npm ls', 'go mod graph', 'pip install' and 'mvn --version'.
+// Replace it with SetCommandRunner to collect dependencies without running the tools, for example, in tests which stub the tools' outputs.
+type CommandRunner interface {
+	// Run runs the command, whose path, arguments, directory and environment are already set, and returns its standard output and standard error.
+	// If the command's Stdout or Stderr is already set, the matching output may be written to it rather than returned.
+	Run(command *exec.Cmd) (stdout, stderr []byte, err error)
+}
+
+// CommandRunnerFunc is an adapter which allows using a function as a CommandRunner.
+type CommandRunnerFunc func(command *exec.Cmd) (stdout, stderr []byte, err error)
+
+func (runnerFunc CommandRunnerFunc) Run(command *exec.Cmd) (stdout, stderr []byte, err error) {
+	return runnerFunc(command)
+}
+
+// The default runner, which runs the commands as processes.
+// The outputs whose writers are set are streamed to them, and the others are returned.
+type processCommandRunner struct{}
+
+func (processCommandRunner) Run(command *exec.Cmd) (stdout, stderr []byte, err error) {
+	var outBuffer, errBuffer *bytes.Buffer
+	if command.Stdout == nil {
+		outBuffer = bytes.NewBuffer([]byte{})
+		command.Stdout = outBuffer
+	}
+	if command.Stderr == nil {
+		errBuffer = bytes.NewBuffer([]byte{})
+		command.Stderr = errBuffer
+	}
+	err = command.Run()
+	if outBuffer != nil {
+		stdout = outBuffer.Bytes()
+	}
+	if errBuffer != nil {
+		stderr = errBuffer.Bytes()
+	}
+	return
+}
+
+var (
+	commandRunner      CommandRunner = processCommandRunner{}
+	commandRunnerMutex sync.RWMutex
+)
+
+// SetCommandRunner replaces the runner of the collectors' commands, and returns a function which restores the previous runner.
+// The runner is shared by all the collectors, so it shouldn't be replaced while dependencies are being collected.
+func SetCommandRunner(runner CommandRunner) (restore func()) {
+	commandRunnerMutex.Lock()
+	defer commandRunnerMutex.Unlock()
+	previous := commandRunner
+	commandRunner = runner
+	return func() {
+		SetCommandRunner(previous)
+	}
+}
+
+func getCommandRunner() CommandRunner {
+	commandRunnerMutex.RLock()
+	defer commandRunnerMutex.RUnlock()
+	return commandRunner
+}
+
+// IsProcessCommandRunner returns true if the commands run as processes, rather than by a runner set with SetCommandRunner.
+func IsProcessCommandRunner() bool {
+	_, isProcess := getCommandRunner().(processCommandRunner)
+	return isProcess
+}
+
+// RunCommand runs the command with the current CommandRunner, and records it in the CommandStats.
+// If the command's Stdout or Stderr is set, the matching output is written to it, and isn't returned.
+func RunCommand(command *exec.Cmd) (stdout, stderr []byte, err error) {
+	stdoutWriter, stderrWriter := command.Stdout, command.Stderr
+	started := time.Now()
+	defer func() {
+		RecordCommand(time.Since(started))
+	}()
+	stdout, stderr, err = getCommandRunner().Run(command)
+	// A runner which doesn't run processes returns the outputs, rather than writing them.
+	if stdoutWriter != nil && len(stdout) > 0 {
+		if _, writeErr := stdoutWriter.Write(stdout); writeErr != nil && err == nil {
+			err = writeErr
+		}
+		stdout = nil
+	}
+	if stderrWriter != nil && len(stderr) > 0 {
+		if _, writeErr := stderrWriter.Write(stderr); writeErr != nil && err == nil {
+			err = writeErr
+		}
+		stderr = nil
+	}
+	return
+}
+
+// RunCommandWithOutputParser runs the command of the config with the current CommandRunner, like gofrog's io.RunCmdWithOutputParser:
+// each line of the command's outputs is processed by the patterns, and, if prompt is true, written to the standard error.
+// Returns the processed standard output and standard error. If a pattern fails, its error is returned.
+func RunCommandWithOutputParser(config gofrogcmd.CmdConfig, prompt bool, patterns ...*gofrogcmd.CmdOutputPattern) (stdout, stderr string, err error) {
+	for key, value := range config.GetEnv() {
+		if err = os.Setenv(key, value); err != nil {
+			return
+		}
+	}
+	command := config.GetCmd()
+	stdoutParser := &outputParser{patterns: patterns, prompt: prompt}
+	stderrParser := &outputParser{patterns: patterns, prompt: prompt}
+	command.Stdout, command.Stderr = stdoutParser, stderrParser
+	_, _, err = RunCommand(command)
+	stdoutParser.flush()
+	stderrParser.flush()
+	if stdoutParser.err != nil {
+		err = stdoutParser.err
+	} else if stderrParser.err != nil {
+		err = stderrParser.err
+	}
+	return stdoutParser.output.String(), stderrParser.output.String(), err
+}
+
+// A writer which processes each line written to it by the patterns, and keeps the processed lines.
+// After the first failure of a pattern, the lines are kept without being processed.
+type outputParser struct {
+	patterns []*gofrogcmd.CmdOutputPattern
+	prompt   bool
+	// The last line written, until its end is written.
+	partialLine []byte
+	output      strings.Builder
+	err         error
+}
+
+func (op *outputParser) Write(content []byte) (int, error) {
+	op.partialLine = append(op.partialLine, content...)
+	for {
+		lineEnd := bytes.IndexByte(op.partialLine, '\n')
+		if lineEnd < 0 {
+			break
+		}
+		op.processLine(string(bytes.TrimSuffix(op.partialLine[:lineEnd], []byte{'\r'})))
+		op.partialLine = op.partialLine[lineEnd+1:]
+	}
+	return len(content), nil
+}
+
+// Processes the last line, if it doesn't end with a line break.
+func (op *outputParser) flush() {
+	if len(op.partialLine) > 0 {
+		op.processLine(string(op.partialLine))
+		op.partialLine = nil
+	}
+}
+
+func (op *outputParser) processLine(line string) {
+	for _, pattern := range op.patterns {
+		if op.err != nil || !pattern.RegExp.MatchString(line) {
+			continue
+		}
+		var err error
+		line, err = pattern.ExecFunc(&gofrogcmd.CmdOutputPattern{RegExp: pattern.RegExp, MatchedResults: pattern.RegExp.FindStringSubmatch(line), Line: line, ExecFunc: pattern.ExecFunc})
+		if err != nil {
+			op.err = err
+		}
+	}
+	if op.prompt {
+		fmt.Fprintln(os.Stderr, line)
+	}
+	op.output.WriteString(line)
+	op.output.WriteRune('\n')
+}
+
+// RunCommandOutput runs the command of the config with the current CommandRunner, like gofrog's io.RunCmdOutput,
+// and returns its standard output. The standard error is written to the config's error writer, if it has one, and otherwise added to the returned error.
+func RunCommandOutput(config gofrogcmd.CmdConfig) (string, error) {
+	for key, value := range config.GetEnv() {
+		if err := os.Setenv(key, value); err != nil {
+			return "", err
+		}
+	}
+	command := config.GetCmd()
+	if errWriter := config.GetErrWriter(); errWriter != nil {
+		command.Stderr = errWriter
+		defer func() {
+			_ = errWriter.Close()
+		}()
+	}
+	stdout, stderr, err := RunCommand(command)
+	if err != nil {
+		return "", fmt.Errorf("failed running command: '%s' with error: %w - %s", strings.Join(command.Args, " "), err, strings.TrimSpace(string(stderr)))
+	}
+	return string(stdout), nil
+}
+
+// CommandStats counts the external commands run by the collectors of the process, and their total duration.
+type CommandStats struct {
+	Count    int
+	Duration time.Duration
+}
+
+var (
+	commandStats      CommandStats
+	commandStatsMutex sync.Mutex
+)
+
+// RecordCommand adds an external command, which ran for the provided duration, to the CommandStats of the process.
+// The commands run with RunCommand are recorded automatically.
+func RecordCommand(duration time.Duration) {
+	commandStatsMutex.Lock()
+	defer commandStatsMutex.Unlock()
+	commandStats.Count++
+	commandStats.Duration += duration
+}
+
+// GetCommandStats returns the external commands run by the collectors of the process so far.
+func GetCommandStats() CommandStats {
+	commandStatsMutex.Lock()
+	defer commandStatsMutex.Unlock()
+	return commandStats
+}
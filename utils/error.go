@@ -1,7 +1,12 @@
 package utils
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
 	"strings"
 )
 
@@ -55,3 +60,93 @@ func IsForbiddenOutput(tech PackageManager, cmdOutput string) bool {
 	}
 	return false
 }
+
+// ErrorCategory classifies a failure, so that callers (and CI pipelines) can branch on the type of the failure instead of parsing logs.
+type ErrorCategory string
+
+const (
+	GeneralError   ErrorCategory = "general"
+	ToolNotFound   ErrorCategory = "tool-not-found"
+	ParseFailure   ErrorCategory = "parse-failure"
+	CacheMiss      ErrorCategory = "cache-miss"
+	Timeout        ErrorCategory = "timeout"
+	PublishFailure ErrorCategory = "publish-failure"
+	// A dependency's checksum doesn't match the hash declared in its lockfile.
+	IntegrityMismatch ErrorCategory = "integrity-mismatch"
+	// The build-info violates the rules of a policy.
+	PolicyViolation ErrorCategory = "policy-violation"
+)
+
+// Process exit codes, one per error category.
+var errorCategoryExitCodes = map[ErrorCategory]int{
+	GeneralError:      1,
+	ToolNotFound:      2,
+	ParseFailure:      3,
+	CacheMiss:         4,
+	Timeout:           5,
+	PublishFailure:    6,
+	IntegrityMismatch: 7,
+	PolicyViolation:   8,
+}
+
+// ExitCode returns the process exit code of the error category.
+func (category ErrorCategory) ExitCode() int {
+	if exitCode, ok := errorCategoryExitCodes[category]; ok {
+		return exitCode
+	}
+	return errorCategoryExitCodes[GeneralError]
+}
+
+// CategorizedError wraps an error with its ErrorCategory.
+type CategorizedError struct {
+	Category ErrorCategory
+	Err      error
+}
+
+func (err *CategorizedError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *CategorizedError) Unwrap() error {
+	return err.Err
+}
+
+// NewCategorizedError wraps err with the given category. If err is nil, nil is returned.
+func NewCategorizedError(category ErrorCategory, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CategorizedError{Category: category, Err: err}
+}
+
+// GetErrorCategory returns the category of the provided error.
+// Errors that were not explicitly categorized are classified by their underlying type, falling back to GeneralError.
+func GetErrorCategory(err error) ErrorCategory {
+	var categorizedError *CategorizedError
+	if errors.As(err, &categorizedError) {
+		return categorizedError.Category
+	}
+	var syntaxError *json.SyntaxError
+	var unmarshalTypeError *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return ToolNotFound
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		return Timeout
+	case errors.As(err, &syntaxError), errors.As(err, &unmarshalTypeError):
+		return ParseFailure
+	}
+	return GeneralError
+}
+
+// ErrorDetails is the machine-readable representation of an error, as printed with '--error-format json'.
+type ErrorDetails struct {
+	Category ErrorCategory `json:"category"`
+	ExitCode int           `json:"exitCode"`
+	Message  string        `json:"message"`
+}
+
+func NewErrorDetails(err error) *ErrorDetails {
+	category := GetErrorCategory(err)
+	return &ErrorDetails{Category: category, ExitCode: category.ExitCode(), Message: err.Error()}
+}
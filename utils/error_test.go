@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetErrorCategory(t *testing.T) {
+	_, lookPathErr := exec.LookPath("bi-test-non-existing-executable")
+	var syntaxErr *json.SyntaxError
+	assert.ErrorAs(t, json.Unmarshal([]byte("{"), &struct{}{}), &syntaxErr)
+	testCases := []struct {
+		name     string
+		err      error
+		expected ErrorCategory
+	}{
+		{"general", errors.New("error"), GeneralError},
+		{"categorized", NewCategorizedError(CacheMiss, errors.New("error")), CacheMiss},
+		{"wrapped categorized", fmt.Errorf("wrapped: %w", NewCategorizedError(PublishFailure, errors.New("error"))), PublishFailure},
+		{"tool not found", lookPathErr, ToolNotFound},
+		{"timeout", fmt.Errorf("wrapped: %w", context.DeadlineExceeded), Timeout},
+		{"parse failure", syntaxErr, ParseFailure},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, GetErrorCategory(testCase.err))
+		})
+	}
+}
+
+func TestErrorCategoryExitCode(t *testing.T) {
+	assert.Equal(t, 1, GeneralError.ExitCode())
+	assert.Equal(t, 2, ToolNotFound.ExitCode())
+	assert.Equal(t, 6, PublishFailure.ExitCode())
+	assert.Equal(t, 7, IntegrityMismatch.ExitCode())
+	assert.Equal(t, 8, PolicyViolation.ExitCode())
+	assert.Equal(t, 1, ErrorCategory("unknown").ExitCode())
+	assert.Nil(t, NewCategorizedError(Timeout, nil))
+}
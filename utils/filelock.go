@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	lockFileSuffix = ".lock"
+	// The suffix of a stale lock file which is moved aside before it's removed.
+	movedStaleLockSuffix = ".stale"
+	// The locks are only held while files are read or written, so a lock file older than this
+	// was left by a process which was killed while holding the lock, and is removed.
+	staleLockTimeout  = time.Minute
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// The time to wait for a lock which is held by another process. Longer than staleLockTimeout, so that a stale lock is always recovered before giving up.
+var lockTimeout = 2 * staleLockTimeout
+
+// LockFile acquires an advisory lock on path, which synchronizes the bi processes that run concurrently on the same machine
+// and write the same files, such as the files of a build and the caches. The file itself doesn't have to exist.
+// The lock is a file named path + ".lock", created exclusively, which works the same way on all operating systems and filesystems.
+// Waits for the lock while it's held by another process, and removes it if it's stale. The returned function releases the lock.
+func LockFile(path string) (unlock func() error, err error) {
+	lockPath := path + lockFileSuffix
+	if err = os.MkdirAll(filepath.Dir(lockPath), 0777); err != nil {
+		return nil, err
+	}
+	// The lock's owner is identified by the process ID and the acquisition time, so that a lock which was removed as stale isn't released by its former owner.
+	owner := strconv.Itoa(os.Getpid()) + ":" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		acquired, err := createLockFile(lockPath, owner)
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			return func() error {
+				return releaseLockFile(lockPath, owner)
+			}, nil
+		}
+		if err = removeStaleLockFile(lockPath); err != nil {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the lock %s, which is held by another process. If no other process is running, remove the lock file", lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// Creates the lock file, unless it already exists. Returns false if the lock is held by another process.
+func createLockFile(lockPath, owner string) (bool, error) {
+	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	_, err = lockFile.WriteString(owner)
+	if err = errors.Join(err, lockFile.Close()); err != nil {
+		return false, errors.Join(err, os.Remove(lockPath))
+	}
+	return true, nil
+}
+
+func releaseLockFile(lockPath, owner string) error {
+	content, err := os.ReadFile(lockPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if string(content) != owner {
+		// The lock was held for too long, so another process removed it as stale.
+		return nil
+	}
+	return os.Remove(lockPath)
+}
+
+// Removes the lock file if it's stale. Several processes may find the same stale lock, and one of them may remove it and acquire the lock
+// before another one removes it too. So the stale file is moved aside atomically, and it's removed only if it's still the stale lock, by its owner.
+// Otherwise, the lock acquired in the meantime was moved, and it's moved back, unless the lock was acquired again since.
+func removeStaleLockFile(lockPath string) error {
+	staleOwner, isStale, err := readStaleLockFile(lockPath)
+	if err != nil || !isStale {
+		return err
+	}
+	movedPath := lockPath + "." + strconv.Itoa(os.Getpid()) + "-" + strconv.FormatInt(time.Now().UnixNano(), 10) + movedStaleLockSuffix
+	if err = os.Rename(lockPath, movedPath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			// Another process removed the stale lock.
+			return nil
+		}
+		return err
+	}
+	movedOwner, err := os.ReadFile(movedPath)
+	if err != nil {
+		return errors.Join(err, os.Remove(movedPath))
+	}
+	if string(movedOwner) != staleOwner {
+		// A hard link doesn't replace the lock file if it exists.
+		if err = os.Link(movedPath, lockPath); err != nil && !errors.Is(err, fs.ErrExist) {
+			return errors.Join(err, os.Remove(movedPath))
+		}
+	}
+	return os.Remove(movedPath)
+}
+
+// Returns the owner of the lock file, and true if the lock is stale. The owner and the modification time are read from the same file,
+// even if the lock is released and acquired again meanwhile.
+func readStaleLockFile(lockPath string) (owner string, isStale bool, err error) {
+	// The file is opened only if it seems stale, since the lock file of a running process can't be removed while it's open on Windows.
+	lockInfo, err := os.Stat(lockPath)
+	if err != nil || time.Since(lockInfo.ModTime()) < staleLockTimeout {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	lockFile, err := os.Open(lockPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	defer func() {
+		err = errors.Join(err, lockFile.Close())
+	}()
+	if lockInfo, err = lockFile.Stat(); err != nil || time.Since(lockInfo.ModTime()) < staleLockTimeout {
+		return "", false, err
+	}
+	content, err := io.ReadAll(lockFile)
+	return string(content), err == nil, err
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "cache", "cache.json")
+	unlock, err := LockFile(filePath)
+	require.NoError(t, err)
+	assert.FileExists(t, filePath+lockFileSuffix)
+
+	// The lock is only acquired by another writer after it's released.
+	// The counter is read and written separately, so that concurrent writers would lose increments.
+	var counter atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := LockFile(filePath)
+			if !assert.NoError(t, err) {
+				return
+			}
+			value := counter.Load()
+			time.Sleep(time.Millisecond)
+			counter.Store(value + 1)
+			assert.NoError(t, unlock())
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	assert.Zero(t, counter.Load())
+	require.NoError(t, unlock())
+	wg.Wait()
+	assert.Equal(t, int32(5), counter.Load())
+	assert.NoFileExists(t, filePath+lockFileSuffix)
+}
+
+func TestLockFileStale(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "build")
+	// A lock left by a process which was killed while holding it.
+	staleTime := time.Now().Add(-2 * staleLockTimeout)
+	require.NoError(t, os.WriteFile(filePath+lockFileSuffix, []byte("1:1"), 0600))
+	require.NoError(t, os.Chtimes(filePath+lockFileSuffix, staleTime, staleTime))
+
+	unlock, err := LockFile(filePath)
+	require.NoError(t, err)
+	// The lock is held for too long, so it's removed as stale and acquired by another process.
+	require.NoError(t, os.Chtimes(filePath+lockFileSuffix, staleTime, staleTime))
+	otherUnlock, err := LockFile(filePath)
+	require.NoError(t, err)
+	// Releasing the lock which was removed doesn't release the lock of the other process.
+	require.NoError(t, unlock())
+	assert.FileExists(t, filePath+lockFileSuffix)
+	require.NoError(t, otherUnlock())
+	assert.NoFileExists(t, filePath+lockFileSuffix)
+}
+
+func TestLockFileStaleConcurrently(t *testing.T) {
+	lockDir := t.TempDir()
+	filePath := filepath.Join(lockDir, "build")
+	staleTime := time.Now().Add(-2 * staleLockTimeout)
+	require.NoError(t, os.WriteFile(filePath+lockFileSuffix, []byte("1:1"), 0600))
+	require.NoError(t, os.Chtimes(filePath+lockFileSuffix, staleTime, staleTime))
+
+	// The writers find the stale lock at the same time. Only one of them removes it, and the lock acquired by one writer isn't removed by another.
+	// The counter is read and written separately, so that concurrent writers would lose increments.
+	var counter, holders atomic.Int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			unlock, err := LockFile(filePath)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, int32(1), holders.Add(1))
+			value := counter.Load()
+			time.Sleep(time.Millisecond)
+			counter.Store(value + 1)
+			holders.Add(-1)
+			assert.NoError(t, unlock())
+		}()
+	}
+	close(start)
+	wg.Wait()
+	assert.Equal(t, int32(20), counter.Load())
+	// Neither the lock nor the moved stale lock are left.
+	entries, err := os.ReadDir(lockDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLockFileTimeout(t *testing.T) {
+	previousTimeout := lockTimeout
+	lockTimeout = 200 * time.Millisecond
+	defer func() {
+		lockTimeout = previousTimeout
+	}()
+	filePath := filepath.Join(t.TempDir(), "build")
+	unlock, err := LockFile(filePath)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, unlock())
+	}()
+	_, err = LockFile(filePath)
+	assert.ErrorContains(t, err, "timed out waiting for the lock")
+}
@@ -7,9 +7,9 @@ import (
 	"fmt"
 	"golang.org/x/exp/slices"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
-	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -19,6 +19,9 @@ import (
 )
 
 const (
+	// The environment variable which sets the directory in which the temp files and directories are created, instead of the system's temp directory.
+	TempDirEnv = "BI_TMPDIR"
+
 	tempDirPrefix = "build-info-temp-"
 
 	// Max temp file age in hours
@@ -147,6 +150,49 @@ func MoveFile(sourcePath, destPath string) (err error) {
 	return
 }
 
+// The suffix of the temporary files written by WriteFileAtomically and StreamFileAtomically, before they're renamed to their paths.
+// The readers of directories which are written concurrently should skip these files, since an interrupted write may leave them behind.
+const AtomicTempFileSuffix = ".tmp"
+
+// WriteFileAtomically writes content to the file at path, like os.WriteFile, but never leaves a partially written file at path,
+// even if the process is interrupted. See StreamFileAtomically.
+func WriteFileAtomically(path string, content []byte, perm os.FileMode) error {
+	return StreamFileAtomically(path, perm, func(writer io.Writer) error {
+		_, err := writer.Write(content)
+		return err
+	})
+}
+
+// StreamFileAtomically writes the file at path using the write function. The content is written to a temporary file in the same directory,
+// which is synced to the disk and renamed to path once it's complete, replacing the previous file atomically.
+// Readers of path therefore see either the previous content or the new one, and never a partially written file.
+func StreamFileAtomically(path string, perm os.FileMode, write func(writer io.Writer) error) (err error) {
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*"+AtomicTempFileSuffix)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, os.Remove(tempFile.Name()))
+		}
+	}()
+	if err = write(tempFile); err != nil {
+		return errors.Join(err, tempFile.Close())
+	}
+	// Flush the content to the disk before renaming, so that a crash of the machine doesn't leave an empty file at path.
+	if err = tempFile.Sync(); err != nil {
+		return errors.Join(err, tempFile.Close())
+	}
+	if err = tempFile.Close(); err != nil {
+		return err
+	}
+	// os.CreateTemp creates the file with 0600 permissions.
+	if err = os.Chmod(tempFile.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tempFile.Name(), path)
+}
+
 // Return the list of files and directories in the specified path
 func ListFiles(path string, includeDirs bool) ([]string, error) {
 	sep := GetFileSeparator()
@@ -274,9 +320,22 @@ func GetFileContentAndInfo(filePath string) (fileContent []byte, fileInfo os.Fil
 	return
 }
 
-// CreateTempDir creates a temporary directory and returns its path.
+// GetTempDirBase returns the directory in which the temp files and directories are created:
+// the directory set by the BI_TMPDIR environment variable, or the system's temp directory if it isn't set.
+func GetTempDirBase() string {
+	if tempDirBase := os.Getenv(TempDirEnv); tempDirBase != "" {
+		return tempDirBase
+	}
+	return os.TempDir()
+}
+
+// CreateTempDir creates a temporary directory in the directory returned by GetTempDirBase, and returns its path.
+// Old temporary directories, which weren't removed because their process was killed, are removed by CleanOldDirs and RemoveTempDirs.
 func CreateTempDir() (string, error) {
-	tempDirBase := os.TempDir()
+	tempDirBase := GetTempDirBase()
+	if err := os.MkdirAll(tempDirBase, 0777); err != nil {
+		return "", err
+	}
 	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
 	return os.MkdirTemp(tempDirBase, tempDirPrefix+timestamp+"-*")
 }
@@ -323,45 +382,116 @@ func removeDirContents(dirPath string) (err error) {
 }
 
 // Old runs/tests may leave junk at temp dir.
-// Each temp file/Dir is named with prefix+timestamp, search for all temp files/dirs that match the common prefix and validate their timestamp.
+// Each temp file/Dir is named with a common prefix, search for all temp files/dirs that match the prefix and validate their modification time.
 func CleanOldDirs() error {
+	_, err := RemoveTempDirs(time.Duration(maxFileAge * float64(time.Hour)))
+	return err
+}
+
+// RemoveTempDirs removes the temporary directories created by CreateTempDir which weren't modified in the last olderThan, and returns their paths.
+// A directory is removed while holding its lock, so that the directories of the running processes are kept, even if they were created long ago.
+// The lock files left by processes which were killed while holding them are removed too.
+func RemoveTempDirs(olderThan time.Duration) (removed []string, err error) {
 	// Get all files at temp dir
-	tempDirBase := os.TempDir()
+	tempDirBase := GetTempDirBase()
 	files, err := os.ReadDir(tempDirBase)
 	if err != nil {
-		return err
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
 	}
-	now := time.Now()
-	// Search for files/dirs that match the template.
+	// Search for dirs that match the template.
 	for _, file := range files {
-		if strings.HasPrefix(file.Name(), tempDirPrefix) {
-			timeStamp, err := extractTimestamp(file.Name())
-			if err != nil {
-				return err
-			}
-			// Delete old file/dirs.
-			if now.Sub(timeStamp).Hours() > maxFileAge {
-				if err := os.RemoveAll(path.Join(tempDirBase, file.Name())); err != nil {
-					return err
-				}
-			}
+		if !file.IsDir() || !strings.HasPrefix(file.Name(), tempDirPrefix) {
+			continue
+		}
+		tempDirPath := filepath.Join(tempDirBase, file.Name())
+		var isRemoved bool
+		if isRemoved, err = RemoveDirIfOlder(tempDirPath, olderThan); err != nil {
+			return
+		}
+		if isRemoved {
+			removed = append(removed, tempDirPath)
 		}
 	}
-	return nil
+	return removed, RemoveOrphanLockFiles(tempDirBase, olderThan)
 }
 
-func extractTimestamp(item string) (time.Time, error) {
-	// Get timestamp from file/dir.
-	endTimestampIndex := strings.LastIndex(item, "-")
-	beginningTimestampIndex := strings.LastIndex(item[:endTimestampIndex], "-")
-	timestampStr := item[beginningTimestampIndex+1 : endTimestampIndex]
-	// Convert to int.
-	timestampInt, err := strconv.ParseInt(timestampStr, 10, 64)
+// RemoveDirIfOlder removes the directory, while holding its lock, if none of its files were modified in the last olderThan.
+func RemoveDirIfOlder(dirPath string, olderThan time.Duration) (removed bool, err error) {
+	unlock, err := LockFile(dirPath)
 	if err != nil {
-		return time.Time{}, err
+		return false, err
+	}
+	defer func() {
+		err = errors.Join(err, unlock())
+	}()
+	lastModified, err := GetLastModified(dirPath)
+	if err != nil || time.Since(lastModified) <= olderThan {
+		return false, err
+	}
+	return true, os.RemoveAll(dirPath)
+}
+
+// GetLastModified returns the latest modification time of the directory and the files in it, recursively.
+func GetLastModified(dirPath string) (lastModified time.Time, err error) {
+	err = filepath.WalkDir(dirPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(lastModified) {
+			lastModified = info.ModTime()
+		}
+		return nil
+	})
+	return
+}
+
+// RemoveOrphanLockFiles removes the lock files in the directory, which weren't modified in the last olderThan, of files which no longer exist.
+// Such lock files are left by processes which were killed while holding the locks of files they were removing,
+// or while removing stale locks.
+func RemoveOrphanLockFiles(dirPath string, olderThan time.Duration) error {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
 	}
-	// Convert to time type.
-	return time.Unix(timestampInt, 0), nil
+	for _, entry := range entries {
+		isMovedStaleLock := strings.HasSuffix(entry.Name(), movedStaleLockSuffix) && strings.Contains(entry.Name(), lockFileSuffix+".")
+		if entry.IsDir() || (!strings.HasSuffix(entry.Name(), lockFileSuffix) && !isMovedStaleLock) {
+			continue
+		}
+		lockPath := filepath.Join(dirPath, entry.Name())
+		if !isMovedStaleLock {
+			// The lock of an existing file is removed by LockFile when it's stale.
+			if _, err = os.Lstat(strings.TrimSuffix(lockPath, lockFileSuffix)); err == nil {
+				continue
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if time.Since(info.ModTime()) <= olderThan || time.Since(info.ModTime()) < staleLockTimeout {
+			continue
+		}
+		if err = os.Remove(lockPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
 }
 
 // FindFileInDirAndParents looks for a file named fileName in dirPath and its parents, and returns the path of the directory where it was found.
@@ -1,12 +1,18 @@
 package utils
 
 import (
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFindFileInDirAndParents(t *testing.T) {
@@ -63,10 +69,79 @@ func TestCreateTempDir(t *testing.T) {
 	assert.NotErrorIs(t, err, os.ErrNotExist)
 
 	defer func() {
-		// Check that a timestamp can be extracted from the temp dir name
-		_, err = extractTimestamp(tempDir)
-		assert.NoError(t, err)
-
+		assert.True(t, strings.HasPrefix(filepath.Base(tempDir), tempDirPrefix))
 		assert.NoError(t, os.RemoveAll(tempDir))
 	}()
 }
+
+func TestCreateTempDirInCustomTempDir(t *testing.T) {
+	tempDirBase := filepath.Join(t.TempDir(), "bi-tmp")
+	t.Setenv(TempDirEnv, tempDirBase)
+	assert.Equal(t, tempDirBase, GetTempDirBase())
+
+	// The directory of BI_TMPDIR is created if it doesn't exist.
+	tempDir, err := CreateTempDir()
+	require.NoError(t, err)
+	assert.Equal(t, tempDirBase, filepath.Dir(tempDir))
+	assert.DirExists(t, tempDir)
+
+	// Only the temporary directories which weren't modified in the provided age are removed, regardless of the time in their names.
+	twoHoursAgo := time.Now().Add(-2 * time.Hour)
+	oldTempDir := filepath.Join(tempDirBase, tempDirPrefix+strconv.FormatInt(time.Now().Unix(), 10)+"-1234")
+	require.NoError(t, os.Mkdir(oldTempDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(oldTempDir, "file"), nil, 0644))
+	require.NoError(t, os.Chtimes(filepath.Join(oldTempDir, "file"), twoHoursAgo, twoHoursAgo))
+	require.NoError(t, os.Chtimes(oldTempDir, twoHoursAgo, twoHoursAgo))
+	// A directory with an old name, which is still used by a long-running process.
+	usedTempDir := filepath.Join(tempDirBase, tempDirPrefix+strconv.FormatInt(twoHoursAgo.Unix(), 10)+"-5678")
+	require.NoError(t, os.Mkdir(usedTempDir, 0755))
+	// The lock file of a directory which was removed by a process that was killed before releasing the lock.
+	orphanLockFile := filepath.Join(tempDirBase, tempDirPrefix+"1-9012"+lockFileSuffix)
+	require.NoError(t, os.WriteFile(orphanLockFile, nil, 0600))
+	require.NoError(t, os.Chtimes(orphanLockFile, twoHoursAgo, twoHoursAgo))
+	// A stale lock which was moved aside by a process that was killed before removing it.
+	movedStaleLockFile := usedTempDir + lockFileSuffix + ".1234-1" + movedStaleLockSuffix
+	require.NoError(t, os.WriteFile(movedStaleLockFile, nil, 0600))
+	require.NoError(t, os.Chtimes(movedStaleLockFile, twoHoursAgo, twoHoursAgo))
+	removed, err := RemoveTempDirs(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{oldTempDir}, removed)
+	assert.NoDirExists(t, oldTempDir)
+	assert.NoFileExists(t, oldTempDir+lockFileSuffix)
+	assert.NoFileExists(t, orphanLockFile)
+	assert.NoFileExists(t, movedStaleLockFile)
+	assert.DirExists(t, usedTempDir)
+	assert.DirExists(t, tempDir)
+
+	t.Setenv(TempDirEnv, filepath.Join(tempDirBase, "missing"))
+	removed, err = RemoveTempDirs(time.Hour)
+	assert.NoError(t, err)
+	assert.Empty(t, removed)
+}
+
+func TestWriteFileAtomically(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "cache.json")
+	require.NoError(t, WriteFileAtomically(filePath, []byte(`{"version":1}`), 0644))
+	require.NoError(t, WriteFileAtomically(filePath, []byte(`{"version":2}`), 0644))
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, `{"version":2}`, string(content))
+	if runtime.GOOS != "windows" {
+		fileInfo, err := os.Stat(filePath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0644), fileInfo.Mode().Perm())
+	}
+
+	// A failed write keeps the previous content, and doesn't leave the temporary file.
+	err = StreamFileAtomically(filePath, 0644, func(writer io.Writer) error {
+		_, err := writer.Write([]byte(`{"vers`))
+		return errors.Join(err, errors.New("interrupted"))
+	})
+	assert.ErrorContains(t, err, "interrupted")
+	content, err = os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, `{"version":2}`, string(content))
+	files, err := ListFiles(filepath.Dir(filePath), false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filePath}, files)
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The GOPROXY entry which stands for fetching the modules directly from their version control repositories.
+const goProxyDirect = "direct"
+
+// GoProxySettings holds the module proxy configuration of the go command.
+type GoProxySettings struct {
+	// The entries of GOPROXY, in the order in which the go command tries them, without their credentials.
+	// The "direct" entry stands for fetching the modules directly from their version control repositories.
+	Proxies []string
+	// Comma-separated glob patterns of module path prefixes, which the go command fetches directly.
+	NoProxyPatterns string
+	// The directory in which the go command caches the downloaded modules.
+	downloadCacheDir string
+}
+
+// GetGoProxySettings reads the module proxy configuration of the go command in the project directory.
+func GetGoProxySettings(projectDir string) (*GoProxySettings, error) {
+	goEnv, err := readGoEnv(projectDir, "GOPROXY", "GONOPROXY", "GOPRIVATE", "GOMODCACHE")
+	if err != nil {
+		return nil, err
+	}
+	return newGoProxySettings(goEnv), nil
+}
+
+func newGoProxySettings(goEnv map[string]string) *GoProxySettings {
+	settings := &GoProxySettings{NoProxyPatterns: goEnv["GONOPROXY"]}
+	if settings.NoProxyPatterns == "" {
+		settings.NoProxyPatterns = goEnv["GOPRIVATE"]
+	}
+	// The entries are separated by commas or pipes. The go command never tries the entries which follow "off".
+	for _, proxy := range strings.FieldsFunc(goEnv["GOPROXY"], func(r rune) bool { return r == ',' || r == '|' }) {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "off" {
+			break
+		}
+		if proxy != "" {
+			settings.Proxies = append(settings.Proxies, removeUrlCredentials(proxy))
+		}
+	}
+	if goEnv["GOMODCACHE"] != "" {
+		settings.downloadCacheDir = filepath.Join(goEnv["GOMODCACHE"], "cache", "download")
+	}
+	return settings
+}
+
+// GetModuleSource returns the URL of the proxy from which the go command downloaded a module,
+// or the URL of the module's version control repository if it was fetched directly.
+// The module cache doesn't record which proxy served a module, so when GOPROXY lists several proxies, the module is attributed to the first one.
+// The repository of a module which was fetched directly is read from the origin which the go command records in the module's .info file.
+// Returns an empty string if the source is unknown.
+func (s *GoProxySettings) GetModuleSource(modulePath, version string) string {
+	if !matchGoPrefixPatterns(s.NoProxyPatterns, modulePath) {
+		if len(s.Proxies) == 0 {
+			return ""
+		}
+		if s.Proxies[0] != goProxyDirect {
+			return s.Proxies[0]
+		}
+	}
+	if s.downloadCacheDir == "" {
+		return ""
+	}
+	content, err := os.ReadFile(filepath.Join(s.downloadCacheDir, escapeGoModulePath(modulePath), "@v", escapeGoModulePath(version)+".info"))
+	if err != nil {
+		return ""
+	}
+	var moduleInfo struct {
+		Origin *struct {
+			URL string `json:"URL"`
+		} `json:"Origin"`
+	}
+	if err = json.Unmarshal(content, &moduleInfo); err != nil || moduleInfo.Origin == nil {
+		return ""
+	}
+	return removeUrlCredentials(moduleInfo.Origin.URL)
+}
+
+// Removes the user and password from the URL, if it has any.
+func removeUrlCredentials(rawUrl string) string {
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil || parsedUrl.User == nil {
+		return rawUrl
+	}
+	parsedUrl.User = nil
+	return parsedUrl.String()
+}
@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jfrog/build-info-go/entities"
+)
+
+// GoSumDbSettings holds the checksum database configuration of the go command.
+type GoSumDbSettings struct {
+	// The name of the checksum database, for example: sum.golang.org.
+	Name string
+	// The setting which disables the verification of all modules, for example: GOSUMDB=off.
+	// Empty if the verification is enabled.
+	DisabledBy string
+	// Comma-separated glob patterns of module path prefixes, which the go command doesn't verify.
+	NoSumDbPatterns string
+	// The directory in which the go command caches the checksum database lookups.
+	lookupCacheDir string
+}
+
+// GetGoSumDbSettings reads the checksum database configuration of the go command in the project directory.
+func GetGoSumDbSettings(projectDir string) (*GoSumDbSettings, error) {
+	goEnv, err := readGoEnv(projectDir, "GOSUMDB", "GONOSUMDB", "GOPRIVATE", "GOFLAGS", "GOMODCACHE")
+	if err != nil {
+		return nil, err
+	}
+	return newGoSumDbSettings(goEnv, os.Getenv("GONOSUMCHECK")), nil
+}
+
+// Returns the values of the go command's environment variables in the project directory, by their names.
+func readGoEnv(projectDir string, names ...string) (map[string]string, error) {
+	goEnvCmd := exec.Command("go", append([]string{"env", "-json"}, names...)...)
+	goEnvCmd.Dir = projectDir
+	output, _, err := RunCommand(goEnvCmd)
+	if err != nil {
+		return nil, err
+	}
+	goEnv := make(map[string]string)
+	return goEnv, json.Unmarshal(output, &goEnv)
+}
+
+func newGoSumDbSettings(goEnv map[string]string, noSumCheck string) *GoSumDbSettings {
+	// GOSUMDB has the syntax: <name>[+<public key>] [<URL>]
+	name, _, _ := strings.Cut(strings.TrimSpace(goEnv["GOSUMDB"]), " ")
+	name, _, _ = strings.Cut(name, "+")
+	settings := &GoSumDbSettings{Name: name, NoSumDbPatterns: goEnv["GONOSUMDB"]}
+	if settings.NoSumDbPatterns == "" {
+		settings.NoSumDbPatterns = goEnv["GOPRIVATE"]
+	}
+	switch {
+	case name == "off":
+		settings.DisabledBy = "GOSUMDB=off"
+	case noSumCheck == "1":
+		settings.DisabledBy = "GONOSUMCHECK=1"
+	default:
+		for _, goFlag := range strings.Fields(goEnv["GOFLAGS"]) {
+			if goFlag == "-insecure" || goFlag == "-insecure=true" {
+				settings.DisabledBy = "GOFLAGS=" + goFlag
+			}
+		}
+	}
+	if settings.DisabledBy == "" && goEnv["GOMODCACHE"] != "" {
+		settings.lookupCacheDir = filepath.Join(goEnv["GOMODCACHE"], "cache", "download", "sumdb", name, "lookup")
+	}
+	return settings
+}
+
+// GetModuleIntegrity returns the integrity details of a module, by comparing its go.sum hash with the checksum database record
+// cached by the go command when it looked up the module.
+// goSumHash is the module's hash in go.sum, or an empty string if it's missing.
+func (s *GoSumDbSettings) GetModuleIntegrity(modulePath, version, goSumHash string) entities.GoModuleIntegrity {
+	integrity := entities.GoModuleIntegrity{GoSumHash: goSumHash, SumDbStatus: entities.GoSumDbUnverified}
+	switch {
+	case s.DisabledBy != "":
+		integrity.SumDbStatus = entities.GoSumDbDisabled
+		return integrity
+	case matchGoPrefixPatterns(s.NoSumDbPatterns, modulePath):
+		integrity.SumDbStatus = entities.GoSumDbExcluded
+		return integrity
+	case goSumHash == "" || s.lookupCacheDir == "":
+		return integrity
+	}
+	recordId, sumDbHash := s.readCachedLookup(modulePath, version)
+	if sumDbHash == "" {
+		return integrity
+	}
+	integrity.SumDbName = s.Name
+	integrity.SumDbRecordId = recordId
+	if sumDbHash == goSumHash {
+		integrity.SumDbStatus = entities.GoSumDbVerified
+	} else {
+		integrity.SumDbStatus = entities.GoSumDbMismatch
+	}
+	return integrity
+}
+
+// Returns the record ID and the module's content hash from the cached lookup of the module.
+// A lookup starts with the record ID, followed by the module's go.sum lines and the signed tree head.
+// Returns an empty hash if the lookup isn't cached.
+func (s *GoSumDbSettings) readCachedLookup(modulePath, version string) (recordId int64, hash string) {
+	content, err := os.ReadFile(filepath.Join(s.lookupCacheDir, escapeGoModulePath(modulePath)+"@"+escapeGoModulePath(version)))
+	if err != nil {
+		return 0, ""
+	}
+	lines := strings.Split(string(content), "\n")
+	if recordId, err = strconv.ParseInt(strings.TrimSpace(lines[0]), 10, 64); err != nil {
+		return 0, ""
+	}
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == modulePath && fields[1] == version {
+			return recordId, fields[2]
+		}
+	}
+	return 0, ""
+}
+
+// Returns true if any of the comma-separated glob patterns matches a prefix of the module path, the same way the go command matches GONOSUMDB.
+// Each pattern is matched against the path's prefix with the same number of elements as the pattern.
+func matchGoPrefixPatterns(globs, modulePath string) bool {
+	for _, glob := range strings.Split(globs, ",") {
+		glob = strings.TrimSpace(glob)
+		if glob == "" {
+			continue
+		}
+		elements := strings.Count(glob, "/") + 1
+		prefixElements := strings.SplitN(modulePath, "/", elements+1)
+		if len(prefixElements) < elements {
+			continue
+		}
+		if matched, _ := path.Match(glob, strings.Join(prefixElements[:elements], "/")); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// In the module cache, capital letters are escaped with "!" followed by the lowercase letter.
+func escapeGoModulePath(modulePath string) string {
+	var escaped strings.Builder
+	for _, letter := range modulePath {
+		if unicode.IsUpper(letter) {
+			escaped.WriteRune('!')
+			escaped.WriteRune(unicode.ToLower(letter))
+		} else {
+			escaped.WriteRune(letter)
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGoSumDbSettings(t *testing.T) {
+	testCases := []struct {
+		name               string
+		goEnv              map[string]string
+		noSumCheck         string
+		expectedName       string
+		expectedDisabledBy string
+		expectedPatterns   string
+	}{
+		{"default", map[string]string{"GOSUMDB": "sum.golang.org"}, "", "sum.golang.org", "", ""},
+		{"custom key and url", map[string]string{"GOSUMDB": "sum.example.com+abcd https://sum.example.com"}, "", "sum.example.com", "", ""},
+		{"gosumdb off", map[string]string{"GOSUMDB": "off"}, "", "off", "GOSUMDB=off", ""},
+		{"gonosumcheck", map[string]string{"GOSUMDB": "sum.golang.org"}, "1", "sum.golang.org", "GONOSUMCHECK=1", ""},
+		{"insecure goflag", map[string]string{"GOSUMDB": "sum.golang.org", "GOFLAGS": "-mod=mod -insecure"}, "", "sum.golang.org", "GOFLAGS=-insecure", ""},
+		{"goprivate", map[string]string{"GOSUMDB": "sum.golang.org", "GOPRIVATE": "github.com/jfrog"}, "", "sum.golang.org", "", "github.com/jfrog"},
+		{"gonosumdb overrides goprivate", map[string]string{"GOSUMDB": "sum.golang.org", "GOPRIVATE": "github.com/jfrog", "GONOSUMDB": "example.com"}, "", "sum.golang.org", "", "example.com"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			settings := newGoSumDbSettings(testCase.goEnv, testCase.noSumCheck)
+			assert.Equal(t, testCase.expectedName, settings.Name)
+			assert.Equal(t, testCase.expectedDisabledBy, settings.DisabledBy)
+			assert.Equal(t, testCase.expectedPatterns, settings.NoSumDbPatterns)
+		})
+	}
+}
+
+func TestMatchGoPrefixPatterns(t *testing.T) {
+	assert.True(t, matchGoPrefixPatterns("github.com/jfrog", "github.com/jfrog/gofrog"))
+	assert.True(t, matchGoPrefixPatterns("example.com, *.corp.example.com", "git.corp.example.com/team/repo"))
+	assert.True(t, matchGoPrefixPatterns("github.com/*/gofrog", "github.com/jfrog/gofrog/io"))
+	assert.False(t, matchGoPrefixPatterns("github.com/jfrog", "github.com/jfrogdev/gofrog"))
+	assert.False(t, matchGoPrefixPatterns("github.com/jfrog/gofrog", "github.com/jfrog"))
+	assert.False(t, matchGoPrefixPatterns("", "github.com/jfrog/gofrog"))
+}
+
+func TestGetModuleIntegrity(t *testing.T) {
+	goModCache := t.TempDir()
+	lookupPath := filepath.Join(goModCache, "cache", "download", "sumdb", "sum.golang.org", "lookup", "github.com", "!burnt!sushi", "toml@v1.3.2")
+	require.NoError(t, os.MkdirAll(filepath.Dir(lookupPath), 0755))
+	lookup := `18577216
+github.com/BurntSushi/toml v1.3.2 h1:o7IhLm0Msx3BaB+n3Ag7L8EVlByGnpq14C4YWiu/gL8=
+github.com/BurntSushi/toml v1.3.2/go.mod h1:CxXYINrC8qIiEnFrOxCa7Jy5BFHlXnUU2pbicEuybxQ=
+
+go.sum database tree
+20000000
+9khnbDVfbgGP7r1oR494c1ah2D4tdsCywNQB1R5ORo8=
+`
+	require.NoError(t, os.WriteFile(lookupPath, []byte(lookup), 0644))
+	settings := newGoSumDbSettings(map[string]string{"GOSUMDB": "sum.golang.org", "GOPRIVATE": "github.com/jfrog", "GOMODCACHE": goModCache}, "")
+
+	integrity := settings.GetModuleIntegrity("github.com/BurntSushi/toml", "v1.3.2", "h1:o7IhLm0Msx3BaB+n3Ag7L8EVlByGnpq14C4YWiu/gL8=")
+	assert.Equal(t, entities.GoModuleIntegrity{
+		GoSumHash:     "h1:o7IhLm0Msx3BaB+n3Ag7L8EVlByGnpq14C4YWiu/gL8=",
+		SumDbStatus:   entities.GoSumDbVerified,
+		SumDbName:     "sum.golang.org",
+		SumDbRecordId: 18577216,
+	}, integrity)
+
+	integrity = settings.GetModuleIntegrity("github.com/BurntSushi/toml", "v1.3.2", "h1:tampered=")
+	assert.Equal(t, entities.GoSumDbMismatch, integrity.SumDbStatus)
+
+	integrity = settings.GetModuleIntegrity("github.com/pkg/errors", "v0.9.1", "h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4=")
+	assert.Equal(t, entities.GoModuleIntegrity{GoSumHash: "h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4=", SumDbStatus: entities.GoSumDbUnverified}, integrity)
+
+	integrity = settings.GetModuleIntegrity("github.com/jfrog/gofrog", "v1.7.6", "h1:QmfAiRzVyaI7JYGsB7cxfAJePAZTzFz0gRWZSE27c6s=")
+	assert.Equal(t, entities.GoSumDbExcluded, integrity.SumDbStatus)
+
+	settings = newGoSumDbSettings(map[string]string{"GOSUMDB": "off", "GOMODCACHE": goModCache}, "")
+	integrity = settings.GetModuleIntegrity("github.com/BurntSushi/toml", "v1.3.2", "h1:o7IhLm0Msx3BaB+n3Ag7L8EVlByGnpq14C4YWiu/gL8=")
+	assert.Equal(t, entities.GoSumDbDisabled, integrity.SumDbStatus)
+}
@@ -49,9 +49,9 @@ func RunGo(goArg []string, repoUrl string) error {
 	}
 	var errorOut string
 	if performPasswordMask {
-		_, errorOut, _, err = gofrogcmd.RunCmdWithOutputParser(goCmd, true, protocolRegExp)
+		_, errorOut, err = RunCommandWithOutputParser(goCmd, true, protocolRegExp)
 	} else {
-		_, errorOut, _, err = gofrogcmd.RunCmdWithOutputParser(goCmd, true)
+		_, errorOut, err = RunCommandWithOutputParser(goCmd, true)
 	}
 	if err != nil {
 		return fmt.Errorf("failed running 'go %s' command with error: '%s - %s'", strings.Join(goArg, " "), err.Error(), errorOut)
@@ -166,9 +166,9 @@ func runDependenciesCmd(projectDir string, commandArgs []string, log Log) (outpu
 	var executionError error
 	var errorOut string
 	if performPasswordMask {
-		output, errorOut, _, executionError = gofrogcmd.RunCmdWithOutputParser(goCmd, false, protocolRegExp)
+		output, errorOut, executionError = RunCommandWithOutputParser(goCmd, false, protocolRegExp)
 	} else {
-		output, errorOut, _, executionError = gofrogcmd.RunCmdWithOutputParser(goCmd, false)
+		output, errorOut, executionError = RunCommandWithOutputParser(goCmd, false)
 	}
 	if len(output) != 0 {
 		log.Debug(output)
@@ -235,7 +235,7 @@ func GetParsedGoVersion() (*version.Version, error) {
 
 func getGoVersion() (string, error) {
 	goCmd := io.NewCommand("go", "version", nil)
-	output, err := gofrogcmd.RunCmdOutput(goCmd)
+	output, err := RunCommandOutput(goCmd)
 	return output, err
 }
 
@@ -287,7 +287,7 @@ func GetGoModCachePath() (string, error) {
 // GetGOPATH returns the location of the GOPATH
 func getGOPATH() (string, error) {
 	goCmd := io.NewCommand("go", "env", []string{"GOPATH"})
-	output, err := gofrogcmd.RunCmdOutput(goCmd)
+	output, err := RunCommandOutput(goCmd)
 	if err != nil {
 		return "", fmt.Errorf("could not find GOPATH env: %s", err.Error())
 	}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// IntegrityVerificationMode determines how a mismatch between a dependency's computed checksum and the hash declared in its lockfile is handled.
+type IntegrityVerificationMode string
+
+const (
+	// The checksums aren't verified.
+	IntegrityVerificationOff IntegrityVerificationMode = ""
+	// A warning is logged for each mismatch.
+	IntegrityVerificationWarn IntegrityVerificationMode = "warn"
+	// The collection fails if there's at least one mismatch.
+	IntegrityVerificationFail IntegrityVerificationMode = "fail"
+)
+
+// ParseIntegrityVerificationMode returns the IntegrityVerificationMode matching the provided value.
+func ParseIntegrityVerificationMode(value string) (IntegrityVerificationMode, error) {
+	switch mode := IntegrityVerificationMode(value); mode {
+	case IntegrityVerificationOff, IntegrityVerificationWarn, IntegrityVerificationFail:
+		return mode, nil
+	}
+	return "", fmt.Errorf("'%s' is not a valid integrity verification mode. Supported values are '%s' and '%s'", value, IntegrityVerificationWarn, IntegrityVerificationFail)
+}
+
+// IntegrityMismatchDetails describes a dependency whose computed checksum differs from the hash declared in its lockfile.
+type IntegrityMismatchDetails struct {
+	DependencyId string
+	Lockfile     string
+	Expected     string
+	Actual       string
+}
+
+func (imd IntegrityMismatchDetails) String() string {
+	return fmt.Sprintf("%s: %s declares '%s', but the computed hash is '%s'", imd.DependencyId, imd.Lockfile, imd.Expected, imd.Actual)
+}
+
+// HandleMismatches logs a warning for each of the mismatches, or returns an error if the mode is IntegrityVerificationFail.
+func (mode IntegrityVerificationMode) HandleMismatches(mismatches []IntegrityMismatchDetails, log Log) error {
+	if mode == IntegrityVerificationOff || len(mismatches) == 0 {
+		return nil
+	}
+	var descriptions []string
+	for _, mismatch := range mismatches {
+		descriptions = append(descriptions, mismatch.String())
+	}
+	message := "The checksums of the following dependencies don't match the hashes declared in their lockfiles. This may indicate a poisoned cache:\n" + strings.Join(descriptions, "\n")
+	if mode == IntegrityVerificationFail {
+		return NewCategorizedError(IntegrityMismatch, errors.New(message))
+	}
+	log.Warn(message)
+	return nil
+}
+
+// ReadGoSumHashes reads the go.sum file in the project directory, and returns the hashes of the modules' content,
+// mapped by the module path and version, for example: 'github.com/jfrog/gofrog:v1.7.6'.
+// The hashes of the go.mod files are not returned. If go.sum doesn't exist, an empty map is returned.
+func ReadGoSumHashes(projectDir string) (map[string]string, error) {
+	hashes := make(map[string]string)
+	content, err := os.ReadFile(filepath.Join(projectDir, "go.sum"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return hashes, nil
+		}
+		return nil, err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		// The expected syntax: <module> <version>[/go.mod] <hash>
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		hashes[fields[0]+":"+fields[1]] = fields[2]
+	}
+	return hashes, nil
+}
+
+// GetGoModuleZipHash returns the 'h1:' hash of a module zip file in the module cache.
+// When the go command downloads a zip, it writes the zip's hash to the .ziphash file next to it, and verifies go.sum against that file.
+// The hash is read from this file, like the go command does, and the zip is only hashed if the file is missing.
+func GetGoModuleZipHash(zipPath string) (string, error) {
+	content, err := os.ReadFile(strings.TrimSuffix(zipPath, ".zip") + ".ziphash")
+	if hash := strings.TrimSpace(string(content)); err == nil && strings.HasPrefix(hash, "h1:") {
+		return hash, nil
+	}
+	return HashGoModuleZip(zipPath)
+}
+
+// HashGoModuleZip calculates the 'h1:' hash of a module zip file, as recorded in go.sum.
+// The hash is the base64-encoded SHA-256 of a summary, which lists the SHA-256 and the name of each file in the zip, sorted by the names.
+func HashGoModuleZip(zipPath string) (hash string, err error) {
+	zipReader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		err = errors.Join(err, zipReader.Close())
+	}()
+	files := make(map[string]*zip.File)
+	var names []string
+	for _, file := range zipReader.File {
+		if strings.Contains(file.Name, "\n") {
+			return "", errors.New("the file name '" + file.Name + "' in " + zipPath + " contains a newline")
+		}
+		files[file.Name] = file
+		names = append(names, file.Name)
+	}
+	sort.Strings(names)
+	summary := sha256.New()
+	for _, name := range names {
+		fileHash, err := hashZipFile(files[name])
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(summary, "%x  %s\n", fileHash, name)
+	}
+	return "h1:" + base64.StdEncoding.EncodeToString(summary.Sum(nil)), nil
+}
+
+func hashZipFile(file *zip.File) (fileHash []byte, err error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, reader.Close())
+	}()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, reader); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
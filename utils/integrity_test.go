@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIntegrityVerificationMode(t *testing.T) {
+	for _, value := range []string{"", "warn", "fail"} {
+		mode, err := ParseIntegrityVerificationMode(value)
+		assert.NoError(t, err)
+		assert.Equal(t, IntegrityVerificationMode(value), mode)
+	}
+	_, err := ParseIntegrityVerificationMode("ignore")
+	assert.Error(t, err)
+}
+
+func TestHandleMismatches(t *testing.T) {
+	mismatches := []IntegrityMismatchDetails{{DependencyId: "github.com/jfrog/gofrog:v1.7.6", Lockfile: "go.sum", Expected: "h1:expected=", Actual: "h1:actual="}}
+	assert.NoError(t, IntegrityVerificationOff.HandleMismatches(mismatches, NewDefaultLogger(ERROR)))
+	assert.NoError(t, IntegrityVerificationWarn.HandleMismatches(mismatches, NewDefaultLogger(ERROR)))
+	assert.NoError(t, IntegrityVerificationFail.HandleMismatches(nil, NewDefaultLogger(ERROR)))
+
+	err := IntegrityVerificationFail.HandleMismatches(mismatches, NewDefaultLogger(ERROR))
+	var categorizedErr *CategorizedError
+	require.True(t, errors.As(err, &categorizedErr))
+	assert.Equal(t, IntegrityMismatch, categorizedErr.Category)
+	assert.Contains(t, err.Error(), "github.com/jfrog/gofrog:v1.7.6: go.sum declares 'h1:expected=', but the computed hash is 'h1:actual='")
+}
+
+func TestReadGoSumHashes(t *testing.T) {
+	projectDir := t.TempDir()
+	goSum := `github.com/jfrog/gofrog v1.7.6 h1:QmfAiRzVyaI7JYGsB7cxfAJePAZTzFz0gRWZSE27c6s=
+github.com/jfrog/gofrog v1.7.6/go.mod h1:ntr1txqNOZtHplmaNd7rS4f8jpA5Apx8em70oYEe7+4=
+golang.org/x/exp v0.0.0-20240506185415-9bf2ced13842/go.mod h1:XtvwrStGgCCjAOcKaR2Aa8MVPXd7dVzrTnE7sUPWmXU=
+`
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "go.sum"), []byte(goSum), 0644))
+	hashes, err := ReadGoSumHashes(projectDir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"github.com/jfrog/gofrog:v1.7.6": "h1:QmfAiRzVyaI7JYGsB7cxfAJePAZTzFz0gRWZSE27c6s="}, hashes)
+
+	// A project without go.sum.
+	hashes, err = ReadGoSumHashes(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, hashes)
+}
+
+func TestHashGoModuleZip(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "v1.0.0.zip")
+	zipFile, err := os.Create(zipPath)
+	require.NoError(t, err)
+	zipWriter := zip.NewWriter(zipFile)
+	// The files are written in a different order than their sorted names, to check that the hash doesn't depend on it.
+	for _, file := range []struct{ name, content string }{
+		{"example.com/m@v1.0.0/m.go", "package m\n"},
+		{"example.com/m@v1.0.0/go.mod", "module example.com/m\n"},
+	} {
+		writer, err := zipWriter.Create(file.name)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(file.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	require.NoError(t, zipFile.Close())
+
+	hash, err := HashGoModuleZip(zipPath)
+	require.NoError(t, err)
+	assert.Equal(t, "h1:fCHMqo5ggHEQvwcrsN81zr5orRk5lClR36KRHpfUjKg=", hash)
+
+	// Without a .ziphash file, the zip is hashed.
+	hash, err = GetGoModuleZipHash(zipPath)
+	require.NoError(t, err)
+	assert.Equal(t, "h1:fCHMqo5ggHEQvwcrsN81zr5orRk5lClR36KRHpfUjKg=", hash)
+	// The hash written by the go command when it downloaded the zip is preferred.
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(zipPath), "v1.0.0.ziphash"), []byte("h1:ZipHashFromTheGoCommand=\n"), 0644))
+	hash, err = GetGoModuleZipHash(zipPath)
+	require.NoError(t, err)
+	assert.Equal(t, "h1:ZipHashFromTheGoCommand=", hash)
+}
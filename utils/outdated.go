@@ -0,0 +1,296 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gofrog/parallel"
+	"github.com/jfrog/gofrog/version"
+	"golang.org/x/exp/slices"
+)
+
+// The ecosystems whose dependencies can be checked for newer versions.
+const (
+	NpmEcosystem   = "npm"
+	MavenEcosystem = "maven"
+	PypiEcosystem  = "pypi"
+)
+
+// RegistryEndpoints holds the base URLs of the registries queried for the latest versions of the dependencies.
+type RegistryEndpoints struct {
+	// An npm registry, for example: https://registry.npmjs.org
+	Npm string
+	// A Maven repository, for example: https://repo.maven.apache.org/maven2
+	Maven string
+	// The JSON API of a PyPI index, for example: https://pypi.org/pypi
+	Pypi string
+}
+
+// DefaultRegistryEndpoints returns the endpoints of the public registries.
+func DefaultRegistryEndpoints() RegistryEndpoints {
+	return RegistryEndpoints{
+		Npm:   "https://registry.npmjs.org",
+		Maven: "https://repo.maven.apache.org/maven2",
+		Pypi:  "https://pypi.org/pypi",
+	}
+}
+
+// OutdatedDependency is a dependency of a build-info, which has a newer version in its registry.
+type OutdatedDependency struct {
+	Ecosystem      string `json:"ecosystem"`
+	Name           string `json:"name"`
+	CurrentVersion string `json:"currentVersion"`
+	LatestVersion  string `json:"latestVersion"`
+	// The IDs of the modules which depend on the current version.
+	Modules []string `json:"modules"`
+}
+
+// A package whose latest version is looked up.
+type registryPackage struct {
+	ecosystem, name string
+}
+
+// A dependency of the build-info, and the modules which depend on it.
+type collectedDependency struct {
+	registryPackage
+	version string
+	modules []string
+}
+
+// FindOutdatedDependencies queries the registries for the latest versions of the npm, Maven (and Gradle) and Python dependencies of the build-info,
+// and returns the dependencies which have newer versions, sorted by their ecosystems and names.
+// The dependencies of other module types are skipped. A dependency whose latest version can't be found, for example
+// because it isn't published to the registry, is skipped with a warning.
+// threads - The number of registry requests to send in parallel.
+func FindOutdatedDependencies(ctx context.Context, buildInfo *entities.BuildInfo, endpoints RegistryEndpoints, threads int, logger Log) ([]OutdatedDependency, error) {
+	dependencies := collectRegistryDependencies(buildInfo)
+	var packages []registryPackage
+	for _, dependency := range dependencies {
+		if !slices.Contains(packages, dependency.registryPackage) {
+			packages = append(packages, dependency.registryPackage)
+		}
+	}
+
+	var latestVersionsLock sync.Mutex
+	latestVersions := make(map[registryPackage]string)
+	runner := parallel.NewBounedRunner(threads, false)
+	go func() {
+		defer runner.Done()
+		for _, pkg := range packages {
+			pkg := pkg
+			_, _ = runner.AddTaskWithError(func(int) error {
+				latestVersion, err := getLatestVersion(ctx, pkg, endpoints)
+				if err != nil {
+					return err
+				}
+				latestVersionsLock.Lock()
+				defer latestVersionsLock.Unlock()
+				latestVersions[pkg] = latestVersion
+				return nil
+			}, func(err error) {
+				logger.Warn(err.Error())
+			})
+		}
+	}()
+	runner.Run()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var outdated []OutdatedDependency
+	for _, dependency := range dependencies {
+		latestVersion := latestVersions[dependency.registryPackage]
+		if latestVersion == "" || version.NewVersion(dependency.version).Compare(latestVersion) <= 0 {
+			continue
+		}
+		outdated = append(outdated, OutdatedDependency{
+			Ecosystem:      dependency.ecosystem,
+			Name:           dependency.name,
+			CurrentVersion: dependency.version,
+			LatestVersion:  latestVersion,
+			Modules:        dependency.modules,
+		})
+	}
+	sort.SliceStable(outdated, func(i, j int) bool {
+		if outdated[i].Ecosystem != outdated[j].Ecosystem {
+			return outdated[i].Ecosystem < outdated[j].Ecosystem
+		}
+		return outdated[i].Name < outdated[j].Name
+	})
+	return outdated, nil
+}
+
+// Returns the dependencies of the build-info's modules, whose ecosystems are supported, each version with the modules which depend on it.
+func collectRegistryDependencies(buildInfo *entities.BuildInfo) []*collectedDependency {
+	var dependencies []*collectedDependency
+	for _, module := range buildInfo.Modules {
+		for _, dependency := range module.Dependencies {
+			pkg, dependencyVersion, ok := parseRegistryDependency(module.Type, dependency.Id)
+			if !ok {
+				continue
+			}
+			var collected *collectedDependency
+			for _, existing := range dependencies {
+				if existing.registryPackage == pkg && existing.version == dependencyVersion {
+					collected = existing
+					break
+				}
+			}
+			if collected == nil {
+				collected = &collectedDependency{registryPackage: pkg, version: dependencyVersion}
+				dependencies = append(dependencies, collected)
+			}
+			if len(collected.modules) == 0 || collected.modules[len(collected.modules)-1] != module.Id {
+				collected.modules = append(collected.modules, module.Id)
+			}
+		}
+	}
+	return dependencies
+}
+
+// Splits a dependency ID to its package and version, by the type of the module which depends on it.
+// The IDs are in the formats: <name>:<version> for npm and Python, and <groupId>:<artifactId>:<version> for Maven and Gradle.
+func parseRegistryDependency(moduleType entities.ModuleType, dependencyId string) (pkg registryPackage, dependencyVersion string, ok bool) {
+	index := strings.LastIndex(dependencyId, ":")
+	if index <= 0 || index == len(dependencyId)-1 {
+		return
+	}
+	pkg.name, dependencyVersion = dependencyId[:index], dependencyId[index+1:]
+	switch moduleType {
+	case entities.Npm:
+		pkg.ecosystem = NpmEcosystem
+	case entities.Python:
+		pkg.ecosystem = PypiEcosystem
+	case entities.Maven, entities.Gradle:
+		if strings.Count(pkg.name, ":") != 1 {
+			return
+		}
+		pkg.ecosystem = MavenEcosystem
+	default:
+		return
+	}
+	ok = true
+	return
+}
+
+func getLatestVersion(ctx context.Context, pkg registryPackage, endpoints RegistryEndpoints) (latestVersion string, err error) {
+	switch pkg.ecosystem {
+	case NpmEcosystem:
+		latestVersion, err = getNpmLatestVersion(ctx, endpoints.Npm, pkg.name)
+	case MavenEcosystem:
+		latestVersion, err = getMavenLatestVersion(ctx, endpoints.Maven, pkg.name)
+	case PypiEcosystem:
+		latestVersion, err = getPypiLatestVersion(ctx, endpoints.Pypi, pkg.name)
+	}
+	if err == nil && latestVersion == "" {
+		err = errors.New("no versions were found")
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to get the latest version of the %s package %s: %w", pkg.ecosystem, pkg.name, err)
+	}
+	return
+}
+
+// Returns the version tagged as 'latest' in the npm registry.
+func getNpmLatestVersion(ctx context.Context, registry, name string) (string, error) {
+	var packument struct {
+		DistTags struct {
+			Latest string `json:"latest"`
+		} `json:"dist-tags"`
+	}
+	// The slash of scoped packages must be escaped.
+	content, err := getRegistryContent(ctx, joinRegistryUrl(registry, strings.Replace(name, "/", "%2F", 1)), "application/vnd.npm.install-v1+json")
+	if err != nil {
+		return "", err
+	}
+	if err = json.Unmarshal(content, &packument); err != nil {
+		return "", err
+	}
+	return packument.DistTags.Latest, nil
+}
+
+// Returns the release version of the artifact in the maven-metadata.xml of the Maven repository.
+// If the release version is missing, the latest version is returned, or the last listed version if it's missing too.
+func getMavenLatestVersion(ctx context.Context, repository, name string) (string, error) {
+	groupId, artifactId, _ := strings.Cut(name, ":")
+	var metadata struct {
+		Versioning struct {
+			Latest   string   `xml:"latest"`
+			Release  string   `xml:"release"`
+			Versions []string `xml:"versions>version"`
+		} `xml:"versioning"`
+	}
+	content, err := getRegistryContent(ctx, joinRegistryUrl(repository, strings.ReplaceAll(groupId, ".", "/"), artifactId, "maven-metadata.xml"), "application/xml")
+	if err != nil {
+		return "", err
+	}
+	if err = xml.Unmarshal(content, &metadata); err != nil {
+		return "", err
+	}
+	switch {
+	case metadata.Versioning.Release != "":
+		return metadata.Versioning.Release, nil
+	case metadata.Versioning.Latest != "":
+		return metadata.Versioning.Latest, nil
+	case len(metadata.Versioning.Versions) > 0:
+		return metadata.Versioning.Versions[len(metadata.Versioning.Versions)-1], nil
+	}
+	return "", nil
+}
+
+// Returns the latest version of the project in the JSON API of the PyPI index.
+func getPypiLatestVersion(ctx context.Context, index, name string) (string, error) {
+	var project struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+	}
+	content, err := getRegistryContent(ctx, joinRegistryUrl(index, url.PathEscape(name), "json"), "application/json")
+	if err != nil {
+		return "", err
+	}
+	if err = json.Unmarshal(content, &project); err != nil {
+		return "", err
+	}
+	return project.Info.Version, nil
+}
+
+func joinRegistryUrl(baseUrl string, elements ...string) string {
+	return strings.TrimSuffix(baseUrl, "/") + "/" + strings.Join(elements, "/")
+}
+
+func getRegistryContent(ctx context.Context, registryUrl, accept string) ([]byte, error) {
+	content, _, err := getRegistryResponse(ctx, registryUrl, accept)
+	return content, err
+}
+
+// Returns the content and the headers of the registry's response.
+func getRegistryResponse(ctx context.Context, registryUrl, accept string) (content []byte, header http.Header, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, registryUrl, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Accept", accept)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("%s returned status: %s", registryUrl, resp.Status)
+	}
+	content, err = io.ReadAll(resp.Body)
+	return content, resp.Header, err
+}
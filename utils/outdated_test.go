@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOutdatedDependencies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.EscapedPath() {
+		case "/npm/lodash":
+			body = `{"name":"lodash","dist-tags":{"latest":"4.17.21"}}`
+		case "/npm/@types%2Fnode":
+			body = `{"name":"@types/node","dist-tags":{"latest":"20.11.0"}}`
+		case "/maven/com/google/guava/guava/maven-metadata.xml":
+			body = `<metadata><versioning><latest>33.0.0-jre</latest><release>33.0.0-jre</release><versions><version>32.1.2-jre</version><version>33.0.0-jre</version></versions></versioning></metadata>`
+		case "/maven/org/slf4j/slf4j-api/maven-metadata.xml":
+			body = `<metadata><versioning><versions><version>2.0.8</version><version>2.0.9</version></versions></versioning></metadata>`
+		case "/pypi/requests/json":
+			body = `{"info":{"version":"2.31.0"}}`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	buildInfo := &entities.BuildInfo{Modules: []entities.Module{
+		{Id: "app:1.0.0", Type: entities.Npm, Dependencies: []entities.Dependency{
+			{Id: "lodash:4.17.15"},
+			{Id: "@types/node:20.11.0"},
+			// Isn't published to the registry.
+			{Id: "@internal/lib:1.0.0"},
+		}},
+		{Id: "web:1.0.0", Type: entities.Npm, Dependencies: []entities.Dependency{{Id: "lodash:4.17.15"}}},
+		{Id: "org.example:app:1.0", Type: entities.Maven, Dependencies: []entities.Dependency{
+			{Id: "com.google.guava:guava:32.1.2-jre"},
+			{Id: "org.slf4j:slf4j-api:2.0.9"},
+		}},
+		{Id: "service", Type: entities.Python, Dependencies: []entities.Dependency{{Id: "requests:2.28.0"}}},
+		{Id: "example.com/app", Type: entities.Go, Dependencies: []entities.Dependency{{Id: "github.com/jfrog/gofrog:v1.0.0"}}},
+	}}
+	endpoints := RegistryEndpoints{Npm: server.URL + "/npm/", Maven: server.URL + "/maven", Pypi: server.URL + "/pypi"}
+	outdated, err := FindOutdatedDependencies(context.Background(), buildInfo, endpoints, 2, NewDefaultLogger(INFO))
+	require.NoError(t, err)
+	assert.Equal(t, []OutdatedDependency{
+		{Ecosystem: MavenEcosystem, Name: "com.google.guava:guava", CurrentVersion: "32.1.2-jre", LatestVersion: "33.0.0-jre", Modules: []string{"org.example:app:1.0"}},
+		{Ecosystem: NpmEcosystem, Name: "lodash", CurrentVersion: "4.17.15", LatestVersion: "4.17.21", Modules: []string{"app:1.0.0", "web:1.0.0"}},
+		{Ecosystem: PypiEcosystem, Name: "requests", CurrentVersion: "2.28.0", LatestVersion: "2.31.0", Modules: []string{"service"}},
+	}, outdated)
+}
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+)
+
+// PackageMetadata holds the details of a version of a package, as published to its registry.
+type PackageMetadata struct {
+	// The time in which the version was published. Zero if the registry doesn't provide it.
+	PublishTime time.Time
+	// The licenses declared by the version, as SPDX identifiers or expressions when the registry provides them, for example: MIT
+	Licenses []string
+	// True if the registry signed the version. Only npm registries provide signatures.
+	Signed bool
+	// True if the version has a provenance attestation, which links it to the source and the build which published it. Only npm registries provide attestations.
+	ProvenanceAttested bool
+}
+
+// GetPackageMetadata queries the registry of a dependency of the build-info for the metadata of the dependency's version.
+// Like FindOutdatedDependencies, the npm, Maven (and Gradle) and Python dependencies are supported,
+// and nil metadata is returned for the dependencies of other module types.
+// The licenses of Maven artifacts are read from their POMs, without the licenses inherited from parent POMs,
+// and their publish time is the time in which the POMs were last modified.
+func GetPackageMetadata(ctx context.Context, moduleType entities.ModuleType, dependencyId string, endpoints RegistryEndpoints) (metadata *PackageMetadata, err error) {
+	pkg, dependencyVersion, ok := parseRegistryDependency(moduleType, dependencyId)
+	if !ok {
+		return nil, nil
+	}
+	switch pkg.ecosystem {
+	case NpmEcosystem:
+		metadata, err = getNpmPackageMetadata(ctx, endpoints.Npm, pkg.name, dependencyVersion)
+	case MavenEcosystem:
+		metadata, err = getMavenPackageMetadata(ctx, endpoints.Maven, pkg.name, dependencyVersion)
+	case PypiEcosystem:
+		metadata, err = getPypiPackageMetadata(ctx, endpoints.Pypi, pkg.name, dependencyVersion)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to get the metadata of version %s of the %s package %s: %w", dependencyVersion, pkg.ecosystem, pkg.name, err)
+	}
+	return
+}
+
+// Reads the publish time from the 'time' field of the package's document in the npm registry, and the licenses,
+// the registry signatures and the attestations from the version's manifest.
+func getNpmPackageMetadata(ctx context.Context, registry, name, packageVersion string) (*PackageMetadata, error) {
+	var packument struct {
+		Time     map[string]time.Time `json:"time"`
+		Versions map[string]struct {
+			License  json.RawMessage   `json:"license"`
+			Licenses []json.RawMessage `json:"licenses"`
+			Dist     struct {
+				Signatures   []json.RawMessage `json:"signatures"`
+				Attestations *struct {
+					Provenance *struct {
+						PredicateType string `json:"predicateType"`
+					} `json:"provenance"`
+				} `json:"attestations"`
+			} `json:"dist"`
+		} `json:"versions"`
+	}
+	// The abbreviated document, which is used to find the latest version, has no publish times.
+	content, err := getRegistryContent(ctx, joinRegistryUrl(registry, strings.Replace(name, "/", "%2F", 1)), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(content, &packument); err != nil {
+		return nil, err
+	}
+	manifest, ok := packument.Versions[packageVersion]
+	if !ok {
+		return nil, fmt.Errorf("version %s wasn't found in the registry", packageVersion)
+	}
+	metadata := &PackageMetadata{PublishTime: packument.Time[packageVersion], Signed: len(manifest.Dist.Signatures) > 0}
+	metadata.ProvenanceAttested = manifest.Dist.Attestations != nil && manifest.Dist.Attestations.Provenance != nil
+	// The license is an SPDX expression. Old packages declare an object, or a list of objects in the deprecated 'licenses' field.
+	for _, license := range append([]json.RawMessage{manifest.License}, manifest.Licenses...) {
+		if licenseName := parseNpmLicense(license); licenseName != "" {
+			metadata.Licenses = append(metadata.Licenses, licenseName)
+		}
+	}
+	return metadata, nil
+}
+
+func parseNpmLicense(license json.RawMessage) string {
+	if len(license) == 0 {
+		return ""
+	}
+	var licenseName string
+	if json.Unmarshal(license, &licenseName) == nil {
+		return licenseName
+	}
+	var licenseObject struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(license, &licenseObject)
+	return licenseObject.Type
+}
+
+// Reads the licenses from the artifact's POM, and the publish time from the POM's Last-Modified header.
+func getMavenPackageMetadata(ctx context.Context, repository, name, packageVersion string) (*PackageMetadata, error) {
+	groupId, artifactId, _ := strings.Cut(name, ":")
+	var pom struct {
+		Licenses []string `xml:"licenses>license>name"`
+	}
+	content, header, err := getRegistryResponse(ctx, joinRegistryUrl(repository, strings.ReplaceAll(groupId, ".", "/"), artifactId, packageVersion, artifactId+"-"+packageVersion+".pom"), "application/xml")
+	if err != nil {
+		return nil, err
+	}
+	if err = xml.Unmarshal(content, &pom); err != nil {
+		return nil, err
+	}
+	metadata := &PackageMetadata{}
+	for _, license := range pom.Licenses {
+		if license = strings.TrimSpace(license); license != "" {
+			metadata.Licenses = append(metadata.Licenses, license)
+		}
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		if metadata.PublishTime, err = http.ParseTime(lastModified); err != nil {
+			return nil, err
+		}
+	}
+	return metadata, nil
+}
+
+// Reads the metadata of the release from the JSON API of the PyPI index. The publish time is the upload time of the release's first file.
+func getPypiPackageMetadata(ctx context.Context, index, name, packageVersion string) (*PackageMetadata, error) {
+	var release struct {
+		Info struct {
+			License           string `json:"license"`
+			LicenseExpression string `json:"license_expression"`
+		} `json:"info"`
+		Urls []struct {
+			UploadTime time.Time `json:"upload_time_iso_8601"`
+		} `json:"urls"`
+	}
+	content, err := getRegistryContent(ctx, joinRegistryUrl(index, url.PathEscape(name), url.PathEscape(packageVersion), "json"), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(content, &release); err != nil {
+		return nil, err
+	}
+	metadata := &PackageMetadata{}
+	if len(release.Urls) > 0 {
+		metadata.PublishTime = release.Urls[0].UploadTime
+	}
+	// The 'license' field is free text, which sometimes holds the license's full text, so only its first line is kept.
+	license := release.Info.LicenseExpression
+	if license == "" {
+		license, _, _ = strings.Cut(strings.TrimSpace(release.Info.License), "\n")
+	}
+	if license = strings.TrimSpace(license); license != "" {
+		metadata.Licenses = []string{license}
+	}
+	return metadata, nil
+}
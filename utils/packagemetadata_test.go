@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPackageMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.EscapedPath() {
+		case "/npm/lodash":
+			body = `{"name":"lodash","time":{"created":"2012-04-23T16:37:11.912Z","4.17.21":"2021-02-20T15:42:16.891Z"},"versions":{"4.17.21":{"license":"MIT","dist":{"signatures":[{"keyid":"SHA256:jl3bwswu80PjjokCgh0o2w5c2U4LhQAE57gj9cz1kzA","sig":"MEUCIQ"}],"attestations":{"url":"https://registry.npmjs.org/-/npm/v1/attestations/lodash@4.17.21","provenance":{"predicateType":"https://slsa.dev/provenance/v1"}}}}}}`
+		case "/npm/@types%2Fnode":
+			body = `{"time":{"1.0.0":"2015-01-01T00:00:00.000Z"},"versions":{"1.0.0":{"license":{"type":"BSD"},"licenses":[{"type":"Apache-2.0"}]}}}`
+		case "/maven/com/google/guava/guava/32.1.2-jre/guava-32.1.2-jre.pom":
+			w.Header().Set("Last-Modified", "Wed, 26 Jul 2023 18:07:33 GMT")
+			body = `<project><licenses><license><name>Apache License, Version 2.0</name></license></licenses></project>`
+		case "/pypi/requests/2.28.0/json":
+			body = `{"info":{"license":"Apache 2.0\nFull text"},"urls":[{"upload_time_iso_8601":"2022-06-09T14:44:38.132Z"}]}`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	endpoints := RegistryEndpoints{Npm: server.URL + "/npm", Maven: server.URL + "/maven", Pypi: server.URL + "/pypi"}
+
+	testCases := []struct {
+		moduleType   entities.ModuleType
+		dependencyId string
+		expected     *PackageMetadata
+	}{
+		{entities.Npm, "lodash:4.17.21", &PackageMetadata{PublishTime: time.Date(2021, 2, 20, 15, 42, 16, 891000000, time.UTC), Licenses: []string{"MIT"}, Signed: true, ProvenanceAttested: true}},
+		{entities.Npm, "@types/node:1.0.0", &PackageMetadata{PublishTime: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC), Licenses: []string{"BSD", "Apache-2.0"}}},
+		{entities.Gradle, "com.google.guava:guava:32.1.2-jre", &PackageMetadata{PublishTime: time.Date(2023, 7, 26, 18, 7, 33, 0, time.UTC), Licenses: []string{"Apache License, Version 2.0"}}},
+		{entities.Python, "requests:2.28.0", &PackageMetadata{PublishTime: time.Date(2022, 6, 9, 14, 44, 38, 132000000, time.UTC), Licenses: []string{"Apache 2.0"}}},
+		// Unsupported module types have no metadata.
+		{entities.Go, "github.com/jfrog/gofrog:v1.0.0", nil},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.dependencyId, func(t *testing.T) {
+			metadata, err := GetPackageMetadata(context.Background(), testCase.moduleType, testCase.dependencyId, endpoints)
+			require.NoError(t, err)
+			if testCase.expected == nil {
+				assert.Nil(t, metadata)
+				return
+			}
+			require.NotNil(t, metadata)
+			assert.True(t, testCase.expected.PublishTime.Equal(metadata.PublishTime), metadata.PublishTime)
+			assert.Equal(t, testCase.expected.Licenses, metadata.Licenses)
+			assert.Equal(t, testCase.expected.Signed, metadata.Signed)
+			assert.Equal(t, testCase.expected.ProvenanceAttested, metadata.ProvenanceAttested)
+		})
+	}
+
+	_, err := GetPackageMetadata(context.Background(), entities.Npm, "lodash:1.0.0", endpoints)
+	assert.ErrorContains(t, err, "version 1.0.0 wasn't found")
+}
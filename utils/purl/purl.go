@@ -0,0 +1,344 @@
+// Package purl converts the package IDs of build-info to package URLs (purls), and back.
+// See the package URL specification: https://github.com/package-url/purl-spec
+package purl
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// The supported package URL types.
+const (
+	Maven   = "maven"
+	Npm     = "npm"
+	Pypi    = "pypi"
+	Conan   = "conan"
+	Helm    = "helm"
+	Golang  = "golang"
+	Docker  = "docker"
+	Nuget   = "nuget"
+	Gem     = "gem"
+	Hex     = "hex"
+	Hackage = "hackage"
+)
+
+// The characters which aren't percent-encoded, in addition to the unreserved characters.
+const (
+	segmentSafeChars   = ":"
+	qualifierSafeChars = ":/"
+)
+
+// PackageURL is a parsed package URL: pkg:<type>/<namespace>/<name>@<version>?<qualifiers>#<subpath>
+type PackageURL struct {
+	Type string
+	// The namespace's segments are separated by slashes, for example: github.com/jfrog
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	Subpath    string
+}
+
+// Parse parses a package URL.
+func Parse(purl string) (*PackageURL, error) {
+	remainder, found := cutPrefixFold(purl, "pkg:")
+	if !found {
+		return nil, fmt.Errorf("the package URL '%s' doesn't start with 'pkg:'", purl)
+	}
+	remainder = strings.TrimLeft(remainder, "/")
+	p := &PackageURL{}
+	var err error
+	if index := strings.LastIndex(remainder, "#"); index >= 0 {
+		if p.Subpath, err = unescapePath(strings.Trim(remainder[index+1:], "/")); err != nil {
+			return nil, err
+		}
+		remainder = remainder[:index]
+	}
+	if index := strings.LastIndex(remainder, "?"); index >= 0 {
+		if p.Qualifiers, err = parseQualifiers(remainder[index+1:]); err != nil {
+			return nil, err
+		}
+		remainder = remainder[:index]
+	}
+	p.Type, remainder, found = strings.Cut(strings.TrimRight(remainder, "/"), "/")
+	if !found || p.Type == "" {
+		return nil, fmt.Errorf("the package URL '%s' doesn't have a type and a name", purl)
+	}
+	p.Type = strings.ToLower(p.Type)
+	// An unescaped '@' may also start an npm scope.
+	if index := strings.LastIndex(remainder, "@"); index > 0 && remainder[index-1] != '/' {
+		if p.Version, err = url.PathUnescape(remainder[index+1:]); err != nil {
+			return nil, err
+		}
+		remainder = remainder[:index]
+	}
+	if index := strings.LastIndex(remainder, "/"); index >= 0 {
+		if p.Namespace, err = unescapePath(remainder[:index]); err != nil {
+			return nil, err
+		}
+		remainder = remainder[index+1:]
+	}
+	if p.Name, err = url.PathUnescape(remainder); err != nil {
+		return nil, err
+	}
+	if p.Name == "" {
+		return nil, fmt.Errorf("the package URL '%s' doesn't have a name", purl)
+	}
+	p.normalize()
+	return p, nil
+}
+
+// String returns the canonical form of the package URL, in which the qualifiers are sorted by their keys.
+func (p *PackageURL) String() string {
+	var builder strings.Builder
+	builder.WriteString("pkg:" + p.Type + "/")
+	if p.Namespace != "" {
+		builder.WriteString(escapePath(p.Namespace) + "/")
+	}
+	builder.WriteString(escape(p.Name, segmentSafeChars))
+	if p.Version != "" {
+		builder.WriteString("@" + escape(p.Version, segmentSafeChars))
+	}
+	var qualifiers []string
+	for key, value := range p.Qualifiers {
+		if value != "" {
+			qualifiers = append(qualifiers, strings.ToLower(key)+"="+escape(value, qualifierSafeChars))
+		}
+	}
+	if len(qualifiers) > 0 {
+		sort.Strings(qualifiers)
+		builder.WriteString("?" + strings.Join(qualifiers, "&"))
+	}
+	if p.Subpath != "" {
+		builder.WriteString("#" + escapePath(p.Subpath))
+	}
+	return builder.String()
+}
+
+// Applies the type-specific normalization rules of the specification.
+func (p *PackageURL) normalize() {
+	switch p.Type {
+	case Pypi:
+		// PyPI names are case insensitive, and '_' is equivalent to '-'.
+		p.Name = strings.ReplaceAll(strings.ToLower(p.Name), "_", "-")
+	}
+}
+
+// FromPackageId converts a build-info package ID to a package URL of the given type. The ID formats are:
+// maven: <groupId>:<artifactId>:<version>
+// npm: [@<scope>/]<name>:<version>
+// golang: <module path>:<version>
+// conan: <name>/<version>[@<user>/<channel>], or <name>:<version>
+// docker: [<registry>/][<namespace>/]<name>(:<tag>|@<digest>)
+// pypi, nuget, helm, gem, hex and hackage: <name>:<version>
+func FromPackageId(purlType, packageId string) (*PackageURL, error) {
+	p := &PackageURL{Type: strings.ToLower(purlType)}
+	var err error
+	switch p.Type {
+	case Maven:
+		parts := strings.Split(packageId, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("the Maven package ID '%s' isn't in the <groupId>:<artifactId>:<version> format", packageId)
+		}
+		p.Namespace, p.Name, p.Version = parts[0], parts[1], parts[2]
+	case Npm, Golang:
+		var name string
+		if name, p.Version, err = cutVersion(packageId); err != nil {
+			return nil, err
+		}
+		if index := strings.LastIndex(name, "/"); index >= 0 {
+			p.Namespace, name = name[:index], name[index+1:]
+		}
+		p.Name = name
+	case Pypi, Nuget, Helm, Gem, Hex, Hackage:
+		if p.Name, p.Version, err = cutVersion(packageId); err != nil {
+			return nil, err
+		}
+	case Conan:
+		if err = p.setConanReference(packageId); err != nil {
+			return nil, err
+		}
+	case Docker:
+		if err = p.setDockerImage(packageId); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("the package URL type '%s' isn't supported", purlType)
+	}
+	if p.Name == "" {
+		return nil, fmt.Errorf("the package ID '%s' doesn't have a name", packageId)
+	}
+	p.normalize()
+	return p, nil
+}
+
+// ToPackageId converts the package URL to a build-info package ID, in the formats described in FromPackageId.
+// Package URLs of other types are converted to <namespace>/<name>:<version>.
+// The version is omitted if the package URL doesn't have one.
+func (p *PackageURL) ToPackageId() string {
+	name := p.Name
+	switch p.Type {
+	case Maven:
+		if p.Namespace != "" {
+			name = p.Namespace + ":" + name
+		}
+	case Conan:
+		if p.Version == "" {
+			return name
+		}
+		reference := name + "/" + p.Version
+		if p.Qualifiers["user"] != "" || p.Qualifiers["channel"] != "" {
+			reference += "@" + valueOrDefault(p.Qualifiers["user"], "_") + "/" + valueOrDefault(p.Qualifiers["channel"], "_")
+		}
+		return reference
+	case Docker:
+		if p.Namespace != "" {
+			name = p.Namespace + "/" + name
+		}
+		if registry := p.Qualifiers["repository_url"]; registry != "" {
+			name = strings.TrimSuffix(registry, "/") + "/" + name
+		}
+		if strings.Contains(p.Version, ":") {
+			return name + "@" + p.Version
+		}
+	default:
+		if p.Namespace != "" {
+			name = p.Namespace + "/" + name
+		}
+	}
+	if p.Version == "" {
+		return name
+	}
+	return name + ":" + p.Version
+}
+
+// Sets the name, version, user and channel of a Conan reference: <name>/<version>[@<user>/<channel>]
+// The '_' user and channel, which Conan uses for references without them, are omitted.
+func (p *PackageURL) setConanReference(reference string) error {
+	if !strings.Contains(reference, "/") {
+		var err error
+		p.Name, p.Version, err = cutVersion(reference)
+		return err
+	}
+	nameVersion, userChannel, _ := strings.Cut(reference, "@")
+	// The recipe revision and the package ID aren't part of the package URL.
+	nameVersion, _, _ = strings.Cut(nameVersion, "#")
+	nameVersion, _, _ = strings.Cut(nameVersion, ":")
+	userChannel, _, _ = strings.Cut(userChannel, "#")
+	userChannel, _, _ = strings.Cut(userChannel, ":")
+	p.Name, p.Version, _ = strings.Cut(nameVersion, "/")
+	if p.Version == "" {
+		return fmt.Errorf("the Conan reference '%s' doesn't have a version", reference)
+	}
+	user, channel, _ := strings.Cut(userChannel, "/")
+	for key, value := range map[string]string{"user": user, "channel": channel} {
+		if value != "" && value != "_" {
+			if p.Qualifiers == nil {
+				p.Qualifiers = make(map[string]string)
+			}
+			p.Qualifiers[key] = value
+		}
+	}
+	return nil
+}
+
+// Sets the name, namespace, version and registry of a Docker image: [<registry>/][<namespace>/]<name>(:<tag>|@<digest>)
+func (p *PackageURL) setDockerImage(image string) error {
+	name := image
+	if index := strings.Index(image, "@"); index >= 0 {
+		name, p.Version = image[:index], image[index+1:]
+	} else if index = strings.LastIndex(image, ":"); index > strings.LastIndex(image, "/") {
+		name, p.Version = image[:index], image[index+1:]
+	}
+	if name == "" {
+		return fmt.Errorf("the Docker image '%s' doesn't have a name", image)
+	}
+	segments := strings.Split(name, "/")
+	// The first segment is a registry if it's a host name, as in the Docker reference format.
+	if first := segments[0]; len(segments) > 1 && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		p.Qualifiers = map[string]string{"repository_url": first}
+		segments = segments[1:]
+	}
+	p.Name = segments[len(segments)-1]
+	p.Namespace = strings.Join(segments[:len(segments)-1], "/")
+	return nil
+}
+
+// Splits a package ID to its name and version, which follows the last colon.
+func cutVersion(packageId string) (name, version string, err error) {
+	index := strings.LastIndex(packageId, ":")
+	if index <= 0 || index == len(packageId)-1 {
+		return "", "", fmt.Errorf("the package ID '%s' isn't in the <name>:<version> format", packageId)
+	}
+	return packageId[:index], packageId[index+1:], nil
+}
+
+func parseQualifiers(rawQualifiers string) (map[string]string, error) {
+	qualifiers := make(map[string]string)
+	for _, pair := range strings.Split(rawQualifiers, "&") {
+		key, value, found := strings.Cut(pair, "=")
+		if !found || key == "" {
+			if pair == "" {
+				continue
+			}
+			return nil, errors.New("invalid package URL qualifier: " + pair)
+		}
+		value, err := url.PathUnescape(value)
+		if err != nil {
+			return nil, err
+		}
+		qualifiers[strings.ToLower(key)] = value
+	}
+	return qualifiers, nil
+}
+
+// Percent-encodes the characters of the value, except for the unreserved characters and the safe characters.
+// Unlike in URL paths, '@' and '+' are encoded as well.
+func escape(value, safeChars string) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		if 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || strings.IndexByte("-._~"+safeChars, b) >= 0 {
+			builder.WriteByte(b)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+// Percent-encodes each of the segments of the slash-separated path.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		segments[i] = escape(segments[i], segmentSafeChars)
+	}
+	return strings.Join(segments, "/")
+}
+
+func unescapePath(path string) (string, error) {
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		var err error
+		if segments[i], err = url.PathUnescape(segments[i]); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+func cutPrefixFold(value, prefix string) (string, bool) {
+	if len(value) < len(prefix) || !strings.EqualFold(value[:len(prefix)], prefix) {
+		return value, false
+	}
+	return value[len(prefix):], true
+}
+
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,73 @@
+package purl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromPackageId(t *testing.T) {
+	tests := []struct {
+		purlType, packageId, expectedPurl, expectedId string
+	}{
+		{Maven, "org.jfrog:build-info:1.0.0", "pkg:maven/org.jfrog/build-info@1.0.0", "org.jfrog:build-info:1.0.0"},
+		{Npm, "@jfrog/build-info:1.0.0+build.1", "pkg:npm/%40jfrog/build-info@1.0.0%2Bbuild.1", "@jfrog/build-info:1.0.0+build.1"},
+		{Npm, "lodash:4.17.21", "pkg:npm/lodash@4.17.21", "lodash:4.17.21"},
+		{Golang, "github.com/jfrog/gofrog:v1.7.6", "pkg:golang/github.com/jfrog/gofrog@v1.7.6", "github.com/jfrog/gofrog:v1.7.6"},
+		{Pypi, "Typing_Extensions:4.8.0", "pkg:pypi/typing-extensions@4.8.0", "typing-extensions:4.8.0"},
+		{Nuget, "Newtonsoft.Json:13.0.3", "pkg:nuget/Newtonsoft.Json@13.0.3", "Newtonsoft.Json:13.0.3"},
+		{Helm, "nginx:15.4.3", "pkg:helm/nginx@15.4.3", "nginx:15.4.3"},
+		{Gem, "nokogiri:1.15.4", "pkg:gem/nokogiri@1.15.4", "nokogiri:1.15.4"},
+		{Hex, "plug_cowboy:2.6.1", "pkg:hex/plug_cowboy@2.6.1", "plug_cowboy:2.6.1"},
+		{Hackage, "text-show:3.10.4", "pkg:hackage/text-show@3.10.4", "text-show:3.10.4"},
+		{Conan, "zlib/1.3@conan/stable", "pkg:conan/zlib@1.3?channel=stable&user=conan", "zlib/1.3@conan/stable"},
+		{Conan, "zlib/1.3#revision:package-id", "pkg:conan/zlib@1.3", "zlib/1.3"},
+		{Conan, "zlib:1.3", "pkg:conan/zlib@1.3", "zlib/1.3"},
+		{Docker, "nginx:1.25", "pkg:docker/nginx@1.25", "nginx:1.25"},
+		{Docker, "acme.jfrog.io/docker-local/app/web@sha256:abc", "pkg:docker/docker-local/app/web@sha256:abc?repository_url=acme.jfrog.io", "acme.jfrog.io/docker-local/app/web@sha256:abc"},
+		{Docker, "localhost:5000/app", "pkg:docker/app?repository_url=localhost:5000", "localhost:5000/app"},
+	}
+	for _, test := range tests {
+		p, err := FromPackageId(test.purlType, test.packageId)
+		require.NoError(t, err, test.packageId)
+		assert.Equal(t, test.expectedPurl, p.String(), test.packageId)
+
+		parsed, err := Parse(test.expectedPurl)
+		require.NoError(t, err, test.expectedPurl)
+		assert.Equal(t, test.expectedId, parsed.ToPackageId(), test.expectedPurl)
+	}
+
+	for purlType, packageId := range map[string]string{Maven: "org.jfrog:build-info", Npm: "lodash", Pypi: "requests:", "cargo": "serde:1.0.0"} {
+		_, err := FromPackageId(purlType, packageId)
+		assert.Error(t, err, packageId)
+	}
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse("PKG:Maven/org.jfrog/build-info@1.0.0?Type=pom&classifier=sources#src/main")
+	require.NoError(t, err)
+	assert.Equal(t, &PackageURL{
+		Type:       Maven,
+		Namespace:  "org.jfrog",
+		Name:       "build-info",
+		Version:    "1.0.0",
+		Qualifiers: map[string]string{"type": "pom", "classifier": "sources"},
+		Subpath:    "src/main",
+	}, p)
+	assert.Equal(t, "pkg:maven/org.jfrog/build-info@1.0.0?classifier=sources&type=pom#src/main", p.String())
+
+	// An unescaped '@' which follows a slash starts an npm scope.
+	p, err = Parse("pkg:npm/@jfrog/build-info")
+	require.NoError(t, err)
+	assert.Equal(t, "@jfrog/build-info", p.ToPackageId())
+
+	p, err = Parse("pkg:cargo/serde@1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "serde:1.0.0", p.ToPackageId())
+
+	for _, invalid := range []string{"npm/build-info@1.0.0", "pkg:npm", "pkg:npm/", "pkg:npm/lodash?invalid"} {
+		_, err = Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
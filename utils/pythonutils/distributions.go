@@ -0,0 +1,60 @@
+package pythonutils
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The directory in which PEP 517 build frontends, such as 'python -m build' and 'poetry build', write the distributions they build.
+const distributionsDir = "dist"
+
+// GetDistributionFiles returns the paths of the wheels and source distributions of a version of a package, from the 'dist' directory under srcPath.
+// The files of other versions, which were built earlier and weren't removed from the directory, are ignored.
+// If the directory doesn't exist, no paths are returned.
+func GetDistributionFiles(srcPath, packageName, packageVersion string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(srcPath, distributionsDir))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var distributionFiles []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		fileName, fileVersion, ok := parseDistributionFileName(entry.Name())
+		if ok && normalizePackageName(fileName) == normalizePackageName(packageName) && fileVersion == packageVersion {
+			distributionFiles = append(distributionFiles, filepath.Join(srcPath, distributionsDir, entry.Name()))
+		}
+	}
+	return distributionFiles, nil
+}
+
+// Returns the package name and version of a wheel, such as 'my_package-1.0.0-py3-none-any.whl',
+// or of a source distribution, such as 'my_package-1.0.0.tar.gz' or 'my-package-1.0.0.zip'.
+func parseDistributionFileName(fileName string) (packageName, packageVersion string, ok bool) {
+	if wheel, isWheel := strings.CutSuffix(fileName, ".whl"); isWheel {
+		// {distribution}-{version}(-{build tag})?-{python tag}-{abi tag}-{platform tag}.whl
+		parts := strings.Split(wheel, "-")
+		if len(parts) < 5 {
+			return "", "", false
+		}
+		return parts[0], parts[1], true
+	}
+	for _, extension := range []string{".tar.gz", ".zip"} {
+		if sdist, isSdist := strings.CutSuffix(fileName, extension); isSdist {
+			// Old source distributions may keep the dashes in the package's name, but versions never contain them.
+			separatorIndex := strings.LastIndex(sdist, "-")
+			if separatorIndex <= 0 {
+				return "", "", false
+			}
+			return sdist[:separatorIndex], sdist[separatorIndex+1:], true
+		}
+	}
+	return "", "", false
+}
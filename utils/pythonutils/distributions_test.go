@@ -0,0 +1,64 @@
+package pythonutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDistributionFiles(t *testing.T) {
+	srcPath := t.TempDir()
+	distPath := filepath.Join(srcPath, "dist")
+	require.NoError(t, os.MkdirAll(distPath, 0755))
+	for _, fileName := range []string{
+		"my_package-1.0.0-py3-none-any.whl",
+		"my_package-1.0.0.tar.gz",
+		"my-package-1.0.0.zip",
+		// Older versions and other packages are ignored.
+		"my_package-0.9.0-py3-none-any.whl",
+		"my_package-0.9.0.tar.gz",
+		"other_package-1.0.0-py3-none-any.whl",
+		"README.md",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(distPath, fileName), []byte(fileName), 0644))
+	}
+
+	distributionFiles, err := GetDistributionFiles(srcPath, "My.Package", "1.0.0")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(distPath, "my_package-1.0.0-py3-none-any.whl"),
+		filepath.Join(distPath, "my_package-1.0.0.tar.gz"),
+		filepath.Join(distPath, "my-package-1.0.0.zip"),
+	}, distributionFiles)
+
+	// A project which wasn't built has no distributions.
+	distributionFiles, err = GetDistributionFiles(t.TempDir(), "my-package", "1.0.0")
+	require.NoError(t, err)
+	assert.Empty(t, distributionFiles)
+}
+
+func TestParseDistributionFileName(t *testing.T) {
+	testCases := []struct {
+		fileName        string
+		expectedName    string
+		expectedVersion string
+		expectedOk      bool
+	}{
+		{"requests-2.31.0-py3-none-any.whl", "requests", "2.31.0", true},
+		{"numpy-1.26.0-1-cp311-cp311-manylinux_2_17_x86_64.whl", "numpy", "1.26.0", true},
+		{"jfrog-python-example-1.0.tar.gz", "jfrog-python-example", "1.0", true},
+		{"invalid.whl", "", "", false},
+		{"setup.py", "", "", false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.fileName, func(t *testing.T) {
+			name, version, ok := parseDistributionFileName(testCase.fileName)
+			assert.Equal(t, testCase.expectedOk, ok)
+			assert.Equal(t, testCase.expectedName, name)
+			assert.Equal(t, testCase.expectedVersion, version)
+		})
+	}
+}
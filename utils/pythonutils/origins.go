@@ -0,0 +1,201 @@
+package pythonutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/gofrog/io"
+)
+
+// The properties of the pip dependencies, which describe where they were installed from.
+const (
+	// The origin of the dependency: index, vcs, archive or directory.
+	PipOriginProperty = "pip.origin"
+	// The URL of the package index which the dependency was downloaded from, for dependencies whose origin is an index.
+	PipIndexUrlProperty = "pip.indexUrl"
+	// The URL which the dependency was installed from, for dependencies installed from a direct URL (PEP 610), without its credentials.
+	PipUrlProperty = "pip.url"
+	// The version control system and the commit of the dependencies installed from a repository, for example: git
+	PipVcsProperty      = "pip.vcs"
+	PipCommitIdProperty = "pip.commitId"
+	// The branch, tag or commit requested for the dependencies installed from a repository, if any.
+	PipRequestedRevisionProperty = "pip.requestedRevision"
+	// True for the dependencies installed from a local directory in editable mode (pip install -e).
+	PipEditableProperty = "pip.editable"
+)
+
+// The origins of the pip dependencies. See PipOriginProperty.
+const (
+	IndexOrigin     = "index"
+	VcsOrigin       = "vcs"
+	ArchiveOrigin   = "archive"
+	DirectoryOrigin = "directory"
+)
+
+// The index which pip uses unless another index is configured, and which it doesn't print.
+const defaultPipIndexUrl = "https://pypi.org/simple"
+
+// Prints the site-packages directories of the environment which pip installs into, one per line.
+const sitePackagesScript = `import site, sysconfig
+paths = sysconfig.get_paths()
+print(paths["purelib"])
+print(paths["platlib"])
+if site.ENABLE_USER_SITE:
+    print(site.getusersitepackages())`
+
+var lookingInIndexesRegexp = regexp.MustCompile(`^\s*Looking\sin\sindexes:\s*(\S.*)$`)
+
+// The content of the direct_url.json file, which pip writes to the .dist-info directory of the packages installed from a direct URL,
+// rather than from an index. See https://packaging.python.org/en/latest/specifications/direct-url-data-structure/
+type directUrl struct {
+	Url     string `json:"url"`
+	VcsInfo *struct {
+		Vcs               string `json:"vcs"`
+		CommitId          string `json:"commit_id"`
+		RequestedRevision string `json:"requested_revision"`
+	} `json:"vcs_info"`
+	ArchiveInfo *struct{} `json:"archive_info"`
+	DirInfo     *struct {
+		Editable bool `json:"editable"`
+	} `json:"dir_info"`
+}
+
+// ApplyPipOrigins records where each of the installed pip dependencies came from, by the direct_url.json files (PEP 610)
+// of the packages in the site-packages directories of the environment.
+// The dependencies installed from a repository, an archive or a local directory get their URL and origin properties, and the local directories
+// are also marked as mutable, since their content may change without a change to their version. The others keep the index properties
+// recorded while their install log was parsed. The dependencies map is mapped by the packages' names, as returned by InstallWithLogParsing.
+func ApplyPipOrigins(srcPath string, dependenciesMap map[string]entities.Dependency, log utils.Log) error {
+	sitePackagesCmd := io.NewCommand("python", "", []string{"-c", sitePackagesScript})
+	sitePackagesCmd.Dir = srcPath
+	output, err := utils.RunCommandOutput(sitePackagesCmd)
+	if err != nil {
+		return fmt.Errorf("failed getting the site-packages directories of the Python environment: %w", err)
+	}
+	directUrls, err := readDirectUrls(strings.Split(strings.TrimSpace(output), "\n"), log)
+	if err != nil {
+		return err
+	}
+	applyDirectUrls(dependenciesMap, directUrls)
+	return nil
+}
+
+// Reads the direct_url.json files of the packages in the site-packages directories, mapped by the packages' normalized names.
+// If a package is installed in several directories, the first directory wins, like in Python's import path.
+func readDirectUrls(sitePackagesDirs []string, log utils.Log) (map[string]*directUrl, error) {
+	directUrls := make(map[string]*directUrl)
+	for _, sitePackagesDir := range sitePackagesDirs {
+		sitePackagesDir = strings.TrimSpace(sitePackagesDir)
+		if sitePackagesDir == "" {
+			continue
+		}
+		directUrlFiles, err := filepath.Glob(filepath.Join(sitePackagesDir, "*.dist-info", "direct_url.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, directUrlFile := range directUrlFiles {
+			// The directory's name is <name>-<version>.dist-info, in which the '-' of the name are escaped as '_'.
+			packageName, _, _ := strings.Cut(filepath.Base(filepath.Dir(directUrlFile)), "-")
+			packageName = normalizePackageName(packageName)
+			if directUrls[packageName] != nil {
+				continue
+			}
+			content, err := os.ReadFile(directUrlFile)
+			if err != nil {
+				return nil, err
+			}
+			parsed := new(directUrl)
+			if err = json.Unmarshal(content, parsed); err != nil {
+				log.Warn(fmt.Sprintf("Skipping %s, which couldn't be parsed: %s", directUrlFile, err.Error()))
+				continue
+			}
+			directUrls[packageName] = parsed
+		}
+	}
+	return directUrls, nil
+}
+
+func applyDirectUrls(dependenciesMap map[string]entities.Dependency, directUrls map[string]*directUrl) {
+	for name, dependency := range dependenciesMap {
+		directUrl := directUrls[normalizePackageName(name)]
+		if directUrl == nil {
+			continue
+		}
+		if dependency.Properties == nil {
+			dependency.Properties = make(map[string]string)
+		}
+		delete(dependency.Properties, PipIndexUrlProperty)
+		dependency.Properties[PipUrlProperty] = removeUrlCredentials(directUrl.Url)
+		switch {
+		case directUrl.VcsInfo != nil:
+			dependency.Properties[PipOriginProperty] = VcsOrigin
+			dependency.Properties[PipVcsProperty] = directUrl.VcsInfo.Vcs
+			dependency.Properties[PipCommitIdProperty] = directUrl.VcsInfo.CommitId
+			if directUrl.VcsInfo.RequestedRevision != "" {
+				dependency.Properties[PipRequestedRevisionProperty] = directUrl.VcsInfo.RequestedRevision
+			}
+		case directUrl.DirInfo != nil:
+			dependency.Properties[PipOriginProperty] = DirectoryOrigin
+			if directUrl.DirInfo.Editable {
+				dependency.Properties[PipEditableProperty] = "true"
+			}
+			dependency.SetMutable()
+		default:
+			dependency.Properties[PipOriginProperty] = ArchiveOrigin
+		}
+		dependenciesMap[name] = dependency
+	}
+}
+
+// Returns the index which the downloaded file belongs to, out of the indexes pip printed, or the default index if none were printed.
+// Returns an empty string if the file may belong to several of the indexes.
+func getPipIndexUrl(indexUrls []string, downloadPath string) string {
+	if len(indexUrls) == 0 {
+		return defaultPipIndexUrl
+	}
+	if len(indexUrls) == 1 {
+		return indexUrls[0]
+	}
+	// The files of the indexes are usually served under their base URLs, for example: <base URL>/simple and <base URL>/packages/...
+	// pip masks the credentials of the indexes it prints, so they're compared without them.
+	downloadPath = removeUrlCredentials(downloadPath)
+	for _, indexUrl := range indexUrls {
+		baseUrl := strings.TrimSuffix(strings.TrimSuffix(removeUrlCredentials(indexUrl), "/"), "/simple")
+		if _, address, found := strings.Cut(baseUrl, "://"); found && strings.Contains(downloadPath, address+"/") {
+			return indexUrl
+		}
+	}
+	return ""
+}
+
+// Parses the indexes from pip's 'Looking in indexes: <index>, <index>' line.
+func parsePipIndexUrls(line string) []string {
+	match := lookingInIndexesRegexp.FindStringSubmatch(line)
+	if match == nil {
+		return nil
+	}
+	var indexUrls []string
+	for _, indexUrl := range strings.Split(match[1], ",") {
+		if indexUrl = strings.TrimSpace(indexUrl); indexUrl != "" {
+			indexUrls = append(indexUrls, removeUrlCredentials(indexUrl))
+		}
+	}
+	return indexUrls
+}
+
+// Returns the URL without its user info. URLs which can't be parsed are returned as they are.
+func removeUrlCredentials(rawUrl string) string {
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil || parsedUrl.User == nil {
+		return rawUrl
+	}
+	parsedUrl.User = nil
+	return parsedUrl.String()
+}
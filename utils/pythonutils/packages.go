@@ -0,0 +1,127 @@
+package pythonutils
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"golang.org/x/exp/maps"
+)
+
+var packageNameSeparatorsRegexp = regexp.MustCompile(`[-_.]+`)
+
+// Directories which never contain the packages of a repository.
+var skippedPackagesSearchDirs = []string{"__pycache__", "node_modules", "site-packages"}
+
+// PythonPackage is one of the packages of a Python repository with several packages, each with its own pyproject.toml or setup.py.
+type PythonPackage struct {
+	// The package's directory.
+	Path string
+	// The package's ID, in the format of name:version.
+	Id string
+}
+
+// FindPythonPackages recursively searches srcPath for the directories of Python packages, which have a pyproject.toml or a setup.py file.
+// Hidden directories and virtual environments are skipped. Packages whose name can't be determined by the tool are skipped with a warning.
+// The packages are sorted by their paths, so that the root package (if srcPath is a package) is the first.
+func FindPythonPackages(tool PythonTool, srcPath string, log utils.Log) ([]PythonPackage, error) {
+	var packages []PythonPackage
+	err := filepath.WalkDir(srcPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return err
+		}
+		if path != srcPath && isSkippedPackagesSearchDir(path, entry.Name()) {
+			return filepath.SkipDir
+		}
+		pyProjectFilePath, err := getPyProjectFilePath(path)
+		if err != nil {
+			return err
+		}
+		setupPyFilePath, err := getSetupPyFilePath(path)
+		if err != nil {
+			return err
+		}
+		if pyProjectFilePath == "" && setupPyFilePath == "" {
+			return nil
+		}
+		packageId, err := GetPackageName(tool, path)
+		if err != nil {
+			log.Warn(fmt.Sprintf("Skipping the Python package at %s, since its name couldn't be determined: %s", path, err.Error()))
+			return nil
+		}
+		if packageId == "" {
+			log.Debug("Skipping", path+", since it has no package name.")
+			return nil
+		}
+		packages = append(packages, PythonPackage{Path: path, Id: packageId})
+		return nil
+	})
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Path < packages[j].Path })
+	return packages, err
+}
+
+func isSkippedPackagesSearchDir(path, name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".egg-info") {
+		return true
+	}
+	for _, skippedDir := range skippedPackagesSearchDirs {
+		if name == skippedDir {
+			return true
+		}
+	}
+	// Virtual environments are identified by their configuration file, whatever their names are.
+	_, err := os.Stat(filepath.Join(path, "pyvenv.cfg"))
+	return err == nil
+}
+
+// GetPackagesDependencies attributes the dependencies of a repository with several packages to the packages which require them.
+// The dependencies are resolved once for the whole repository, so dependenciesMap and dependenciesGraph are those of the environment in which
+// all the packages were installed, as returned by InstallWithLogParsing and GetPythonDependencies, and they aren't modified.
+// The dependencies on the other packages are project dependencies (see entities.ProjectDependencyType),
+// without their own dependencies, which are attributed to the packages which they belong to.
+// Returns the dependencies of each package, mapped by the package's ID.
+func GetPackagesDependencies(packages []PythonPackage, dependenciesMap map[string]entities.Dependency, dependenciesGraph map[string][]string) map[string]map[string]entities.Dependency {
+	// The nodes of the graph, mapped by the normalized names of their packages.
+	nodes := make(map[string]string, len(dependenciesGraph))
+	for node := range dependenciesGraph {
+		nodeName, _, _ := strings.Cut(node, ":")
+		nodes[normalizePackageName(nodeName)] = node
+	}
+	packagesGraph := maps.Clone(dependenciesGraph)
+	projectDependencies := make(map[string]entities.Dependency)
+	for _, pkg := range packages {
+		packageName, _, _ := strings.Cut(pkg.Id, ":")
+		if node, exist := nodes[normalizePackageName(packageName)]; exist {
+			delete(packagesGraph, node)
+			nodeName, _, _ := strings.Cut(node, ":")
+			projectDependencies[nodeName] = entities.Dependency{Type: entities.ProjectDependencyType}
+		}
+	}
+
+	packagesDependencies := make(map[string]map[string]entities.Dependency, len(packages))
+	for _, pkg := range packages {
+		packageName, _, _ := strings.Cut(pkg.Id, ":")
+		packageDependencies := maps.Clone(dependenciesMap)
+		maps.Copy(packageDependencies, projectDependencies)
+		packageGraph := maps.Clone(packagesGraph)
+		packageGraph[pkg.Id] = dependenciesGraph[nodes[normalizePackageName(packageName)]]
+		UpdateDepsIdsAndRequestedBy(packageDependencies, packageGraph, nil, pkg.Id, pkg.Id)
+		// Only the dependencies which were reached from the package belong to it.
+		maps.DeleteFunc(packageDependencies, func(_ string, dependency entities.Dependency) bool {
+			return len(dependency.RequestedBy) == 0
+		})
+		packagesDependencies[pkg.Id] = packageDependencies
+	}
+	return packagesDependencies
+}
+
+// Normalizes a package name as defined by PEP 503, since pip and pipdeptree may write names differently than the packages declare them.
+func normalizePackageName(name string) string {
+	return strings.ToLower(packageNameSeparatorsRegexp.ReplaceAllString(name, "-"))
+}
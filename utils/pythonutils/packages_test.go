@@ -0,0 +1,74 @@
+package pythonutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindPythonPackages(t *testing.T) {
+	root := t.TempDir()
+	writePyProject := func(dir, name string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+		content := "[project]\nname = \"" + name + "\"\nversion = \"1.0.0\"\n"
+		require.NoError(t, os.WriteFile(filepath.Join(root, dir, "pyproject.toml"), []byte(content), 0644))
+	}
+	writePyProject("", "monorepo")
+	writePyProject(filepath.Join("packages", "core"), "core")
+	writePyProject(filepath.Join("packages", "api"), "api")
+	// Packages in hidden directories and virtual environments are skipped.
+	writePyProject(filepath.Join(".tox", "py311"), "tox-package")
+	writePyProject(filepath.Join("venv", "lib", "vendored"), "vendored")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "venv", "pyvenv.cfg"), []byte("home = /usr/bin\n"), 0644))
+	// A pyproject.toml without a [project] section, which only configures tools.
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docs", "pyproject.toml"), []byte("[tool.black]\nline-length = 120\n"), 0644))
+
+	packages, err := FindPythonPackages(Pip, root, utils.NewDefaultLogger(utils.INFO))
+	require.NoError(t, err)
+	assert.Equal(t, []PythonPackage{
+		{Path: root, Id: "monorepo:1.0.0"},
+		{Path: filepath.Join(root, "packages", "api"), Id: "api:1.0.0"},
+		{Path: filepath.Join(root, "packages", "core"), Id: "core:1.0.0"},
+	}, packages)
+}
+
+func TestGetPackagesDependencies(t *testing.T) {
+	packages := []PythonPackage{{Id: "My_Api:1.0.0"}, {Id: "core:2.0.0"}, {Id: "docs:1.0.0"}}
+	dependenciesMap := map[string]entities.Dependency{
+		"flask":    {Id: "Flask-3.0.0-py3-none-any.whl"},
+		"click":    {Id: "click-8.1.7-py3-none-any.whl"},
+		"requests": {Id: "requests-2.31.0-py3-none-any.whl"},
+		// Unused by the packages.
+		"pip": {Id: "pip-23.3.1-py3-none-any.whl"},
+	}
+	dependenciesGraph := map[string][]string{
+		"my-api:1.0.0":    {"flask:3.0.0", "core:2.0.0"},
+		"flask:3.0.0":     {"click:8.1.7"},
+		"core:2.0.0":      {"requests:2.31.0"},
+		"click:8.1.7":     {},
+		"requests:2.31.0": {},
+		"pip:23.3.1":      {},
+	}
+
+	packagesDependencies := GetPackagesDependencies(packages, dependenciesMap, dependenciesGraph)
+	assert.Equal(t, map[string]entities.Dependency{
+		"flask": {Id: "flask:3.0.0", Type: "whl", RequestedBy: [][]string{{"My_Api:1.0.0"}}},
+		"click": {Id: "click:8.1.7", Type: "whl", RequestedBy: [][]string{{"flask:3.0.0", "My_Api:1.0.0"}}},
+		// The dependencies of other packages are collected in their own modules.
+		"core": {Id: "core:2.0.0", Type: entities.ProjectDependencyType, RequestedBy: [][]string{{"My_Api:1.0.0"}}},
+	}, packagesDependencies["My_Api:1.0.0"])
+	assert.Equal(t, map[string]entities.Dependency{
+		"requests": {Id: "requests:2.31.0", Type: "whl", RequestedBy: [][]string{{"core:2.0.0"}}},
+	}, packagesDependencies["core:2.0.0"])
+	// A package which isn't installed has no dependencies.
+	assert.Empty(t, packagesDependencies["docs:1.0.0"])
+	// The shared dependencies aren't modified.
+	assert.Equal(t, "Flask-3.0.0-py3-none-any.whl", dependenciesMap["flask"].Id)
+	assert.Len(t, dependenciesGraph["my-api:1.0.0"], 2)
+}
@@ -23,15 +23,15 @@ func getPipenvDependencies(srcPath string, logger utils.Log) (dependenciesGraph
 	// Run pipenv graph
 	pipenvGraphCmd := io.NewCommand("pipenv", "graph", []string{"--json"})
 	pipenvGraphCmd.Dir = srcPath
-	output, err := pipenvGraphCmd.RunWithOutput()
+	output, err := utils.RunCommandOutput(pipenvGraphCmd)
 	if err != nil {
 		err = fmt.Errorf("failed to run pipenv graph --json: %s", err.Error())
 		return
 	}
-	logger.Debug("pipenv graph --json output:\n" + string(output))
+	logger.Debug("pipenv graph --json output:\n" + output)
 	// Parse into array.
 	packages := make([]pythonDependencyPackage, 0)
-	err = json.Unmarshal(cleanJsonOutput(output), &packages)
+	err = json.Unmarshal(cleanJsonOutput([]byte(output)), &packages)
 	if err != nil {
 		err = fmt.Errorf("failed to parse pipenv graph --json output: %s", err.Error())
 		return
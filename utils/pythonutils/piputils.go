@@ -21,13 +21,13 @@ func getPipDependencies(srcPath, dependenciesDirName string) (map[string][]strin
 	}
 	localPipdeptree := io.NewCommand("python", "", []string{localPipdeptreeScript, "--json"})
 	localPipdeptree.Dir = srcPath
-	output, err := localPipdeptree.RunWithOutput()
+	output, err := utils.RunCommandOutput(localPipdeptree)
 	if err != nil {
 		return nil, nil, err
 	}
 	// Parse into array.
 	packages := make([]pythonDependencyPackage, 0)
-	if err = json.Unmarshal(output, &packages); err != nil {
+	if err = json.Unmarshal([]byte(output), &packages); err != nil {
 		return nil, nil, err
 	}
 
@@ -128,7 +128,7 @@ func getEgginfoPkginfoContent(setuppyFilePath string) (output []byte, err error)
 	if err != nil {
 		return nil, err
 	}
-	if err = exec.Command(pythonExecutable, args...).Run(); err != nil {
+	if _, _, err = utils.RunCommand(exec.Command(pythonExecutable, args...)); err != nil {
 		return nil, err
 	}
 
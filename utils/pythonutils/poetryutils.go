@@ -20,6 +20,22 @@ type PoetryLock struct {
 	Package []*PoetryPackage
 }
 
+type poetryLockFile struct {
+	File string
+	Hash string
+}
+
+// The hashes of the packages' files. Poetry >=1.2 lists them per package, while earlier versions list them under 'metadata.files'.
+type poetryLockHashes struct {
+	Package []struct {
+		Name  string
+		Files []poetryLockFile
+	}
+	Metadata struct {
+		Files map[string][]poetryLockFile
+	}
+}
+
 // Extract all poetry dependencies from the pyproject.toml and poetry.lock files.
 // Returns a dependency map of all the installed poetry packages in the current environment and another list of the top level dependencies.
 func getPoetryDependencies(srcPath string) (graph map[string][]string, directDependencies []string, err error) {
@@ -106,3 +122,37 @@ func extractPackagesFromPoetryLock(lockFilePath string) (dependencies map[string
 	}
 	return
 }
+
+// GetPoetryLockHashes returns the SHA-256 hashes of the files of each package in the poetry.lock file, mapped by the lowercase package name.
+// A package's dependency may be resolved from any of its files, such as a wheel or a source distribution.
+// If poetry.lock doesn't exist, an empty map is returned.
+func GetPoetryLockHashes(srcPath string) (map[string][]string, error) {
+	hashes := make(map[string][]string)
+	lockFilePath, err := getPoetryLockFilePath(srcPath)
+	if err != nil || lockFilePath == "" {
+		return hashes, err
+	}
+	content, err := os.ReadFile(lockFilePath)
+	if err != nil {
+		return nil, err
+	}
+	var lockHashes poetryLockHashes
+	if _, err = toml.Decode(string(content), &lockHashes); err != nil {
+		return nil, err
+	}
+	filesByPackage := lockHashes.Metadata.Files
+	if filesByPackage == nil {
+		filesByPackage = make(map[string][]poetryLockFile)
+	}
+	for _, lockPackage := range lockHashes.Package {
+		filesByPackage[lockPackage.Name] = append(filesByPackage[lockPackage.Name], lockPackage.Files...)
+	}
+	for packageName, files := range filesByPackage {
+		for _, file := range files {
+			if hash, found := strings.CutPrefix(file.Hash, "sha256:"); found {
+				hashes[strings.ToLower(packageName)] = append(hashes[strings.ToLower(packageName)], hash)
+			}
+		}
+	}
+	return hashes, nil
+}
@@ -1,6 +1,7 @@
 package pythonutils
 
 import (
+	"os"
 	"path/filepath"
 	"reflect"
 	"sort"
@@ -64,3 +65,23 @@ func TestGetProjectDependencies(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPoetryLockHashes(t *testing.T) {
+	hashes, err := GetPoetryLockHashes(filepath.Join("..", "testdata", "poetry", "project"))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"2d27e3784d7a565d36ab851fe94887c5eccd6a463168875832a1be79c82828b4", "626ba8234211db98e869df76230a137c4c40a12d72445c45d5f5b716f076e2fd"}, hashes["attrs"])
+
+	// Poetry >=1.2 lists the files of each package under the package itself.
+	projectPath := t.TempDir()
+	lock := `[[package]]
+name = "Colorama"
+version = "0.4.6"
+files = [
+    {file = "colorama-0.4.6-py2.py3-none-any.whl", hash = "sha256:4f1d9991f5acc0ca119f9d443620b77f9d6b33703e51011c16baf57afb285fc6"},
+]
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(projectPath, "poetry.lock"), []byte(lock), 0644))
+	hashes, err = GetPoetryLockHashes(projectPath)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"colorama": {"4f1d9991f5acc0ca119f9d443620b77f9d6b33703e51011c16baf57afb285fc6"}}, hashes)
+}
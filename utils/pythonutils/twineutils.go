@@ -3,6 +3,7 @@ package pythonutils
 import (
 	"fmt"
 	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/gofrog/crypto"
 	gofrogcmd "github.com/jfrog/gofrog/io"
 	"github.com/jfrog/gofrog/log"
@@ -26,7 +27,7 @@ func TwineUploadWithLogParsing(commandArgs []string, srcPath string) (artifactsP
 	uploadCmd := gofrogcmd.NewCommand(_twineExeName, _twineUploadCmdName, commandArgs)
 	uploadCmd.Dir = srcPath
 	log.Debug("Running twine command: '", _twineExeName, _twineUploadCmdName, strings.Join(commandArgs, " "), "'with build info collection")
-	_, errorOut, _, err := gofrogcmd.RunCmdWithOutputParser(uploadCmd, true, getArtifactsParser(&artifactsPaths))
+	_, errorOut, err := utils.RunCommandWithOutputParser(uploadCmd, true, getArtifactsParser(&artifactsPaths))
 	if err != nil {
 		return nil, fmt.Errorf("failed running '%s %s %s' command with error: '%s - %s'", _twineExeName, _twineUploadCmdName, strings.Join(commandArgs, " "), err.Error(), errorOut)
 	}
@@ -81,8 +82,8 @@ func CreateArtifactsFromPaths(artifactsPaths []string) (artifacts []entities.Art
 		}
 
 		artifact := entities.Artifact{Name: filepath.Base(absPath), Path: path.Join(projectName, projectVersion, filepath.Base(absPath)),
-			Type: strings.TrimPrefix(filepath.Ext(absPath), ".")}
-		artifact.Checksum = entities.Checksum{Sha1: fileDetails.Checksum.Sha1, Md5: fileDetails.Checksum.Md5}
+			Type: strings.TrimPrefix(filepath.Ext(absPath), "."), Size: fileDetails.Size}
+		artifact.Checksum = entities.Checksum{Sha1: fileDetails.Checksum.Sha1, Md5: fileDetails.Checksum.Md5, Sha256: fileDetails.Checksum.Sha256}
 		artifacts = append(artifacts, artifact)
 	}
 	return
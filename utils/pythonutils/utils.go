@@ -274,6 +274,16 @@ func InstallWithLogParsing(tool PythonTool, commandArgs []string, log utils.Log,
 
 	var packageName string
 	expectingPackageFilePath := false
+	// The indexes which pip looks in, if it printed them.
+	var indexUrls []string
+
+	parsers = append(parsers, &gofrogcmd.CmdOutputPattern{
+		RegExp: lookingInIndexesRegexp,
+		ExecFunc: func(pattern *gofrogcmd.CmdOutputPattern) (string, error) {
+			indexUrls = parsePipIndexUrls(pattern.Line)
+			return pattern.Line, nil
+		},
+	})
 
 	// Extract downloaded package name.
 	parsers = append(parsers, &gofrogcmd.CmdOutputPattern{
@@ -286,7 +296,9 @@ func InstallWithLogParsing(tool PythonTool, commandArgs []string, log utils.Log,
 				log.Debug(fmt.Sprintf("Could not resolve download path for package: %s, continuing...", packageName))
 
 				// Save package with empty file path.
-				dependenciesMap[strings.ToLower(packageName)] = entities.Dependency{Id: ""}
+				dependency := entities.Dependency{Id: ""}
+				dependency.SetResolutionSource(entities.FallbackRegexSource)
+				dependenciesMap[strings.ToLower(packageName)] = dependency
 			}
 
 			// Check for out of bound results.
@@ -315,7 +327,12 @@ func InstallWithLogParsing(tool PythonTool, commandArgs []string, log utils.Log,
 			return pattern.Line, nil
 		}
 		// Save dependency information.
-		dependenciesMap[strings.ToLower(packageName)] = entities.Dependency{Id: fileName}
+		dependency := entities.Dependency{Id: fileName, Properties: map[string]string{PipOriginProperty: IndexOrigin}}
+		dependency.SetResolutionSource(entities.FallbackRegexSource)
+		if indexUrl := getPipIndexUrl(indexUrls, pattern.MatchedResults[1]); indexUrl != "" {
+			dependency.Properties[PipIndexUrlProperty] = indexUrl
+		}
+		dependenciesMap[strings.ToLower(packageName)] = dependency
 		expectingPackageFilePath = false
 		log.Debug(fmt.Sprintf("Found package: %s installed with: %s", packageName, fileName))
 		return pattern.Line, nil
@@ -339,14 +356,16 @@ func InstallWithLogParsing(tool PythonTool, commandArgs []string, log utils.Log,
 			}
 
 			// Save dependency with empty file name.
-			dependenciesMap[strings.ToLower(pattern.MatchedResults[1])] = entities.Dependency{Id: ""}
+			dependency := entities.Dependency{Id: ""}
+			dependency.SetResolutionSource(entities.FallbackRegexSource)
+			dependenciesMap[strings.ToLower(pattern.MatchedResults[1])] = dependency
 			log.Debug(fmt.Sprintf("Found package: %s already installed", pattern.MatchedResults[1]))
 			return pattern.Line, nil
 		},
 	})
 
 	// Execute command.
-	_, errorOut, _, err := gofrogcmd.RunCmdWithOutputParser(installCmd, true, parsers...)
+	_, errorOut, err := utils.RunCommandWithOutputParser(installCmd, true, parsers...)
 	if err != nil {
 		return nil, fmt.Errorf("failed running %s command with error: '%s - %s'", string(tool), err.Error(), errorOut)
 	}